@@ -21,6 +21,8 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/rs/zerolog/log"
 	"github.com/uber/jaeger-client-go"
 	jaegercfg "github.com/uber/jaeger-client-go/config"
@@ -37,6 +39,7 @@ import (
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/prometheus"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/pubsubapi"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/slackapi"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/audit"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/bitbucket"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/catalog"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/cloudsource"
@@ -45,6 +48,7 @@ import (
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/queue"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/rbac"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/slack"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/webhook"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/ziplinee"
 	crypt "github.com/ziplineeci/ziplinee-ci-crypt"
 	foundation "github.com/ziplineeci/ziplinee-foundation"
@@ -132,10 +136,22 @@ func initRequestHandlers(ctx context.Context, stopChannel <-chan struct{}, waitG
 	bqClient, pubsubClient, gcsClient, sourcerepoTokenSource, sourcerepoService := getGoogleCloudClients(ctx, config)
 	bigqueryClient, bitbucketapiClient, githubapiClient, slackapiClient, pubsubapiClient, databaseClient, dockerhubapiClient, builderapiClient, cloudstorageClient, prometheusClient, cloudsourceClient := getClients(ctx, config, encryptedConfig, secretHelper, bqClient, pubsubClient, gcsClient, sourcerepoTokenSource, sourcerepoService)
 	ziplineeService, queueService, rbacService, githubService, bitbucketService, cloudsourceService, catalogService := getServices(ctx, config, encryptedConfig, secretHelper, bigqueryClient, bitbucketapiClient, githubapiClient, slackapiClient, pubsubapiClient, databaseClient, dockerhubapiClient, builderapiClient, cloudstorageClient, prometheusClient, cloudsourceClient)
-	bitbucketHandler, githubHandler, ziplineeHandler, rbacHandler, pubsubHandler, slackHandler, cloudsourceHandler, catalogHandler := getHandlers(ctx, config, encryptedConfig, secretHelper, bitbucketapiClient, githubapiClient, slackapiClient, pubsubapiClient, databaseClient, builderapiClient, cloudstorageClient, ziplineeService, rbacService, githubService, bitbucketService, cloudsourceService, catalogService)
+	bitbucketHandler, githubHandler, ziplineeHandler, rbacHandler, pubsubHandler, slackHandler, cloudsourceHandler, catalogHandler, webhookHandler, auditHandler := getHandlers(ctx, config, encryptedConfig, secretHelper, bitbucketapiClient, githubapiClient, slackapiClient, pubsubapiClient, databaseClient, builderapiClient, cloudstorageClient, ziplineeService, rbacService, githubService, bitbucketService, cloudsourceService, catalogService)
 
 	waitGroup.Add(1)
-	//go ziplineeHandler.PollMigrationTasks(stopChannel, waitGroup.Done)
+	go ziplineeHandler.PollMigrationTasks(stopChannel, waitGroup.Done)
+
+	waitGroup.Add(1)
+	go ziplineeHandler.PollLogRetention(stopChannel, waitGroup.Done)
+
+	waitGroup.Add(1)
+	go ziplineeHandler.PollLogReconciliations(stopChannel, waitGroup.Done)
+
+	waitGroup.Add(1)
+	go ziplineeHandler.PollNotificationDeliveries(stopChannel, waitGroup.Done)
+
+	waitGroup.Add(1)
+	go ziplineeHandler.PollQueuedJobs(stopChannel, waitGroup.Done)
 	err := queueService.CreateConnection(ctx)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed creating connection to queue")
@@ -145,7 +161,7 @@ func initRequestHandlers(ctx context.Context, stopChannel <-chan struct{}, waitG
 		log.Fatal().Err(err).Msg("Failed initializing queue subscriptions")
 	}
 
-	srv := configureGinGonic(config, bitbucketHandler, githubHandler, ziplineeHandler, rbacHandler, pubsubHandler, slackHandler, cloudsourceHandler, catalogHandler)
+	srv := configureGinGonic(config, bitbucketHandler, githubHandler, ziplineeHandler, rbacHandler, pubsubHandler, slackHandler, cloudsourceHandler, catalogHandler, webhookHandler, auditHandler, rbacService)
 
 	// watch for config changes
 	foundation.WatchForFileChanges(*configFilesPath, func(event fsnotify.Event) {
@@ -334,6 +350,7 @@ func getClients(ctx context.Context, config *api.APIConfig, encryptedConfig *api
 		api.NewRequestCounter("bigquery_client"),
 		api.NewRequestHistogram("bigquery_client"),
 	)
+	bigqueryClient = bigquery.NewRetryClient(config, bigqueryClient)
 	err = bigqueryClient.Init(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("Initializing BigQuery tables has failed")
@@ -412,7 +429,22 @@ func getClients(ctx context.Context, config *api.APIConfig, encryptedConfig *api
 	)
 
 	// cloudstorage client
-	cloudstorageClient = cloudstorage.NewClient(config, gcsClient)
+	switch config.APIServer.ObjectStorageLogTarget() {
+	case api.LogTargetS3:
+		minioClient, err := minio.New(config.Integrations.S3Storage.Endpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(config.Integrations.S3Storage.AccessKeyID, config.Integrations.S3Storage.SecretAccessKey, ""),
+			Secure: !config.Integrations.S3Storage.Insecure,
+			Region: config.Integrations.S3Storage.Region,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed creating s3 client")
+		}
+		cloudstorageClient = cloudstorage.NewS3Client(config, minioClient)
+	case api.LogTargetFilesystem:
+		cloudstorageClient = cloudstorage.NewFilesystemClient(config)
+	default:
+		cloudstorageClient = cloudstorage.NewClient(config, gcsClient)
+	}
 	cloudstorageClient = cloudstorage.NewTracingClient(cloudstorageClient)
 	cloudstorageClient = cloudstorage.NewLoggingClient(cloudstorageClient)
 	cloudstorageClient = cloudstorage.NewMetricsClient(cloudstorageClient,
@@ -446,7 +478,7 @@ func getServices(ctx context.Context, config *api.APIConfig, encryptedConfig *ap
 	log.Debug().Msg("Creating services...")
 
 	// ziplinee service
-	ziplineeService = ziplinee.NewService(config, databaseClient, secretHelper, prometheusClient, cloudstorageClient, builderapiClient, githubapiClient.JobVarsFunc(ctx), bitbucketapiClient.JobVarsFunc(ctx), cloudsourceClient.JobVarsFunc(ctx))
+	ziplineeService = ziplinee.NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudstorageClient, builderapiClient, githubapiClient.JobVarsFunc(ctx), bitbucketapiClient.JobVarsFunc(ctx), cloudsourceClient.JobVarsFunc(ctx))
 	ziplineeService = ziplinee.NewTracingService(ziplineeService)
 	ziplineeService = ziplinee.NewLoggingService(ziplineeService)
 	ziplineeService = ziplinee.NewMetricsService(ziplineeService,
@@ -473,6 +505,7 @@ func getServices(ctx context.Context, config *api.APIConfig, encryptedConfig *ap
 	githubService = github.NewMetricsService(githubService,
 		api.NewRequestCounter("github_service"),
 		api.NewRequestHistogram("github_service"),
+		api.NewRejectionCounter("github_service"),
 	)
 
 	// bitbucket service
@@ -506,7 +539,7 @@ func getServices(ctx context.Context, config *api.APIConfig, encryptedConfig *ap
 }
 
 func getHandlers(_ context.Context, config *api.APIConfig, encryptedConfig *api.APIConfig, secretHelper crypt.SecretHelper, bitbucketapiClient bitbucketapi.Client, githubapiClient githubapi.Client, slackapiClient slackapi.Client, pubsubapiClient pubsubapi.Client, databaseClient database.Client, builderapiClient builderapi.Client, cloudstorageClient cloudstorage.Client, ziplineeService ziplinee.Service, rbacService rbac.Service, githubService github.Service, bitbucketService bitbucket.Service, cloudsourceService cloudsource.Service, catalogService catalog.Service) (
-	bitbucketHandler bitbucket.Handler, githubHandler github.Handler, ziplineeHandler ziplinee.Handler, rbacHandler rbac.Handler, pubsubHandler pubsub.Handler, slackHandler slack.Handler, cloudsourceHandler cloudsource.Handler, catalogHandler catalog.Handler) {
+	bitbucketHandler bitbucket.Handler, githubHandler github.Handler, ziplineeHandler ziplinee.Handler, rbacHandler rbac.Handler, pubsubHandler pubsub.Handler, slackHandler slack.Handler, cloudsourceHandler cloudsource.Handler, catalogHandler catalog.Handler, webhookHandler webhook.Handler, auditHandler audit.Handler) {
 	log.Debug().Msg("Creating http handlers...")
 
 	warningHelper := api.NewWarningHelper(secretHelper)
@@ -517,14 +550,16 @@ func getHandlers(_ context.Context, config *api.APIConfig, encryptedConfig *api.
 	ziplineeHandler = ziplinee.NewHandler(*templatesPath, config, encryptedConfig, databaseClient, cloudstorageClient, builderapiClient, ziplineeService, warningHelper, secretHelper)
 	rbacHandler = rbac.NewHandler(config, rbacService, databaseClient, bitbucketapiClient, githubapiClient)
 	pubsubHandler = pubsub.NewHandler(pubsubapiClient, ziplineeService)
-	slackHandler = slack.NewHandler(secretHelper, config, slackapiClient, databaseClient, ziplineeService)
+	slackHandler = slack.NewHandler(secretHelper, config, slackapiClient, databaseClient, ziplineeService, rbacService)
 	cloudsourceHandler = cloudsource.NewHandler(pubsubapiClient, cloudsourceService)
 	catalogHandler = catalog.NewHandler(config, catalogService, databaseClient)
+	webhookHandler = webhook.NewHandler(config, databaseClient)
+	auditHandler = audit.NewHandler(config, databaseClient)
 
 	return
 }
 
-func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler, githubHandler github.Handler, ziplineeHandler ziplinee.Handler, rbacHandler rbac.Handler, pubsubHandler pubsub.Handler, slackHandler slack.Handler, cloudsourceHandler cloudsource.Handler, catalogHandler catalog.Handler) *http.Server {
+func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler, githubHandler github.Handler, ziplineeHandler ziplinee.Handler, rbacHandler rbac.Handler, pubsubHandler pubsub.Handler, slackHandler slack.Handler, cloudsourceHandler cloudsource.Handler, catalogHandler catalog.Handler, webhookHandler webhook.Handler, auditHandler audit.Handler, sessionStore api.SessionStore) *http.Server {
 
 	// run gin in release mode and other defaults
 	gin.SetMode(gin.ReleaseMode)
@@ -553,7 +588,7 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 
 	// middleware to handle auth for different endpoints
 	log.Debug().Msg("Adding auth middleware...")
-	authMiddleware := api.NewAuthMiddleware(config)
+	authMiddleware := api.NewAuthMiddleware(config, sessionStore)
 	jwtMiddleware, err := authMiddleware.GinJWTMiddleware(rbacHandler.HandleOAuthLoginProviderAuthenticator())
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed creating JWT middleware")
@@ -567,49 +602,62 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 		log.Fatal().Err(err).Msg("Failed creating JWT middleware for user impersonation")
 	}
 
-	preZippedJWTMiddlewareRoutes := router.Group("/", jwtMiddleware.MiddlewareFunc())
+	// besides jwts, routes requiring to be logged in accept personal access tokens for scripting against the api
+	personalAccessTokenOrJWTMiddleware := authMiddleware.PersonalAccessTokenMiddlewareFunc(jwtMiddleware, rbacHandler.HandlePersonalAccessTokenAuthenticator())
+
+	preZippedJWTMiddlewareRoutes := router.Group("/", personalAccessTokenOrJWTMiddleware)
 
 	// Gzip and logging middleware
 	log.Debug().Msg("Adding gzip middleware...")
 	routes := router.Group("/", gzip.Gzip(gzip.BestSpeed))
 
 	log.Debug().Msg("Setting up routes...")
-	routes.POST("/api/integrations/github/events", githubHandler.Handle)
+	routes.POST("/api/integrations/github/events", auditHandler.DetailsMiddleware(), webhookHandler.Record("github", "X-Github-Event", githubHandler.Handle))
 	routes.GET("/api/integrations/github/status", func(c *gin.Context) { c.String(200, "Github, I'm cool!") })
-	routes.GET("/api/integrations/github/redirect", githubHandler.Redirect)
+	routes.GET("/api/integrations/github/redirect", auditHandler.Middleware(), githubHandler.Redirect)
 
-	routes.POST("/api/integrations/bitbucket/events", bitbucketHandler.Handle)
+	routes.POST("/api/integrations/bitbucket/events", webhookHandler.Record("bitbucket", "X-Event-Key", bitbucketHandler.Handle))
 	routes.GET("/api/integrations/bitbucket/status", func(c *gin.Context) { c.String(200, "Bitbucket, I'm cool!") })
 	routes.GET("/api/integrations/bitbucket/descriptor", bitbucketHandler.Descriptor)
-	routes.POST("/api/integrations/bitbucket/installed", bitbucketHandler.Installed)
-	routes.POST("/api/integrations/bitbucket/uninstalled", bitbucketHandler.Uninstalled)
+	routes.POST("/api/integrations/bitbucket/installed", auditHandler.Middleware(), bitbucketHandler.Installed)
+	routes.POST("/api/integrations/bitbucket/uninstalled", auditHandler.Middleware(), bitbucketHandler.Uninstalled)
 	routes.GET("/api/integrations/bitbucket/redirect", bitbucketHandler.Redirect)
 
-	routes.POST("/api/integrations/slack/slash", slackHandler.Handle)
+	routes.POST("/api/integrations/slack/slash", auditHandler.DetailsMiddleware(), webhookHandler.Record("slack", "", slackHandler.Handle))
+	routes.POST("/api/integrations/slack/interactive", auditHandler.DetailsMiddleware(), webhookHandler.Record("slackinteractions", "", slackHandler.HandleInteraction))
 	routes.GET("/api/integrations/slack/status", func(c *gin.Context) { c.String(200, "Slack, I'm cool!") })
 
 	// google jwt auth protected endpoints
 	googleAuthorizedRoutes := routes.Group("/", authMiddleware.GoogleJWTMiddlewareFunc())
 	{
 		googleAuthorizedRoutes.POST("/api/integrations/pubsub/events", pubsubHandler.PostPubsubEvent)
-		googleAuthorizedRoutes.POST("/api/integrations/cloudsource/events", cloudsourceHandler.PostPubsubEvent)
+		googleAuthorizedRoutes.POST("/api/integrations/cloudsource/events", webhookHandler.RecordAuthenticated("cloudsource", "", authMiddleware.GoogleJWTMiddlewareFunc(), cloudsourceHandler.PostPubsubEvent))
 	}
 	routes.GET("/api/integrations/pubsub/status", func(c *gin.Context) { c.String(200, "Pub/Sub, I'm cool!") })
 	routes.GET("/api/integrations/cloudsource/status", func(c *gin.Context) { c.String(200, "Cloud Source, I'm cool!") })
 
 	// public routes for logging in
 	routes.GET("/api/auth/providers", rbacHandler.GetProviders)
+	routes.GET("/api/auth/jwks", rbacHandler.GetJSONWebKeySet)
 	routes.GET("/api/auth/login/:provider", rbacHandler.LoginProvider)
 	routes.GET("/api/auth/logout", jwtMiddleware.LogoutHandler)
-	routes.GET("/api/auth/handle/:provider", jwtMiddleware.LoginHandler)
-	routes.POST("/api/auth/client/login", clientLoginJWTMiddleware.LoginHandler)
+	routes.GET("/api/auth/handle/:provider", authMiddleware.LoginHandler(jwtMiddleware))
+	routes.POST("/api/auth/client/login", authMiddleware.LoginHandler(clientLoginJWTMiddleware))
 	routes.POST("/api/auth/client/logout", clientLoginJWTMiddleware.LogoutHandler)
+	routes.POST("/api/auth/oauth/token", rbacHandler.PostOAuthToken)
+	routes.POST("/api/auth/oauth/introspect", rbacHandler.PostOAuthIntrospect)
 
-	// routes that require to be logged in and have a valid jwt
-	jwtMiddlewareRoutes := routes.Group("/", jwtMiddleware.MiddlewareFunc())
+	// routes that require to be logged in and have a valid jwt; changes made through them are recorded in the audit log
+	jwtMiddlewareRoutes := routes.Group("/", personalAccessTokenOrJWTMiddleware, auditHandler.Middleware())
 	{
 		// logged in user endpoints
 		jwtMiddlewareRoutes.GET("/api/me", rbacHandler.GetLoggedInUser)
+		jwtMiddlewareRoutes.GET("/api/me/tokens", rbacHandler.GetPersonalAccessTokens)
+		jwtMiddlewareRoutes.POST("/api/me/tokens", rbacHandler.CreatePersonalAccessToken)
+		jwtMiddlewareRoutes.DELETE("/api/me/tokens/:id", rbacHandler.DeletePersonalAccessToken)
+		jwtMiddlewareRoutes.GET("/api/me/sessions", rbacHandler.GetSessions)
+		jwtMiddlewareRoutes.DELETE("/api/me/sessions", rbacHandler.DeleteSessions)
+		jwtMiddlewareRoutes.DELETE("/api/me/sessions/:id", rbacHandler.DeleteSession)
 
 		// actions
 		jwtMiddlewareRoutes.POST("/api/pipelines/:source/:owner/:repo/builds", ziplineeHandler.CreatePipelineBuild)
@@ -628,6 +676,7 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 		jwtMiddlewareRoutes.POST("/api/users", rbacHandler.CreateUser)
 		jwtMiddlewareRoutes.PUT("/api/users/:id", rbacHandler.UpdateUser)
 		jwtMiddlewareRoutes.DELETE("/api/users/:id", rbacHandler.DeleteUser)
+		jwtMiddlewareRoutes.DELETE("/api/users/:id/sessions", rbacHandler.DeleteUserSessions)
 
 		jwtMiddlewareRoutes.GET("/api/groups", rbacHandler.GetGroups)
 		jwtMiddlewareRoutes.GET("/api/groups/:id", rbacHandler.GetGroup)
@@ -646,9 +695,12 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 		jwtMiddlewareRoutes.POST("/api/clients", rbacHandler.CreateClient)
 		jwtMiddlewareRoutes.PUT("/api/clients/:id", rbacHandler.UpdateClient)
 		jwtMiddlewareRoutes.DELETE("/api/clients/:id", rbacHandler.DeleteClient)
+		jwtMiddlewareRoutes.GET("/api/clients/:id/secrets", rbacHandler.GetClientSecrets)
+		jwtMiddlewareRoutes.POST("/api/clients/:id/secrets", rbacHandler.CreateClientSecret)
+		jwtMiddlewareRoutes.DELETE("/api/clients/:id/secrets/:secretID", rbacHandler.DeleteClientSecret)
 
 		// admin section
-		jwtMiddlewareRoutes.GET("/api/auth/impersonate/:id", impersonateJWTMiddleware.LoginHandler)
+		jwtMiddlewareRoutes.GET("/api/auth/impersonate/:id", authMiddleware.LoginHandler(impersonateJWTMiddleware))
 		jwtMiddlewareRoutes.GET("/api/admin/roles", rbacHandler.GetRoles)
 
 		jwtMiddlewareRoutes.GET("/api/admin/users", rbacHandler.GetUsers)
@@ -684,11 +736,41 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 		jwtMiddlewareRoutes.POST("/api/admin/clients", rbacHandler.CreateClient)
 		jwtMiddlewareRoutes.PUT("/api/admin/clients/:id", rbacHandler.UpdateClient)
 		jwtMiddlewareRoutes.DELETE("/api/admin/clients/:id", rbacHandler.DeleteClient)
+		jwtMiddlewareRoutes.GET("/api/admin/clients/:id/secrets", rbacHandler.GetClientSecrets)
+		jwtMiddlewareRoutes.POST("/api/admin/clients/:id/secrets", rbacHandler.CreateClientSecret)
+		jwtMiddlewareRoutes.DELETE("/api/admin/clients/:id/secrets/:secretID", rbacHandler.DeleteClientSecret)
+
+		jwtMiddlewareRoutes.GET("/api/admin/customroles", rbacHandler.GetCustomRoles)
+		jwtMiddlewareRoutes.GET("/api/admin/customroles/:id", rbacHandler.GetCustomRole)
+		jwtMiddlewareRoutes.POST("/api/admin/customroles", rbacHandler.CreateCustomRole)
+		jwtMiddlewareRoutes.PUT("/api/admin/customroles/:id", rbacHandler.UpdateCustomRole)
+		jwtMiddlewareRoutes.DELETE("/api/admin/customroles/:id", rbacHandler.DeleteCustomRole)
 
 		jwtMiddlewareRoutes.GET("/api/admin/integrations", rbacHandler.GetIntegrations)
 		jwtMiddlewareRoutes.PUT("/api/admin/integrations/github/", rbacHandler.UpdateGithubInstallation)
 		jwtMiddlewareRoutes.PUT("/api/admin/integrations/bitbucket/", rbacHandler.UpdateBitbucketInstallation)
 
+		jwtMiddlewareRoutes.GET("/api/admin/webhooks", webhookHandler.GetDeliveries)
+		jwtMiddlewareRoutes.GET("/api/admin/webhooks/:id", webhookHandler.GetDelivery)
+		jwtMiddlewareRoutes.POST("/api/admin/webhooks/:id/replay", webhookHandler.ReplayDelivery)
+
+		jwtMiddlewareRoutes.GET("/api/admin/audit", auditHandler.GetAuditLogRecords)
+
+		jwtMiddlewareRoutes.GET("/api/admin/logretention/dryrun", ziplineeHandler.GetLogRetentionDryRun)
+
+		jwtMiddlewareRoutes.GET("/api/admin/logreconciliations", ziplineeHandler.GetLogReconciliations)
+		jwtMiddlewareRoutes.GET("/api/admin/logreconciliations/:id", ziplineeHandler.GetLogReconciliation)
+		jwtMiddlewareRoutes.POST("/api/admin/logreconciliations", ziplineeHandler.QueueLogReconciliation)
+		jwtMiddlewareRoutes.GET("/api/admin/notifications/deliveries", ziplineeHandler.GetNotificationDeliveries)
+		jwtMiddlewareRoutes.GET("/api/admin/notifications/deliveries/:id", ziplineeHandler.GetNotificationDelivery)
+
+		// migration routes
+		jwtMiddlewareRoutes.POST("/api/migration", ziplineeHandler.QueueMigration)
+		jwtMiddlewareRoutes.GET("/api/migration", ziplineeHandler.GetMigrations)
+		jwtMiddlewareRoutes.GET("/api/migration/:taskID", ziplineeHandler.GetMigrationByID)
+		jwtMiddlewareRoutes.DELETE("/api/migration/:taskID", ziplineeHandler.RollbackMigration)
+		jwtMiddlewareRoutes.GET("/api/migration/from/:source/:owner/:name", ziplineeHandler.GetMigrationByFromRepo)
+
 		// catalog routes
 		jwtMiddlewareRoutes.GET("/api/catalog/entity-labels", catalogHandler.GetCatalogEntityLabels)
 		jwtMiddlewareRoutes.GET("/api/catalog/entity-parent-keys", catalogHandler.GetCatalogEntityParentKeys)
@@ -725,6 +807,7 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/bots", ziplineeHandler.GetPipelineBots)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/bots/:botId", ziplineeHandler.GetPipelineBot)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/bots/:botId/alllogs", ziplineeHandler.GetPipelineBotLogsPerPage)
+		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/logs/search", ziplineeHandler.SearchPipelineLogs)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/stats/buildsdurations", ziplineeHandler.GetPipelineStatsBuildsDurations)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/stats/releasesdurations", ziplineeHandler.GetPipelineStatsReleasesDurations)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/stats/botsdurations", ziplineeHandler.GetPipelineStatsBotsDurations)
@@ -734,6 +817,8 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/stats/buildsmemory", ziplineeHandler.GetPipelineStatsBuildsMemoryUsageMeasurements)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/stats/releasesmemory", ziplineeHandler.GetPipelineStatsReleasesMemoryUsageMeasurements)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/stats/botsmemory", ziplineeHandler.GetPipelineStatsBotsMemoryUsageMeasurements)
+		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/stats/dora", ziplineeHandler.GetPipelineStatsDora)
+		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/resources/explain", ziplineeHandler.ExplainPipelineJobResources)
 		jwtMiddlewareRoutes.GET("/api/pipelines/:source/:owner/:repo/warnings", ziplineeHandler.GetPipelineWarnings)
 		jwtMiddlewareRoutes.GET("/api/builds", ziplineeHandler.GetAllPipelineBuilds)
 		jwtMiddlewareRoutes.GET("/api/releases", ziplineeHandler.GetAllPipelineReleases)
@@ -754,6 +839,8 @@ func configureGinGonic(config *api.APIConfig, bitbucketHandler bitbucket.Handler
 		jwtMiddlewareRoutes.GET("/api/stats/mostbuilds", ziplineeHandler.GetStatsMostBuilds)
 		jwtMiddlewareRoutes.GET("/api/stats/mostreleases", ziplineeHandler.GetStatsMostReleases)
 		jwtMiddlewareRoutes.GET("/api/stats/mostbots", ziplineeHandler.GetStatsMostBots)
+		jwtMiddlewareRoutes.GET("/api/stats/costs/:dimension", ziplineeHandler.GetStatsCosts)
+		jwtMiddlewareRoutes.GET("/api/stats/dora", ziplineeHandler.GetStatsDora)
 		jwtMiddlewareRoutes.GET("/api/manifest/templates", ziplineeHandler.GetManifestTemplates)
 		jwtMiddlewareRoutes.POST("/api/manifest/generate", ziplineeHandler.GenerateManifest)
 		jwtMiddlewareRoutes.POST("/api/manifest/validate", ziplineeHandler.ValidateManifest)
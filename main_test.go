@@ -15,6 +15,7 @@ import (
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/pubsubapi"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/slackapi"
 
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/audit"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/bitbucket"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/catalog"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/cloudsource"
@@ -22,6 +23,7 @@ import (
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/pubsub"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/rbac"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/slack"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/webhook"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/ziplinee"
 
 	crypt "github.com/ziplineeci/ziplinee-ci-crypt"
@@ -62,13 +64,16 @@ func TestConfigureGinGonic(t *testing.T) {
 		githubHandler := github.NewHandler(github.NewMockService(ctrl), config, githubapiClient, nil)
 		ziplineeHandler := ziplinee.NewHandler("", config, config, databaseClient, cloudstorageClient, builderapiClient, ziplineeService, warningHelper, secretHelper)
 
-		rbacHandler := rbac.NewHandler(config, rbac.NewMockService(ctrl), databaseClient, bitbucketapiClient, githubapiClient)
+		rbacService := rbac.NewMockService(ctrl)
+		rbacHandler := rbac.NewHandler(config, rbacService, databaseClient, bitbucketapiClient, githubapiClient)
 		pubsubHandler := pubsub.NewHandler(pubsubapiclient, ziplineeService)
-		slackHandler := slack.NewHandler(secretHelper, config, slackapiClient, databaseClient, ziplineeService)
+		slackHandler := slack.NewHandler(secretHelper, config, slackapiClient, databaseClient, ziplineeService, rbacService)
 		cloudsourceHandler := cloudsource.NewHandler(pubsubapiclient, cloudsource.NewMockService(ctrl))
 		catalogHandler := catalog.NewHandler(config, catalog.NewMockService(ctrl), databaseClient)
+		webhookHandler := webhook.NewHandler(config, databaseClient)
+		auditHandler := audit.NewHandler(config, databaseClient)
 
 		// act
-		_ = configureGinGonic(config, bitbucketHandler, githubHandler, ziplineeHandler, rbacHandler, pubsubHandler, slackHandler, cloudsourceHandler, catalogHandler)
+		_ = configureGinGonic(config, bitbucketHandler, githubHandler, ziplineeHandler, rbacHandler, pubsubHandler, slackHandler, cloudsourceHandler, catalogHandler, webhookHandler, auditHandler, rbacService)
 	})
 }
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDHeader is the header used to pass a request id, which is generated if it's missing, to correlate audit log records with logs
+	RequestIDHeader = "X-Request-ID"
+
+	auditDetailsKey = "auditDetails"
+	requestIDKey    = "requestID"
+)
+
+// AuditDetails describes a change for the audit log in more detail than the route through which it was made
+type AuditDetails struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     interface{}
+	After      interface{}
+}
+
+// AddAuditDetails describes a change made by the request, to be recorded in the audit log once the request has succeeded;
+// requests changing multiple targets add details for each of them
+func AddAuditDetails(c *gin.Context, action, targetType, targetID string, before, after interface{}) {
+	details, _ := GetAuditDetails(c)
+	c.Set(auditDetailsKey, append(details, AuditDetails{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+	}))
+}
+
+// GetAuditDetails returns the audit details added by the handler of the request, if any
+func GetAuditDetails(c *gin.Context) (details []AuditDetails, ok bool) {
+	value, exists := c.Get(auditDetailsKey)
+	if !exists {
+		return
+	}
+	details, ok = value.([]AuditDetails)
+
+	return
+}
+
+// GetRouteAuditDetails describes a change by its route, for handlers that don't set audit details themselves; the target
+// type is the last fixed path segment and the target id the path parameters, for example 'builds' and 'github.com/ziplineeci/ziplinee-ci-api/1234'
+func GetRouteAuditDetails(c *gin.Context) AuditDetails {
+
+	details := AuditDetails{
+		Action: fmt.Sprintf("%v %v", c.Request.Method, c.FullPath()),
+	}
+
+	for _, segment := range strings.Split(c.FullPath(), "/") {
+		if segment != "" && !strings.HasPrefix(segment, ":") && !strings.HasPrefix(segment, "*") {
+			details.TargetType = segment
+		}
+	}
+
+	paramValues := []string{}
+	for _, p := range c.Params {
+		paramValues = append(paramValues, p.Value)
+	}
+	details.TargetID = strings.Join(paramValues, "/")
+
+	return details
+}
+
+// SetRequestID stores the request id for the remainder of the request
+func SetRequestID(c *gin.Context, requestID string) {
+	c.Set(requestIDKey, requestID)
+}
+
+// GetRequestID returns the request id set by the audit middleware
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// GetClientIDFromRequest returns the client id for requests made with a client login
+func GetClientIDFromRequest(c *gin.Context) string {
+
+	if !RequestTokenIsValid(c) {
+		return ""
+	}
+
+	claims := jwt.ExtractClaims(c)
+	if clientID, ok := claims["clientID"].(string); ok {
+		return clientID
+	}
+
+	return ""
+}
+
+// GetSessionIDFromRequest returns the id of the session of the jwt used for the request, if it has one
+func GetSessionIDFromRequest(c *gin.Context) string {
+
+	if !RequestTokenIsValid(c) {
+		return ""
+	}
+
+	claims := jwt.ExtractClaims(c)
+	if sessionID, ok := claims["jti"].(string); ok {
+		return sessionID
+	}
+
+	return ""
+}
+
+// GetImpersonatorFromRequest returns the email address of the administrator impersonating the user of the request, if any
+func GetImpersonatorFromRequest(c *gin.Context) string {
+
+	if !RequestTokenIsValid(c) {
+		return ""
+	}
+
+	claims := jwt.ExtractClaims(c)
+	if impersonator, ok := claims["impersonator"].(string); ok {
+		return impersonator
+	}
+
+	return ""
+}
@@ -8,7 +8,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -36,8 +38,14 @@ type APIConfig struct {
 	Jobs                      *JobsConfig                           `yaml:"jobs,omitempty"`
 	Database                  *DatabaseConfig                       `yaml:"database,omitempty"`
 	Queue                     *QueueConfig                          `yaml:"queue,omitempty"`
+	JobQueue                  *JobQueueConfig                       `yaml:"jobQueue,omitempty"`
+	Migration                 *MigrationConfig                      `yaml:"migration,omitempty"`
+	LogRetention              *LogRetentionConfig                   `yaml:"logRetention,omitempty"`
+	LogReconciliation         *LogReconciliationConfig              `yaml:"logReconciliation,omitempty"`
+	Notifications             *NotificationsConfig                  `yaml:"notifications,omitempty"`
 	ManifestPreferences       *manifest.ZiplineeManifestPreferences `yaml:"manifestPreferences,omitempty"`
 	Catalog                   *CatalogConfig                        `yaml:"catalog,omitempty"`
+	Costs                     *CostsConfig                          `yaml:"costs,omitempty"`
 	Credentials               []*contracts.CredentialConfig         `yaml:"credentials,omitempty" json:"credentials,omitempty"`
 	ClearDefaultTrustedImages bool                                  `yaml:"clearDefaultTrustedImages,omitempty"`
 	TrustedImages             []*contracts.TrustedImageConfig       `yaml:"trustedImages,omitempty" json:"trustedImages,omitempty"`
@@ -79,6 +87,31 @@ func (c *APIConfig) SetDefaults() {
 	}
 	c.Queue.SetDefaults()
 
+	if c.JobQueue == nil {
+		c.JobQueue = &JobQueueConfig{}
+	}
+	c.JobQueue.SetDefaults()
+
+	if c.Migration == nil {
+		c.Migration = &MigrationConfig{}
+	}
+	c.Migration.SetDefaults()
+
+	if c.LogRetention == nil {
+		c.LogRetention = &LogRetentionConfig{}
+	}
+	c.LogRetention.SetDefaults()
+
+	if c.LogReconciliation == nil {
+		c.LogReconciliation = &LogReconciliationConfig{}
+	}
+	c.LogReconciliation.SetDefaults()
+
+	if c.Notifications == nil {
+		c.Notifications = &NotificationsConfig{}
+	}
+	c.Notifications.SetDefaults()
+
 	if c.ManifestPreferences == nil {
 		c.ManifestPreferences = &manifest.ZiplineeManifestPreferences{}
 	}
@@ -88,6 +121,10 @@ func (c *APIConfig) SetDefaults() {
 		c.Catalog.SetDefaults()
 	}
 
+	if c.Costs != nil {
+		c.Costs.SetDefaults()
+	}
+
 	if c.Credentials == nil {
 		c.Credentials = make([]*contracts.CredentialConfig, 0)
 	}
@@ -184,6 +221,22 @@ func (c *APIConfig) Validate() (err error) {
 		return
 	}
 
+	// the object storage log target needs its integration to be enabled
+	switch c.APIServer.ObjectStorageLogTarget() {
+	case LogTargetCloudStorage:
+		if !c.Integrations.CloudStorage.Enable {
+			return errors.New("Configuration item 'integrations.gcs.enable' has to be true when writing logs to cloudstorage")
+		}
+	case LogTargetS3:
+		if !c.Integrations.S3Storage.Enable {
+			return errors.New("Configuration item 'integrations.s3.enable' has to be true when writing logs to s3")
+		}
+	case LogTargetFilesystem:
+		if !c.Integrations.Filesystem.Enable {
+			return errors.New("Configuration item 'integrations.filesystem.enable' has to be true when writing logs to filesystem")
+		}
+	}
+
 	err = c.Auth.Validate()
 	if err != nil {
 		return
@@ -199,6 +252,31 @@ func (c *APIConfig) Validate() (err error) {
 		return
 	}
 
+	err = c.JobQueue.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Migration.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.LogRetention.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.LogReconciliation.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Notifications.Validate()
+	if err != nil {
+		return
+	}
+
 	if c.Catalog != nil {
 		err = c.Catalog.Validate()
 		if err != nil {
@@ -206,6 +284,13 @@ func (c *APIConfig) Validate() (err error) {
 		}
 	}
 
+	if c.Costs != nil {
+		err = c.Costs.Validate()
+		if err != nil {
+			return
+		}
+	}
+
 	// for _, credential := range c.Credentials {
 	// 	err = credential.Validate()
 	// 	if err != nil {
@@ -241,8 +326,20 @@ const (
 	LogTargetUnknown      LogTarget = ""
 	LogTargetDatabase     LogTarget = "database"
 	LogTargetCloudStorage LogTarget = "cloudstorage"
+	LogTargetS3           LogTarget = "s3"
+	LogTargetFilesystem   LogTarget = "filesystem"
 )
 
+// IsObjectStorage indicates if the log target stores the log lines as gzipped files, keyed by the id of the log record in the database
+func (t LogTarget) IsObjectStorage() bool {
+	return t == LogTargetCloudStorage || t == LogTargetS3 || t == LogTargetFilesystem
+}
+
+// IsValid indicates if the log target is one of the supported log targets
+func (t LogTarget) IsValid() bool {
+	return t == LogTargetDatabase || t.IsObjectStorage()
+}
+
 func (c *APIServerConfig) SetDefaults() {
 	if c.ServiceURL == "" {
 		c.ServiceURL = "http://ziplinee-ci-api.ziplinee-ci.svc.cluster.local"
@@ -284,6 +381,25 @@ func (c *APIServerConfig) Validate() (err error) {
 		return errors.New("Configuration item 'apiServer.logReader' is required; please set it to either 'database' or 'cloudstorage'")
 	}
 
+	objectStorageLogTargets := 0
+	for _, w := range c.LogWriters {
+		if !w.IsValid() {
+			return fmt.Errorf("Configuration item 'apiServer.logWriters' has unsupported value %v; please set it to %v, %v, %v or %v", w, LogTargetDatabase, LogTargetCloudStorage, LogTargetS3, LogTargetFilesystem)
+		}
+		if w.IsObjectStorage() {
+			objectStorageLogTargets++
+		}
+	}
+	if objectStorageLogTargets > 1 {
+		return fmt.Errorf("Configuration item 'apiServer.logWriters' can only contain one of %v, %v or %v", LogTargetCloudStorage, LogTargetS3, LogTargetFilesystem)
+	}
+	if !c.LogReader.IsValid() {
+		return fmt.Errorf("Configuration item 'apiServer.logReader' has unsupported value %v; please set it to %v, %v, %v or %v", c.LogReader, LogTargetDatabase, LogTargetCloudStorage, LogTargetS3, LogTargetFilesystem)
+	}
+	if c.LogReader.IsObjectStorage() && !LogTargetArrayContains(c.LogWriters, c.LogReader) {
+		return fmt.Errorf("Configuration item 'apiServer.logReader' is set to %v, but it's missing in 'apiServer.logWriters'", c.LogReader)
+	}
+
 	return nil
 }
 
@@ -323,6 +439,21 @@ func (c *APIServerConfig) WriteLogToCloudStorage() bool {
 	return LogTargetArrayContains(c.LogWriters, LogTargetCloudStorage)
 }
 
+// WriteLogToObjectStorage indicates if cloudstorage, s3 or filesystem is in the logWriters config
+func (c *APIServerConfig) WriteLogToObjectStorage() bool {
+	return c.ObjectStorageLogTarget() != LogTargetUnknown
+}
+
+// ObjectStorageLogTarget returns the cloudstorage, s3 or filesystem log target in the logWriters config, if any
+func (c *APIServerConfig) ObjectStorageLogTarget() LogTarget {
+	for _, w := range c.LogWriters {
+		if w.IsObjectStorage() {
+			return w
+		}
+	}
+	return LogTargetUnknown
+}
+
 // ReadLogFromDatabase indicates if logReader config is database
 func (c *APIServerConfig) ReadLogFromDatabase() bool {
 	return c.LogReader == LogTargetUnknown || c.LogReader == LogTargetDatabase
@@ -333,13 +464,24 @@ func (c *APIServerConfig) ReadLogFromCloudStorage() bool {
 	return c.LogReader == LogTargetCloudStorage
 }
 
+// ReadLogFromObjectStorage indicates if logReader config is cloudstorage, s3 or filesystem
+func (c *APIServerConfig) ReadLogFromObjectStorage() bool {
+	return c.LogReader.IsObjectStorage()
+}
+
 // AuthConfig determines whether to use IAP for authentication and authorization
 type AuthConfig struct {
 	JWT            *JWTConfig                `yaml:"jwt"`
 	Administrators []string                  `yaml:"administrators"`
 	Google         *OAuthProvider            `yaml:"google" env:"GOOGLE"`
 	Github         *OAuthProvider            `yaml:"github" env:"GITHUB"`
+	OIDC           []*OAuthProvider          `yaml:"oidc"`
 	Organizations  []*AuthOrganizationConfig `yaml:"organizations"`
+	// EnforcePipelinePermissions requires builds, releases and bots to be started and canceled with the matching permission,
+	// granted by a role or by a custom role bound to the pipeline; when disabled any user that can see a pipeline can do so,
+	// unless custom roles with the permission are bound to the pipeline. Token scopes apply either way. It's disabled by
+	// default, to keep existing installations working until roles and custom roles have been set up
+	EnforcePipelinePermissions bool `yaml:"enforcePipelinePermissions"`
 }
 
 func (c *AuthConfig) SetDefaults() {
@@ -356,10 +498,20 @@ func (c *AuthConfig) SetDefaults() {
 		c.Github.Name = "github"
 		c.Github.SetDefaults()
 	}
+	for _, provider := range c.OIDC {
+		if provider != nil {
+			provider.Type = OAuthProviderTypeOIDC
+			provider.SetDefaults()
+		}
+	}
+
+	// EnforcePipelinePermissions is left disabled unless configured, see its comment
 
 	for _, orgProviders := range c.Organizations {
 		for _, provider := range orgProviders.OAuthProviders {
-			provider.Organization = orgProviders.Name
+			if provider != nil {
+				provider.Organization = orgProviders.Name
+			}
 		}
 
 		orgProviders.SetDefaults()
@@ -384,6 +536,15 @@ func (c *AuthConfig) Validate() (err error) {
 			return
 		}
 	}
+	for _, provider := range c.OIDC {
+		if provider == nil {
+			continue
+		}
+		err = provider.Validate()
+		if err != nil {
+			return
+		}
+	}
 
 	for _, orgProviders := range c.Organizations {
 		err = orgProviders.Validate()
@@ -397,17 +558,123 @@ func (c *AuthConfig) Validate() (err error) {
 
 // AuthOrganizationConfig configures things relevant to each organization using the system
 type AuthOrganizationConfig struct {
-	Name           string           `yaml:"name"`
-	OAuthProviders []*OAuthProvider `yaml:"oauthProviders"`
+	Name           string               `yaml:"name"`
+	OAuthProviders []*OAuthProvider     `yaml:"oauthProviders"`
+	RoleMappings   []*RoleMappingConfig `yaml:"roleMappings"`
 }
 
 func (c *AuthOrganizationConfig) SetDefaults() {
+	for _, provider := range c.OAuthProviders {
+		if provider != nil {
+			provider.SetDefaults()
+		}
+	}
 }
 
 func (c *AuthOrganizationConfig) Validate() (err error) {
+	for _, provider := range c.OAuthProviders {
+		if provider == nil {
+			continue
+		}
+		err = provider.Validate()
+		if err != nil {
+			return
+		}
+	}
+
+	for i, mapping := range c.RoleMappings {
+		if mapping == nil {
+			continue
+		}
+		err = mapping.Validate()
+		if err != nil {
+			return fmt.Errorf("Role mapping %v of organization %v is invalid: %w", i, c.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetRoleMappings returns the role mappings configured for an organization
+func (config *AuthConfig) GetRoleMappings(organization string) (mappings []*RoleMappingConfig) {
+	mappings = []*RoleMappingConfig{}
+	if organization == "" {
+		return
+	}
+
+	for _, o := range config.Organizations {
+		if o != nil && o.Name == organization {
+			for _, m := range o.RoleMappings {
+				if m != nil {
+					mappings = append(mappings, m)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// RoleMappingConfig grants group memberships, organizations and roles to users logging in with a matching identity provider group or email address;
+// the groups, organizations and roles of all mappings for a provider are managed by these mappings and get revoked at login when no longer matching
+type RoleMappingConfig struct {
+	Provider              string   `yaml:"provider"`
+	IdentityProviderGroup string   `yaml:"idpGroup"`
+	EmailRegex            string   `yaml:"emailRegex"`
+	Groups                []string `yaml:"groups"`
+	Organizations         []string `yaml:"organizations"`
+	Roles                 []string `yaml:"roles"`
+}
+
+func (c *RoleMappingConfig) Validate() (err error) {
+	if c.IdentityProviderGroup == "" && c.EmailRegex == "" {
+		return errors.New("Role mapping needs property `idpGroup` or `emailRegex` to be set")
+	}
+	if c.EmailRegex != "" {
+		_, err = regexp.Compile(fmt.Sprintf("^%v$", strings.TrimSpace(c.EmailRegex)))
+		if err != nil {
+			return fmt.Errorf("Role mapping property `emailRegex` is invalid: %w", err)
+		}
+	}
+	if len(c.Groups) == 0 && len(c.Organizations) == 0 && len(c.Roles) == 0 {
+		return errors.New("Role mapping needs at least one of `groups`, `organizations` or `roles` to be set")
+	}
+	for _, r := range c.Roles {
+		if ToRole(r) == nil {
+			return fmt.Errorf("Role mapping role %v is unknown", r)
+		}
+		// the administrator role is reconciled against the configured administrators on every user update
+		if r == RoleAdministrator.String() {
+			return errors.New("Role mapping cannot grant the administrator role, use `auth.administrators` instead")
+		}
+	}
+
 	return nil
 }
 
+// AppliesToProvider returns whether the mapping is evaluated for logins with a provider
+func (c *RoleMappingConfig) AppliesToProvider(provider string) bool {
+	return c.Provider == "" || c.Provider == provider
+}
+
+// Matches returns whether a user with email address and identity provider groups matches all conditions of the mapping
+func (c *RoleMappingConfig) Matches(email string, identityProviderGroups []string) bool {
+	if c.IdentityProviderGroup != "" && !foundation.StringArrayContains(identityProviderGroups, c.IdentityProviderGroup) {
+		return false
+	}
+	if c.EmailRegex != "" {
+		if email == "" {
+			return false
+		}
+		match, err := regexp.MatchString(fmt.Sprintf("^%v$", strings.TrimSpace(c.EmailRegex)), email)
+		if err != nil || !match {
+			return false
+		}
+	}
+
+	return true
+}
+
 // IsConfiguredAsAdministrator returns for a user whether they're configured as administrator
 func (config *AuthConfig) IsConfiguredAsAdministrator(email string) bool {
 	if email == "" {
@@ -423,16 +690,37 @@ func (config *AuthConfig) IsConfiguredAsAdministrator(email string) bool {
 	return false
 }
 
+const (
+	// OAuthProviderTypeGoogle logs in with a google account
+	OAuthProviderTypeGoogle = "google"
+	// OAuthProviderTypeGithub logs in with a github account
+	OAuthProviderTypeGithub = "github"
+	// OAuthProviderTypeOIDC logs in with any OpenID Connect compliant identity provider, like keycloak, okta or azure ad
+	OAuthProviderTypeOIDC = "oidc"
+)
+
 // OAuthProvider is used to configure one or more oauth providers like google, github
 type OAuthProvider struct {
-	Name                   string `yaml:"name"`
-	ClientID               string `yaml:"clientID"`
-	ClientSecret           string `yaml:"clientSecret"`
-	Organization           string `yaml:"organization"`
-	AllowedIdentitiesRegex string `yaml:"allowedIdentitiesRegex"`
+	Name                   string      `yaml:"name"`
+	Type                   string      `yaml:"type"`
+	ClientID               string      `yaml:"clientID"`
+	ClientSecret           string      `yaml:"clientSecret"`
+	Organization           string      `yaml:"organization"`
+	AllowedIdentitiesRegex string      `yaml:"allowedIdentitiesRegex"`
+	OIDC                   *OIDCConfig `yaml:"oidc,omitempty"`
 }
 
 func (c *OAuthProvider) SetDefaults() {
+	// providers configured before the type property existed are identified by their name
+	if c.Type == "" {
+		c.Type = c.Name
+	}
+	if c.Type == OAuthProviderTypeOIDC {
+		if c.OIDC == nil {
+			c.OIDC = &OIDCConfig{}
+		}
+		c.OIDC.SetDefaults()
+	}
 }
 
 func (c *OAuthProvider) Validate() (err error) {
@@ -445,10 +733,73 @@ func (c *OAuthProvider) Validate() (err error) {
 	if c.ClientSecret == "" {
 		return errors.New("Oauth provider config property `clientSecret` is empty")
 	}
+	if c.Type == OAuthProviderTypeOIDC {
+		if c.OIDC == nil {
+			return fmt.Errorf("Oauth provider %v config property `oidc` is empty", c.Name)
+		}
+		err = c.OIDC.Validate()
+		if err != nil {
+			return fmt.Errorf("Oauth provider %v: %w", c.Name, err)
+		}
+	}
 
 	return nil
 }
 
+// OIDCConfig configures an OpenID Connect provider; its endpoints and keys are retrieved from the issuer's discovery document
+type OIDCConfig struct {
+	IssuerURL   string   `yaml:"issuerURL"`
+	Scopes      []string `yaml:"scopes"`
+	EmailClaim  string   `yaml:"emailClaim"`
+	NameClaim   string   `yaml:"nameClaim"`
+	GroupsClaim string   `yaml:"groupsClaim"`
+}
+
+func (c *OIDCConfig) SetDefaults() {
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"profile", "email"}
+	}
+	// the openid scope is what makes the provider return an id token
+	hasOpenIDScope := false
+	for _, s := range c.Scopes {
+		if s == "openid" {
+			hasOpenIDScope = true
+		}
+	}
+	if !hasOpenIDScope {
+		c.Scopes = append([]string{"openid"}, c.Scopes...)
+	}
+	if c.EmailClaim == "" {
+		c.EmailClaim = "email"
+	}
+	if c.NameClaim == "" {
+		c.NameClaim = "name"
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = "groups"
+	}
+}
+
+func (c *OIDCConfig) Validate() (err error) {
+	if c.IssuerURL == "" {
+		return errors.New("Oidc config property `issuerURL` is empty")
+	}
+	issuerURL, err := url.Parse(c.IssuerURL)
+	if err != nil || issuerURL.Host == "" || (issuerURL.Scheme != "https" && issuerURL.Scheme != "http") {
+		return fmt.Errorf("Oidc config property `issuerURL` %v is not a valid url", c.IssuerURL)
+	}
+
+	return nil
+}
+
+// getType returns the provider type, falling back to the name for providers that skipped SetDefaults
+func (p *OAuthProvider) getType() string {
+	if p.Type == "" {
+		return p.Name
+	}
+	return p.Type
+}
+
 // OAuthProviderInfo provides non configurable information for oauth providers
 type OAuthProviderInfo struct {
 	AuthURL  string
@@ -467,18 +818,29 @@ func (p *OAuthProvider) GetConfig(baseURL string) *oauth2.Config {
 		RedirectURL:  redirectURI,
 	}
 
-	switch p.Name {
-	case "google":
+	switch p.getType() {
+	case OAuthProviderTypeGoogle:
 		oauthConfig.Endpoint = endpoints.Google
 		oauthConfig.Scopes = []string{
 			"https://www.googleapis.com/auth/userinfo.profile",
 			"https://www.googleapis.com/auth/userinfo.email",
 		}
-	case "github":
+	case OAuthProviderTypeGithub:
 		oauthConfig.Endpoint = endpoints.GitHub
 		oauthConfig.Scopes = []string{
 			"user:email",
 		}
+	case OAuthProviderTypeOIDC:
+		discovery, err := getCachedOIDCDiscovery(p.OIDC.IssuerURL)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed retrieving discovery document for oidc provider %v", p.Name)
+			return nil
+		}
+		oauthConfig.Endpoint = oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		}
+		oauthConfig.Scopes = p.OIDC.Scopes
 	default:
 		return nil
 	}
@@ -486,15 +848,25 @@ func (p *OAuthProvider) GetConfig(baseURL string) *oauth2.Config {
 	return &oauthConfig
 }
 
-// AuthCodeURL returns the url to redirect to for login
-func (p *OAuthProvider) AuthCodeURL(baseURL, state string) string {
-	return p.GetConfig(baseURL).AuthCodeURL(state, oauth2.AccessTypeOnline)
+// AuthCodeURL returns the url to redirect to for login; oidc providers get the nonce to include in the id token
+func (p *OAuthProvider) AuthCodeURL(baseURL, state, nonce string) string {
+	config := p.GetConfig(baseURL)
+	if config == nil {
+		return ""
+	}
+
+	options := []oauth2.AuthCodeOption{oauth2.AccessTypeOnline}
+	if p.getType() == OAuthProviderTypeOIDC && nonce != "" {
+		options = append(options, oauth2.SetAuthURLParam("nonce", nonce))
+	}
+
+	return config.AuthCodeURL(state, options...)
 }
 
-// GetUserIdentity returns the user info after a token has been retrieved
-func (p *OAuthProvider) GetUserIdentity(ctx context.Context, config *oauth2.Config, token *oauth2.Token) (identity *contracts.UserIdentity, err error) {
-	switch p.Name {
-	case "google":
+// GetUserIdentity returns the user info after a token has been retrieved; for oidc providers the id token has to carry the nonce of the login
+func (p *OAuthProvider) GetUserIdentity(ctx context.Context, config *oauth2.Config, token *oauth2.Token, nonce string) (identity *contracts.UserIdentity, err error) {
+	switch p.getType() {
+	case OAuthProviderTypeGoogle:
 		oauth2Service, err := googleoauth2v2.NewService(ctx, option.WithTokenSource(config.TokenSource(ctx, token)))
 		if err != nil {
 			return nil, err
@@ -525,7 +897,7 @@ func (p *OAuthProvider) GetUserIdentity(ctx context.Context, config *oauth2.Conf
 
 		return identity, nil
 
-	case "github":
+	case OAuthProviderTypeGithub:
 		log.Debug().Msg("Fetching user details from github api")
 
 		body, callErr := p.callGithubAPI(ctx, "GET", "https://api.github.com/user", []int{http.StatusOK}, nil, "Bearer", token.AccessToken)
@@ -588,12 +960,53 @@ func (p *OAuthProvider) GetUserIdentity(ctx context.Context, config *oauth2.Conf
 			}
 		}
 
+		return identity, nil
+
+	case OAuthProviderTypeOIDC:
+		claims, err := p.getOIDCClaims(ctx, token, nonce)
+		if err != nil {
+			return nil, err
+		}
+
+		// the email address decides whether a user is an administrator and which role mappings apply, so it has to be verified
+		email := getOIDCStringClaim(claims, p.OIDC.EmailClaim)
+		if email != "" && !isOIDCEmailVerified(claims) {
+			return nil, fmt.Errorf("Email address %v from oidc provider %v: %w", email, p.Name, ErrOIDCEmailNotVerified)
+		}
+		username := getOIDCStringClaim(claims, p.OIDC.NameClaim)
+		if username == "" {
+			username = email
+		}
+
+		// map claims to user identity
+		identity = &contracts.UserIdentity{
+			Provider: p.Name,
+			Email:    email,
+			Name:     username,
+			ID:       getOIDCStringClaim(claims, "sub"),
+			Avatar:   getOIDCStringClaim(claims, "picture"),
+		}
+
 		return identity, nil
 	}
 
 	return nil, fmt.Errorf("The GetUser function has not been implemented for provider '%v'", p.Name)
 }
 
+// GetUserGroups returns the groups the identity provider reports for the user; only oidc providers report groups
+func (p *OAuthProvider) GetUserGroups(ctx context.Context, config *oauth2.Config, token *oauth2.Token, nonce string) (groups []string, err error) {
+	if p.getType() != OAuthProviderTypeOIDC {
+		return []string{}, nil
+	}
+
+	claims, err := p.getOIDCClaims(ctx, token, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return getOIDCStringArrayClaim(claims, p.OIDC.GroupsClaim), nil
+}
+
 func (p *OAuthProvider) callGithubAPI(ctx context.Context, method, url string, allowedStatusCodes []int, params interface{}, authorizationType, token string) (body []byte, err error) {
 
 	// convert params to json if they're present
@@ -696,21 +1109,95 @@ func (p *OAuthProvider) UserIsAllowed(ctx context.Context, email string) (isAllo
 // JWTConfig is used to configure JWT middleware
 type JWTConfig struct {
 	Domain string `yaml:"domain"`
-	// Key to sign JWT; use 256-bit key (or 32 bytes) minimum length
+	// Key to sign JWT; use 256-bit key (or 32 bytes) minimum length; when keys are configured it only verifies jwts without key id, issued before rotating to those keys
 	Key string `yaml:"key"`
+	// Keys to sign and verify JWTs by key id; add a new key and switch signingKeyID to it to rotate keys, and remove the old one once all jwts it signed have expired
+	Keys []*JWTKeyConfig `yaml:"keys"`
+	// SigningKeyID is the id of the key in keys used to sign new JWTs, defaults to the first key
+	SigningKeyID string `yaml:"signingKeyID"`
 }
 
 func (c *JWTConfig) SetDefaults() {
+	for _, k := range c.Keys {
+		if k != nil {
+			k.SetDefaults()
+		}
+	}
+
+	if c.SigningKeyID == "" && len(c.Keys) > 0 && c.Keys[0] != nil {
+		c.SigningKeyID = c.Keys[0].ID
+	}
 }
 
 func (c *JWTConfig) Validate() (err error) {
 	if c.Domain == "" {
 		return errors.New("Configuration item 'auth.jwt.domain' is required; please set it to the same host as used in 'apiServer.baseURL'")
 	}
-	if c.Key == "" {
+	if c.Key == "" && len(c.Keys) == 0 {
 		return errors.New("Configuration item 'auth.jwt.key' is required; please set it to a 256-bit key (or 32 bytes) at the minimum")
 	}
 
+	keyIDs := map[string]bool{}
+	for _, k := range c.Keys {
+		if k == nil {
+			continue
+		}
+		err = k.Validate()
+		if err != nil {
+			return
+		}
+		if keyIDs[k.ID] {
+			return fmt.Errorf("Configuration item 'auth.jwt.keys' has more than one key with id %v", k.ID)
+		}
+		keyIDs[k.ID] = true
+	}
+
+	if len(c.Keys) > 0 && !keyIDs[c.SigningKeyID] {
+		return fmt.Errorf("Configuration item 'auth.jwt.signingKeyID' is set to %v, but there is no key with that id in 'auth.jwt.keys'", c.SigningKeyID)
+	}
+
+	return nil
+}
+
+// JWTKeyConfig is a key to sign and verify JWTs, identified by the kid header of the JWTs it signs
+type JWTKeyConfig struct {
+	ID string `yaml:"id"`
+	// Algorithm is HS256 for a shared secret, or RS256 or ES256 to publish the public key for other services to verify JWTs with
+	Algorithm string `yaml:"algorithm"`
+	// Key is the shared secret for HS256; use 256-bit key (or 32 bytes) minimum length
+	Key string `yaml:"key"`
+	// PrivateKey is the pem encoded rsa private key for RS256 or P-256 ecdsa private key for ES256
+	PrivateKey string `yaml:"privateKey"`
+}
+
+func (c *JWTKeyConfig) SetDefaults() {
+	if c.Algorithm == "" {
+		c.Algorithm = JWTAlgorithmHS256
+	}
+}
+
+func (c *JWTKeyConfig) Validate() (err error) {
+	if c.ID == "" {
+		return errors.New("Configuration item 'auth.jwt.keys.id' is required")
+	}
+
+	switch c.Algorithm {
+	case JWTAlgorithmHS256:
+		if c.Key == "" {
+			return fmt.Errorf("Configuration item 'auth.jwt.keys.key' is required for HS256 key %v", c.ID)
+		}
+	case JWTAlgorithmRS256, JWTAlgorithmES256:
+		if c.PrivateKey == "" {
+			return fmt.Errorf("Configuration item 'auth.jwt.keys.privateKey' is required for %v key %v", c.Algorithm, c.ID)
+		}
+		_, err = parseJWTKey(c)
+		if err != nil {
+			return fmt.Errorf("Configuration item 'auth.jwt.keys.privateKey' of key %v is invalid: %w", c.ID, err)
+		}
+	default:
+		return fmt.Errorf("Configuration item 'auth.jwt.keys.algorithm' of key %v has unsupported value %v; supported values are HS256, RS256 and ES256", c.ID, c.Algorithm)
+	}
+
 	return nil
 }
 
@@ -734,6 +1221,15 @@ type JobsConfig struct {
 	BuildAffinityAndTolerations   *AffinityAndTolerationsConfig `yaml:"build"`
 	ReleaseAffinityAndTolerations *AffinityAndTolerationsConfig `yaml:"release"`
 	BotAffinityAndTolerations     *AffinityAndTolerationsConfig `yaml:"bot"`
+
+	// MaxRunDurationMinutes stops jobs running longer; the token a job uses to report back expires shortly after
+	MaxRunDurationMinutes int `yaml:"maxRunDurationMinutes"`
+
+	// cancel queued, pending and running builds for a branch when a newer build for that branch gets created
+	CancelSupersededBuilds bool `yaml:"cancelSupersededBuilds"`
+
+	// Sizing configures how the resource requests of a job are derived from the resource usage of previous jobs
+	Sizing *JobSizingConfig `yaml:"sizing"`
 }
 
 func (c *JobsConfig) SetDefaults() {
@@ -786,6 +1282,14 @@ func (c *JobsConfig) SetDefaults() {
 	if c.MemoryLimitRatio <= 0 {
 		c.MemoryLimitRatio = 1.0
 	}
+	if c.MaxRunDurationMinutes <= 0 {
+		c.MaxRunDurationMinutes = 360
+	}
+
+	if c.Sizing == nil {
+		c.Sizing = &JobSizingConfig{}
+	}
+	c.Sizing.SetDefaults()
 }
 
 func (c *JobsConfig) Validate() (err error) {
@@ -828,9 +1332,92 @@ func (c *JobsConfig) Validate() (err error) {
 		return errors.New("Configuration item 'jobs.memoryLimitRatio' is required; please set it to 1.0 or larger")
 	}
 
+	if c.Sizing != nil {
+		err = c.Sizing.Validate()
+		if err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+// JobSizingConfig configures the percentiles, history and bump-ups used for sizing the resource requests of jobs
+type JobSizingConfig struct {
+	// LastNRecords is the number of previous jobs to size on; with fewer than MinRecords of them the defaults are used
+	LastNRecords int `yaml:"lastNRecords"`
+	MinRecords   int `yaml:"minRecords"`
+
+	// CPUPercentile and MemoryPercentile select the max usage of previous jobs to size on, so a single outlier doesn't inflate requests
+	CPUPercentile    float64 `yaml:"cpuPercentile"`
+	MemoryPercentile float64 `yaml:"memoryPercentile"`
+
+	// ExtrapolateTrend sizes on the usage expected for the next job instead, if usage is growing and that is higher
+	ExtrapolateTrend *bool `yaml:"extrapolateTrend"`
+
+	// OOMBumpRatio multiplies the memory request after the previous job got killed for running out of memory; CPUThrottledBumpRatio
+	// multiplies the cpu request after the previous job failed while throttled for at least CPUThrottledThreshold of the time
+	OOMBumpRatio          float64 `yaml:"oomBumpRatio"`
+	CPUThrottledBumpRatio float64 `yaml:"cpuThrottledBumpRatio"`
+	CPUThrottledThreshold float64 `yaml:"cpuThrottledThreshold"`
+}
+
+func (c *JobSizingConfig) SetDefaults() {
+	if c.LastNRecords <= 0 {
+		c.LastNRecords = 25
+	}
+	if c.MinRecords <= 0 {
+		c.MinRecords = 5
+	}
+	if c.CPUPercentile <= 0 {
+		c.CPUPercentile = 90
+	}
+	if c.MemoryPercentile <= 0 {
+		c.MemoryPercentile = 95
+	}
+	if c.ExtrapolateTrend == nil {
+		trueValue := true
+		c.ExtrapolateTrend = &trueValue
+	}
+	if c.OOMBumpRatio <= 0 {
+		c.OOMBumpRatio = 1.5
+	}
+	if c.CPUThrottledBumpRatio <= 0 {
+		c.CPUThrottledBumpRatio = 1.25
+	}
+	if c.CPUThrottledThreshold <= 0 {
+		c.CPUThrottledThreshold = 0.25
+	}
+}
+
+func (c *JobSizingConfig) Validate() (err error) {
+	if c.MinRecords > c.LastNRecords {
+		return errors.New("Configuration item 'jobs.sizing.minRecords' should be less than or equal to 'jobs.sizing.lastNRecords'")
+	}
+	if c.CPUPercentile <= 0 || c.CPUPercentile > 100 {
+		return errors.New("Configuration item 'jobs.sizing.cpuPercentile' is required; please set it to a value larger than 0 and at most 100")
+	}
+	if c.MemoryPercentile <= 0 || c.MemoryPercentile > 100 {
+		return errors.New("Configuration item 'jobs.sizing.memoryPercentile' is required; please set it to a value larger than 0 and at most 100")
+	}
+	if c.OOMBumpRatio < 1.0 {
+		return errors.New("Configuration item 'jobs.sizing.oomBumpRatio' is required; please set it to 1.0 or larger")
+	}
+	if c.CPUThrottledBumpRatio < 1.0 {
+		return errors.New("Configuration item 'jobs.sizing.cpuThrottledBumpRatio' is required; please set it to 1.0 or larger")
+	}
+	if c.CPUThrottledThreshold <= 0 || c.CPUThrottledThreshold > 1.0 {
+		return errors.New("Configuration item 'jobs.sizing.cpuThrottledThreshold' is required; please set it to a fraction larger than 0 and at most 1.0")
+	}
+
 	return nil
 }
 
+// IsTrendExtrapolated returns true if the usage expected for the next job is taken into account when sizing
+func (c *JobSizingConfig) IsTrendExtrapolated() bool {
+	return c.ExtrapolateTrend == nil || *c.ExtrapolateTrend
+}
+
 type AffinityAndTolerationsConfig struct {
 	Affinity    *v1.Affinity    `yaml:"affinity"`
 	Tolerations []v1.Toleration `yaml:"tolerations"`
@@ -971,20 +1558,546 @@ func (c *QueueConfig) Validate() (err error) {
 	return nil
 }
 
-// CatalogConfig configures various aspect of the catalog page
-type CatalogConfig struct {
-	Filters []string `yaml:"filters,omitempty" json:"filters,omitempty"`
-}
+// JobQueueConfig configures the queue holding build, release and bot jobs until they can be dispatched within the concurrency limits
+type JobQueueConfig struct {
+	Enable              bool `yaml:"enable"`
+	PollIntervalSeconds int  `yaml:"pollIntervalSeconds"`
 
-func (c *CatalogConfig) SetDefaults() {
-}
+	// limits on the number of concurrently pending and running jobs; 0 means unlimited
+	MaxConcurrentJobs                int `yaml:"maxConcurrentJobs"`
+	MaxConcurrentJobsPerOrganization int `yaml:"maxConcurrentJobsPerOrganization"`
+	MaxConcurrentJobsPerPipeline     int `yaml:"maxConcurrentJobsPerPipeline"`
+	MaxConcurrentBuilds              int `yaml:"maxConcurrentBuilds"`
+	MaxConcurrentReleases            int `yaml:"maxConcurrentReleases"`
+	MaxConcurrentBots                int `yaml:"maxConcurrentBots"`
 
-func (c *CatalogConfig) Validate() (err error) {
-	return nil
+	// MaxDispatchAttempts is the number of times creating the builder job for a queued job is tried before it's set to failed
+	MaxDispatchAttempts int `yaml:"maxDispatchAttempts"`
 }
 
-// APIConfigIntegrations contains config for 3rd party integrations
-type APIConfigIntegrations struct {
+func (c *JobQueueConfig) SetDefaults() {
+	if !c.Enable {
+		return
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		c.PollIntervalSeconds = 5
+	}
+	if c.MaxDispatchAttempts <= 0 {
+		c.MaxDispatchAttempts = 5
+	}
+}
+
+func (c *JobQueueConfig) Validate() (err error) {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		return errors.New("Configuration item 'jobQueue.pollIntervalSeconds' is required; please set it to a number of seconds larger than 0")
+	}
+	if c.MaxConcurrentJobs < 0 || c.MaxConcurrentJobsPerOrganization < 0 || c.MaxConcurrentJobsPerPipeline < 0 || c.MaxConcurrentBuilds < 0 || c.MaxConcurrentReleases < 0 || c.MaxConcurrentBots < 0 {
+		return errors.New("Configuration items 'jobQueue.maxConcurrent*' cannot be negative; please set them to 0 for unlimited or to a number larger than 0")
+	}
+	if c.MaxDispatchAttempts <= 0 {
+		return errors.New("Configuration item 'jobQueue.maxDispatchAttempts' is required; please set it to a number larger than 0")
+	}
+
+	return nil
+}
+
+// MaxConcurrentJobsForJobType returns the configured limit for a specific job type; 0 means unlimited
+func (c *JobQueueConfig) MaxConcurrentJobsForJobType(jobType contracts.JobType) int {
+	switch jobType {
+	case contracts.JobTypeBuild:
+		return c.MaxConcurrentBuilds
+	case contracts.JobTypeRelease:
+		return c.MaxConcurrentReleases
+	case contracts.JobTypeBot:
+		return c.MaxConcurrentBots
+	}
+
+	return 0
+}
+
+// MigrationConfig configures the background worker that moves pipelines with their builds, releases and logs to another repository
+type MigrationConfig struct {
+	Enable              bool `yaml:"enable"`
+	PollIntervalSeconds int  `yaml:"pollIntervalSeconds"`
+	MaxConcurrentTasks  int  `yaml:"maxConcurrentTasks"`
+}
+
+func (c *MigrationConfig) SetDefaults() {
+	if !c.Enable {
+		return
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		c.PollIntervalSeconds = 10
+	}
+	if c.MaxConcurrentTasks <= 0 {
+		c.MaxConcurrentTasks = 1
+	}
+}
+
+func (c *MigrationConfig) Validate() (err error) {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		return errors.New("Configuration item 'migration.pollIntervalSeconds' is required; please set it to a number of seconds larger than 0")
+	}
+	if c.MaxConcurrentTasks <= 0 {
+		return errors.New("Configuration item 'migration.maxConcurrentTasks' is required; please set it to a number larger than 0")
+	}
+
+	return nil
+}
+
+// LogRetentionConfig configures the background pruning of logs that have outlived their retention
+type LogRetentionConfig struct {
+	Enable              bool               `yaml:"enable"`
+	PollIntervalSeconds int                `yaml:"pollIntervalSeconds"`
+	BatchSize           int                `yaml:"batchSize"`
+	Rules               []LogRetentionRule `yaml:"rules"`
+}
+
+// LogRetentionRule sets the number of days logs are kept for a log target, job type and set of pipeline labels; empty fields match anything
+type LogRetentionRule struct {
+	LogTarget     LogTarget         `yaml:"logTarget,omitempty"`
+	JobType       contracts.JobType `yaml:"jobType,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	RetentionDays int               `yaml:"retentionDays"`
+}
+
+func (c *LogRetentionConfig) SetDefaults() {
+	if !c.Enable {
+		return
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		c.PollIntervalSeconds = 3600
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 500
+	}
+}
+
+func (c *LogRetentionConfig) Validate() (err error) {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		return errors.New("Configuration item 'logRetention.pollIntervalSeconds' is required; please set it to a number of seconds larger than 0")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("Configuration item 'logRetention.batchSize' is required; please set it to a number larger than 0")
+	}
+	for i, r := range c.Rules {
+		if r.RetentionDays <= 0 {
+			return fmt.Errorf("Configuration item 'logRetention.rules[%v].retentionDays' is required; please set it to a number of days larger than 0", i)
+		}
+		if r.LogTarget != LogTargetUnknown && !r.LogTarget.IsValid() {
+			return fmt.Errorf("Configuration item 'logRetention.rules[%v].logTarget' has unsupported value %v; please set it to %v, %v, %v or %v", i, r.LogTarget, LogTargetDatabase, LogTargetCloudStorage, LogTargetS3, LogTargetFilesystem)
+		}
+		if r.JobType != contracts.JobTypeUnknown && r.JobType != contracts.JobTypeBuild && r.JobType != contracts.JobTypeRelease && r.JobType != contracts.JobTypeBot {
+			return fmt.Errorf("Configuration item 'logRetention.rules[%v].jobType' has unsupported value %v; please set it to %v, %v or %v", i, r.JobType, contracts.JobTypeBuild, contracts.JobTypeRelease, contracts.JobTypeBot)
+		}
+	}
+
+	return nil
+}
+
+// GetRetentionDays returns the retention of the first rule matching the log target, job type and pipeline labels; logs without a matching rule are kept forever
+func (c *LogRetentionConfig) GetRetentionDays(logTarget LogTarget, jobType contracts.JobType, labels []contracts.Label) (retentionDays int, found bool) {
+	for _, r := range c.Rules {
+		if r.LogTarget != LogTargetUnknown && r.LogTarget != logTarget {
+			continue
+		}
+		if r.JobType != contracts.JobTypeUnknown && r.JobType != jobType {
+			continue
+		}
+		if !labelsContain(labels, r.Labels) {
+			continue
+		}
+
+		return r.RetentionDays, true
+	}
+
+	return 0, false
+}
+
+// LogReconciliationConfig configures the background worker that copies logs missing in one log target from another one
+type LogReconciliationConfig struct {
+	Enable              bool `yaml:"enable"`
+	PollIntervalSeconds int  `yaml:"pollIntervalSeconds"`
+}
+
+func (c *LogReconciliationConfig) SetDefaults() {
+	if !c.Enable {
+		return
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		c.PollIntervalSeconds = 30
+	}
+}
+
+func (c *LogReconciliationConfig) Validate() (err error) {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		return errors.New("Configuration item 'logReconciliation.pollIntervalSeconds' is required; please set it to a number of seconds larger than 0")
+	}
+
+	return nil
+}
+
+// NotificationEventType is the kind of event dispatched to notification channels
+type NotificationEventType string
+
+const (
+	NotificationEventTypeBuild        NotificationEventType = "build"
+	NotificationEventTypeRelease      NotificationEventType = "release"
+	NotificationEventTypeBot          NotificationEventType = "bot"
+	NotificationEventTypeNotification NotificationEventType = "notification"
+)
+
+// NotificationChannelType is the way a notification channel delivers events
+type NotificationChannelType string
+
+const (
+	NotificationChannelTypeWebhook NotificationChannelType = "webhook"
+	NotificationChannelTypeSlack   NotificationChannelType = "slack"
+	NotificationChannelTypeEmail   NotificationChannelType = "email"
+)
+
+// NotificationsConfig configures the dispatcher that delivers build, release and bot status changes and stored notifications to
+// channels, with the routes deciding which events go to which channels
+type NotificationsConfig struct {
+	Enable                bool                         `yaml:"enable"`
+	PollIntervalSeconds   int                          `yaml:"pollIntervalSeconds"`
+	BatchSize             int                          `yaml:"batchSize"`
+	MaxAttempts           int                          `yaml:"maxAttempts"`
+	InitialBackoffSeconds int                          `yaml:"initialBackoffSeconds"`
+	MaxBackoffSeconds     int                          `yaml:"maxBackoffSeconds"`
+	TimeoutSeconds        int                          `yaml:"timeoutSeconds"`
+	Channels              []*NotificationChannelConfig `yaml:"channels"`
+	Routes                []NotificationRoute          `yaml:"routes"`
+}
+
+// NotificationChannelConfig is a destination for notifications; webhooks are signed with the signing secret if set, slack
+// channels post to an incoming webhook url and email channels send to their recipients through an smtp server
+type NotificationChannelConfig struct {
+	Name          string                  `yaml:"name"`
+	Type          NotificationChannelType `yaml:"type"`
+	URL           string                  `yaml:"url,omitempty"`
+	SigningSecret string                  `yaml:"signingSecret,omitempty"`
+	SMTP          *SMTPConfig             `yaml:"smtp,omitempty"`
+	Recipients    []string                `yaml:"recipients,omitempty"`
+}
+
+// SMTPConfig is the smtp server used by an email notification channel
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	From     string `yaml:"from"`
+}
+
+// NotificationRoute sends the events matching its event types, statuses, organizations and pipeline labels to its channels;
+// empty fields match anything
+type NotificationRoute struct {
+	Events        []NotificationEventType `yaml:"events,omitempty"`
+	Statuses      []contracts.Status      `yaml:"statuses,omitempty"`
+	Organizations []string                `yaml:"organizations,omitempty"`
+	Labels        map[string]string       `yaml:"labels,omitempty"`
+	Channels      []string                `yaml:"channels"`
+}
+
+func (c *NotificationsConfig) SetDefaults() {
+	if !c.Enable {
+		return
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		c.PollIntervalSeconds = 10
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 50
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.InitialBackoffSeconds <= 0 {
+		c.InitialBackoffSeconds = 30
+	}
+	if c.MaxBackoffSeconds <= 0 {
+		c.MaxBackoffSeconds = 3600
+	}
+	if c.TimeoutSeconds <= 0 {
+		c.TimeoutSeconds = 10
+	}
+	for _, ch := range c.Channels {
+		if ch.Type == NotificationChannelTypeEmail && ch.SMTP != nil && ch.SMTP.Port <= 0 {
+			ch.SMTP.Port = 587
+		}
+	}
+}
+
+func (c *NotificationsConfig) Validate() (err error) {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.PollIntervalSeconds <= 0 {
+		return errors.New("Configuration item 'notifications.pollIntervalSeconds' is required; please set it to a number of seconds larger than 0")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("Configuration item 'notifications.batchSize' is required; please set it to a number larger than 0")
+	}
+	if c.MaxAttempts <= 0 {
+		return errors.New("Configuration item 'notifications.maxAttempts' is required; please set it to a number larger than 0")
+	}
+
+	names := map[string]bool{}
+	for i, ch := range c.Channels {
+		if ch.Name == "" {
+			return fmt.Errorf("Configuration item 'notifications.channels[%v].name' is required; please set it to a unique name", i)
+		}
+		if names[ch.Name] {
+			return fmt.Errorf("Configuration item 'notifications.channels[%v].name' has duplicate value %v; please set it to a unique name", i, ch.Name)
+		}
+		names[ch.Name] = true
+
+		switch ch.Type {
+		case NotificationChannelTypeWebhook, NotificationChannelTypeSlack:
+			if ch.URL == "" {
+				return fmt.Errorf("Configuration item 'notifications.channels[%v].url' is required for %v channels; please set it to the url to post notifications to", i, ch.Type)
+			}
+		case NotificationChannelTypeEmail:
+			if ch.SMTP == nil || ch.SMTP.Host == "" || ch.SMTP.From == "" {
+				return fmt.Errorf("Configuration items 'notifications.channels[%v].smtp.host' and 'notifications.channels[%v].smtp.from' are required for email channels", i, i)
+			}
+			if len(ch.Recipients) == 0 {
+				return fmt.Errorf("Configuration item 'notifications.channels[%v].recipients' is required for email channels; please set it to at least one email address", i)
+			}
+		default:
+			return fmt.Errorf("Configuration item 'notifications.channels[%v].type' has unsupported value %v; please set it to %v, %v or %v", i, ch.Type, NotificationChannelTypeWebhook, NotificationChannelTypeSlack, NotificationChannelTypeEmail)
+		}
+	}
+
+	for i, r := range c.Routes {
+		if len(r.Channels) == 0 {
+			return fmt.Errorf("Configuration item 'notifications.routes[%v].channels' is required; please set it to at least one channel name", i)
+		}
+		for _, n := range r.Channels {
+			if !names[n] {
+				return fmt.Errorf("Configuration item 'notifications.routes[%v].channels' has unknown channel %v; please add it to 'notifications.channels'", i, n)
+			}
+		}
+		for _, e := range r.Events {
+			if e != NotificationEventTypeBuild && e != NotificationEventTypeRelease && e != NotificationEventTypeBot && e != NotificationEventTypeNotification {
+				return fmt.Errorf("Configuration item 'notifications.routes[%v].events' has unsupported value %v; please set it to %v, %v, %v or %v", i, e, NotificationEventTypeBuild, NotificationEventTypeRelease, NotificationEventTypeBot, NotificationEventTypeNotification)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetChannels returns the channels of all routes matching an event, each channel once
+func (c *NotificationsConfig) GetChannels(eventType NotificationEventType, status contracts.Status, organizations []string, labels []contracts.Label) (channels []*NotificationChannelConfig) {
+	channels = []*NotificationChannelConfig{}
+	for _, r := range c.Routes {
+		if len(r.Events) > 0 && !notificationEventTypesContain(r.Events, eventType) {
+			continue
+		}
+		if len(r.Statuses) > 0 && !statusesContain(r.Statuses, status) {
+			continue
+		}
+		if len(r.Organizations) > 0 && !stringArraysOverlap(r.Organizations, organizations) {
+			continue
+		}
+		if !labelsContain(labels, r.Labels) {
+			continue
+		}
+
+		for _, n := range r.Channels {
+			channel := c.GetChannel(n)
+			if channel != nil && !notificationChannelsContain(channels, n) {
+				channels = append(channels, channel)
+			}
+		}
+	}
+
+	return channels
+}
+
+// GetChannel returns the channel with a name, or nil if it isn't configured (anymore)
+func (c *NotificationsConfig) GetChannel(name string) *NotificationChannelConfig {
+	for _, ch := range c.Channels {
+		if ch.Name == name {
+			return ch
+		}
+	}
+
+	return nil
+}
+
+// GetBackoff returns how long to wait before the next attempt after a number of failed attempts, doubling every attempt
+func (c *NotificationsConfig) GetBackoff(attempts int) time.Duration {
+	backoff := time.Duration(c.InitialBackoffSeconds) * time.Second
+	maxBackoff := time.Duration(c.MaxBackoffSeconds) * time.Second
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return backoff
+}
+
+func notificationEventTypesContain(eventTypes []NotificationEventType, eventType NotificationEventType) bool {
+	for _, e := range eventTypes {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func notificationChannelsContain(channels []*NotificationChannelConfig, name string) bool {
+	for _, ch := range channels {
+		if ch.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func statusesContain(statuses []contracts.Status, status contracts.Status) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringArraysOverlap(a, b []string) bool {
+	for _, s := range a {
+		if StringArrayContains(b, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func labelsContain(labels []contracts.Label, required map[string]string) bool {
+	for key, value := range required {
+		found := false
+		for _, l := range labels {
+			if l.Key == key && l.Value == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CostsConfig is the cost model for attributing the spend on ci jobs to organizations, groups, pipelines and labels; each job
+// costs its cpu and memory requests times its duration, priced by the node pool its job type runs on
+type CostsConfig struct {
+	Currency string              `yaml:"currency"`
+	Prices   []*CostsPriceConfig `yaml:"prices"`
+}
+
+// CostsPriceConfig prices a node pool per core-hour and per GB-hour, a GB being 2^30 bytes; it applies to the jobs of its job types,
+// or to all jobs not priced by another node pool if it has none
+type CostsPriceConfig struct {
+	NodePool         string              `yaml:"nodePool"`
+	JobTypes         []contracts.JobType `yaml:"jobTypes,omitempty"`
+	PricePerCoreHour float64             `yaml:"pricePerCoreHour"`
+	PricePerGBHour   float64             `yaml:"pricePerGBHour"`
+}
+
+func (c *CostsConfig) SetDefaults() {
+	if c.Currency == "" {
+		c.Currency = "USD"
+	}
+}
+
+func (c *CostsConfig) Validate() (err error) {
+	if len(c.Prices) == 0 {
+		return errors.New("Configuration item 'costs.prices' is required; please set the price per core-hour and GB-hour for the node pool of each job type")
+	}
+	for _, p := range c.Prices {
+		if p.NodePool == "" {
+			return errors.New("Configuration item 'costs.prices.nodePool' is required; please set it to the name of the node pool jobs run on")
+		}
+		if p.PricePerCoreHour < 0 || p.PricePerGBHour < 0 {
+			return fmt.Errorf("Configuration items 'costs.prices.pricePerCoreHour' and 'costs.prices.pricePerGBHour' for node pool %v cannot be negative", p.NodePool)
+		}
+		for _, jt := range p.JobTypes {
+			if jt != contracts.JobTypeBuild && jt != contracts.JobTypeRelease && jt != contracts.JobTypeBot {
+				return fmt.Errorf("Configuration item 'costs.prices.jobTypes' for node pool %v has invalid job type %v; use build, release or bot", p.NodePool, jt)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetPrice returns the price of the node pool jobs of a job type run on, or nil if they aren't priced
+func (c *CostsConfig) GetPrice(jobType contracts.JobType) *CostsPriceConfig {
+	var defaultPrice *CostsPriceConfig
+	for _, p := range c.Prices {
+		if len(p.JobTypes) == 0 {
+			if defaultPrice == nil {
+				defaultPrice = p
+			}
+			continue
+		}
+		for _, jt := range p.JobTypes {
+			if jt == jobType {
+				return p
+			}
+		}
+	}
+
+	return defaultPrice
+}
+
+// CatalogConfig configures various aspect of the catalog page
+type CatalogConfig struct {
+	Filters []string `yaml:"filters,omitempty" json:"filters,omitempty"`
+}
+
+func (c *CatalogConfig) SetDefaults() {
+}
+
+func (c *CatalogConfig) Validate() (err error) {
+	return nil
+}
+
+// APIConfigIntegrations contains config for 3rd party integrations
+type APIConfigIntegrations struct {
 	Github       *GithubConfig       `yaml:"github,omitempty"`
 	Bitbucket    *BitbucketConfig    `yaml:"bitbucket,omitempty"`
 	Slack        *SlackConfig        `yaml:"slack,omitempty"`
@@ -992,6 +2105,8 @@ type APIConfigIntegrations struct {
 	Prometheus   *PrometheusConfig   `yaml:"prometheus,omitempty"`
 	BigQuery     *BigQueryConfig     `yaml:"bigquery,omitempty"`
 	CloudStorage *CloudStorageConfig `yaml:"gcs,omitempty"`
+	S3Storage    *S3StorageConfig    `yaml:"s3,omitempty"`
+	Filesystem   *FilesystemConfig   `yaml:"filesystem,omitempty"`
 	CloudSource  *CloudSourceConfig  `yaml:"cloudsource,omitempty"`
 }
 
@@ -1036,6 +2151,16 @@ func (c *APIConfigIntegrations) SetDefaults() {
 	}
 	c.CloudStorage.SetDefaults()
 
+	if c.S3Storage == nil {
+		c.S3Storage = &S3StorageConfig{}
+	}
+	c.S3Storage.SetDefaults()
+
+	if c.Filesystem == nil {
+		c.Filesystem = &FilesystemConfig{}
+	}
+	c.Filesystem.SetDefaults()
+
 	if c.CloudSource == nil {
 		c.CloudSource = &CloudSourceConfig{}
 	}
@@ -1083,6 +2208,16 @@ func (c *APIConfigIntegrations) Validate() (err error) {
 		return
 	}
 
+	err = c.S3Storage.Validate()
+	if err != nil {
+		return
+	}
+
+	err = c.Filesystem.Validate()
+	if err != nil {
+		return
+	}
+
 	err = c.CloudSource.Validate()
 	if err != nil {
 		return
@@ -1093,7 +2228,8 @@ func (c *APIConfigIntegrations) Validate() (err error) {
 
 // GithubConfig is used to configure github integration
 type GithubConfig struct {
-	Enable bool `yaml:"enable"`
+	Enable            bool `yaml:"enable"`
+	InsecureLocalMode bool `yaml:"insecureLocalMode"`
 }
 
 func (c *GithubConfig) SetDefaults() {
@@ -1139,12 +2275,14 @@ type OwnerOrganizations struct {
 	Organizations []*contracts.Organization `yaml:"organizations"`
 }
 
-// SlackConfig is used to configure slack integration
+// SlackConfig is used to configure slack integration; requests from Slack are verified with the signing secret, or with the
+// legacy verification token if no signing secret is set
 type SlackConfig struct {
 	Enable               bool   `yaml:"enable"`
 	ClientID             string `yaml:"clientID"`
 	ClientSecret         string `yaml:"clientSecret"`
-	AppVerificationToken string `yaml:"appVerificationToken"`
+	SigningSecret        string `yaml:"signingSecret,omitempty"`
+	AppVerificationToken string `yaml:"appVerificationToken,omitempty"`
 	AppOAuthAccessToken  string `yaml:"appOAuthAccessToken"`
 }
 
@@ -1165,8 +2303,8 @@ func (c *SlackConfig) Validate() (err error) {
 	if c.ClientSecret == "" {
 		return errors.New("Configuration item 'integrations.slack.clientSecret' is required; please set it to a Slack client secret")
 	}
-	if c.AppVerificationToken == "" {
-		return errors.New("Configuration item 'integrations.slack.appVerificationToken' is required; please set it to a Slack app verification token")
+	if c.SigningSecret == "" && c.AppVerificationToken == "" {
+		return errors.New("Configuration item 'integrations.slack.signingSecret' is required; please set it to the Slack app signing secret")
 	}
 	if c.AppOAuthAccessToken == "" {
 		return errors.New("Configuration item 'integrations.slack.appOAuthAccessToken' is required; please set it to a Slack app OAuth access token")
@@ -1265,6 +2403,70 @@ func (c *CloudStorageConfig) Validate() (err error) {
 	return nil
 }
 
+// S3StorageConfig is used to configure an s3 compatible bucket, like aws s3 or minio, to be used to store logs
+type S3StorageConfig struct {
+	Enable          bool   `yaml:"enable"`
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	Bucket          string `yaml:"bucket"`
+	AccessKeyID     string `yaml:"accessKeyID"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	Insecure        bool   `yaml:"insecure"`
+	LogsDirectory   string `yaml:"logsDir"`
+}
+
+func (c *S3StorageConfig) SetDefaults() {
+	if !c.Enable {
+		return
+	}
+
+	if c.LogsDirectory == "" {
+		c.LogsDirectory = "logs"
+	}
+}
+
+func (c *S3StorageConfig) Validate() (err error) {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.Endpoint == "" {
+		return errors.New("Configuration item 'integrations.s3.endpoint' is required; please set it to the host and optional port of the s3 compatible storage, without scheme")
+	}
+	if c.Bucket == "" {
+		return errors.New("Configuration item 'integrations.s3.bucket' is required; please set it to a bucket name you want to write logs to")
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return errors.New("Configuration items 'integrations.s3.accessKeyID' and 'integrations.s3.secretAccessKey' are required; please set them to credentials with read and write access to the bucket")
+	}
+	if c.LogsDirectory == "" {
+		return errors.New("Configuration item 'integrations.s3.logsDir' is required; please set it to the directory within the bucket you want to write logs to")
+	}
+
+	return nil
+}
+
+// FilesystemConfig is used to configure a local or network mounted directory to be used to store logs
+type FilesystemConfig struct {
+	Enable        bool   `yaml:"enable"`
+	LogsDirectory string `yaml:"logsDir"`
+}
+
+func (c *FilesystemConfig) SetDefaults() {
+}
+
+func (c *FilesystemConfig) Validate() (err error) {
+	if !c.Enable {
+		return nil
+	}
+
+	if c.LogsDirectory == "" || !filepath.IsAbs(c.LogsDirectory) {
+		return errors.New("Configuration item 'integrations.filesystem.logsDir' is required; please set it to the absolute path of the directory you want to write logs to")
+	}
+
+	return nil
+}
+
 // PrometheusConfig configures where to find prometheus for retrieving max cpu and memory consumption of build and release jobs
 type PrometheusConfig struct {
 	Enable                *bool  `yaml:"enable"`
@@ -1310,6 +2512,12 @@ type BigQueryConfig struct {
 	Enable    bool   `yaml:"enable"`
 	ProjectID string `yaml:"projectID"`
 	Dataset   string `yaml:"dataset"`
+
+	// events that fail to get inserted are stored in this directory and retried every retryIntervalSeconds; mount a persistent
+	// volume here to keep them when the api restarts
+	RetryBufferDirectory string `yaml:"retryBufferDirectory"`
+	MaxBufferedEvents    int    `yaml:"maxBufferedEvents"`
+	RetryIntervalSeconds int    `yaml:"retryIntervalSeconds"`
 }
 
 func (c *BigQueryConfig) SetDefaults() {
@@ -1320,6 +2528,12 @@ func (c *BigQueryConfig) SetDefaults() {
 	if c.Dataset == "" {
 		c.Dataset = "ziplinee_ci"
 	}
+	if c.MaxBufferedEvents <= 0 {
+		c.MaxBufferedEvents = 10000
+	}
+	if c.RetryIntervalSeconds <= 0 {
+		c.RetryIntervalSeconds = 60
+	}
 }
 
 func (c *BigQueryConfig) Validate() (err error) {
@@ -1333,6 +2547,9 @@ func (c *BigQueryConfig) Validate() (err error) {
 	if c.Dataset == "" {
 		return errors.New("Configuration item 'integrations.bigquery.dataset' is required; please set it to a BigQuery dataset name for a BigQuery table to get created in and written to")
 	}
+	if c.RetryBufferDirectory == "" {
+		return errors.New("Configuration item 'integrations.bigquery.retryBufferDirectory' is required; please set it to a directory on a persistent volume where events are stored when inserting them fails")
+	}
 
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"math"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
@@ -49,6 +50,7 @@ func TestReadConfigFromFiles(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.True(t, githubConfig.Enable)
+		assert.False(t, githubConfig.InsecureLocalMode)
 	})
 
 	t.Run("ReturnsBitbucketConfig", func(t *testing.T) {
@@ -113,6 +115,7 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.True(t, slackConfig.Enable)
 		assert.Equal(t, "d9ew90weoijewjke", slackConfig.ClientID)
 		assert.Equal(t, "this is my secret", slackConfig.ClientSecret)
+		assert.Equal(t, "this is my secret", slackConfig.SigningSecret)
 		assert.Equal(t, "this is my secret", slackConfig.AppVerificationToken)
 		assert.Equal(t, "this is my secret", slackConfig.AppOAuthAccessToken)
 	})
@@ -145,6 +148,9 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.True(t, bigqueryConfig.Enable)
 		assert.Equal(t, "my-gcp-project", bigqueryConfig.ProjectID)
 		assert.Equal(t, "my-dataset", bigqueryConfig.Dataset)
+		assert.Equal(t, "/tmp/bigquery", bigqueryConfig.RetryBufferDirectory)
+		assert.Equal(t, 5000, bigqueryConfig.MaxBufferedEvents)
+		assert.Equal(t, 30, bigqueryConfig.RetryIntervalSeconds)
 	})
 
 	t.Run("ReturnsCloudStorageConfig", func(t *testing.T) {
@@ -163,6 +169,40 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.Equal(t, "logs", cloudStorageConfig.LogsDirectory)
 	})
 
+	t.Run("ReturnsS3StorageConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		s3StorageConfig := config.Integrations.S3Storage
+
+		assert.Nil(t, err)
+		assert.True(t, s3StorageConfig.Enable)
+		assert.Equal(t, "minio.ziplinee.svc.cluster.local:9000", s3StorageConfig.Endpoint)
+		assert.Equal(t, "us-east-1", s3StorageConfig.Region)
+		assert.Equal(t, "my-bucket", s3StorageConfig.Bucket)
+		assert.Equal(t, "ziplinee", s3StorageConfig.AccessKeyID)
+		assert.Equal(t, "s3cr3t", s3StorageConfig.SecretAccessKey)
+		assert.True(t, s3StorageConfig.Insecure)
+		assert.Equal(t, "logs", s3StorageConfig.LogsDirectory)
+	})
+
+	t.Run("ReturnsFilesystemConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		filesystemConfig := config.Integrations.Filesystem
+
+		assert.Nil(t, err)
+		assert.True(t, filesystemConfig.Enable)
+		assert.Equal(t, "/var/lib/ziplinee/logs", filesystemConfig.LogsDirectory)
+	})
+
 	t.Run("ReturnsAPIServerConfig", func(t *testing.T) {
 
 		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
@@ -240,6 +280,7 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, "ci.ziplinee.io", authConfig.JWT.Domain)
 		assert.Equal(t, "this is my secret", authConfig.JWT.Key)
+		assert.True(t, authConfig.EnforcePipelinePermissions)
 
 		assert.Equal(t, "google", authConfig.Google.Name)
 		assert.Equal(t, "abcdasa", authConfig.Google.ClientID)
@@ -270,6 +311,54 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.Equal(t, "admin2@server.com", authConfig.Administrators[1])
 	})
 
+	t.Run("ReturnsOIDCAuthConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		authConfig := config.Auth
+
+		assert.Nil(t, err)
+		assert.Equal(t, "google", authConfig.Google.Type)
+		assert.Equal(t, "github", authConfig.Github.Type)
+		assert.Equal(t, "google", authConfig.Organizations[0].OAuthProviders[0].Type)
+
+		if assert.Equal(t, 1, len(authConfig.OIDC)) {
+			provider := authConfig.OIDC[0]
+			assert.Equal(t, "keycloak", provider.Name)
+			assert.Equal(t, OAuthProviderTypeOIDC, provider.Type)
+			assert.Equal(t, "ziplinee-ci", provider.ClientID)
+			assert.Equal(t, "https://keycloak.ziplinee.io/realms/ziplinee", provider.OIDC.IssuerURL)
+			assert.Equal(t, []string{"openid", "profile", "email", "groups"}, provider.OIDC.Scopes)
+			assert.Equal(t, "email", provider.OIDC.EmailClaim)
+			assert.Equal(t, "name", provider.OIDC.NameClaim)
+			assert.Equal(t, "realm_access.roles", provider.OIDC.GroupsClaim)
+		}
+	})
+
+	t.Run("ReturnsRoleMappingsConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		assert.Nil(t, err)
+		mappings := config.Auth.GetRoleMappings("Org A")
+		if assert.Equal(t, 2, len(mappings)) {
+			assert.Equal(t, "keycloak", mappings[0].Provider)
+			assert.Equal(t, "ci-operators", mappings[0].IdentityProviderGroup)
+			assert.Equal(t, []string{"team-platform"}, mappings[0].Groups)
+			assert.Equal(t, []string{"organization.pipelines.operator"}, mappings[0].Roles)
+			assert.Equal(t, ".+@ziplinee\\.io", mappings[1].EmailRegex)
+			assert.Equal(t, []string{"Org A"}, mappings[1].Organizations)
+		}
+		assert.Equal(t, 0, len(config.Auth.GetRoleMappings("Org B")))
+		assert.Equal(t, 0, len(config.Auth.GetRoleMappings("")))
+	})
+
 	t.Run("ReturnsJobsConfig", func(t *testing.T) {
 
 		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
@@ -293,6 +382,27 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.Equal(t, 200*math.Pow(2, 10)*math.Pow(2, 10)*math.Pow(2, 10), jobsConfig.MaxMemoryBytes)   // 200Gi
 		assert.Equal(t, 1.25, jobsConfig.MemoryRequestRatio)
 		assert.Equal(t, 1.0, jobsConfig.MemoryLimitRatio)
+		assert.True(t, jobsConfig.CancelSupersededBuilds)
+	})
+
+	t.Run("ReturnsJobsSizingConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		sizingConfig := config.Jobs.Sizing
+
+		assert.Nil(t, err)
+		assert.Equal(t, 50, sizingConfig.LastNRecords)
+		assert.Equal(t, 10, sizingConfig.MinRecords)
+		assert.Equal(t, 80.0, sizingConfig.CPUPercentile)
+		assert.Equal(t, 99.0, sizingConfig.MemoryPercentile)
+		assert.False(t, sizingConfig.IsTrendExtrapolated())
+		assert.Equal(t, 2.0, sizingConfig.OOMBumpRatio)
+		assert.Equal(t, 1.5, sizingConfig.CPUThrottledBumpRatio)
+		assert.Equal(t, 0.4, sizingConfig.CPUThrottledThreshold)
 	})
 
 	t.Run("ReturnsJobsConfigAffinityAndTolerations", func(t *testing.T) {
@@ -409,6 +519,121 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.Equal(t, "event.bitbucket", queueConfig.SubjectBitbucket)
 	})
 
+	t.Run("ReturnsJobQueueConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		jobQueueConfig := config.JobQueue
+
+		assert.Nil(t, err)
+		assert.NotNil(t, jobQueueConfig)
+		assert.True(t, jobQueueConfig.Enable)
+		assert.Equal(t, 10, jobQueueConfig.PollIntervalSeconds)
+		assert.Equal(t, 100, jobQueueConfig.MaxConcurrentJobs)
+		assert.Equal(t, 50, jobQueueConfig.MaxConcurrentJobsPerOrganization)
+		assert.Equal(t, 5, jobQueueConfig.MaxConcurrentJobsPerPipeline)
+		assert.Equal(t, 80, jobQueueConfig.MaxConcurrentJobsForJobType(contracts.JobTypeBuild))
+		assert.Equal(t, 30, jobQueueConfig.MaxConcurrentJobsForJobType(contracts.JobTypeRelease))
+		assert.Equal(t, 10, jobQueueConfig.MaxConcurrentJobsForJobType(contracts.JobTypeBot))
+		assert.Equal(t, 3, jobQueueConfig.MaxDispatchAttempts)
+	})
+
+	t.Run("ReturnsMigrationConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		migrationConfig := config.Migration
+
+		assert.Nil(t, err)
+		assert.NotNil(t, migrationConfig)
+		assert.True(t, migrationConfig.Enable)
+		assert.Equal(t, 30, migrationConfig.PollIntervalSeconds)
+		assert.Equal(t, 2, migrationConfig.MaxConcurrentTasks)
+	})
+
+	t.Run("ReturnsLogRetentionConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		logRetentionConfig := config.LogRetention
+
+		assert.Nil(t, err)
+		assert.NotNil(t, logRetentionConfig)
+		assert.True(t, logRetentionConfig.Enable)
+		assert.Equal(t, 7200, logRetentionConfig.PollIntervalSeconds)
+		assert.Equal(t, 250, logRetentionConfig.BatchSize)
+		assert.Equal(t, 3, len(logRetentionConfig.Rules))
+		assert.Equal(t, contracts.JobTypeBuild, logRetentionConfig.Rules[0].JobType)
+		assert.Equal(t, "ziplinee-team", logRetentionConfig.Rules[0].Labels["team"])
+		assert.Equal(t, 30, logRetentionConfig.Rules[0].RetentionDays)
+	})
+
+	t.Run("ReturnsLogReconciliationConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		logReconciliationConfig := config.LogReconciliation
+
+		assert.Nil(t, err)
+		assert.NotNil(t, logReconciliationConfig)
+		assert.True(t, logReconciliationConfig.Enable)
+		assert.Equal(t, 60, logReconciliationConfig.PollIntervalSeconds)
+	})
+
+	t.Run("ReturnsNotificationsConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		notificationsConfig := config.Notifications
+
+		assert.Nil(t, err)
+		assert.NotNil(t, notificationsConfig)
+		assert.True(t, notificationsConfig.Enable)
+		assert.Equal(t, 15, notificationsConfig.PollIntervalSeconds)
+		assert.Equal(t, 50, notificationsConfig.BatchSize)
+		assert.Equal(t, 3, notificationsConfig.MaxAttempts)
+		assert.Equal(t, 2, len(notificationsConfig.Channels))
+		assert.Equal(t, NotificationChannelTypeWebhook, notificationsConfig.Channels[0].Type)
+		assert.Equal(t, "this-is-a-signing-secret", notificationsConfig.Channels[0].SigningSecret)
+		assert.Equal(t, 2, len(notificationsConfig.Routes))
+		assert.Equal(t, "ziplinee-team", notificationsConfig.Routes[1].Labels["team"])
+	})
+
+	t.Run("ReturnsCostsConfig", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("configs", true)
+
+		costsConfig := config.Costs
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, costsConfig) {
+			assert.Equal(t, "EUR", costsConfig.Currency)
+			assert.Equal(t, 2, len(costsConfig.Prices))
+			assert.Equal(t, "privileged", costsConfig.Prices[0].NodePool)
+			assert.Equal(t, []contracts.JobType{contracts.JobTypeBuild}, costsConfig.Prices[0].JobTypes)
+			assert.Equal(t, 0.0475, costsConfig.Prices[0].PricePerCoreHour)
+			assert.Equal(t, 0.0064, costsConfig.Prices[0].PricePerGBHour)
+		}
+	})
+
 	t.Run("ReturnsManifestPreferences", func(t *testing.T) {
 
 		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
@@ -571,6 +796,17 @@ func TestReadConfigFromFiles(t *testing.T) {
 		assert.Nil(t, config.Jobs.ReleaseAffinityAndTolerations)
 		assert.Nil(t, config.Jobs.BotAffinityAndTolerations)
 	})
+
+	t.Run("Keeps_EnforcePipelinePermissions_Disabled_For_Minimal_Config", func(t *testing.T) {
+
+		configReader := NewConfigReader(crypt.NewSecretHelper("SazbwMf3NZxVVbBqQHebPcXCqrVn3DDp", false), "za4BeKbXyMJVsX6gLU2AF352DEu9J5qE")
+
+		// act
+		config, err := configReader.ReadConfigFromFiles("minimal-configs", true)
+
+		assert.Nil(t, err)
+		assert.False(t, config.Auth.EnforcePipelinePermissions)
+	})
 }
 
 func TestWriteLogToDatabase(t *testing.T) {
@@ -661,6 +897,93 @@ func TestWriteLogToCloudStorage(t *testing.T) {
 	})
 }
 
+func TestWriteLogToObjectStorage(t *testing.T) {
+
+	t.Run("ReturnsFalseIfLogWritersOnlyContainsDatabase", func(t *testing.T) {
+
+		config := APIServerConfig{
+			LogWriters: []LogTarget{
+				LogTargetDatabase,
+			},
+		}
+
+		// act
+		result := config.WriteLogToObjectStorage()
+
+		assert.False(t, result)
+	})
+
+	t.Run("ReturnsTrueIfLogWritersContainsS3", func(t *testing.T) {
+
+		config := APIServerConfig{
+			LogWriters: []LogTarget{
+				LogTargetDatabase,
+				LogTargetS3,
+			},
+		}
+
+		// act
+		result := config.WriteLogToObjectStorage()
+
+		assert.True(t, result)
+		assert.Equal(t, LogTargetS3, config.ObjectStorageLogTarget())
+	})
+}
+
+func TestAPIServerConfigValidate(t *testing.T) {
+
+	t.Run("ReturnsErrorIfLogWritersContainsMoreThanOneObjectStorage", func(t *testing.T) {
+
+		config := APIServerConfig{
+			ServiceURL: "http://ziplinee-ci-api.ziplinee-ci.svc.cluster.local",
+			LogWriters: []LogTarget{
+				LogTargetCloudStorage,
+				LogTargetFilesystem,
+			},
+			LogReader: LogTargetFilesystem,
+		}
+
+		// act
+		err := config.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfLogReaderIsObjectStorageMissingInLogWriters", func(t *testing.T) {
+
+		config := APIServerConfig{
+			ServiceURL: "http://ziplinee-ci-api.ziplinee-ci.svc.cluster.local",
+			LogWriters: []LogTarget{
+				LogTargetDatabase,
+				LogTargetS3,
+			},
+			LogReader: LogTargetFilesystem,
+		}
+
+		// act
+		err := config.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorForDatabaseAndFilesystem", func(t *testing.T) {
+
+		config := APIServerConfig{
+			ServiceURL: "http://ziplinee-ci-api.ziplinee-ci.svc.cluster.local",
+			LogWriters: []LogTarget{
+				LogTargetDatabase,
+				LogTargetFilesystem,
+			},
+			LogReader: LogTargetFilesystem,
+		}
+
+		// act
+		err := config.Validate()
+
+		assert.Nil(t, err)
+	})
+}
+
 func TestReadLogFromDatabase(t *testing.T) {
 
 	t.Run("ReturnsTrueIfLogReaderIsEmpty", func(t *testing.T) {
@@ -738,3 +1061,275 @@ func TestReadLogFromCloudStorage(t *testing.T) {
 		assert.False(t, result)
 	})
 }
+
+func TestLogRetentionConfigGetRetentionDays(t *testing.T) {
+
+	config := LogRetentionConfig{
+		Rules: []LogRetentionRule{
+			{LogTarget: LogTargetCloudStorage, JobType: contracts.JobTypeBuild, Labels: map[string]string{"team": "ziplinee-team"}, RetentionDays: 30},
+			{JobType: contracts.JobTypeBuild, RetentionDays: 90},
+			{JobType: contracts.JobTypeRelease, RetentionDays: 365},
+		},
+	}
+
+	t.Run("ReturnsRetentionOfFirstMatchingRule", func(t *testing.T) {
+
+		// act
+		retentionDays, found := config.GetRetentionDays(LogTargetCloudStorage, contracts.JobTypeBuild, []contracts.Label{{Key: "language", Value: "golang"}, {Key: "team", Value: "ziplinee-team"}})
+
+		assert.True(t, found)
+		assert.Equal(t, 30, retentionDays)
+	})
+
+	t.Run("SkipsRuleForOtherLogTarget", func(t *testing.T) {
+
+		// act
+		retentionDays, found := config.GetRetentionDays(LogTargetDatabase, contracts.JobTypeBuild, []contracts.Label{{Key: "team", Value: "ziplinee-team"}})
+
+		assert.True(t, found)
+		assert.Equal(t, 90, retentionDays)
+	})
+
+	t.Run("ReturnsNotFoundIfNoRuleMatches", func(t *testing.T) {
+
+		// act
+		_, found := config.GetRetentionDays(LogTargetDatabase, contracts.JobTypeBot, []contracts.Label{})
+
+		assert.False(t, found)
+	})
+}
+
+func TestNotificationsConfigGetChannels(t *testing.T) {
+
+	config := NotificationsConfig{
+		Enable: true,
+		Channels: []*NotificationChannelConfig{
+			{Name: "deployments", Type: NotificationChannelTypeWebhook, URL: "https://hooks.ziplinee.io/deployments"},
+			{Name: "team-slack", Type: NotificationChannelTypeSlack, URL: "https://hooks.slack.com/services/T000/B000/XXXX"},
+		},
+		Routes: []NotificationRoute{
+			{Events: []NotificationEventType{NotificationEventTypeRelease}, Channels: []string{"deployments"}},
+			{Statuses: []contracts.Status{contracts.StatusFailed}, Labels: map[string]string{"team": "ziplinee-team"}, Channels: []string{"team-slack"}},
+			{Organizations: []string{"Org A"}, Channels: []string{"team-slack", "deployments"}},
+		},
+	}
+
+	t.Run("ReturnsChannelsOfRouteMatchingEventType", func(t *testing.T) {
+
+		// act
+		channels := config.GetChannels(NotificationEventTypeRelease, contracts.StatusSucceeded, []string{}, []contracts.Label{})
+
+		if assert.Equal(t, 1, len(channels)) {
+			assert.Equal(t, "deployments", channels[0].Name)
+		}
+	})
+
+	t.Run("ReturnsChannelsOfRouteMatchingStatusAndLabels", func(t *testing.T) {
+
+		// act
+		channels := config.GetChannels(NotificationEventTypeBuild, contracts.StatusFailed, []string{}, []contracts.Label{{Key: "team", Value: "ziplinee-team"}})
+
+		if assert.Equal(t, 1, len(channels)) {
+			assert.Equal(t, "team-slack", channels[0].Name)
+		}
+	})
+
+	t.Run("ReturnsNoChannelsIfLabelsDoNotMatch", func(t *testing.T) {
+
+		// act
+		channels := config.GetChannels(NotificationEventTypeBuild, contracts.StatusFailed, []string{}, []contracts.Label{{Key: "team", Value: "other-team"}})
+
+		assert.Equal(t, 0, len(channels))
+	})
+
+	t.Run("ReturnsEachChannelOnceIfMultipleRoutesMatch", func(t *testing.T) {
+
+		// act
+		channels := config.GetChannels(NotificationEventTypeRelease, contracts.StatusFailed, []string{"Org A"}, []contracts.Label{{Key: "team", Value: "ziplinee-team"}})
+
+		if assert.Equal(t, 2, len(channels)) {
+			assert.Equal(t, "deployments", channels[0].Name)
+			assert.Equal(t, "team-slack", channels[1].Name)
+		}
+	})
+}
+
+func TestNotificationsConfigGetBackoff(t *testing.T) {
+
+	config := NotificationsConfig{
+		InitialBackoffSeconds: 30,
+		MaxBackoffSeconds:     300,
+	}
+
+	t.Run("ReturnsInitialBackoffAfterFirstAttempt", func(t *testing.T) {
+
+		// act
+		backoff := config.GetBackoff(1)
+
+		assert.Equal(t, 30*time.Second, backoff)
+	})
+
+	t.Run("DoublesBackoffEveryAttempt", func(t *testing.T) {
+
+		// act
+		backoff := config.GetBackoff(3)
+
+		assert.Equal(t, 120*time.Second, backoff)
+	})
+
+	t.Run("CapsBackoffAtMaximum", func(t *testing.T) {
+
+		// act
+		backoff := config.GetBackoff(10)
+
+		assert.Equal(t, 300*time.Second, backoff)
+	})
+}
+
+func TestCostsConfigGetPrice(t *testing.T) {
+
+	config := CostsConfig{
+		Prices: []*CostsPriceConfig{
+			{NodePool: "default", PricePerCoreHour: 0.03},
+			{NodePool: "privileged", JobTypes: []contracts.JobType{contracts.JobTypeBuild}, PricePerCoreHour: 0.05},
+		},
+	}
+
+	t.Run("ReturnsPriceOfNodePoolForJobType", func(t *testing.T) {
+
+		// act
+		price := config.GetPrice(contracts.JobTypeBuild)
+
+		assert.Equal(t, "privileged", price.NodePool)
+	})
+
+	t.Run("ReturnsPriceWithoutJobTypesForOtherJobTypes", func(t *testing.T) {
+
+		// act
+		price := config.GetPrice(contracts.JobTypeRelease)
+
+		assert.Equal(t, "default", price.NodePool)
+	})
+
+	t.Run("ReturnsNilIfJobTypeIsNotPriced", func(t *testing.T) {
+
+		config := CostsConfig{
+			Prices: []*CostsPriceConfig{
+				{NodePool: "privileged", JobTypes: []contracts.JobType{contracts.JobTypeBuild}, PricePerCoreHour: 0.05},
+			},
+		}
+
+		// act
+		price := config.GetPrice(contracts.JobTypeBot)
+
+		assert.Nil(t, price)
+	})
+}
+
+func TestNotificationsConfigValidate(t *testing.T) {
+
+	t.Run("ReturnsErrorIfRouteHasUnknownChannel", func(t *testing.T) {
+
+		config := NotificationsConfig{
+			Enable:   true,
+			Channels: []*NotificationChannelConfig{{Name: "deployments", Type: NotificationChannelTypeWebhook, URL: "https://hooks.ziplinee.io/deployments"}},
+			Routes:   []NotificationRoute{{Channels: []string{"team-slack"}}},
+		}
+		config.SetDefaults()
+
+		// act
+		err := config.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorIfEmailChannelHasNoRecipients", func(t *testing.T) {
+
+		config := NotificationsConfig{
+			Enable:   true,
+			Channels: []*NotificationChannelConfig{{Name: "email", Type: NotificationChannelTypeEmail, SMTP: &SMTPConfig{Host: "smtp.ziplinee.io", From: "ci@ziplinee.io"}}},
+		}
+		config.SetDefaults()
+
+		// act
+		err := config.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorForValidConfig", func(t *testing.T) {
+
+		config := NotificationsConfig{
+			Enable: true,
+			Channels: []*NotificationChannelConfig{
+				{Name: "deployments", Type: NotificationChannelTypeWebhook, URL: "https://hooks.ziplinee.io/deployments"},
+				{Name: "email", Type: NotificationChannelTypeEmail, SMTP: &SMTPConfig{Host: "smtp.ziplinee.io", From: "ci@ziplinee.io"}, Recipients: []string{"team@ziplinee.io"}},
+			},
+			Routes: []NotificationRoute{{Events: []NotificationEventType{NotificationEventTypeBuild}, Channels: []string{"deployments", "email"}}},
+		}
+		config.SetDefaults()
+
+		// act
+		err := config.Validate()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 587, config.Channels[1].SMTP.Port)
+	})
+}
+
+func TestRoleMappingConfig(t *testing.T) {
+	t.Run("MatchesOnIdentityProviderGroup", func(t *testing.T) {
+
+		mapping := RoleMappingConfig{IdentityProviderGroup: "ci-operators", Roles: []string{"organization.pipelines.operator"}}
+
+		assert.True(t, mapping.Matches("jane@ziplinee.io", []string{"developers", "ci-operators"}))
+		assert.False(t, mapping.Matches("jane@ziplinee.io", []string{"developers"}))
+	})
+
+	t.Run("MatchesOnAnchoredEmailRegex", func(t *testing.T) {
+
+		mapping := RoleMappingConfig{EmailRegex: ".+@ziplinee\\.io", Roles: []string{"organization.pipelines.operator"}}
+
+		assert.True(t, mapping.Matches("jane@ziplinee.io", nil))
+		assert.False(t, mapping.Matches("jane@ziplinee.io.evil.com", nil))
+		assert.False(t, mapping.Matches("", nil))
+	})
+
+	t.Run("RequiresAllConditionsToMatch", func(t *testing.T) {
+
+		mapping := RoleMappingConfig{IdentityProviderGroup: "ci-operators", EmailRegex: ".+@ziplinee\\.io", Roles: []string{"organization.pipelines.operator"}}
+
+		assert.True(t, mapping.Matches("jane@ziplinee.io", []string{"ci-operators"}))
+		assert.False(t, mapping.Matches("jane@other.io", []string{"ci-operators"}))
+	})
+
+	t.Run("ValidateReturnsErrorForUnknownRole", func(t *testing.T) {
+
+		mapping := RoleMappingConfig{IdentityProviderGroup: "ci-operators", Roles: []string{"pipeline.overlord"}}
+
+		// act
+		err := mapping.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorForAdministratorRole", func(t *testing.T) {
+
+		mapping := RoleMappingConfig{IdentityProviderGroup: "ci-operators", Roles: []string{"administrator"}}
+
+		// act
+		err := mapping.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorWithoutCondition", func(t *testing.T) {
+
+		mapping := RoleMappingConfig{Roles: []string{"organization.pipelines.operator"}}
+
+		// act
+		err := mapping.Validate()
+
+		assert.NotNil(t, err)
+	})
+}
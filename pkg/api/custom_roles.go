@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+const (
+	// customRolesCacheTTL is how long custom roles are used for permission checks before retrieving them again; changes made
+	// through any replica take effect within this time
+	customRolesCacheTTL = 30 * time.Second
+
+	// customRolesPageSize is the number of custom roles retrieved at once when refreshing the cache
+	customRolesPageSize = 100
+)
+
+// CustomRoleStore retrieves custom roles for the pipeline permission checks
+type CustomRoleStore interface {
+	GetCustomRoles(ctx context.Context, pageNumber, pageSize int, filters map[FilterType][]string, sortings []OrderField) (customRoles []*CustomRole, err error)
+}
+
+// CustomRolesCache saves paging through all custom roles for every pipeline permission check
+type CustomRolesCache struct {
+	store    CustomRoleStore
+	ttl      time.Duration
+	timeFunc func() time.Time
+
+	mu          sync.Mutex
+	customRoles []*CustomRole
+	retrievedAt time.Time
+}
+
+// NewCustomRolesCache returns a new CustomRolesCache
+func NewCustomRolesCache(store CustomRoleStore) *CustomRolesCache {
+	return &CustomRolesCache{
+		store:    store,
+		ttl:      customRolesCacheTTL,
+		timeFunc: time.Now,
+	}
+}
+
+// GetCustomRoles returns all custom roles, from cache if they have been retrieved within the ttl
+func (c *CustomRolesCache) GetCustomRoles(ctx context.Context) (customRoles []*CustomRole, err error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.timeFunc()
+	if c.customRoles != nil && now.Sub(c.retrievedAt) < c.ttl {
+		return c.customRoles, nil
+	}
+
+	customRoles = []*CustomRole{}
+	for pageNumber := 1; ; pageNumber++ {
+		customRolesPage, err := c.store.GetCustomRoles(ctx, pageNumber, customRolesPageSize, map[FilterType][]string{}, []OrderField{})
+		if err != nil {
+			return nil, err
+		}
+		customRoles = append(customRoles, customRolesPage...)
+
+		if len(customRolesPage) < customRolesPageSize {
+			break
+		}
+	}
+
+	c.customRoles = customRoles
+	c.retrievedAt = now
+
+	return customRoles, nil
+}
+
+// RequestHasPermission checks whether the request may perform an action that isn't tied to a pipeline; personal access and
+// client access tokens never get permissions outside of their scopes, and without enforcement the request doesn't need
+// the permission through its roles
+func RequestHasPermission(c *gin.Context, config *APIConfig, permission Permission) bool {
+
+	if scopes, isScoped := GetScopesFromRequest(c); isScoped && !StringArrayContains(scopes, permission.String()) {
+		return false
+	}
+
+	return RequestTokenHasPermission(c, permission) || !pipelinePermissionsAreEnforced(config)
+}
+
+// RequestHasPipelinePermission checks whether the request may perform an action on a pipeline, either through its roles or
+// through custom roles bound to the pipeline; personal access and client access tokens never get permissions outside of
+// their scopes. Custom roles bound to the pipeline for the permission always limit the action to their subjects, so without
+// enforcement only pipelines without such bindings can be acted on by any user who can see them
+func RequestHasPipelinePermission(c *gin.Context, config *APIConfig, customRolesCache *CustomRolesCache, permission Permission, pipeline contracts.Pipeline, releaseTarget string) (bool, error) {
+
+	if scopes, isScoped := GetScopesFromRequest(c); isScoped && !StringArrayContains(scopes, permission.String()) {
+		return false, nil
+	}
+
+	if RequestTokenHasPermission(c, permission) {
+		return true, nil
+	}
+
+	customRoles, err := customRolesCache.GetCustomRoles(c.Request.Context())
+	if err != nil {
+		return false, err
+	}
+
+	if RequestTokenHasPipelinePermission(c, permission, customRoles, pipeline, releaseTarget) {
+		return true, nil
+	}
+
+	if pipelinePermissionsAreEnforced(config) {
+		return false, nil
+	}
+
+	return !PipelineHasCustomRoleBindings(permission, customRoles, pipeline, releaseTarget), nil
+}
+
+// PipelineHasCustomRoleBindings returns true if any active custom role containing the permission is bound to the pipeline
+func PipelineHasCustomRoleBindings(permission Permission, customRoles []*CustomRole, pipeline contracts.Pipeline, releaseTarget string) bool {
+
+	for _, r := range customRoles {
+		if r == nil || !r.Active || !r.HasPermission(permission) {
+			continue
+		}
+		for _, b := range r.Bindings {
+			if b != nil && b.MatchesPipeline(pipeline) && b.AllowsReleaseTarget(releaseTarget) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func pipelinePermissionsAreEnforced(config *APIConfig) bool {
+	return config != nil && config.Auth != nil && config.Auth.EnforcePipelinePermissions
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+type fakeCustomRoleStore struct {
+	customRoles []*CustomRole
+	err         error
+	calls       int
+}
+
+func (s *fakeCustomRoleStore) GetCustomRoles(ctx context.Context, pageNumber, pageSize int, filters map[FilterType][]string, sortings []OrderField) (customRoles []*CustomRole, err error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	start := (pageNumber - 1) * pageSize
+	if start >= len(s.customRoles) {
+		return []*CustomRole{}, nil
+	}
+	end := start + pageSize
+	if end > len(s.customRoles) {
+		end = len(s.customRoles)
+	}
+
+	return s.customRoles[start:end], nil
+}
+
+func TestCustomRolesCache(t *testing.T) {
+	t.Run("RetrievesAllPagesOfCustomRoles", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{}
+		for i := 0; i < customRolesPageSize+5; i++ {
+			store.customRoles = append(store.customRoles, &CustomRole{Name: fmt.Sprintf("role-%v", i)})
+		}
+		cache := NewCustomRolesCache(store)
+
+		// act
+		customRoles, err := cache.GetCustomRoles(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, customRolesPageSize+5, len(customRoles))
+		assert.Equal(t, 2, store.calls)
+	})
+
+	t.Run("RetrievesCustomRolesOnlyOnceWithinTTL", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{customRoles: []*CustomRole{{Name: "payments-operator"}}}
+		cache := NewCustomRolesCache(store)
+
+		// act
+		_, err := cache.GetCustomRoles(context.Background())
+		assert.Nil(t, err)
+		customRoles, err := cache.GetCustomRoles(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(customRoles))
+		assert.Equal(t, 1, store.calls)
+	})
+
+	t.Run("RetrievesCustomRolesAgainAfterTTL", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeCustomRoleStore{customRoles: []*CustomRole{{Name: "payments-operator"}}}
+		cache := NewCustomRolesCache(store)
+		cache.timeFunc = func() time.Time { return now }
+		_, err := cache.GetCustomRoles(context.Background())
+		assert.Nil(t, err)
+
+		// added through another replica
+		store.customRoles = append(store.customRoles, &CustomRole{Name: "payments-releaser"})
+		now = now.Add(customRolesCacheTTL)
+
+		// act
+		customRoles, err := cache.GetCustomRoles(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(customRoles))
+		assert.Equal(t, 2, store.calls)
+	})
+
+	t.Run("ReturnsErrorAndCachesNothingIfStoreFails", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{err: errors.New("connection refused")}
+		cache := NewCustomRolesCache(store)
+
+		// act
+		_, err := cache.GetCustomRoles(context.Background())
+		assert.NotNil(t, err)
+		store.err = nil
+		_, err = cache.GetCustomRoles(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, store.calls)
+	})
+}
+
+func TestRequestHasPipelinePermission(t *testing.T) {
+
+	pipeline := contracts.Pipeline{
+		RepoSource: "github.com",
+		RepoOwner:  "ziplineeci",
+		RepoName:   "payments-api",
+		Labels: []contracts.Label{
+			{
+				Key:   "team",
+				Value: "payments",
+			},
+		},
+	}
+
+	paymentsOperator := &CustomRole{
+		Name:        "payments-operator",
+		Permissions: []string{"ci.builds.rebuild"},
+		Bindings: []*CustomRoleBinding{
+			{
+				Groups: []string{"team-payments"},
+				Labels: []contracts.Label{{Key: "team", Value: "payments"}},
+			},
+		},
+		Active: true,
+	}
+
+	newRequest := func(claims jwt.MapClaims) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds", nil)
+		c.Set("JWT_PAYLOAD", claims)
+		return c
+	}
+
+	t.Run("ReturnsFalseForTokenScopedToReadingIfPipelinePermissionsAreNotEnforced", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{}
+		c := newRequest(jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleAdministrator.String()},
+			"tokenID":       "5678",
+			"scopes":        []interface{}{"ci.pipelines.get", "ci.builds.get"},
+		})
+
+		// act
+		hasPermission, err := RequestHasPipelinePermission(c, &APIConfig{Auth: &AuthConfig{}}, NewCustomRolesCache(store), PermissionBuildsRebuild, pipeline, "")
+
+		assert.Nil(t, err)
+		assert.False(t, hasPermission)
+		assert.Equal(t, 0, store.calls)
+	})
+
+	t.Run("ReturnsTrueWithoutPermissionIfPipelinePermissionsAreNotEnforcedAndNoCustomRoleIsBoundToPipeline", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{customRoles: []*CustomRole{paymentsOperator}}
+		c := newRequest(jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+		})
+
+		// act
+		hasPermission, err := RequestHasPipelinePermission(c, &APIConfig{Auth: &AuthConfig{}}, NewCustomRolesCache(store), PermissionBuildsRebuild, contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "orders-api"}, "")
+
+		assert.Nil(t, err)
+		assert.True(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForOtherSubjectsIfPipelinePermissionsAreNotEnforcedButCustomRoleIsBoundToPipeline", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{customRoles: []*CustomRole{paymentsOperator}}
+		c := newRequest(jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-orders"},
+		})
+
+		// act
+		hasPermission, err := RequestHasPipelinePermission(c, &APIConfig{Auth: &AuthConfig{}}, NewCustomRolesCache(store), PermissionBuildsRebuild, pipeline, "")
+
+		assert.Nil(t, err)
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("ReturnsTrueForSubjectOfCustomRoleBoundToPipeline", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{customRoles: []*CustomRole{paymentsOperator}}
+		c := newRequest(jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+		})
+
+		// act
+		hasPermission, err := RequestHasPipelinePermission(c, &APIConfig{Auth: &AuthConfig{EnforcePipelinePermissions: true}}, NewCustomRolesCache(store), PermissionBuildsRebuild, pipeline, "")
+
+		assert.Nil(t, err)
+		assert.True(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseWithoutPermissionIfPipelinePermissionsAreEnforced", func(t *testing.T) {
+
+		store := &fakeCustomRoleStore{}
+		c := newRequest(jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+		})
+
+		// act
+		hasPermission, err := RequestHasPipelinePermission(c, &APIConfig{Auth: &AuthConfig{EnforcePipelinePermissions: true}}, NewCustomRolesCache(store), PermissionBuildsRebuild, pipeline, "")
+
+		assert.Nil(t, err)
+		assert.False(t, hasPermission)
+	})
+}
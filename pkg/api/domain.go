@@ -1,5 +1,13 @@
 package api
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
 // {
 // 	"keys" : [
 // 	   {
@@ -54,6 +62,33 @@ type GoogleJWKResponse struct {
 	Keys []GoogleJSONWebKey `json:"keys"`
 }
 
+// OIDCDiscoveryDocument as returned by <issuer>/.well-known/openid-configuration
+type OIDCDiscoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint,omitempty"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// JSONWebKey is the public part of an rsa or ecdsa key used to sign json web tokens
+type JSONWebKey struct {
+	KeyType      string `json:"kty"`
+	Algorithm    string `json:"alg,omitempty"`
+	PublicKeyUse string `json:"use,omitempty"`
+	KeyID        string `json:"kid,omitempty"`
+	N            string `json:"n,omitempty"`
+	E            string `json:"e,omitempty"`
+	Curve        string `json:"crv,omitempty"`
+	X            string `json:"x,omitempty"`
+	Y            string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet as returned by the jwks_uri of an oidc provider
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
 // Role is used to hand out permissions to users and clients
 type Role int
 
@@ -94,6 +129,10 @@ const (
 	RoleCatalogEntitiesViewer
 	// RoleCatalogEntitiesAdmin allows to view, create, update and delete catalog entities
 	RoleCatalogEntitiesAdmin
+	// RoleMigrationAdmin allows to view, queue and roll back migrations of pipelines to another repository
+	RoleMigrationAdmin
+	// RoleOrganizationManager allows to view, create and update users, groups, clients and pipelines within the organizations it's a member of
+	RoleOrganizationManager
 )
 
 var roles = []string{
@@ -115,6 +154,8 @@ var roles = []string{
 	"group.pipelines.operator",
 	"catalog.entities.viewer",
 	"catalog.entities.admin",
+	"migration.admin",
+	"organization.manager",
 }
 
 func (r Role) String() string {
@@ -187,11 +228,42 @@ const (
 	PermissionReleasesCreate
 	PermissionReleasesCancel
 
+	PermissionBotsCreate
+	PermissionBotsCancel
+
+	PermissionNotificationsCreate
+
 	PermissionCatalogEntitiesList
 	PermissionCatalogEntitiesGet
 	PermissionCatalogEntitiesCreate
 	PermissionCatalogEntitiesUpdate
 	PermissionCatalogEntitiesDelete
+
+	PermissionWebhooksList
+	PermissionWebhooksGet
+	PermissionWebhooksReplay
+
+	PermissionMigrationsList
+	PermissionMigrationsGet
+	PermissionMigrationsCreate
+	PermissionMigrationsRollback
+
+	PermissionLogRetentionGet
+
+	PermissionLogReconciliationsList
+	PermissionLogReconciliationsGet
+	PermissionLogReconciliationsCreate
+
+	PermissionCustomRolesList
+	PermissionCustomRolesGet
+	PermissionCustomRolesCreate
+	PermissionCustomRolesUpdate
+	PermissionCustomRolesDelete
+
+	PermissionAuditLogList
+
+	PermissionNotificationDeliveriesList
+	PermissionNotificationDeliveriesGet
 )
 
 var permissions = []string{
@@ -218,10 +290,14 @@ var permissions = []string{
 
 	"rbac.clients.list",
 	"rbac.clients.get",
+	"rbac.clients.viewsecret",
 	"rbac.clients.create",
 	"rbac.clients.update",
 	"rbac.clients.delete",
 
+	"rbac.integrations.get",
+	"rbac.integrations.update",
+
 	"ci.pipelines.list",
 	"ci.pipelines.get",
 	"ci.pipelines.update",
@@ -237,11 +313,42 @@ var permissions = []string{
 	"ci.releases.create",
 	"ci.releases.cancel",
 
+	"ci.bots.create",
+	"ci.bots.cancel",
+
+	"ci.notifications.create",
+
 	"catalog.entities.list",
 	"catalog.entities.get",
 	"catalog.entities.create",
 	"catalog.entities.update",
 	"catalog.entities.delete",
+
+	"ci.webhooks.list",
+	"ci.webhooks.get",
+	"ci.webhooks.replay",
+
+	"ci.migrations.list",
+	"ci.migrations.get",
+	"ci.migrations.create",
+	"ci.migrations.rollback",
+
+	"ci.logretention.get",
+
+	"ci.logreconciliations.list",
+	"ci.logreconciliations.get",
+	"ci.logreconciliations.create",
+
+	"rbac.customroles.list",
+	"rbac.customroles.get",
+	"rbac.customroles.create",
+	"rbac.customroles.update",
+	"rbac.customroles.delete",
+
+	"rbac.auditlog.list",
+
+	"ci.notificationdeliveries.list",
+	"ci.notificationdeliveries.get",
 }
 
 func (p Permission) String() string {
@@ -264,6 +371,28 @@ func Permissions() []string {
 	return permissions
 }
 
+// GetPermissionsForRoles returns the names of all permissions the roles grant, without duplicates
+func GetPermissionsForRoles(roles []*string) (permissions []string) {
+
+	permissions = []string{}
+	for _, r := range roles {
+		if r == nil {
+			continue
+		}
+		role := ToRole(*r)
+		if role == nil {
+			continue
+		}
+		for _, p := range rolesToPermissionMap[*role] {
+			if !StringArrayContains(permissions, p.String()) {
+				permissions = append(permissions, p.String())
+			}
+		}
+	}
+
+	return
+}
+
 var rolesToPermissionMap = map[Role][]Permission{
 	RoleAdministrator: {
 		PermissionRolesList,
@@ -303,11 +432,33 @@ var rolesToPermissionMap = map[Role][]Permission{
 		PermissionReleasesGet,
 		PermissionReleasesCreate,
 		PermissionReleasesCancel,
+		PermissionBotsCreate,
+		PermissionBotsCancel,
+		PermissionNotificationsCreate,
 		PermissionCatalogEntitiesList,
 		PermissionCatalogEntitiesGet,
 		PermissionCatalogEntitiesCreate,
 		PermissionCatalogEntitiesUpdate,
 		PermissionCatalogEntitiesDelete,
+		PermissionWebhooksList,
+		PermissionWebhooksGet,
+		PermissionWebhooksReplay,
+		PermissionMigrationsList,
+		PermissionMigrationsGet,
+		PermissionMigrationsCreate,
+		PermissionMigrationsRollback,
+		PermissionLogRetentionGet,
+		PermissionLogReconciliationsList,
+		PermissionLogReconciliationsGet,
+		PermissionLogReconciliationsCreate,
+		PermissionCustomRolesList,
+		PermissionCustomRolesGet,
+		PermissionCustomRolesCreate,
+		PermissionCustomRolesUpdate,
+		PermissionCustomRolesDelete,
+		PermissionAuditLogList,
+		PermissionNotificationDeliveriesList,
+		PermissionNotificationDeliveriesGet,
 	},
 	RoleRoleViewer: {
 		PermissionRolesList,
@@ -376,6 +527,8 @@ var rolesToPermissionMap = map[Role][]Permission{
 		PermissionReleasesGet,
 		PermissionReleasesCreate,
 		PermissionReleasesCancel,
+		PermissionBotsCreate,
+		PermissionBotsCancel,
 	},
 	RoleGroupPipelinesViewer: {
 		PermissionPipelinesList,
@@ -396,6 +549,8 @@ var rolesToPermissionMap = map[Role][]Permission{
 		PermissionReleasesGet,
 		PermissionReleasesCreate,
 		PermissionReleasesCancel,
+		PermissionBotsCreate,
+		PermissionBotsCancel,
 	},
 	RoleCatalogEntitiesViewer: {
 		PermissionCatalogEntitiesList,
@@ -408,6 +563,222 @@ var rolesToPermissionMap = map[Role][]Permission{
 		PermissionCatalogEntitiesUpdate,
 		PermissionCatalogEntitiesDelete,
 	},
+	RoleMigrationAdmin: {
+		PermissionMigrationsList,
+		PermissionMigrationsGet,
+		PermissionMigrationsCreate,
+		PermissionMigrationsRollback,
+	},
+	RoleLogMigrator: {
+		PermissionLogReconciliationsList,
+		PermissionLogReconciliationsGet,
+		PermissionLogReconciliationsCreate,
+	},
+	RoleOrganizationManager: {
+		PermissionRolesList,
+		PermissionUsersList,
+		PermissionUsersGet,
+		PermissionUsersCreate,
+		PermissionUsersUpdate,
+		PermissionUsersDelete,
+		PermissionGroupsList,
+		PermissionGroupsGet,
+		PermissionGroupsCreate,
+		PermissionGroupsUpdate,
+		PermissionGroupsDelete,
+		PermissionClientsList,
+		PermissionClientsGet,
+		PermissionClientsCreate,
+		PermissionClientsUpdate,
+		PermissionClientsDelete,
+		PermissionPipelinesList,
+		PermissionPipelinesGet,
+		PermissionPipelinesUpdate,
+	},
+}
+
+// organizationScopedRoles only grant their permissions within the organizations of the request; they're left out of
+// GetPermissionsFromRequest, so only handlers that limit their changes with GetOrganizationScopeFromRequest honour them
+var organizationScopedRoles = map[Role]bool{
+	RoleOrganizationManager: true,
+}
+
+// CustomRole is a role defined by administrators as a set of permissions; its bindings grant it to users, groups and
+// organizations for a selection of pipelines
+type CustomRole struct {
+	ID          string               `json:"id,omitempty"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Permissions []string             `json:"permissions"`
+	Bindings    []*CustomRoleBinding `json:"bindings,omitempty"`
+	Active      bool                 `json:"active"`
+}
+
+// CustomRoleBinding grants a custom role to the listed users (by email address), groups and organizations (by name) for
+// the listed pipelines (as source/owner/name) or for pipelines having all of the labels
+type CustomRoleBinding struct {
+	Users          []string          `json:"users,omitempty"`
+	Groups         []string          `json:"groups,omitempty"`
+	Organizations  []string          `json:"organizations,omitempty"`
+	Pipelines      []string          `json:"pipelines,omitempty"`
+	Labels         []contracts.Label `json:"labels,omitempty"`
+	ReleaseTargets []string          `json:"releaseTargets,omitempty"`
+}
+
+func (r *CustomRole) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("Custom role name is required")
+	}
+	if len(r.Permissions) == 0 {
+		return fmt.Errorf("Custom role %v needs at least one permission", r.Name)
+	}
+	for _, p := range r.Permissions {
+		if ToPermission(p) == nil {
+			return fmt.Errorf("Custom role %v has unknown permission %v", r.Name, p)
+		}
+	}
+	for _, b := range r.Bindings {
+		if b == nil {
+			return fmt.Errorf("Custom role %v has an empty binding", r.Name)
+		}
+		if len(b.Users) == 0 && len(b.Groups) == 0 && len(b.Organizations) == 0 {
+			return fmt.Errorf("Custom role %v has a binding without users, groups or organizations", r.Name)
+		}
+		if len(b.Pipelines) == 0 && len(b.Labels) == 0 {
+			return fmt.Errorf("Custom role %v has a binding without pipelines or labels", r.Name)
+		}
+		for _, p := range b.Pipelines {
+			if len(strings.Split(p, "/")) != 3 {
+				return fmt.Errorf("Custom role %v has binding for pipeline %v, which is not formatted as source/owner/name", r.Name, p)
+			}
+		}
+	}
+
+	return nil
+}
+
+// HasPermission returns true if the custom role contains the permission
+func (r *CustomRole) HasPermission(permission Permission) bool {
+	return StringArrayContains(r.Permissions, permission.String())
+}
+
+// AppliesToSubject returns true if the binding grants the role to the email address or any of the groups or organizations
+func (b *CustomRoleBinding) AppliesToSubject(email string, groups, organizations []string) bool {
+	if email != "" && StringArrayContains(b.Users, email) {
+		return true
+	}
+	for _, g := range groups {
+		if StringArrayContains(b.Groups, g) {
+			return true
+		}
+	}
+	for _, o := range organizations {
+		if StringArrayContains(b.Organizations, o) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchesPipeline returns true if the pipeline is listed in the binding or has all of the binding's labels
+func (b *CustomRoleBinding) MatchesPipeline(pipeline contracts.Pipeline) bool {
+	if StringArrayContains(b.Pipelines, fmt.Sprintf("%v/%v/%v", pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName)) {
+		return true
+	}
+	if len(b.Labels) == 0 {
+		return false
+	}
+	for _, l := range b.Labels {
+		hasLabel := false
+		for _, pl := range pipeline.Labels {
+			if pl.Key == l.Key && pl.Value == l.Value {
+				hasLabel = true
+				break
+			}
+		}
+		if !hasLabel {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AllowsReleaseTarget returns true if the binding has no release targets or lists the release target; for actions that do
+// not involve a release target the restriction does not apply
+func (b *CustomRoleBinding) AllowsReleaseTarget(releaseTarget string) bool {
+	return releaseTarget == "" || len(b.ReleaseTargets) == 0 || StringArrayContains(b.ReleaseTargets, releaseTarget)
+}
+
+// PersonalAccessTokenPrefix starts every personal access token, to tell them apart from jwts in the authorization header
+const PersonalAccessTokenPrefix = "zpat_"
+
+// MaxPersonalAccessTokenLifetime limits how long a personal access token can be valid for
+const MaxPersonalAccessTokenLifetime = 366 * 24 * time.Hour
+
+// PersonalAccessToken lets a user script against the api with a subset of their own permissions; only a hash of the token
+// itself is stored, so it's returned just once when the token is created
+type PersonalAccessToken struct {
+	ID         string     `json:"id,omitempty"`
+	UserID     string     `json:"userID,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	Active     bool       `json:"active"`
+	Token      string     `json:"token,omitempty"`
+}
+
+func (t *PersonalAccessToken) Validate(now time.Time) error {
+	if t.Name == "" {
+		return fmt.Errorf("Personal access token name is required")
+	}
+	if len(t.Scopes) == 0 {
+		return fmt.Errorf("Personal access token %v needs at least one scope", t.Name)
+	}
+	for _, s := range t.Scopes {
+		if ToPermission(s) == nil {
+			return fmt.Errorf("Personal access token %v has unknown scope %v", t.Name, s)
+		}
+	}
+	if t.ExpiresAt == nil {
+		return fmt.Errorf("Personal access token %v needs an expiry date", t.Name)
+	}
+	if !t.ExpiresAt.After(now) {
+		return fmt.Errorf("Personal access token %v expires in the past", t.Name)
+	}
+	if t.ExpiresAt.After(now.Add(MaxPersonalAccessTokenLifetime)) {
+		return fmt.Errorf("Personal access token %v can't be valid for longer than %v days", t.Name, int(MaxPersonalAccessTokenLifetime.Hours()/24))
+	}
+
+	return nil
+}
+
+// IsExpired returns true if the token can no longer be used
+func (t *PersonalAccessToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt == nil || !t.ExpiresAt.After(now)
+}
+
+// MaxActiveClientSecrets is the number of secrets a client can have at once, to rotate its secret without downtime
+const MaxActiveClientSecrets = 2
+
+// ClientSecret authenticates a client; only a hash of the secret itself is stored, so it's returned just once when it's created
+type ClientSecret struct {
+	ID string `json:"id,omitempty"`
+	// ClientID is the id of the client the secret belongs to, not its public client id
+	ClientID   string     `json:"clientID,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Active     bool       `json:"active"`
+	Secret     string     `json:"secret,omitempty"`
+}
+
+// Impersonation is used to log in as another user, while keeping track of the administrator doing so
+type Impersonation struct {
+	User         *contracts.User
+	Impersonator string
 }
 
 // OrderField determines sorting direction
@@ -437,6 +808,11 @@ const (
 	FilterLast
 	FilterArchived
 	FilterBotName
+	FilterSource
+	FilterActor
+	FilterAction
+	FilterTargetType
+	FilterTargetID
 )
 
 var filters = []string{
@@ -458,6 +834,11 @@ var filters = []string{
 	"last",
 	"archived",
 	"bot",
+	"source",
+	"actor",
+	"action",
+	"target-type",
+	"target-id",
 }
 
 func (f FilterType) String() string {
@@ -479,3 +860,9 @@ func ToFilter(f string) *FilterType {
 func Filters() []string {
 	return filters
 }
+
+// StatusQueued is the status of a build, release or bot waiting in the job queue for a builder job to be created
+const StatusQueued contracts.Status = "queued"
+
+// StatusReasonSuperseded is the status reason of a build canceled because a newer build for the same branch was created
+const StatusReasonSuperseded = "superseded"
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 )
 
 func TestUnmarshalIAPJWK(t *testing.T) {
@@ -140,6 +142,13 @@ func TestPermissionsToString(t *testing.T) {
 		}
 	})
 
+	t.Run("AllPermissionConstantsHaveAString", func(t *testing.T) {
+
+		permissions := Permissions()
+
+		assert.Equal(t, int(PermissionNotificationDeliveriesGet)+1, len(permissions))
+	})
+
 	t.Run("AllPermissionsCanBeConvertedToPermission", func(t *testing.T) {
 
 		permissions := Permissions()
@@ -176,3 +185,189 @@ func TestFiltersToString(t *testing.T) {
 		}
 	})
 }
+
+func TestCustomRole(t *testing.T) {
+	t.Run("ValidateReturnsNilForValidCustomRole", func(t *testing.T) {
+
+		customRole := getCustomRole()
+
+		// act
+		err := customRole.Validate()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorForUnknownPermission", func(t *testing.T) {
+
+		customRole := getCustomRole()
+		customRole.Permissions = []string{"ci.releases.launch"}
+
+		// act
+		err := customRole.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorForBindingWithoutSubject", func(t *testing.T) {
+
+		customRole := getCustomRole()
+		customRole.Bindings[0].Groups = []string{}
+
+		// act
+		err := customRole.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorForBindingWithoutPipelinesOrLabels", func(t *testing.T) {
+
+		customRole := getCustomRole()
+		customRole.Bindings[0].Labels = []contracts.Label{}
+
+		// act
+		err := customRole.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorForIncompletePipeline", func(t *testing.T) {
+
+		customRole := getCustomRole()
+		customRole.Bindings[0].Pipelines = []string{"github.com/ziplineeci"}
+
+		// act
+		err := customRole.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("MatchesPipelineReturnsTrueForListedPipeline", func(t *testing.T) {
+
+		binding := CustomRoleBinding{
+			Pipelines: []string{"github.com/ziplineeci/ziplinee-ci-api"},
+		}
+
+		// act
+		matches := binding.MatchesPipeline(contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api"})
+
+		assert.True(t, matches)
+	})
+
+	t.Run("MatchesPipelineReturnsTrueIfPipelineHasAllLabels", func(t *testing.T) {
+
+		binding := getCustomRole().Bindings[0]
+
+		// act
+		matches := binding.MatchesPipeline(contracts.Pipeline{Labels: []contracts.Label{{Key: "language", Value: "golang"}, {Key: "team", Value: "payments"}}})
+
+		assert.True(t, matches)
+	})
+
+	t.Run("MatchesPipelineReturnsFalseIfPipelineMissesALabel", func(t *testing.T) {
+
+		binding := getCustomRole().Bindings[0]
+
+		// act
+		matches := binding.MatchesPipeline(contracts.Pipeline{Labels: []contracts.Label{{Key: "team", Value: "checkout"}}})
+
+		assert.False(t, matches)
+	})
+
+	t.Run("AllowsReleaseTargetReturnsFalseForUnlistedReleaseTarget", func(t *testing.T) {
+
+		binding := getCustomRole().Bindings[0]
+
+		// act
+		allowed := binding.AllowsReleaseTarget("staging")
+
+		assert.False(t, allowed)
+	})
+
+	t.Run("AllowsReleaseTargetReturnsTrueForActionsWithoutReleaseTarget", func(t *testing.T) {
+
+		binding := getCustomRole().Bindings[0]
+
+		// act
+		allowed := binding.AllowsReleaseTarget("")
+
+		assert.True(t, allowed)
+	})
+}
+
+func TestPersonalAccessToken(t *testing.T) {
+	t.Run("ValidateReturnsNilForValidToken", func(t *testing.T) {
+
+		now := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		token := getPersonalAccessToken(now)
+
+		// act
+		err := token.Validate(now)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorForUnknownScope", func(t *testing.T) {
+
+		now := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		token := getPersonalAccessToken(now)
+		token.Scopes = []string{"ci.builds.delete"}
+
+		// act
+		err := token.Validate(now)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ValidateReturnsErrorForExpiryBeyondMaximumLifetime", func(t *testing.T) {
+
+		now := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		token := getPersonalAccessToken(now)
+		expiresAt := now.Add(MaxPersonalAccessTokenLifetime + time.Hour)
+		token.ExpiresAt = &expiresAt
+
+		// act
+		err := token.Validate(now)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("IsExpiredReturnsTrueAfterExpiry", func(t *testing.T) {
+
+		now := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		token := getPersonalAccessToken(now)
+
+		// act
+		isExpired := token.IsExpired(now.Add(31 * 24 * time.Hour))
+
+		assert.True(t, isExpired)
+	})
+}
+
+func getCustomRole() CustomRole {
+	return CustomRole{
+		Name:        "payments-releaser",
+		Permissions: []string{"ci.releases.create"},
+		Bindings: []*CustomRoleBinding{
+			{
+				Groups: []string{"team-payments"},
+				Labels: []contracts.Label{
+					{
+						Key:   "team",
+						Value: "payments",
+					},
+				},
+				ReleaseTargets: []string{"production"},
+			},
+		},
+		Active: true,
+	}
+}
+
+func getPersonalAccessToken(now time.Time) PersonalAccessToken {
+	expiresAt := now.Add(30 * 24 * time.Hour)
+	return PersonalAccessToken{
+		Name:      "release-script",
+		Scopes:    []string{"ci.releases.create"},
+		ExpiresAt: &expiresAt,
+	}
+}
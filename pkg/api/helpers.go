@@ -28,8 +28,16 @@ var (
 
 func GenerateJWT(config *APIConfig, now time.Time, expiry time.Time, optionalClaims jwtgo.MapClaims) (tokenString string, err error) {
 
+	key, err := config.Auth.JWT.getSigningKey()
+	if err != nil {
+		return
+	}
+
 	// Create the token
-	token := jwtgo.New(jwtgo.SigningMethodHS256)
+	token := jwtgo.New(key.method)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
 	claims := token.Claims.(jwtgo.MapClaims)
 
 	// set required claims
@@ -41,16 +49,11 @@ func GenerateJWT(config *APIConfig, now time.Time, expiry time.Time, optionalCla
 	}
 
 	// sign the token
-	return token.SignedString([]byte(config.Auth.JWT.Key))
+	return token.SignedString(key.signingKey)
 }
 
 func ValidateJWT(config *APIConfig, tokenString string) (token *jwtgo.Token, err error) {
-	return jwtgo.Parse(tokenString, func(t *jwtgo.Token) (interface{}, error) {
-		if jwtgo.SigningMethodHS256 != t.Method {
-			return nil, ErrInvalidSigningAlgorithm
-		}
-		return []byte(config.Auth.JWT.Key), nil
-	})
+	return jwtgo.Parse(tokenString, config.Auth.JWT.getVerificationKey)
 }
 
 func GetClaimsFromJWT(config *APIConfig, tokenString string) (claims jwtgo.MapClaims, err error) {
@@ -295,14 +298,97 @@ func GetRolesFromRequest(c *gin.Context) (roles []Role) {
 func GetPermissionsFromRequest(c *gin.Context) (permissions []Permission) {
 
 	roles := GetRolesFromRequest(c)
+	scopes, isScoped := GetScopesFromRequest(c)
 
 	for _, r := range roles {
-		permissions = append(permissions, rolesToPermissionMap[r]...)
+		if organizationScopedRoles[r] {
+			continue
+		}
+		for _, p := range rolesToPermissionMap[r] {
+			// personal access tokens only have the permissions of their user that are within their scopes
+			if isScoped && !StringArrayContains(scopes, p.String()) {
+				continue
+			}
+			permissions = append(permissions, p)
+		}
 	}
 
 	return
 }
 
+// GetScopesFromRequest returns the scopes of the personal access token used for the request; requests made otherwise aren't scoped
+func GetScopesFromRequest(c *gin.Context) (scopes []string, isScoped bool) {
+
+	if !RequestTokenIsValid(c) {
+		return
+	}
+
+	claims := jwt.ExtractClaims(c)
+	val, ok := claims["scopes"]
+	if !ok {
+		return
+	}
+
+	scopesFromClaim, ok := val.([]interface{})
+	if !ok {
+		// the claim being present scopes the request, even if it can't be read
+		return []string{}, true
+	}
+
+	scopes = []string{}
+	for _, s := range scopesFromClaim {
+		if sval, ok := s.(string); ok {
+			scopes = append(scopes, sval)
+		}
+	}
+
+	return scopes, true
+}
+
+// GetPersonalAccessTokenIDFromRequest returns the id of the personal access token used for the request, if any
+func GetPersonalAccessTokenIDFromRequest(c *gin.Context) string {
+
+	if !RequestTokenIsValid(c) {
+		return ""
+	}
+
+	claims := jwt.ExtractClaims(c)
+	if tokenID, ok := claims["tokenID"].(string); ok {
+		return tokenID
+	}
+
+	return ""
+}
+
+// GetOrganizationScopeFromRequest returns whether the request has a permission and, if it only has it through an organization
+// scoped role, the names of the organizations it has the permission in; without organizations the request has it everywhere
+func GetOrganizationScopeFromRequest(c *gin.Context, permission Permission) (hasPermission bool, organizations []string) {
+
+	if RequestTokenHasPermission(c, permission) {
+		return true, nil
+	}
+
+	scopes, isScoped := GetScopesFromRequest(c)
+	if isScoped && !StringArrayContains(scopes, permission.String()) {
+		return false, nil
+	}
+
+	for _, r := range GetRolesFromRequest(c) {
+		if !organizationScopedRoles[r] {
+			continue
+		}
+		for _, p := range rolesToPermissionMap[r] {
+			if p == permission {
+				// a request without organizations doesn't have the permission anywhere
+				organizations = GetOrganizationsFromRequest(c)
+				return len(organizations) > 0, organizations
+			}
+		}
+	}
+
+	return false, nil
+}
+
 func RequestTokenHasPermission(c *gin.Context, permission Permission) bool {
 
 	permissions := GetPermissionsFromRequest(c)
@@ -316,6 +402,51 @@ func RequestTokenHasPermission(c *gin.Context, permission Permission) bool {
 	return false
 }
 
+// RequestTokenHasPipelinePermission returns true if the request has the permission through its roles, or if one of the custom
+// roles containing the permission is bound to the request's user, groups or organizations for the pipeline
+func RequestTokenHasPipelinePermission(c *gin.Context, permission Permission, customRoles []*CustomRole, pipeline contracts.Pipeline, releaseTarget string) bool {
+
+	if RequestTokenHasPermission(c, permission) {
+		return true
+	}
+
+	// custom roles can't grant personal access tokens permissions outside of their scopes either
+	if scopes, isScoped := GetScopesFromRequest(c); isScoped && !StringArrayContains(scopes, permission.String()) {
+		return false
+	}
+
+	email := GetEmailFromRequest(c)
+	groups := GetGroupsFromRequest(c)
+	organizations := GetOrganizationsFromRequest(c)
+
+	for _, r := range customRoles {
+		if r == nil || !r.Active || !r.HasPermission(permission) {
+			continue
+		}
+		for _, b := range r.Bindings {
+			if b != nil && b.AppliesToSubject(email, groups, organizations) && b.MatchesPipeline(pipeline) && b.AllowsReleaseTarget(releaseTarget) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func GetEmailFromRequest(c *gin.Context) string {
+
+	if !RequestTokenIsValid(c) {
+		return ""
+	}
+
+	claims := jwt.ExtractClaims(c)
+	if email, ok := claims["email"].(string); ok {
+		return email
+	}
+
+	return ""
+}
+
 func GetGroupsFromRequest(c *gin.Context) (groups []string) {
 
 	if !RequestTokenIsValid(c) {
@@ -376,8 +507,9 @@ func SetPermissionsFilters(c *gin.Context, filters map[FilterType][]string) map[
 	filters[FilterOrganizations] = GetGenericFilter(c, FilterOrganizations)
 	filters[FilterGroups] = GetGenericFilter(c, FilterGroups)
 
-	if RequestTokenHasRole(c, RoleAdministrator) {
-		// admin can see all pipelines for all orgs and groups
+	// admin can see all pipelines for all orgs and groups, unless it's a token scoped to exclude listing pipelines
+	scopes, isScoped := GetScopesFromRequest(c)
+	if RequestTokenHasRole(c, RoleAdministrator) && (!isScoped || StringArrayContains(scopes, PermissionPipelinesList.String())) {
 		return filters
 	}
 
@@ -477,6 +609,11 @@ func GetFilters(c *gin.Context) map[FilterType][]string {
 	filters[FilterEntity] = GetGenericFilter(c, FilterEntity)
 	filters[FilterBranch] = GetGenericFilter(c, FilterBranch)
 	filters[FilterBotName] = GetGenericFilter(c, FilterBotName)
+	filters[FilterSource] = GetGenericFilter(c, FilterSource)
+	filters[FilterActor] = GetGenericFilter(c, FilterActor)
+	filters[FilterAction] = GetGenericFilter(c, FilterAction)
+	filters[FilterTargetType] = GetGenericFilter(c, FilterTargetType)
+	filters[FilterTargetID] = GetGenericFilter(c, FilterTargetID)
 
 	return filters
 }
@@ -2,11 +2,15 @@ package api
 
 import (
 	"io"
+	"net/http/httptest"
 	"regexp"
 	"testing"
 
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
 	"github.com/sethgrid/pester"
 	"github.com/stretchr/testify/assert"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 )
 
 func TestRetrievingGoogleJSONWebKeys(t *testing.T) {
@@ -43,3 +47,348 @@ func TestRetrievingGoogleJSONWebKeys(t *testing.T) {
 		}
 	})
 }
+
+func TestRequestTokenHasPipelinePermission(t *testing.T) {
+
+	pipeline := contracts.Pipeline{
+		RepoSource: "github.com",
+		RepoOwner:  "ziplineeci",
+		RepoName:   "payments-api",
+		Labels: []contracts.Label{
+			{
+				Key:   "team",
+				Value: "payments",
+			},
+		},
+	}
+
+	t.Run("ReturnsTrueForRoleWithPermission", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationPipelinesOperator.String()},
+		})
+
+		// act
+		hasPermission := RequestTokenHasPipelinePermission(c, PermissionReleasesCreate, []*CustomRole{}, pipeline, "production")
+
+		assert.True(t, hasPermission)
+	})
+
+	t.Run("ReturnsTrueForCustomRoleBoundToGroupOfRequest", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+		})
+		customRole := getCustomRole()
+
+		// act
+		hasPermission := RequestTokenHasPipelinePermission(c, PermissionReleasesCreate, []*CustomRole{&customRole}, pipeline, "production")
+
+		assert.True(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForCustomRoleBoundToOtherReleaseTarget", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+		})
+		customRole := getCustomRole()
+
+		// act
+		hasPermission := RequestTokenHasPipelinePermission(c, PermissionReleasesCreate, []*CustomRole{&customRole}, pipeline, "staging")
+
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForCustomRoleWithoutPermission", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+		})
+		customRole := getCustomRole()
+
+		// act
+		hasPermission := RequestTokenHasPipelinePermission(c, PermissionReleasesCancel, []*CustomRole{&customRole}, pipeline, "production")
+
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForInactiveCustomRole", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+		})
+		customRole := getCustomRole()
+		customRole.Active = false
+
+		// act
+		hasPermission := RequestTokenHasPipelinePermission(c, PermissionReleasesCreate, []*CustomRole{&customRole}, pipeline, "production")
+
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("ReturnsTrueForCustomRoleBoundToUserOfRequest", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+		})
+		customRole := getCustomRole()
+		customRole.Bindings[0].Groups = nil
+		customRole.Bindings[0].Users = []string{"jane@ziplinee.io"}
+
+		// act
+		hasPermission := RequestTokenHasPipelinePermission(c, PermissionReleasesCreate, []*CustomRole{&customRole}, pipeline, "production")
+
+		assert.True(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForCustomRoleOutsideOfPersonalAccessTokenScopes", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+			"scopes":        []interface{}{PermissionBuildsCancel.String()},
+		})
+		customRole := getCustomRole()
+
+		// act
+		hasPermission := RequestTokenHasPipelinePermission(c, PermissionReleasesCreate, []*CustomRole{&customRole}, pipeline, "production")
+
+		assert.False(t, hasPermission)
+	})
+}
+
+func TestRequestTokenHasPermission(t *testing.T) {
+
+	t.Run("ReturnsTrueForPermissionWithinPersonalAccessTokenScopes", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationPipelinesOperator.String()},
+			"scopes":        []interface{}{PermissionBuildsCancel.String()},
+		})
+
+		// act
+		hasPermission := RequestTokenHasPermission(c, PermissionBuildsCancel)
+
+		assert.True(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForPermissionOfRoleOutsideOfPersonalAccessTokenScopes", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationPipelinesOperator.String()},
+			"scopes":        []interface{}{PermissionBuildsCancel.String()},
+		})
+
+		// act
+		hasPermission := RequestTokenHasPermission(c, PermissionReleasesCreate)
+
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForScopeWithoutRoleGrantingIt", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"scopes":        []interface{}{PermissionUsersDelete.String()},
+		})
+
+		// act
+		hasPermission := RequestTokenHasPermission(c, PermissionUsersDelete)
+
+		assert.False(t, hasPermission)
+	})
+}
+
+func TestSetUserClaims(t *testing.T) {
+
+	t.Run("GivesRequestPermissionsGroupsAndOrganizationsOfUser", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		roleName := RoleAdministrator.String()
+		user := &contracts.User{
+			ID:            "5",
+			Identities:    []*contracts.UserIdentity{{Provider: "google", Email: "jane@ziplinee.io"}},
+			Roles:         []*string{&roleName},
+			Groups:        []*contracts.Group{{Name: "team-payments"}},
+			Organizations: []*contracts.Organization{{Name: "Org A"}},
+		}
+
+		// act
+		err := SetUserClaims(c, user)
+
+		assert.Nil(t, err)
+		assert.True(t, RequestTokenIsValid(c))
+		assert.True(t, RequestTokenHasPermission(c, PermissionBuildsCancel))
+		assert.Equal(t, "jane@ziplinee.io", GetEmailFromRequest(c))
+		assert.Equal(t, []string{"team-payments"}, GetGroupsFromRequest(c))
+		assert.Equal(t, []string{"Org A"}, GetOrganizationsFromRequest(c))
+	})
+}
+
+func TestGetOrganizationScopeFromRequest(t *testing.T) {
+
+	t.Run("ReturnsTrueWithoutOrganizationsForPermissionOfUnscopedRole", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationManager.String(), RoleUserAdmin.String()},
+			"organizations": []interface{}{"Org A"},
+		})
+
+		// act
+		hasPermission, organizations := GetOrganizationScopeFromRequest(c, PermissionUsersUpdate)
+
+		assert.True(t, hasPermission)
+		assert.Nil(t, organizations)
+	})
+
+	t.Run("ReturnsTrueWithOrganizationsForPermissionOfOrganizationManager", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationManager.String()},
+			"organizations": []interface{}{"Org A", "Org B"},
+		})
+
+		// act
+		hasPermission, organizations := GetOrganizationScopeFromRequest(c, PermissionUsersUpdate)
+
+		assert.True(t, hasPermission)
+		assert.Equal(t, []string{"Org A", "Org B"}, organizations)
+	})
+
+	t.Run("ReturnsFalseForOrganizationManagerWithoutOrganizations", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationManager.String()},
+		})
+
+		// act
+		hasPermission, _ := GetOrganizationScopeFromRequest(c, PermissionUsersUpdate)
+
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForPermissionOutsideOfScopes", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationManager.String()},
+			"organizations": []interface{}{"Org A"},
+			"scopes":        []interface{}{PermissionUsersList.String()},
+		})
+
+		// act
+		hasPermission, _ := GetOrganizationScopeFromRequest(c, PermissionUsersUpdate)
+
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("ReturnsFalseForPermissionOrganizationManagerDoesNotHave", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationManager.String()},
+			"organizations": []interface{}{"Org A"},
+		})
+
+		// act
+		hasPermission, _ := GetOrganizationScopeFromRequest(c, PermissionOrganizationsUpdate)
+
+		assert.False(t, hasPermission)
+	})
+
+	t.Run("OrganizationManagerPermissionsAreNotGrantedUnscoped", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleOrganizationManager.String()},
+			"organizations": []interface{}{"Org A"},
+		})
+
+		// act
+		hasPermission := RequestTokenHasPermission(c, PermissionUsersUpdate)
+
+		assert.False(t, hasPermission)
+	})
+}
+
+func TestSetPermissionsFilters(t *testing.T) {
+
+	t.Run("DoesNotFilterForAdministrator", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "https://ci.ziplinee.io/api/pipelines", nil)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleAdministrator.String()},
+			"organizations": []interface{}{"team-payments"},
+		})
+
+		// act
+		filters := SetPermissionsFilters(c, map[FilterType][]string{})
+
+		assert.Equal(t, 0, len(filters[FilterOrganizations]))
+	})
+
+	t.Run("FiltersOnOrganizationsForAdministratorTokenScopedToExcludeListingPipelines", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "https://ci.ziplinee.io/api/pipelines", nil)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{RoleAdministrator.String()},
+			"organizations": []interface{}{"team-payments"},
+			"tokenID":       "5678",
+			"scopes":        []interface{}{"ci.builds.get"},
+		})
+
+		// act
+		filters := SetPermissionsFilters(c, map[FilterType][]string{})
+
+		assert.Equal(t, []string{"team-payments"}, filters[FilterOrganizations])
+	})
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+const (
+	// jobTokenExpiryMargin keeps a job token valid a little longer than its job can run, for the callbacks after the last stage
+	jobTokenExpiryMargin = 15 * time.Minute
+
+	// legacyJobTokenGracePeriod is how long after starting tokens that aren't bound to a job are still accepted, for the jobs
+	// started by a previous version; those tokens expire within 6 hours after being issued
+	legacyJobTokenGracePeriod = 6 * time.Hour
+)
+
+// legacyJobTokenGracePeriodStart is when this version started; only tokens issued before can be legacy job tokens
+var legacyJobTokenGracePeriodStart = time.Now().UTC()
+
+// JobTokenClaims bind the token a build, release or bot job uses for its callbacks to that job only
+type JobTokenClaims struct {
+	JobName    string
+	JobType    contracts.JobType
+	RepoSource string
+	RepoOwner  string
+	RepoName   string
+	// ID is the id of the build, release or bot
+	ID string
+	// Legacy is set for a token issued by a previous version, which is only bound to its job name
+	Legacy bool
+}
+
+// GenerateJobToken returns a token for the callbacks of a job, expiring shortly after the job's maximum run duration
+func GenerateJobToken(config *APIConfig, now time.Time, jobClaims JobTokenClaims) (token string, expiry time.Time, err error) {
+
+	expiry = now.Add(time.Duration(config.Jobs.MaxRunDurationMinutes) * time.Minute).Add(jobTokenExpiryMargin)
+
+	token, err = GenerateJWT(config, now, expiry, jwtgo.MapClaims{
+		"job":        jobClaims.JobName,
+		"jobType":    string(jobClaims.JobType),
+		"repoSource": jobClaims.RepoSource,
+		"repoOwner":  jobClaims.RepoOwner,
+		"repoName":   jobClaims.RepoName,
+		"jobID":      jobClaims.ID,
+	})
+
+	return
+}
+
+// GetJobTokenClaimsFromRequest returns the claims of the job token used for the request; tokens without all job claims aren't job tokens
+func GetJobTokenClaimsFromRequest(c *gin.Context) (jobClaims JobTokenClaims, ok bool) {
+
+	claims := jwt.ExtractClaims(c)
+
+	jobClaims.JobName, _ = claims["job"].(string)
+	jobType, _ := claims["jobType"].(string)
+	jobClaims.JobType = contracts.JobType(jobType)
+	jobClaims.RepoSource, _ = claims["repoSource"].(string)
+	jobClaims.RepoOwner, _ = claims["repoOwner"].(string)
+	jobClaims.RepoName, _ = claims["repoName"].(string)
+	jobClaims.ID, _ = claims["jobID"].(string)
+
+	ok = jobClaims.JobName != "" && jobClaims.JobType != contracts.JobTypeUnknown && jobClaims.RepoSource != "" && jobClaims.RepoOwner != "" && jobClaims.RepoName != "" && jobClaims.ID != ""
+	if !ok && isLegacyJobToken(claims, time.Now().UTC()) {
+		log.Warn().Msgf("Accepting legacy token for job %v, which isn't bound to its job", jobClaims.JobName)
+		return JobTokenClaims{JobName: jobClaims.JobName, Legacy: true}, true
+	}
+
+	return
+}
+
+// isLegacyJobToken returns true for a token with only a job name, issued by a previous version before this one started, during
+// the grace period for the jobs still running with such tokens
+func isLegacyJobToken(claims jwt.MapClaims, now time.Time) bool {
+
+	jobName, _ := claims["job"].(string)
+	issuedAt, hasIssuedAt := claims["orig_iat"].(float64)
+	_, hasJobType := claims["jobType"]
+	_, hasJobID := claims["jobID"]
+
+	if jobName == "" || !hasIssuedAt || hasJobType || hasJobID {
+		return false
+	}
+
+	return time.Unix(int64(issuedAt), 0).Before(legacyJobTokenGracePeriodStart) && now.Before(legacyJobTokenGracePeriodStart.Add(legacyJobTokenGracePeriod))
+}
+
+// Matches returns true if the job the token is issued for is the given build, release or bot; legacy tokens match any job
+func (c JobTokenClaims) Matches(jobType contracts.JobType, repoSource, repoOwner, repoName, id string) bool {
+	if c.Legacy {
+		return true
+	}
+
+	return c.JobType == jobType && c.RepoSource == repoSource && c.RepoOwner == repoOwner && c.RepoName == repoName && c.ID == id
+}
+
+// MatchesEvent returns true if a builder event is sent for the job the token is issued for
+func (c JobTokenClaims) MatchesEvent(event contracts.ZiplineeCiBuilderEvent) bool {
+	if event.JobName != c.JobName {
+		return false
+	}
+	if c.Legacy {
+		return true
+	}
+	if event.Git == nil {
+		return false
+	}
+
+	var id string
+	switch event.JobType {
+	case contracts.JobTypeBuild:
+		if event.Build == nil {
+			return false
+		}
+		id = event.Build.ID
+	case contracts.JobTypeRelease:
+		if event.Release == nil {
+			return false
+		}
+		id = event.Release.ID
+	case contracts.JobTypeBot:
+		if event.Bot == nil {
+			return false
+		}
+		id = event.Bot.ID
+	}
+
+	return c.Matches(event.JobType, event.Git.RepoSource, event.Git.RepoOwner, event.Git.RepoName, id)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+func TestGenerateJobToken(t *testing.T) {
+	t.Run("ReturnsTokenWithJobClaimsExpiringAfterMaxRunDuration", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{Key: "this is my secret"})
+		config.Jobs = &JobsConfig{MaxRunDurationMinutes: 60}
+		now := time.Now().UTC()
+
+		// act
+		token, expiry, err := GenerateJobToken(config, now, JobTokenClaims{
+			JobName:    "build-ziplineeci-payments-api-1234",
+			JobType:    contracts.JobTypeBuild,
+			RepoSource: "github.com",
+			RepoOwner:  "ziplineeci",
+			RepoName:   "payments-api",
+			ID:         "1234",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, now.Add(75*time.Minute), expiry)
+
+		claims, err := GetClaimsFromJWT(config, token)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "build-ziplineeci-payments-api-1234", claims["job"])
+			assert.Equal(t, "build", claims["jobType"])
+			assert.Equal(t, "1234", claims["jobID"])
+		}
+	})
+}
+
+func TestGetJobTokenClaimsFromRequest(t *testing.T) {
+	t.Run("ReturnsFalseForTokenWithJobNameOnly", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(nil)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			"job": "build-ziplineeci-payments-api-1234",
+		})
+
+		// act
+		_, ok := GetJobTokenClaimsFromRequest(c)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("ReturnsLegacyClaimsForTokenWithJobNameOnlyIssuedBeforeStart", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(nil)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			"job":      "build-ziplineeci-payments-api-1234",
+			"orig_iat": float64(legacyJobTokenGracePeriodStart.Add(-time.Hour).Unix()),
+		})
+
+		// act
+		jobClaims, ok := GetJobTokenClaimsFromRequest(c)
+
+		assert.True(t, ok)
+		assert.True(t, jobClaims.Legacy)
+		assert.Equal(t, "build-ziplineeci-payments-api-1234", jobClaims.JobName)
+	})
+
+	t.Run("ReturnsFalseForTokenWithJobNameOnlyIssuedAfterStart", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(nil)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			"job":      "build-ziplineeci-payments-api-1234",
+			"orig_iat": float64(legacyJobTokenGracePeriodStart.Add(time.Minute).Unix()),
+		})
+
+		// act
+		_, ok := GetJobTokenClaimsFromRequest(c)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("ReturnsFalseForUserToken", func(t *testing.T) {
+
+		c, _ := gin.CreateTestContext(nil)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "15",
+			"email":         "user@server.com",
+		})
+
+		// act
+		_, ok := GetJobTokenClaimsFromRequest(c)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestIsLegacyJobToken(t *testing.T) {
+
+	claims := jwt.MapClaims{
+		"job":      "build-ziplineeci-payments-api-1234",
+		"orig_iat": float64(legacyJobTokenGracePeriodStart.Add(-time.Hour).Unix()),
+	}
+
+	t.Run("ReturnsTrueDuringGracePeriod", func(t *testing.T) {
+
+		// act
+		isLegacy := isLegacyJobToken(claims, legacyJobTokenGracePeriodStart.Add(legacyJobTokenGracePeriod-time.Minute))
+
+		assert.True(t, isLegacy)
+	})
+
+	t.Run("ReturnsFalseAfterGracePeriod", func(t *testing.T) {
+
+		// act
+		isLegacy := isLegacyJobToken(claims, legacyJobTokenGracePeriodStart.Add(legacyJobTokenGracePeriod))
+
+		assert.False(t, isLegacy)
+	})
+
+	t.Run("ReturnsFalseForIncompleteBoundToken", func(t *testing.T) {
+
+		boundClaims := jwt.MapClaims{
+			"job":      "build-ziplineeci-payments-api-1234",
+			"jobType":  "build",
+			"orig_iat": float64(legacyJobTokenGracePeriodStart.Add(-time.Hour).Unix()),
+		}
+
+		// act
+		isLegacy := isLegacyJobToken(boundClaims, legacyJobTokenGracePeriodStart)
+
+		assert.False(t, isLegacy)
+	})
+}
+
+func TestJobTokenClaimsMatchesEvent(t *testing.T) {
+
+	jobClaims := JobTokenClaims{
+		JobName:    "release-ziplineeci-payments-api-55",
+		JobType:    contracts.JobTypeRelease,
+		RepoSource: "github.com",
+		RepoOwner:  "ziplineeci",
+		RepoName:   "payments-api",
+		ID:         "55",
+	}
+
+	t.Run("ReturnsTrueForEventOfJob", func(t *testing.T) {
+
+		event := contracts.ZiplineeCiBuilderEvent{
+			JobType: contracts.JobTypeRelease,
+			JobName: "release-ziplineeci-payments-api-55",
+			Git:     &contracts.GitConfig{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "payments-api"},
+			Release: &contracts.Release{ID: "55"},
+		}
+
+		// act
+		matches := jobClaims.MatchesEvent(event)
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsFalseForEventOfOtherRelease", func(t *testing.T) {
+
+		event := contracts.ZiplineeCiBuilderEvent{
+			JobType: contracts.JobTypeRelease,
+			JobName: "release-ziplineeci-payments-api-55",
+			Git:     &contracts.GitConfig{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "payments-api"},
+			Release: &contracts.Release{ID: "56"},
+		}
+
+		// act
+		matches := jobClaims.MatchesEvent(event)
+
+		assert.False(t, matches)
+	})
+
+	t.Run("ReturnsFalseForEventOfOtherJobType", func(t *testing.T) {
+
+		event := contracts.ZiplineeCiBuilderEvent{
+			JobType: contracts.JobTypeBuild,
+			JobName: "release-ziplineeci-payments-api-55",
+			Git:     &contracts.GitConfig{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "payments-api"},
+			Build:   &contracts.Build{ID: "55"},
+		}
+
+		// act
+		matches := jobClaims.MatchesEvent(event)
+
+		assert.False(t, matches)
+	})
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// JWTAlgorithmHS256 signs jwts with a shared secret
+	JWTAlgorithmHS256 = "HS256"
+	// JWTAlgorithmRS256 signs jwts with an rsa private key
+	JWTAlgorithmRS256 = "RS256"
+	// JWTAlgorithmES256 signs jwts with a P-256 ecdsa private key
+	JWTAlgorithmES256 = "ES256"
+)
+
+var (
+	// ErrUnknownJWTKeyID indicates a jwt is signed with a key that isn't configured (anymore)
+	ErrUnknownJWTKeyID = errors.New("unknown jwt key id")
+
+	// parsing pem encoded keys for every request is wasteful, so parsed keys are kept by their key id; the shared key without
+	// key id is kept under an empty one
+	jwtKeysCacheMutex sync.Mutex
+	jwtKeysCache      = map[string]*jwtKey{}
+)
+
+// jwtKey is a parsed JWTKeyConfig
+type jwtKey struct {
+	config          JWTKeyConfig
+	id              string
+	method          jwtgo.SigningMethod
+	signingKey      interface{}
+	verificationKey interface{}
+}
+
+// parseJWTKey returns the keys to sign and verify jwts with for a key configuration
+func parseJWTKey(config *JWTKeyConfig) (key *jwtKey, err error) {
+
+	jwtKeysCacheMutex.Lock()
+	defer jwtKeysCacheMutex.Unlock()
+
+	// a changed configuration for the key id replaces the cached key, so rotations don't pile up keys
+	if key, ok := jwtKeysCache[config.ID]; ok && key.config == *config {
+		return key, nil
+	}
+
+	key = &jwtKey{
+		config: *config,
+		id:     config.ID,
+	}
+
+	switch config.Algorithm {
+	case JWTAlgorithmHS256, "":
+		key.method = jwtgo.SigningMethodHS256
+		key.signingKey = []byte(config.Key)
+		key.verificationKey = key.signingKey
+
+	case JWTAlgorithmRS256:
+		privateKey, err := jwtgo.ParseRSAPrivateKeyFromPEM([]byte(config.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		key.method = jwtgo.SigningMethodRS256
+		key.signingKey = privateKey
+		key.verificationKey = &privateKey.PublicKey
+
+	case JWTAlgorithmES256:
+		privateKey, err := jwtgo.ParseECPrivateKeyFromPEM([]byte(config.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		if privateKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ES256 requires a P-256 key, not %v", privateKey.Curve.Params().Name)
+		}
+		key.method = jwtgo.SigningMethodES256
+		key.signingKey = privateKey
+		key.verificationKey = &privateKey.PublicKey
+
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSigningAlgorithm, config.Algorithm)
+	}
+
+	jwtKeysCache[config.ID] = key
+
+	return key, nil
+}
+
+// getSigningKey returns the key new jwts are signed with
+func (c *JWTConfig) getSigningKey() (key *jwtKey, err error) {
+	for _, k := range c.Keys {
+		if k != nil && k.ID == c.SigningKeyID {
+			return parseJWTKey(k)
+		}
+	}
+
+	if len(c.Keys) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownJWTKeyID, c.SigningKeyID)
+	}
+
+	// without keys all jwts are signed with the single shared secret, without key id
+	return parseJWTKey(&JWTKeyConfig{Algorithm: JWTAlgorithmHS256, Key: c.Key})
+}
+
+// getVerificationKey returns the key to verify a jwt with by its kid header, to be used as jwtgo.Keyfunc
+func (c *JWTConfig) getVerificationKey(token *jwtgo.Token) (verificationKey interface{}, err error) {
+
+	var key *jwtKey
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// jwts signed before keys were configured
+		if c.Key == "" {
+			return nil, fmt.Errorf("%w: jwt has no key id", ErrUnknownJWTKeyID)
+		}
+		key, err = parseJWTKey(&JWTKeyConfig{Algorithm: JWTAlgorithmHS256, Key: c.Key})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, k := range c.Keys {
+			if k != nil && k.ID == kid {
+				key, err = parseJWTKey(k)
+				if err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+		if key == nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownJWTKeyID, kid)
+		}
+	}
+
+	// the algorithm is dictated by the key, not by the jwt
+	if token.Method != key.method {
+		return nil, ErrInvalidSigningAlgorithm
+	}
+
+	return key.verificationKey, nil
+}
+
+// GetJSONWebKeySet returns the public keys of all asymmetric keys, for other services to verify jwts issued by this api
+func (c *JWTConfig) GetJSONWebKeySet() (keySet JSONWebKeySet, err error) {
+
+	keySet.Keys = []JSONWebKey{}
+
+	for _, k := range c.Keys {
+		if k == nil {
+			continue
+		}
+		key, err := parseJWTKey(k)
+		if err != nil {
+			return keySet, err
+		}
+
+		switch publicKey := key.verificationKey.(type) {
+		case *rsa.PublicKey:
+			keySet.Keys = append(keySet.Keys, JSONWebKey{
+				KeyType:      "RSA",
+				Algorithm:    key.method.Alg(),
+				PublicKeyUse: "sig",
+				KeyID:        key.id,
+				N:            base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+
+		case *ecdsa.PublicKey:
+			// coordinates have the fixed length of the curve
+			size := (publicKey.Curve.Params().BitSize + 7) / 8
+			keySet.Keys = append(keySet.Keys, JSONWebKey{
+				KeyType:      "EC",
+				Algorithm:    key.method.Alg(),
+				PublicKeyUse: "sig",
+				KeyID:        key.id,
+				Curve:        publicKey.Curve.Params().Name,
+				X:            base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size))),
+				Y:            base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size))),
+			})
+		}
+	}
+
+	return keySet, nil
+}
@@ -0,0 +1,337 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAndValidateJWT(t *testing.T) {
+	t.Run("SignsWithSharedKeyWithoutKeyIDIfNoKeysAreConfigured", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{Key: "this is my secret"})
+
+		// act
+		tokenString, err := GenerateJWT(config, time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{"job": "abc"})
+
+		assert.Nil(t, err)
+		token, err := ValidateJWT(config, tokenString)
+		if assert.Nil(t, err) {
+			assert.Nil(t, token.Header["kid"])
+			assert.Equal(t, "HS256", token.Method.Alg())
+			assert.Equal(t, "abc", token.Claims.(jwtgo.MapClaims)["job"])
+		}
+	})
+
+	t.Run("SignsWithSigningKeyAndItsKeyID", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{
+			Key: "this is my secret",
+			Keys: []*JWTKeyConfig{
+				{ID: "2024-01", Key: "old secret"},
+				{ID: "2024-06", Key: "new secret"},
+			},
+			SigningKeyID: "2024-06",
+		})
+
+		// act
+		tokenString, err := GenerateJWT(config, time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{})
+
+		assert.Nil(t, err)
+		token, err := ValidateJWT(config, tokenString)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "2024-06", token.Header["kid"])
+		}
+	})
+
+	t.Run("ValidatesJWTSignedWithPreviousKeyDuringGracePeriod", func(t *testing.T) {
+
+		oldConfig := getJWTTestConfig(&JWTConfig{
+			Keys: []*JWTKeyConfig{
+				{ID: "2024-01", Key: "old secret"},
+			},
+		})
+		tokenString, err := GenerateJWT(oldConfig, time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{})
+		assert.Nil(t, err)
+
+		newConfig := getJWTTestConfig(&JWTConfig{
+			Keys: []*JWTKeyConfig{
+				{ID: "2024-01", Key: "old secret"},
+				{ID: "2024-06", Key: "new secret"},
+			},
+			SigningKeyID: "2024-06",
+		})
+
+		// act
+		_, err = ValidateJWT(newConfig, tokenString)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ValidatesJWTWithoutKeyIDWithSharedKey", func(t *testing.T) {
+
+		tokenString, err := GenerateJWT(getJWTTestConfig(&JWTConfig{Key: "this is my secret"}), time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{})
+		assert.Nil(t, err)
+
+		config := getJWTTestConfig(&JWTConfig{
+			Key: "this is my secret",
+			Keys: []*JWTKeyConfig{
+				{ID: "2024-06", Key: "new secret"},
+			},
+		})
+
+		// act
+		_, err = ValidateJWT(config, tokenString)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrorForRemovedKey", func(t *testing.T) {
+
+		tokenString, err := GenerateJWT(getJWTTestConfig(&JWTConfig{Keys: []*JWTKeyConfig{{ID: "2024-01", Key: "old secret"}}}), time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{})
+		assert.Nil(t, err)
+
+		config := getJWTTestConfig(&JWTConfig{
+			Keys: []*JWTKeyConfig{
+				{ID: "2024-06", Key: "new secret"},
+			},
+		})
+
+		// act
+		_, err = ValidateJWT(config, tokenString)
+
+		assert.True(t, errors.Is(err, ErrUnknownJWTKeyID))
+	})
+
+	t.Run("SignsAndValidatesWithRS256Key", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{
+			Keys: []*JWTKeyConfig{
+				{ID: "rsa", Algorithm: JWTAlgorithmRS256, PrivateKey: getRSAPrivateKeyPEM(t)},
+			},
+		})
+
+		// act
+		tokenString, err := GenerateJWT(config, time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{})
+
+		assert.Nil(t, err)
+		token, err := ValidateJWT(config, tokenString)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "RS256", token.Method.Alg())
+		}
+	})
+
+	t.Run("SignsAndValidatesWithES256Key", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{
+			Keys: []*JWTKeyConfig{
+				{ID: "ec", Algorithm: JWTAlgorithmES256, PrivateKey: getECPrivateKeyPEM(t)},
+			},
+		})
+
+		// act
+		tokenString, err := GenerateJWT(config, time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{})
+
+		assert.Nil(t, err)
+		token, err := ValidateJWT(config, tokenString)
+		if assert.Nil(t, err) {
+			assert.Equal(t, "ES256", token.Method.Alg())
+		}
+	})
+
+	t.Run("ReturnsErrorIfAlgorithmDoesNotMatchKey", func(t *testing.T) {
+
+		// a jwt using the kid of an rsa key, but signed with hs256
+		token := jwtgo.New(jwtgo.SigningMethodHS256)
+		token.Header["kid"] = "rsa"
+		tokenString, err := token.SignedString([]byte("guessed"))
+		assert.Nil(t, err)
+
+		config := getJWTTestConfig(&JWTConfig{
+			Keys: []*JWTKeyConfig{
+				{ID: "rsa", Algorithm: JWTAlgorithmRS256, PrivateKey: getRSAPrivateKeyPEM(t)},
+			},
+		})
+
+		// act
+		_, err = ValidateJWT(config, tokenString)
+
+		assert.True(t, errors.Is(err, ErrInvalidSigningAlgorithm))
+	})
+}
+
+func TestParseJWTKey(t *testing.T) {
+	t.Run("ReplacesCachedKeyIfConfigurationForKeyIDChanges", func(t *testing.T) {
+
+		// act
+		key, err := parseJWTKey(&JWTKeyConfig{ID: "rotated", Algorithm: JWTAlgorithmHS256, Key: "first-secret-of-at-least-32-bytes"})
+		assert.Nil(t, err)
+		rotatedKey, err := parseJWTKey(&JWTKeyConfig{ID: "rotated", Algorithm: JWTAlgorithmHS256, Key: "second-secret-of-at-least-32-bytes"})
+		assert.Nil(t, err)
+
+		assert.Equal(t, []byte("first-secret-of-at-least-32-bytes"), key.signingKey)
+		assert.Equal(t, []byte("second-secret-of-at-least-32-bytes"), rotatedKey.signingKey)
+		jwtKeysCacheMutex.Lock()
+		defer jwtKeysCacheMutex.Unlock()
+		assert.Same(t, rotatedKey, jwtKeysCache["rotated"])
+	})
+
+	t.Run("ReturnsCachedKeyForUnchangedConfiguration", func(t *testing.T) {
+
+		// act
+		key, err := parseJWTKey(&JWTKeyConfig{ID: "unchanged", Algorithm: JWTAlgorithmHS256, Key: "secret-of-at-least-32-bytes-long"})
+		assert.Nil(t, err)
+		cachedKey, err := parseJWTKey(&JWTKeyConfig{ID: "unchanged", Algorithm: JWTAlgorithmHS256, Key: "secret-of-at-least-32-bytes-long"})
+		assert.Nil(t, err)
+
+		assert.Same(t, key, cachedKey)
+	})
+}
+
+func TestGetJSONWebKeySet(t *testing.T) {
+	t.Run("ReturnsPublicKeysOfAsymmetricKeysOnly", func(t *testing.T) {
+
+		config := &JWTConfig{
+			Key: "this is my secret",
+			Keys: []*JWTKeyConfig{
+				{ID: "hmac", Key: "new secret"},
+				{ID: "rsa", Algorithm: JWTAlgorithmRS256, PrivateKey: getRSAPrivateKeyPEM(t)},
+				{ID: "ec", Algorithm: JWTAlgorithmES256, PrivateKey: getECPrivateKeyPEM(t)},
+			},
+		}
+		config.SetDefaults()
+
+		// act
+		keySet, err := config.GetJSONWebKeySet()
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(keySet.Keys)) {
+			assert.Equal(t, "rsa", keySet.Keys[0].KeyID)
+			assert.Equal(t, "RS256", keySet.Keys[0].Algorithm)
+			assert.Equal(t, "ec", keySet.Keys[1].KeyID)
+			assert.Equal(t, "P-256", keySet.Keys[1].Curve)
+		}
+	})
+
+	t.Run("ReturnsKeysThatVerifyIssuedJWTs", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{
+			Keys: []*JWTKeyConfig{
+				{ID: "ec", Algorithm: JWTAlgorithmES256, PrivateKey: getECPrivateKeyPEM(t)},
+			},
+		})
+		tokenString, err := GenerateJWT(config, time.Now(), time.Now().Add(time.Minute), jwtgo.MapClaims{})
+		assert.Nil(t, err)
+
+		keySet, err := config.Auth.JWT.GetJSONWebKeySet()
+		assert.Nil(t, err)
+
+		// act
+		_, err = jwtgo.Parse(tokenString, func(token *jwtgo.Token) (interface{}, error) {
+			return parseJSONWebKey(keySet.Keys[0])
+		})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestJWTConfigValidate(t *testing.T) {
+	t.Run("ReturnsErrorIfSigningKeyIDIsUnknown", func(t *testing.T) {
+
+		config := &JWTConfig{
+			Domain:       "ci.ziplinee.io",
+			Keys:         []*JWTKeyConfig{{ID: "2024-01", Key: "old secret"}},
+			SigningKeyID: "2024-06",
+		}
+		config.SetDefaults()
+
+		// act
+		err := config.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForDuplicateKeyIDs", func(t *testing.T) {
+
+		config := &JWTConfig{
+			Domain: "ci.ziplinee.io",
+			Keys:   []*JWTKeyConfig{{ID: "2024-01", Key: "old secret"}, {ID: "2024-01", Key: "new secret"}},
+		}
+		config.SetDefaults()
+
+		// act
+		err := config.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsErrorForInvalidPrivateKey", func(t *testing.T) {
+
+		config := &JWTConfig{
+			Domain: "ci.ziplinee.io",
+			Keys:   []*JWTKeyConfig{{ID: "rsa", Algorithm: JWTAlgorithmRS256, PrivateKey: "not a pem"}},
+		}
+		config.SetDefaults()
+
+		// act
+		err := config.Validate()
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("ReturnsNoErrorForKeysWithoutSharedKey", func(t *testing.T) {
+
+		config := &JWTConfig{
+			Domain: "ci.ziplinee.io",
+			Keys:   []*JWTKeyConfig{{ID: "2024-01", Key: "old secret"}},
+		}
+		config.SetDefaults()
+
+		// act
+		err := config.Validate()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-01", config.SigningKeyID)
+	})
+}
+
+func getJWTTestConfig(jwtConfig *JWTConfig) *APIConfig {
+	jwtConfig.SetDefaults()
+
+	return &APIConfig{
+		Auth: &AuthConfig{
+			JWT: jwtConfig,
+		},
+	}
+}
+
+func getRSAPrivateKeyPEM(t *testing.T) string {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+}
+
+func getECPrivateKeyPEM(t *testing.T) string {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKeyBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateKeyBytes}))
+}
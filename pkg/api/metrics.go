@@ -47,3 +47,20 @@ func NewRequestHistogram(subsystem string) metrics.Histogram {
 
 	return requestHistograms[subsystem]
 }
+
+var rejectionCounters map[string]metrics.Counter = map[string]metrics.Counter{}
+
+// NewRejectionCounter counts requests a subsystem rejects, by the reason they got rejected for
+func NewRejectionCounter(subsystem string) metrics.Counter {
+
+	if _, ok := rejectionCounters[subsystem]; !ok {
+		rejectionCounters[subsystem] = kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "api",
+			Subsystem: subsystem,
+			Name:      "rejection_count",
+			Help:      "Number of requests rejected.",
+		}, []string{"reason"})
+	}
+
+	return rejectionCounters[subsystem]
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -8,6 +10,8 @@ import (
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 )
@@ -17,19 +21,30 @@ type Middleware interface {
 	GoogleJWTMiddlewareFunc() gin.HandlerFunc
 	GinJWTMiddleware(authenticator func(c *gin.Context) (interface{}, error)) (middleware *jwt.GinJWTMiddleware, err error)
 	GinJWTMiddlewareForClientLogin(authenticator func(c *gin.Context) (interface{}, error)) (middleware *jwt.GinJWTMiddleware, err error)
+	LoginHandler(middleware *jwt.GinJWTMiddleware) gin.HandlerFunc
+	PersonalAccessTokenMiddlewareFunc(jwtMiddleware *jwt.GinJWTMiddleware, authenticator func(ctx context.Context, token string) (*contracts.User, *PersonalAccessToken, error)) gin.HandlerFunc
 }
 
-// NewAuthMiddleware returns a new api.AuthMiddleware
-func NewAuthMiddleware(config *APIConfig) (authMiddleware Middleware) {
-	authMiddleware = &authMiddlewareImpl{
-		config: config,
+// jwtTimeout is how long a jwt issued at login is valid for, unless its session gets revoked
+const jwtTimeout = time.Duration(8) * time.Hour
+
+// NewAuthMiddleware returns a new api.AuthMiddleware; sessions are tracked and can be revoked if a session store is passed
+func NewAuthMiddleware(config *APIConfig, sessionStore SessionStore) (authMiddleware Middleware) {
+	impl := &authMiddlewareImpl{
+		config:       config,
+		sessionStore: sessionStore,
+	}
+	if sessionStore != nil {
+		impl.revocationCache = newSessionRevocationCache(sessionStore)
 	}
 
-	return
+	return impl
 }
 
 type authMiddlewareImpl struct {
-	config *APIConfig
+	config          *APIConfig
+	sessionStore    SessionStore
+	revocationCache *sessionRevocationCache
 }
 
 func (m *authMiddlewareImpl) GoogleJWTMiddlewareFunc() gin.HandlerFunc {
@@ -63,13 +78,131 @@ func (m *authMiddlewareImpl) coreGinJWTMiddleware(authenticator func(c *gin.Cont
 	return jwt.New(&jwt.GinJWTMiddleware{
 		Realm:         m.config.Auth.JWT.Domain,
 		Key:           []byte(m.config.Auth.JWT.Key),
+		KeyFunc:       m.config.Auth.JWT.getVerificationKey,
 		TokenLookup:   "header:Authorization, cookie:jwt",
 		Authenticator: authenticator,
-		Timeout:       time.Duration(8) * time.Hour,
+		Authorizator:  m.authorizeSession,
+		Timeout:       jwtTimeout,
 		TimeFunc:      time.Now,
 	})
 }
 
+// LoginHandler replaces the gin-jwt login handler, to sign jwts with the configured signing key and its key id
+func (m *authMiddlewareImpl) LoginHandler(middleware *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		unauthorized := func(code int, err error) {
+			c.Header("WWW-Authenticate", "JWT realm="+middleware.Realm)
+			c.Abort()
+			middleware.Unauthorized(c, code, middleware.HTTPStatusMessageFunc(err, c))
+		}
+
+		data, err := middleware.Authenticator(c)
+		if err != nil {
+			unauthorized(http.StatusUnauthorized, err)
+			return
+		}
+
+		claims := jwtgo.MapClaims{}
+		if middleware.PayloadFunc != nil {
+			for key, value := range middleware.PayloadFunc(data) {
+				claims[key] = value
+			}
+		}
+
+		now := middleware.TimeFunc()
+		expire := now.Add(middleware.Timeout)
+
+		token, err := GenerateJWT(m.config, now, expire, claims)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed signing jwt at login")
+			unauthorized(http.StatusUnauthorized, jwt.ErrFailedTokenCreation)
+			return
+		}
+
+		if middleware.SendCookie {
+			if middleware.CookieSameSite != 0 {
+				c.SetSameSite(middleware.CookieSameSite)
+			}
+			c.SetCookie(middleware.CookieName, token, int(middleware.CookieMaxAge.Seconds()), "/", middleware.CookieDomain, middleware.SecureCookie, middleware.CookieHTTPOnly)
+		}
+
+		middleware.LoginResponse(c, http.StatusOK, token, expire)
+	}
+}
+
+// authorizeSession rejects jwts of revoked sessions; jwts issued before sessions were tracked have no id and remain valid until they expire
+func (m *authMiddlewareImpl) authorizeSession(data interface{}, c *gin.Context) bool {
+	if m.revocationCache == nil {
+		return true
+	}
+
+	sessionID := GetSessionIDFromRequest(c)
+	if sessionID == "" {
+		return true
+	}
+
+	return !m.revocationCache.IsRevoked(c.Request.Context(), sessionID)
+}
+
+// createSession records the session of a jwt issued at login; failing to do so doesn't fail the login, the session just can't be listed
+func (m *authMiddlewareImpl) createSession(c *gin.Context, middleware *jwt.GinJWTMiddleware, token string, expire time.Time) {
+	if m.sessionStore == nil {
+		return
+	}
+
+	parsedToken, err := middleware.ParseTokenString(token)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed parsing issued jwt for recording its session")
+		return
+	}
+
+	claims := jwt.ExtractClaimsFromToken(parsedToken)
+	session := Session{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+		IssuedAt:  time.Now().UTC(),
+		ExpiresAt: expire.UTC(),
+	}
+	session.ID, _ = claims["jti"].(string)
+	session.SubjectID, _ = claims[jwt.IdentityKey].(string)
+	session.Email, _ = claims["email"].(string)
+	session.ClientID, _ = claims["clientID"].(string)
+	session.Impersonator, _ = claims["impersonator"].(string)
+
+	if session.ID == "" || session.SubjectID == "" {
+		return
+	}
+
+	err = m.sessionStore.CreateSession(c.Request.Context(), session)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed recording session %v for %v", session.ID, session.SubjectID)
+	}
+}
+
+// revokeSession revokes the session of the jwt of the request, for logging out
+func (m *authMiddlewareImpl) revokeSession(c *gin.Context, middleware *jwt.GinJWTMiddleware) {
+	if m.sessionStore == nil {
+		return
+	}
+
+	claims, err := middleware.GetClaimsFromJWT(c)
+	if err != nil {
+		return
+	}
+	sessionID, _ := claims["jti"].(string)
+	if sessionID == "" {
+		return
+	}
+
+	err = m.sessionStore.RevokeSession(c.Request.Context(), sessionID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed revoking session %v at logout", sessionID)
+		return
+	}
+	m.revocationCache.MarkRevoked(sessionID)
+}
+
 func (m *authMiddlewareImpl) GinJWTMiddleware(authenticator func(c *gin.Context) (interface{}, error)) (middleware *jwt.GinJWTMiddleware, err error) {
 	middleware, err = m.coreGinJWTMiddleware(authenticator)
 	if err != nil {
@@ -84,6 +217,8 @@ func (m *authMiddlewareImpl) GinJWTMiddleware(authenticator func(c *gin.Context)
 
 	// redirect after login
 	middleware.LoginResponse = func(c *gin.Context, code int, token string, expire time.Time) {
+		m.createSession(c, middleware, token, expire)
+
 		// see if gin context has a return url
 		returnURL, exists := c.Get("returnURL")
 		if exists {
@@ -97,31 +232,25 @@ func (m *authMiddlewareImpl) GinJWTMiddleware(authenticator func(c *gin.Context)
 
 	// redirect after logout
 	middleware.LogoutResponse = func(c *gin.Context, code int) {
+		m.revokeSession(c, middleware)
 		c.Redirect(http.StatusFound, "/login")
 	}
 
 	// set some user properties as claims
 	middleware.PayloadFunc = func(data interface{}) jwt.MapClaims {
+		// add impersonated user properties as claims, with the impersonator to be able to trace back its actions
+		if impersonation, ok := data.(*Impersonation); ok && impersonation.User != nil {
+			claims := getUserClaims(impersonation.User)
+			claims["impersonator"] = impersonation.Impersonator
+			claims["jti"] = uuid.New().String()
+			return claims
+		}
+
 		// add user properties as claims
 		if user, ok := data.(*contracts.User); ok {
-
-			organizations := []string{}
-			for _, o := range user.Organizations {
-				organizations = append(organizations, o.Name)
-			}
-
-			groups := []string{}
-			for _, g := range user.Groups {
-				groups = append(groups, g.Name)
-			}
-
-			return jwt.MapClaims{
-				jwt.IdentityKey: user.ID,
-				"email":         user.GetEmail(),
-				"roles":         user.Roles,
-				"groups":        groups,
-				"organizations": organizations,
-			}
+			claims := getUserClaims(user)
+			claims["jti"] = uuid.New().String()
+			return claims
 		}
 		return jwt.MapClaims{}
 	}
@@ -129,6 +258,27 @@ func (m *authMiddlewareImpl) GinJWTMiddleware(authenticator func(c *gin.Context)
 	return middleware, nil
 }
 
+func getUserClaims(user *contracts.User) jwt.MapClaims {
+
+	organizations := []string{}
+	for _, o := range user.Organizations {
+		organizations = append(organizations, o.Name)
+	}
+
+	groups := []string{}
+	for _, g := range user.Groups {
+		groups = append(groups, g.Name)
+	}
+
+	return jwt.MapClaims{
+		jwt.IdentityKey: user.ID,
+		"email":         user.GetEmail(),
+		"roles":         user.Roles,
+		"groups":        groups,
+		"organizations": organizations,
+	}
+}
+
 func (m *authMiddlewareImpl) GinJWTMiddlewareForClientLogin(authenticator func(c *gin.Context) (interface{}, error)) (middleware *jwt.GinJWTMiddleware, err error) {
 	middleware, err = m.coreGinJWTMiddleware(authenticator)
 	if err != nil {
@@ -140,22 +290,111 @@ func (m *authMiddlewareImpl) GinJWTMiddlewareForClientLogin(authenticator func(c
 
 		// add client properties as claims
 		if client, ok := data.(*contracts.Client); ok {
-
-			organizations := []string{}
-			for _, o := range client.Organizations {
-				organizations = append(organizations, o.Name)
-			}
-
-			return jwt.MapClaims{
-				jwt.IdentityKey: client.ID,
-				"email":         fmt.Sprintf("%v@client.ziplinee.io", client.Name),
-				"clientID":      client.ClientID,
-				"roles":         client.Roles,
-				"organizations": organizations,
-			}
+			claims := getClientClaims(client)
+			claims["jti"] = uuid.New().String()
+			return claims
 		}
 		return jwt.MapClaims{}
 	}
 
+	// return the token like gin-jwt does by default, after recording its session
+	middleware.LoginResponse = func(c *gin.Context, code int, token string, expire time.Time) {
+		m.createSession(c, middleware, token, expire)
+
+		c.JSON(http.StatusOK, gin.H{
+			"code":   http.StatusOK,
+			"token":  token,
+			"expire": expire.Format(time.RFC3339),
+		})
+	}
+
 	return middleware, nil
 }
+
+func getClientClaims(client *contracts.Client) jwt.MapClaims {
+
+	organizations := []string{}
+	for _, o := range client.Organizations {
+		organizations = append(organizations, o.Name)
+	}
+
+	return jwt.MapClaims{
+		jwt.IdentityKey: client.ID,
+		"email":         fmt.Sprintf("%v@client.ziplinee.io", client.Name),
+		"clientID":      client.ClientID,
+		"roles":         client.Roles,
+		"organizations": organizations,
+	}
+}
+
+// PersonalAccessTokenMiddlewareFunc authenticates requests with a personal access token as bearer token and leaves all
+// other requests to the jwt middleware; the claims are those of the token's user, limited to the token's scopes
+func (m *authMiddlewareImpl) PersonalAccessTokenMiddlewareFunc(jwtMiddleware *jwt.GinJWTMiddleware, authenticator func(ctx context.Context, token string) (*contracts.User, *PersonalAccessToken, error)) gin.HandlerFunc {
+
+	jwtMiddlewareFunc := jwtMiddleware.MiddlewareFunc()
+
+	return func(c *gin.Context) {
+
+		authorizationHeader := c.Request.Header.Get("Authorization")
+
+		if !strings.HasPrefix(authorizationHeader, "Bearer "+PersonalAccessTokenPrefix) {
+			jwtMiddlewareFunc(c)
+			return
+		}
+
+		user, token, err := authenticator(c.Request.Context(), strings.TrimPrefix(authorizationHeader, "Bearer "))
+		if err != nil || user == nil || token == nil {
+			log.Warn().Err(err).Msg("Authenticating personal access token failed")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": http.StatusText(http.StatusUnauthorized), "message": "Personal access token is invalid"})
+			return
+		}
+
+		claims, err := getPersonalAccessTokenClaims(user, token)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed setting claims for personal access token %v", token.ID)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+
+		c.Set("JWT_PAYLOAD", claims)
+		c.Set(jwtMiddleware.IdentityKey, claims[jwt.IdentityKey])
+		c.Next()
+	}
+}
+
+// getPersonalAccessTokenClaims returns the user's claims with the token's id and scopes, as they would be read from a jwt
+func getPersonalAccessTokenClaims(user *contracts.User, token *PersonalAccessToken) (claims jwt.MapClaims, err error) {
+
+	userClaims := getUserClaims(user)
+	userClaims["tokenID"] = token.ID
+	userClaims["scopes"] = token.Scopes
+
+	return toDecodedClaims(userClaims)
+}
+
+// SetUserClaims sets the claims of a user on a request that isn't authenticated with a jwt, like a command from Slack, so the
+// same permission checks apply as if the user made the request through the api
+func SetUserClaims(c *gin.Context, user *contracts.User) (err error) {
+
+	claims, err := toDecodedClaims(getUserClaims(user))
+	if err != nil {
+		return
+	}
+
+	c.Set("JWT_PAYLOAD", claims)
+	c.Set(jwt.IdentityKey, claims[jwt.IdentityKey])
+
+	return nil
+}
+
+// toDecodedClaims round trips claims through json so they have the same types as those decoded from a jwt
+func toDecodedClaims(claims jwt.MapClaims) (decodedClaims jwt.MapClaims, err error) {
+
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(claimsBytes, &decodedClaims)
+
+	return
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+const (
+	// OAuthGrantTypeClientCredentials is the only grant the oauth2 token endpoint supports, for clients to authenticate as themselves
+	OAuthGrantTypeClientCredentials = "client_credentials"
+
+	// OAuthTokenTypeBearer is the type of all access tokens issued by the oauth2 token endpoint
+	OAuthTokenTypeBearer = "Bearer"
+
+	// ClientAccessTokenLifetime is how long an access token issued to a client is valid for, unless its session gets revoked
+	ClientAccessTokenLifetime = time.Hour
+)
+
+// error codes of the oauth2 token and introspection endpoints, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
+const (
+	OAuthErrorInvalidRequest       = "invalid_request"
+	OAuthErrorInvalidClient        = "invalid_client"
+	OAuthErrorUnsupportedGrantType = "unsupported_grant_type"
+	OAuthErrorInvalidScope         = "invalid_scope"
+	OAuthErrorServerError          = "server_error"
+)
+
+// ErrInvalidOAuthScope indicates a client requested a scope it doesn't have the permission for
+var ErrInvalidOAuthScope = errors.New("invalid scope")
+
+// OAuthTokenResponse is returned by the oauth2 token endpoint, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.1
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// OAuthErrorResponse is returned by the oauth2 endpoints if a request fails, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuthIntrospectionResponse is returned by the token introspection endpoint, see https://datatracker.ietf.org/doc/html/rfc7662#section-2.2;
+// inactive tokens don't reveal anything else about themselves
+type OAuthIntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	JWTID     string `json:"jti,omitempty"`
+}
+
+// GetOAuthScopes returns the scopes to grant for the space-separated scope parameter of a token request; without requested
+// scopes the token isn't scoped and has all of the client's permissions
+func GetOAuthScopes(scope string, permissions []string) (scopes []string, err error) {
+
+	for _, s := range strings.Fields(scope) {
+		if !StringArrayContains(permissions, s) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOAuthScope, s)
+		}
+		if !StringArrayContains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	return scopes, nil
+}
+
+// GenerateClientAccessToken returns an access token for a client authenticated with the client credentials grant, with the
+// session to record for it
+func GenerateClientAccessToken(config *APIConfig, now time.Time, client *contracts.Client, scopes []string) (token string, session Session, err error) {
+
+	claims := getClientClaims(client)
+	claims["jti"] = uuid.New().String()
+	if len(scopes) > 0 {
+		claims["scopes"] = scopes
+	}
+
+	expiry := now.Add(ClientAccessTokenLifetime)
+
+	token, err = GenerateJWT(config, now, expiry, jwtgo.MapClaims(claims))
+	if err != nil {
+		return
+	}
+
+	session = Session{
+		ID:        claims["jti"].(string),
+		SubjectID: client.ID,
+		Email:     claims["email"].(string),
+		ClientID:  client.ClientID,
+		IssuedAt:  now.UTC(),
+		ExpiresAt: expiry.UTC(),
+	}
+
+	return
+}
+
+// NewOAuthIntrospectionResponse returns the introspection response for the claims of a valid jwt
+func NewOAuthIntrospectionResponse(claims jwtgo.MapClaims) (response OAuthIntrospectionResponse) {
+
+	response.Active = true
+	response.TokenType = OAuthTokenTypeBearer
+	response.ClientID, _ = claims["clientID"].(string)
+	response.JWTID, _ = claims["jti"].(string)
+	response.Subject, _ = claims[jwt.IdentityKey].(string)
+
+	if exp, ok := claims["exp"].(float64); ok {
+		response.ExpiresAt = int64(exp)
+	}
+	if iat, ok := claims["orig_iat"].(float64); ok {
+		response.IssuedAt = int64(iat)
+	}
+
+	// the scope is what the token can be used for, either its scopes or all permissions of its roles
+	if scopes, ok := claims["scopes"].([]interface{}); ok {
+		response.Scope = strings.Join(interfaceArrayToStrings(scopes), " ")
+	} else if roles, ok := claims["roles"].([]interface{}); ok {
+		roleNames := []*string{}
+		for _, r := range interfaceArrayToStrings(roles) {
+			roleName := r
+			roleNames = append(roleNames, &roleName)
+		}
+		response.Scope = strings.Join(GetPermissionsForRoles(roleNames), " ")
+	}
+
+	return
+}
+
+func interfaceArrayToStrings(values []interface{}) (strs []string) {
+	strs = []string{}
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return
+}
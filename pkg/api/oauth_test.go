@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+func TestGetOAuthScopes(t *testing.T) {
+	t.Run("ReturnsNoScopesIfNoneAreRequested", func(t *testing.T) {
+
+		// act
+		scopes, err := GetOAuthScopes("", []string{"ci.pipelines.list"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(scopes))
+	})
+
+	t.Run("ReturnsRequestedScopesWithoutDuplicates", func(t *testing.T) {
+
+		// act
+		scopes, err := GetOAuthScopes("ci.pipelines.list  ci.builds.list ci.pipelines.list", []string{"ci.pipelines.list", "ci.builds.list", "ci.builds.create"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ci.pipelines.list", "ci.builds.list"}, scopes)
+	})
+
+	t.Run("ReturnsErrInvalidOAuthScopeForScopeClientDoesNotHavePermissionFor", func(t *testing.T) {
+
+		// act
+		_, err := GetOAuthScopes("ci.pipelines.list ci.builds.create", []string{"ci.pipelines.list"})
+
+		assert.True(t, errors.Is(err, ErrInvalidOAuthScope))
+	})
+}
+
+func TestGenerateClientAccessToken(t *testing.T) {
+	t.Run("ReturnsScopedTokenThatIntrospectsAsActive", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{Key: "this is my secret"})
+		role := RoleClientViewer.String()
+		client := &contracts.Client{ID: "25", Name: "cron", ClientID: "client-id", Roles: []*string{&role}}
+		now := time.Now()
+
+		// act
+		token, session, err := GenerateClientAccessToken(config, now, client, []string{PermissionClientsList.String()})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "25", session.SubjectID)
+		assert.Equal(t, "client-id", session.ClientID)
+		claims, err := GetClaimsFromJWT(config, token)
+		if assert.Nil(t, err) {
+			response := NewOAuthIntrospectionResponse(claims)
+			assert.True(t, response.Active)
+			assert.Equal(t, PermissionClientsList.String(), response.Scope)
+			assert.Equal(t, "client-id", response.ClientID)
+			assert.Equal(t, "25", response.Subject)
+			assert.Equal(t, session.ID, response.JWTID)
+			assert.Equal(t, now.Add(ClientAccessTokenLifetime).Unix(), response.ExpiresAt)
+		}
+	})
+
+	t.Run("ReturnsUnscopedTokenWithAllPermissionsOfClientRolesAsScope", func(t *testing.T) {
+
+		config := getJWTTestConfig(&JWTConfig{Key: "this is my secret"})
+		role := RoleClientViewer.String()
+		client := &contracts.Client{ID: "25", Name: "cron", ClientID: "client-id", Roles: []*string{&role}}
+
+		// act
+		token, _, err := GenerateClientAccessToken(config, time.Now(), client, nil)
+
+		assert.Nil(t, err)
+		claims, err := GetClaimsFromJWT(config, token)
+		if assert.Nil(t, err) {
+			response := NewOAuthIntrospectionResponse(claims)
+			assert.Equal(t, PermissionClientsList.String()+" "+PermissionClientsGet.String(), response.Scope)
+		}
+	})
+}
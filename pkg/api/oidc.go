@@ -0,0 +1,365 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/sethgrid/pester"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// oidcDiscoveryCacheDuration is how long discovery documents are used before fetching them again
+	oidcDiscoveryCacheDuration = time.Hour
+	// oidcKeysCacheDuration is how long json web key sets are used before fetching them again
+	oidcKeysCacheDuration = 24 * time.Hour
+	// oidcKeysMinimumRefreshInterval limits how often an unknown kid triggers fetching the key set, to pick up rotated keys without hammering the provider
+	oidcKeysMinimumRefreshInterval = time.Minute
+)
+
+var (
+	// ErrOIDCIDTokenMissing indicates the token response of an oidc provider has no id_token
+	ErrOIDCIDTokenMissing = errors.New("token response has no id_token")
+
+	// ErrOIDCEmailNotVerified indicates the oidc provider doesn't vouch for the email address of the user
+	ErrOIDCEmailNotVerified = errors.New("email address is not verified")
+
+	oidcValidSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+	oidcCacheMutex     sync.Mutex
+	oidcDiscoveryCache = map[string]*cachedOIDCDiscovery{}
+	oidcKeysCache      = map[string]*cachedOIDCKeys{}
+)
+
+type cachedOIDCDiscovery struct {
+	document    *OIDCDiscoveryDocument
+	lastFetched time.Time
+}
+
+type cachedOIDCKeys struct {
+	keys        map[string]interface{}
+	lastFetched time.Time
+}
+
+// getCachedOIDCDiscovery returns the discovery document of an oidc issuer from cache or fetches it from source
+func getCachedOIDCDiscovery(issuerURL string) (document *OIDCDiscoveryDocument, err error) {
+
+	oidcCacheMutex.Lock()
+	defer oidcCacheMutex.Unlock()
+
+	if cached, ok := oidcDiscoveryCache[issuerURL]; ok && cached.lastFetched.Add(oidcDiscoveryCacheDuration).After(time.Now().UTC()) {
+		return cached.document, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+
+	err = getOIDCJSON(discoveryURL, "", &document)
+	if err != nil {
+		return nil, err
+	}
+
+	// the issuer in the document has to match the configured one, otherwise tokens could be accepted from another issuer
+	if strings.TrimSuffix(document.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return nil, fmt.Errorf("Issuer %v in discovery document at %v does not match issuer url %v", document.Issuer, discoveryURL, issuerURL)
+	}
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return nil, fmt.Errorf("Discovery document at %v is missing authorization_endpoint, token_endpoint or jwks_uri", discoveryURL)
+	}
+
+	oidcDiscoveryCache[issuerURL] = &cachedOIDCDiscovery{
+		document:    document,
+		lastFetched: time.Now().UTC(),
+	}
+
+	return document, nil
+}
+
+// getCachedOIDCKey returns a public key from the key set of an oidc issuer from cache or fetches it from source
+func getCachedOIDCKey(jwksURI, kid string) (key interface{}, err error) {
+
+	oidcCacheMutex.Lock()
+	defer oidcCacheMutex.Unlock()
+
+	now := time.Now().UTC()
+	cached, ok := oidcKeysCache[jwksURI]
+
+	if ok && cached.lastFetched.Add(oidcKeysCacheDuration).After(now) {
+		if key, found := cached.getKey(kid); found {
+			return key, nil
+		}
+		if cached.lastFetched.Add(oidcKeysMinimumRefreshInterval).After(now) {
+			return nil, fmt.Errorf("Key with kid %v does not exist at %v", kid, jwksURI)
+		}
+	}
+
+	var keySet JSONWebKeySet
+	err = getOIDCJSON(jwksURI, "", &keySet)
+	if err != nil {
+		return nil, err
+	}
+
+	cached = &cachedOIDCKeys{
+		keys:        map[string]interface{}{},
+		lastFetched: now,
+	}
+	for _, k := range keySet.Keys {
+		if k.PublicKeyUse != "" && k.PublicKeyUse != "sig" {
+			continue
+		}
+		publicKey, err := parseJSONWebKey(k)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Skipping key with kid %v at %v", k.KeyID, jwksURI)
+			continue
+		}
+		cached.keys[k.KeyID] = publicKey
+	}
+	oidcKeysCache[jwksURI] = cached
+
+	if key, found := cached.getKey(kid); found {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("Key with kid %v does not exist at %v", kid, jwksURI)
+}
+
+// getKey returns the key for a kid; tokens without kid can only be verified if the key set has a single key
+func (c *cachedOIDCKeys) getKey(kid string) (key interface{}, found bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, k := range c.keys {
+			return k, true
+		}
+	}
+	key, found = c.keys[kid]
+	return
+}
+
+// parseJSONWebKey converts a json web key into an *rsa.PublicKey or *ecdsa.PublicKey
+func parseJSONWebKey(key JSONWebKey) (publicKey interface{}, err error) {
+	switch key.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("JWK key exponent %v can't be converted to int", key.E)
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch key.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("JWK curve %v is not supported", key.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(key.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("JWK key type %v is not supported", key.KeyType)
+}
+
+// getOIDCJSON retrieves and unmarshals a json document, optionally authorized with a bearer token
+func getOIDCJSON(url, bearerToken string, v interface{}) (err error) {
+
+	client := pester.NewExtendedClient(&http.Client{})
+	client.MaxRetries = 3
+	client.Backoff = pester.ExponentialJitterBackoff
+	client.Timeout = time.Second * 10
+
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return
+	}
+	request.Header.Add("Accept", "application/json")
+	if bearerToken != "" {
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %v", bearerToken))
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v responded with status code %v", url, response.StatusCode)
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// getOIDCClaims verifies the id token returned alongside the access token, including the nonce sent when the login started, and returns its
+// claims, complemented with the claims from the userinfo endpoint
+func (p *OAuthProvider) getOIDCClaims(ctx context.Context, token *oauth2.Token, nonce string) (claims jwtgo.MapClaims, err error) {
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrOIDCIDTokenMissing
+	}
+
+	discovery, err := getCachedOIDCDiscovery(p.OIDC.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := jwtgo.Parse(rawIDToken, func(t *jwtgo.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return getCachedOIDCKey(discovery.JWKSURI, kid)
+	}, jwtgo.WithValidMethods(oidcValidSigningMethods))
+	if err != nil {
+		return nil, fmt.Errorf("Failed verifying id token from oidc provider %v: %w", p.Name, err)
+	}
+
+	claims, ok = idToken.Claims.(jwtgo.MapClaims)
+	if !ok || !idToken.Valid {
+		return nil, fmt.Errorf("Id token from oidc provider %v is invalid", p.Name)
+	}
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, fmt.Errorf("Id token from oidc provider %v has issuer %v instead of %v", p.Name, claims["iss"], discovery.Issuer)
+	}
+	if !claims.VerifyAudience(p.ClientID, true) {
+		return nil, fmt.Errorf("Id token from oidc provider %v is not issued for client id %v", p.Name, p.ClientID)
+	}
+	if !claims.VerifyExpiresAt(time.Now().UTC().Unix(), true) {
+		return nil, fmt.Errorf("Id token from oidc provider %v is expired or has no expiry", p.Name)
+	}
+	if getOIDCStringClaim(claims, "sub") == "" {
+		return nil, fmt.Errorf("Id token from oidc provider %v has no subject", p.Name)
+	}
+
+	// the nonce ties the id token to the login started from this browser, so a token issued for another login can't be replayed
+	tokenNonce := getOIDCStringClaim(claims, "nonce")
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("Id token from oidc provider %v has no nonce or a nonce that doesn't match the login", p.Name)
+	}
+
+	// some providers, like okta and azure ad, leave profile claims out of the id token by default and only return them from the userinfo endpoint
+	if discovery.UserinfoEndpoint != "" && token.AccessToken != "" && (getOIDCClaim(claims, p.OIDC.EmailClaim) == nil || getOIDCClaim(claims, "email_verified") == nil || getOIDCClaim(claims, p.OIDC.NameClaim) == nil || getOIDCClaim(claims, p.OIDC.GroupsClaim) == nil) {
+		userInfo := map[string]interface{}{}
+		err = getOIDCJSON(discovery.UserinfoEndpoint, token.AccessToken, &userInfo)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed retrieving userinfo from oidc provider %v, continuing with id token claims", p.Name)
+			return claims, nil
+		}
+
+		// userinfo claims belong to another user if the subjects differ
+		if sub, _ := userInfo["sub"].(string); sub != getOIDCStringClaim(claims, "sub") {
+			log.Warn().Msgf("Subject of userinfo from oidc provider %v does not match id token subject, ignoring userinfo", p.Name)
+			return claims, nil
+		}
+		for key, value := range userInfo {
+			if _, ok := claims[key]; !ok {
+				claims[key] = value
+			}
+		}
+	}
+
+	return claims, nil
+}
+
+// getOIDCClaim returns a claim by name; names that don't exist as top level claim are looked up as dot separated path, like realm_access.roles
+func getOIDCClaim(claims map[string]interface{}, name string) interface{} {
+	if name == "" {
+		return nil
+	}
+	if value, ok := claims[name]; ok {
+		return value
+	}
+
+	var value interface{} = claims
+	for _, part := range strings.Split(name, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value, ok = object[part]
+		if !ok {
+			return nil
+		}
+	}
+
+	return value
+}
+
+// isOIDCEmailVerified returns true if the provider vouches for the email address; some providers send email_verified as string
+func isOIDCEmailVerified(claims map[string]interface{}) bool {
+	switch value := getOIDCClaim(claims, "email_verified").(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+func getOIDCStringClaim(claims map[string]interface{}, name string) string {
+	if value, ok := getOIDCClaim(claims, name).(string); ok {
+		return value
+	}
+	return ""
+}
+
+func getOIDCStringArrayClaim(claims map[string]interface{}, name string) []string {
+	values := []string{}
+	switch value := getOIDCClaim(claims, name).(type) {
+	case string:
+		if value != "" {
+			values = append(values, value)
+		}
+	case []interface{}:
+		for _, v := range value {
+			if s, ok := v.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,385 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+// stubOIDCIssuer serves a discovery document, key set and userinfo endpoint like a real oidc provider
+type stubOIDCIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	userInfo map[string]interface{}
+}
+
+func newStubOIDCIssuer(t *testing.T) *stubOIDCIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &stubOIDCIssuer{
+		key:      key,
+		userInfo: map[string]interface{}{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(OIDCDiscoveryDocument{
+			Issuer:                issuer.server.URL,
+			AuthorizationEndpoint: issuer.server.URL + "/authorize",
+			TokenEndpoint:         issuer.server.URL + "/token",
+			UserinfoEndpoint:      issuer.server.URL + "/userinfo",
+			JWKSURI:               issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(JSONWebKeySet{
+			Keys: []JSONWebKey{
+				{
+					KeyType:      "RSA",
+					Algorithm:    "RS256",
+					PublicKeyUse: "sig",
+					KeyID:        "stub-key",
+					N:            base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+					E:            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(issuer.userInfo)
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *stubOIDCIssuer) provider() *OAuthProvider {
+	provider := &OAuthProvider{
+		Name:         "keycloak",
+		Type:         OAuthProviderTypeOIDC,
+		ClientID:     "ziplinee-ci",
+		ClientSecret: "secret",
+		OIDC: &OIDCConfig{
+			IssuerURL:   i.server.URL,
+			GroupsClaim: "realm_access.roles",
+		},
+	}
+	provider.SetDefaults()
+	return provider
+}
+
+func (i *stubOIDCIssuer) token(t *testing.T, claims jwtgo.MapClaims) *oauth2.Token {
+	idToken := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, claims)
+	idToken.Header["kid"] = "stub-key"
+	rawIDToken, err := idToken.SignedString(i.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := &oauth2.Token{AccessToken: "access-token"}
+	return token.WithExtra(map[string]interface{}{"id_token": rawIDToken})
+}
+
+func (i *stubOIDCIssuer) claims() jwtgo.MapClaims {
+	return jwtgo.MapClaims{
+		"iss":            i.server.URL,
+		"aud":            "ziplinee-ci",
+		"sub":            "f7a3c1",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"nonce":          "f3c9e1d2",
+		"email":          "jane@ziplinee.io",
+		"email_verified": true,
+		"name":           "Jane Doe",
+		"realm_access": map[string]interface{}{
+			"roles": []string{"ci-admins", "developers"},
+		},
+	}
+}
+
+func TestOIDCProvider(t *testing.T) {
+
+	t.Run("GetConfigReturnsEndpointsFromDiscoveryDocument", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+
+		// act
+		config := provider.GetConfig("https://ci.ziplinee.io/")
+
+		if assert.NotNil(t, config) {
+			assert.Equal(t, issuer.server.URL+"/authorize", config.Endpoint.AuthURL)
+			assert.Equal(t, issuer.server.URL+"/token", config.Endpoint.TokenURL)
+			assert.Equal(t, []string{"openid", "profile", "email"}, config.Scopes)
+			assert.Equal(t, "https://ci.ziplinee.io/api/auth/handle/keycloak", config.RedirectURL)
+		}
+	})
+
+	t.Run("GetConfigReturnsNilIfDiscoveryFails", func(t *testing.T) {
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		provider := &OAuthProvider{
+			Name:         "keycloak",
+			Type:         OAuthProviderTypeOIDC,
+			ClientID:     "ziplinee-ci",
+			ClientSecret: "secret",
+			OIDC:         &OIDCConfig{IssuerURL: server.URL},
+		}
+
+		// act
+		config := provider.GetConfig("https://ci.ziplinee.io/")
+
+		assert.Nil(t, config)
+		assert.Equal(t, "", provider.AuthCodeURL("https://ci.ziplinee.io/", "state", "f3c9e1d2"))
+	})
+
+	t.Run("AuthCodeURLSendsNonce", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+
+		// act
+		authCodeURL := provider.AuthCodeURL("https://ci.ziplinee.io/", "state", "f3c9e1d2")
+
+		assert.Contains(t, authCodeURL, "nonce=f3c9e1d2")
+		assert.Contains(t, authCodeURL, "state=state")
+	})
+
+	t.Run("GetUserIdentityMapsVerifiedIDTokenClaims", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+
+		// act
+		identity, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, issuer.claims()), "f3c9e1d2")
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, identity) {
+			assert.Equal(t, "keycloak", identity.Provider)
+			assert.Equal(t, "f7a3c1", identity.ID)
+			assert.Equal(t, "jane@ziplinee.io", identity.Email)
+			assert.Equal(t, "Jane Doe", identity.Name)
+		}
+	})
+
+	t.Run("GetUserIdentityUsesConfiguredClaims", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		provider.OIDC.EmailClaim = "upn"
+		provider.OIDC.NameClaim = "preferred_username"
+		claims := issuer.claims()
+		claims["upn"] = "jdoe@corp.ziplinee.io"
+		claims["preferred_username"] = "jdoe"
+
+		// act
+		identity, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, identity) {
+			assert.Equal(t, "jdoe@corp.ziplinee.io", identity.Email)
+			assert.Equal(t, "jdoe", identity.Name)
+		}
+	})
+
+	t.Run("GetUserIdentityFillsMissingClaimsFromUserinfo", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		issuer.userInfo = map[string]interface{}{
+			"sub":   "f7a3c1",
+			"email": "jane@ziplinee.io",
+			"name":  "Jane Doe",
+		}
+		provider := issuer.provider()
+		claims := issuer.claims()
+		delete(claims, "email")
+		delete(claims, "name")
+
+		// act
+		identity, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, identity) {
+			assert.Equal(t, "jane@ziplinee.io", identity.Email)
+			assert.Equal(t, "Jane Doe", identity.Name)
+		}
+	})
+
+	t.Run("GetUserIdentityIgnoresUserinfoForOtherSubject", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		issuer.userInfo = map[string]interface{}{
+			"sub":   "someone-else",
+			"email": "mallory@ziplinee.io",
+		}
+		provider := issuer.provider()
+		claims := issuer.claims()
+		delete(claims, "email")
+
+		// act
+		identity, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, identity) {
+			assert.Equal(t, "", identity.Email)
+		}
+	})
+
+	t.Run("GetUserIdentityReturnsErrorForOtherAudience", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		claims := issuer.claims()
+		claims["aud"] = "another-client"
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("GetUserIdentityReturnsErrorForOtherIssuer", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		claims := issuer.claims()
+		claims["iss"] = "https://evil.ziplinee.io"
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("GetUserIdentityReturnsErrorForExpiredToken", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		claims := issuer.claims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("GetUserIdentityReturnsErrorForTokenSignedWithUnknownKey", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		otherIssuer := newStubOIDCIssuer(t)
+		token := otherIssuer.token(t, issuer.claims())
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), token, "f3c9e1d2")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("GetUserIdentityReturnsErrorForOtherNonce", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, issuer.claims()), "a7b2d4e6")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("GetUserIdentityReturnsErrorForTokenWithoutNonce", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		claims := issuer.claims()
+		delete(claims, "nonce")
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("GetUserIdentityReturnsErrorForUnverifiedEmail", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		claims := issuer.claims()
+		claims["email_verified"] = false
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.ErrorIs(t, err, ErrOIDCEmailNotVerified)
+	})
+
+	t.Run("GetUserIdentityAcceptsEmailVerifiedAsString", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+		claims := issuer.claims()
+		claims["email_verified"] = "true"
+
+		// act
+		identity, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), issuer.token(t, claims), "f3c9e1d2")
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, identity) {
+			assert.Equal(t, "jane@ziplinee.io", identity.Email)
+		}
+	})
+
+	t.Run("GetUserIdentityReturnsErrorWithoutIDToken", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+
+		// act
+		_, err := provider.GetUserIdentity(context.Background(), provider.GetConfig(""), &oauth2.Token{AccessToken: "access-token"}, "f3c9e1d2")
+
+		assert.ErrorIs(t, err, ErrOIDCIDTokenMissing)
+	})
+
+	t.Run("GetUserGroupsReturnsGroupsFromNestedClaim", func(t *testing.T) {
+
+		issuer := newStubOIDCIssuer(t)
+		provider := issuer.provider()
+
+		// act
+		groups, err := provider.GetUserGroups(context.Background(), provider.GetConfig(""), issuer.token(t, issuer.claims()), "f3c9e1d2")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ci-admins", "developers"}, groups)
+	})
+
+	t.Run("GetUserGroupsReturnsNoGroupsForNonOIDCProvider", func(t *testing.T) {
+
+		provider := &OAuthProvider{Name: "github"}
+
+		// act
+		groups, err := provider.GetUserGroups(context.Background(), nil, &oauth2.Token{}, "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(groups))
+	})
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// sessionRevocationCacheTTL is how long a session is known not to be revoked, before checking the session store again;
+	// sessions revoked by other replicas are rejected by this one within this time
+	sessionRevocationCacheTTL = 30 * time.Second
+
+	// sessionRevocationCacheMaxEntries triggers purging entries that are no longer useful
+	sessionRevocationCacheMaxEntries = 10000
+
+	// sessionRevocationCacheMaxStaleness is how long the last known state of a session is used while the session store
+	// can't be reached
+	sessionRevocationCacheMaxStaleness = 10 * time.Minute
+
+	// sessionRevocationStoreOutageGracePeriod is how long sessions without a usable known state are let through once the
+	// session store can't be reached, so a short outage doesn't log out every user
+	sessionRevocationStoreOutageGracePeriod = 5 * time.Minute
+)
+
+// Session is a jwt issued at login, tracked by its id so it can be listed and revoked before it expires
+type Session struct {
+	ID           string     `json:"id"`
+	SubjectID    string     `json:"subjectID"`
+	Email        string     `json:"email,omitempty"`
+	ClientID     string     `json:"clientID,omitempty"`
+	Impersonator string     `json:"impersonator,omitempty"`
+	UserAgent    string     `json:"userAgent,omitempty"`
+	IPAddress    string     `json:"ipAddress,omitempty"`
+	IssuedAt     time.Time  `json:"issuedAt"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+	Current      bool       `json:"current,omitempty"`
+}
+
+// IsActive returns true if the session has neither expired nor been revoked
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && s.ExpiresAt.After(now)
+}
+
+// SessionStore keeps track of sessions for the jwt middleware
+type SessionStore interface {
+	CreateSession(ctx context.Context, session Session) (err error)
+	RevokeSession(ctx context.Context, id string) (err error)
+	IsSessionRevoked(ctx context.Context, id string) (revoked bool, err error)
+}
+
+// sessionRevocationCache saves a round trip to the session store for every request; revoked sessions are cached until
+// their jwt would have expired anyway, others for a short time only
+type sessionRevocationCache struct {
+	store        SessionStore
+	ttl          time.Duration
+	maxStaleness time.Duration
+	gracePeriod  time.Duration
+	maxEntries   int
+	timeFunc     func() time.Time
+
+	mu           sync.RWMutex
+	entries      map[string]sessionRevocationCacheEntry
+	failingSince time.Time
+}
+
+type sessionRevocationCacheEntry struct {
+	revoked   bool
+	checkedAt time.Time
+}
+
+func newSessionRevocationCache(store SessionStore) *sessionRevocationCache {
+	return &sessionRevocationCache{
+		store:        store,
+		ttl:          sessionRevocationCacheTTL,
+		maxStaleness: sessionRevocationCacheMaxStaleness,
+		gracePeriod:  sessionRevocationStoreOutageGracePeriod,
+		maxEntries:   sessionRevocationCacheMaxEntries,
+		timeFunc:     time.Now,
+		entries:      map[string]sessionRevocationCacheEntry{},
+	}
+}
+
+// IsRevoked returns whether the session has been revoked; if the store can't be reached the last known state is used for
+// a limited time, and sessions without it are let through during a grace period after the store started failing only
+func (c *sessionRevocationCache) IsRevoked(ctx context.Context, id string) bool {
+
+	now := c.timeFunc()
+
+	c.mu.RLock()
+	entry, found := c.entries[id]
+	c.mu.RUnlock()
+
+	if found && (entry.revoked || now.Sub(entry.checkedAt) < c.ttl) {
+		return entry.revoked
+	}
+
+	revoked, err := c.store.IsSessionRevoked(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed checking whether session %v is revoked", id)
+		if found && (entry.revoked || now.Sub(entry.checkedAt) < c.maxStaleness) {
+			return entry.revoked
+		}
+		return !c.withinOutageGracePeriod(now)
+	}
+
+	c.mu.Lock()
+	c.failingSince = time.Time{}
+	c.mu.Unlock()
+
+	c.set(id, revoked, now)
+
+	return revoked
+}
+
+// MarkRevoked rejects the session straightaway on this replica, without waiting for the cached state to expire
+func (c *sessionRevocationCache) MarkRevoked(id string) {
+	c.set(id, true, c.timeFunc())
+}
+
+// withinOutageGracePeriod records when the store started failing and returns whether that was recently enough to let
+// sessions through
+func (c *sessionRevocationCache) withinOutageGracePeriod(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failingSince.IsZero() {
+		c.failingSince = now
+	}
+
+	return now.Sub(c.failingSince) < c.gracePeriod
+}
+
+func (c *sessionRevocationCache) set(id string, revoked bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxEntries {
+		c.purge(now)
+	}
+
+	c.entries[id] = sessionRevocationCacheEntry{
+		revoked:   revoked,
+		checkedAt: now,
+	}
+}
+
+// purge removes entries that would be checked again anyway and revoked entries for jwts that have expired by now
+func (c *sessionRevocationCache) purge(now time.Time) {
+	for id, entry := range c.entries {
+		if (!entry.revoked && now.Sub(entry.checkedAt) >= c.ttl) || now.Sub(entry.checkedAt) >= jwtTimeout {
+			delete(c.entries, id)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSessionStore struct {
+	revoked map[string]bool
+	err     error
+	checks  int
+}
+
+func (s *fakeSessionStore) CreateSession(ctx context.Context, session Session) (err error) {
+	return nil
+}
+
+func (s *fakeSessionStore) RevokeSession(ctx context.Context, id string) (err error) {
+	s.revoked[id] = true
+	return nil
+}
+
+func (s *fakeSessionStore) IsSessionRevoked(ctx context.Context, id string) (revoked bool, err error) {
+	s.checks++
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.revoked[id], nil
+}
+
+func TestSessionRevocationCache(t *testing.T) {
+	t.Run("ChecksStoreOnlyOnceWithinTTL", func(t *testing.T) {
+
+		store := &fakeSessionStore{revoked: map[string]bool{}}
+		cache := newSessionRevocationCache(store)
+
+		// act
+		first := cache.IsRevoked(context.Background(), "abc")
+		second := cache.IsRevoked(context.Background(), "abc")
+
+		assert.False(t, first)
+		assert.False(t, second)
+		assert.Equal(t, 1, store.checks)
+	})
+
+	t.Run("ChecksStoreAgainAfterTTL", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeSessionStore{revoked: map[string]bool{}}
+		cache := newSessionRevocationCache(store)
+		cache.timeFunc = func() time.Time { return now }
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+
+		// revoked by another replica
+		store.revoked["abc"] = true
+		now = now.Add(sessionRevocationCacheTTL)
+
+		// act
+		revoked := cache.IsRevoked(context.Background(), "abc")
+
+		assert.True(t, revoked)
+		assert.Equal(t, 2, store.checks)
+	})
+
+	t.Run("ReturnsTrueAfterMarkRevokedWithoutCheckingStore", func(t *testing.T) {
+
+		store := &fakeSessionStore{revoked: map[string]bool{}}
+		cache := newSessionRevocationCache(store)
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+
+		// act
+		cache.MarkRevoked("abc")
+
+		assert.True(t, cache.IsRevoked(context.Background(), "abc"))
+		assert.Equal(t, 1, store.checks)
+	})
+
+	t.Run("ReturnsLastKnownStateIfStoreFails", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeSessionStore{revoked: map[string]bool{}}
+		cache := newSessionRevocationCache(store)
+		cache.timeFunc = func() time.Time { return now }
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+
+		store.err = errors.New("database unavailable")
+		now = now.Add(sessionRevocationCacheTTL)
+
+		// act
+		revoked := cache.IsRevoked(context.Background(), "abc")
+
+		assert.False(t, revoked)
+	})
+
+	t.Run("ReturnsTrueIfLastKnownStateIsTooStaleAndStoreFailsLongerThanGracePeriod", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeSessionStore{revoked: map[string]bool{}}
+		cache := newSessionRevocationCache(store)
+		cache.timeFunc = func() time.Time { return now }
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+
+		store.err = errors.New("database unavailable")
+		now = now.Add(sessionRevocationCacheMaxStaleness)
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+		now = now.Add(sessionRevocationStoreOutageGracePeriod)
+
+		// act
+		revoked := cache.IsRevoked(context.Background(), "abc")
+
+		assert.True(t, revoked)
+	})
+
+	t.Run("ReturnsFalseForUncheckedSessionIfStoreFailsWithinGracePeriod", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeSessionStore{revoked: map[string]bool{}, err: errors.New("database unavailable")}
+		cache := newSessionRevocationCache(store)
+		cache.timeFunc = func() time.Time { return now }
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+		now = now.Add(sessionRevocationStoreOutageGracePeriod - time.Second)
+
+		// act
+		revoked := cache.IsRevoked(context.Background(), "def")
+
+		assert.False(t, revoked)
+	})
+
+	t.Run("ReturnsTrueForUncheckedSessionIfStoreFailsLongerThanGracePeriod", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeSessionStore{revoked: map[string]bool{}, err: errors.New("database unavailable")}
+		cache := newSessionRevocationCache(store)
+		cache.timeFunc = func() time.Time { return now }
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+		now = now.Add(sessionRevocationStoreOutageGracePeriod)
+
+		// act
+		revoked := cache.IsRevoked(context.Background(), "def")
+
+		assert.True(t, revoked)
+	})
+
+	t.Run("StartsNewGracePeriodAfterStoreRecovers", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeSessionStore{revoked: map[string]bool{}, err: errors.New("database unavailable")}
+		cache := newSessionRevocationCache(store)
+		cache.timeFunc = func() time.Time { return now }
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+		now = now.Add(sessionRevocationStoreOutageGracePeriod)
+		store.err = nil
+		assert.False(t, cache.IsRevoked(context.Background(), "abc"))
+		store.err = errors.New("database unavailable")
+
+		// act
+		revoked := cache.IsRevoked(context.Background(), "def")
+
+		assert.False(t, revoked)
+	})
+
+	t.Run("PurgesStaleEntriesWhenFull", func(t *testing.T) {
+
+		now := time.Now()
+		store := &fakeSessionStore{revoked: map[string]bool{}}
+		cache := newSessionRevocationCache(store)
+		cache.maxEntries = 2
+		cache.timeFunc = func() time.Time { return now }
+		cache.IsRevoked(context.Background(), "a")
+		cache.MarkRevoked("b")
+		now = now.Add(sessionRevocationCacheTTL)
+
+		// act
+		cache.IsRevoked(context.Background(), "c")
+
+		assert.Equal(t, 2, len(cache.entries))
+		assert.True(t, cache.entries["b"].revoked)
+	})
+}
@@ -21,6 +21,7 @@ type Client interface {
 	UpdateTableSchema(ctx context.Context, table string, typeForSchema interface{}) (err error)
 	InsertBuildEvent(ctx context.Context, event PipelineBuildEvent) (err error)
 	InsertReleaseEvent(ctx context.Context, event PipelineReleaseEvent) (err error)
+	InsertBotEvent(ctx context.Context, event PipelineBotEvent) (err error)
 }
 
 // NewClient returns new bigquery.Client
@@ -37,6 +38,7 @@ func NewClient(config *api.APIConfig, bigqueryClient *bigquery.Client) Client {
 		config:                 config,
 		buildEventsTableName:   "ziplinee_ci_build_events",
 		releaseEventsTableName: "ziplinee_ci_release_events",
+		botEventsTableName:     "ziplinee_ci_bot_events",
 	}
 }
 
@@ -46,6 +48,7 @@ type client struct {
 	config                 *api.APIConfig
 	buildEventsTableName   string
 	releaseEventsTableName string
+	botEventsTableName     string
 }
 
 func (c *client) Init(ctx context.Context) (err error) {
@@ -54,7 +57,7 @@ func (c *client) Init(ctx context.Context) (err error) {
 		return
 	}
 
-	log.Debug().Msgf("Initializing BigQuery tables %v and %v...", c.buildEventsTableName, c.releaseEventsTableName)
+	log.Debug().Msgf("Initializing BigQuery tables %v, %v and %v...", c.buildEventsTableName, c.releaseEventsTableName, c.botEventsTableName)
 
 	datasetExists := c.CheckIfDatasetExists(ctx)
 	if !datasetExists {
@@ -81,6 +84,16 @@ func (c *client) Init(ctx context.Context) (err error) {
 		return
 	}
 
+	botEventsTableExists := c.CheckIfTableExists(ctx, c.botEventsTableName)
+	if botEventsTableExists {
+		err = c.UpdateTableSchema(ctx, c.botEventsTableName, PipelineBotEvent{})
+	} else {
+		err = c.CreateTable(ctx, c.botEventsTableName, PipelineBotEvent{}, "inserted_at", true)
+	}
+	if err != nil {
+		return
+	}
+
 	return nil
 }
 
@@ -231,3 +244,20 @@ func (c *client) InsertReleaseEvent(ctx context.Context, event PipelineReleaseEv
 
 	return nil
 }
+
+func (c *client) InsertBotEvent(ctx context.Context, event PipelineBotEvent) error {
+
+	if !c.enabled {
+		return nil
+	}
+
+	tbl := c.client.Dataset(c.config.Integrations.BigQuery.Dataset).Table(c.botEventsTableName)
+
+	u := tbl.Uploader()
+
+	if err := u.Put(ctx, event); err != nil {
+		return err
+	}
+
+	return nil
+}
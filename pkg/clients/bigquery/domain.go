@@ -17,20 +17,12 @@ type PipelineBuildEvent struct {
 	BuildVersion string `bigquery:"build_version"`
 	BuildStatus  string `bigquery:"build_status"`
 
-	Labels []struct {
-		Key   string `bigquery:"key"`
-		Value string `bigquery:"value"`
-	} `bigquery:"labels"`
+	Labels []Label `bigquery:"labels"`
 
 	InsertedAt time.Time `bigquery:"inserted_at"`
 	UpdatedAt  time.Time `bigquery:"updated_at"`
 
-	Commits []struct {
-		Message string `bigquery:"message"`
-		Author  struct {
-			Email string `bigquery:"email"`
-		} `bigquery:"author"`
-	} `bigquery:"commits"`
+	Commits []Commit `bigquery:"commits"`
 
 	CPURequest     bigquery.NullFloat64 `bigquery:"cpu_request"`
 	CPULimit       bigquery.NullFloat64 `bigquery:"cpu_limit"`
@@ -57,10 +49,7 @@ type PipelineReleaseEvent struct {
 	ReleaseVersion string `bigquery:"release_version"`
 	ReleaseStatus  string `bigquery:"release_status"`
 
-	Labels []struct {
-		Key   string `bigquery:"key"`
-		Value string `bigquery:"value"`
-	} `bigquery:"labels"`
+	Labels []Label `bigquery:"labels"`
 
 	InsertedAt time.Time `bigquery:"inserted_at"`
 	UpdatedAt  time.Time `bigquery:"updated_at"`
@@ -78,6 +67,47 @@ type PipelineReleaseEvent struct {
 	Jobs []Job `bigquery:"logs"`
 }
 
+// PipelineBotEvent tracks a bot once it's finished
+type PipelineBotEvent struct {
+	BotID      int    `bigquery:"bot_id"`
+	RepoSource string `bigquery:"repo_source"`
+	RepoOwner  string `bigquery:"repo_owner"`
+	RepoName   string `bigquery:"repo_name"`
+	BotName    string `bigquery:"bot_name"`
+	BotStatus  string `bigquery:"bot_status"`
+
+	Labels []Label `bigquery:"labels"`
+
+	InsertedAt time.Time `bigquery:"inserted_at"`
+	UpdatedAt  time.Time `bigquery:"updated_at"`
+
+	CPURequest     bigquery.NullFloat64 `bigquery:"cpu_request"`
+	CPULimit       bigquery.NullFloat64 `bigquery:"cpu_limit"`
+	CPUMaxUsage    bigquery.NullFloat64 `bigquery:"cpu_max_usage"`
+	MemoryRequest  bigquery.NullFloat64 `bigquery:"memory_request"`
+	MemoryLimit    bigquery.NullFloat64 `bigquery:"memory_limit"`
+	MemoryMaxUsage bigquery.NullFloat64 `bigquery:"memory_max_usage"`
+
+	TotalDuration time.Duration `bigquery:"duration"`
+	TimeToRunning time.Duration `bigquery:"time_to_running"`
+
+	Jobs []Job `bigquery:"logs"`
+}
+
+// Label is a pipeline label attached to an event
+type Label struct {
+	Key   string `bigquery:"key"`
+	Value string `bigquery:"value"`
+}
+
+// Commit is a git commit included in a build
+type Commit struct {
+	Message string `bigquery:"message"`
+	Author  struct {
+		Email string `bigquery:"email"`
+	} `bigquery:"author"`
+}
+
 // Job represent and actual job execution; a build / release can have multiple runs of a job if Kubernetes reschedules it
 type Job struct {
 	JobID  int `bigquery:"job_id"`
@@ -0,0 +1,79 @@
+package bigquery
+
+import (
+	"context"
+	"sync"
+)
+
+// NewFakeClient returns a Client that keeps inserted events in memory, for testing without access to BigQuery
+func NewFakeClient() *FakeClient {
+	return &FakeClient{}
+}
+
+// FakeClient is an in-memory Client
+type FakeClient struct {
+	// InsertError is returned by every insert when set, to act as if BigQuery is unavailable
+	InsertError error
+
+	BuildEvents   []PipelineBuildEvent
+	ReleaseEvents []PipelineReleaseEvent
+	BotEvents     []PipelineBotEvent
+
+	mutex sync.Mutex
+}
+
+func (c *FakeClient) Init(ctx context.Context) (err error) {
+	return nil
+}
+
+func (c *FakeClient) CheckIfDatasetExists(ctx context.Context) (exists bool) {
+	return true
+}
+
+func (c *FakeClient) CheckIfTableExists(ctx context.Context, table string) (exists bool) {
+	return true
+}
+
+func (c *FakeClient) CreateTable(ctx context.Context, table string, typeForSchema interface{}, partitionField string, waitReady bool) (err error) {
+	return nil
+}
+
+func (c *FakeClient) UpdateTableSchema(ctx context.Context, table string, typeForSchema interface{}) (err error) {
+	return nil
+}
+
+func (c *FakeClient) InsertBuildEvent(ctx context.Context, event PipelineBuildEvent) (err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.InsertError != nil {
+		return c.InsertError
+	}
+	c.BuildEvents = append(c.BuildEvents, event)
+
+	return nil
+}
+
+func (c *FakeClient) InsertReleaseEvent(ctx context.Context, event PipelineReleaseEvent) (err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.InsertError != nil {
+		return c.InsertError
+	}
+	c.ReleaseEvents = append(c.ReleaseEvents, event)
+
+	return nil
+}
+
+func (c *FakeClient) InsertBotEvent(ctx context.Context, event PipelineBotEvent) (err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.InsertError != nil {
+		return c.InsertError
+	}
+	c.BotEvents = append(c.BotEvents, event)
+
+	return nil
+}
@@ -52,3 +52,9 @@ func (c *loggingClient) InsertReleaseEvent(ctx context.Context, event PipelineRe
 
 	return c.Client.InsertReleaseEvent(ctx, event)
 }
+
+func (c *loggingClient) InsertBotEvent(ctx context.Context, event PipelineBotEvent) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertBotEvent", err) }()
+
+	return c.Client.InsertBotEvent(ctx, event)
+}
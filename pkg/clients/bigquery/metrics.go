@@ -70,3 +70,11 @@ func (c *metricsClient) InsertReleaseEvent(ctx context.Context, event PipelineRe
 
 	return c.Client.InsertReleaseEvent(ctx, event)
 }
+
+func (c *metricsClient) InsertBotEvent(ctx context.Context, event PipelineBotEvent) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertBotEvent", begin)
+	}(time.Now())
+
+	return c.Client.InsertBotEvent(ctx, event)
+}
@@ -90,6 +90,20 @@ func (mr *MockClientMockRecorder) Init(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockClient)(nil).Init), ctx)
 }
 
+// InsertBotEvent mocks base method.
+func (m *MockClient) InsertBotEvent(ctx context.Context, event PipelineBotEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBotEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertBotEvent indicates an expected call of InsertBotEvent.
+func (mr *MockClientMockRecorder) InsertBotEvent(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBotEvent", reflect.TypeOf((*MockClient)(nil).InsertBotEvent), ctx, event)
+}
+
 // InsertBuildEvent mocks base method.
 func (m *MockClient) InsertBuildEvent(ctx context.Context, event PipelineBuildEvent) error {
 	m.ctrl.T.Helper()
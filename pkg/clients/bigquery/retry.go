@@ -0,0 +1,294 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+)
+
+// maxBufferedEventAttempts is the number of times a buffered event is retried before it's dropped, so an event that BigQuery
+// keeps failing on doesn't hold up the events buffered after it forever
+const maxBufferedEventAttempts = 10
+
+// NewRetryClient returns a new instance of a Client that stores events failing to get inserted on disk and retries them in the background.
+func NewRetryClient(config *api.APIConfig, c Client) Client {
+	if config == nil || config.Integrations == nil || config.Integrations.BigQuery == nil || !config.Integrations.BigQuery.Enable {
+		return c
+	}
+
+	return &retryClient{
+		Client:            c,
+		directory:         config.Integrations.BigQuery.RetryBufferDirectory,
+		maxBufferedEvents: config.Integrations.BigQuery.MaxBufferedEvents,
+		retryInterval:     time.Duration(config.Integrations.BigQuery.RetryIntervalSeconds) * time.Second,
+	}
+}
+
+type retryClient struct {
+	Client            Client
+	directory         string
+	maxBufferedEvents int
+	retryInterval     time.Duration
+
+	// mutex guards the files in the buffer directory; it's never held while talking to BigQuery
+	mutex sync.Mutex
+
+	// retryMutex makes sure only one retry of buffered events runs at a time
+	retryMutex sync.Mutex
+}
+
+// bufferedEvent is the file content for an event that failed to get inserted
+type bufferedEvent struct {
+	BuildEvent   *PipelineBuildEvent   `json:"buildEvent,omitempty"`
+	ReleaseEvent *PipelineReleaseEvent `json:"releaseEvent,omitempty"`
+	BotEvent     *PipelineBotEvent     `json:"botEvent,omitempty"`
+
+	// Attempts is the number of times retrying the event failed
+	Attempts int `json:"attempts,omitempty"`
+}
+
+func (c *retryClient) Init(ctx context.Context) (err error) {
+	err = c.Client.Init(ctx)
+
+	// insert events left behind by a previous instance and keep retrying buffered events until the context gets canceled
+	c.retryBufferedEvents(ctx)
+	go c.retryBufferedEventsLoop(ctx)
+
+	return err
+}
+
+func (c *retryClient) CheckIfDatasetExists(ctx context.Context) (exists bool) {
+	return c.Client.CheckIfDatasetExists(ctx)
+}
+
+func (c *retryClient) CheckIfTableExists(ctx context.Context, table string) (exists bool) {
+	return c.Client.CheckIfTableExists(ctx, table)
+}
+
+func (c *retryClient) CreateTable(ctx context.Context, table string, typeForSchema interface{}, partitionField string, waitReady bool) (err error) {
+	return c.Client.CreateTable(ctx, table, typeForSchema, partitionField, waitReady)
+}
+
+func (c *retryClient) UpdateTableSchema(ctx context.Context, table string, typeForSchema interface{}) (err error) {
+	return c.Client.UpdateTableSchema(ctx, table, typeForSchema)
+}
+
+func (c *retryClient) InsertBuildEvent(ctx context.Context, event PipelineBuildEvent) (err error) {
+	return c.insertOrBuffer(ctx, bufferedEvent{BuildEvent: &event})
+}
+
+func (c *retryClient) InsertReleaseEvent(ctx context.Context, event PipelineReleaseEvent) (err error) {
+	return c.insertOrBuffer(ctx, bufferedEvent{ReleaseEvent: &event})
+}
+
+func (c *retryClient) InsertBotEvent(ctx context.Context, event PipelineBotEvent) (err error) {
+	return c.insertOrBuffer(ctx, bufferedEvent{BotEvent: &event})
+}
+
+func (c *retryClient) insertOrBuffer(ctx context.Context, event bufferedEvent) (err error) {
+	err = c.insert(ctx, event)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed inserting event into BigQuery, storing it in %v to retry later", c.directory)
+		return c.buffer(event)
+	}
+
+	return nil
+}
+
+func (c *retryClient) insert(ctx context.Context, event bufferedEvent) error {
+	switch {
+	case event.BuildEvent != nil:
+		return c.Client.InsertBuildEvent(ctx, *event.BuildEvent)
+	case event.ReleaseEvent != nil:
+		return c.Client.InsertReleaseEvent(ctx, *event.ReleaseEvent)
+	case event.BotEvent != nil:
+		return c.Client.InsertBotEvent(ctx, *event.BotEvent)
+	}
+
+	return nil
+}
+
+func (c *retryClient) retryBufferedEventsLoop(ctx context.Context) {
+	if c.retryInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.retryBufferedEvents(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// retryBufferedEvents inserts buffered events oldest first and stops at the first failure, since BigQuery is probably still
+// unavailable; events BigQuery rejects are skipped instead, and events failing too often are dropped
+func (c *retryClient) retryBufferedEvents(ctx context.Context) {
+	c.retryMutex.Lock()
+	defer c.retryMutex.Unlock()
+
+	c.mutex.Lock()
+	files, err := c.bufferedEventFiles()
+	c.mutex.Unlock()
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed listing buffered BigQuery events in %v", c.directory)
+		return
+	}
+
+	inserted := 0
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if os.IsNotExist(err) {
+			// dropped to stay within the limit in the meantime
+			continue
+		}
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed reading buffered BigQuery event %v", f)
+			break
+		}
+
+		var event bufferedEvent
+		err = json.Unmarshal(data, &event)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Buffered BigQuery event %v is invalid, removing it", f)
+			c.remove(f)
+			continue
+		}
+
+		err = c.insert(ctx, event)
+		if err != nil {
+			dropped := c.recordFailedAttempt(f, event, err)
+
+			var putMultiError bigquery.PutMultiError
+			if dropped || errors.As(err, &putMultiError) {
+				// BigQuery is available, but rejected the event's rows, or the event is no longer in the way
+				continue
+			}
+			break
+		}
+
+		c.remove(f)
+		inserted++
+	}
+
+	if inserted > 0 {
+		log.Info().Msgf("Inserted %v buffered BigQuery events", inserted)
+	}
+}
+
+// recordFailedAttempt stores the failed attempt in the event's file, or drops the event once it has failed too often
+func (c *retryClient) recordFailedAttempt(file string, event bufferedEvent, insertErr error) (dropped bool) {
+
+	event.Attempts++
+	if event.Attempts >= maxBufferedEventAttempts {
+		log.Warn().Err(insertErr).Msgf("Inserting buffered BigQuery event %v failed %v times, dropping it", file, event.Attempts)
+		c.remove(file)
+		return true
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed marshalling buffered BigQuery event %v", file)
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, err = os.Stat(file); os.IsNotExist(err) {
+		// dropped to stay within the limit in the meantime
+		return true
+	}
+	err = os.WriteFile(file, data, 0600)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed storing attempt for buffered BigQuery event %v", file)
+	}
+
+	return false
+}
+
+func (c *retryClient) remove(file string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	err := os.Remove(file)
+	if err != nil && !os.IsNotExist(err) {
+		log.Warn().Err(err).Msgf("Failed removing buffered BigQuery event %v", file)
+	}
+}
+
+func (c *retryClient) buffer(event bufferedEvent) (err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	err = os.MkdirAll(c.directory, 0700)
+	if err != nil {
+		return
+	}
+
+	files, err := c.bufferedEventFiles()
+	if err != nil {
+		return
+	}
+
+	// drop the oldest events to stay within the limit
+	for len(files) >= c.maxBufferedEvents && len(files) > 0 {
+		log.Warn().Msgf("BigQuery retry buffer holds the maximum of %v events, dropping oldest event %v", c.maxBufferedEvents, files[0])
+		err = os.Remove(files[0])
+		if err != nil {
+			return
+		}
+		files = files[1:]
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	// prefix the file name with the time so sorting by name returns the oldest first
+	f, err := os.CreateTemp(c.directory, fmt.Sprintf("%020d-*.json", time.Now().UnixNano()))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+
+	return
+}
+
+func (c *retryClient) bufferedEventFiles() (files []string, err error) {
+	entries, err := os.ReadDir(c.directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, filepath.Join(c.directory, e.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	return
+}
@@ -0,0 +1,180 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+)
+
+// failingBuildEventClient fails inserting the build event with a specific id and inserts all other events
+type failingBuildEventClient struct {
+	*FakeClient
+	buildID int
+	err     error
+}
+
+func (c *failingBuildEventClient) InsertBuildEvent(ctx context.Context, event PipelineBuildEvent) (err error) {
+	if event.BuildID == c.buildID {
+		return c.err
+	}
+
+	return c.FakeClient.InsertBuildEvent(ctx, event)
+}
+
+func TestRetryClient(t *testing.T) {
+
+	getConfig := func(directory string, maxBufferedEvents int) *api.APIConfig {
+		return &api.APIConfig{
+			Integrations: &api.APIConfigIntegrations{
+				BigQuery: &api.BigQueryConfig{
+					Enable:               true,
+					RetryBufferDirectory: directory,
+					MaxBufferedEvents:    maxBufferedEvents,
+				},
+			},
+		}
+	}
+
+	t.Run("StoresEventOnDiskIfInsertFails", func(t *testing.T) {
+
+		ctx := context.Background()
+		directory := t.TempDir()
+		fakeClient := NewFakeClient()
+		fakeClient.InsertError = errors.New("bigquery is unavailable")
+		client := NewRetryClient(getConfig(directory, 10), fakeClient)
+
+		// act
+		err := client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 15})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(fakeClient.BuildEvents))
+		files, _ := os.ReadDir(directory)
+		assert.Equal(t, 1, len(files))
+	})
+
+	t.Run("InsertsBufferedEventsOnRetryOnceInsertSucceedsAgain", func(t *testing.T) {
+
+		ctx := context.Background()
+		directory := t.TempDir()
+		fakeClient := NewFakeClient()
+		fakeClient.InsertError = errors.New("bigquery is unavailable")
+		client := NewRetryClient(getConfig(directory, 10), fakeClient)
+
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 15})
+		_ = client.InsertReleaseEvent(ctx, PipelineReleaseEvent{ReleaseID: 16})
+		fakeClient.InsertError = nil
+
+		// act
+		client.(*retryClient).retryBufferedEvents(ctx)
+
+		if assert.Equal(t, 1, len(fakeClient.BuildEvents)) {
+			assert.Equal(t, 15, fakeClient.BuildEvents[0].BuildID)
+		}
+		if assert.Equal(t, 1, len(fakeClient.ReleaseEvents)) {
+			assert.Equal(t, 16, fakeClient.ReleaseEvents[0].ReleaseID)
+		}
+		files, _ := os.ReadDir(directory)
+		assert.Equal(t, 0, len(files))
+	})
+
+	t.Run("InsertsNewEventWithoutWaitingForBufferedEvents", func(t *testing.T) {
+
+		ctx := context.Background()
+		directory := t.TempDir()
+		fakeClient := NewFakeClient()
+		fakeClient.InsertError = errors.New("bigquery is unavailable")
+		client := NewRetryClient(getConfig(directory, 10), fakeClient)
+
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 15})
+		fakeClient.InsertError = nil
+
+		// act
+		err := client.InsertBotEvent(ctx, PipelineBotEvent{BotID: 17})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(fakeClient.BuildEvents))
+		if assert.Equal(t, 1, len(fakeClient.BotEvents)) {
+			assert.Equal(t, 17, fakeClient.BotEvents[0].BotID)
+		}
+		files, _ := os.ReadDir(directory)
+		assert.Equal(t, 1, len(files))
+	})
+
+	t.Run("DropsOldestEventIfBufferIsFull", func(t *testing.T) {
+
+		ctx := context.Background()
+		directory := t.TempDir()
+		fakeClient := NewFakeClient()
+		fakeClient.InsertError = errors.New("bigquery is unavailable")
+		client := NewRetryClient(getConfig(directory, 2), fakeClient)
+
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 1})
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 2})
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 3})
+		fakeClient.InsertError = nil
+
+		// act
+		err := client.Init(ctx)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(fakeClient.BuildEvents)) {
+			assert.Equal(t, 2, fakeClient.BuildEvents[0].BuildID)
+			assert.Equal(t, 3, fakeClient.BuildEvents[1].BuildID)
+		}
+	})
+
+	t.Run("SkipsBufferedEventThatBigqueryRejects", func(t *testing.T) {
+
+		ctx := context.Background()
+		directory := t.TempDir()
+		fakeClient := NewFakeClient()
+		fakeClient.InsertError = errors.New("bigquery is unavailable")
+		failingClient := &failingBuildEventClient{FakeClient: fakeClient, buildID: 1, err: bigquery.PutMultiError{bigquery.RowInsertionError{}}}
+		client := NewRetryClient(getConfig(directory, 10), failingClient)
+
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 1})
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 2})
+		fakeClient.InsertError = nil
+
+		// act
+		client.(*retryClient).retryBufferedEvents(ctx)
+
+		if assert.Equal(t, 1, len(fakeClient.BuildEvents)) {
+			assert.Equal(t, 2, fakeClient.BuildEvents[0].BuildID)
+		}
+		files, _ := os.ReadDir(directory)
+		assert.Equal(t, 1, len(files))
+	})
+
+	t.Run("DropsBufferedEventThatKeepsFailingSoLaterEventsGetInserted", func(t *testing.T) {
+
+		ctx := context.Background()
+		directory := t.TempDir()
+		fakeClient := NewFakeClient()
+		fakeClient.InsertError = errors.New("bigquery is unavailable")
+		failingClient := &failingBuildEventClient{FakeClient: fakeClient, buildID: 1, err: errors.New("bigquery is unavailable")}
+		client := NewRetryClient(getConfig(directory, 10), failingClient)
+
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 1})
+		_ = client.InsertBuildEvent(ctx, PipelineBuildEvent{BuildID: 2})
+		fakeClient.InsertError = nil
+
+		// act
+		for i := 0; i < maxBufferedEventAttempts-1; i++ {
+			client.(*retryClient).retryBufferedEvents(ctx)
+		}
+		assert.Equal(t, 0, len(fakeClient.BuildEvents))
+		client.(*retryClient).retryBufferedEvents(ctx)
+
+		if assert.Equal(t, 1, len(fakeClient.BuildEvents)) {
+			assert.Equal(t, 2, fakeClient.BuildEvents[0].BuildID)
+		}
+		files, _ := os.ReadDir(directory)
+		assert.Equal(t, 0, len(files))
+	})
+}
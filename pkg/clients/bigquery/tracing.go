@@ -65,3 +65,10 @@ func (c *tracingClient) InsertReleaseEvent(ctx context.Context, event PipelineRe
 
 	return c.Client.InsertReleaseEvent(ctx, event)
 }
+
+func (c *tracingClient) InsertBotEvent(ctx context.Context, event PipelineBotEvent) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertBotEvent"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertBotEvent(ctx, event)
+}
@@ -12,7 +12,6 @@ import (
 	"strings"
 	"time"
 
-	jwtgo "github.com/golang-jwt/jwt/v4"
 	"github.com/jinzhu/copier"
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
@@ -151,6 +150,13 @@ func (c *client) CreateCiBuilderJob(ctx context.Context, ciBuilderParams CiBuild
 
 	terminationGracePeriodSeconds := int64(120)
 
+	// stop jobs once they can no longer report back
+	var activeDeadlineSeconds *int64
+	if c.config.Jobs.MaxRunDurationMinutes > 0 {
+		maxRunDurationSeconds := int64(c.config.Jobs.MaxRunDurationMinutes) * 60
+		activeDeadlineSeconds = &maxRunDurationSeconds
+	}
+
 	job = &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
@@ -161,6 +167,7 @@ func (c *client) CreateCiBuilderJob(ctx context.Context, ciBuilderParams CiBuild
 			},
 		},
 		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds: activeDeadlineSeconds,
 			Template: v1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
@@ -662,12 +669,24 @@ func (c *client) getBuilderConfig(ctx context.Context, ciBuilderParams CiBuilder
 		return localBuilderConfig, err
 	}
 
-	now := time.Now().UTC()
-	expiry := now.Add(time.Duration(6) * time.Hour)
+	// bind the token to this job, so it can't be used to report for another one
+	jobClaims := api.JobTokenClaims{
+		JobName:    jobName,
+		JobType:    localBuilderConfig.JobType,
+		RepoSource: localBuilderConfig.Git.RepoSource,
+		RepoOwner:  localBuilderConfig.Git.RepoOwner,
+		RepoName:   localBuilderConfig.Git.RepoName,
+	}
+	switch localBuilderConfig.JobType {
+	case contracts.JobTypeBuild:
+		jobClaims.ID = localBuilderConfig.Build.ID
+	case contracts.JobTypeRelease:
+		jobClaims.ID = localBuilderConfig.Release.ID
+	case contracts.JobTypeBot:
+		jobClaims.ID = localBuilderConfig.Bot.ID
+	}
 
-	jwt, err := api.GenerateJWT(c.config, now, expiry, jwtgo.MapClaims{
-		"job": jobName,
-	})
+	jwt, expiry, err := api.GenerateJobToken(c.config, time.Now().UTC(), jobClaims)
 	if err != nil {
 		return contracts.BuilderConfig{}, err
 	}
@@ -388,6 +388,9 @@ func TestGetBuilderConfig(t *testing.T) {
 				APIServer: &api.APIServerConfig{
 					ServiceURL: "https://ci.ziplinee.api",
 				},
+				Jobs: &api.JobsConfig{
+					MaxRunDurationMinutes: 360,
+				},
 			},
 		}
 		ciBuilderParams := CiBuilderParams{
@@ -460,6 +463,9 @@ func TestGetBuilderConfig(t *testing.T) {
 				APIServer: &api.APIServerConfig{
 					ServiceURL: "https://ci.ziplinee.api",
 				},
+				Jobs: &api.JobsConfig{
+					MaxRunDurationMinutes: 360,
+				},
 			},
 		}
 		ciBuilderParams := CiBuilderParams{
@@ -6,10 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/rs/zerolog/log"
@@ -35,8 +35,16 @@ type Client interface {
 	GetPipelineBuildLogs(ctx context.Context, buildLog contracts.BuildLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error)
 	GetPipelineReleaseLogs(ctx context.Context, releaseLog contracts.ReleaseLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error)
 	GetPipelineBotLogs(ctx context.Context, botLog contracts.BotLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error)
+	GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) (steps []*contracts.BuildLogStep, err error)
+	GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) (steps []*contracts.BuildLogStep, err error)
+	GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) (steps []*contracts.BuildLogStep, err error)
 	Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) (err error)
 	DeleteLogs(ctx context.Context, repoSource, repoOwner, repoName string) (err error)
+	CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error)
+	CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error)
+	PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (count int, bytes int64, err error)
+	GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) (logIDs []string, err error)
+	CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) (err error)
 }
 
 // NewClient returns new cloudstorage.Client
@@ -220,30 +228,47 @@ func (c *client) getLog(ctx context.Context, path string, acceptGzipEncoding boo
 	}
 	defer reader.Close()
 
-	// create source reader to either copy compressed bytes or decompress them first
-	sourceReader := io.Reader(reader)
-	if acceptGzipEncoding {
-		responseWriter.Header().Set("Content-Encoding", "gzip")
-		responseWriter.Header().Set("Vary", "Accept-Encoding")
-	} else {
-		gzr, err := gzip.NewReader(reader)
-		if err != nil {
-			return err
-		}
-		defer gzr.Close()
-		sourceReader = io.Reader(gzr)
-	}
+	return writeLog(reader, acceptGzipEncoding, responseWriter)
+}
+
+func (c *client) GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath := c.getBuildLogPath(buildLog)
+
+	return c.getLogSteps(ctx, logPath)
+}
+
+func (c *client) GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath := c.getReleaseLogPath(releaseLog)
+
+	return c.getLogSteps(ctx, logPath)
+}
+
+func (c *client) GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath := c.getBotLogPath(botLog)
+
+	return c.getLogSteps(ctx, logPath)
+}
 
-	responseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+// getLogSteps reads and decompresses a log object and unmarshals it into its steps, for inspecting logs server-side
+func (c *client) getLogSteps(ctx context.Context, path string) (steps []*contracts.BuildLogStep, err error) {
+
+	bucket := c.client.Bucket(c.config.Integrations.CloudStorage.Bucket)
 
-	writtenBytes, err := io.Copy(responseWriter, sourceReader)
+	logObject := bucket.Object(path).ReadCompressed(true)
+	reader, err := logObject.NewReader(ctx)
 	if err != nil {
-		return err
-	}
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, ErrLogNotExist
+		}
 
-	responseWriter.Header().Set("Content-Length", fmt.Sprint(writtenBytes))
+		return nil, err
+	}
+	defer reader.Close()
 
-	return nil
+	return readLogSteps(reader, path)
 }
 
 func (c *client) getBuildLogPath(buildLog contracts.BuildLog) (logPath string) {
@@ -297,6 +322,64 @@ func (c *client) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, from
 	return nil
 }
 
+func (c *client) CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+
+	fromLogDirectory := c.getLogDirectory(fromRepoSource, fromRepoOwner, fromRepoName, "builds")
+	toLogDirectory := c.getLogDirectory(toRepoSource, toRepoOwner, toRepoName, "builds")
+
+	return c.copyLogs(ctx, fromLogDirectory, toLogDirectory, logIDs)
+}
+
+func (c *client) CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+
+	fromLogDirectory := c.getLogDirectory(fromRepoSource, fromRepoOwner, fromRepoName, "releases")
+	toLogDirectory := c.getLogDirectory(toRepoSource, toRepoOwner, toRepoName, "releases")
+
+	return c.copyLogs(ctx, fromLogDirectory, toLogDirectory, logIDs)
+}
+
+// copyLogs copies the log files for the original ids in logIDs to files named after the new ids, leaving the original files in place
+func (c *client) copyLogs(ctx context.Context, fromLogDirectory, toLogDirectory string, logIDs map[string]string) (err error) {
+
+	log.Debug().Msgf("Copying %v cloud storage logs from %v to %v", len(logIDs), fromLogDirectory, toLogDirectory)
+
+	bucket := c.client.Bucket(c.config.Integrations.CloudStorage.Bucket)
+
+	worker := func(ctx context.Context, job [2]string) (bool, error) {
+		fromLogFilePath := path.Join(fromLogDirectory, fmt.Sprintf("%v.log", job[0]))
+		toLogFilePath := path.Join(toLogDirectory, fmt.Sprintf("%v.log", job[1]))
+
+		_, err := bucket.Object(toLogFilePath).CopierFrom(bucket.Object(fromLogFilePath)).Run(ctx)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			// logs written before cloud storage got enabled only exist in the database
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed copying log %v to %v: %w", fromLogFilePath, toLogFilePath, err)
+		}
+		return true, nil
+	}
+	p, err := pool.NewPool(ctx, pool.DefaultConfig(20, worker))
+	if err != nil {
+		return fmt.Errorf("failed creating pool: %w", err)
+	}
+	for fromID, toID := range logIDs {
+		p.SendJobs([2]string{fromID, toID})
+	}
+	for range p.Close() {
+	}
+
+	jobErrs := p.Errors()
+	if len(jobErrs) > 0 {
+		for _, jobErr := range jobErrs {
+			log.Error().Err(jobErr.Err).Send()
+		}
+		return fmt.Errorf("failed copying %v of %v logs from %v to %v", len(jobErrs), len(logIDs), fromLogDirectory, toLogDirectory)
+	}
+
+	return nil
+}
+
 func (c *client) renameFilesInDirectory(ctx context.Context, bucket *storage.BucketHandle, fromLogFileDirectory, toLogFileDirectory string) (err error) {
 
 	query := &storage.Query{
@@ -343,3 +426,125 @@ func (c *client) renameFile(ctx context.Context, bucket *storage.BucketHandle, f
 
 	return nil
 }
+
+// PruneLogs deletes the log files of a job type created before a point in time, except for the logs with ids in keepLogIDs; with dryRun set it only counts them
+func (c *client) PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (count int, bytes int64, err error) {
+
+	logDirectory := c.getLogDirectory(repoSource, repoOwner, repoName, string(jobType)+"s")
+
+	keep := map[string]bool{}
+	for _, id := range keepLogIDs {
+		keep[fmt.Sprintf("%v.log", id)] = true
+	}
+
+	bucket := c.client.Bucket(c.config.Integrations.CloudStorage.Bucket)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: logDirectory})
+
+	batch := []string{}
+	for {
+		objAttrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return count, bytes, fmt.Errorf("failed listing logs in %v: %w", logDirectory, err)
+		}
+		if !objAttrs.Created.Before(createdBefore) || keep[path.Base(objAttrs.Name)] {
+			continue
+		}
+
+		count++
+		bytes += objAttrs.Size
+
+		if dryRun {
+			continue
+		}
+
+		batch = append(batch, objAttrs.Name)
+		if len(batch) >= batchSize {
+			err = c.deleteLogs(ctx, bucket, batch)
+			if err != nil {
+				return count, bytes, err
+			}
+			batch = []string{}
+		}
+	}
+
+	if len(batch) > 0 {
+		err = c.deleteLogs(ctx, bucket, batch)
+		if err != nil {
+			return count, bytes, err
+		}
+	}
+
+	return count, bytes, nil
+}
+
+// GetLogIDs returns the ids of all log files stored for a job type of a pipeline
+func (c *client) GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) (logIDs []string, err error) {
+
+	logDirectory := c.getLogDirectory(repoSource, repoOwner, repoName, string(jobType)+"s")
+
+	bucket := c.client.Bucket(c.config.Integrations.CloudStorage.Bucket)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: logDirectory})
+
+	logIDs = []string{}
+	for {
+		objAttrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed listing logs in %v: %w", logDirectory, err)
+		}
+
+		logFileName := path.Base(objAttrs.Name)
+		if path.Ext(logFileName) != ".log" {
+			continue
+		}
+		logIDs = append(logIDs, strings.TrimSuffix(logFileName, ".log"))
+	}
+
+	return logIDs, nil
+}
+
+// CopyLogs copies log files of a job type within a pipeline, from the original ids in logIDs to files named after the new ids
+func (c *client) CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) (err error) {
+
+	logDirectory := c.getLogDirectory(repoSource, repoOwner, repoName, string(jobType)+"s")
+
+	return c.copyLogs(ctx, logDirectory, logDirectory, logIDs)
+}
+
+func (c *client) deleteLogs(ctx context.Context, bucket *storage.BucketHandle, logFilePaths []string) (err error) {
+
+	log.Debug().Msgf("Deleting %v cloud storage logs", len(logFilePaths))
+
+	worker := func(ctx context.Context, job string) (bool, error) {
+		err := bucket.Object(job).Delete(ctx)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed deleting log %v: %w", job, err)
+		}
+		return true, nil
+	}
+	p, err := pool.NewPool(ctx, pool.DefaultConfig(20, worker))
+	if err != nil {
+		return fmt.Errorf("failed creating pool: %w", err)
+	}
+	p.SendJobs(logFilePaths...)
+	for range p.Close() {
+	}
+
+	jobErrs := p.Errors()
+	if len(jobErrs) > 0 {
+		for _, jobErr := range jobErrs {
+			log.Error().Err(jobErr.Err).Send()
+		}
+		return fmt.Errorf("failed deleting %v of %v logs", len(jobErrs), len(logFilePaths))
+	}
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package cloudstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+// NewFilesystemClient returns a cloudstorage.Client storing logs on a local or network mounted filesystem
+func NewFilesystemClient(config *api.APIConfig) Client {
+	return &filesystemClient{
+		config: config,
+	}
+}
+
+type filesystemClient struct {
+	config *api.APIConfig
+}
+
+func (c *filesystemClient) InsertBuildLog(ctx context.Context, buildLog contracts.BuildLog) (err error) {
+
+	logPath, err := c.getLogPath(buildLog.RepoSource, buildLog.RepoOwner, buildLog.RepoName, contracts.JobTypeBuild, buildLog.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.insertLog(logPath, buildLog.Steps)
+}
+
+func (c *filesystemClient) InsertReleaseLog(ctx context.Context, releaseLog contracts.ReleaseLog) (err error) {
+
+	logPath, err := c.getLogPath(releaseLog.RepoSource, releaseLog.RepoOwner, releaseLog.RepoName, contracts.JobTypeRelease, releaseLog.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.insertLog(logPath, releaseLog.Steps)
+}
+
+func (c *filesystemClient) InsertBotLog(ctx context.Context, botLog contracts.BotLog) (err error) {
+
+	logPath, err := c.getLogPath(botLog.RepoSource, botLog.RepoOwner, botLog.RepoName, contracts.JobTypeBot, botLog.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.insertLog(logPath, botLog.Steps)
+}
+
+func (c *filesystemClient) insertLog(path string, steps []*contracts.BuildLogStep) (err error) {
+
+	// don't allow overwrites, return when file already exists
+	if _, err = os.Stat(path); err == nil {
+		return nil
+	}
+
+	compressedBytes, err := compressLogSteps(steps)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(path, compressedBytes)
+}
+
+func (c *filesystemClient) GetPipelineBuildLogs(ctx context.Context, buildLog contracts.BuildLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	logPath, err := c.getLogPath(buildLog.RepoSource, buildLog.RepoOwner, buildLog.RepoName, contracts.JobTypeBuild, buildLog.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.getLog(logPath, acceptGzipEncoding, responseWriter)
+}
+
+func (c *filesystemClient) GetPipelineReleaseLogs(ctx context.Context, releaseLog contracts.ReleaseLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	logPath, err := c.getLogPath(releaseLog.RepoSource, releaseLog.RepoOwner, releaseLog.RepoName, contracts.JobTypeRelease, releaseLog.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.getLog(logPath, acceptGzipEncoding, responseWriter)
+}
+
+func (c *filesystemClient) GetPipelineBotLogs(ctx context.Context, botLog contracts.BotLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	logPath, err := c.getLogPath(botLog.RepoSource, botLog.RepoOwner, botLog.RepoName, contracts.JobTypeBot, botLog.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.getLog(logPath, acceptGzipEncoding, responseWriter)
+}
+
+func (c *filesystemClient) getLog(path string, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	file, err := openLogFile(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return writeLog(file, acceptGzipEncoding, responseWriter)
+}
+
+func (c *filesystemClient) GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath, err := c.getLogPath(buildLog.RepoSource, buildLog.RepoOwner, buildLog.RepoName, contracts.JobTypeBuild, buildLog.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getLogSteps(logPath)
+}
+
+func (c *filesystemClient) GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath, err := c.getLogPath(releaseLog.RepoSource, releaseLog.RepoOwner, releaseLog.RepoName, contracts.JobTypeRelease, releaseLog.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getLogSteps(logPath)
+}
+
+func (c *filesystemClient) GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath, err := c.getLogPath(botLog.RepoSource, botLog.RepoOwner, botLog.RepoName, contracts.JobTypeBot, botLog.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getLogSteps(logPath)
+}
+
+func (c *filesystemClient) getLogSteps(path string) (steps []*contracts.BuildLogStep, err error) {
+
+	file, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readLogSteps(file, path)
+}
+
+func (c *filesystemClient) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) (err error) {
+
+	fromLogDirectory, err := c.getLogDirectory(fromRepoSource, fromRepoOwner, fromRepoName, "")
+	if err != nil {
+		return err
+	}
+	toLogDirectory, err := c.getLogDirectory(toRepoSource, toRepoOwner, toRepoName, "")
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Renaming filesystem logs from %v to %v", fromLogDirectory, toLogDirectory)
+
+	// move file by file, so logs already present at the new location are merged with the moved ones
+	err = filepath.WalkDir(fromLogDirectory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(fromLogDirectory, path)
+		if err != nil {
+			return err
+		}
+		toLogFilePath := filepath.Join(toLogDirectory, relativePath)
+
+		err = os.MkdirAll(filepath.Dir(toLogFilePath), 0755)
+		if err != nil {
+			return err
+		}
+
+		return os.Rename(path, toLogFilePath)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed renaming logs from %v to %v: %w", fromLogDirectory, toLogDirectory, err)
+	}
+
+	return os.RemoveAll(fromLogDirectory)
+}
+
+func (c *filesystemClient) DeleteLogs(ctx context.Context, repoSource, repoOwner, repoName string) (err error) {
+
+	logDirectory, err := c.getLogDirectory(repoSource, repoOwner, repoName, "")
+	if err != nil {
+		return err
+	}
+
+	log.Debug().Msgf("Deleting filesystem logs in %s", logDirectory)
+
+	return os.RemoveAll(logDirectory)
+}
+
+func (c *filesystemClient) CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	return c.copyPipelineLogs(fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, contracts.JobTypeBuild, logIDs)
+}
+
+func (c *filesystemClient) CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	return c.copyPipelineLogs(fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, contracts.JobTypeRelease, logIDs)
+}
+
+func (c *filesystemClient) CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) (err error) {
+	return c.copyPipelineLogs(repoSource, repoOwner, repoName, repoSource, repoOwner, repoName, jobType, logIDs)
+}
+
+// copyPipelineLogs copies the log files for the original ids in logIDs to files named after the new ids, leaving the original files in place
+func (c *filesystemClient) copyPipelineLogs(fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, jobType contracts.JobType, logIDs map[string]string) (err error) {
+
+	for fromID, toID := range logIDs {
+		fromLogFilePath, err := c.getLogPath(fromRepoSource, fromRepoOwner, fromRepoName, jobType, fromID)
+		if err != nil {
+			return err
+		}
+		toLogFilePath, err := c.getLogPath(toRepoSource, toRepoOwner, toRepoName, jobType, toID)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(fromLogFilePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			// logs written before object storage got enabled only exist in the database
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading log %v: %w", fromLogFilePath, err)
+		}
+
+		err = writeFileAtomically(toLogFilePath, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PruneLogs deletes the log files of a job type last modified before a point in time, except for the logs with ids in keepLogIDs; with dryRun set it only counts them
+func (c *filesystemClient) PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (count int, bytes int64, err error) {
+
+	logDirectory, err := c.getLogDirectory(repoSource, repoOwner, repoName, getLogType(jobType))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	keep := map[string]bool{}
+	for _, id := range keepLogIDs {
+		keep[id] = true
+	}
+
+	entries, err := os.ReadDir(logDirectory)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed listing logs in %v: %w", logDirectory, err)
+	}
+
+	for _, entry := range entries {
+		logID, ok := getLogID(entry.Name())
+		if entry.IsDir() || !ok || keep[logID] {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return count, bytes, err
+		}
+		if !info.ModTime().Before(createdBefore) {
+			continue
+		}
+
+		if !dryRun {
+			err = os.Remove(filepath.Join(logDirectory, entry.Name()))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return count, bytes, fmt.Errorf("failed deleting log %v: %w", entry.Name(), err)
+			}
+		}
+
+		count++
+		bytes += info.Size()
+	}
+
+	return count, bytes, nil
+}
+
+// GetLogIDs returns the ids of all log files stored for a job type of a pipeline
+func (c *filesystemClient) GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) (logIDs []string, err error) {
+
+	logDirectory, err := c.getLogDirectory(repoSource, repoOwner, repoName, getLogType(jobType))
+	if err != nil {
+		return nil, err
+	}
+
+	logIDs = []string{}
+
+	entries, err := os.ReadDir(logDirectory)
+	if errors.Is(err, fs.ErrNotExist) {
+		return logIDs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed listing logs in %v: %w", logDirectory, err)
+	}
+
+	for _, entry := range entries {
+		if logID, ok := getLogID(entry.Name()); ok && !entry.IsDir() {
+			logIDs = append(logIDs, logID)
+		}
+	}
+
+	return logIDs, nil
+}
+
+func (c *filesystemClient) getLogPath(repoSource, repoOwner, repoName string, jobType contracts.JobType, logID string) (logPath string, err error) {
+	return c.withinLogsDirectory(getLogPath(c.config.Integrations.Filesystem.LogsDirectory, repoSource, repoOwner, repoName, getLogType(jobType), logID))
+}
+
+func (c *filesystemClient) getLogDirectory(repoSource, repoOwner, repoName, logType string) (logDirectory string, err error) {
+	return c.withinLogsDirectory(getLogDirectory(c.config.Integrations.Filesystem.LogsDirectory, repoSource, repoOwner, repoName, logType))
+}
+
+// withinLogsDirectory guards against repository names escaping the logs directory
+func (c *filesystemClient) withinLogsDirectory(path string) (string, error) {
+	logsDirectory := filepath.Clean(c.config.Integrations.Filesystem.LogsDirectory)
+	path = filepath.Clean(path)
+	if path == logsDirectory || !strings.HasPrefix(path, logsDirectory+string(filepath.Separator)) {
+		return "", fmt.Errorf("log path %v is outside of logs directory %v", path, logsDirectory)
+	}
+	return path, nil
+}
+
+func openLogFile(path string) (file *os.File, err error) {
+	file, err = os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrLogNotExist
+	}
+	return file, err
+}
+
+// writeFileAtomically writes to a temporary file first and renames it afterwards, so readers never see a partially written log
+func writeFileAtomically(path string, data []byte) (err error) {
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(data)
+	if err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed writing log %v: %w", path, err)
+	}
+	err = tempFile.Close()
+	if err != nil {
+		return fmt.Errorf("failed writing log %v: %w", path, err)
+	}
+
+	return os.Rename(tempFile.Name(), path)
+}
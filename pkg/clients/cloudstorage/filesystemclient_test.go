@@ -0,0 +1,142 @@
+package cloudstorage
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+func TestFilesystemClient(t *testing.T) {
+
+	buildLog := contracts.BuildLog{
+		ID:         "15",
+		RepoSource: "github.com",
+		RepoOwner:  "ziplineeci",
+		RepoName:   "ziplinee-ci-api",
+		Steps: []*contracts.BuildLogStep{
+			{
+				Step: "build",
+				LogLines: []contracts.BuildLogLine{
+					{LineNumber: 1, Text: "ok", StreamType: "stdout"},
+				},
+			},
+		},
+	}
+
+	t.Run("InsertedLogCanBeRetrievedAsSteps", func(t *testing.T) {
+
+		client := getFilesystemClient(t)
+
+		// act
+		err := client.InsertBuildLog(context.Background(), buildLog)
+
+		assert.Nil(t, err)
+		steps, err := client.GetPipelineBuildLogSteps(context.Background(), buildLog)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(steps)) {
+			assert.Equal(t, "build", steps[0].Step)
+			assert.Equal(t, "ok", steps[0].LogLines[0].Text)
+		}
+	})
+
+	t.Run("InsertedLogIsPassedThroughGzippedIfAccepted", func(t *testing.T) {
+
+		client := getFilesystemClient(t)
+		err := client.InsertBuildLog(context.Background(), buildLog)
+		assert.Nil(t, err)
+		recorder := httptest.NewRecorder()
+
+		// act
+		err = client.GetPipelineBuildLogs(context.Background(), buildLog, true, recorder)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("ReturnsErrLogNotExistForMissingLog", func(t *testing.T) {
+
+		client := getFilesystemClient(t)
+
+		// act
+		_, err := client.GetPipelineBuildLogSteps(context.Background(), buildLog)
+
+		assert.ErrorIs(t, err, ErrLogNotExist)
+	})
+
+	t.Run("ReturnsErrorForPathOutsideLogsDirectory", func(t *testing.T) {
+
+		client := getFilesystemClient(t)
+		escapingLog := buildLog
+		escapingLog.RepoOwner = ".."
+		escapingLog.RepoName = ".."
+		escapingLog.RepoSource = ".."
+
+		// act
+		err := client.InsertBuildLog(context.Background(), escapingLog)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("RenameMovesLogsToNewPipeline", func(t *testing.T) {
+
+		client := getFilesystemClient(t)
+		err := client.InsertBuildLog(context.Background(), buildLog)
+		assert.Nil(t, err)
+
+		// act
+		err = client.Rename(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", "github.com", "ziplineeci", "ziplinee-ci-server")
+
+		assert.Nil(t, err)
+		logIDs, err := client.GetLogIDs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-server", contracts.JobTypeBuild)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"15"}, logIDs)
+		logIDs, err = client.GetLogIDs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(logIDs))
+	})
+
+	t.Run("PruneLogsDeletesOldLogsExceptKeptOnes", func(t *testing.T) {
+
+		client := getFilesystemClient(t)
+		for _, id := range []string{"15", "16", "17"} {
+			l := buildLog
+			l.ID = id
+			err := client.InsertBuildLog(context.Background(), l)
+			assert.Nil(t, err)
+		}
+		logsDirectory := client.(*filesystemClient).config.Integrations.Filesystem.LogsDirectory
+		old := time.Now().AddDate(0, 0, -10)
+		for _, id := range []string{"15", "16"} {
+			err := os.Chtimes(filepath.Join(logsDirectory, "github.com", "ziplineeci", "ziplinee-ci-api", "builds", id+".log"), old, old)
+			assert.Nil(t, err)
+		}
+
+		// act
+		count, bytes, err := client.PruneLogs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild, time.Now().AddDate(0, 0, -5), []string{"16"}, 100, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+		assert.True(t, bytes > 0)
+		logIDs, err := client.GetLogIDs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"16", "17"}, logIDs)
+	})
+}
+
+func getFilesystemClient(t *testing.T) Client {
+	return NewFilesystemClient(&api.APIConfig{
+		Integrations: &api.APIConfigIntegrations{
+			Filesystem: &api.FilesystemConfig{
+				Enable:        true,
+				LogsDirectory: t.TempDir(),
+			},
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package cloudstorage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+// compressLogSteps marshals log steps to json and gzips them, the format logs are stored in by all object storage backends
+func compressLogSteps(steps []*contracts.BuildLogStep) (compressedBytes []byte, err error) {
+
+	jsonBytes, err := json.Marshal(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buffer, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	_, err = gz.Write(jsonBytes)
+	if err != nil {
+		_ = gz.Close()
+		return nil, err
+	}
+	err = gz.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// writeLog copies a gzipped log to the response as is if the client accepts gzip encoding, or decompresses it first otherwise
+func writeLog(reader io.Reader, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	// create source reader to either copy compressed bytes or decompress them first
+	sourceReader := reader
+	if acceptGzipEncoding {
+		responseWriter.Header().Set("Content-Encoding", "gzip")
+		responseWriter.Header().Set("Vary", "Accept-Encoding")
+	} else {
+		gzr, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzr.Close()
+		sourceReader = io.Reader(gzr)
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	writtenBytes, err := io.Copy(responseWriter, sourceReader)
+	if err != nil {
+		return err
+	}
+
+	responseWriter.Header().Set("Content-Length", fmt.Sprint(writtenBytes))
+
+	return nil
+}
+
+// readLogSteps decompresses a gzipped log and unmarshals it into its steps, for inspecting logs server-side
+func readLogSteps(reader io.Reader, path string) (steps []*contracts.BuildLogStep, err error) {
+
+	gzr, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gzr.Close()
+
+	err = json.NewDecoder(gzr).Decode(&steps)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling log %v: %w", path, err)
+	}
+
+	return steps, nil
+}
+
+// getLogDirectory returns the directory holding the logs of one type of a pipeline, or all its logs for an empty log type
+func getLogDirectory(logsDirectory, repoSource, repoOwner, repoName, logType string) (logDirectory string) {
+	return path.Join(logsDirectory, repoSource, repoOwner, repoName, logType) + "/"
+}
+
+// getLogPath returns the path of the log file for a log id
+func getLogPath(logsDirectory, repoSource, repoOwner, repoName, logType, logID string) (logPath string) {
+	return path.Join(logsDirectory, repoSource, repoOwner, repoName, logType, fmt.Sprintf("%v.log", logID))
+}
+
+// getLogType returns the directory name for the logs of a job type
+func getLogType(jobType contracts.JobType) string {
+	return string(jobType) + "s"
+}
+
+// getLogID returns the log id for a log file name, or false if it isn't a log file
+func getLogID(logFileName string) (logID string, ok bool) {
+	logFileName = path.Base(logFileName)
+	if path.Ext(logFileName) != ".log" {
+		return "", false
+	}
+	return strings.TrimSuffix(logFileName, ".log"), true
+}
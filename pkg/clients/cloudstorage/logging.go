@@ -3,6 +3,7 @@ package cloudstorage
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
@@ -65,3 +66,51 @@ func (c *loggingClient) Rename(ctx context.Context, fromRepoSource, fromRepoOwne
 
 	return c.Client.Rename(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName)
 }
+
+func (c *loggingClient) CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "CopyBuildLogs", err) }()
+
+	return c.Client.CopyBuildLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
+func (c *loggingClient) CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "CopyReleaseLogs", err) }()
+
+	return c.Client.CopyReleaseLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
+func (c *loggingClient) GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) (steps []*contracts.BuildLogStep, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPipelineBuildLogSteps", err) }()
+
+	return c.Client.GetPipelineBuildLogSteps(ctx, buildLog)
+}
+
+func (c *loggingClient) GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) (steps []*contracts.BuildLogStep, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPipelineReleaseLogSteps", err) }()
+
+	return c.Client.GetPipelineReleaseLogSteps(ctx, releaseLog)
+}
+
+func (c *loggingClient) GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) (steps []*contracts.BuildLogStep, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPipelineBotLogSteps", err) }()
+
+	return c.Client.GetPipelineBotLogSteps(ctx, botLog)
+}
+
+func (c *loggingClient) PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (count int, bytes int64, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "PruneLogs", err) }()
+
+	return c.Client.PruneLogs(ctx, repoSource, repoOwner, repoName, jobType, createdBefore, keepLogIDs, batchSize, dryRun)
+}
+
+func (c *loggingClient) GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) (logIDs []string, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetLogIDs", err) }()
+
+	return c.Client.GetLogIDs(ctx, repoSource, repoOwner, repoName, jobType)
+}
+
+func (c *loggingClient) CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "CopyLogs", err) }()
+
+	return c.Client.CopyLogs(ctx, repoSource, repoOwner, repoName, jobType, logIDs)
+}
@@ -84,3 +84,67 @@ func (c *metricsClient) Rename(ctx context.Context, fromRepoSource, fromRepoOwne
 
 	return c.Client.Rename(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName)
 }
+
+func (c *metricsClient) CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "CopyBuildLogs", begin)
+	}(time.Now())
+
+	return c.Client.CopyBuildLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
+func (c *metricsClient) CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "CopyReleaseLogs", begin)
+	}(time.Now())
+
+	return c.Client.CopyReleaseLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
+func (c *metricsClient) GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) (steps []*contracts.BuildLogStep, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPipelineBuildLogSteps", begin)
+	}(time.Now())
+
+	return c.Client.GetPipelineBuildLogSteps(ctx, buildLog)
+}
+
+func (c *metricsClient) GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) (steps []*contracts.BuildLogStep, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPipelineReleaseLogSteps", begin)
+	}(time.Now())
+
+	return c.Client.GetPipelineReleaseLogSteps(ctx, releaseLog)
+}
+
+func (c *metricsClient) GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) (steps []*contracts.BuildLogStep, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPipelineBotLogSteps", begin)
+	}(time.Now())
+
+	return c.Client.GetPipelineBotLogSteps(ctx, botLog)
+}
+
+func (c *metricsClient) PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (count int, bytes int64, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "PruneLogs", begin)
+	}(time.Now())
+
+	return c.Client.PruneLogs(ctx, repoSource, repoOwner, repoName, jobType, createdBefore, keepLogIDs, batchSize, dryRun)
+}
+
+func (c *metricsClient) GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) (logIDs []string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetLogIDs", begin)
+	}(time.Now())
+
+	return c.Client.GetLogIDs(ctx, repoSource, repoOwner, repoName, jobType)
+}
+
+func (c *metricsClient) CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "CopyLogs", begin)
+	}(time.Now())
+
+	return c.Client.CopyLogs(ctx, repoSource, repoOwner, repoName, jobType, logIDs)
+}
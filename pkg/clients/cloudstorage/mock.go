@@ -8,6 +8,7 @@ import (
 	context "context"
 	http "net/http"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
@@ -36,6 +37,48 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CopyBuildLogs mocks base method.
+func (m *MockClient) CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyBuildLogs", ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyBuildLogs indicates an expected call of CopyBuildLogs.
+func (mr *MockClientMockRecorder) CopyBuildLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyBuildLogs", reflect.TypeOf((*MockClient)(nil).CopyBuildLogs), ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
+// CopyLogs mocks base method.
+func (m *MockClient) CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyLogs", ctx, repoSource, repoOwner, repoName, jobType, logIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyLogs indicates an expected call of CopyLogs.
+func (mr *MockClientMockRecorder) CopyLogs(ctx, repoSource, repoOwner, repoName, jobType, logIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyLogs", reflect.TypeOf((*MockClient)(nil).CopyLogs), ctx, repoSource, repoOwner, repoName, jobType, logIDs)
+}
+
+// CopyReleaseLogs mocks base method.
+func (m *MockClient) CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyReleaseLogs", ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyReleaseLogs indicates an expected call of CopyReleaseLogs.
+func (mr *MockClientMockRecorder) CopyReleaseLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyReleaseLogs", reflect.TypeOf((*MockClient)(nil).CopyReleaseLogs), ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
 // DeleteLogs mocks base method.
 func (m *MockClient) DeleteLogs(ctx context.Context, repoSource, repoOwner, repoName string) error {
 	m.ctrl.T.Helper()
@@ -50,6 +93,36 @@ func (mr *MockClientMockRecorder) DeleteLogs(ctx, repoSource, repoOwner, repoNam
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogs", reflect.TypeOf((*MockClient)(nil).DeleteLogs), ctx, repoSource, repoOwner, repoName)
 }
 
+// GetLogIDs mocks base method.
+func (m *MockClient) GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogIDs", ctx, repoSource, repoOwner, repoName, jobType)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogIDs indicates an expected call of GetLogIDs.
+func (mr *MockClientMockRecorder) GetLogIDs(ctx, repoSource, repoOwner, repoName, jobType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogIDs", reflect.TypeOf((*MockClient)(nil).GetLogIDs), ctx, repoSource, repoOwner, repoName, jobType)
+}
+
+// GetPipelineBotLogSteps mocks base method.
+func (m *MockClient) GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) ([]*contracts.BuildLogStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineBotLogSteps", ctx, botLog)
+	ret0, _ := ret[0].([]*contracts.BuildLogStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineBotLogSteps indicates an expected call of GetPipelineBotLogSteps.
+func (mr *MockClientMockRecorder) GetPipelineBotLogSteps(ctx, botLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotLogSteps", reflect.TypeOf((*MockClient)(nil).GetPipelineBotLogSteps), ctx, botLog)
+}
+
 // GetPipelineBotLogs mocks base method.
 func (m *MockClient) GetPipelineBotLogs(ctx context.Context, botLog contracts.BotLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) error {
 	m.ctrl.T.Helper()
@@ -64,6 +137,21 @@ func (mr *MockClientMockRecorder) GetPipelineBotLogs(ctx, botLog, acceptGzipEnco
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotLogs", reflect.TypeOf((*MockClient)(nil).GetPipelineBotLogs), ctx, botLog, acceptGzipEncoding, responseWriter)
 }
 
+// GetPipelineBuildLogSteps mocks base method.
+func (m *MockClient) GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) ([]*contracts.BuildLogStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineBuildLogSteps", ctx, buildLog)
+	ret0, _ := ret[0].([]*contracts.BuildLogStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineBuildLogSteps indicates an expected call of GetPipelineBuildLogSteps.
+func (mr *MockClientMockRecorder) GetPipelineBuildLogSteps(ctx, buildLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBuildLogSteps", reflect.TypeOf((*MockClient)(nil).GetPipelineBuildLogSteps), ctx, buildLog)
+}
+
 // GetPipelineBuildLogs mocks base method.
 func (m *MockClient) GetPipelineBuildLogs(ctx context.Context, buildLog contracts.BuildLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) error {
 	m.ctrl.T.Helper()
@@ -78,6 +166,21 @@ func (mr *MockClientMockRecorder) GetPipelineBuildLogs(ctx, buildLog, acceptGzip
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBuildLogs", reflect.TypeOf((*MockClient)(nil).GetPipelineBuildLogs), ctx, buildLog, acceptGzipEncoding, responseWriter)
 }
 
+// GetPipelineReleaseLogSteps mocks base method.
+func (m *MockClient) GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) ([]*contracts.BuildLogStep, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineReleaseLogSteps", ctx, releaseLog)
+	ret0, _ := ret[0].([]*contracts.BuildLogStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineReleaseLogSteps indicates an expected call of GetPipelineReleaseLogSteps.
+func (mr *MockClientMockRecorder) GetPipelineReleaseLogSteps(ctx, releaseLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineReleaseLogSteps", reflect.TypeOf((*MockClient)(nil).GetPipelineReleaseLogSteps), ctx, releaseLog)
+}
+
 // GetPipelineReleaseLogs mocks base method.
 func (m *MockClient) GetPipelineReleaseLogs(ctx context.Context, releaseLog contracts.ReleaseLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) error {
 	m.ctrl.T.Helper()
@@ -134,6 +237,22 @@ func (mr *MockClientMockRecorder) InsertReleaseLog(ctx, releaseLog interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReleaseLog", reflect.TypeOf((*MockClient)(nil).InsertReleaseLog), ctx, releaseLog)
 }
 
+// PruneLogs mocks base method.
+func (m *MockClient) PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (int, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneLogs", ctx, repoSource, repoOwner, repoName, jobType, createdBefore, keepLogIDs, batchSize, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PruneLogs indicates an expected call of PruneLogs.
+func (mr *MockClientMockRecorder) PruneLogs(ctx, repoSource, repoOwner, repoName, jobType, createdBefore, keepLogIDs, batchSize, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneLogs", reflect.TypeOf((*MockClient)(nil).PruneLogs), ctx, repoSource, repoOwner, repoName, jobType, createdBefore, keepLogIDs, batchSize, dryRun)
+}
+
 // Rename mocks base method.
 func (m *MockClient) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,388 @@
+package cloudstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/pool"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+	foundation "github.com/ziplineeci/ziplinee-foundation"
+)
+
+// NewS3Client returns a cloudstorage.Client storing logs in an s3 compatible bucket, like aws s3 or minio
+func NewS3Client(config *api.APIConfig, minioClient *minio.Client) Client {
+	return &s3Client{
+		client: minioClient,
+		config: config,
+	}
+}
+
+type s3Client struct {
+	client *minio.Client
+	config *api.APIConfig
+}
+
+func (c *s3Client) InsertBuildLog(ctx context.Context, buildLog contracts.BuildLog) (err error) {
+
+	logPath := c.getLogPath(buildLog.RepoSource, buildLog.RepoOwner, buildLog.RepoName, contracts.JobTypeBuild, buildLog.ID)
+
+	return foundation.Retry(func() error {
+		return c.insertLog(ctx, logPath, buildLog.Steps)
+	})
+}
+
+func (c *s3Client) InsertReleaseLog(ctx context.Context, releaseLog contracts.ReleaseLog) (err error) {
+
+	logPath := c.getLogPath(releaseLog.RepoSource, releaseLog.RepoOwner, releaseLog.RepoName, contracts.JobTypeRelease, releaseLog.ID)
+
+	return foundation.Retry(func() error {
+		return c.insertLog(ctx, logPath, releaseLog.Steps)
+	})
+}
+
+func (c *s3Client) InsertBotLog(ctx context.Context, botLog contracts.BotLog) (err error) {
+
+	logPath := c.getLogPath(botLog.RepoSource, botLog.RepoOwner, botLog.RepoName, contracts.JobTypeBot, botLog.ID)
+
+	return foundation.Retry(func() error {
+		return c.insertLog(ctx, logPath, botLog.Steps)
+	})
+}
+
+func (c *s3Client) insertLog(ctx context.Context, path string, steps []*contracts.BuildLogStep) (err error) {
+
+	bucket := c.config.Integrations.S3Storage.Bucket
+
+	// don't allow overwrites, return when file already exists
+	_, err = c.client.StatObject(ctx, bucket, path, minio.StatObjectOptions{})
+	if err == nil {
+		return nil
+	}
+	if !isS3NotFound(err) {
+		return err
+	}
+
+	compressedBytes, err := compressLogSteps(steps)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.PutObject(ctx, bucket, path, bytes.NewReader(compressedBytes), int64(len(compressedBytes)), minio.PutObjectOptions{ContentType: "application/gzip"})
+	if err != nil {
+		return fmt.Errorf("failed writing log %v: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *s3Client) GetPipelineBuildLogs(ctx context.Context, buildLog contracts.BuildLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	logPath := c.getLogPath(buildLog.RepoSource, buildLog.RepoOwner, buildLog.RepoName, contracts.JobTypeBuild, buildLog.ID)
+
+	return c.getLog(ctx, logPath, acceptGzipEncoding, responseWriter)
+}
+
+func (c *s3Client) GetPipelineReleaseLogs(ctx context.Context, releaseLog contracts.ReleaseLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	logPath := c.getLogPath(releaseLog.RepoSource, releaseLog.RepoOwner, releaseLog.RepoName, contracts.JobTypeRelease, releaseLog.ID)
+
+	return c.getLog(ctx, logPath, acceptGzipEncoding, responseWriter)
+}
+
+func (c *s3Client) GetPipelineBotLogs(ctx context.Context, botLog contracts.BotLog, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	logPath := c.getLogPath(botLog.RepoSource, botLog.RepoOwner, botLog.RepoName, contracts.JobTypeBot, botLog.ID)
+
+	return c.getLog(ctx, logPath, acceptGzipEncoding, responseWriter)
+}
+
+func (c *s3Client) getLog(ctx context.Context, path string, acceptGzipEncoding bool, responseWriter http.ResponseWriter) (err error) {
+
+	object, err := c.getObject(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	return writeLog(object, acceptGzipEncoding, responseWriter)
+}
+
+func (c *s3Client) GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath := c.getLogPath(buildLog.RepoSource, buildLog.RepoOwner, buildLog.RepoName, contracts.JobTypeBuild, buildLog.ID)
+
+	return c.getLogSteps(ctx, logPath)
+}
+
+func (c *s3Client) GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath := c.getLogPath(releaseLog.RepoSource, releaseLog.RepoOwner, releaseLog.RepoName, contracts.JobTypeRelease, releaseLog.ID)
+
+	return c.getLogSteps(ctx, logPath)
+}
+
+func (c *s3Client) GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) (steps []*contracts.BuildLogStep, err error) {
+
+	logPath := c.getLogPath(botLog.RepoSource, botLog.RepoOwner, botLog.RepoName, contracts.JobTypeBot, botLog.ID)
+
+	return c.getLogSteps(ctx, logPath)
+}
+
+func (c *s3Client) getLogSteps(ctx context.Context, path string) (steps []*contracts.BuildLogStep, err error) {
+
+	object, err := c.getObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return readLogSteps(object, path)
+}
+
+// getObject opens an object for reading; the object is stat-ed first, because a missing object only surfaces on the first read otherwise
+func (c *s3Client) getObject(ctx context.Context, path string) (object *minio.Object, err error) {
+
+	object, err = c.client.GetObject(ctx, c.config.Integrations.S3Storage.Bucket, path, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = object.Stat()
+	if err != nil {
+		_ = object.Close()
+		if isS3NotFound(err) {
+			return nil, ErrLogNotExist
+		}
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func (c *s3Client) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) (err error) {
+
+	log.Debug().Msgf("Renaming s3 logs from %v/%v/%v to %v/%v/%v for bucket %v", fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, c.config.Integrations.S3Storage.Bucket)
+
+	// list all log files in old location, copy them to the new location and delete the originals
+	fromLogDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, fromRepoSource, fromRepoOwner, fromRepoName, "")
+	toLogDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, toRepoSource, toRepoOwner, toRepoName, "")
+
+	bucket := c.config.Integrations.S3Storage.Bucket
+
+	for object := range c.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: fromLogDirectory, Recursive: true}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed listing logs in %v: %w", fromLogDirectory, object.Err)
+		}
+
+		toLogFilePath := strings.Replace(object.Key, fromLogDirectory, toLogDirectory, 1)
+
+		_, err = c.client.CopyObject(ctx, minio.CopyDestOptions{Bucket: bucket, Object: toLogFilePath}, minio.CopySrcOptions{Bucket: bucket, Object: object.Key})
+		if err != nil {
+			return fmt.Errorf("failed copying log %v to %v: %w", object.Key, toLogFilePath, err)
+		}
+		err = c.client.RemoveObject(ctx, bucket, object.Key, minio.RemoveObjectOptions{})
+		if err != nil {
+			return fmt.Errorf("failed deleting log %v: %w", object.Key, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *s3Client) DeleteLogs(ctx context.Context, repoSource, repoOwner, repoName string) (err error) {
+
+	logDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, repoSource, repoOwner, repoName, "")
+	log.Debug().Msgf("Deleting s3 logs in %s", logDirectory)
+
+	logFilePaths := []string{}
+	for object := range c.client.ListObjects(ctx, c.config.Integrations.S3Storage.Bucket, minio.ListObjectsOptions{Prefix: logDirectory, Recursive: true}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed listing logs in %v: %w", logDirectory, object.Err)
+		}
+		logFilePaths = append(logFilePaths, object.Key)
+	}
+
+	return c.deleteLogs(ctx, logFilePaths)
+}
+
+func (c *s3Client) CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+
+	fromLogDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, fromRepoSource, fromRepoOwner, fromRepoName, getLogType(contracts.JobTypeBuild))
+	toLogDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, toRepoSource, toRepoOwner, toRepoName, getLogType(contracts.JobTypeBuild))
+
+	return c.copyLogs(ctx, fromLogDirectory, toLogDirectory, logIDs)
+}
+
+func (c *s3Client) CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+
+	fromLogDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, fromRepoSource, fromRepoOwner, fromRepoName, getLogType(contracts.JobTypeRelease))
+	toLogDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, toRepoSource, toRepoOwner, toRepoName, getLogType(contracts.JobTypeRelease))
+
+	return c.copyLogs(ctx, fromLogDirectory, toLogDirectory, logIDs)
+}
+
+func (c *s3Client) CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) (err error) {
+
+	logDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, repoSource, repoOwner, repoName, getLogType(jobType))
+
+	return c.copyLogs(ctx, logDirectory, logDirectory, logIDs)
+}
+
+// copyLogs copies the log files for the original ids in logIDs to files named after the new ids, leaving the original files in place
+func (c *s3Client) copyLogs(ctx context.Context, fromLogDirectory, toLogDirectory string, logIDs map[string]string) (err error) {
+
+	log.Debug().Msgf("Copying %v s3 logs from %v to %v", len(logIDs), fromLogDirectory, toLogDirectory)
+
+	bucket := c.config.Integrations.S3Storage.Bucket
+
+	worker := func(ctx context.Context, job [2]string) (bool, error) {
+		fromLogFilePath := fromLogDirectory + fmt.Sprintf("%v.log", job[0])
+		toLogFilePath := toLogDirectory + fmt.Sprintf("%v.log", job[1])
+
+		_, err := c.client.CopyObject(ctx, minio.CopyDestOptions{Bucket: bucket, Object: toLogFilePath}, minio.CopySrcOptions{Bucket: bucket, Object: fromLogFilePath})
+		if isS3NotFound(err) {
+			// logs written before object storage got enabled only exist in the database
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed copying log %v to %v: %w", fromLogFilePath, toLogFilePath, err)
+		}
+		return true, nil
+	}
+	p, err := pool.NewPool(ctx, pool.DefaultConfig(20, worker))
+	if err != nil {
+		return fmt.Errorf("failed creating pool: %w", err)
+	}
+	for fromID, toID := range logIDs {
+		p.SendJobs([2]string{fromID, toID})
+	}
+	for range p.Close() {
+	}
+
+	jobErrs := p.Errors()
+	if len(jobErrs) > 0 {
+		for _, jobErr := range jobErrs {
+			log.Error().Err(jobErr.Err).Send()
+		}
+		return fmt.Errorf("failed copying %v of %v logs from %v to %v", len(jobErrs), len(logIDs), fromLogDirectory, toLogDirectory)
+	}
+
+	return nil
+}
+
+// PruneLogs deletes the log files of a job type last modified before a point in time, except for the logs with ids in keepLogIDs; with dryRun set it only counts them
+func (c *s3Client) PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (count int, bytes int64, err error) {
+
+	logDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, repoSource, repoOwner, repoName, getLogType(jobType))
+
+	keep := map[string]bool{}
+	for _, id := range keepLogIDs {
+		keep[id] = true
+	}
+
+	// log files are never modified after being written, so their last modified time is the time they were created
+	batch := []string{}
+	for object := range c.client.ListObjects(ctx, c.config.Integrations.S3Storage.Bucket, minio.ListObjectsOptions{Prefix: logDirectory, Recursive: true}) {
+		if object.Err != nil {
+			return count, bytes, fmt.Errorf("failed listing logs in %v: %w", logDirectory, object.Err)
+		}
+		logID, ok := getLogID(object.Key)
+		if !ok || !object.LastModified.Before(createdBefore) || keep[logID] {
+			continue
+		}
+
+		count++
+		bytes += object.Size
+
+		if dryRun {
+			continue
+		}
+
+		batch = append(batch, object.Key)
+		if len(batch) >= batchSize {
+			err = c.deleteLogs(ctx, batch)
+			if err != nil {
+				return count, bytes, err
+			}
+			batch = []string{}
+		}
+	}
+
+	if len(batch) > 0 {
+		err = c.deleteLogs(ctx, batch)
+		if err != nil {
+			return count, bytes, err
+		}
+	}
+
+	return count, bytes, nil
+}
+
+// GetLogIDs returns the ids of all log files stored for a job type of a pipeline
+func (c *s3Client) GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) (logIDs []string, err error) {
+
+	logDirectory := getLogDirectory(c.config.Integrations.S3Storage.LogsDirectory, repoSource, repoOwner, repoName, getLogType(jobType))
+
+	logIDs = []string{}
+	for object := range c.client.ListObjects(ctx, c.config.Integrations.S3Storage.Bucket, minio.ListObjectsOptions{Prefix: logDirectory, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed listing logs in %v: %w", logDirectory, object.Err)
+		}
+		if logID, ok := getLogID(object.Key); ok {
+			logIDs = append(logIDs, logID)
+		}
+	}
+
+	return logIDs, nil
+}
+
+// deleteLogs removes log files with multi-object delete requests
+func (c *s3Client) deleteLogs(ctx context.Context, logFilePaths []string) (err error) {
+
+	log.Debug().Msgf("Deleting %v s3 logs", len(logFilePaths))
+
+	objectsChannel := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectsChannel)
+		for _, p := range logFilePaths {
+			objectsChannel <- minio.ObjectInfo{Key: p}
+		}
+	}()
+
+	failed := 0
+	for removeErr := range c.client.RemoveObjects(ctx, c.config.Integrations.S3Storage.Bucket, objectsChannel, minio.RemoveObjectsOptions{}) {
+		if isS3NotFound(removeErr.Err) {
+			continue
+		}
+		log.Error().Err(removeErr.Err).Msgf("Failed deleting log %v", removeErr.ObjectName)
+		failed++
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed deleting %v of %v logs", failed, len(logFilePaths))
+	}
+
+	return nil
+}
+
+func (c *s3Client) getLogPath(repoSource, repoOwner, repoName string, jobType contracts.JobType, logID string) (logPath string) {
+	return getLogPath(c.config.Integrations.S3Storage.LogsDirectory, repoSource, repoOwner, repoName, getLogType(jobType), logID)
+}
+
+func isS3NotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var errorResponse minio.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.Code == "NoSuchKey" || errorResponse.StatusCode == http.StatusNotFound
+	}
+	return false
+}
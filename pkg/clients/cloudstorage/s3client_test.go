@@ -0,0 +1,374 @@
+package cloudstorage
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+func TestS3Client(t *testing.T) {
+
+	buildLog := contracts.BuildLog{
+		ID:         "15",
+		RepoSource: "github.com",
+		RepoOwner:  "ziplineeci",
+		RepoName:   "ziplinee-ci-api",
+		Steps: []*contracts.BuildLogStep{
+			{
+				Step: "build",
+				LogLines: []contracts.BuildLogLine{
+					{LineNumber: 1, Text: "ok", StreamType: "stdout"},
+				},
+			},
+		},
+	}
+
+	t.Run("InsertedLogCanBeRetrievedAsSteps", func(t *testing.T) {
+
+		client, server := getS3Client(t)
+
+		// act
+		err := client.InsertBuildLog(context.Background(), buildLog)
+
+		assert.Nil(t, err)
+		assert.Contains(t, server.getKeys(), "logs/github.com/ziplineeci/ziplinee-ci-api/builds/15.log")
+		steps, err := client.GetPipelineBuildLogSteps(context.Background(), buildLog)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(steps)) {
+			assert.Equal(t, "build", steps[0].Step)
+			assert.Equal(t, "ok", steps[0].LogLines[0].Text)
+		}
+	})
+
+	t.Run("InsertedLogIsPassedThroughGzippedIfAccepted", func(t *testing.T) {
+
+		client, _ := getS3Client(t)
+		err := client.InsertBuildLog(context.Background(), buildLog)
+		assert.Nil(t, err)
+		recorder := httptest.NewRecorder()
+
+		// act
+		err = client.GetPipelineBuildLogs(context.Background(), buildLog, true, recorder)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("InsertDoesNotOverwriteExistingLog", func(t *testing.T) {
+
+		client, _ := getS3Client(t)
+		err := client.InsertBuildLog(context.Background(), buildLog)
+		assert.Nil(t, err)
+		overwritingLog := buildLog
+		overwritingLog.Steps = []*contracts.BuildLogStep{{Step: "overwrite"}}
+
+		// act
+		err = client.InsertBuildLog(context.Background(), overwritingLog)
+
+		assert.Nil(t, err)
+		steps, err := client.GetPipelineBuildLogSteps(context.Background(), buildLog)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(steps)) {
+			assert.Equal(t, "build", steps[0].Step)
+		}
+	})
+
+	t.Run("ReturnsErrLogNotExistForMissingLog", func(t *testing.T) {
+
+		client, _ := getS3Client(t)
+
+		// act
+		_, err := client.GetPipelineBuildLogSteps(context.Background(), buildLog)
+
+		assert.ErrorIs(t, err, ErrLogNotExist)
+	})
+
+	t.Run("RenameMovesLogsToNewPipeline", func(t *testing.T) {
+
+		client, _ := getS3Client(t)
+		err := client.InsertBuildLog(context.Background(), buildLog)
+		assert.Nil(t, err)
+
+		// act
+		err = client.Rename(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", "github.com", "ziplineeci", "ziplinee-ci-server")
+
+		assert.Nil(t, err)
+		logIDs, err := client.GetLogIDs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-server", contracts.JobTypeBuild)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"15"}, logIDs)
+		logIDs, err = client.GetLogIDs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(logIDs))
+	})
+
+	t.Run("CopyLogsSkipsLogsThatOnlyExistInTheDatabase", func(t *testing.T) {
+
+		client, _ := getS3Client(t)
+		err := client.InsertBuildLog(context.Background(), buildLog)
+		assert.Nil(t, err)
+
+		// act
+		err = client.CopyLogs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild, map[string]string{"15": "25", "16": "26"})
+
+		assert.Nil(t, err)
+		logIDs, err := client.GetLogIDs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"15", "25"}, logIDs)
+	})
+
+	t.Run("PruneLogsDeletesOldLogsExceptKeptOnes", func(t *testing.T) {
+
+		client, server := getS3Client(t)
+		for _, id := range []string{"15", "16", "17"} {
+			l := buildLog
+			l.ID = id
+			err := client.InsertBuildLog(context.Background(), l)
+			assert.Nil(t, err)
+		}
+		old := time.Now().AddDate(0, 0, -10)
+		for _, id := range []string{"15", "16"} {
+			server.setLastModified("logs/github.com/ziplineeci/ziplinee-ci-api/builds/"+id+".log", old)
+		}
+
+		// act
+		count, bytes, err := client.PruneLogs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild, time.Now().AddDate(0, 0, -5), []string{"16"}, 100, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+		assert.True(t, bytes > 0)
+		logIDs, err := client.GetLogIDs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"16", "17"}, logIDs)
+	})
+
+	t.Run("DeleteLogsRemovesAllLogsOfPipeline", func(t *testing.T) {
+
+		client, server := getS3Client(t)
+		for _, id := range []string{"15", "16"} {
+			l := buildLog
+			l.ID = id
+			err := client.InsertBuildLog(context.Background(), l)
+			assert.Nil(t, err)
+		}
+		otherLog := buildLog
+		otherLog.RepoName = "ziplinee-ci-web"
+		err := client.InsertBuildLog(context.Background(), otherLog)
+		assert.Nil(t, err)
+
+		// act
+		err = client.DeleteLogs(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"logs/github.com/ziplineeci/ziplinee-ci-web/builds/15.log"}, server.getKeys())
+	})
+}
+
+func getS3Client(t *testing.T) (Client, *fakeS3Server) {
+
+	fakeServer := &fakeS3Server{bucket: "ziplinee-logs", objects: map[string]*fakeS3Object{}}
+	server := httptest.NewTLSServer(fakeServer)
+	t.Cleanup(server.Close)
+
+	endpoint := strings.TrimPrefix(server.URL, "https://")
+	minioClient, err := minio.New(endpoint, &minio.Options{
+		Creds:     credentials.NewStaticV4("access-key", "secret-key", ""),
+		Secure:    true,
+		Region:    "us-east-1",
+		Transport: server.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewS3Client(&api.APIConfig{
+		Integrations: &api.APIConfigIntegrations{
+			S3Storage: &api.S3StorageConfig{
+				Enable:        true,
+				Endpoint:      endpoint,
+				Region:        "us-east-1",
+				Bucket:        fakeServer.bucket,
+				LogsDirectory: "logs",
+			},
+		},
+	}, minioClient)
+
+	return client, fakeServer
+}
+
+// fakeS3Server serves the subset of the s3 api the s3Client uses from memory, for a single path-style bucket
+type fakeS3Server struct {
+	bucket  string
+	mutex   sync.Mutex
+	objects map[string]*fakeS3Object
+}
+
+type fakeS3Object struct {
+	data         []byte
+	lastModified time.Time
+}
+
+func (s *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	bucketPrefix := "/" + s.bucket
+	if !strings.HasPrefix(r.URL.Path, bucketPrefix) {
+		s.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, bucketPrefix), "/")
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		s.listObjects(w, r)
+	case key == "" && r.Method == http.MethodPost && r.URL.Query().Has("delete"):
+		s.deleteObjects(w, r)
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		s.getObject(w, r, key)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		s.copyObject(w, r, key)
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		s.objects[key] = &fakeS3Object{data: data, lastModified: time.Now().UTC()}
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.writeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (s *fakeS3Server) getObject(w http.ResponseWriter, r *http.Request, key string) {
+	object, ok := s.objects[key]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	w.Header().Set("ETag", `"etag"`)
+	w.Header().Set("Last-Modified", object.lastModified.Format(http.TimeFormat))
+	w.Header().Set("Content-Length", fmt.Sprint(len(object.data)))
+	w.Header().Set("Content-Type", "application/gzip")
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(object.data)
+	}
+}
+
+func (s *fakeS3Server) copyObject(w http.ResponseWriter, r *http.Request, key string) {
+	source, _ := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	sourceKey := strings.TrimPrefix(strings.TrimPrefix(source, "/"), s.bucket+"/")
+
+	object, ok := s.objects[sourceKey]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	s.objects[key] = &fakeS3Object{data: object.data, lastModified: time.Now().UTC()}
+
+	fmt.Fprintf(w, `<CopyObjectResult><LastModified>%v</LastModified><ETag>"etag"</ETag></CopyObjectResult>`, time.Now().UTC().Format(time.RFC3339))
+}
+
+func (s *fakeS3Server) listObjects(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Name: s.bucket, Prefix: prefix, MaxKeys: 1000}
+
+	for _, key := range s.sortedKeys() {
+		if strings.HasPrefix(key, prefix) {
+			object := s.objects[key]
+			result.Contents = append(result.Contents, content{Key: key, LastModified: object.lastModified.Format(time.RFC3339), ETag: `"etag"`, Size: len(object.data)})
+		}
+	}
+	result.KeyCount = len(result.Contents)
+
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func (s *fakeS3Server) deleteObjects(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	err := xml.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+
+	type deleted struct {
+		Key string
+	}
+	result := struct {
+		XMLName xml.Name  `xml:"DeleteResult"`
+		Deleted []deleted `xml:"Deleted"`
+	}{}
+	for _, o := range request.Objects {
+		delete(s.objects, o.Key)
+		result.Deleted = append(result.Deleted, deleted{Key: o.Key})
+	}
+
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func (s *fakeS3Server) writeError(w http.ResponseWriter, statusCode int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, `<Error><Code>%v</Code><Message>%v</Message></Error>`, code, code)
+}
+
+func (s *fakeS3Server) sortedKeys() (keys []string) {
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (s *fakeS3Server) getKeys() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.sortedKeys()
+}
+
+func (s *fakeS3Server) setLastModified(key string, lastModified time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if object, ok := s.objects[key]; ok {
+		object.lastModified = lastModified
+	}
+}
@@ -3,6 +3,7 @@ package cloudstorage
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
@@ -74,3 +75,59 @@ func (c *tracingClient) Rename(ctx context.Context, fromRepoSource, fromRepoOwne
 
 	return c.Client.Rename(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName)
 }
+
+func (c *tracingClient) CopyBuildLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "CopyBuildLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.CopyBuildLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
+func (c *tracingClient) CopyReleaseLogs(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string, logIDs map[string]string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "CopyReleaseLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.CopyReleaseLogs(ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName, logIDs)
+}
+
+func (c *tracingClient) GetPipelineBuildLogSteps(ctx context.Context, buildLog contracts.BuildLog) (steps []*contracts.BuildLogStep, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPipelineBuildLogSteps"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPipelineBuildLogSteps(ctx, buildLog)
+}
+
+func (c *tracingClient) GetPipelineReleaseLogSteps(ctx context.Context, releaseLog contracts.ReleaseLog) (steps []*contracts.BuildLogStep, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPipelineReleaseLogSteps"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPipelineReleaseLogSteps(ctx, releaseLog)
+}
+
+func (c *tracingClient) GetPipelineBotLogSteps(ctx context.Context, botLog contracts.BotLog) (steps []*contracts.BuildLogStep, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPipelineBotLogSteps"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPipelineBotLogSteps(ctx, botLog)
+}
+
+func (c *tracingClient) PruneLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (count int, bytes int64, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "PruneLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.PruneLogs(ctx, repoSource, repoOwner, repoName, jobType, createdBefore, keepLogIDs, batchSize, dryRun)
+}
+
+func (c *tracingClient) GetLogIDs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType) (logIDs []string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetLogIDs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetLogIDs(ctx, repoSource, repoOwner, repoName, jobType)
+}
+
+func (c *tracingClient) CopyLogs(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, logIDs map[string]string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "CopyLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.CopyLogs(ctx, repoSource, repoOwner, repoName, jobType, logIDs)
+}
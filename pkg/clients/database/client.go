@@ -39,6 +39,38 @@ var (
 
 	// ErrCatalogEntityNotFound is returned if a query for a catalog entity returns no results
 	ErrCatalogEntityNotFound = errors.New("the catalog entity can't be found")
+
+	// ErrWebhookDeliveryNotFound is returned if a query for a webhook delivery returns no results
+	ErrWebhookDeliveryNotFound = errors.New("the webhook delivery can't be found")
+
+	// ErrMigrationTaskNotFound is returned if a query for a migration task returns no results
+	ErrMigrationTaskNotFound = errors.New("the migration task can't be found")
+
+	// ErrLogReconciliationNotFound is returned if a query for a log reconciliation returns no results
+	ErrLogReconciliationNotFound = errors.New("the log reconciliation can't be found")
+
+	// ErrCustomRoleNotFound is returned if a query for a custom role returns no results
+	ErrCustomRoleNotFound = errors.New("the custom role can't be found")
+
+	// ErrPersonalAccessTokenNotFound is returned if a query for a personal access token returns no results
+	ErrPersonalAccessTokenNotFound = errors.New("the personal access token can't be found")
+
+	// ErrSessionNotFound is returned if a query for a session returns no results
+	ErrSessionNotFound = errors.New("the session can't be found")
+
+	// ErrClientSecretNotFound is returned if a query for a client secret returns no results
+	ErrClientSecretNotFound = errors.New("the client secret can't be found")
+
+	// ErrNotificationDeliveryNotFound is returned if a query for a notification delivery returns no results
+	ErrNotificationDeliveryNotFound = errors.New("the notification delivery can't be found")
+)
+
+const (
+	// migrateBuildsBatchSize is the number of builds copied per query when migrating a pipeline
+	migrateBuildsBatchSize = 1000
+
+	// migrateComputedReleasesLimit is the maximum number of distinct release targets recomputed when migrating a pipeline
+	migrateComputedReleasesLimit = 100
 )
 
 // Client is the interface for communicating with the database
@@ -53,6 +85,8 @@ type Client interface {
 	InsertBuild(ctx context.Context, build contracts.Build, jobResources JobResources) (b *contracts.Build, err error)
 	UpdateBuildStatus(ctx context.Context, repoSource, repoOwner, repoName string, buildID string, buildStatus contracts.Status) (err error)
 	UpdateBuildResourceUtilization(ctx context.Context, repoSource, repoOwner, repoName string, buildID string, jobResources JobResources) (err error)
+	UpdateBuildStatusReason(ctx context.Context, repoSource, repoOwner, repoName string, buildID string, statusReason string) (err error)
+	GetBuildStatusReasons(ctx context.Context, buildIDs []string) (statusReasons map[string]string, err error)
 	InsertRelease(ctx context.Context, release contracts.Release, jobResources JobResources) (r *contracts.Release, err error)
 	UpdateReleaseStatus(ctx context.Context, repoSource, repoOwner, repoName string, releaseID string, releaseStatus contracts.Status) (err error)
 	UpdateReleaseResourceUtilization(ctx context.Context, repoSource, repoOwner, repoName string, releaseID string, jobResources JobResources) (err error)
@@ -92,6 +126,7 @@ type Client interface {
 	GetPipelineBuildLogsPerPage(ctx context.Context, repoSource, repoOwner, repoName, repoBranch, repoRevision, buildID string, pageNumber int, pageSize int) (buildLogs []*contracts.BuildLog, err error)
 	GetPipelineBuildLogsCount(ctx context.Context, repoSource, repoOwner, repoName, repoBranch, repoRevision, buildID string) (count int, err error)
 	GetPipelineBuildMaxResourceUtilization(ctx context.Context, repoSource, repoOwner, repoName string, lastNRecords int) (jobresources JobResources, count int, err error)
+	GetPipelineBuildResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, repoBranch string, lastNRecords int) (usages []*JobResourceUsage, err error)
 	GetPipelineReleases(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (releases []*contracts.Release, err error)
 	GetPipelineReleasesCount(ctx context.Context, repoSource, repoOwner, repoName string, filters map[api.FilterType][]string) (count int, err error)
 	GetPipelineRelease(ctx context.Context, repoSource, repoOwner, repoName string, releaseID string) (release *contracts.Release, err error)
@@ -101,6 +136,7 @@ type Client interface {
 	GetPipelineReleaseLogsPerPage(ctx context.Context, repoSource, repoOwner, repoName string, releaseID string, pageNumber int, pageSize int) (releaselogs []*contracts.ReleaseLog, err error)
 	GetPipelineReleaseLogsCount(ctx context.Context, repoSource, repoOwner, repoName string, releaseID string) (count int, err error)
 	GetPipelineReleaseMaxResourceUtilization(ctx context.Context, repoSource, repoOwner, repoName, targetName string, lastNRecords int) (jobresources JobResources, count int, err error)
+	GetPipelineReleaseResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, targetName, actionName string, lastNRecords int) (usages []*JobResourceUsage, err error)
 	GetPipelineBots(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (bots []*contracts.Bot, err error)
 	GetPipelineBotsCount(ctx context.Context, repoSource, repoOwner, repoName string, filters map[api.FilterType][]string) (count int, err error)
 	GetPipelineBot(ctx context.Context, repoSource, repoOwner, repoName string, botID string) (bot *contracts.Bot, err error)
@@ -109,6 +145,10 @@ type Client interface {
 	GetPipelineBotLogsPerPage(ctx context.Context, repoSource, repoOwner, repoName string, botID string, pageNumber int, pageSize int) (releaselogs []*contracts.BotLog, err error)
 	GetPipelineBotLogsCount(ctx context.Context, repoSource, repoOwner, repoName string, botID string) (count int, err error)
 	GetPipelineBotMaxResourceUtilization(ctx context.Context, repoSource, repoOwner, repoName, targetName string, lastNRecords int) (jobresources JobResources, count int, err error)
+	GetPipelineBotResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, botName string, lastNRecords int) (usages []*JobResourceUsage, err error)
+	GetJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string) (jobResources JobResources, err error)
+	GetJobResourceUtilizations(ctx context.Context, jobType contracts.JobType, from, to time.Time) (utilizations []*JobResourceUtilization, err error)
+	GetReleaseOutcomes(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (outcomes []*ReleaseOutcome, err error)
 	GetBuildsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
 	GetReleasesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
 	GetBotsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
@@ -182,6 +222,7 @@ type Client interface {
 	UpdateUser(ctx context.Context, user contracts.User) (err error)
 	DeleteUser(ctx context.Context, user contracts.User) (err error)
 	GetUserByIdentity(ctx context.Context, identity contracts.UserIdentity) (user *contracts.User, err error)
+	GetUserByEmail(ctx context.Context, email string) (user *contracts.User, err error)
 	GetUserByID(ctx context.Context, id string, filters map[api.FilterType][]string) (user *contracts.User, err error)
 	GetUsers(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (users []*contracts.User, err error)
 	GetUsersCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
@@ -191,6 +232,7 @@ type Client interface {
 	DeleteGroup(ctx context.Context, group contracts.Group) (err error)
 	GetGroupByIdentity(ctx context.Context, identity contracts.GroupIdentity) (group *contracts.Group, err error)
 	GetGroupByID(ctx context.Context, id string, filters map[api.FilterType][]string) (group *contracts.Group, err error)
+	GetGroupByName(ctx context.Context, name string) (group *contracts.Group, err error)
 	GetGroups(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (groups []*contracts.Group, err error)
 	GetGroupsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
 
@@ -211,6 +253,33 @@ type Client interface {
 	GetClients(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (clients []*contracts.Client, err error)
 	GetClientsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
 
+	InsertCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error)
+	UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error)
+	DeleteCustomRole(ctx context.Context, customRole api.CustomRole) (err error)
+	GetCustomRoleByID(ctx context.Context, id string) (customRole *api.CustomRole, err error)
+	GetCustomRoles(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (customRoles []*api.CustomRole, err error)
+	GetCustomRolesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
+
+	InsertPersonalAccessToken(ctx context.Context, token api.PersonalAccessToken, tokenHash string) (insertedToken *api.PersonalAccessToken, err error)
+	UpdatePersonalAccessTokenLastUsed(ctx context.Context, id string) (err error)
+	DeletePersonalAccessToken(ctx context.Context, token api.PersonalAccessToken) (err error)
+	GetPersonalAccessTokenByID(ctx context.Context, id string) (token *api.PersonalAccessToken, err error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (token *api.PersonalAccessToken, err error)
+	GetPersonalAccessTokensForUser(ctx context.Context, userID string) (tokens []*api.PersonalAccessToken, err error)
+
+	InsertClientSecret(ctx context.Context, secret api.ClientSecret, secretHash string) (insertedSecret *api.ClientSecret, err error)
+	HashLegacyClientSecret(ctx context.Context, client contracts.Client, secretHash string) (hashed bool, err error)
+	UpdateClientSecretLastUsed(ctx context.Context, id string) (err error)
+	DeleteClientSecret(ctx context.Context, secret api.ClientSecret) (err error)
+	GetClientSecretByHash(ctx context.Context, clientID, secretHash string) (secret *api.ClientSecret, err error)
+	GetClientSecretsForClient(ctx context.Context, clientID string) (secrets []*api.ClientSecret, err error)
+
+	InsertSession(ctx context.Context, session api.Session) (err error)
+	GetSessionByID(ctx context.Context, id string) (session *api.Session, err error)
+	GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error)
+	RevokeSession(ctx context.Context, id string) (err error)
+	RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error)
+
 	InsertCatalogEntity(ctx context.Context, catalogEntity contracts.CatalogEntity) (insertedCatalogEntity *contracts.CatalogEntity, err error)
 	UpdateCatalogEntity(ctx context.Context, catalogEntity contracts.CatalogEntity) (err error)
 	DeleteCatalogEntity(ctx context.Context, id string) (err error)
@@ -228,6 +297,63 @@ type Client interface {
 	GetCatalogEntityValuesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
 	GetCatalogEntityLabels(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (labels []map[string]interface{}, err error)
 	GetCatalogEntityLabelsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
+
+	InsertQueuedJob(ctx context.Context, queuedJob QueuedJob) (qj *QueuedJob, err error)
+	GetQueuedJobs(ctx context.Context, limit int) (queuedJobs []*QueuedJob, err error)
+	DeleteQueuedJob(ctx context.Context, jobType contracts.JobType, jobID string) (deleted bool, err error)
+	ClaimQueuedJob(ctx context.Context, queuedJob QueuedJob) (claimed bool, err error)
+	RequeueJob(ctx context.Context, queuedJob QueuedJob) (err error)
+	AcquireLock(ctx context.Context, name, holder string, ttl time.Duration) (acquired bool, err error)
+	ReleaseLock(ctx context.Context, name, holder string) (err error)
+	GetActiveJobs(ctx context.Context) (activeJobs []*ActiveJob, err error)
+
+	InsertWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (wd *WebhookDelivery, err error)
+	UpdateWebhookDeliveryOutcome(ctx context.Context, id, outcome string, statusCode int, errorMessage string) (err error)
+	GetWebhookDeliveryByID(ctx context.Context, id string) (delivery *WebhookDelivery, err error)
+	GetWebhookDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (deliveries []*WebhookDelivery, err error)
+	GetWebhookDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
+
+	QueueMigrationTask(ctx context.Context, task MigrationTask) (queuedTask *MigrationTask, err error)
+	PickMigrationTasks(ctx context.Context, maxTasks int) (tasks []*MigrationTask, err error)
+	UpdateMigrationTask(ctx context.Context, id string, status MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) (err error)
+	GetMigrationTaskByID(ctx context.Context, id string) (task *MigrationTask, err error)
+	GetMigrationTaskByFromRepo(ctx context.Context, fromSource, fromOwner, fromName string) (task *MigrationTask, err error)
+	GetMigrationTasks(ctx context.Context) (tasks []*MigrationTask, err error)
+	MigrateReleases(ctx context.Context, task MigrationTask) (count int, err error)
+	MigrateReleaseLogs(ctx context.Context, task MigrationTask) (err error)
+	GetMigratedReleaseLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error)
+	MigrateBuilds(ctx context.Context, task MigrationTask) (count int, err error)
+	MigrateBuildLogs(ctx context.Context, task MigrationTask) (err error)
+	GetMigratedBuildLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error)
+	MigrateBuildVersions(ctx context.Context, task MigrationTask, shortFromRepoSource, shortToRepoSource string) (err error)
+	MigrateComputedTables(ctx context.Context, task MigrationTask) (err error)
+	RollbackMigrationStep(ctx context.Context, task MigrationTask, step MigrationStep, shortToRepoSource string) (err error)
+	RollbackMigration(ctx context.Context, task MigrationTask, shortToRepoSource string) (rollback *MigrationRollback, err error)
+
+	GetLatestSuccessfulReleaseLogIDs(ctx context.Context, repoSource, repoOwner, repoName string) (logIDs []string, err error)
+	GetExpiredLogIDs(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string, limit int) (logIDs []string, err error)
+	GetExpiredLogsCount(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string) (count int, err error)
+	DeleteLogsByID(ctx context.Context, jobType contracts.JobType, logIDs []string) (count int, err error)
+
+	QueueLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (queuedReconciliation *LogReconciliation, err error)
+	PickLogReconciliation(ctx context.Context, staleBefore time.Time) (reconciliation *LogReconciliation, err error)
+	UpdateLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (err error)
+	GetLogReconciliationByID(ctx context.Context, id string) (reconciliation *LogReconciliation, err error)
+	GetActiveLogReconciliation(ctx context.Context) (reconciliation *LogReconciliation, err error)
+	GetLogReconciliations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (reconciliations []*LogReconciliation, err error)
+	GetLogReconciliationsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
+	GetLogRecords(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string) (records []*LogRecord, err error)
+
+	InsertNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (insertedDelivery *NotificationDelivery, err error)
+	PickNotificationDeliveries(ctx context.Context, limit int, staleBefore time.Time) (deliveries []*NotificationDelivery, err error)
+	UpdateNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (err error)
+	GetNotificationDeliveryByID(ctx context.Context, id string) (delivery *NotificationDelivery, err error)
+	GetNotificationDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (deliveries []*NotificationDelivery, err error)
+	GetNotificationDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
+
+	InsertAuditLogRecord(ctx context.Context, record AuditLogRecord) (insertedRecord *AuditLogRecord, err error)
+	GetAuditLogRecords(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (records []*AuditLogRecord, err error)
+	GetAuditLogRecordsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error)
 }
 
 // NewClient returns a new cockroach.Client
@@ -443,7 +569,9 @@ func (c *client) InsertBuild(ctx context.Context, build contracts.Build, jobReso
 			$19
 		)
 		RETURNING
-			id
+			id,
+			inserted_at,
+			updated_at
 		`,
 		build.RepoSource,
 		build.RepoOwner,
@@ -468,7 +596,7 @@ func (c *client) InsertBuild(ctx context.Context, build contracts.Build, jobReso
 
 	insertedBuild = &build
 
-	if err = row.Scan(&insertedBuild.ID); err != nil {
+	if err = row.Scan(&insertedBuild.ID, &insertedBuild.InsertedAt, &insertedBuild.UpdatedAt); err != nil {
 		return
 	}
 
@@ -495,14 +623,18 @@ func (c *client) UpdateBuildStatus(ctx context.Context, repoSource, repoOwner, r
 
 	var allowedBuildStatusesToTransitionFrom []contracts.Status
 	switch buildStatus {
+	case contracts.StatusPending:
+		allowedBuildStatusesToTransitionFrom = []contracts.Status{api.StatusQueued}
 	case contracts.StatusRunning:
 		allowedBuildStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending}
 	case contracts.StatusSucceeded,
-		contracts.StatusFailed,
 		contracts.StatusCanceling:
 		allowedBuildStatusesToTransitionFrom = []contracts.Status{contracts.StatusRunning}
+	case contracts.StatusFailed:
+		// a pending job fails if its builder job can't be created
+		allowedBuildStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusRunning}
 	case contracts.StatusCanceled:
-		allowedBuildStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusCanceling}
+		allowedBuildStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusCanceling, api.StatusQueued}
 	}
 
 	// turn into string array so query works as expected
@@ -568,6 +700,8 @@ func (c *client) UpdateBuildResourceUtilization(ctx context.Context, repoSource,
 		Update("builds").
 		Set("cpu_max_usage", jobResources.CPUMaxUsage).
 		Set("memory_max_usage", jobResources.MemoryMaxUsage).
+		Set("oom_killed", jobResources.OOMKilled).
+		Set("cpu_throttled", jobResources.CPUThrottled).
 		Where(sq.Eq{"id": buildID}).
 		Where(sq.Eq{"repo_source": repoSource}).
 		Where(sq.Eq{"repo_owner": repoOwner}).
@@ -582,6 +716,62 @@ func (c *client) UpdateBuildResourceUtilization(ctx context.Context, repoSource,
 	return
 }
 
+func (c *client) UpdateBuildStatusReason(ctx context.Context, repoSource, repoOwner, repoName string, buildID string, statusReason string) (err error) {
+	if buildID == "" {
+		return fmt.Errorf("UpdateBuildStatusReason argument buildID is empty")
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Update("builds").
+		Set("status_reason", statusReason).
+		Where(sq.Eq{"id": buildID}).
+		Where(sq.Eq{"repo_source": repoSource}).
+		Where(sq.Eq{"repo_owner": repoOwner}).
+		Where(sq.Eq{"repo_name": repoName})
+
+	// update build status reason
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (c *client) GetBuildStatusReasons(ctx context.Context, buildIDs []string) (statusReasons map[string]string, err error) {
+
+	statusReasons = map[string]string{}
+	if len(buildIDs) == 0 {
+		return
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.status_reason").
+		From("builds a").
+		Where(sq.Eq{"a.id": buildIDs}).
+		Where(sq.NotEq{"a.status_reason": nil})
+
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		var id, statusReason string
+		if err = rows.Scan(&id, &statusReason); err != nil {
+			return
+		}
+		statusReasons[id] = statusReason
+	}
+
+	return
+}
+
 func (c *client) InsertRelease(ctx context.Context, release contracts.Release, jobResources JobResources) (insertedRelease *contracts.Release, err error) {
 
 	eventsBytes, err := json.Marshal(release.Events)
@@ -700,14 +890,18 @@ func (c *client) UpdateReleaseStatus(ctx context.Context, repoSource, repoOwner,
 
 	var allowedReleaseStatusesToTransitionFrom []contracts.Status
 	switch releaseStatus {
+	case contracts.StatusPending:
+		allowedReleaseStatusesToTransitionFrom = []contracts.Status{api.StatusQueued}
 	case contracts.StatusRunning:
 		allowedReleaseStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending}
 	case contracts.StatusSucceeded,
-		contracts.StatusFailed,
 		contracts.StatusCanceling:
 		allowedReleaseStatusesToTransitionFrom = []contracts.Status{contracts.StatusRunning}
+	case contracts.StatusFailed:
+		// a pending job fails if its builder job can't be created
+		allowedReleaseStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusRunning}
 	case contracts.StatusCanceled:
-		allowedReleaseStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusCanceling}
+		allowedReleaseStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusCanceling, api.StatusQueued}
 	}
 
 	// turn into string array so query works as expected
@@ -782,6 +976,8 @@ func (c *client) UpdateReleaseResourceUtilization(ctx context.Context, repoSourc
 		Update("releases").
 		Set("cpu_max_usage", jobResources.CPUMaxUsage).
 		Set("memory_max_usage", jobResources.MemoryMaxUsage).
+		Set("oom_killed", jobResources.OOMKilled).
+		Set("cpu_throttled", jobResources.CPUThrottled).
 		Where(sq.Eq{"id": releaseID}).
 		Where(sq.Eq{"repo_source": repoSource}).
 		Where(sq.Eq{"repo_owner": repoOwner}).
@@ -888,14 +1084,18 @@ func (c *client) UpdateBotStatus(ctx context.Context, repoSource, repoOwner, rep
 
 	var allowedBotStatusesToTransitionFrom []contracts.Status
 	switch botStatus {
+	case contracts.StatusPending:
+		allowedBotStatusesToTransitionFrom = []contracts.Status{api.StatusQueued}
 	case contracts.StatusRunning:
 		allowedBotStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending}
 	case contracts.StatusSucceeded,
-		contracts.StatusFailed,
 		contracts.StatusCanceling:
 		allowedBotStatusesToTransitionFrom = []contracts.Status{contracts.StatusRunning}
+	case contracts.StatusFailed:
+		// a pending job fails if its builder job can't be created
+		allowedBotStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusRunning}
 	case contracts.StatusCanceled:
-		allowedBotStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusCanceling}
+		allowedBotStatusesToTransitionFrom = []contracts.Status{contracts.StatusPending, contracts.StatusCanceling, api.StatusQueued}
 	}
 
 	// turn into string array so query works as expected
@@ -938,6 +1138,8 @@ func (c *client) UpdateBotResourceUtilization(ctx context.Context, repoSource, r
 		Update("bots").
 		Set("cpu_max_usage", jobResources.CPUMaxUsage).
 		Set("memory_max_usage", jobResources.MemoryMaxUsage).
+		Set("oom_killed", jobResources.OOMKilled).
+		Set("cpu_throttled", jobResources.CPUThrottled).
 		Where(sq.Eq{"id": botID}).
 		Where(sq.Eq{"repo_source": repoSource}).
 		Where(sq.Eq{"repo_owner": repoOwner}).
@@ -2314,6 +2516,29 @@ func (c *client) GetPipelineBuildMaxResourceUtilization(ctx context.Context, rep
 	return
 }
 
+func (c *client) GetPipelineBuildResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, repoBranch string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+
+	// generate query
+	query := c.selectJobResourceUsagesQuery("builds", "build_status").
+		Where(sq.Eq{"repo_source": repoSource}).
+		Where(sq.Eq{"repo_owner": repoOwner}).
+		Where(sq.Eq{"repo_name": repoName}).
+		Limit(uint64(lastNRecords))
+
+	// without a branch the builds of all branches are used
+	if repoBranch != "" {
+		query = query.Where(sq.Eq{"repo_branch": repoBranch})
+	}
+
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return c.scanJobResourceUsages(rows)
+}
+
 func (c *client) GetPipelineReleases(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (releases []*contracts.Release, err error) {
 
 	// generate query
@@ -2629,6 +2854,30 @@ func (c *client) GetPipelineReleaseMaxResourceUtilization(ctx context.Context, r
 	return
 }
 
+func (c *client) GetPipelineReleaseResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, targetName, actionName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+
+	// generate query
+	query := c.selectJobResourceUsagesQuery("releases", "release_status").
+		Where(sq.Eq{"repo_source": repoSource}).
+		Where(sq.Eq{"repo_owner": repoOwner}).
+		Where(sq.Eq{"repo_name": repoName}).
+		Where(sq.Eq{"release": targetName}).
+		Limit(uint64(lastNRecords))
+
+	// without an action the releases of all actions of the target are used
+	if actionName != "" {
+		query = query.Where(sq.Eq{"release_action": actionName})
+	}
+
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return c.scanJobResourceUsages(rows)
+}
+
 func (c *client) GetPipelineBots(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (bots []*contracts.Bot, err error) {
 
 	// generate query
@@ -2894,6 +3143,234 @@ func (c *client) GetPipelineBotMaxResourceUtilization(ctx context.Context, repoS
 	return
 }
 
+func (c *client) GetPipelineBotResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, botName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+
+	// generate query
+	query := c.selectJobResourceUsagesQuery("bots", "bot_status").
+		Where(sq.Eq{"repo_source": repoSource}).
+		Where(sq.Eq{"repo_owner": repoOwner}).
+		Where(sq.Eq{"repo_name": repoName}).
+		Where(sq.Eq{"bot": botName}).
+		Limit(uint64(lastNRecords))
+
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return c.scanJobResourceUsages(rows)
+}
+
+func (c *client) GetJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string) (jobResources JobResources, err error) {
+	table, _, err := getJobTableAndStatusColumn(jobType)
+	if err != nil {
+		return
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// generate query
+	query := psql.
+		Select("COALESCE(a.cpu_request,0), COALESCE(a.cpu_limit,0), COALESCE(a.cpu_max_usage,0), COALESCE(a.memory_request,0), COALESCE(a.memory_limit,0), COALESCE(a.memory_max_usage,0), COALESCE(a.oom_killed,false), COALESCE(a.cpu_throttled,false)").
+		From(fmt.Sprintf("%v a", table)).
+		Where(sq.Eq{"a.id": jobID}).
+		Where(sq.Eq{"a.repo_source": repoSource}).
+		Where(sq.Eq{"a.repo_owner": repoOwner}).
+		Where(sq.Eq{"a.repo_name": repoName})
+
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	err = row.Scan(
+		&jobResources.CPURequest,
+		&jobResources.CPULimit,
+		&jobResources.CPUMaxUsage,
+		&jobResources.MemoryRequest,
+		&jobResources.MemoryLimit,
+		&jobResources.MemoryMaxUsage,
+		&jobResources.OOMKilled,
+		&jobResources.CPUThrottled)
+
+	return
+}
+
+func (c *client) GetJobResourceUtilizations(ctx context.Context, jobType contracts.JobType, from, to time.Time) (utilizations []*JobResourceUtilization, err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	var table, statusColumn string
+	switch jobType {
+	case contracts.JobTypeBuild:
+		table, statusColumn = "builds", "build_status"
+	case contracts.JobTypeRelease:
+		table, statusColumn = "releases", "release_status"
+	case contracts.JobTypeBot:
+		table, statusColumn = "bots", "bot_status"
+	default:
+		return nil, fmt.Errorf("GetJobResourceUtilizations argument jobType %v is invalid", jobType)
+	}
+
+	// releases and bots take the labels of their pipeline
+	labelsColumn := "p.labels"
+	if jobType == contracts.JobTypeBuild {
+		labelsColumn = "a.labels"
+	}
+
+	// generate query
+	query := psql.
+		Select(fmt.Sprintf("a.id, a.repo_source, a.repo_owner, a.repo_name, %v, a.groups, a.organizations, COALESCE(a.cpu_request,0), COALESCE(a.cpu_max_usage,0), COALESCE(a.memory_request,0), COALESCE(a.memory_max_usage,0), a.cpu_max_usage IS NOT NULL AND a.memory_max_usage IS NOT NULL, a.inserted_at, EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at)))", labelsColumn)).
+		From(fmt.Sprintf("%v a", table)).
+		LeftJoin("computed_pipelines p ON p.repo_source = a.repo_source AND p.repo_owner = a.repo_owner AND p.repo_name = a.repo_name").
+		Where(sq.GtOrEq{"a.inserted_at": from}).
+		Where(sq.Lt{"a.inserted_at": to}).
+		Where(sq.Eq{fmt.Sprintf("a.%v", statusColumn): []string{string(contracts.StatusSucceeded), string(contracts.StatusFailed), string(contracts.StatusCanceled)}}).
+		OrderBy("a.inserted_at")
+
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	utilizations = make([]*JobResourceUtilization, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		utilization := JobResourceUtilization{
+			JobType: jobType,
+		}
+		var id int
+		var durationSeconds float64
+		var labelsData, groupsData, organizationsData []uint8
+
+		if err = rows.Scan(
+			&id,
+			&utilization.RepoSource,
+			&utilization.RepoOwner,
+			&utilization.RepoName,
+			&labelsData,
+			&groupsData,
+			&organizationsData,
+			&utilization.CPURequest,
+			&utilization.CPUMaxUsage,
+			&utilization.MemoryRequest,
+			&utilization.MemoryMaxUsage,
+			&utilization.Measured,
+			&utilization.InsertedAt,
+			&durationSeconds); err != nil {
+			return
+		}
+
+		utilization.ID = strconv.Itoa(id)
+		utilization.Duration = time.Duration(durationSeconds * float64(time.Second))
+
+		if len(labelsData) > 0 {
+			if err = json.Unmarshal(labelsData, &utilization.Labels); err != nil {
+				return
+			}
+		}
+		if len(groupsData) > 0 {
+			if err = json.Unmarshal(groupsData, &utilization.Groups); err != nil {
+				return
+			}
+		}
+		if len(organizationsData) > 0 {
+			if err = json.Unmarshal(organizationsData, &utilization.Organizations); err != nil {
+				return
+			}
+		}
+
+		utilizations = append(utilizations, &utilization)
+	}
+
+	return
+}
+
+func (c *client) GetReleaseOutcomes(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (outcomes []*ReleaseOutcome, err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// generate query
+	query := psql.
+		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.release, a.release_action, a.release_version, a.release_status, a.inserted_at, a.updated_at, (SELECT MIN(b.inserted_at) FROM builds b WHERE b.repo_source = a.repo_source AND b.repo_owner = a.repo_owner AND b.repo_name = a.repo_name AND b.build_version = a.release_version)").
+		From("releases a").
+		LeftJoin("computed_pipelines p ON p.repo_source = a.repo_source AND p.repo_owner = a.repo_owner AND p.repo_name = a.repo_name").
+		Where(sq.GtOrEq{"a.inserted_at": from}).
+		Where(sq.Lt{"a.inserted_at": to}).
+		Where(sq.Eq{"a.release_status": []string{string(contracts.StatusSucceeded), string(contracts.StatusFailed)}}).
+		OrderBy("a.inserted_at")
+
+	// an empty repository selects the releases of all pipelines
+	if repoSource != "" && repoOwner != "" && repoName != "" {
+		query = query.
+			Where(sq.Eq{"a.repo_source": repoSource}).
+			Where(sq.Eq{"a.repo_owner": repoOwner}).
+			Where(sq.Eq{"a.repo_name": repoName})
+	}
+
+	// dynamically set where clauses for filtering
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterReleaseTarget, "release")
+	if err != nil {
+		return
+	}
+	query, err = whereClauseGeneratorForGroupsFilter(query, filters)
+	if err != nil {
+		return
+	}
+	query, err = whereClauseGeneratorForOrganizationsFilter(query, filters)
+	if err != nil {
+		return
+	}
+	// releases take the labels of their pipeline
+	query, err = whereClauseGeneratorForLabelsColumnFilter(query, "p.labels", filters)
+	if err != nil {
+		return
+	}
+
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	outcomes = make([]*ReleaseOutcome, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		outcome := ReleaseOutcome{}
+		var id int
+		var releaseStatus string
+		var buildInsertedAt sql.NullTime
+
+		if err = rows.Scan(
+			&id,
+			&outcome.RepoSource,
+			&outcome.RepoOwner,
+			&outcome.RepoName,
+			&outcome.Name,
+			&outcome.Action,
+			&outcome.ReleaseVersion,
+			&releaseStatus,
+			&outcome.InsertedAt,
+			&outcome.FinishedAt,
+			&buildInsertedAt); err != nil {
+			return
+		}
+
+		outcome.ID = strconv.Itoa(id)
+		outcome.ReleaseStatus = contracts.Status(releaseStatus)
+		if buildInsertedAt.Valid {
+			outcome.BuildInsertedAt = &buildInsertedAt.Time
+		}
+
+		outcomes = append(outcomes, &outcome)
+	}
+
+	return
+}
+
 func (c *client) GetBuildsCount(ctx context.Context, filters map[api.FilterType][]string) (totalCount int, err error) {
 
 	// generate query
@@ -4898,6 +5375,10 @@ func whereClauseGeneratorForReleaseTargetFilterOnPipelines(query sq.SelectBuilde
 }
 
 func whereClauseGeneratorForLabelsFilter(query sq.SelectBuilder, filters map[api.FilterType][]string) (sq.SelectBuilder, error) {
+	return whereClauseGeneratorForLabelsColumnFilter(query, "a.labels", filters)
+}
+
+func whereClauseGeneratorForLabelsColumnFilter(query sq.SelectBuilder, labelsColumn string, filters map[api.FilterType][]string) (sq.SelectBuilder, error) {
 
 	if labels, ok := filters[api.FilterLabels]; ok && len(labels) > 0 {
 
@@ -4920,7 +5401,7 @@ func whereClauseGeneratorForLabelsFilter(query sq.SelectBuilder, filters map[api
 				return query, err
 			}
 
-			query = query.Where("a.labels @> ?", string(bytes))
+			query = query.Where(fmt.Sprintf("%v @> ?", labelsColumn), string(bytes))
 		}
 	}
 
@@ -5065,6 +5546,33 @@ func whereClauseGeneratorForOrganizationsInGroupDataFilter(query sq.SelectBuilde
 	return query, nil
 }
 
+func whereClauseGeneratorForOrganizationsInClientDataFilter(query sq.SelectBuilder, filters map[api.FilterType][]string) (sq.SelectBuilder, error) {
+
+	if organizations, ok := filters[api.FilterOrganizations]; ok && len(organizations) > 0 {
+
+		expressions := sq.Or{}
+		for _, o := range organizations {
+			organizationParam := contracts.Client{
+				Organizations: []*contracts.Organization{
+					{
+						Name: o,
+					},
+				},
+			}
+
+			bytes, err := json.Marshal(organizationParam)
+			if err != nil {
+				return query, err
+			}
+
+			expressions = append(expressions, sq.Expr("a.client_data @> ?", string(bytes)))
+		}
+		query = query.Where(expressions)
+	}
+
+	return query, nil
+}
+
 func whereClauseGeneratorForArchivedFilter(query sq.SelectBuilder, filters map[api.FilterType][]string) (sq.SelectBuilder, error) {
 
 	if archiveds, ok := filters[api.FilterArchived]; ok && len(archiveds) > 0 {
@@ -5806,11 +6314,40 @@ func (c *client) scanPipelineReleases(rows *sql.Rows) (releases []*contracts.Rel
 	return
 }
 
-func (c *client) GetTriggers(ctx context.Context, triggerType, identifier, event string) (pipelines []*contracts.Pipeline, err error) {
+func (c *client) scanJobResourceUsages(rows *sql.Rows) (usages []*JobResourceUsage, err error) {
 
-	// generate query
-	query := c.selectPipelinesQuery().
-		Where(sq.Eq{"a.archived": false})
+	usages = make([]*JobResourceUsage, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		usage := JobResourceUsage{}
+
+		if err = rows.Scan(
+			&usage.CPURequest,
+			&usage.CPULimit,
+			&usage.CPUMaxUsage,
+			&usage.MemoryRequest,
+			&usage.MemoryLimit,
+			&usage.MemoryMaxUsage,
+			&usage.OOMKilled,
+			&usage.CPUThrottled,
+			&usage.Status,
+			&usage.InsertedAt); err != nil {
+			return
+		}
+
+		usages = append(usages, &usage)
+	}
+
+	return
+}
+
+func (c *client) GetTriggers(ctx context.Context, triggerType, identifier, event string) (pipelines []*contracts.Pipeline, err error) {
+
+	// generate query
+	query := c.selectPipelinesQuery().
+		Where(sq.Eq{"a.archived": false})
 
 	trigger := manifest.ZiplineeTrigger{}
 
@@ -6328,6 +6865,47 @@ func (c *client) GetUserByIdentity(ctx context.Context, identity contracts.UserI
 	return user, nil
 }
 
+// GetUserByEmail returns the active user with an identity for the email address, regardless of its provider
+func (c *client) GetUserByEmail(ctx context.Context, email string) (user *contracts.User, err error) {
+
+	filter := struct {
+		Identities []struct {
+			Email string `json:"email"`
+		} `json:"identities"`
+	}{
+		[]struct {
+			Email string `json:"email"`
+		}{
+			{
+				Email: email,
+			},
+		},
+	}
+
+	filterBytes, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.user_data, a.inserted_at, a.active").
+		From("users a").
+		Where("a.user_data @> ?", string(filterBytes)).
+		Where(sq.Eq{"a.active": true}).
+		Limit(uint64(1))
+
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	user, err = c.scanUser(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (c *client) GetUsers(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, _ []api.OrderField) (users []*contracts.User, err error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
@@ -6379,7 +6957,7 @@ func (c *client) GetUsers(ctx context.Context, pageNumber, pageSize int, filters
 	return c.scanUsers(rows)
 }
 
-func (c *client) GetUsersCount(ctx context.Context, _ map[api.FilterType][]string) (count int, err error) {
+func (c *client) GetUsersCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
 
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
@@ -6388,6 +6966,11 @@ func (c *client) GetUsersCount(ctx context.Context, _ map[api.FilterType][]strin
 		From("users a").
 		Where(sq.Eq{"a.active": true})
 
+	query, err = whereClauseGeneratorForUserFilters(query, filters)
+	if err != nil {
+		return
+	}
+
 	// execute query
 	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
 	if err = row.Scan(&count); err != nil {
@@ -6555,6 +7138,26 @@ func (c *client) GetGroupByID(ctx context.Context, id string, filters map[api.Fi
 	return group, nil
 }
 
+func (c *client) GetGroupByName(ctx context.Context, name string) (group *contracts.Group, err error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.group_data, a.inserted_at, a.active").
+		From("groups a").
+		Where(sq.Eq{"a.group_data->>'name'": name}).
+		Where(sq.Eq{"a.active": true}).
+		Limit(uint64(1))
+
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	group, err = c.scanGroup(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
 func (c *client) GetGroups(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, _ []api.OrderField) (groups []*contracts.Group, err error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
@@ -6579,7 +7182,7 @@ func (c *client) GetGroups(ctx context.Context, pageNumber, pageSize int, filter
 	return c.scanGroups(rows)
 }
 
-func (c *client) GetGroupsCount(ctx context.Context, _ map[api.FilterType][]string) (count int, err error) {
+func (c *client) GetGroupsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	query := psql.
@@ -6587,6 +7190,11 @@ func (c *client) GetGroupsCount(ctx context.Context, _ map[api.FilterType][]stri
 		From("groups a").
 		Where(sq.Eq{"a.active": true})
 
+	query, err = whereClauseGeneratorForOrganizationsInGroupDataFilter(query, filters)
+	if err != nil {
+		return
+	}
+
 	// execute query
 	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
 	if err = row.Scan(&count); err != nil {
@@ -6934,7 +7542,7 @@ func (c *client) GetClientByID(ctx context.Context, id string) (client *contract
 	return client, nil
 }
 
-func (c *client) GetClients(ctx context.Context, pageNumber, pageSize int, _ map[api.FilterType][]string, _ []api.OrderField) (clients []*contracts.Client, err error) {
+func (c *client) GetClients(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, _ []api.OrderField) (clients []*contracts.Client, err error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	query := psql.
@@ -6944,6 +7552,11 @@ func (c *client) GetClients(ctx context.Context, pageNumber, pageSize int, _ map
 		Limit(uint64(pageSize)).
 		Offset(uint64((pageNumber - 1) * pageSize))
 
+	query, err = whereClauseGeneratorForOrganizationsInClientDataFilter(query, filters)
+	if err != nil {
+		return
+	}
+
 	// execute query
 	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
 	if err != nil {
@@ -6953,7 +7566,7 @@ func (c *client) GetClients(ctx context.Context, pageNumber, pageSize int, _ map
 	return c.scanClients(rows)
 }
 
-func (c *client) GetClientsCount(ctx context.Context, _ map[api.FilterType][]string) (count int, err error) {
+func (c *client) GetClientsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	query := psql.
@@ -6961,6 +7574,11 @@ func (c *client) GetClientsCount(ctx context.Context, _ map[api.FilterType][]str
 		From("clients a").
 		Where(sq.Eq{"a.active": true})
 
+	query, err = whereClauseGeneratorForOrganizationsInClientDataFilter(query, filters)
+	if err != nil {
+		return
+	}
+
 	// execute query
 	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
 	if err = row.Scan(&count); err != nil {
@@ -6970,74 +7588,47 @@ func (c *client) GetClientsCount(ctx context.Context, _ map[api.FilterType][]str
 	return
 }
 
-func (c *client) InsertCatalogEntity(ctx context.Context, catalogEntity contracts.CatalogEntity) (insertedCatalogEntity *contracts.CatalogEntity, err error) {
+func (c *client) InsertCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
 
-	labelBytes, err := json.Marshal(catalogEntity.Labels)
-	if err != nil {
-		return nil, err
-	}
+	customRole.Active = true
 
-	metadataBytes, err := json.Marshal(catalogEntity.Metadata)
+	customRoleBytes, err := json.Marshal(customRole)
 	if err != nil {
 		return nil, err
 	}
 
-	// upsert user
 	row := c.databaseConnection.QueryRowContext(ctx,
 		`
 		INSERT INTO
-		catalog_entities
+			custom_roles
 		(
-			parent_key,
-			parent_value,
-			entity_key,
-			entity_value,
-			linked_pipeline,
-			labels,
-			entity_metadata
+			role_data
 		)
 		VALUES
 		(
-			$1,
-			$2,
-			$3,
-			$4,
-			$5,
-			$6,
-			$7
+			$1
 		)
 		RETURNING
 			id
 		`,
-		catalogEntity.ParentKey,
-		catalogEntity.ParentValue,
-		catalogEntity.Key,
-		catalogEntity.Value,
-		catalogEntity.LinkedPipeline,
-		labelBytes,
-		metadataBytes,
+		customRoleBytes,
 	)
 
-	insertedCatalogEntity = &catalogEntity
+	insertedCustomRole = &customRole
 
-	if err = row.Scan(&insertedCatalogEntity.ID); err != nil {
+	if err = row.Scan(&insertedCustomRole.ID); err != nil {
 		return nil, err
 	}
 
 	return
 }
 
-func (c *client) UpdateCatalogEntity(ctx context.Context, catalogEntity contracts.CatalogEntity) (err error) {
-	if catalogEntity.ID == "" {
-		return fmt.Errorf("UpdateCatalogEntity argument catalogEntity.ID is empty")
-	}
-
-	labelBytes, err := json.Marshal(catalogEntity.Labels)
-	if err != nil {
-		return
+func (c *client) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	if customRole.ID == "" {
+		return fmt.Errorf("UpdateCustomRole argument customRole.ID is empty")
 	}
 
-	metadataBytes, err := json.Marshal(catalogEntity.Metadata)
+	customRoleBytes, err := json.Marshal(customRole)
 	if err != nil {
 		return
 	}
@@ -7045,92 +7636,90 @@ func (c *client) UpdateCatalogEntity(ctx context.Context, catalogEntity contract
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	query := psql.
-		Update("catalog_entities").
-		Set("linked_pipeline", catalogEntity.LinkedPipeline).
-		Set("labels", labelBytes).
-		Set("entity_metadata", metadataBytes).
+		Update("custom_roles").
+		Set("role_data", customRoleBytes).
 		Set("updated_at", sq.Expr("now()")).
-		Where(sq.Eq{"id": catalogEntity.ID})
+		Where(sq.Eq{"id": customRole.ID})
 
 	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
 
 	return
 }
 
-func (c *client) DeleteCatalogEntity(ctx context.Context, id string) (err error) {
-	if id == "" {
-		return fmt.Errorf("DeleteCatalogEntity argument id is empty")
+func (c *client) DeleteCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	if customRole.ID == "" {
+		return fmt.Errorf("DeleteCustomRole argument customRole.ID is empty")
+	}
+
+	// deactivate custom role
+	customRole.Active = false
+
+	customRoleBytes, err := json.Marshal(customRole)
+	if err != nil {
+		return
 	}
 
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	query := psql.
-		Delete("catalog_entities a").
-		Where(sq.Eq{"a.id": id})
+		Update("custom_roles").
+		Set("role_data", customRoleBytes).
+		Set("updated_at", sq.Expr("now()")).
+		Set("active", false).
+		Where(sq.Eq{"id": customRole.ID})
 
 	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
-	if err != nil {
-		return
-	}
 
-	return nil
+	return
 }
 
-func (c *client) GetCatalogEntityByID(ctx context.Context, id string) (catalogEntity *contracts.CatalogEntity, err error) {
+func (c *client) GetCustomRoleByID(ctx context.Context, id string) (customRole *api.CustomRole, err error) {
 	if id == "" {
-		return nil, fmt.Errorf("GetCatalogEntityByID argument id is empty")
+		return nil, fmt.Errorf("GetCustomRoleByID argument id is empty")
 	}
 
-	query := c.selectCatalogEntityQuery().
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.role_data, a.active").
+		From("custom_roles a").
 		Where(sq.Eq{"a.id": id}).
+		Where(sq.Eq{"a.active": true}).
 		Limit(uint64(1))
 
 	// execute query
 	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
-	catalogEntity, err = c.scanCatalogEntity(row)
-	if err != nil {
-		return nil, err
-	}
 
-	return catalogEntity, nil
+	return c.scanCustomRole(row)
 }
 
-func (c *client) GetCatalogEntities(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (catalogEntities []*contracts.CatalogEntity, err error) {
+func (c *client) GetCustomRoles(ctx context.Context, pageNumber, pageSize int, _ map[api.FilterType][]string, _ []api.OrderField) (customRoles []*api.CustomRole, err error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	query := c.selectCatalogEntityQuery().
+	query := psql.
+		Select("a.id, a.role_data, a.active").
+		From("custom_roles a").
+		Where(sq.Eq{"a.active": true}).
+		OrderBy("a.role_data->>'name'").
 		Limit(uint64(pageSize)).
 		Offset(uint64((pageNumber - 1) * pageSize))
 
-	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
-	if err != nil {
-		return nil, err
-	}
-
-	query, err = orderByClauseGeneratorForSortings(query, "a.parent_key, a.parent_value, a.entity_key, a.entity_value", sortings)
-	if err != nil {
-		return nil, err
-	}
-
 	// execute query
 	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
 	if err != nil {
 		return
 	}
 
-	return c.scanCatalogEntities(rows)
+	return c.scanCustomRoles(rows)
 }
 
-func (c *client) GetCatalogEntitiesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+func (c *client) GetCustomRolesCount(ctx context.Context, _ map[api.FilterType][]string) (count int, err error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 	query := psql.
 		Select("COUNT(a.id)").
-		From("catalog_entities a")
-
-	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
-	if err != nil {
-		return
-	}
+		From("custom_roles a").
+		Where(sq.Eq{"a.active": true})
 
 	// execute query
 	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
@@ -7141,839 +7730,3681 @@ func (c *client) GetCatalogEntitiesCount(ctx context.Context, filters map[api.Fi
 	return
 }
 
-func (c *client) GetCatalogEntityParentKeys(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (keys []map[string]interface{}, err error) {
-	return c.getCatalogEntityColumn(ctx, "parent_key", "id", pageNumber, pageSize, filters, sortings)
-}
+func (c *client) InsertPersonalAccessToken(ctx context.Context, token api.PersonalAccessToken, tokenHash string) (insertedToken *api.PersonalAccessToken, err error) {
+	if token.UserID == "" {
+		return nil, fmt.Errorf("InsertPersonalAccessToken argument token.UserID is empty")
+	}
+	if tokenHash == "" {
+		return nil, fmt.Errorf("InsertPersonalAccessToken argument tokenHash is empty")
+	}
 
-func (c *client) GetCatalogEntityParentKeysCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
-	return c.getCatalogEntityColumnCount(ctx, "parent_key", filters)
-}
+	token.Active = true
 
-func (c *client) GetCatalogEntityParentValues(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (values []map[string]interface{}, err error) {
-	return c.getCatalogEntityColumn(ctx, "parent_value", "id", pageNumber, pageSize, filters, sortings)
-}
+	// the token itself is never stored, only its hash
+	tokenWithoutSecret := token
+	tokenWithoutSecret.Token = ""
 
-func (c *client) GetCatalogEntityParentValuesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
-	return c.getCatalogEntityColumnCount(ctx, "parent_value", filters)
-}
+	tokenBytes, err := json.Marshal(tokenWithoutSecret)
+	if err != nil {
+		return nil, err
+	}
 
-func (c *client) GetCatalogEntityKeys(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (keys []map[string]interface{}, err error) {
-	return c.getCatalogEntityColumn(ctx, "entity_key", "id", pageNumber, pageSize, filters, sortings)
-}
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+			personal_access_tokens
+		(
+			user_id,
+			token_hash,
+			token_data
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3
+		)
+		RETURNING
+			id,
+			inserted_at
+		`,
+		token.UserID,
+		tokenHash,
+		tokenBytes,
+	)
 
-func (c *client) GetCatalogEntityKeysCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
-	return c.getCatalogEntityColumnCount(ctx, "entity_key", filters)
-}
+	insertedToken = &token
 
-func (c *client) GetCatalogEntityValues(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (values []map[string]interface{}, err error) {
-	return c.getCatalogEntityColumn(ctx, "entity_value", "id", pageNumber, pageSize, filters, sortings)
-}
+	var insertedAt time.Time
+	if err = row.Scan(&insertedToken.ID, &insertedAt); err != nil {
+		return nil, err
+	}
+	insertedToken.CreatedAt = &insertedAt
 
-func (c *client) GetCatalogEntityValuesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
-	return c.getCatalogEntityColumnCount(ctx, "entity_value", filters)
+	return
 }
 
-func (c *client) getCatalogEntityColumn(ctx context.Context, groupColumn, countColumn string, pageNumber, pageSize int, filters map[api.FilterType][]string, _ []api.OrderField) (keys []map[string]interface{}, err error) {
+func (c *client) UpdatePersonalAccessTokenLastUsed(ctx context.Context, id string) (err error) {
+	if id == "" {
+		return fmt.Errorf("UpdatePersonalAccessTokenLastUsed argument id is empty")
+	}
 
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	query :=
-		psql.
-			Select(fmt.Sprintf("a.%v AS key, COUNT(a.%v) AS count", groupColumn, countColumn)).
-			From("catalog_entities a").
-			GroupBy(fmt.Sprintf("a.%v", groupColumn)).
-			OrderBy("count DESC, key").
-			Limit(uint64(pageSize)).
-			Offset(uint64((pageNumber - 1) * pageSize))
+	query := psql.
+		Update("personal_access_tokens").
+		Set("last_used_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id})
 
-	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
+
+	return
+}
+
+func (c *client) DeletePersonalAccessToken(ctx context.Context, token api.PersonalAccessToken) (err error) {
+	if token.ID == "" {
+		return fmt.Errorf("DeletePersonalAccessToken argument token.ID is empty")
+	}
+
+	// deactivate token, so it can no longer be used but remains visible for auditing
+	token.Active = false
+	token.Token = ""
+
+	tokenBytes, err := json.Marshal(token)
 	if err != nil {
 		return
 	}
-	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
-	if err != nil {
-		return
-	}
-	defer _CloseRows(rows)
 
-	return c.scanItems(rows)
-}
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-func (c *client) getCatalogEntityColumnCount(ctx context.Context, groupColumn string, filters map[api.FilterType][]string) (count int, err error) {
+	query := psql.
+		Update("personal_access_tokens").
+		Set("token_data", tokenBytes).
+		Set("active", false).
+		Where(sq.Eq{"id": token.ID})
 
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
 
-	query :=
-		psql.
-			Select(fmt.Sprintf("COUNT(a.%v)", groupColumn)).
-			From("catalog_entities a").
-			GroupBy(fmt.Sprintf("a.%v", groupColumn))
+	return
+}
 
-	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
-	if err != nil {
-		return
+func (c *client) GetPersonalAccessTokenByID(ctx context.Context, id string) (token *api.PersonalAccessToken, err error) {
+	if id == "" {
+		return nil, fmt.Errorf("GetPersonalAccessTokenByID argument id is empty")
 	}
 
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.user_id, a.token_data, a.active, a.inserted_at, a.last_used_at").
+		From("personal_access_tokens a").
+		Where(sq.Eq{"a.id": id}).
+		Where(sq.Eq{"a.active": true}).
+		Limit(uint64(1))
+
 	// execute query
 	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
-	if err = row.Scan(&count); err != nil {
-		return
-	}
 
-	return
+	return c.scanPersonalAccessToken(row)
 }
 
-func (c *client) GetCatalogEntityLabels(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (labels []map[string]interface{}, err error) {
+func (c *client) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (token *api.PersonalAccessToken, err error) {
+	if tokenHash == "" {
+		return nil, fmt.Errorf("GetPersonalAccessTokenByHash argument tokenHash is empty")
+	}
+
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	arrayElementsQuery :=
-		psql.
-			Select("a.id, jsonb_array_elements(a.labels) AS l").
-			From("catalog_entities a").
-			Where("jsonb_typeof(labels) = 'array'")
+	query := psql.
+		Select("a.id, a.user_id, a.token_data, a.active, a.inserted_at, a.last_used_at").
+		From("personal_access_tokens a").
+		Where(sq.Eq{"a.token_hash": tokenHash}).
+		Where(sq.Eq{"a.active": true}).
+		Limit(uint64(1))
 
-	arrayElementsQuery, err = whereClauseGeneratorForLabelsFilter(arrayElementsQuery, filters)
-	if err != nil {
-		return
-	}
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
 
-	selectCountQuery :=
-		psql.
-			Select("l->>'key' AS key, l->>'value' AS value, id").
-			FromSelect(arrayElementsQuery, "b")
+	return c.scanPersonalAccessToken(row)
+}
 
-	groupByQuery :=
-		psql.
-			Select("key, value, count(DISTINCT id) AS count").
-			FromSelect(selectCountQuery, "c").
-			GroupBy("key, value")
+func (c *client) GetPersonalAccessTokensForUser(ctx context.Context, userID string) (tokens []*api.PersonalAccessToken, err error) {
+	if userID == "" {
+		return nil, fmt.Errorf("GetPersonalAccessTokensForUser argument userID is empty")
+	}
 
-	query :=
-		psql.
-			Select("key, value, count").
-			FromSelect(groupByQuery, "d").
-			Where(sq.Gt{"count": 1}).
-			OrderBy("count DESC, key, value").
-			Limit(uint64(pageSize)).
-			Offset(uint64((pageNumber - 1) * pageSize))
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.user_id, a.token_data, a.active, a.inserted_at, a.last_used_at").
+		From("personal_access_tokens a").
+		Where(sq.Eq{"a.user_id": userID}).
+		Where(sq.Eq{"a.active": true}).
+		OrderBy("a.inserted_at DESC")
 
+	// execute query
 	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
 	if err != nil {
 		return
 	}
-	defer _CloseRows(rows)
 
-	return c.scanItems(rows)
+	return c.scanPersonalAccessTokens(rows)
 }
 
-func (c *client) GetCatalogEntityLabelsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-
-	arrayElementsQuery :=
-		psql.
-			Select("a.id, jsonb_array_elements(a.labels) AS l").
-			From("catalog_entities a").
-			Where("jsonb_typeof(labels) = 'array'")
-
-	arrayElementsQuery, err = whereClauseGeneratorForLabelsFilter(arrayElementsQuery, filters)
-	if err != nil {
-		return
+func (c *client) InsertClientSecret(ctx context.Context, secret api.ClientSecret, secretHash string) (insertedSecret *api.ClientSecret, err error) {
+	if secret.ClientID == "" {
+		return nil, fmt.Errorf("InsertClientSecret argument secret.ClientID is empty")
+	}
+	if secretHash == "" {
+		return nil, fmt.Errorf("InsertClientSecret argument secretHash is empty")
 	}
 
-	selectCountQuery :=
-		psql.
-			Select("l->>'key' AS key, l->>'value' AS value, id").
-			FromSelect(arrayElementsQuery, "b")
-
-	groupByQuery :=
-		psql.
-			Select("key, value, count(DISTINCT id) AS count").
-			FromSelect(selectCountQuery, "c").
-			GroupBy("key, value")
+	// the secret itself is never stored, only its hash
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+			client_secrets
+		(
+			client_id,
+			secret_hash
+		)
+		VALUES
+		(
+			$1,
+			$2
+		)
+		RETURNING
+			id,
+			inserted_at
+		`,
+		secret.ClientID,
+		secretHash,
+	)
 
-	query :=
-		psql.
-			Select("COUNT(key)").
-			FromSelect(groupByQuery, "d").
-			Where(sq.Gt{"count": 1})
+	insertedSecret = &secret
+	insertedSecret.Active = true
 
-	// execute query
-	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
-	if err = row.Scan(&count); err != nil {
-		return
+	var insertedAt time.Time
+	if err = row.Scan(&insertedSecret.ID, &insertedAt); err != nil {
+		return nil, err
 	}
+	insertedSecret.CreatedAt = &insertedAt
 
 	return
 }
 
-func (c *client) scanUsers(rows *sql.Rows) (users []*contracts.User, err error) {
-	users = make([]*contracts.User, 0)
-
-	defer _CloseRows(rows)
-	for rows.Next() {
-
-		user := &contracts.User{}
-		var id string
-		var userData []uint8
-		var insertedAt *time.Time
+func (c *client) HashLegacyClientSecret(ctx context.Context, client contracts.Client, secretHash string) (hashed bool, err error) {
+	if client.ID == "" {
+		return false, fmt.Errorf("HashLegacyClientSecret argument client.ID is empty")
+	}
+	if client.ClientSecret == "" {
+		return false, fmt.Errorf("HashLegacyClientSecret argument client.ClientSecret is empty")
+	}
+	if secretHash == "" {
+		return false, fmt.Errorf("HashLegacyClientSecret argument secretHash is empty")
+	}
 
-		if err = rows.Scan(
-			&id,
-			&userData,
-			&insertedAt,
-			&user.Active); err != nil {
-			return
+	// removing the plain text secret and inserting its hash happen together, so a failure leaves the plain text secret in place
+	tx, err := c.databaseConnection.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil || !hashed {
+			_ = tx.Rollback()
 		}
+	}()
 
-		if len(userData) > 0 {
-			if err = json.Unmarshal(userData, &user); err != nil {
-				return nil, err
-			}
-		}
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-		user.ID = id
-		user.FirstVisit = insertedAt
+	// only one of concurrent requests still finds the plain text secret, so its hash is inserted once
+	result, err := psql.
+		Update("clients").
+		Set("client_data", sq.Expr("client_data - 'clientSecret'")).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": client.ID}).
+		Where(sq.Eq{"client_data->>'clientSecret'": client.ClientSecret}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		// another request hashed the secret already
+		return false, err
+	}
 
-		users = append(users, user)
+	_, err = psql.
+		Insert("client_secrets").
+		Columns("client_id", "secret_hash").
+		Values(client.ID, secretHash).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return false, err
 	}
 
-	return
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
-func (c *client) scanUser(row sq.RowScanner) (user *contracts.User, err error) {
+func (c *client) UpdateClientSecretLastUsed(ctx context.Context, id string) (err error) {
+	if id == "" {
+		return fmt.Errorf("UpdateClientSecretLastUsed argument id is empty")
+	}
 
-	user = &contracts.User{}
-	var id string
-	var userData []uint8
-	var insertedAt *time.Time
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	if err = row.Scan(
-		&id,
-		&userData,
-		&insertedAt,
-		&user.Active); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrUserNotFound
-		}
+	query := psql.
+		Update("client_secrets").
+		Set("last_used_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id})
 
-		return
-	}
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
 
-	if len(userData) > 0 {
-		if err = json.Unmarshal(userData, &user); err != nil {
-			return nil, err
-		}
+	return
+}
+
+func (c *client) DeleteClientSecret(ctx context.Context, secret api.ClientSecret) (err error) {
+	if secret.ID == "" {
+		return fmt.Errorf("DeleteClientSecret argument secret.ID is empty")
 	}
 
-	user.ID = id
-	user.FirstVisit = insertedAt
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// deactivate secret, so it can no longer be used but remains visible for auditing
+	query := psql.
+		Update("client_secrets").
+		Set("active", false).
+		Where(sq.Eq{"id": secret.ID})
+
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
 
 	return
 }
 
-func (c *client) scanGroups(rows *sql.Rows) (groups []*contracts.Group, err error) {
-	groups = make([]*contracts.Group, 0)
+func (c *client) GetClientSecretByHash(ctx context.Context, clientID, secretHash string) (secret *api.ClientSecret, err error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("GetClientSecretByHash argument clientID is empty")
+	}
+	if secretHash == "" {
+		return nil, fmt.Errorf("GetClientSecretByHash argument secretHash is empty")
+	}
 
-	defer _CloseRows(rows)
-	for rows.Next() {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-		group := &contracts.Group{}
-		var id string
-		var groupData []uint8
-		var insertedAt *time.Time
+	query := psql.
+		Select("a.id, a.client_id, a.active, a.inserted_at, a.last_used_at").
+		From("client_secrets a").
+		Where(sq.Eq{"a.client_id": clientID}).
+		Where(sq.Eq{"a.secret_hash": secretHash}).
+		Where(sq.Eq{"a.active": true}).
+		Limit(uint64(1))
 
-		if err = rows.Scan(
-			&id,
-			&groupData,
-			&insertedAt,
-			&group.Active); err != nil {
-			return
-		}
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
 
-		if len(groupData) > 0 {
-			if err = json.Unmarshal(groupData, &group); err != nil {
-				return nil, err
-			}
-		}
+	return c.scanClientSecret(row)
+}
 
-		group.ID = id
+func (c *client) GetClientSecretsForClient(ctx context.Context, clientID string) (secrets []*api.ClientSecret, err error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("GetClientSecretsForClient argument clientID is empty")
+	}
 
-		groups = append(groups, group)
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.client_id, a.active, a.inserted_at, a.last_used_at").
+		From("client_secrets a").
+		Where(sq.Eq{"a.client_id": clientID}).
+		Where(sq.Eq{"a.active": true}).
+		OrderBy("a.inserted_at DESC")
+
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
 	}
 
-	return
+	return c.scanClientSecrets(rows)
 }
 
-func (c *client) scanGroup(row sq.RowScanner) (group *contracts.Group, err error) {
+func (c *client) InsertSession(ctx context.Context, session api.Session) (err error) {
+	if session.ID == "" {
+		return fmt.Errorf("InsertSession argument session.ID is empty")
+	}
+	if session.SubjectID == "" {
+		return fmt.Errorf("InsertSession argument session.SubjectID is empty")
+	}
 
-	group = &contracts.Group{}
-	var id string
-	var groupData []uint8
-	var insertedAt *time.Time
-
-	if err = row.Scan(
-		&id,
-		&groupData,
-		&insertedAt,
-		&group.Active); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrGroupNotFound
-		}
+	// revocation and the current flag are tracked in their own columns or per request
+	session.RevokedAt = nil
+	session.Current = false
 
+	sessionBytes, err := json.Marshal(session)
+	if err != nil {
 		return
 	}
 
-	if len(groupData) > 0 {
-		if err = json.Unmarshal(groupData, &group); err != nil {
-			return nil, err
-		}
-	}
-
-	group.ID = id
+	_, err = c.databaseConnection.ExecContext(ctx,
+		`
+		INSERT INTO
+			sessions
+		(
+			id,
+			subject_id,
+			session_data,
+			expires_at
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4
+		)
+		`,
+		session.ID,
+		session.SubjectID,
+		sessionBytes,
+		session.ExpiresAt,
+	)
 
 	return
 }
 
-func (c *client) scanOrganizations(rows *sql.Rows) (organizations []*contracts.Organization, err error) {
-	organizations = make([]*contracts.Organization, 0)
-
-	defer _CloseRows(rows)
-	for rows.Next() {
-
-		organization := &contracts.Organization{}
-		var id string
-		var organizationData []uint8
-		var insertedAt *time.Time
-
-		if err = rows.Scan(
-			&id,
-			&organizationData,
-			&insertedAt,
-			&organization.Active); err != nil {
-			return
-		}
+func (c *client) GetSessionByID(ctx context.Context, id string) (session *api.Session, err error) {
+	if id == "" {
+		return nil, fmt.Errorf("GetSessionByID argument id is empty")
+	}
 
-		if len(organizationData) > 0 {
-			if err = json.Unmarshal(organizationData, &organization); err != nil {
-				return nil, err
-			}
-		}
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-		organization.ID = id
+	query := psql.
+		Select("a.id, a.subject_id, a.session_data, a.expires_at, a.revoked_at").
+		From("sessions a").
+		Where(sq.Eq{"a.id": id}).
+		Limit(uint64(1))
 
-		organizations = append(organizations, organization)
-	}
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
 
-	return
+	return c.scanSession(row)
 }
 
-func (c *client) scanOrganization(row sq.RowScanner) (organization *contracts.Organization, err error) {
+func (c *client) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	if subjectID == "" {
+		return nil, fmt.Errorf("GetActiveSessionsForSubject argument subjectID is empty")
+	}
 
-	organization = &contracts.Organization{}
-	var id string
-	var organizationData []uint8
-	var insertedAt *time.Time
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	if err = row.Scan(
-		&id,
-		&organizationData,
-		&insertedAt,
-		&organization.Active); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrOrganizationNotFound
-		}
+	query := psql.
+		Select("a.id, a.subject_id, a.session_data, a.expires_at, a.revoked_at").
+		From("sessions a").
+		Where(sq.Eq{"a.subject_id": subjectID}).
+		Where(sq.Eq{"a.revoked_at": nil}).
+		Where(sq.Expr("a.expires_at > now()")).
+		OrderBy("a.inserted_at DESC")
 
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
 		return
 	}
 
-	if len(organizationData) > 0 {
-		if err = json.Unmarshal(organizationData, &organization); err != nil {
-			return nil, err
-		}
-	}
-
-	organization.ID = id
-
-	return
+	return c.scanSessions(rows)
 }
 
-func (c *client) scanClients(rows *sql.Rows) (clients []*contracts.Client, err error) {
-	clients = make([]*contracts.Client, 0)
-
-	defer _CloseRows(rows)
-	for rows.Next() {
-
-		client := &contracts.Client{}
-		var id string
-		var clientData []uint8
-		var insertedAt *time.Time
-
-		if err = rows.Scan(
-			&id,
-			&clientData,
-			&insertedAt,
-			&client.Active); err != nil {
-			return
-		}
+func (c *client) RevokeSession(ctx context.Context, id string) (err error) {
+	if id == "" {
+		return fmt.Errorf("RevokeSession argument id is empty")
+	}
 
-		if len(clientData) > 0 {
-			if err = json.Unmarshal(clientData, &client); err != nil {
-				return nil, err
-			}
-		}
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-		client.ID = id
+	query := psql.
+		Update("sessions").
+		Set("revoked_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"revoked_at": nil})
 
-		clients = append(clients, client)
-	}
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
 
 	return
 }
 
-func (c *client) scanClient(row sq.RowScanner) (client *contracts.Client, err error) {
+func (c *client) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+	if subjectID == "" {
+		return nil, fmt.Errorf("RevokeSessionsForSubject argument subjectID is empty")
+	}
 
-	client = &contracts.Client{}
-	var id string
-	var clientData []uint8
-	var insertedAt *time.Time
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-	if err = row.Scan(
-		&id,
-		&clientData,
-		&insertedAt,
-		&client.Active); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrClientNotFound
-		}
+	// sessions that have expired already don't need revoking
+	query := psql.
+		Update("sessions").
+		Set("revoked_at", sq.Expr("now()")).
+		Where(sq.Eq{"subject_id": subjectID}).
+		Where(sq.Eq{"revoked_at": nil}).
+		Where(sq.Expr("expires_at > now()")).
+		Suffix("RETURNING id")
 
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
 		return
 	}
 
-	if len(clientData) > 0 {
-		if err = json.Unmarshal(clientData, &client); err != nil {
+	revokedSessionIDs = make([]string, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
 			return nil, err
 		}
+		revokedSessionIDs = append(revokedSessionIDs, id)
 	}
 
-	client.ID = id
-
 	return
 }
 
-func (c *client) scanCatalogEntities(rows *sql.Rows) (catalogEntities []*contracts.CatalogEntity, err error) {
-	catalogEntities = make([]*contracts.CatalogEntity, 0)
-
-	defer _CloseRows(rows)
-	for rows.Next() {
+func (c *client) InsertCatalogEntity(ctx context.Context, catalogEntity contracts.CatalogEntity) (insertedCatalogEntity *contracts.CatalogEntity, err error) {
 
-		catalogEntity := &contracts.CatalogEntity{}
-		var labelData, metadataData []uint8
+	labelBytes, err := json.Marshal(catalogEntity.Labels)
+	if err != nil {
+		return nil, err
+	}
 
-		if err = rows.Scan(
-			&catalogEntity.ID,
-			&catalogEntity.ParentKey,
-			&catalogEntity.ParentValue,
-			&catalogEntity.Key,
-			&catalogEntity.Value,
-			&catalogEntity.LinkedPipeline,
-			&labelData,
-			&metadataData,
-			&catalogEntity.InsertedAt,
-			&catalogEntity.UpdatedAt); err != nil {
-			return
-		}
+	metadataBytes, err := json.Marshal(catalogEntity.Metadata)
+	if err != nil {
+		return nil, err
+	}
 
-		if len(labelData) > 0 {
-			if err = json.Unmarshal(labelData, &catalogEntity.Labels); err != nil {
-				return nil, err
-			}
-		}
+	// upsert user
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+		catalog_entities
+		(
+			parent_key,
+			parent_value,
+			entity_key,
+			entity_value,
+			linked_pipeline,
+			labels,
+			entity_metadata
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7
+		)
+		RETURNING
+			id
+		`,
+		catalogEntity.ParentKey,
+		catalogEntity.ParentValue,
+		catalogEntity.Key,
+		catalogEntity.Value,
+		catalogEntity.LinkedPipeline,
+		labelBytes,
+		metadataBytes,
+	)
 
-		if len(metadataData) > 0 {
-			if err = json.Unmarshal(metadataData, &catalogEntity.Metadata); err != nil {
-				return nil, err
-			}
-		}
+	insertedCatalogEntity = &catalogEntity
 
-		catalogEntities = append(catalogEntities, catalogEntity)
+	if err = row.Scan(&insertedCatalogEntity.ID); err != nil {
+		return nil, err
 	}
 
 	return
 }
 
-func (c *client) scanCatalogEntity(row sq.RowScanner) (catalogEntity *contracts.CatalogEntity, err error) {
-
-	catalogEntity = &contracts.CatalogEntity{}
-
-	var labelData, metadataData []uint8
+func (c *client) UpdateCatalogEntity(ctx context.Context, catalogEntity contracts.CatalogEntity) (err error) {
+	if catalogEntity.ID == "" {
+		return fmt.Errorf("UpdateCatalogEntity argument catalogEntity.ID is empty")
+	}
 
-	if err = row.Scan(
-		&catalogEntity.ID,
-		&catalogEntity.ParentKey,
-		&catalogEntity.ParentValue,
-		&catalogEntity.Key,
-		&catalogEntity.Value,
-		&catalogEntity.LinkedPipeline,
-		&labelData,
-		&metadataData,
-		&catalogEntity.InsertedAt,
-		&catalogEntity.UpdatedAt); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrCatalogEntityNotFound
-		}
+	labelBytes, err := json.Marshal(catalogEntity.Labels)
+	if err != nil {
+		return
+	}
 
+	metadataBytes, err := json.Marshal(catalogEntity.Metadata)
+	if err != nil {
 		return
 	}
 
-	if len(labelData) > 0 {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Update("catalog_entities").
+		Set("linked_pipeline", catalogEntity.LinkedPipeline).
+		Set("labels", labelBytes).
+		Set("entity_metadata", metadataBytes).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": catalogEntity.ID})
+
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
+
+	return
+}
+
+func (c *client) DeleteCatalogEntity(ctx context.Context, id string) (err error) {
+	if id == "" {
+		return fmt.Errorf("DeleteCatalogEntity argument id is empty")
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Delete("catalog_entities a").
+		Where(sq.Eq{"a.id": id})
+
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return nil
+}
+
+func (c *client) GetCatalogEntityByID(ctx context.Context, id string) (catalogEntity *contracts.CatalogEntity, err error) {
+	if id == "" {
+		return nil, fmt.Errorf("GetCatalogEntityByID argument id is empty")
+	}
+
+	query := c.selectCatalogEntityQuery().
+		Where(sq.Eq{"a.id": id}).
+		Limit(uint64(1))
+
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	catalogEntity, err = c.scanCatalogEntity(row)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalogEntity, nil
+}
+
+func (c *client) GetCatalogEntities(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (catalogEntities []*contracts.CatalogEntity, err error) {
+
+	query := c.selectCatalogEntityQuery().
+		Limit(uint64(pageSize)).
+		Offset(uint64((pageNumber - 1) * pageSize))
+
+	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err = orderByClauseGeneratorForSortings(query, "a.parent_key, a.parent_value, a.entity_key, a.entity_value", sortings)
+	if err != nil {
+		return nil, err
+	}
+
+	// execute query
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return c.scanCatalogEntities(rows)
+}
+
+func (c *client) GetCatalogEntitiesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("COUNT(a.id)").
+		From("catalog_entities a")
+
+	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
+	if err != nil {
+		return
+	}
+
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&count); err != nil {
+		return
+	}
+
+	return
+}
+
+func (c *client) GetCatalogEntityParentKeys(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (keys []map[string]interface{}, err error) {
+	return c.getCatalogEntityColumn(ctx, "parent_key", "id", pageNumber, pageSize, filters, sortings)
+}
+
+func (c *client) GetCatalogEntityParentKeysCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	return c.getCatalogEntityColumnCount(ctx, "parent_key", filters)
+}
+
+func (c *client) GetCatalogEntityParentValues(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (values []map[string]interface{}, err error) {
+	return c.getCatalogEntityColumn(ctx, "parent_value", "id", pageNumber, pageSize, filters, sortings)
+}
+
+func (c *client) GetCatalogEntityParentValuesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	return c.getCatalogEntityColumnCount(ctx, "parent_value", filters)
+}
+
+func (c *client) GetCatalogEntityKeys(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (keys []map[string]interface{}, err error) {
+	return c.getCatalogEntityColumn(ctx, "entity_key", "id", pageNumber, pageSize, filters, sortings)
+}
+
+func (c *client) GetCatalogEntityKeysCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	return c.getCatalogEntityColumnCount(ctx, "entity_key", filters)
+}
+
+func (c *client) GetCatalogEntityValues(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (values []map[string]interface{}, err error) {
+	return c.getCatalogEntityColumn(ctx, "entity_value", "id", pageNumber, pageSize, filters, sortings)
+}
+
+func (c *client) GetCatalogEntityValuesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	return c.getCatalogEntityColumnCount(ctx, "entity_value", filters)
+}
+
+func (c *client) getCatalogEntityColumn(ctx context.Context, groupColumn, countColumn string, pageNumber, pageSize int, filters map[api.FilterType][]string, _ []api.OrderField) (keys []map[string]interface{}, err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query :=
+		psql.
+			Select(fmt.Sprintf("a.%v AS key, COUNT(a.%v) AS count", groupColumn, countColumn)).
+			From("catalog_entities a").
+			GroupBy(fmt.Sprintf("a.%v", groupColumn)).
+			OrderBy("count DESC, key").
+			Limit(uint64(pageSize)).
+			Offset(uint64((pageNumber - 1) * pageSize))
+
+	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
+	if err != nil {
+		return
+	}
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+	defer _CloseRows(rows)
+
+	return c.scanItems(rows)
+}
+
+func (c *client) getCatalogEntityColumnCount(ctx context.Context, groupColumn string, filters map[api.FilterType][]string) (count int, err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query :=
+		psql.
+			Select(fmt.Sprintf("COUNT(a.%v)", groupColumn)).
+			From("catalog_entities a").
+			GroupBy(fmt.Sprintf("a.%v", groupColumn))
+
+	query, err = whereClauseGeneratorForCatalogEntityFilters(query, filters)
+	if err != nil {
+		return
+	}
+
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&count); err != nil {
+		return
+	}
+
+	return
+}
+
+func (c *client) GetCatalogEntityLabels(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (labels []map[string]interface{}, err error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	arrayElementsQuery :=
+		psql.
+			Select("a.id, jsonb_array_elements(a.labels) AS l").
+			From("catalog_entities a").
+			Where("jsonb_typeof(labels) = 'array'")
+
+	arrayElementsQuery, err = whereClauseGeneratorForLabelsFilter(arrayElementsQuery, filters)
+	if err != nil {
+		return
+	}
+
+	selectCountQuery :=
+		psql.
+			Select("l->>'key' AS key, l->>'value' AS value, id").
+			FromSelect(arrayElementsQuery, "b")
+
+	groupByQuery :=
+		psql.
+			Select("key, value, count(DISTINCT id) AS count").
+			FromSelect(selectCountQuery, "c").
+			GroupBy("key, value")
+
+	query :=
+		psql.
+			Select("key, value, count").
+			FromSelect(groupByQuery, "d").
+			Where(sq.Gt{"count": 1}).
+			OrderBy("count DESC, key, value").
+			Limit(uint64(pageSize)).
+			Offset(uint64((pageNumber - 1) * pageSize))
+
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+	defer _CloseRows(rows)
+
+	return c.scanItems(rows)
+}
+
+func (c *client) GetCatalogEntityLabelsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	arrayElementsQuery :=
+		psql.
+			Select("a.id, jsonb_array_elements(a.labels) AS l").
+			From("catalog_entities a").
+			Where("jsonb_typeof(labels) = 'array'")
+
+	arrayElementsQuery, err = whereClauseGeneratorForLabelsFilter(arrayElementsQuery, filters)
+	if err != nil {
+		return
+	}
+
+	selectCountQuery :=
+		psql.
+			Select("l->>'key' AS key, l->>'value' AS value, id").
+			FromSelect(arrayElementsQuery, "b")
+
+	groupByQuery :=
+		psql.
+			Select("key, value, count(DISTINCT id) AS count").
+			FromSelect(selectCountQuery, "c").
+			GroupBy("key, value")
+
+	query :=
+		psql.
+			Select("COUNT(key)").
+			FromSelect(groupByQuery, "d").
+			Where(sq.Gt{"count": 1})
+
+	// execute query
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&count); err != nil {
+		return
+	}
+
+	return
+}
+
+func (c *client) scanUsers(rows *sql.Rows) (users []*contracts.User, err error) {
+	users = make([]*contracts.User, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		user := &contracts.User{}
+		var id string
+		var userData []uint8
+		var insertedAt *time.Time
+
+		if err = rows.Scan(
+			&id,
+			&userData,
+			&insertedAt,
+			&user.Active); err != nil {
+			return
+		}
+
+		if len(userData) > 0 {
+			if err = json.Unmarshal(userData, &user); err != nil {
+				return nil, err
+			}
+		}
+
+		user.ID = id
+		user.FirstVisit = insertedAt
+
+		users = append(users, user)
+	}
+
+	return
+}
+
+func (c *client) scanUser(row sq.RowScanner) (user *contracts.User, err error) {
+
+	user = &contracts.User{}
+	var id string
+	var userData []uint8
+	var insertedAt *time.Time
+
+	if err = row.Scan(
+		&id,
+		&userData,
+		&insertedAt,
+		&user.Active); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+
+		return
+	}
+
+	if len(userData) > 0 {
+		if err = json.Unmarshal(userData, &user); err != nil {
+			return nil, err
+		}
+	}
+
+	user.ID = id
+	user.FirstVisit = insertedAt
+
+	return
+}
+
+func (c *client) scanGroups(rows *sql.Rows) (groups []*contracts.Group, err error) {
+	groups = make([]*contracts.Group, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		group := &contracts.Group{}
+		var id string
+		var groupData []uint8
+		var insertedAt *time.Time
+
+		if err = rows.Scan(
+			&id,
+			&groupData,
+			&insertedAt,
+			&group.Active); err != nil {
+			return
+		}
+
+		if len(groupData) > 0 {
+			if err = json.Unmarshal(groupData, &group); err != nil {
+				return nil, err
+			}
+		}
+
+		group.ID = id
+
+		groups = append(groups, group)
+	}
+
+	return
+}
+
+func (c *client) scanGroup(row sq.RowScanner) (group *contracts.Group, err error) {
+
+	group = &contracts.Group{}
+	var id string
+	var groupData []uint8
+	var insertedAt *time.Time
+
+	if err = row.Scan(
+		&id,
+		&groupData,
+		&insertedAt,
+		&group.Active); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrGroupNotFound
+		}
+
+		return
+	}
+
+	if len(groupData) > 0 {
+		if err = json.Unmarshal(groupData, &group); err != nil {
+			return nil, err
+		}
+	}
+
+	group.ID = id
+
+	return
+}
+
+func (c *client) scanOrganizations(rows *sql.Rows) (organizations []*contracts.Organization, err error) {
+	organizations = make([]*contracts.Organization, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		organization := &contracts.Organization{}
+		var id string
+		var organizationData []uint8
+		var insertedAt *time.Time
+
+		if err = rows.Scan(
+			&id,
+			&organizationData,
+			&insertedAt,
+			&organization.Active); err != nil {
+			return
+		}
+
+		if len(organizationData) > 0 {
+			if err = json.Unmarshal(organizationData, &organization); err != nil {
+				return nil, err
+			}
+		}
+
+		organization.ID = id
+
+		organizations = append(organizations, organization)
+	}
+
+	return
+}
+
+func (c *client) scanOrganization(row sq.RowScanner) (organization *contracts.Organization, err error) {
+
+	organization = &contracts.Organization{}
+	var id string
+	var organizationData []uint8
+	var insertedAt *time.Time
+
+	if err = row.Scan(
+		&id,
+		&organizationData,
+		&insertedAt,
+		&organization.Active); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrOrganizationNotFound
+		}
+
+		return
+	}
+
+	if len(organizationData) > 0 {
+		if err = json.Unmarshal(organizationData, &organization); err != nil {
+			return nil, err
+		}
+	}
+
+	organization.ID = id
+
+	return
+}
+
+func (c *client) scanClients(rows *sql.Rows) (clients []*contracts.Client, err error) {
+	clients = make([]*contracts.Client, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		client := &contracts.Client{}
+		var id string
+		var clientData []uint8
+		var insertedAt *time.Time
+
+		if err = rows.Scan(
+			&id,
+			&clientData,
+			&insertedAt,
+			&client.Active); err != nil {
+			return
+		}
+
+		if len(clientData) > 0 {
+			if err = json.Unmarshal(clientData, &client); err != nil {
+				return nil, err
+			}
+		}
+
+		client.ID = id
+
+		clients = append(clients, client)
+	}
+
+	return
+}
+
+func (c *client) scanClient(row sq.RowScanner) (client *contracts.Client, err error) {
+
+	client = &contracts.Client{}
+	var id string
+	var clientData []uint8
+	var insertedAt *time.Time
+
+	if err = row.Scan(
+		&id,
+		&clientData,
+		&insertedAt,
+		&client.Active); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrClientNotFound
+		}
+
+		return
+	}
+
+	if len(clientData) > 0 {
+		if err = json.Unmarshal(clientData, &client); err != nil {
+			return nil, err
+		}
+	}
+
+	client.ID = id
+
+	return
+}
+
+func (c *client) scanCustomRoles(rows *sql.Rows) (customRoles []*api.CustomRole, err error) {
+	customRoles = make([]*api.CustomRole, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		customRole, err := c.scanCustomRole(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		customRoles = append(customRoles, customRole)
+	}
+
+	return
+}
+
+func (c *client) scanCustomRole(row sq.RowScanner) (customRole *api.CustomRole, err error) {
+
+	customRole = &api.CustomRole{}
+	var id string
+	var customRoleData []uint8
+
+	if err = row.Scan(
+		&id,
+		&customRoleData,
+		&customRole.Active); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCustomRoleNotFound
+		}
+
+		return
+	}
+
+	if len(customRoleData) > 0 {
+		if err = json.Unmarshal(customRoleData, &customRole); err != nil {
+			return nil, err
+		}
+	}
+
+	customRole.ID = id
+
+	return
+}
+
+func (c *client) scanPersonalAccessTokens(rows *sql.Rows) (tokens []*api.PersonalAccessToken, err error) {
+	tokens = make([]*api.PersonalAccessToken, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		token, err := c.scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return
+}
+
+func (c *client) scanPersonalAccessToken(row sq.RowScanner) (token *api.PersonalAccessToken, err error) {
+
+	token = &api.PersonalAccessToken{}
+	var id, userID string
+	var tokenData []uint8
+	var active bool
+	var insertedAt time.Time
+	var lastUsedAt sql.NullTime
+
+	if err = row.Scan(
+		&id,
+		&userID,
+		&tokenData,
+		&active,
+		&insertedAt,
+		&lastUsedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrPersonalAccessTokenNotFound
+		}
+
+		return
+	}
+
+	if len(tokenData) > 0 {
+		if err = json.Unmarshal(tokenData, &token); err != nil {
+			return nil, err
+		}
+	}
+
+	token.ID = id
+	token.UserID = userID
+	token.Active = active
+	token.CreatedAt = &insertedAt
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return
+}
+
+func (c *client) scanClientSecrets(rows *sql.Rows) (secrets []*api.ClientSecret, err error) {
+	secrets = make([]*api.ClientSecret, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		secret, err := c.scanClientSecret(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return
+}
+
+func (c *client) scanClientSecret(row sq.RowScanner) (secret *api.ClientSecret, err error) {
+
+	secret = &api.ClientSecret{}
+	var insertedAt time.Time
+	var lastUsedAt sql.NullTime
+
+	if err = row.Scan(
+		&secret.ID,
+		&secret.ClientID,
+		&secret.Active,
+		&insertedAt,
+		&lastUsedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrClientSecretNotFound
+		}
+
+		return
+	}
+
+	secret.CreatedAt = &insertedAt
+	if lastUsedAt.Valid {
+		secret.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return
+}
+
+func (c *client) scanSessions(rows *sql.Rows) (sessions []*api.Session, err error) {
+	sessions = make([]*api.Session, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		session, err := c.scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return
+}
+
+func (c *client) scanSession(row sq.RowScanner) (session *api.Session, err error) {
+
+	session = &api.Session{}
+	var id, subjectID string
+	var sessionData []uint8
+	var expiresAt time.Time
+	var revokedAt sql.NullTime
+
+	if err = row.Scan(
+		&id,
+		&subjectID,
+		&sessionData,
+		&expiresAt,
+		&revokedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSessionNotFound
+		}
+
+		return
+	}
+
+	if len(sessionData) > 0 {
+		if err = json.Unmarshal(sessionData, &session); err != nil {
+			return nil, err
+		}
+	}
+
+	session.ID = id
+	session.SubjectID = subjectID
+	session.ExpiresAt = expiresAt
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return
+}
+
+func (c *client) scanCatalogEntities(rows *sql.Rows) (catalogEntities []*contracts.CatalogEntity, err error) {
+	catalogEntities = make([]*contracts.CatalogEntity, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		catalogEntity := &contracts.CatalogEntity{}
+		var labelData, metadataData []uint8
+
+		if err = rows.Scan(
+			&catalogEntity.ID,
+			&catalogEntity.ParentKey,
+			&catalogEntity.ParentValue,
+			&catalogEntity.Key,
+			&catalogEntity.Value,
+			&catalogEntity.LinkedPipeline,
+			&labelData,
+			&metadataData,
+			&catalogEntity.InsertedAt,
+			&catalogEntity.UpdatedAt); err != nil {
+			return
+		}
+
+		if len(labelData) > 0 {
+			if err = json.Unmarshal(labelData, &catalogEntity.Labels); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(metadataData) > 0 {
+			if err = json.Unmarshal(metadataData, &catalogEntity.Metadata); err != nil {
+				return nil, err
+			}
+		}
+
+		catalogEntities = append(catalogEntities, catalogEntity)
+	}
+
+	return
+}
+
+func (c *client) scanCatalogEntity(row sq.RowScanner) (catalogEntity *contracts.CatalogEntity, err error) {
+
+	catalogEntity = &contracts.CatalogEntity{}
+
+	var labelData, metadataData []uint8
+
+	if err = row.Scan(
+		&catalogEntity.ID,
+		&catalogEntity.ParentKey,
+		&catalogEntity.ParentValue,
+		&catalogEntity.Key,
+		&catalogEntity.Value,
+		&catalogEntity.LinkedPipeline,
+		&labelData,
+		&metadataData,
+		&catalogEntity.InsertedAt,
+		&catalogEntity.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCatalogEntityNotFound
+		}
+
+		return
+	}
+
+	if len(labelData) > 0 {
 		if err = json.Unmarshal(labelData, &catalogEntity.Labels); err != nil {
 			return nil, err
 		}
 	}
 
-	if len(metadataData) > 0 {
-		if err = json.Unmarshal(metadataData, &catalogEntity.Metadata); err != nil {
-			return nil, err
+	if len(metadataData) > 0 {
+		if err = json.Unmarshal(metadataData, &catalogEntity.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+func (c *client) selectBuildsQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.repo_branch, a.repo_revision, a.build_version, a.build_status, a.labels, a.release_targets, a.manifest, a.commits, a.triggers, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.groups, a.organizations").
+		From("builds a")
+}
+
+func (c *client) selectPipelinesQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select("a.pipeline_id, a.repo_source, a.repo_owner, a.repo_name, a.repo_branch, a.repo_revision, a.build_version, a.build_status, a.labels, a.release_targets, a.manifest, a.commits, a.triggers, a.archived, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.last_updated_at, a.triggered_by_event, a.extra_info, a.groups, a.organizations").
+		From("computed_pipelines a")
+}
+
+func (c *client) selectReleasesQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.release, a.release_action, a.release_version, a.release_status, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.groups, a.organizations").
+		From("releases a")
+}
+
+func (c *client) selectBotsQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.bot, a.bot_status, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.groups, a.organizations").
+		From("bots a")
+}
+
+func (c *client) selectNotificationsQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select("a.id, a.link_type, a.link_id, a.link_detail, a.source, a.notifications, a.inserted_at, a.groups, a.organizations").
+		From("notifications a")
+}
+
+func (c *client) selectComputedReleasesQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select("a.release_id, a.repo_source, a.repo_owner, a.repo_name, a.release, a.release_action, a.release_version, a.release_status, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.extra_info, a.groups, a.organizations").
+		From("computed_releases a")
+}
+
+func (c *client) selectBuildLogsQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	return psql.
+		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.repo_branch, a.repo_revision, a.build_id, a.inserted_at").
+		From("build_logs a")
+}
+
+func (c *client) selectReleaseLogsQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	return psql.
+		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.release_id, a.inserted_at").
+		From("release_logs a")
+}
+
+func (c *client) selectBotLogsQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	return psql.
+		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.bot_id, a.inserted_at").
+		From("bot_logs a")
+}
+
+func (c *client) selectCatalogEntityQuery() sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select("a.id, a.parent_key, a.parent_value, a.entity_key, a.entity_value, a.linked_pipeline, a.labels, a.entity_metadata, a.inserted_at, a.updated_at").
+		From("catalog_entities a")
+}
+
+// selectJobResourceUsagesQuery selects the resources of the most recent jobs with measured usage from the builds, releases or bots table
+func (c *client) selectJobResourceUsagesQuery(table, statusColumn string) sq.SelectBuilder {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	return psql.
+		Select(fmt.Sprintf("COALESCE(cpu_request,0), COALESCE(cpu_limit,0), cpu_max_usage, COALESCE(memory_request,0), COALESCE(memory_limit,0), memory_max_usage, COALESCE(oom_killed,false), COALESCE(cpu_throttled,false), %v, inserted_at", statusColumn)).
+		From(table).
+		Where(sq.NotEq{"cpu_max_usage": nil}).
+		Where(sq.NotEq{"memory_max_usage": nil}).
+		OrderBy("inserted_at DESC")
+}
+
+func (c *client) enrichPipeline(ctx context.Context, pipeline *contracts.Pipeline) {
+	c.getLatestReleasesForPipeline(ctx, pipeline)
+}
+
+func (c *client) enrichBuild(ctx context.Context, build *contracts.Build) {
+	c.getLatestReleasesForBuild(ctx, build)
+}
+
+func (c *client) setPipelinePropertiesFromJSONB(pipeline *contracts.Pipeline, labelsData, releaseTargetsData, commitsData, triggersData, triggeredByEventsData, extraInfoData, groupsData, organizationsData []uint8, optimized bool) (err error) {
+
+	if len(labelsData) > 0 {
+		if err = json.Unmarshal(labelsData, &pipeline.Labels); err != nil {
+			return
+		}
+	}
+	if len(releaseTargetsData) > 0 {
+		if err = json.Unmarshal(releaseTargetsData, &pipeline.ReleaseTargets); err != nil {
+			return
+		}
+	}
+	if len(commitsData) > 0 {
+		if err = json.Unmarshal(commitsData, &pipeline.Commits); err != nil {
+			return
+		}
+
+		// remove all but the first 6 commits
+		if len(pipeline.Commits) > 6 {
+			pipeline.Commits = pipeline.Commits[:6]
+		}
+	}
+	if len(triggersData) > 0 {
+		if err = json.Unmarshal(triggersData, &pipeline.Triggers); err != nil {
+			return
+		}
+	}
+	if len(triggeredByEventsData) > 0 {
+		if err = json.Unmarshal(triggeredByEventsData, &pipeline.Events); err != nil {
+			return
+		}
+	}
+	if len(extraInfoData) > 0 {
+		if err = json.Unmarshal(extraInfoData, &pipeline.ExtraInfo); err != nil {
+			return
+		}
+	}
+	if len(groupsData) > 0 {
+		if err = json.Unmarshal(groupsData, &pipeline.Groups); err != nil {
+			return
+		}
+	}
+	if len(organizationsData) > 0 {
+		if err = json.Unmarshal(organizationsData, &pipeline.Organizations); err != nil {
+			return
+		}
+	}
+
+	if !optimized {
+		// unmarshal then marshal manifest to include defaults
+		_manifest, err := manifest.ReadManifest(c.config.ManifestPreferences, pipeline.Manifest, false)
+		if err == nil {
+			pipeline.ManifestObject = &_manifest
+			manifestWithDefaultBytes, err := yaml.Marshal(_manifest)
+			if err == nil {
+				pipeline.ManifestWithDefaults = string(manifestWithDefaultBytes)
+			} else {
+				log.Warn().Err(err).Interface("manifest", _manifest).Msgf("Marshalling manifest for %v/%v/%v revision %v failed", pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, pipeline.RepoRevision)
+			}
+		} else {
+			log.Debug().Err(err).Str("manifest", pipeline.Manifest).Msgf("Unmarshalling manifest for %v/%v/%v revision %v failed", pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, pipeline.RepoRevision)
+		}
+	}
+
+	return
+}
+
+func (c *client) setBuildPropertiesFromJSONB(build *contracts.Build, labelsData, releaseTargetsData, commitsData, triggersData, triggeredByEventsData, groupsData, organizationsData []uint8, optimized bool) (err error) {
+
+	if len(labelsData) > 0 {
+		if err = json.Unmarshal(labelsData, &build.Labels); err != nil {
+			return
+		}
+	}
+	if len(releaseTargetsData) > 0 {
+		if err = json.Unmarshal(releaseTargetsData, &build.ReleaseTargets); err != nil {
+			return
+		}
+	}
+	if len(commitsData) > 0 {
+		if err = json.Unmarshal(commitsData, &build.Commits); err != nil {
+			return
+		}
+
+		// remove all but the first 6 commits
+		if len(build.Commits) > 6 {
+			build.Commits = build.Commits[:6]
+		}
+	}
+	if len(triggersData) > 0 {
+		if err = json.Unmarshal(triggersData, &build.Triggers); err != nil {
+			return
+		}
+	}
+	if len(triggeredByEventsData) > 0 {
+		if err = json.Unmarshal(triggeredByEventsData, &build.Events); err != nil {
+			return
+		}
+	}
+	if len(groupsData) > 0 {
+		if err = json.Unmarshal(groupsData, &build.Groups); err != nil {
+			return
+		}
+	}
+	if len(organizationsData) > 0 {
+		if err = json.Unmarshal(organizationsData, &build.Organizations); err != nil {
+			return
+		}
+	}
+
+	if !optimized {
+		// unmarshal then marshal manifest to include defaults
+		_manifest, err := manifest.ReadManifest(c.config.ManifestPreferences, build.Manifest, false)
+		if err == nil {
+			build.ManifestObject = &_manifest
+			manifestWithDefaultBytes, err := yaml.Marshal(_manifest)
+			if err == nil {
+				build.ManifestWithDefaults = string(manifestWithDefaultBytes)
+			} else {
+				log.Warn().Err(err).Interface("manifest", _manifest).Msgf("Marshalling manifest for %v/%v/%v revision %v failed", build.RepoSource, build.RepoOwner, build.RepoName, build.RepoRevision)
+			}
+		} else {
+			log.Debug().Err(err).Str("manifest", build.Manifest).Msgf("Unmarshalling manifest for %v/%v/%v revision %v failed", build.RepoSource, build.RepoOwner, build.RepoName, build.RepoRevision)
+		}
+	}
+
+	return
+}
+
+func (c *client) setReleasePropertiesFromJSONB(release *contracts.Release, triggeredByEventsData, groupsData, organizationsData []uint8) (err error) {
+
+	if len(triggeredByEventsData) > 0 {
+		if err = json.Unmarshal(triggeredByEventsData, &release.Events); err != nil {
+			return
+		}
+	}
+	if len(groupsData) > 0 {
+		if err = json.Unmarshal(groupsData, &release.Groups); err != nil {
+			return
+		}
+	}
+	if len(organizationsData) > 0 {
+		if err = json.Unmarshal(organizationsData, &release.Organizations); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (c *client) setBotPropertiesFromJSONB(bot *contracts.Bot, triggeredByEventsData, groupsData, organizationsData []uint8) (err error) {
+
+	if len(triggeredByEventsData) > 0 {
+		if err = json.Unmarshal(triggeredByEventsData, &bot.Events); err != nil {
+			return
+		}
+	}
+	if len(groupsData) > 0 {
+		if err = json.Unmarshal(groupsData, &bot.Groups); err != nil {
+			return
+		}
+	}
+	if len(organizationsData) > 0 {
+		if err = json.Unmarshal(organizationsData, &bot.Organizations); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (c *client) getLatestReleasesForPipeline(ctx context.Context, pipeline *contracts.Pipeline) {
+	pipeline.ReleaseTargets = c.getLatestReleases(ctx, pipeline.ReleaseTargets, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName)
+}
+
+func (c *client) getLatestReleasesForBuild(ctx context.Context, build *contracts.Build) {
+	build.ReleaseTargets = c.getLatestReleases(ctx, build.ReleaseTargets, build.RepoSource, build.RepoOwner, build.RepoName)
+}
+
+func (c *client) getLatestReleases(ctx context.Context, releaseTargets []contracts.ReleaseTarget, repoSource, repoOwner, repoName string) []contracts.ReleaseTarget {
+
+	// set latest release version per release targets
+	updatedReleaseTargets := make([]contracts.ReleaseTarget, 0)
+	for _, rt := range releaseTargets {
+
+		actions := getActionNamesFromReleaseTarget(rt)
+		latestReleases, err := c.GetPipelineLastReleasesByName(ctx, repoSource, repoOwner, repoName, rt.Name, actions)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed retrieving latest release for %v/%v/%v %v", repoSource, repoOwner, repoName, rt.Name)
+		} else {
+			rt.ActiveReleases = latestReleases
+		}
+		updatedReleaseTargets = append(updatedReleaseTargets, rt)
+	}
+
+	return updatedReleaseTargets
+}
+
+func getActionNamesFromReleaseTarget(releaseTarget contracts.ReleaseTarget) (actions []string) {
+
+	actions = []string{}
+
+	if releaseTarget.Actions != nil && len(releaseTarget.Actions) > 0 {
+		for _, a := range releaseTarget.Actions {
+			actions = append(actions, a.Name)
+		}
+	}
+
+	return
+}
+
+func (c *client) mapBuildToPipeline(build *contracts.Build) (pipeline *contracts.Pipeline) {
+
+	// get archived value from manifest
+	mft, err := manifest.ReadManifest(c.config.ManifestPreferences, build.Manifest, false)
+	archived := false
+	if err == nil {
+		archived = mft.Archived
+	}
+
+	return &contracts.Pipeline{
+		ID:                   build.ID,
+		RepoSource:           build.RepoSource,
+		RepoOwner:            build.RepoOwner,
+		RepoName:             build.RepoName,
+		RepoBranch:           build.RepoBranch,
+		RepoRevision:         build.RepoRevision,
+		BuildVersion:         build.BuildVersion,
+		BuildStatus:          build.BuildStatus,
+		Labels:               build.Labels,
+		ReleaseTargets:       build.ReleaseTargets,
+		Manifest:             build.Manifest,
+		ManifestWithDefaults: build.ManifestWithDefaults,
+		Commits:              build.Commits,
+		Groups:               build.Groups,
+		Organizations:        build.Organizations,
+		Triggers:             build.Triggers,
+		Archived:             archived,
+		InsertedAt:           build.InsertedAt,
+		StartedAt:            build.StartedAt,
+		UpdatedAt:            build.UpdatedAt,
+		Duration:             build.Duration,
+		Events:               build.Events,
+	}
+}
+
+func (c *client) InsertQueuedJob(ctx context.Context, queuedJob QueuedJob) (qj *QueuedJob, err error) {
+
+	organizationsBytes, err := json.Marshal(queuedJob.Organizations)
+	if err != nil {
+		return
+	}
+
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+			job_queue
+		(
+			job_type,
+			job_id,
+			repo_source,
+			repo_owner,
+			repo_name,
+			organizations,
+			ci_builder_params,
+			attempts
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7,
+			$8
+		)
+		RETURNING
+			id,
+			queued_at
+		`,
+		queuedJob.JobType,
+		queuedJob.JobID,
+		queuedJob.RepoSource,
+		queuedJob.RepoOwner,
+		queuedJob.RepoName,
+		organizationsBytes,
+		queuedJob.CiBuilderParams,
+		queuedJob.Attempts,
+	)
+
+	qj = &queuedJob
+
+	if err = row.Scan(&qj.ID, &qj.QueuedAt); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+func (c *client) GetQueuedJobs(ctx context.Context, limit int) (queuedJobs []*QueuedJob, err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.job_type, a.job_id, a.repo_source, a.repo_owner, a.repo_name, a.organizations, a.ci_builder_params, a.queued_at, a.attempts").
+		From("job_queue a").
+		OrderBy("a.queued_at ASC", "a.id ASC")
+
+	if limit > 0 {
+		query = query.Limit(uint64(limit))
+	}
+
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return c.scanQueuedJobs(rows)
+}
+
+func (c *client) DeleteQueuedJob(ctx context.Context, jobType contracts.JobType, jobID string) (deleted bool, err error) {
+	if jobID == "" {
+		return false, fmt.Errorf("DeleteQueuedJob argument jobID is empty")
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Delete("job_queue").
+		Where(sq.Eq{"job_type": jobType}).
+		Where(sq.Eq{"job_id": jobID})
+
+	result, err := query.RunWith(c.databaseConnection).ExecContext(ctx)
+	if err != nil {
+		return
+	}
+
+	// only one caller gets to delete the record, which makes this usable to claim a job when running multiple replicas
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (c *client) ClaimQueuedJob(ctx context.Context, queuedJob QueuedJob) (claimed bool, err error) {
+	table, statusColumn, err := getJobTableAndStatusColumn(queuedJob.JobType)
+	if err != nil {
+		return false, err
+	}
+
+	// taking the job off the queue and marking it pending happen together, so a failure leaves it queued instead of lost
+	tx, err := c.databaseConnection.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil || !claimed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	result, err := psql.
+		Delete("job_queue").
+		Where(sq.Eq{"job_type": queuedJob.JobType}).
+		Where(sq.Eq{"job_id": queuedJob.JobID}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		// another replica took the job
+		return false, err
+	}
+
+	result, err = psql.
+		Update(table).
+		Set(statusColumn, string(contracts.StatusPending)).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": queuedJob.JobID}).
+		Where(sq.Eq{"repo_source": queuedJob.RepoSource}).
+		Where(sq.Eq{"repo_owner": queuedJob.RepoOwner}).
+		Where(sq.Eq{"repo_name": queuedJob.RepoName}).
+		Where(sq.Eq{statusColumn: string(api.StatusQueued)}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err = result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		// the job is no longer queued, for example because it got canceled
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *client) RequeueJob(ctx context.Context, queuedJob QueuedJob) (err error) {
+	table, statusColumn, err := getJobTableAndStatusColumn(queuedJob.JobType)
+	if err != nil {
+		return err
+	}
+
+	organizationsBytes, err := json.Marshal(queuedJob.Organizations)
+	if err != nil {
+		return
+	}
+
+	tx, err := c.databaseConnection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// the job keeps its original place in the queue, with one more failed attempt
+	_, err = psql.
+		Insert("job_queue").
+		Columns("job_type", "job_id", "repo_source", "repo_owner", "repo_name", "organizations", "ci_builder_params", "queued_at", "attempts").
+		Values(queuedJob.JobType, queuedJob.JobID, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, organizationsBytes, queuedJob.CiBuilderParams, queuedJob.QueuedAt, queuedJob.Attempts+1).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = psql.
+		Update(table).
+		Set(statusColumn, string(api.StatusQueued)).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": queuedJob.JobID}).
+		Where(sq.Eq{"repo_source": queuedJob.RepoSource}).
+		Where(sq.Eq{"repo_owner": queuedJob.RepoOwner}).
+		Where(sq.Eq{"repo_name": queuedJob.RepoName}).
+		Where(sq.Eq{statusColumn: string(contracts.StatusPending)}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (c *client) AcquireLock(ctx context.Context, name, holder string, ttl time.Duration) (acquired bool, err error) {
+
+	// the lock is taken over once it expires, so a replica that stops while holding it doesn't block the others forever
+	rows, err := c.databaseConnection.QueryContext(ctx,
+		`
+		INSERT INTO
+			locks
+		(
+			name,
+			holder,
+			expires_at
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3
+		)
+		ON CONFLICT
+			(name)
+		DO UPDATE SET
+			holder = excluded.holder,
+			expires_at = excluded.expires_at
+		WHERE
+			locks.holder = excluded.holder OR locks.expires_at < now()
+		RETURNING
+			holder
+		`,
+		name,
+		holder,
+		time.Now().UTC().Add(ttl),
+	)
+	if err != nil {
+		return false, err
+	}
+	defer _CloseRows(rows)
+
+	return rows.Next(), rows.Err()
+}
+
+func (c *client) ReleaseLock(ctx context.Context, name, holder string) (err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	_, err = psql.
+		Delete("locks").
+		Where(sq.Eq{"name": name}).
+		Where(sq.Eq{"holder": holder}).
+		RunWith(c.databaseConnection).
+		ExecContext(ctx)
+
+	return
+}
+
+func getJobTableAndStatusColumn(jobType contracts.JobType) (table, statusColumn string, err error) {
+	switch jobType {
+	case contracts.JobTypeBuild:
+		return "builds", "build_status", nil
+	case contracts.JobTypeRelease:
+		return "releases", "release_status", nil
+	case contracts.JobTypeBot:
+		return "bots", "bot_status", nil
+	}
+
+	return "", "", fmt.Errorf("Job type %v is invalid", jobType)
+}
+
+func (c *client) GetActiveJobs(ctx context.Context) (activeJobs []*ActiveJob, err error) {
+
+	activeStatuses := []string{string(contracts.StatusPending), string(contracts.StatusRunning), string(contracts.StatusCanceling)}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	activeJobs = make([]*ActiveJob, 0)
+
+	for _, jt := range []struct {
+		jobType      contracts.JobType
+		table        string
+		statusColumn string
+	}{
+		{contracts.JobTypeBuild, "builds", "build_status"},
+		{contracts.JobTypeRelease, "releases", "release_status"},
+		{contracts.JobTypeBot, "bots", "bot_status"},
+	} {
+		query := psql.
+			Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.organizations").
+			From(jt.table + " a").
+			Where(sq.Eq{"a." + jt.statusColumn: activeStatuses})
+
+		rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs, err := c.scanActiveJobs(rows, jt.jobType)
+		if err != nil {
+			return nil, err
+		}
+
+		activeJobs = append(activeJobs, jobs...)
+	}
+
+	return
+}
+
+func (c *client) scanActiveJobs(rows *sql.Rows, jobType contracts.JobType) (activeJobs []*ActiveJob, err error) {
+
+	activeJobs = make([]*ActiveJob, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		activeJob := ActiveJob{
+			JobType: jobType,
+		}
+		var organizationsData []uint8
+
+		if err = rows.Scan(
+			&activeJob.JobID,
+			&activeJob.RepoSource,
+			&activeJob.RepoOwner,
+			&activeJob.RepoName,
+			&organizationsData); err != nil {
+			return
+		}
+
+		if len(organizationsData) > 0 {
+			if err = json.Unmarshal(organizationsData, &activeJob.Organizations); err != nil {
+				return
+			}
+		}
+
+		activeJobs = append(activeJobs, &activeJob)
+	}
+
+	return
+}
+
+func (c *client) scanQueuedJobs(rows *sql.Rows) (queuedJobs []*QueuedJob, err error) {
+
+	queuedJobs = make([]*QueuedJob, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		queuedJob := QueuedJob{}
+		var organizationsData []uint8
+
+		if err = rows.Scan(
+			&queuedJob.ID,
+			&queuedJob.JobType,
+			&queuedJob.JobID,
+			&queuedJob.RepoSource,
+			&queuedJob.RepoOwner,
+			&queuedJob.RepoName,
+			&organizationsData,
+			&queuedJob.CiBuilderParams,
+			&queuedJob.QueuedAt,
+			&queuedJob.Attempts); err != nil {
+			return
+		}
+
+		if len(organizationsData) > 0 {
+			if err = json.Unmarshal(organizationsData, &queuedJob.Organizations); err != nil {
+				return
+			}
+		}
+
+		queuedJobs = append(queuedJobs, &queuedJob)
+	}
+
+	return
+}
+
+func (c *client) InsertWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (wd *WebhookDelivery, err error) {
+
+	headersBytes, err := json.Marshal(delivery.Headers)
+	if err != nil {
+		return
+	}
+
+	var replayOf *string
+	if delivery.ReplayOf != "" {
+		replayOf = &delivery.ReplayOf
+	}
+
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+			webhook_deliveries
+		(
+			source,
+			event_type,
+			headers,
+			body,
+			body_hash,
+			auth_user,
+			outcome,
+			replay_of
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7,
+			$8
+		)
+		RETURNING
+			id,
+			inserted_at,
+			updated_at
+		`,
+		delivery.Source,
+		delivery.EventType,
+		headersBytes,
+		delivery.Body,
+		delivery.BodyHash,
+		delivery.AuthUser,
+		delivery.Outcome,
+		replayOf,
+	)
+
+	wd = &delivery
+
+	if err = row.Scan(&wd.ID, &wd.InsertedAt, &wd.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+func (c *client) UpdateWebhookDeliveryOutcome(ctx context.Context, id, outcome string, statusCode int, errorMessage string) (err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Update("webhook_deliveries").
+		Set("outcome", outcome).
+		Set("status_code", statusCode).
+		Set("error", errorMessage).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id})
+
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
+
+	return
+}
+
+func (c *client) GetWebhookDeliveryByID(ctx context.Context, id string) (delivery *WebhookDelivery, err error) {
+	if id == "" {
+		return nil, fmt.Errorf("GetWebhookDeliveryByID argument id is empty")
+	}
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	query := psql.
+		Select("a.id, a.source, a.event_type, a.headers, a.body, a.body_hash, a.auth_user, a.outcome, a.status_code, a.error, a.replay_of, a.inserted_at, a.updated_at").
+		From("webhook_deliveries a").
+		Where(sq.Eq{"a.id": id}).
+		Limit(uint64(1))
+
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+
+	delivery = &WebhookDelivery{}
+	var headersData []uint8
+	var statusCode *int
+	var errorMessage, replayOf *string
+
+	if err = row.Scan(
+		&delivery.ID,
+		&delivery.Source,
+		&delivery.EventType,
+		&headersData,
+		&delivery.Body,
+		&delivery.BodyHash,
+		&delivery.AuthUser,
+		&delivery.Outcome,
+		&statusCode,
+		&errorMessage,
+		&replayOf,
+		&delivery.InsertedAt,
+		&delivery.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+
+		return nil, err
+	}
+
+	if len(headersData) > 0 {
+		if err = json.Unmarshal(headersData, &delivery.Headers); err != nil {
+			return nil, err
+		}
+	}
+	if statusCode != nil {
+		delivery.StatusCode = *statusCode
+	}
+	if errorMessage != nil {
+		delivery.Error = *errorMessage
+	}
+	if replayOf != nil {
+		delivery.ReplayOf = *replayOf
+	}
+
+	return delivery, nil
+}
+
+func (c *client) GetWebhookDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (deliveries []*WebhookDelivery, err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// leave out headers and body to keep the list light
+	query := psql.
+		Select("a.id, a.source, a.event_type, a.body_hash, a.outcome, a.status_code, a.error, a.replay_of, a.inserted_at, a.updated_at").
+		From("webhook_deliveries a").
+		Limit(uint64(pageSize)).
+		Offset(uint64((pageNumber - 1) * pageSize))
+
+	// dynamically set order by clause
+	query, err = orderByClauseGeneratorForSortings(query, "a.inserted_at DESC", sortings)
+	if err != nil {
+		return
+	}
+
+	// dynamically set where clauses for filtering
+	query, err = whereClauseGeneratorForWebhookDeliveryFilters(query, filters)
+	if err != nil {
+		return
+	}
+
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
+	}
+
+	return c.scanWebhookDeliveries(rows)
+}
+
+func (c *client) GetWebhookDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (totalCount int, err error) {
+
+	query :=
+		sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+			Select("COUNT(*)").
+			From("webhook_deliveries a")
+
+	// dynamically set where clauses for filtering
+	query, err = whereClauseGeneratorForWebhookDeliveryFilters(query, filters)
+	if err != nil {
+		return
+	}
+
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&totalCount); err != nil {
+		return
+	}
+
+	return
+}
+
+func whereClauseGeneratorForWebhookDeliveryFilters(query sq.SelectBuilder, filters map[api.FilterType][]string) (sq.SelectBuilder, error) {
+
+	query, err := whereClauseGeneratorForSinceFilter(query, "inserted_at", filters)
+	if err != nil {
+		return query, err
+	}
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterStatus, "outcome")
+	if err != nil {
+		return query, err
+	}
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterSource, "source")
+	if err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+func (c *client) scanWebhookDeliveries(rows *sql.Rows) (deliveries []*WebhookDelivery, err error) {
+
+	deliveries = make([]*WebhookDelivery, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		delivery := WebhookDelivery{}
+		var statusCode *int
+		var errorMessage, replayOf *string
+
+		if err = rows.Scan(
+			&delivery.ID,
+			&delivery.Source,
+			&delivery.EventType,
+			&delivery.BodyHash,
+			&delivery.Outcome,
+			&statusCode,
+			&errorMessage,
+			&replayOf,
+			&delivery.InsertedAt,
+			&delivery.UpdatedAt); err != nil {
+			return
+		}
+
+		if statusCode != nil {
+			delivery.StatusCode = *statusCode
+		}
+		if errorMessage != nil {
+			delivery.Error = *errorMessage
+		}
+		if replayOf != nil {
+			delivery.ReplayOf = *replayOf
+		}
+
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return
+}
+
+func (c *client) QueueMigrationTask(ctx context.Context, task MigrationTask) (queuedTask *MigrationTask, err error) {
+
+	query, args := queries.QueueMigration(
+		sql.NamedArg{Name: "id", Value: task.ID},
+		sql.NamedArg{Name: "fromSource", Value: task.FromSource},
+		sql.NamedArg{Name: "fromOwner", Value: task.FromOwner},
+		sql.NamedArg{Name: "fromName", Value: task.FromName},
+		sql.NamedArg{Name: "toSource", Value: task.ToSource},
+		sql.NamedArg{Name: "toOwner", Value: task.ToOwner},
+		sql.NamedArg{Name: "toName", Value: task.ToName},
+		sql.NamedArg{Name: "callbackURL", Value: task.CallbackURL},
+		sql.NamedArg{Name: "status", Value: string(task.Status)},
+		sql.NamedArg{Name: "lastStep", Value: task.LastStep},
+	)
+
+	row := c.databaseConnection.QueryRowContext(ctx, query, args...)
+	queuedTask, err = c.scanMigrationTask(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue migration task for repository %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	// link the pipeline to the migration task
+	query, args = queries.SetMigrationIdForPipeline(
+		sql.NamedArg{Name: "id", Value: queuedTask.ID},
+		sql.NamedArg{Name: "fromSource", Value: task.FromSource},
+		sql.NamedArg{Name: "fromOwner", Value: task.FromOwner},
+		sql.NamedArg{Name: "fromName", Value: task.FromName},
+	)
+	_, err = c.databaseConnection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set migration task id for repository %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	return queuedTask, nil
+}
+
+func (c *client) PickMigrationTasks(ctx context.Context, maxTasks int) (tasks []*MigrationTask, err error) {
+
+	query, args := queries.PickMigration(sql.NamedArg{Name: "maxTasks", Value: maxTasks})
+
+	rows, err := c.databaseConnection.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick migration tasks: %w", err)
+	}
+
+	return c.scanMigrationTasks(rows)
+}
+
+func (c *client) UpdateMigrationTask(ctx context.Context, id string, status MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) (err error) {
+
+	query, args := queries.UpdateMigration(
+		sql.NamedArg{Name: "id", Value: id},
+		sql.NamedArg{Name: "lastStep", Value: lastStep},
+		sql.NamedArg{Name: "builds", Value: builds},
+		sql.NamedArg{Name: "releases", Value: releases},
+		sql.NamedArg{Name: "totalDuration", Value: int64(duration)},
+		sql.NamedArg{Name: "errorDetails", Value: errorDetails},
+		sql.NamedArg{Name: "status", Value: string(status)},
+	)
+
+	_, err = c.databaseConnection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update migration task %s: %w", id, err)
+	}
+
+	return nil
+}
+
+func (c *client) GetMigrationTaskByID(ctx context.Context, id string) (task *MigrationTask, err error) {
+	if id == "" {
+		return nil, fmt.Errorf("GetMigrationTaskByID argument id is empty")
+	}
+
+	query, args := queries.GetMigrationByID(sql.NamedArg{Name: "id", Value: id})
+
+	row := c.databaseConnection.QueryRowContext(ctx, query, args...)
+	task, err = c.scanMigrationTask(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMigrationTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to get migration task %s: %w", id, err)
+	}
+
+	return task, nil
+}
+
+func (c *client) GetMigrationTaskByFromRepo(ctx context.Context, fromSource, fromOwner, fromName string) (task *MigrationTask, err error) {
+
+	query, args := queries.GetMigrationByFromRepo(
+		sql.NamedArg{Name: "fromSource", Value: fromSource},
+		sql.NamedArg{Name: "fromOwner", Value: fromOwner},
+		sql.NamedArg{Name: "fromName", Value: fromName},
+	)
+
+	row := c.databaseConnection.QueryRowContext(ctx, query, args...)
+	task, err = c.scanMigrationTask(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMigrationTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to get migration task for repository %s/%s/%s: %w", fromSource, fromOwner, fromName, err)
+	}
+
+	return task, nil
+}
+
+func (c *client) GetMigrationTasks(ctx context.Context) (tasks []*MigrationTask, err error) {
+
+	query, args := queries.GetAllMigrations()
+
+	rows, err := c.databaseConnection.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration tasks: %w", err)
+	}
+	defer _CloseRows(rows)
+
+	tasks = make([]*MigrationTask, 0)
+	for rows.Next() {
+
+		task := MigrationTask{}
+		var lastStep, errorDetails *string
+
+		if err = rows.Scan(
+			&task.ID,
+			&task.Status,
+			&lastStep,
+			&task.FromSource,
+			&task.FromOwner,
+			&task.FromName,
+			&task.ToSource,
+			&task.ToOwner,
+			&task.ToName,
+			&errorDetails,
+			&task.QueuedAt,
+			&task.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration tasks: %w", err)
+		}
+
+		if lastStep != nil {
+			task.LastStep = *lastStep
+		}
+		if errorDetails != nil {
+			task.ErrorDetails = *errorDetails
+		}
+
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, nil
+}
+
+func (c *client) MigrateReleases(ctx context.Context, task MigrationTask) (count int, err error) {
+
+	query, args := queries.MigrateReleases(migrationRepoArgs(task)...)
+
+	result, err := c.databaseConnection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to migrate releases from %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (c *client) MigrateReleaseLogs(ctx context.Context, task MigrationTask) (err error) {
+
+	query, args := queries.MigrateReleaseLogs(migrationRepoArgs(task)...)
+
+	_, err = c.databaseConnection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to migrate release logs from %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	return nil
+}
+
+func (c *client) GetMigratedReleaseLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+
+	query, args := queries.GetMigratedReleaseLogs(migrationRepoArgs(task)...)
+
+	rows, err := c.databaseConnection.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migrated release logs for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+
+	return c.scanMigratedIDs(rows)
+}
+
+func (c *client) MigrateBuilds(ctx context.Context, task MigrationTask) (count int, err error) {
+
+	query, args := queries.MigrateBuilds(migrationRepoArgs(task)...)
+
+	// migrate builds in batches, since a pipeline can have a lot of them
+	for offset := 0; ; offset += migrateBuildsBatchSize {
+		result, err := c.databaseConnection.ExecContext(ctx, fmt.Sprintf("%s\nORDER BY id\nLIMIT %d OFFSET %d", query, migrateBuildsBatchSize, offset), args...)
+		if err != nil {
+			return count, fmt.Errorf("failed to migrate builds from %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return count, err
+		}
+
+		count += int(rowsAffected)
+		if rowsAffected < migrateBuildsBatchSize {
+			break
+		}
+	}
+
+	return count, nil
+}
+
+func (c *client) MigrateBuildLogs(ctx context.Context, task MigrationTask) (err error) {
+
+	query, args := queries.MigrateBuildLogs(migrationRepoArgs(task)...)
+
+	_, err = c.databaseConnection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to migrate build logs from %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	return nil
+}
+
+func (c *client) GetMigratedBuildLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+
+	query, args := queries.GetMigratedBuildLogs(migrationRepoArgs(task)...)
+
+	rows, err := c.databaseConnection.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migrated build logs for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+
+	return c.scanMigratedIDs(rows)
+}
+
+func (c *client) MigrateBuildVersions(ctx context.Context, task MigrationTask, shortFromRepoSource, shortToRepoSource string) (err error) {
+
+	query, args := queries.MigrateBuildVersions(
+		sql.NamedArg{Name: "fromSourceName", Value: shortFromRepoSource},
+		sql.NamedArg{Name: "fromFullName", Value: fmt.Sprintf("%v/%v", task.FromOwner, task.FromName)},
+		sql.NamedArg{Name: "toSourceName", Value: shortToRepoSource},
+		sql.NamedArg{Name: "toFullName", Value: fmt.Sprintf("%v/%v", task.ToOwner, task.ToName)},
+	)
+
+	_, err = c.databaseConnection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to migrate build versions from %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	return nil
+}
+
+func (c *client) MigrateComputedTables(ctx context.Context, task MigrationTask) (err error) {
+
+	// the migrated pipeline stays archived until the migration task completes
+	query, args := queries.MigrateComputedPipeline(migrationRepoArgs(task)...)
+
+	_, err = c.databaseConnection.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to migrate computed pipeline from %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	releases, err := c.GetUniquePipelineReleases(ctx, task.ToSource, task.ToOwner, task.ToName, migrateComputedReleasesLimit)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range releases {
+		err = c.UpsertComputedRelease(ctx, task.ToSource, task.ToOwner, task.ToName, r.Name, r.Action)
+		if err != nil {
+			return fmt.Errorf("failed to upsert computed release %s for %s/%s/%s: %w", r.Name, task.ToSource, task.ToOwner, task.ToName, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *client) RollbackMigrationStep(ctx context.Context, task MigrationTask, step MigrationStep, shortToRepoSource string) (err error) {
+
+	args := migrationRepoArgs(task)
+
+	switch step {
+	case MigrationStepReleases:
+		_, err = c.rollbackMigrationQuery(ctx, queries.RollbackReleases, args...)
+	case MigrationStepReleaseLogs:
+		_, err = c.rollbackMigrationQuery(ctx, queries.RollbackReleaseLogs, args...)
+	case MigrationStepBuilds:
+		_, err = c.rollbackMigrationQuery(ctx, queries.RollbackBuilds, args...)
+	case MigrationStepBuildLogs:
+		_, err = c.rollbackMigrationQuery(ctx, queries.RollbackBuildLogs, args...)
+	case MigrationStepBuildVersions:
+		_, err = c.rollbackMigrationQuery(ctx, queries.RollbackBuildVersions, migrationBuildVersionArgs(task, shortToRepoSource)...)
+	case MigrationStepComputedTables:
+		_, err = c.rollbackMigrationQuery(ctx, queries.RollbackComputedReleases, args...)
+		if err == nil {
+			_, err = c.rollbackMigrationQuery(ctx, queries.RollbackComputedPipelines, args...)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration step %s for %s/%s/%s: %w", step, task.ToSource, task.ToOwner, task.ToName, err)
+	}
+
+	return nil
+}
+
+func (c *client) RollbackMigration(ctx context.Context, task MigrationTask, shortToRepoSource string) (rollback *MigrationRollback, err error) {
+
+	args := migrationRepoArgs(task)
+	rollback = &MigrationRollback{}
+
+	// remove logs before the builds and releases they belong to
+	if rollback.ReleaseLogs, err = c.rollbackMigrationQuery(ctx, queries.RollbackReleaseLogs, args...); err != nil {
+		return nil, fmt.Errorf("failed to roll back release logs for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+	if rollback.Releases, err = c.rollbackMigrationQuery(ctx, queries.RollbackReleases, args...); err != nil {
+		return nil, fmt.Errorf("failed to roll back releases for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+	if rollback.BuildLogs, err = c.rollbackMigrationQuery(ctx, queries.RollbackBuildLogs, args...); err != nil {
+		return nil, fmt.Errorf("failed to roll back build logs for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+	if rollback.Builds, err = c.rollbackMigrationQuery(ctx, queries.RollbackBuilds, args...); err != nil {
+		return nil, fmt.Errorf("failed to roll back builds for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+	if rollback.BuildVersions, err = c.rollbackMigrationQuery(ctx, queries.RollbackBuildVersions, migrationBuildVersionArgs(task, shortToRepoSource)...); err != nil {
+		return nil, fmt.Errorf("failed to roll back build versions for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+	if _, err = c.rollbackMigrationQuery(ctx, queries.RollbackComputedReleases, args...); err != nil {
+		return nil, fmt.Errorf("failed to roll back computed releases for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+	if _, err = c.rollbackMigrationQuery(ctx, queries.RollbackComputedPipelines, args...); err != nil {
+		return nil, fmt.Errorf("failed to roll back computed pipeline for %s/%s/%s: %w", task.ToSource, task.ToOwner, task.ToName, err)
+	}
+	if _, err = c.rollbackMigrationQuery(ctx, queries.RollbackMigrationTaskQueue, args...); err != nil {
+		return nil, fmt.Errorf("failed to remove migration task for %s/%s/%s: %w", task.FromSource, task.FromOwner, task.FromName, err)
+	}
+
+	return rollback, nil
+}
+
+// rollbackMigrationQuery executes one of the rollback queries, which return the number of deleted rows
+func (c *client) rollbackMigrationQuery(ctx context.Context, queryFunc func(...sql.NamedArg) (string, []interface{}), namedArgs ...sql.NamedArg) (count int, err error) {
+
+	query, args := queryFunc(namedArgs...)
+
+	row := c.databaseConnection.QueryRowContext(ctx, query, args...)
+	if err = row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// migrationRepoArgs returns the named arguments for the source and target repository of a migration task; queries only use the ones they need
+func migrationRepoArgs(task MigrationTask) []sql.NamedArg {
+	return []sql.NamedArg{
+		{Name: "fromSource", Value: task.FromSource},
+		{Name: "fromOwner", Value: task.FromOwner},
+		{Name: "fromName", Value: task.FromName},
+		{Name: "toSource", Value: task.ToSource},
+		{Name: "toOwner", Value: task.ToOwner},
+		{Name: "toName", Value: task.ToName},
+	}
+}
+
+func migrationBuildVersionArgs(task MigrationTask, shortToRepoSource string) []sql.NamedArg {
+	return []sql.NamedArg{
+		{Name: "toSource", Value: shortToRepoSource},
+		{Name: "toFullName", Value: fmt.Sprintf("%v/%v", task.ToOwner, task.ToName)},
+	}
+}
+
+func (c *client) scanMigrationTask(row sq.RowScanner) (task *MigrationTask, err error) {
+
+	task = &MigrationTask{}
+	var lastStep, callbackURL, errorDetails *string
+	var builds, releases, totalDuration *int64
+
+	if err = row.Scan(
+		&task.ID,
+		&task.Status,
+		&lastStep,
+		&builds,
+		&releases,
+		&totalDuration,
+		&task.FromSource,
+		&task.FromOwner,
+		&task.FromName,
+		&task.ToSource,
+		&task.ToOwner,
+		&task.ToName,
+		&callbackURL,
+		&errorDetails,
+		&task.QueuedAt,
+		&task.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if lastStep != nil {
+		task.LastStep = *lastStep
+	}
+	if builds != nil {
+		task.Builds = int(*builds)
+	}
+	if releases != nil {
+		task.Releases = int(*releases)
+	}
+	if totalDuration != nil {
+		task.TotalDuration = time.Duration(*totalDuration)
+	}
+	if callbackURL != nil {
+		task.CallbackURL = *callbackURL
+	}
+	if errorDetails != nil {
+		task.ErrorDetails = *errorDetails
+	}
+
+	return task, nil
+}
+
+func (c *client) scanMigrationTasks(rows *sql.Rows) (tasks []*MigrationTask, err error) {
+
+	tasks = make([]*MigrationTask, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		task, err := c.scanMigrationTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan migration tasks: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// scanMigratedIDs returns the original ids of migrated records mapped to their new id
+func (c *client) scanMigratedIDs(rows *sql.Rows) (ids map[string]string, err error) {
+
+	ids = map[string]string{}
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		var fromID, toID string
+		if err = rows.Scan(&fromID, &toID); err != nil {
+			return nil, err
+		}
+		ids[fromID] = toID
+	}
+
+	return ids, nil
+}
+
+func (c *client) GetLatestSuccessfulReleaseLogIDs(ctx context.Context, repoSource, repoOwner, repoName string) (logIDs []string, err error) {
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// the logs of all attempts of the most recent succeeded release per release target
+	query := psql.
+		Select("a.id").
+		From("release_logs a").
+		Where(sq.Eq{"a.repo_source": repoSource}).
+		Where(sq.Eq{"a.repo_owner": repoOwner}).
+		Where(sq.Eq{"a.repo_name": repoName}).
+		Where(sq.Expr("a.release_id IN (SELECT DISTINCT ON (r.release) r.id FROM releases r WHERE r.repo_source = ? AND r.repo_owner = ? AND r.repo_name = ? AND r.release_status = ? ORDER BY r.release, r.inserted_at DESC)", repoSource, repoOwner, repoName, contracts.StatusSucceeded))
+
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest successful release logs for %s/%s/%s: %w", repoSource, repoOwner, repoName, err)
+	}
+
+	return c.scanLogIDs(rows)
+}
+
+func (c *client) GetExpiredLogIDs(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string, limit int) (logIDs []string, err error) {
+
+	query, err := c.selectExpiredLogsQuery(jobType, "a.id", repoSource, repoOwner, repoName, insertedBefore, keepLogIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	query = query.
+		OrderBy("a.inserted_at").
+		Limit(uint64(limit))
+
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired %v logs for %s/%s/%s: %w", jobType, repoSource, repoOwner, repoName, err)
+	}
+
+	return c.scanLogIDs(rows)
+}
+
+func (c *client) GetExpiredLogsCount(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string) (count int, err error) {
+
+	query, err := c.selectExpiredLogsQuery(jobType, "COUNT(*)", repoSource, repoOwner, repoName, insertedBefore, keepLogIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count expired %v logs for %s/%s/%s: %w", jobType, repoSource, repoOwner, repoName, err)
+	}
+
+	return count, nil
+}
+
+func (c *client) DeleteLogsByID(ctx context.Context, jobType contracts.JobType, logIDs []string) (count int, err error) {
+	if len(logIDs) == 0 {
+		return 0, nil
+	}
+
+	tableName, err := getLogsTableName(jobType)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Delete(tableName).
+		Where(sq.Eq{"id": logIDs}).
+		RunWith(c.databaseConnection).
+		ExecContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete %v %v logs: %w", len(logIDs), jobType, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (c *client) selectExpiredLogsQuery(jobType contracts.JobType, columns, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string) (query sq.SelectBuilder, err error) {
+
+	tableName, err := getLogsTableName(jobType)
+	if err != nil {
+		return query, err
+	}
+
+	query = sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(columns).
+		From(tableName + " a").
+		Where(sq.Eq{"a.repo_source": repoSource}).
+		Where(sq.Eq{"a.repo_owner": repoOwner}).
+		Where(sq.Eq{"a.repo_name": repoName}).
+		Where(sq.Lt{"a.inserted_at": insertedBefore})
+
+	if len(keepLogIDs) > 0 {
+		query = query.Where(sq.NotEq{"a.id": keepLogIDs})
+	}
+
+	return query, nil
+}
+
+func getLogsTableName(jobType contracts.JobType) (tableName string, err error) {
+	switch jobType {
+	case contracts.JobTypeBuild:
+		return "build_logs", nil
+	case contracts.JobTypeRelease:
+		return "release_logs", nil
+	case contracts.JobTypeBot:
+		return "bot_logs", nil
+	}
+
+	return "", fmt.Errorf("job type %v has no logs table", jobType)
+}
+
+func (c *client) scanLogIDs(rows *sql.Rows) (logIDs []string, err error) {
+
+	logIDs = []string{}
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		logIDs = append(logIDs, id)
+	}
+
+	return logIDs, nil
+}
+
+func (c *client) QueueLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (queuedReconciliation *LogReconciliation, err error) {
+
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+			log_reconciliations
+		(
+			status,
+			dry_run,
+			repo_source,
+			repo_owner,
+			repo_name,
+			queued_by
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6
+		)
+		RETURNING
+			`+logReconciliationColumns,
+		string(LogReconciliationStatusQueued),
+		reconciliation.DryRun,
+		reconciliation.RepoSource,
+		reconciliation.RepoOwner,
+		reconciliation.RepoName,
+		reconciliation.QueuedBy,
+	)
+
+	queuedReconciliation, err = c.scanLogReconciliation(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue log reconciliation: %w", err)
+	}
+
+	return queuedReconciliation, nil
+}
+
+func (c *client) PickLogReconciliation(ctx context.Context, staleBefore time.Time) (reconciliation *LogReconciliation, err error) {
+
+	// a reconciliation left in progress by an api instance that stopped is picked up again once it hasn't been updated for a while
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		UPDATE
+			log_reconciliations
+		SET
+			status = $1,
+			pipelines = 0,
+			checked = 0,
+			copied = 0,
+			unrecoverable = 0,
+			orphaned = 0,
+			error_details = '',
+			started_at = now(),
+			updated_at = now()
+		WHERE
+			id = (
+				SELECT
+					id
+				FROM
+					log_reconciliations
+				WHERE
+					status = $2 OR (status = $1 AND updated_at < $3)
+				ORDER BY
+					queued_at
+				LIMIT 1
+			)
+		RETURNING
+			`+logReconciliationColumns,
+		string(LogReconciliationStatusInProgress),
+		string(LogReconciliationStatusQueued),
+		staleBefore,
+	)
+
+	reconciliation, err = c.scanLogReconciliation(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to pick log reconciliation: %w", err)
+	}
+
+	return reconciliation, nil
+}
+
+func (c *client) UpdateLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (err error) {
+
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Update("log_reconciliations").
+		Set("status", string(reconciliation.Status)).
+		Set("pipelines", reconciliation.Pipelines).
+		Set("checked", reconciliation.Checked).
+		Set("copied", reconciliation.Copied).
+		Set("unrecoverable", reconciliation.Unrecoverable).
+		Set("orphaned", reconciliation.Orphaned).
+		Set("error_details", reconciliation.ErrorDetails).
+		Set("completed_at", reconciliation.CompletedAt).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": reconciliation.ID})
+
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update log reconciliation %s: %w", reconciliation.ID, err)
+	}
+
+	return nil
+}
+
+func (c *client) GetLogReconciliationByID(ctx context.Context, id string) (reconciliation *LogReconciliation, err error) {
+	if id == "" {
+		return nil, fmt.Errorf("GetLogReconciliationByID argument id is empty")
+	}
+
+	query := c.selectLogReconciliationsQuery().
+		Where(sq.Eq{"a.id": id}).
+		Limit(uint64(1))
+
+	reconciliation, err = c.scanLogReconciliation(query.RunWith(c.databaseConnection).QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrLogReconciliationNotFound
+		}
+		return nil, fmt.Errorf("failed to get log reconciliation %s: %w", id, err)
+	}
+
+	return reconciliation, nil
+}
+
+func (c *client) GetActiveLogReconciliation(ctx context.Context) (reconciliation *LogReconciliation, err error) {
+
+	query := c.selectLogReconciliationsQuery().
+		Where(sq.Eq{"a.status": []string{string(LogReconciliationStatusQueued), string(LogReconciliationStatusInProgress)}}).
+		OrderBy("a.queued_at").
+		Limit(uint64(1))
+
+	reconciliation, err = c.scanLogReconciliation(query.RunWith(c.databaseConnection).QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrLogReconciliationNotFound
 		}
+		return nil, fmt.Errorf("failed to get active log reconciliation: %w", err)
 	}
 
-	return
+	return reconciliation, nil
 }
 
-func (c *client) selectBuildsQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+func (c *client) GetLogReconciliations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (reconciliations []*LogReconciliation, err error) {
 
-	return psql.
-		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.repo_branch, a.repo_revision, a.build_version, a.build_status, a.labels, a.release_targets, a.manifest, a.commits, a.triggers, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.groups, a.organizations").
-		From("builds a")
-}
+	query := c.selectLogReconciliationsQuery().
+		OrderBy("a.queued_at DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((pageNumber - 1) * pageSize))
 
-func (c *client) selectPipelinesQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterStatus, "status")
+	if err != nil {
+		return
+	}
 
-	return psql.
-		Select("a.pipeline_id, a.repo_source, a.repo_owner, a.repo_name, a.repo_branch, a.repo_revision, a.build_version, a.build_status, a.labels, a.release_targets, a.manifest, a.commits, a.triggers, a.archived, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.last_updated_at, a.triggered_by_event, a.extra_info, a.groups, a.organizations").
-		From("computed_pipelines a")
-}
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log reconciliations: %w", err)
+	}
+	defer _CloseRows(rows)
 
-func (c *client) selectReleasesQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	reconciliations = make([]*LogReconciliation, 0)
+	for rows.Next() {
+		reconciliation, err := c.scanLogReconciliation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan log reconciliations: %w", err)
+		}
+		reconciliations = append(reconciliations, reconciliation)
+	}
 
-	return psql.
-		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.release, a.release_action, a.release_version, a.release_status, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.groups, a.organizations").
-		From("releases a")
+	return reconciliations, nil
 }
 
-func (c *client) selectBotsQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+func (c *client) GetLogReconciliationsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
 
-	return psql.
-		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.bot, a.bot_status, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.groups, a.organizations").
-		From("bots a")
-}
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("COUNT(*)").
+		From("log_reconciliations a")
 
-func (c *client) selectNotificationsQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterStatus, "status")
+	if err != nil {
+		return
+	}
 
-	return psql.
-		Select("a.id, a.link_type, a.link_id, a.link_detail, a.source, a.notifications, a.inserted_at, a.groups, a.organizations").
-		From("notifications a")
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count log reconciliations: %w", err)
+	}
+
+	return count, nil
 }
 
-func (c *client) selectComputedReleasesQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+func (c *client) GetLogRecords(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string) (records []*LogRecord, err error) {
 
-	return psql.
-		Select("a.release_id, a.repo_source, a.repo_owner, a.repo_name, a.release, a.release_action, a.release_version, a.release_status, a.inserted_at, a.started_at, a.updated_at, EXTRACT(epoch FROM age(COALESCE(a.started_at, a.inserted_at), a.inserted_at)), EXTRACT(epoch FROM age(a.updated_at, COALESCE(a.started_at,a.inserted_at))), a.triggered_by_event, a.extra_info, a.groups, a.organizations").
-		From("computed_releases a")
-}
+	tableName, err := getLogsTableName(jobType)
+	if err != nil {
+		return nil, err
+	}
 
-func (c *client) selectBuildLogsQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	return psql.
-		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.repo_branch, a.repo_revision, a.build_id, a.inserted_at").
-		From("build_logs a")
-}
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(fmt.Sprintf("a.id, a.%v_id, a.inserted_at", jobType)).
+		From(tableName + " a").
+		Where(sq.Eq{"a.repo_source": repoSource}).
+		Where(sq.Eq{"a.repo_owner": repoOwner}).
+		Where(sq.Eq{"a.repo_name": repoName}).
+		OrderBy("a.inserted_at DESC")
 
-func (c *client) selectReleaseLogsQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	return psql.
-		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.release_id, a.inserted_at").
-		From("release_logs a")
-}
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %v log records for %s/%s/%s: %w", jobType, repoSource, repoOwner, repoName, err)
+	}
+	defer _CloseRows(rows)
 
-func (c *client) selectBotLogsQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	return psql.
-		Select("a.id, a.repo_source, a.repo_owner, a.repo_name, a.bot_id, a.inserted_at").
-		From("bot_logs a")
+	records = make([]*LogRecord, 0)
+	for rows.Next() {
+		record := LogRecord{}
+		var jobID sql.NullString
+
+		if err = rows.Scan(&record.ID, &jobID, &record.InsertedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan %v log records for %s/%s/%s: %w", jobType, repoSource, repoOwner, repoName, err)
+		}
+
+		// logs stored before build ids got recorded only belong to themselves
+		record.JobID = jobID.String
+
+		records = append(records, &record)
+	}
+
+	return records, nil
 }
 
-func (c *client) selectCatalogEntityQuery() sq.SelectBuilder {
-	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+const logReconciliationColumns = "id, status, dry_run, repo_source, repo_owner, repo_name, pipelines, checked, copied, unrecoverable, orphaned, error_details, queued_by, queued_at, started_at, completed_at, updated_at"
 
-	return psql.
-		Select("a.id, a.parent_key, a.parent_value, a.entity_key, a.entity_value, a.linked_pipeline, a.labels, a.entity_metadata, a.inserted_at, a.updated_at").
-		From("catalog_entities a")
+func (c *client) selectLogReconciliationsQuery() sq.SelectBuilder {
+	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("a." + strings.ReplaceAll(logReconciliationColumns, ", ", ", a.")).
+		From("log_reconciliations a")
 }
 
-func (c *client) enrichPipeline(ctx context.Context, pipeline *contracts.Pipeline) {
-	c.getLatestReleasesForPipeline(ctx, pipeline)
+func (c *client) scanLogReconciliation(row sq.RowScanner) (reconciliation *LogReconciliation, err error) {
+
+	reconciliation = &LogReconciliation{}
+	var repoSource, repoOwner, repoName, errorDetails, queuedBy *string
+
+	if err = row.Scan(
+		&reconciliation.ID,
+		&reconciliation.Status,
+		&reconciliation.DryRun,
+		&repoSource,
+		&repoOwner,
+		&repoName,
+		&reconciliation.Pipelines,
+		&reconciliation.Checked,
+		&reconciliation.Copied,
+		&reconciliation.Unrecoverable,
+		&reconciliation.Orphaned,
+		&errorDetails,
+		&queuedBy,
+		&reconciliation.QueuedAt,
+		&reconciliation.StartedAt,
+		&reconciliation.CompletedAt,
+		&reconciliation.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if repoSource != nil {
+		reconciliation.RepoSource = *repoSource
+	}
+	if repoOwner != nil {
+		reconciliation.RepoOwner = *repoOwner
+	}
+	if repoName != nil {
+		reconciliation.RepoName = *repoName
+	}
+	if errorDetails != nil {
+		reconciliation.ErrorDetails = *errorDetails
+	}
+	if queuedBy != nil {
+		reconciliation.QueuedBy = *queuedBy
+	}
+
+	return reconciliation, nil
 }
 
-func (c *client) enrichBuild(ctx context.Context, build *contracts.Build) {
-	c.getLatestReleasesForBuild(ctx, build)
+func (c *client) InsertNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (insertedDelivery *NotificationDelivery, err error) {
+
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+			notification_deliveries
+		(
+			channel,
+			channel_type,
+			event_type,
+			repo_source,
+			repo_owner,
+			repo_name,
+			source_id,
+			payload,
+			status,
+			next_attempt_at
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7,
+			$8,
+			$9,
+			now()
+		)
+		RETURNING
+			`+notificationDeliveryColumns,
+		delivery.Channel,
+		delivery.ChannelType,
+		delivery.EventType,
+		delivery.RepoSource,
+		delivery.RepoOwner,
+		delivery.RepoName,
+		delivery.SourceID,
+		[]byte(delivery.Payload),
+		string(NotificationDeliveryStatusPending),
+	)
+
+	insertedDelivery, err = c.scanNotificationDelivery(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert notification delivery for channel %s: %w", delivery.Channel, err)
+	}
+
+	return insertedDelivery, nil
 }
 
-func (c *client) setPipelinePropertiesFromJSONB(pipeline *contracts.Pipeline, labelsData, releaseTargetsData, commitsData, triggersData, triggeredByEventsData, extraInfoData, groupsData, organizationsData []uint8, optimized bool) (err error) {
+func (c *client) PickNotificationDeliveries(ctx context.Context, limit int, staleBefore time.Time) (deliveries []*NotificationDelivery, err error) {
 
-	if len(labelsData) > 0 {
-		if err = json.Unmarshal(labelsData, &pipeline.Labels); err != nil {
-			return
-		}
+	// deliveries left in progress by an api instance that stopped are picked up again once they haven't been updated for a while;
+	// locked rows are skipped so api instances never pick the same delivery
+	rows, err := c.databaseConnection.QueryContext(ctx,
+		`
+		UPDATE
+			notification_deliveries
+		SET
+			status = $1,
+			updated_at = now()
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					notification_deliveries
+				WHERE
+					(status = $2 AND next_attempt_at <= now()) OR (status = $1 AND updated_at < $3)
+				ORDER BY
+					next_attempt_at
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			`+notificationDeliveryColumns,
+		string(NotificationDeliveryStatusInProgress),
+		string(NotificationDeliveryStatusPending),
+		staleBefore,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pick notification deliveries: %w", err)
 	}
-	if len(releaseTargetsData) > 0 {
-		if err = json.Unmarshal(releaseTargetsData, &pipeline.ReleaseTargets); err != nil {
-			return
+	defer _CloseRows(rows)
+
+	deliveries = make([]*NotificationDelivery, 0)
+	for rows.Next() {
+		delivery, err := c.scanNotificationDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan picked notification deliveries: %w", err)
 		}
+		deliveries = append(deliveries, delivery)
 	}
-	if len(commitsData) > 0 {
-		if err = json.Unmarshal(commitsData, &pipeline.Commits); err != nil {
-			return
-		}
 
-		// remove all but the first 6 commits
-		if len(pipeline.Commits) > 6 {
-			pipeline.Commits = pipeline.Commits[:6]
-		}
+	return deliveries, nil
+}
+
+func (c *client) UpdateNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (err error) {
+
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Update("notification_deliveries").
+		Set("status", string(delivery.Status)).
+		Set("attempts", delivery.Attempts).
+		Set("error_details", delivery.ErrorDetails).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("delivered_at", delivery.DeliveredAt).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": delivery.ID})
+
+	_, err = query.RunWith(c.databaseConnection).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update notification delivery %s: %w", delivery.ID, err)
 	}
-	if len(triggersData) > 0 {
-		if err = json.Unmarshal(triggersData, &pipeline.Triggers); err != nil {
-			return
-		}
+
+	return nil
+}
+
+func (c *client) GetNotificationDeliveryByID(ctx context.Context, id string) (delivery *NotificationDelivery, err error) {
+	if id == "" {
+		return nil, fmt.Errorf("GetNotificationDeliveryByID argument id is empty")
 	}
-	if len(triggeredByEventsData) > 0 {
-		if err = json.Unmarshal(triggeredByEventsData, &pipeline.Events); err != nil {
-			return
+
+	query := c.selectNotificationDeliveriesQuery().
+		Where(sq.Eq{"a.id": id}).
+		Limit(uint64(1))
+
+	delivery, err = c.scanNotificationDelivery(query.RunWith(c.databaseConnection).QueryRowContext(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotificationDeliveryNotFound
 		}
+		return nil, fmt.Errorf("failed to get notification delivery %s: %w", id, err)
 	}
-	if len(extraInfoData) > 0 {
-		if err = json.Unmarshal(extraInfoData, &pipeline.ExtraInfo); err != nil {
-			return
-		}
+
+	return delivery, nil
+}
+
+func (c *client) GetNotificationDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (deliveries []*NotificationDelivery, err error) {
+
+	query := c.selectNotificationDeliveriesQuery().
+		OrderBy("a.inserted_at DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((pageNumber - 1) * pageSize))
+
+	query, err = whereClauseGeneratorForNotificationDeliveryFilters(query, filters)
+	if err != nil {
+		return
 	}
-	if len(groupsData) > 0 {
-		if err = json.Unmarshal(groupsData, &pipeline.Groups); err != nil {
-			return
-		}
+
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification deliveries: %w", err)
 	}
-	if len(organizationsData) > 0 {
-		if err = json.Unmarshal(organizationsData, &pipeline.Organizations); err != nil {
-			return
+	defer _CloseRows(rows)
+
+	deliveries = make([]*NotificationDelivery, 0)
+	for rows.Next() {
+		delivery, err := c.scanNotificationDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification deliveries: %w", err)
 		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (c *client) GetNotificationDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+
+	query := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("COUNT(*)").
+		From("notification_deliveries a")
+
+	query, err = whereClauseGeneratorForNotificationDeliveryFilters(query, filters)
+	if err != nil {
+		return
+	}
+
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count notification deliveries: %w", err)
+	}
+
+	return count, nil
+}
+
+func whereClauseGeneratorForNotificationDeliveryFilters(query sq.SelectBuilder, filters map[api.FilterType][]string) (sq.SelectBuilder, error) {
+
+	query, err := whereClauseGeneratorForGenericFilter(query, filters, api.FilterStatus, "status")
+	if err != nil {
+		return query, err
 	}
 
-	if !optimized {
-		// unmarshal then marshal manifest to include defaults
-		_manifest, err := manifest.ReadManifest(c.config.ManifestPreferences, pipeline.Manifest, false)
-		if err == nil {
-			pipeline.ManifestObject = &_manifest
-			manifestWithDefaultBytes, err := yaml.Marshal(_manifest)
-			if err == nil {
-				pipeline.ManifestWithDefaults = string(manifestWithDefaultBytes)
-			} else {
-				log.Warn().Err(err).Interface("manifest", _manifest).Msgf("Marshalling manifest for %v/%v/%v revision %v failed", pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, pipeline.RepoRevision)
-			}
-		} else {
-			log.Debug().Err(err).Str("manifest", pipeline.Manifest).Msgf("Unmarshalling manifest for %v/%v/%v revision %v failed", pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, pipeline.RepoRevision)
+	if pipelines, ok := filters[api.FilterPipeline]; ok && len(pipelines) == 1 {
+		pipelineParts := strings.Split(pipelines[0], "/")
+		if len(pipelineParts) != 3 {
+			return query, fmt.Errorf("Pipeline filter '%v' has invalid name", pipelines[0])
 		}
+		query = query.Where(sq.Eq{"a.repo_source": pipelineParts[0], "a.repo_owner": pipelineParts[1], "a.repo_name": pipelineParts[2]})
 	}
 
-	return
+	return query, nil
 }
 
-func (c *client) setBuildPropertiesFromJSONB(build *contracts.Build, labelsData, releaseTargetsData, commitsData, triggersData, triggeredByEventsData, groupsData, organizationsData []uint8, optimized bool) (err error) {
+const notificationDeliveryColumns = "id, channel, channel_type, event_type, repo_source, repo_owner, repo_name, source_id, payload, status, attempts, error_details, next_attempt_at, delivered_at, inserted_at, updated_at"
 
-	if len(labelsData) > 0 {
-		if err = json.Unmarshal(labelsData, &build.Labels); err != nil {
-			return
-		}
-	}
-	if len(releaseTargetsData) > 0 {
-		if err = json.Unmarshal(releaseTargetsData, &build.ReleaseTargets); err != nil {
-			return
-		}
+func (c *client) selectNotificationDeliveriesQuery() sq.SelectBuilder {
+	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select("a." + strings.ReplaceAll(notificationDeliveryColumns, ", ", ", a.")).
+		From("notification_deliveries a")
+}
+
+func (c *client) scanNotificationDelivery(row sq.RowScanner) (delivery *NotificationDelivery, err error) {
+
+	delivery = &NotificationDelivery{}
+	var repoSource, repoOwner, repoName, sourceID, errorDetails *string
+	var payload []byte
+
+	if err = row.Scan(
+		&delivery.ID,
+		&delivery.Channel,
+		&delivery.ChannelType,
+		&delivery.EventType,
+		&repoSource,
+		&repoOwner,
+		&repoName,
+		&sourceID,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&errorDetails,
+		&delivery.NextAttemptAt,
+		&delivery.DeliveredAt,
+		&delivery.InsertedAt,
+		&delivery.UpdatedAt); err != nil {
+		return nil, err
 	}
-	if len(commitsData) > 0 {
-		if err = json.Unmarshal(commitsData, &build.Commits); err != nil {
-			return
-		}
 
-		// remove all but the first 6 commits
-		if len(build.Commits) > 6 {
-			build.Commits = build.Commits[:6]
-		}
+	delivery.Payload = json.RawMessage(payload)
+	if repoSource != nil {
+		delivery.RepoSource = *repoSource
 	}
-	if len(triggersData) > 0 {
-		if err = json.Unmarshal(triggersData, &build.Triggers); err != nil {
-			return
-		}
+	if repoOwner != nil {
+		delivery.RepoOwner = *repoOwner
 	}
-	if len(triggeredByEventsData) > 0 {
-		if err = json.Unmarshal(triggeredByEventsData, &build.Events); err != nil {
-			return
-		}
+	if repoName != nil {
+		delivery.RepoName = *repoName
 	}
-	if len(groupsData) > 0 {
-		if err = json.Unmarshal(groupsData, &build.Groups); err != nil {
-			return
-		}
+	if sourceID != nil {
+		delivery.SourceID = *sourceID
 	}
-	if len(organizationsData) > 0 {
-		if err = json.Unmarshal(organizationsData, &build.Organizations); err != nil {
-			return
-		}
+	if errorDetails != nil {
+		delivery.ErrorDetails = *errorDetails
 	}
 
-	if !optimized {
-		// unmarshal then marshal manifest to include defaults
-		_manifest, err := manifest.ReadManifest(c.config.ManifestPreferences, build.Manifest, false)
-		if err == nil {
-			build.ManifestObject = &_manifest
-			manifestWithDefaultBytes, err := yaml.Marshal(_manifest)
-			if err == nil {
-				build.ManifestWithDefaults = string(manifestWithDefaultBytes)
-			} else {
-				log.Warn().Err(err).Interface("manifest", _manifest).Msgf("Marshalling manifest for %v/%v/%v revision %v failed", build.RepoSource, build.RepoOwner, build.RepoName, build.RepoRevision)
-			}
-		} else {
-			log.Debug().Err(err).Str("manifest", build.Manifest).Msgf("Unmarshalling manifest for %v/%v/%v revision %v failed", build.RepoSource, build.RepoOwner, build.RepoName, build.RepoRevision)
-		}
-	}
+	return delivery, nil
+}
 
-	return
+func _CloseRows(rows *sql.Rows) {
+	err := rows.Close()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to close rows")
+	}
 }
 
-func (c *client) setReleasePropertiesFromJSONB(release *contracts.Release, triggeredByEventsData, groupsData, organizationsData []uint8) (err error) {
+func (c *client) InsertAuditLogRecord(ctx context.Context, record AuditLogRecord) (insertedRecord *AuditLogRecord, err error) {
 
-	if len(triggeredByEventsData) > 0 {
-		if err = json.Unmarshal(triggeredByEventsData, &release.Events); err != nil {
-			return
-		}
-	}
-	if len(groupsData) > 0 {
-		if err = json.Unmarshal(groupsData, &release.Groups); err != nil {
-			return
-		}
+	beforeBytes, err := marshalAuditLogState(record.Before)
+	if err != nil {
+		return
 	}
-	if len(organizationsData) > 0 {
-		if err = json.Unmarshal(organizationsData, &release.Organizations); err != nil {
-			return
-		}
+	afterBytes, err := marshalAuditLogState(record.After)
+	if err != nil {
+		return
 	}
 
-	return
-}
+	row := c.databaseConnection.QueryRowContext(ctx,
+		`
+		INSERT INTO
+			audit_log
+		(
+			actor,
+			client_id,
+			impersonator,
+			action,
+			target_type,
+			target_id,
+			before,
+			after,
+			request_id
+		)
+		VALUES
+		(
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7,
+			$8,
+			$9
+		)
+		RETURNING
+			id,
+			inserted_at
+		`,
+		record.Actor,
+		record.ClientID,
+		record.Impersonator,
+		record.Action,
+		record.TargetType,
+		record.TargetID,
+		beforeBytes,
+		afterBytes,
+		record.RequestID,
+	)
 
-func (c *client) setBotPropertiesFromJSONB(bot *contracts.Bot, triggeredByEventsData, groupsData, organizationsData []uint8) (err error) {
+	insertedRecord = &record
 
-	if len(triggeredByEventsData) > 0 {
-		if err = json.Unmarshal(triggeredByEventsData, &bot.Events); err != nil {
-			return
-		}
-	}
-	if len(groupsData) > 0 {
-		if err = json.Unmarshal(groupsData, &bot.Groups); err != nil {
-			return
-		}
-	}
-	if len(organizationsData) > 0 {
-		if err = json.Unmarshal(organizationsData, &bot.Organizations); err != nil {
-			return
-		}
+	if err = row.Scan(&insertedRecord.ID, &insertedRecord.InsertedAt); err != nil {
+		return nil, err
 	}
 
 	return
 }
 
-func (c *client) getLatestReleasesForPipeline(ctx context.Context, pipeline *contracts.Pipeline) {
-	pipeline.ReleaseTargets = c.getLatestReleases(ctx, pipeline.ReleaseTargets, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName)
-}
+func (c *client) GetAuditLogRecords(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (records []*AuditLogRecord, err error) {
 
-func (c *client) getLatestReleasesForBuild(ctx context.Context, build *contracts.Build) {
-	build.ReleaseTargets = c.getLatestReleases(ctx, build.ReleaseTargets, build.RepoSource, build.RepoOwner, build.RepoName)
-}
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
-func (c *client) getLatestReleases(ctx context.Context, releaseTargets []contracts.ReleaseTarget, repoSource, repoOwner, repoName string) []contracts.ReleaseTarget {
+	query := psql.
+		Select("a.id, a.actor, a.client_id, a.impersonator, a.action, a.target_type, a.target_id, a.before, a.after, a.request_id, a.inserted_at").
+		From("audit_log a").
+		OrderBy("a.inserted_at DESC").
+		Limit(uint64(pageSize)).
+		Offset(uint64((pageNumber - 1) * pageSize))
 
-	// set latest release version per release targets
-	updatedReleaseTargets := make([]contracts.ReleaseTarget, 0)
-	for _, rt := range releaseTargets {
+	// dynamically set where clauses for filtering
+	query, err = whereClauseGeneratorForAuditLogFilters(query, filters)
+	if err != nil {
+		return
+	}
 
-		actions := getActionNamesFromReleaseTarget(rt)
-		latestReleases, err := c.GetPipelineLastReleasesByName(ctx, repoSource, repoOwner, repoName, rt.Name, actions)
-		if err != nil {
-			log.Error().Err(err).Msgf("Failed retrieving latest release for %v/%v/%v %v", repoSource, repoOwner, repoName, rt.Name)
-		} else {
-			rt.ActiveReleases = latestReleases
-		}
-		updatedReleaseTargets = append(updatedReleaseTargets, rt)
+	rows, err := query.RunWith(c.databaseConnection).QueryContext(ctx)
+	if err != nil {
+		return
 	}
 
-	return updatedReleaseTargets
+	return c.scanAuditLogRecords(rows)
 }
 
-func getActionNamesFromReleaseTarget(releaseTarget contracts.ReleaseTarget) (actions []string) {
+func (c *client) GetAuditLogRecordsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
 
-	actions = []string{}
+	query :=
+		sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+			Select("COUNT(*)").
+			From("audit_log a")
 
-	if releaseTarget.Actions != nil && len(releaseTarget.Actions) > 0 {
-		for _, a := range releaseTarget.Actions {
-			actions = append(actions, a.Name)
-		}
+	// dynamically set where clauses for filtering
+	query, err = whereClauseGeneratorForAuditLogFilters(query, filters)
+	if err != nil {
+		return
+	}
+
+	row := query.RunWith(c.databaseConnection).QueryRowContext(ctx)
+	if err = row.Scan(&count); err != nil {
+		return
 	}
 
 	return
 }
 
-func (c *client) mapBuildToPipeline(build *contracts.Build) (pipeline *contracts.Pipeline) {
+func whereClauseGeneratorForAuditLogFilters(query sq.SelectBuilder, filters map[api.FilterType][]string) (sq.SelectBuilder, error) {
 
-	// get archived value from manifest
-	mft, err := manifest.ReadManifest(c.config.ManifestPreferences, build.Manifest, false)
-	archived := false
-	if err == nil {
-		archived = mft.Archived
+	query, err := whereClauseGeneratorForSinceFilter(query, "inserted_at", filters)
+	if err != nil {
+		return query, err
+	}
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterActor, "actor")
+	if err != nil {
+		return query, err
+	}
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterAction, "action")
+	if err != nil {
+		return query, err
+	}
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterTargetType, "target_type")
+	if err != nil {
+		return query, err
+	}
+	query, err = whereClauseGeneratorForGenericFilter(query, filters, api.FilterTargetID, "target_id")
+	if err != nil {
+		return query, err
 	}
 
-	return &contracts.Pipeline{
-		ID:                   build.ID,
-		RepoSource:           build.RepoSource,
-		RepoOwner:            build.RepoOwner,
-		RepoName:             build.RepoName,
-		RepoBranch:           build.RepoBranch,
-		RepoRevision:         build.RepoRevision,
-		BuildVersion:         build.BuildVersion,
-		BuildStatus:          build.BuildStatus,
-		Labels:               build.Labels,
-		ReleaseTargets:       build.ReleaseTargets,
-		Manifest:             build.Manifest,
-		ManifestWithDefaults: build.ManifestWithDefaults,
-		Commits:              build.Commits,
-		Groups:               build.Groups,
-		Organizations:        build.Organizations,
-		Triggers:             build.Triggers,
-		Archived:             archived,
-		InsertedAt:           build.InsertedAt,
-		StartedAt:            build.StartedAt,
-		UpdatedAt:            build.UpdatedAt,
-		Duration:             build.Duration,
-		Events:               build.Events,
+	return query, nil
+}
+
+func (c *client) scanAuditLogRecords(rows *sql.Rows) (records []*AuditLogRecord, err error) {
+
+	records = make([]*AuditLogRecord, 0)
+
+	defer _CloseRows(rows)
+	for rows.Next() {
+
+		record := AuditLogRecord{}
+		var clientID, impersonator, targetID, requestID *string
+		var before, after []uint8
+
+		if err = rows.Scan(
+			&record.ID,
+			&record.Actor,
+			&clientID,
+			&impersonator,
+			&record.Action,
+			&record.TargetType,
+			&targetID,
+			&before,
+			&after,
+			&requestID,
+			&record.InsertedAt); err != nil {
+			return
+		}
+
+		if clientID != nil {
+			record.ClientID = *clientID
+		}
+		if impersonator != nil {
+			record.Impersonator = *impersonator
+		}
+		if targetID != nil {
+			record.TargetID = *targetID
+		}
+		if requestID != nil {
+			record.RequestID = *requestID
+		}
+		if len(before) > 0 {
+			if err = json.Unmarshal(before, &record.Before); err != nil {
+				return
+			}
+		}
+		if len(after) > 0 {
+			if err = json.Unmarshal(after, &record.After); err != nil {
+				return
+			}
+		}
+
+		records = append(records, &record)
 	}
+
+	return
 }
 
-func _CloseRows(rows *sql.Rows) {
-	err := rows.Close()
-	if err != nil {
-		log.Error().Err(err).Msg("failed to close rows")
+// marshalAuditLogState returns the json for the before or after state of an audit log record, or nil to store NULL when there is no state
+func marshalAuditLogState(state interface{}) (stateBytes []byte, err error) {
+	if state == nil {
+		return nil, nil
 	}
+
+	return json.Marshal(state)
 }
@@ -2,13 +2,16 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
 	"os"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
@@ -71,6 +74,7 @@ func TestIntegrationInsertBuild(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, insertedBuild)
 		assert.True(t, insertedBuild.ID != "")
+		assert.False(t, insertedBuild.InsertedAt.IsZero())
 	})
 }
 
@@ -188,6 +192,120 @@ func TestIntegrationUpdateBuildResourceUtilization(t *testing.T) {
 	})
 }
 
+func TestIntegrationGetPipelineBuildResourceUsages(t *testing.T) {
+	t.Run("ReturnsMeasuredUsageOfBuildsForBranch", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		build := getBuild()
+		build.RepoName = "resource-usages-test"
+		jobResources := getJobResources()
+		insertedBuild, err := databaseClient.InsertBuild(ctx, build, jobResources)
+		assert.Nil(t, err)
+
+		err = databaseClient.UpdateBuildResourceUtilization(ctx, insertedBuild.RepoSource, insertedBuild.RepoOwner, insertedBuild.RepoName, insertedBuild.ID, JobResources{
+			CPUMaxUsage:    float64(0.8),
+			MemoryMaxUsage: float64(1073741824),
+			OOMKilled:      true,
+		})
+		assert.Nil(t, err)
+
+		// act
+		usages, err := databaseClient.GetPipelineBuildResourceUsages(ctx, build.RepoSource, build.RepoOwner, build.RepoName, build.RepoBranch, 25)
+
+		assert.Nil(t, err)
+		if assert.True(t, len(usages) > 0) {
+			assert.Equal(t, float64(0.8), usages[0].CPUMaxUsage)
+			assert.Equal(t, float64(1073741824), usages[0].MemoryMaxUsage)
+			assert.Equal(t, jobResources.MemoryRequest, usages[0].MemoryRequest)
+			assert.True(t, usages[0].OOMKilled)
+			assert.False(t, usages[0].CPUThrottled)
+		}
+	})
+}
+
+func TestIntegrationGetJobResourceUtilizations(t *testing.T) {
+	t.Run("ReturnsResourcesOfFinishedBuildsInsertedInTimeRange", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		build := getBuild()
+		build.BuildStatus = contracts.StatusSucceeded
+		build.RepoName = "job-resource-utilizations-test"
+		jobResources := getJobResources()
+		insertedBuild, err := databaseClient.InsertBuild(ctx, build, jobResources)
+		assert.Nil(t, err)
+
+		// act
+		utilizations, err := databaseClient.GetJobResourceUtilizations(ctx, contracts.JobTypeBuild, time.Now().Add(-1*time.Hour), time.Now().Add(time.Hour))
+
+		assert.Nil(t, err)
+		var utilization *JobResourceUtilization
+		for _, u := range utilizations {
+			if u.ID == insertedBuild.ID {
+				utilization = u
+			}
+		}
+		if assert.NotNil(t, utilization) {
+			assert.Equal(t, contracts.JobTypeBuild, utilization.JobType)
+			assert.Equal(t, build.RepoName, utilization.RepoName)
+			assert.Equal(t, jobResources.CPURequest, utilization.CPURequest)
+			assert.Equal(t, jobResources.MemoryRequest, utilization.MemoryRequest)
+			assert.Equal(t, build.Labels, utilization.Labels)
+			assert.False(t, utilization.Measured)
+		}
+	})
+}
+
+func TestIntegrationGetReleaseOutcomes(t *testing.T) {
+	t.Run("ReturnsFinishedReleasesWithInsertedAtOfBuildOfReleasedVersion", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		build := getBuild()
+		build.RepoName = "release-outcomes-test"
+		build.BuildVersion = "1.0.0"
+		build.BuildStatus = contracts.StatusSucceeded
+		jobResources := getJobResources()
+		_, err := databaseClient.InsertBuild(ctx, build, jobResources)
+		assert.Nil(t, err)
+		release := getRelease()
+		release.RepoName = build.RepoName
+		release.ReleaseVersion = build.BuildVersion
+		release.ReleaseStatus = contracts.StatusSucceeded
+		insertedRelease, err := databaseClient.InsertRelease(ctx, release, jobResources)
+		assert.Nil(t, err)
+
+		// act
+		outcomes, err := databaseClient.GetReleaseOutcomes(ctx, build.RepoSource, build.RepoOwner, build.RepoName, time.Now().Add(-1*time.Hour), time.Now().Add(time.Hour), map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		var outcome *ReleaseOutcome
+		for _, o := range outcomes {
+			if o.ID == insertedRelease.ID {
+				outcome = o
+			}
+		}
+		if assert.NotNil(t, outcome) {
+			assert.Equal(t, "production", outcome.Name)
+			assert.Equal(t, contracts.StatusSucceeded, outcome.ReleaseStatus)
+			assert.NotNil(t, outcome.BuildInsertedAt)
+		}
+	})
+}
+
 func TestIntegrationInsertRelease(t *testing.T) {
 	t.Run("ReturnsInsertedReleaseWithID", func(t *testing.T) {
 
@@ -1611,6 +1729,51 @@ func TestIntegrationGetUserByIdentity(t *testing.T) {
 	})
 }
 
+func TestIntegrationGetUserByEmail(t *testing.T) {
+	t.Run("ReturnsUserWithIdentityForEmailOfAnyProvider", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		user := getUser()
+		user.Identities = []*contracts.UserIdentity{
+			{
+				Provider: "google",
+				ID:       "tim",
+				Email:    "tim-test@homeimprovement.com",
+			},
+		}
+		insertedUser, err := databaseClient.InsertUser(ctx, user)
+		assert.Nil(t, err)
+
+		// act
+		retrievedUser, err := databaseClient.GetUserByEmail(ctx, "tim-test@homeimprovement.com")
+
+		assert.Nil(t, err)
+		assert.NotNil(t, retrievedUser)
+		assert.Equal(t, retrievedUser.ID, insertedUser.ID)
+	})
+
+	t.Run("ReturnsErrUserNotFoundIfNoIdentityHasEmail", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+
+		// act
+		retrievedUser, err := databaseClient.GetUserByEmail(ctx, "nobody@homeimprovement.com")
+
+		assert.True(t, errors.Is(err, ErrUserNotFound))
+		assert.Nil(t, retrievedUser)
+	})
+}
+
 func TestIntegrationGetUserByID(t *testing.T) {
 	t.Run("ReturnsInsertedUserWithID", func(t *testing.T) {
 
@@ -1968,6 +2131,45 @@ func TestIntegrationGetGroupByID(t *testing.T) {
 	})
 }
 
+func TestIntegrationGetGroupByName(t *testing.T) {
+	t.Run("ReturnsInsertedGroupWithName", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		group := getGroup()
+		group.Name = "group-name-test"
+		insertedGroup, err := databaseClient.InsertGroup(ctx, group)
+		assert.Nil(t, err)
+
+		// act
+		retrievedGroup, err := databaseClient.GetGroupByName(ctx, "group-name-test")
+
+		assert.Nil(t, err)
+		assert.NotNil(t, retrievedGroup)
+		assert.Equal(t, retrievedGroup.ID, insertedGroup.ID)
+		assert.Equal(t, retrievedGroup.Name, insertedGroup.Name)
+	})
+
+	t.Run("ReturnsErrGroupNotFoundForUnknownName", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+
+		// act
+		_, err := databaseClient.GetGroupByName(ctx, "group-name-that-does-not-exist")
+
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+}
+
 func TestIntegrationGetGroups(t *testing.T) {
 	t.Run("ReturnsInsertedGroups", func(t *testing.T) {
 
@@ -3560,102 +3762,879 @@ func TestIntegrationGetAllNotificationsCount(t *testing.T) {
 	})
 }
 
-var dbTestClient Client
-var dbTestClientMutex = &sync.Mutex{}
+func TestIntegrationInsertWebhookDelivery(t *testing.T) {
+	t.Run("ReturnsInsertedWebhookDeliveryWithID", func(t *testing.T) {
 
-func getDatabaseClient(ctx context.Context, t *testing.T) Client {
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
 
-	dbTestClientMutex.Lock()
-	defer dbTestClientMutex.Unlock()
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
 
-	if dbTestClient != nil {
-		return dbTestClient
-	}
+		// act
+		insertedDelivery, err := databaseClient.InsertWebhookDelivery(ctx, getWebhookDelivery())
 
-	databaseName := "defaultdb"
-	if os.Getenv("DB_DATABASE") != "" {
-		databaseName = os.Getenv("DB_DATABASE")
-	}
-	host := "ziplinee-ci-db-public"
-	if os.Getenv("DB_HOST") != "" {
-		host = os.Getenv("DB_HOST")
-	}
-	insecure := true
-	if os.Getenv("DB_INSECURE") != "" {
-		dbInsecure, err := strconv.ParseBool(os.Getenv("DB_INSECURE"))
-		if err == nil {
-			insecure = dbInsecure
-		}
-	}
-	port := 26257
-	if os.Getenv("DB_PORT") != "" {
-		dbPort, err := strconv.Atoi(os.Getenv("DB_PORT"))
-		if err == nil {
-			port = dbPort
-		}
-	}
-	user := "root"
-	if os.Getenv("DB_USER") != "" {
-		user = os.Getenv("DB_USER")
-	}
-	password := ""
-	if os.Getenv("DB_PASSWORD") != "" {
-		password = os.Getenv("DB_PASSWORD")
-	}
+		assert.Nil(t, err)
+		assert.NotNil(t, insertedDelivery)
+		assert.True(t, insertedDelivery.ID != "")
+	})
+}
 
-	maxOpenConnections := 0
-	if os.Getenv("DB_MAX_OPEN_CONNECTIONS") != "" {
-		dbMaxOpenConnections, err := strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNECTIONS"))
-		if err == nil {
-			maxOpenConnections = dbMaxOpenConnections
+func TestIntegrationGetWebhookDeliveryByID(t *testing.T) {
+	t.Run("ReturnsWebhookDeliveryWithUpdatedOutcome", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
 		}
-	}
 
-	maxIdleConnections := 2
-	if os.Getenv("DB_MAX_IDLE_CONNECTIONS") != "" {
-		dbMaxIdleConnections, err := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNECTIONS"))
-		if err == nil {
-			maxIdleConnections = dbMaxIdleConnections
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedDelivery, err := databaseClient.InsertWebhookDelivery(ctx, getWebhookDelivery())
+		assert.Nil(t, err)
+		err = databaseClient.UpdateWebhookDeliveryOutcome(ctx, insertedDelivery.ID, "failed", 500, "Creating build job for github push failed")
+		assert.Nil(t, err)
+
+		// act
+		delivery, err := databaseClient.GetWebhookDeliveryByID(ctx, insertedDelivery.ID)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, delivery)
+		assert.Equal(t, "failed", delivery.Outcome)
+		assert.Equal(t, 500, delivery.StatusCode)
+		assert.Equal(t, "push", delivery.Headers.Get("X-Github-Event"))
+		assert.Equal(t, `{"ref":"refs/heads/main"}`, string(delivery.Body))
+	})
+}
+
+func TestIntegrationGetWebhookDeliveries(t *testing.T) {
+	t.Run("ReturnsFirstPageOfWebhookDeliveries", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
 		}
-	}
 
-	apiConfig := &api.APIConfig{
-		Database: &api.DatabaseConfig{
-			DatabaseName: databaseName,
-			Host:         host,
-			Insecure:     insecure,
-			Port:         port,
-			User:         user,
-			Password:     password,
-			MaxOpenConns: maxOpenConnections,
-			MaxIdleConns: maxIdleConnections,
-		},
-	}
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		_, err := databaseClient.InsertWebhookDelivery(ctx, getWebhookDelivery())
+		assert.Nil(t, err)
 
-	apiConfig.SetDefaults()
+		// act
+		deliveries, err := databaseClient.GetWebhookDeliveries(ctx, 1, 20, map[api.FilterType][]string{api.FilterSource: {"github"}}, []api.OrderField{})
 
-	dbTestClient = NewClient(apiConfig)
-	err := dbTestClient.Connect(ctx)
+		assert.Nil(t, err)
+		assert.True(t, len(deliveries) > 0)
+	})
+}
 
-	assert.Nil(t, err)
+func TestIntegrationQueueMigrationTask(t *testing.T) {
+	t.Run("ReturnsQueuedMigrationTaskWithID", func(t *testing.T) {
 
-	err = dbTestClient.AwaitDatabaseReadiness(ctx)
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
 
-	assert.Nil(t, err)
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
 
-	return dbTestClient
+		// act
+		task, err := databaseClient.QueueMigrationTask(ctx, getMigrationTask())
+
+		assert.Nil(t, err)
+		assert.NotNil(t, task)
+		assert.True(t, task.ID != "")
+		assert.Equal(t, MigrationStatusQueued, task.Status)
+	})
 }
 
-func getBuild() contracts.Build {
-	return contracts.Build{
-		RepoSource:     "github.com",
-		RepoOwner:      "ziplineeci",
-		RepoName:       "ziplinee-ci-api",
-		RepoBranch:     "master",
-		RepoRevision:   "08e9480b75154b5584995053344beb4d4aef65f4",
-		BuildVersion:   "0.0.99",
-		BuildStatus:    contracts.StatusPending,
-		Labels:         []contracts.Label{{Key: "app-group", Value: "ziplinee-ci"}, {Key: "language", Value: "golang"}},
+func TestIntegrationGetMigrationTaskByID(t *testing.T) {
+	t.Run("ReturnsMigrationTaskWithUpdatedStatus", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		queuedTask, err := databaseClient.QueueMigrationTask(ctx, getMigrationTask())
+		assert.Nil(t, err)
+		err = databaseClient.UpdateMigrationTask(ctx, queuedTask.ID, MigrationStatusFailed, MigrationStepBuilds.Failed(), 0, 0, time.Second, "builds: failed\n")
+		assert.Nil(t, err)
+
+		// act
+		task, err := databaseClient.GetMigrationTaskByID(ctx, queuedTask.ID)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, task)
+		assert.Equal(t, MigrationStatusFailed, task.Status)
+		assert.Equal(t, "builds_failed", task.LastStep)
+	})
+
+	t.Run("ReturnsErrMigrationTaskNotFoundForUnknownID", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+
+		// act
+		_, err := databaseClient.GetMigrationTaskByID(ctx, "does-not-exist")
+
+		assert.ErrorIs(t, err, ErrMigrationTaskNotFound)
+	})
+}
+
+func TestIntegrationGetExpiredLogIDs(t *testing.T) {
+	t.Run("ReturnsLogsInsertedBeforeCutoffExceptKeptLogs", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		buildLog := getBuildLog()
+		buildLog.RepoName = "ziplinee-ci-api-log-retention"
+		insertedLog, err := databaseClient.InsertBuildLog(ctx, buildLog)
+		assert.Nil(t, err)
+		keptLog, err := databaseClient.InsertBuildLog(ctx, buildLog)
+		assert.Nil(t, err)
+
+		// act
+		logIDs, err := databaseClient.GetExpiredLogIDs(ctx, contracts.JobTypeBuild, buildLog.RepoSource, buildLog.RepoOwner, buildLog.RepoName, time.Now().Add(time.Hour), []string{keptLog.ID}, 100)
+
+		assert.Nil(t, err)
+		assert.Contains(t, logIDs, insertedLog.ID)
+		assert.NotContains(t, logIDs, keptLog.ID)
+	})
+}
+
+func TestIntegrationDeleteLogsByID(t *testing.T) {
+	t.Run("DeletesLogs", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedLog, err := databaseClient.InsertReleaseLog(ctx, getReleaseLog())
+		assert.Nil(t, err)
+
+		// act
+		count, err := databaseClient.DeleteLogsByID(ctx, contracts.JobTypeRelease, []string{insertedLog.ID})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestIntegrationGetLogRecords(t *testing.T) {
+	t.Run("ReturnsLogRecordsWithJobID", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		releaseLog := getReleaseLog()
+		insertedLog, err := databaseClient.InsertReleaseLog(ctx, releaseLog)
+		assert.Nil(t, err)
+
+		// act
+		records, err := databaseClient.GetLogRecords(ctx, contracts.JobTypeRelease, releaseLog.RepoSource, releaseLog.RepoOwner, releaseLog.RepoName)
+
+		assert.Nil(t, err)
+		if assert.True(t, len(records) > 0) {
+			assert.Equal(t, insertedLog.ID, records[0].ID)
+			assert.Equal(t, releaseLog.ReleaseID, records[0].JobID)
+		}
+	})
+}
+
+func TestIntegrationQueueLogReconciliation(t *testing.T) {
+	t.Run("QueuesAndPicksLogReconciliation", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+
+		// act
+		queuedReconciliation, err := databaseClient.QueueLogReconciliation(ctx, LogReconciliation{DryRun: true, QueuedBy: "log-migrator"})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, queuedReconciliation.ID)
+		assert.Equal(t, LogReconciliationStatusQueued, queuedReconciliation.Status)
+
+		pickedReconciliation, err := databaseClient.PickLogReconciliation(ctx, time.Now().UTC().Add(-15*time.Minute))
+		assert.Nil(t, err)
+		if assert.NotNil(t, pickedReconciliation) {
+			assert.Equal(t, LogReconciliationStatusInProgress, pickedReconciliation.Status)
+			assert.NotNil(t, pickedReconciliation.StartedAt)
+
+			completedAt := time.Now().UTC()
+			pickedReconciliation.Status = LogReconciliationStatusCompleted
+			pickedReconciliation.Checked = 5
+			pickedReconciliation.CompletedAt = &completedAt
+			err = databaseClient.UpdateLogReconciliation(ctx, *pickedReconciliation)
+			assert.Nil(t, err)
+		}
+
+		reconciliation, err := databaseClient.GetLogReconciliationByID(ctx, queuedReconciliation.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, LogReconciliationStatusCompleted, reconciliation.Status)
+		assert.Equal(t, 5, reconciliation.Checked)
+	})
+}
+
+func TestIntegrationInsertNotificationDelivery(t *testing.T) {
+	t.Run("InsertsAndPicksNotificationDelivery", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+
+		// act
+		insertedDelivery, err := databaseClient.InsertNotificationDelivery(ctx, NotificationDelivery{
+			Channel:     "team-webhook",
+			ChannelType: "webhook",
+			EventType:   "build",
+			RepoSource:  "github.com",
+			RepoOwner:   "ziplineeci",
+			RepoName:    "ziplinee-ci-api",
+			SourceID:    "1",
+			Payload:     json.RawMessage(`{"event":"build","status":"succeeded"}`),
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, insertedDelivery.ID)
+		assert.Equal(t, NotificationDeliveryStatusPending, insertedDelivery.Status)
+
+		pickedDeliveries, err := databaseClient.PickNotificationDeliveries(ctx, 100, time.Now().UTC().Add(-5*time.Minute))
+		assert.Nil(t, err)
+		for _, d := range pickedDeliveries {
+			assert.Equal(t, NotificationDeliveryStatusInProgress, d.Status)
+			if d.ID != insertedDelivery.ID {
+				continue
+			}
+
+			deliveredAt := time.Now().UTC()
+			d.Status = NotificationDeliveryStatusSucceeded
+			d.Attempts = 1
+			d.DeliveredAt = &deliveredAt
+			err = databaseClient.UpdateNotificationDelivery(ctx, *d)
+			assert.Nil(t, err)
+		}
+
+		delivery, err := databaseClient.GetNotificationDeliveryByID(ctx, insertedDelivery.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, NotificationDeliveryStatusSucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.JSONEq(t, `{"event":"build","status":"succeeded"}`, string(delivery.Payload))
+	})
+}
+
+func TestIntegrationInsertAuditLogRecord(t *testing.T) {
+	t.Run("ReturnsInsertedRecordWithID", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+
+		// act
+		insertedRecord, err := databaseClient.InsertAuditLogRecord(ctx, AuditLogRecord{
+			Actor:      "jane@ziplinee.io",
+			Action:     "user.rolemappings.applied",
+			TargetType: "user",
+			TargetID:   "7",
+			Before:     map[string]interface{}{"roles": []string{}},
+			After:      map[string]interface{}{"roles": []string{"organization.pipelines.operator"}},
+		})
+
+		assert.Nil(t, err)
+		assert.NotNil(t, insertedRecord)
+		assert.True(t, insertedRecord.ID != "")
+		assert.False(t, insertedRecord.InsertedAt.IsZero())
+	})
+}
+
+func TestIntegrationGetAuditLogRecords(t *testing.T) {
+	t.Run("ReturnsRecordsFilteredByTargetTypeAndID", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedRecord, err := databaseClient.InsertAuditLogRecord(ctx, AuditLogRecord{
+			Actor:        "admin@ziplinee.io",
+			Impersonator: "root@ziplinee.io",
+			Action:       "client.updated",
+			TargetType:   "client",
+			TargetID:     "audit-log-test-client",
+			Before:       map[string]interface{}{"roles": []string{}},
+			After:        map[string]interface{}{"roles": []string{"administrator"}},
+			RequestID:    "f2b8a1c6",
+		})
+		assert.Nil(t, err)
+
+		// act
+		records, err := databaseClient.GetAuditLogRecords(ctx, 1, 10, map[api.FilterType][]string{
+			api.FilterTargetType: {"client"},
+			api.FilterTargetID:   {"audit-log-test-client"},
+		})
+
+		assert.Nil(t, err)
+		if assert.True(t, len(records) > 0) {
+			assert.Equal(t, insertedRecord.ID, records[0].ID)
+			assert.Equal(t, "root@ziplinee.io", records[0].Impersonator)
+			assert.Equal(t, "f2b8a1c6", records[0].RequestID)
+			assert.NotNil(t, records[0].After)
+		}
+	})
+}
+
+func TestIntegrationGetAuditLogRecordsCount(t *testing.T) {
+	t.Run("ReturnsCountOfRecordsFilteredByActor", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		_, err := databaseClient.InsertAuditLogRecord(ctx, AuditLogRecord{
+			Actor:      "audit-log-count-test@ziplinee.io",
+			Action:     "DELETE /api/pipelines/:source/:owner/:repo/builds/:revisionOrId",
+			TargetType: "builds",
+			TargetID:   "github.com/ziplineeci/ziplinee-ci-api/1234",
+		})
+		assert.Nil(t, err)
+
+		// act
+		count, err := databaseClient.GetAuditLogRecordsCount(ctx, map[api.FilterType][]string{
+			api.FilterActor: {"audit-log-count-test@ziplinee.io"},
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, count > 0)
+	})
+}
+
+func TestIntegrationInsertCustomRole(t *testing.T) {
+	t.Run("ReturnsInsertedCustomRoleWithID", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+
+		// act
+		insertedCustomRole, err := databaseClient.InsertCustomRole(ctx, getCustomRole())
+
+		assert.Nil(t, err)
+		assert.NotNil(t, insertedCustomRole)
+		assert.True(t, insertedCustomRole.ID != "")
+		assert.True(t, insertedCustomRole.Active)
+	})
+}
+
+func TestIntegrationGetCustomRoleByID(t *testing.T) {
+	t.Run("ReturnsInsertedCustomRoleWithBindings", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedCustomRole, err := databaseClient.InsertCustomRole(ctx, getCustomRole())
+		assert.Nil(t, err)
+
+		// act
+		retrievedCustomRole, err := databaseClient.GetCustomRoleByID(ctx, insertedCustomRole.ID)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, retrievedCustomRole)
+		assert.Equal(t, insertedCustomRole.ID, retrievedCustomRole.ID)
+		assert.Equal(t, []string{"ci.releases.create"}, retrievedCustomRole.Permissions)
+		assert.Equal(t, 1, len(retrievedCustomRole.Bindings))
+		assert.Equal(t, []string{"production"}, retrievedCustomRole.Bindings[0].ReleaseTargets)
+	})
+
+	t.Run("ReturnsErrCustomRoleNotFoundForDeletedCustomRole", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedCustomRole, err := databaseClient.InsertCustomRole(ctx, getCustomRole())
+		assert.Nil(t, err)
+		err = databaseClient.DeleteCustomRole(ctx, *insertedCustomRole)
+		assert.Nil(t, err)
+
+		// act
+		_, err = databaseClient.GetCustomRoleByID(ctx, insertedCustomRole.ID)
+
+		assert.ErrorIs(t, err, ErrCustomRoleNotFound)
+	})
+}
+
+func TestIntegrationUpdateCustomRole(t *testing.T) {
+	t.Run("UpdatesCustomRole", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedCustomRole, err := databaseClient.InsertCustomRole(ctx, getCustomRole())
+		assert.Nil(t, err)
+		insertedCustomRole.Permissions = append(insertedCustomRole.Permissions, "ci.releases.cancel")
+
+		// act
+		err = databaseClient.UpdateCustomRole(ctx, *insertedCustomRole)
+
+		assert.Nil(t, err)
+		retrievedCustomRole, err := databaseClient.GetCustomRoleByID(ctx, insertedCustomRole.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ci.releases.create", "ci.releases.cancel"}, retrievedCustomRole.Permissions)
+	})
+}
+
+func TestIntegrationGetCustomRoles(t *testing.T) {
+	t.Run("ReturnsInsertedCustomRoles", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		_, err := databaseClient.InsertCustomRole(ctx, getCustomRole())
+		assert.Nil(t, err)
+
+		// act
+		customRoles, err := databaseClient.GetCustomRoles(ctx, 1, 100, map[api.FilterType][]string{}, []api.OrderField{})
+
+		assert.Nil(t, err)
+		assert.True(t, len(customRoles) > 0)
+	})
+}
+
+func TestIntegrationGetCustomRolesCount(t *testing.T) {
+	t.Run("ReturnsCountOfInsertedCustomRoles", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		_, err := databaseClient.InsertCustomRole(ctx, getCustomRole())
+		assert.Nil(t, err)
+
+		// act
+		count, err := databaseClient.GetCustomRolesCount(ctx, map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		assert.True(t, count > 0)
+	})
+}
+
+func TestIntegrationInsertPersonalAccessToken(t *testing.T) {
+	t.Run("ReturnsInsertedTokenWithIDWithoutStoringToken", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		token := getPersonalAccessToken()
+
+		// act
+		insertedToken, err := databaseClient.InsertPersonalAccessToken(ctx, token, "hash-of-inserted-token")
+
+		assert.Nil(t, err)
+		assert.True(t, insertedToken.ID != "")
+		assert.True(t, insertedToken.Active)
+		assert.NotNil(t, insertedToken.CreatedAt)
+
+		retrievedToken, err := databaseClient.GetPersonalAccessTokenByID(ctx, insertedToken.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "", retrievedToken.Token)
+		assert.Equal(t, token.Scopes, retrievedToken.Scopes)
+	})
+}
+
+func TestIntegrationGetPersonalAccessTokenByHash(t *testing.T) {
+	t.Run("ReturnsTokenWithLastUsedTimestamp", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedToken, err := databaseClient.InsertPersonalAccessToken(ctx, getPersonalAccessToken(), "hash-of-used-token")
+		assert.Nil(t, err)
+		err = databaseClient.UpdatePersonalAccessTokenLastUsed(ctx, insertedToken.ID)
+		assert.Nil(t, err)
+
+		// act
+		retrievedToken, err := databaseClient.GetPersonalAccessTokenByHash(ctx, "hash-of-used-token")
+
+		assert.Nil(t, err)
+		assert.Equal(t, insertedToken.ID, retrievedToken.ID)
+		assert.NotNil(t, retrievedToken.LastUsedAt)
+	})
+
+	t.Run("ReturnsErrPersonalAccessTokenNotFoundForRevokedToken", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedToken, err := databaseClient.InsertPersonalAccessToken(ctx, getPersonalAccessToken(), "hash-of-revoked-token")
+		assert.Nil(t, err)
+		err = databaseClient.DeletePersonalAccessToken(ctx, *insertedToken)
+		assert.Nil(t, err)
+
+		// act
+		_, err = databaseClient.GetPersonalAccessTokenByHash(ctx, "hash-of-revoked-token")
+
+		assert.ErrorIs(t, err, ErrPersonalAccessTokenNotFound)
+	})
+}
+
+func TestIntegrationGetPersonalAccessTokensForUser(t *testing.T) {
+	t.Run("ReturnsActiveTokensOfUser", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		token := getPersonalAccessToken()
+		token.UserID = "user-with-tokens"
+		_, err := databaseClient.InsertPersonalAccessToken(ctx, token, "hash-of-listed-token")
+		assert.Nil(t, err)
+
+		// act
+		tokens, err := databaseClient.GetPersonalAccessTokensForUser(ctx, "user-with-tokens")
+
+		assert.Nil(t, err)
+		assert.True(t, len(tokens) > 0)
+	})
+}
+
+func TestIntegrationHashLegacyClientSecret(t *testing.T) {
+	t.Run("HashesPlainTextSecretOnlyOnce", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedClient, err := databaseClient.InsertClient(ctx, getClient())
+		assert.Nil(t, err)
+
+		// act
+		hashed, err := databaseClient.HashLegacyClientSecret(ctx, *insertedClient, "hash-of-legacy-secret")
+		assert.Nil(t, err)
+		hashedAgain, err := databaseClient.HashLegacyClientSecret(ctx, *insertedClient, "hash-of-legacy-secret")
+
+		assert.Nil(t, err)
+		assert.True(t, hashed)
+		assert.False(t, hashedAgain)
+		secrets, err := databaseClient.GetClientSecretsForClient(ctx, insertedClient.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(secrets))
+		retrievedClient, err := databaseClient.GetClientByID(ctx, insertedClient.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "", retrievedClient.ClientSecret)
+	})
+}
+
+func TestIntegrationGetClientSecretByHash(t *testing.T) {
+	t.Run("ReturnsSecretWithLastUsedTimestamp", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedSecret, err := databaseClient.InsertClientSecret(ctx, api.ClientSecret{ClientID: "25"}, "hash-of-used-secret")
+		assert.Nil(t, err)
+		err = databaseClient.UpdateClientSecretLastUsed(ctx, insertedSecret.ID)
+		assert.Nil(t, err)
+
+		// act
+		retrievedSecret, err := databaseClient.GetClientSecretByHash(ctx, "25", "hash-of-used-secret")
+
+		assert.Nil(t, err)
+		assert.Equal(t, insertedSecret.ID, retrievedSecret.ID)
+		assert.NotNil(t, retrievedSecret.LastUsedAt)
+	})
+
+	t.Run("ReturnsErrClientSecretNotFoundForSecretOfOtherClient", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		_, err := databaseClient.InsertClientSecret(ctx, api.ClientSecret{ClientID: "25"}, "hash-of-other-secret")
+		assert.Nil(t, err)
+
+		// act
+		_, err = databaseClient.GetClientSecretByHash(ctx, "26", "hash-of-other-secret")
+
+		assert.ErrorIs(t, err, ErrClientSecretNotFound)
+	})
+
+	t.Run("ReturnsErrClientSecretNotFoundForDeletedSecret", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedSecret, err := databaseClient.InsertClientSecret(ctx, api.ClientSecret{ClientID: "25"}, "hash-of-deleted-secret")
+		assert.Nil(t, err)
+		err = databaseClient.DeleteClientSecret(ctx, *insertedSecret)
+		assert.Nil(t, err)
+
+		// act
+		_, err = databaseClient.GetClientSecretByHash(ctx, "25", "hash-of-deleted-secret")
+
+		assert.ErrorIs(t, err, ErrClientSecretNotFound)
+	})
+}
+
+func TestIntegrationGetClientSecretsForClient(t *testing.T) {
+	t.Run("ReturnsActiveSecretsOfClient", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		insertedSecret, err := databaseClient.InsertClientSecret(ctx, api.ClientSecret{ClientID: "27"}, "hash-of-listed-secret")
+		assert.Nil(t, err)
+
+		// act
+		secrets, err := databaseClient.GetClientSecretsForClient(ctx, "27")
+
+		assert.Nil(t, err)
+		if assert.True(t, len(secrets) > 0) {
+			assert.Equal(t, insertedSecret.ID, secrets[0].ID)
+			assert.True(t, secrets[0].Active)
+		}
+	})
+}
+
+func TestIntegrationInsertSession(t *testing.T) {
+	t.Run("ReturnsNoError", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		session := getSession()
+
+		// act
+		err := databaseClient.InsertSession(ctx, session)
+
+		assert.Nil(t, err)
+
+		retrievedSession, err := databaseClient.GetSessionByID(ctx, session.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, session.SubjectID, retrievedSession.SubjectID)
+		assert.Equal(t, session.UserAgent, retrievedSession.UserAgent)
+		assert.Nil(t, retrievedSession.RevokedAt)
+	})
+}
+
+func TestIntegrationRevokeSession(t *testing.T) {
+	t.Run("SetsRevokedAt", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		session := getSession()
+		err := databaseClient.InsertSession(ctx, session)
+		assert.Nil(t, err)
+
+		// act
+		err = databaseClient.RevokeSession(ctx, session.ID)
+
+		assert.Nil(t, err)
+
+		retrievedSession, err := databaseClient.GetSessionByID(ctx, session.ID)
+		assert.Nil(t, err)
+		assert.NotNil(t, retrievedSession.RevokedAt)
+	})
+}
+
+func TestIntegrationRevokeSessionsForSubject(t *testing.T) {
+	t.Run("RevokesActiveSessionsOfSubject", func(t *testing.T) {
+
+		if testing.Short() {
+			t.Skip("skipping test in short mode.")
+		}
+
+		ctx := context.Background()
+		databaseClient := getDatabaseClient(ctx, t)
+		session := getSession()
+		session.SubjectID = "subject-with-sessions"
+		err := databaseClient.InsertSession(ctx, session)
+		assert.Nil(t, err)
+
+		sessions, err := databaseClient.GetActiveSessionsForSubject(ctx, "subject-with-sessions")
+		assert.Nil(t, err)
+		assert.True(t, len(sessions) > 0)
+
+		// act
+		revokedSessionIDs, err := databaseClient.RevokeSessionsForSubject(ctx, "subject-with-sessions")
+
+		assert.Nil(t, err)
+		assert.Contains(t, revokedSessionIDs, session.ID)
+
+		sessions, err = databaseClient.GetActiveSessionsForSubject(ctx, "subject-with-sessions")
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(sessions))
+	})
+}
+
+var dbTestClient Client
+var dbTestClientMutex = &sync.Mutex{}
+
+func getDatabaseClient(ctx context.Context, t *testing.T) Client {
+
+	dbTestClientMutex.Lock()
+	defer dbTestClientMutex.Unlock()
+
+	if dbTestClient != nil {
+		return dbTestClient
+	}
+
+	databaseName := "defaultdb"
+	if os.Getenv("DB_DATABASE") != "" {
+		databaseName = os.Getenv("DB_DATABASE")
+	}
+	host := "ziplinee-ci-db-public"
+	if os.Getenv("DB_HOST") != "" {
+		host = os.Getenv("DB_HOST")
+	}
+	insecure := true
+	if os.Getenv("DB_INSECURE") != "" {
+		dbInsecure, err := strconv.ParseBool(os.Getenv("DB_INSECURE"))
+		if err == nil {
+			insecure = dbInsecure
+		}
+	}
+	port := 26257
+	if os.Getenv("DB_PORT") != "" {
+		dbPort, err := strconv.Atoi(os.Getenv("DB_PORT"))
+		if err == nil {
+			port = dbPort
+		}
+	}
+	user := "root"
+	if os.Getenv("DB_USER") != "" {
+		user = os.Getenv("DB_USER")
+	}
+	password := ""
+	if os.Getenv("DB_PASSWORD") != "" {
+		password = os.Getenv("DB_PASSWORD")
+	}
+
+	maxOpenConnections := 0
+	if os.Getenv("DB_MAX_OPEN_CONNECTIONS") != "" {
+		dbMaxOpenConnections, err := strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNECTIONS"))
+		if err == nil {
+			maxOpenConnections = dbMaxOpenConnections
+		}
+	}
+
+	maxIdleConnections := 2
+	if os.Getenv("DB_MAX_IDLE_CONNECTIONS") != "" {
+		dbMaxIdleConnections, err := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNECTIONS"))
+		if err == nil {
+			maxIdleConnections = dbMaxIdleConnections
+		}
+	}
+
+	apiConfig := &api.APIConfig{
+		Database: &api.DatabaseConfig{
+			DatabaseName: databaseName,
+			Host:         host,
+			Insecure:     insecure,
+			Port:         port,
+			User:         user,
+			Password:     password,
+			MaxOpenConns: maxOpenConnections,
+			MaxIdleConns: maxIdleConnections,
+		},
+	}
+
+	apiConfig.SetDefaults()
+
+	dbTestClient = NewClient(apiConfig)
+	err := dbTestClient.Connect(ctx)
+
+	assert.Nil(t, err)
+
+	err = dbTestClient.AwaitDatabaseReadiness(ctx)
+
+	assert.Nil(t, err)
+
+	return dbTestClient
+}
+
+func getBuild() contracts.Build {
+	return contracts.Build{
+		RepoSource:     "github.com",
+		RepoOwner:      "ziplineeci",
+		RepoName:       "ziplinee-ci-api",
+		RepoBranch:     "master",
+		RepoRevision:   "08e9480b75154b5584995053344beb4d4aef65f4",
+		BuildVersion:   "0.0.99",
+		BuildStatus:    contracts.StatusPending,
+		Labels:         []contracts.Label{{Key: "app-group", Value: "ziplinee-ci"}, {Key: "language", Value: "golang"}},
 		ReleaseTargets: []contracts.ReleaseTarget{},
 		Manifest:       "stages:\n  test:\n    image: golang:1.15.5-alpine3.12\n    commands:\n    - go test -short ./...",
 		Commits: []contracts.GitCommit{
@@ -3841,6 +4820,26 @@ func getUser() contracts.User {
 	}
 }
 
+func getCustomRole() api.CustomRole {
+	return api.CustomRole{
+		Name:        "payments-releaser",
+		Description: "Releases payments pipelines to production",
+		Permissions: []string{"ci.releases.create"},
+		Bindings: []*api.CustomRoleBinding{
+			{
+				Groups: []string{"team-payments"},
+				Labels: []contracts.Label{
+					{
+						Key:   "team",
+						Value: "payments",
+					},
+				},
+				ReleaseTargets: []string{"production"},
+			},
+		},
+	}
+}
+
 func getGroup() contracts.Group {
 	return contracts.Group{
 		Name: "Team A",
@@ -3906,3 +4905,49 @@ func getCatalogEntity() contracts.CatalogEntity {
 		UpdatedAt:  &now,
 	}
 }
+
+func getWebhookDelivery() WebhookDelivery {
+	return WebhookDelivery{
+		Source:    "github",
+		EventType: "push",
+		Headers:   http.Header{"X-Github-Event": []string{"push"}},
+		Body:      []byte(`{"ref":"refs/heads/main"}`),
+		BodyHash:  "090ba1b9ca860d37bb4ca7492549a8a347caac3490f763399e6498622c9b47f9",
+		Outcome:   "received",
+	}
+}
+
+func getMigrationTask() MigrationTask {
+	return MigrationTask{
+		ID:         uuid.New().String(),
+		FromSource: "github.com",
+		FromOwner:  "ziplineeci",
+		FromName:   "ziplinee-ci-api",
+		ToSource:   "github.com",
+		ToOwner:    "ziplineeci",
+		ToName:     "ziplinee-ci-api-migrated",
+	}
+}
+
+func getPersonalAccessToken() api.PersonalAccessToken {
+	expiresAt := time.Now().UTC().Add(30 * 24 * time.Hour)
+	return api.PersonalAccessToken{
+		UserID:    "15",
+		Name:      "release-script",
+		Scopes:    []string{"ci.releases.create"},
+		ExpiresAt: &expiresAt,
+	}
+}
+
+func getSession() api.Session {
+	now := time.Now().UTC()
+	return api.Session{
+		ID:        uuid.New().String(),
+		SubjectID: "15",
+		Email:     "user@server.com",
+		UserAgent: "Mozilla/5.0",
+		IPAddress: "10.0.0.1",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(8 * time.Hour),
+	}
+}
@@ -1,6 +1,12 @@
 package database
 
-import "time"
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
 
 // JobResources represents the used cpu and memory resources for a job and the measured maximum once it's done
 type JobResources struct {
@@ -10,6 +16,54 @@ type JobResources struct {
 	MemoryRequest  float64
 	MemoryLimit    float64
 	MemoryMaxUsage float64
+
+	// OOMKilled and CPUThrottled are measured along with the maximum, for sizing the next job of the same kind up
+	OOMKilled    bool
+	CPUThrottled bool
+}
+
+// JobResourceUtilization represents the resources a finished job requested for the duration of its run and the maximum it used,
+// along with the pipeline properties its costs are attributed by
+type JobResourceUtilization struct {
+	JobResources
+	JobType       contracts.JobType
+	ID            string
+	RepoSource    string
+	RepoOwner     string
+	RepoName      string
+	Labels        []contracts.Label
+	Groups        []*contracts.Group
+	Organizations []*contracts.Organization
+	InsertedAt    time.Time
+	Duration      time.Duration
+
+	// Measured is false if the maximum usage of the job is unknown
+	Measured bool
+}
+
+// ReleaseOutcome represents a finished release along with the time the build of the version it released was created, to measure
+// delivery performance from
+type ReleaseOutcome struct {
+	ID             string
+	RepoSource     string
+	RepoOwner      string
+	RepoName       string
+	Name           string
+	Action         string
+	ReleaseVersion string
+	ReleaseStatus  contracts.Status
+	InsertedAt     time.Time
+	FinishedAt     time.Time
+
+	// BuildInsertedAt is nil if the build of the released version no longer exists
+	BuildInsertedAt *time.Time
+}
+
+// JobResourceUsage represents the resources and measured usage of a single finished job, for sizing future jobs on
+type JobResourceUsage struct {
+	JobResources
+	Status     contracts.Status
+	InsertedAt time.Time
 }
 
 // BuildVersionDetail represents a specific build, including version number, repo, branch, revision and manifest
@@ -23,3 +77,210 @@ type BuildVersionDetail struct {
 	Manifest     string
 	InsertedAt   time.Time
 }
+
+// QueuedJob represents a build, release or bot waiting in the job queue until it can be dispatched within the concurrency limits
+type QueuedJob struct {
+	ID              string
+	JobType         contracts.JobType
+	JobID           string
+	RepoSource      string
+	RepoOwner       string
+	RepoName        string
+	Organizations   []*contracts.Organization
+	CiBuilderParams []byte
+	QueuedAt        time.Time
+	// Attempts is the number of times dispatching the job failed and it got put back in the queue
+	Attempts int
+}
+
+// ActiveJob represents a build, release or bot that has been dispatched and hasn't finished yet
+type ActiveJob struct {
+	JobType       contracts.JobType
+	JobID         string
+	RepoSource    string
+	RepoOwner     string
+	RepoName      string
+	Organizations []*contracts.Organization
+}
+
+// WebhookDelivery represents an inbound integration event, stored with its outcome to be able to investigate and replay it
+type WebhookDelivery struct {
+	ID         string      `json:"id"`
+	Source     string      `json:"source"`
+	EventType  string      `json:"eventType,omitempty"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       []byte      `json:"-"`
+	BodyHash   string      `json:"bodyHash"`
+	AuthUser   string      `json:"-"`
+	Outcome    string      `json:"outcome"`
+	StatusCode int         `json:"statusCode,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	ReplayOf   string      `json:"replayOf,omitempty"`
+	InsertedAt time.Time   `json:"insertedAt"`
+	UpdatedAt  time.Time   `json:"updatedAt"`
+}
+
+// MigrationStatus is the state of a migration task
+type MigrationStatus string
+
+const (
+	MigrationStatusQueued     MigrationStatus = "queued"
+	MigrationStatusInProgress MigrationStatus = "in_progress"
+	MigrationStatusFailed     MigrationStatus = "failed"
+	MigrationStatusCompleted  MigrationStatus = "completed"
+	MigrationStatusCanceled   MigrationStatus = "canceled"
+	MigrationStatusUnknown    MigrationStatus = "unknown"
+)
+
+// MigrationStep is one of the consecutive steps a migration task goes through
+type MigrationStep string
+
+const (
+	MigrationStepReleases          MigrationStep = "releases"
+	MigrationStepReleaseLogs       MigrationStep = "release_logs"
+	MigrationStepReleaseLogObjects MigrationStep = "release_log_objects"
+	MigrationStepBuilds            MigrationStep = "builds"
+	MigrationStepBuildLogs         MigrationStep = "build_logs"
+	MigrationStepBuildLogObjects   MigrationStep = "build_log_objects"
+	MigrationStepBuildVersions     MigrationStep = "build_versions"
+	MigrationStepComputedTables    MigrationStep = "computed_tables"
+	MigrationStepCallback          MigrationStep = "callback"
+)
+
+// MigrationSteps holds all steps in the order they're executed
+var MigrationSteps = []MigrationStep{
+	MigrationStepReleases,
+	MigrationStepReleaseLogs,
+	MigrationStepReleaseLogObjects,
+	MigrationStepBuilds,
+	MigrationStepBuildLogs,
+	MigrationStepBuildLogObjects,
+	MigrationStepBuildVersions,
+	MigrationStepComputedTables,
+	MigrationStepCallback,
+}
+
+// MigrationLastStepWaiting is stored as last step for a task that starts at the first step
+const MigrationLastStepWaiting = "waiting"
+
+// Done returns the last step value stored for a task once this step has succeeded
+func (s MigrationStep) Done() string {
+	return string(s) + "_done"
+}
+
+// Failed returns the last step value stored for a task when this step has failed
+func (s MigrationStep) Failed() string {
+	return string(s) + "_failed"
+}
+
+// MigrationTask represents the move of a pipeline with all its builds, releases and logs to another repository
+type MigrationTask struct {
+	ID            string          `json:"id"`
+	Status        MigrationStatus `json:"status"`
+	LastStep      string          `json:"lastStep"`
+	Builds        int             `json:"builds,omitempty"`
+	Releases      int             `json:"releases,omitempty"`
+	TotalDuration time.Duration   `json:"totalDuration,omitempty"`
+	FromSource    string          `json:"fromSource"`
+	FromOwner     string          `json:"fromOwner"`
+	FromName      string          `json:"fromName"`
+	ToSource      string          `json:"toSource"`
+	ToOwner       string          `json:"toOwner"`
+	ToName        string          `json:"toName"`
+	CallbackURL   string          `json:"callbackURL,omitempty"`
+	ErrorDetails  string          `json:"errorDetails,omitempty"`
+	QueuedAt      time.Time       `json:"queuedAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
+
+// MigrationRollback holds the number of migrated records removed when rolling back a migration task
+type MigrationRollback struct {
+	Releases      int `json:"releases"`
+	ReleaseLogs   int `json:"releaseLogs"`
+	Builds        int `json:"builds"`
+	BuildLogs     int `json:"buildLogs"`
+	BuildVersions int `json:"buildVersions"`
+}
+
+// LogReconciliationStatus is the state of a log reconciliation
+type LogReconciliationStatus string
+
+const (
+	LogReconciliationStatusQueued     LogReconciliationStatus = "queued"
+	LogReconciliationStatusInProgress LogReconciliationStatus = "in_progress"
+	LogReconciliationStatusFailed     LogReconciliationStatus = "failed"
+	LogReconciliationStatusCompleted  LogReconciliationStatus = "completed"
+)
+
+// LogReconciliation represents a run comparing the log records in the database with the log files in cloud storage, copying missing log files where possible
+type LogReconciliation struct {
+	ID            string                  `json:"id"`
+	Status        LogReconciliationStatus `json:"status"`
+	DryRun        bool                    `json:"dryRun"`
+	RepoSource    string                  `json:"repoSource,omitempty"`
+	RepoOwner     string                  `json:"repoOwner,omitempty"`
+	RepoName      string                  `json:"repoName,omitempty"`
+	Pipelines     int                     `json:"pipelines"`
+	Checked       int                     `json:"checked"`
+	Copied        int                     `json:"copied"`
+	Unrecoverable int                     `json:"unrecoverable"`
+	Orphaned      int                     `json:"orphaned"`
+	ErrorDetails  string                  `json:"errorDetails,omitempty"`
+	QueuedBy      string                  `json:"queuedBy,omitempty"`
+	QueuedAt      time.Time               `json:"queuedAt"`
+	StartedAt     *time.Time              `json:"startedAt,omitempty"`
+	CompletedAt   *time.Time              `json:"completedAt,omitempty"`
+	UpdatedAt     time.Time               `json:"updatedAt"`
+}
+
+// NotificationDeliveryStatus is the state of delivering an event to a single notification channel
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryStatusPending    NotificationDeliveryStatus = "pending"
+	NotificationDeliveryStatusInProgress NotificationDeliveryStatus = "in_progress"
+	NotificationDeliveryStatusSucceeded  NotificationDeliveryStatus = "succeeded"
+	NotificationDeliveryStatusFailed     NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery records the delivery of an event to a notification channel, including its retries
+type NotificationDelivery struct {
+	ID            string                     `json:"id"`
+	Channel       string                     `json:"channel"`
+	ChannelType   string                     `json:"channelType"`
+	EventType     string                     `json:"eventType"`
+	RepoSource    string                     `json:"repoSource,omitempty"`
+	RepoOwner     string                     `json:"repoOwner,omitempty"`
+	RepoName      string                     `json:"repoName,omitempty"`
+	SourceID      string                     `json:"sourceID,omitempty"`
+	Payload       json.RawMessage            `json:"payload"`
+	Status        NotificationDeliveryStatus `json:"status"`
+	Attempts      int                        `json:"attempts"`
+	ErrorDetails  string                     `json:"errorDetails,omitempty"`
+	NextAttemptAt time.Time                  `json:"nextAttemptAt"`
+	DeliveredAt   *time.Time                 `json:"deliveredAt,omitempty"`
+	InsertedAt    time.Time                  `json:"insertedAt"`
+	UpdatedAt     time.Time                  `json:"updatedAt"`
+}
+
+// LogRecord is the database record of a single build, release or bot log; the log lines themselves are stored in cloud storage under its id
+type LogRecord struct {
+	ID         string
+	JobID      string
+	InsertedAt time.Time
+}
+
+// AuditLogRecord records a change made through the api or at login, by whom, and what the target looked like before and after
+type AuditLogRecord struct {
+	ID           string      `json:"id"`
+	Actor        string      `json:"actor,omitempty"`
+	ClientID     string      `json:"clientID,omitempty"`
+	Impersonator string      `json:"impersonator,omitempty"`
+	Action       string      `json:"action"`
+	TargetType   string      `json:"targetType"`
+	TargetID     string      `json:"targetID,omitempty"`
+	Before       interface{} `json:"before,omitempty"`
+	After        interface{} `json:"after,omitempty"`
+	RequestID    string      `json:"requestID,omitempty"`
+	InsertedAt   time.Time   `json:"insertedAt"`
+}
@@ -1016,3 +1016,501 @@ func (c *loggingClient) GetPipelineBotNamesCount(ctx context.Context, repoSource
 
 	return c.Client.GetPipelineBotNamesCount(ctx, repoSource, repoOwner, repoName, filters)
 }
+
+func (c *loggingClient) InsertQueuedJob(ctx context.Context, queuedJob QueuedJob) (qj *QueuedJob, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertQueuedJob", err) }()
+
+	return c.Client.InsertQueuedJob(ctx, queuedJob)
+}
+
+func (c *loggingClient) GetQueuedJobs(ctx context.Context, limit int) (queuedJobs []*QueuedJob, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetQueuedJobs", err) }()
+
+	return c.Client.GetQueuedJobs(ctx, limit)
+}
+
+func (c *loggingClient) DeleteQueuedJob(ctx context.Context, jobType contracts.JobType, jobID string) (deleted bool, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "DeleteQueuedJob", err) }()
+
+	return c.Client.DeleteQueuedJob(ctx, jobType, jobID)
+}
+
+func (c *loggingClient) GetActiveJobs(ctx context.Context) (activeJobs []*ActiveJob, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetActiveJobs", err) }()
+
+	return c.Client.GetActiveJobs(ctx)
+}
+
+func (c *loggingClient) UpdateBuildStatusReason(ctx context.Context, repoSource, repoOwner, repoName string, buildID string, statusReason string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdateBuildStatusReason", err) }()
+
+	return c.Client.UpdateBuildStatusReason(ctx, repoSource, repoOwner, repoName, buildID, statusReason)
+}
+
+func (c *loggingClient) GetBuildStatusReasons(ctx context.Context, buildIDs []string) (statusReasons map[string]string, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetBuildStatusReasons", err) }()
+
+	return c.Client.GetBuildStatusReasons(ctx, buildIDs)
+}
+
+func (c *loggingClient) InsertWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (wd *WebhookDelivery, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertWebhookDelivery", err) }()
+
+	return c.Client.InsertWebhookDelivery(ctx, delivery)
+}
+
+func (c *loggingClient) UpdateWebhookDeliveryOutcome(ctx context.Context, id, outcome string, statusCode int, errorMessage string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdateWebhookDeliveryOutcome", err) }()
+
+	return c.Client.UpdateWebhookDeliveryOutcome(ctx, id, outcome, statusCode, errorMessage)
+}
+
+func (c *loggingClient) GetWebhookDeliveryByID(ctx context.Context, id string) (delivery *WebhookDelivery, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetWebhookDeliveryByID", err) }()
+
+	return c.Client.GetWebhookDeliveryByID(ctx, id)
+}
+
+func (c *loggingClient) GetWebhookDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (deliveries []*WebhookDelivery, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetWebhookDeliveries", err) }()
+
+	return c.Client.GetWebhookDeliveries(ctx, pageNumber, pageSize, filters, sortings)
+}
+
+func (c *loggingClient) GetWebhookDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetWebhookDeliveriesCount", err) }()
+
+	return c.Client.GetWebhookDeliveriesCount(ctx, filters)
+}
+
+func (c *loggingClient) QueueMigrationTask(ctx context.Context, task MigrationTask) (queuedTask *MigrationTask, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "QueueMigrationTask", err) }()
+
+	return c.Client.QueueMigrationTask(ctx, task)
+}
+
+func (c *loggingClient) PickMigrationTasks(ctx context.Context, maxTasks int) (tasks []*MigrationTask, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "PickMigrationTasks", err) }()
+
+	return c.Client.PickMigrationTasks(ctx, maxTasks)
+}
+
+func (c *loggingClient) UpdateMigrationTask(ctx context.Context, id string, status MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdateMigrationTask", err) }()
+
+	return c.Client.UpdateMigrationTask(ctx, id, status, lastStep, builds, releases, duration, errorDetails)
+}
+
+func (c *loggingClient) GetMigrationTaskByID(ctx context.Context, id string) (task *MigrationTask, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetMigrationTaskByID", err) }()
+
+	return c.Client.GetMigrationTaskByID(ctx, id)
+}
+
+func (c *loggingClient) GetMigrationTaskByFromRepo(ctx context.Context, fromSource, fromOwner, fromName string) (task *MigrationTask, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetMigrationTaskByFromRepo", err) }()
+
+	return c.Client.GetMigrationTaskByFromRepo(ctx, fromSource, fromOwner, fromName)
+}
+
+func (c *loggingClient) GetMigrationTasks(ctx context.Context) (tasks []*MigrationTask, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetMigrationTasks", err) }()
+
+	return c.Client.GetMigrationTasks(ctx)
+}
+
+func (c *loggingClient) MigrateReleases(ctx context.Context, task MigrationTask) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "MigrateReleases", err) }()
+
+	return c.Client.MigrateReleases(ctx, task)
+}
+
+func (c *loggingClient) MigrateReleaseLogs(ctx context.Context, task MigrationTask) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "MigrateReleaseLogs", err) }()
+
+	return c.Client.MigrateReleaseLogs(ctx, task)
+}
+
+func (c *loggingClient) GetMigratedReleaseLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetMigratedReleaseLogIDs", err) }()
+
+	return c.Client.GetMigratedReleaseLogIDs(ctx, task)
+}
+
+func (c *loggingClient) MigrateBuilds(ctx context.Context, task MigrationTask) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "MigrateBuilds", err) }()
+
+	return c.Client.MigrateBuilds(ctx, task)
+}
+
+func (c *loggingClient) MigrateBuildLogs(ctx context.Context, task MigrationTask) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "MigrateBuildLogs", err) }()
+
+	return c.Client.MigrateBuildLogs(ctx, task)
+}
+
+func (c *loggingClient) GetMigratedBuildLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetMigratedBuildLogIDs", err) }()
+
+	return c.Client.GetMigratedBuildLogIDs(ctx, task)
+}
+
+func (c *loggingClient) MigrateBuildVersions(ctx context.Context, task MigrationTask, shortFromRepoSource, shortToRepoSource string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "MigrateBuildVersions", err) }()
+
+	return c.Client.MigrateBuildVersions(ctx, task, shortFromRepoSource, shortToRepoSource)
+}
+
+func (c *loggingClient) MigrateComputedTables(ctx context.Context, task MigrationTask) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "MigrateComputedTables", err) }()
+
+	return c.Client.MigrateComputedTables(ctx, task)
+}
+
+func (c *loggingClient) RollbackMigrationStep(ctx context.Context, task MigrationTask, step MigrationStep, shortToRepoSource string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "RollbackMigrationStep", err) }()
+
+	return c.Client.RollbackMigrationStep(ctx, task, step, shortToRepoSource)
+}
+
+func (c *loggingClient) RollbackMigration(ctx context.Context, task MigrationTask, shortToRepoSource string) (rollback *MigrationRollback, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "RollbackMigration", err) }()
+
+	return c.Client.RollbackMigration(ctx, task, shortToRepoSource)
+}
+
+func (c *loggingClient) GetLatestSuccessfulReleaseLogIDs(ctx context.Context, repoSource, repoOwner, repoName string) (logIDs []string, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetLatestSuccessfulReleaseLogIDs", err) }()
+
+	return c.Client.GetLatestSuccessfulReleaseLogIDs(ctx, repoSource, repoOwner, repoName)
+}
+
+func (c *loggingClient) GetExpiredLogIDs(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string, limit int) (logIDs []string, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetExpiredLogIDs", err) }()
+
+	return c.Client.GetExpiredLogIDs(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs, limit)
+}
+
+func (c *loggingClient) GetExpiredLogsCount(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetExpiredLogsCount", err) }()
+
+	return c.Client.GetExpiredLogsCount(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs)
+}
+
+func (c *loggingClient) DeleteLogsByID(ctx context.Context, jobType contracts.JobType, logIDs []string) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "DeleteLogsByID", err) }()
+
+	return c.Client.DeleteLogsByID(ctx, jobType, logIDs)
+}
+
+func (c *loggingClient) QueueLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (queuedReconciliation *LogReconciliation, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "QueueLogReconciliation", err) }()
+
+	return c.Client.QueueLogReconciliation(ctx, reconciliation)
+}
+
+func (c *loggingClient) PickLogReconciliation(ctx context.Context, staleBefore time.Time) (reconciliation *LogReconciliation, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "PickLogReconciliation", err) }()
+
+	return c.Client.PickLogReconciliation(ctx, staleBefore)
+}
+
+func (c *loggingClient) UpdateLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdateLogReconciliation", err) }()
+
+	return c.Client.UpdateLogReconciliation(ctx, reconciliation)
+}
+
+func (c *loggingClient) GetLogReconciliationByID(ctx context.Context, id string) (reconciliation *LogReconciliation, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetLogReconciliationByID", err) }()
+
+	return c.Client.GetLogReconciliationByID(ctx, id)
+}
+
+func (c *loggingClient) GetActiveLogReconciliation(ctx context.Context) (reconciliation *LogReconciliation, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetActiveLogReconciliation", err) }()
+
+	return c.Client.GetActiveLogReconciliation(ctx)
+}
+
+func (c *loggingClient) GetLogReconciliations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (reconciliations []*LogReconciliation, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetLogReconciliations", err) }()
+
+	return c.Client.GetLogReconciliations(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *loggingClient) GetLogReconciliationsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetLogReconciliationsCount", err) }()
+
+	return c.Client.GetLogReconciliationsCount(ctx, filters)
+}
+
+func (c *loggingClient) GetLogRecords(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string) (records []*LogRecord, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetLogRecords", err) }()
+
+	return c.Client.GetLogRecords(ctx, jobType, repoSource, repoOwner, repoName)
+}
+
+func (c *loggingClient) GetGroupByName(ctx context.Context, name string) (group *contracts.Group, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetGroupByName", err) }()
+
+	return c.Client.GetGroupByName(ctx, name)
+}
+
+func (c *loggingClient) InsertAuditLogRecord(ctx context.Context, record AuditLogRecord) (insertedRecord *AuditLogRecord, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertAuditLogRecord", err) }()
+
+	return c.Client.InsertAuditLogRecord(ctx, record)
+}
+
+func (c *loggingClient) InsertCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertCustomRole", err) }()
+
+	return c.Client.InsertCustomRole(ctx, customRole)
+}
+
+func (c *loggingClient) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdateCustomRole", err) }()
+
+	return c.Client.UpdateCustomRole(ctx, customRole)
+}
+
+func (c *loggingClient) DeleteCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "DeleteCustomRole", err) }()
+
+	return c.Client.DeleteCustomRole(ctx, customRole)
+}
+
+func (c *loggingClient) GetCustomRoleByID(ctx context.Context, id string) (customRole *api.CustomRole, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetCustomRoleByID", err) }()
+
+	return c.Client.GetCustomRoleByID(ctx, id)
+}
+
+func (c *loggingClient) GetCustomRoles(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (customRoles []*api.CustomRole, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetCustomRoles", err) }()
+
+	return c.Client.GetCustomRoles(ctx, pageNumber, pageSize, filters, sortings)
+}
+
+func (c *loggingClient) GetCustomRolesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetCustomRolesCount", err) }()
+
+	return c.Client.GetCustomRolesCount(ctx, filters)
+}
+
+func (c *loggingClient) GetAuditLogRecords(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (records []*AuditLogRecord, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetAuditLogRecords", err) }()
+
+	return c.Client.GetAuditLogRecords(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *loggingClient) GetAuditLogRecordsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetAuditLogRecordsCount", err) }()
+
+	return c.Client.GetAuditLogRecordsCount(ctx, filters)
+}
+
+func (c *loggingClient) InsertPersonalAccessToken(ctx context.Context, token api.PersonalAccessToken, tokenHash string) (insertedToken *api.PersonalAccessToken, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertPersonalAccessToken", err) }()
+
+	return c.Client.InsertPersonalAccessToken(ctx, token, tokenHash)
+}
+
+func (c *loggingClient) UpdatePersonalAccessTokenLastUsed(ctx context.Context, id string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdatePersonalAccessTokenLastUsed", err) }()
+
+	return c.Client.UpdatePersonalAccessTokenLastUsed(ctx, id)
+}
+
+func (c *loggingClient) DeletePersonalAccessToken(ctx context.Context, token api.PersonalAccessToken) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "DeletePersonalAccessToken", err) }()
+
+	return c.Client.DeletePersonalAccessToken(ctx, token)
+}
+
+func (c *loggingClient) GetPersonalAccessTokenByID(ctx context.Context, id string) (token *api.PersonalAccessToken, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPersonalAccessTokenByID", err) }()
+
+	return c.Client.GetPersonalAccessTokenByID(ctx, id)
+}
+
+func (c *loggingClient) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (token *api.PersonalAccessToken, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPersonalAccessTokenByHash", err) }()
+
+	return c.Client.GetPersonalAccessTokenByHash(ctx, tokenHash)
+}
+
+func (c *loggingClient) GetPersonalAccessTokensForUser(ctx context.Context, userID string) (tokens []*api.PersonalAccessToken, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPersonalAccessTokensForUser", err) }()
+
+	return c.Client.GetPersonalAccessTokensForUser(ctx, userID)
+}
+
+func (c *loggingClient) InsertSession(ctx context.Context, session api.Session) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertSession", err) }()
+
+	return c.Client.InsertSession(ctx, session)
+}
+
+func (c *loggingClient) GetSessionByID(ctx context.Context, id string) (session *api.Session, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetSessionByID", err) }()
+
+	return c.Client.GetSessionByID(ctx, id)
+}
+
+func (c *loggingClient) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetActiveSessionsForSubject", err) }()
+
+	return c.Client.GetActiveSessionsForSubject(ctx, subjectID)
+}
+
+func (c *loggingClient) RevokeSession(ctx context.Context, id string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "RevokeSession", err) }()
+
+	return c.Client.RevokeSession(ctx, id)
+}
+
+func (c *loggingClient) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "RevokeSessionsForSubject", err) }()
+
+	return c.Client.RevokeSessionsForSubject(ctx, subjectID)
+}
+
+func (c *loggingClient) InsertClientSecret(ctx context.Context, secret api.ClientSecret, secretHash string) (insertedSecret *api.ClientSecret, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertClientSecret", err) }()
+
+	return c.Client.InsertClientSecret(ctx, secret, secretHash)
+}
+
+func (c *loggingClient) UpdateClientSecretLastUsed(ctx context.Context, id string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdateClientSecretLastUsed", err) }()
+
+	return c.Client.UpdateClientSecretLastUsed(ctx, id)
+}
+
+func (c *loggingClient) DeleteClientSecret(ctx context.Context, secret api.ClientSecret) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "DeleteClientSecret", err) }()
+
+	return c.Client.DeleteClientSecret(ctx, secret)
+}
+
+func (c *loggingClient) GetClientSecretByHash(ctx context.Context, clientID, secretHash string) (secret *api.ClientSecret, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetClientSecretByHash", err) }()
+
+	return c.Client.GetClientSecretByHash(ctx, clientID, secretHash)
+}
+
+func (c *loggingClient) GetClientSecretsForClient(ctx context.Context, clientID string) (secrets []*api.ClientSecret, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetClientSecretsForClient", err) }()
+
+	return c.Client.GetClientSecretsForClient(ctx, clientID)
+}
+
+func (c *loggingClient) InsertNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (insertedDelivery *NotificationDelivery, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "InsertNotificationDelivery", err) }()
+
+	return c.Client.InsertNotificationDelivery(ctx, delivery)
+}
+
+func (c *loggingClient) PickNotificationDeliveries(ctx context.Context, limit int, staleBefore time.Time) (deliveries []*NotificationDelivery, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "PickNotificationDeliveries", err) }()
+
+	return c.Client.PickNotificationDeliveries(ctx, limit, staleBefore)
+}
+
+func (c *loggingClient) UpdateNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "UpdateNotificationDelivery", err) }()
+
+	return c.Client.UpdateNotificationDelivery(ctx, delivery)
+}
+
+func (c *loggingClient) GetNotificationDeliveryByID(ctx context.Context, id string) (delivery *NotificationDelivery, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetNotificationDeliveryByID", err) }()
+
+	return c.Client.GetNotificationDeliveryByID(ctx, id)
+}
+
+func (c *loggingClient) GetNotificationDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (deliveries []*NotificationDelivery, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetNotificationDeliveries", err) }()
+
+	return c.Client.GetNotificationDeliveries(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *loggingClient) GetNotificationDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetNotificationDeliveriesCount", err) }()
+
+	return c.Client.GetNotificationDeliveriesCount(ctx, filters)
+}
+
+func (c *loggingClient) GetUserByEmail(ctx context.Context, email string) (user *contracts.User, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetUserByEmail", err) }()
+
+	return c.Client.GetUserByEmail(ctx, email)
+}
+
+func (c *loggingClient) GetPipelineBuildResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, repoBranch string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPipelineBuildResourceUsages", err) }()
+
+	return c.Client.GetPipelineBuildResourceUsages(ctx, repoSource, repoOwner, repoName, repoBranch, lastNRecords)
+}
+
+func (c *loggingClient) GetPipelineReleaseResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, targetName, actionName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPipelineReleaseResourceUsages", err) }()
+
+	return c.Client.GetPipelineReleaseResourceUsages(ctx, repoSource, repoOwner, repoName, targetName, actionName, lastNRecords)
+}
+
+func (c *loggingClient) GetPipelineBotResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, botName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetPipelineBotResourceUsages", err) }()
+
+	return c.Client.GetPipelineBotResourceUsages(ctx, repoSource, repoOwner, repoName, botName, lastNRecords)
+}
+
+func (c *loggingClient) GetJobResourceUtilizations(ctx context.Context, jobType contracts.JobType, from, to time.Time) (utilizations []*JobResourceUtilization, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetJobResourceUtilizations", err) }()
+
+	return c.Client.GetJobResourceUtilizations(ctx, jobType, from, to)
+}
+
+func (c *loggingClient) GetReleaseOutcomes(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (outcomes []*ReleaseOutcome, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetReleaseOutcomes", err) }()
+
+	return c.Client.GetReleaseOutcomes(ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
+
+func (c *loggingClient) ClaimQueuedJob(ctx context.Context, queuedJob QueuedJob) (claimed bool, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "ClaimQueuedJob", err) }()
+
+	return c.Client.ClaimQueuedJob(ctx, queuedJob)
+}
+
+func (c *loggingClient) RequeueJob(ctx context.Context, queuedJob QueuedJob) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "RequeueJob", err) }()
+
+	return c.Client.RequeueJob(ctx, queuedJob)
+}
+
+func (c *loggingClient) AcquireLock(ctx context.Context, name, holder string, ttl time.Duration) (acquired bool, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "AcquireLock", err) }()
+
+	return c.Client.AcquireLock(ctx, name, holder, ttl)
+}
+
+func (c *loggingClient) ReleaseLock(ctx context.Context, name, holder string) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "ReleaseLock", err) }()
+
+	return c.Client.ReleaseLock(ctx, name, holder)
+}
+
+func (c *loggingClient) GetJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string) (jobResources JobResources, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetJobResources", err) }()
+
+	return c.Client.GetJobResources(ctx, jobType, repoSource, repoOwner, repoName, jobID)
+}
+
+func (c *loggingClient) HashLegacyClientSecret(ctx context.Context, client contracts.Client, secretHash string) (hashed bool, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "HashLegacyClientSecret", err) }()
+
+	return c.Client.HashLegacyClientSecret(ctx, client, secretHash)
+}
@@ -1332,3 +1332,667 @@ func (c *metricsClient) GetPipelineBotNamesCount(ctx context.Context, repoSource
 
 	return c.Client.GetPipelineBotNamesCount(ctx, repoSource, repoOwner, repoName, filters)
 }
+
+func (c *metricsClient) InsertQueuedJob(ctx context.Context, queuedJob QueuedJob) (qj *QueuedJob, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertQueuedJob", begin)
+	}(time.Now())
+
+	return c.Client.InsertQueuedJob(ctx, queuedJob)
+}
+
+func (c *metricsClient) GetQueuedJobs(ctx context.Context, limit int) (queuedJobs []*QueuedJob, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetQueuedJobs", begin)
+	}(time.Now())
+
+	return c.Client.GetQueuedJobs(ctx, limit)
+}
+
+func (c *metricsClient) DeleteQueuedJob(ctx context.Context, jobType contracts.JobType, jobID string) (deleted bool, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "DeleteQueuedJob", begin)
+	}(time.Now())
+
+	return c.Client.DeleteQueuedJob(ctx, jobType, jobID)
+}
+
+func (c *metricsClient) GetActiveJobs(ctx context.Context) (activeJobs []*ActiveJob, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetActiveJobs", begin)
+	}(time.Now())
+
+	return c.Client.GetActiveJobs(ctx)
+}
+
+func (c *metricsClient) UpdateBuildStatusReason(ctx context.Context, repoSource, repoOwner, repoName string, buildID string, statusReason string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdateBuildStatusReason", begin)
+	}(time.Now())
+
+	return c.Client.UpdateBuildStatusReason(ctx, repoSource, repoOwner, repoName, buildID, statusReason)
+}
+
+func (c *metricsClient) GetBuildStatusReasons(ctx context.Context, buildIDs []string) (statusReasons map[string]string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetBuildStatusReasons", begin)
+	}(time.Now())
+
+	return c.Client.GetBuildStatusReasons(ctx, buildIDs)
+}
+
+func (c *metricsClient) InsertWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (wd *WebhookDelivery, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertWebhookDelivery", begin)
+	}(time.Now())
+
+	return c.Client.InsertWebhookDelivery(ctx, delivery)
+}
+
+func (c *metricsClient) UpdateWebhookDeliveryOutcome(ctx context.Context, id, outcome string, statusCode int, errorMessage string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdateWebhookDeliveryOutcome", begin)
+	}(time.Now())
+
+	return c.Client.UpdateWebhookDeliveryOutcome(ctx, id, outcome, statusCode, errorMessage)
+}
+
+func (c *metricsClient) GetWebhookDeliveryByID(ctx context.Context, id string) (delivery *WebhookDelivery, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetWebhookDeliveryByID", begin)
+	}(time.Now())
+
+	return c.Client.GetWebhookDeliveryByID(ctx, id)
+}
+
+func (c *metricsClient) GetWebhookDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (deliveries []*WebhookDelivery, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetWebhookDeliveries", begin)
+	}(time.Now())
+
+	return c.Client.GetWebhookDeliveries(ctx, pageNumber, pageSize, filters, sortings)
+}
+
+func (c *metricsClient) GetWebhookDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetWebhookDeliveriesCount", begin)
+	}(time.Now())
+
+	return c.Client.GetWebhookDeliveriesCount(ctx, filters)
+}
+
+func (c *metricsClient) QueueMigrationTask(ctx context.Context, task MigrationTask) (queuedTask *MigrationTask, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "QueueMigrationTask", begin)
+	}(time.Now())
+
+	return c.Client.QueueMigrationTask(ctx, task)
+}
+
+func (c *metricsClient) PickMigrationTasks(ctx context.Context, maxTasks int) (tasks []*MigrationTask, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "PickMigrationTasks", begin)
+	}(time.Now())
+
+	return c.Client.PickMigrationTasks(ctx, maxTasks)
+}
+
+func (c *metricsClient) UpdateMigrationTask(ctx context.Context, id string, status MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdateMigrationTask", begin)
+	}(time.Now())
+
+	return c.Client.UpdateMigrationTask(ctx, id, status, lastStep, builds, releases, duration, errorDetails)
+}
+
+func (c *metricsClient) GetMigrationTaskByID(ctx context.Context, id string) (task *MigrationTask, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetMigrationTaskByID", begin)
+	}(time.Now())
+
+	return c.Client.GetMigrationTaskByID(ctx, id)
+}
+
+func (c *metricsClient) GetMigrationTaskByFromRepo(ctx context.Context, fromSource, fromOwner, fromName string) (task *MigrationTask, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetMigrationTaskByFromRepo", begin)
+	}(time.Now())
+
+	return c.Client.GetMigrationTaskByFromRepo(ctx, fromSource, fromOwner, fromName)
+}
+
+func (c *metricsClient) GetMigrationTasks(ctx context.Context) (tasks []*MigrationTask, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetMigrationTasks", begin)
+	}(time.Now())
+
+	return c.Client.GetMigrationTasks(ctx)
+}
+
+func (c *metricsClient) MigrateReleases(ctx context.Context, task MigrationTask) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "MigrateReleases", begin)
+	}(time.Now())
+
+	return c.Client.MigrateReleases(ctx, task)
+}
+
+func (c *metricsClient) MigrateReleaseLogs(ctx context.Context, task MigrationTask) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "MigrateReleaseLogs", begin)
+	}(time.Now())
+
+	return c.Client.MigrateReleaseLogs(ctx, task)
+}
+
+func (c *metricsClient) GetMigratedReleaseLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetMigratedReleaseLogIDs", begin)
+	}(time.Now())
+
+	return c.Client.GetMigratedReleaseLogIDs(ctx, task)
+}
+
+func (c *metricsClient) MigrateBuilds(ctx context.Context, task MigrationTask) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "MigrateBuilds", begin)
+	}(time.Now())
+
+	return c.Client.MigrateBuilds(ctx, task)
+}
+
+func (c *metricsClient) MigrateBuildLogs(ctx context.Context, task MigrationTask) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "MigrateBuildLogs", begin)
+	}(time.Now())
+
+	return c.Client.MigrateBuildLogs(ctx, task)
+}
+
+func (c *metricsClient) GetMigratedBuildLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetMigratedBuildLogIDs", begin)
+	}(time.Now())
+
+	return c.Client.GetMigratedBuildLogIDs(ctx, task)
+}
+
+func (c *metricsClient) MigrateBuildVersions(ctx context.Context, task MigrationTask, shortFromRepoSource, shortToRepoSource string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "MigrateBuildVersions", begin)
+	}(time.Now())
+
+	return c.Client.MigrateBuildVersions(ctx, task, shortFromRepoSource, shortToRepoSource)
+}
+
+func (c *metricsClient) MigrateComputedTables(ctx context.Context, task MigrationTask) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "MigrateComputedTables", begin)
+	}(time.Now())
+
+	return c.Client.MigrateComputedTables(ctx, task)
+}
+
+func (c *metricsClient) RollbackMigrationStep(ctx context.Context, task MigrationTask, step MigrationStep, shortToRepoSource string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "RollbackMigrationStep", begin)
+	}(time.Now())
+
+	return c.Client.RollbackMigrationStep(ctx, task, step, shortToRepoSource)
+}
+
+func (c *metricsClient) RollbackMigration(ctx context.Context, task MigrationTask, shortToRepoSource string) (rollback *MigrationRollback, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "RollbackMigration", begin)
+	}(time.Now())
+
+	return c.Client.RollbackMigration(ctx, task, shortToRepoSource)
+}
+
+func (c *metricsClient) GetLatestSuccessfulReleaseLogIDs(ctx context.Context, repoSource, repoOwner, repoName string) (logIDs []string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetLatestSuccessfulReleaseLogIDs", begin)
+	}(time.Now())
+
+	return c.Client.GetLatestSuccessfulReleaseLogIDs(ctx, repoSource, repoOwner, repoName)
+}
+
+func (c *metricsClient) GetExpiredLogIDs(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string, limit int) (logIDs []string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetExpiredLogIDs", begin)
+	}(time.Now())
+
+	return c.Client.GetExpiredLogIDs(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs, limit)
+}
+
+func (c *metricsClient) GetExpiredLogsCount(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetExpiredLogsCount", begin)
+	}(time.Now())
+
+	return c.Client.GetExpiredLogsCount(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs)
+}
+
+func (c *metricsClient) DeleteLogsByID(ctx context.Context, jobType contracts.JobType, logIDs []string) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "DeleteLogsByID", begin)
+	}(time.Now())
+
+	return c.Client.DeleteLogsByID(ctx, jobType, logIDs)
+}
+
+func (c *metricsClient) QueueLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (queuedReconciliation *LogReconciliation, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "QueueLogReconciliation", begin)
+	}(time.Now())
+
+	return c.Client.QueueLogReconciliation(ctx, reconciliation)
+}
+
+func (c *metricsClient) PickLogReconciliation(ctx context.Context, staleBefore time.Time) (reconciliation *LogReconciliation, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "PickLogReconciliation", begin)
+	}(time.Now())
+
+	return c.Client.PickLogReconciliation(ctx, staleBefore)
+}
+
+func (c *metricsClient) UpdateLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdateLogReconciliation", begin)
+	}(time.Now())
+
+	return c.Client.UpdateLogReconciliation(ctx, reconciliation)
+}
+
+func (c *metricsClient) GetLogReconciliationByID(ctx context.Context, id string) (reconciliation *LogReconciliation, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetLogReconciliationByID", begin)
+	}(time.Now())
+
+	return c.Client.GetLogReconciliationByID(ctx, id)
+}
+
+func (c *metricsClient) GetActiveLogReconciliation(ctx context.Context) (reconciliation *LogReconciliation, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetActiveLogReconciliation", begin)
+	}(time.Now())
+
+	return c.Client.GetActiveLogReconciliation(ctx)
+}
+
+func (c *metricsClient) GetLogReconciliations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (reconciliations []*LogReconciliation, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetLogReconciliations", begin)
+	}(time.Now())
+
+	return c.Client.GetLogReconciliations(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *metricsClient) GetLogReconciliationsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetLogReconciliationsCount", begin)
+	}(time.Now())
+
+	return c.Client.GetLogReconciliationsCount(ctx, filters)
+}
+
+func (c *metricsClient) GetLogRecords(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string) (records []*LogRecord, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetLogRecords", begin)
+	}(time.Now())
+
+	return c.Client.GetLogRecords(ctx, jobType, repoSource, repoOwner, repoName)
+}
+
+func (c *metricsClient) GetGroupByName(ctx context.Context, name string) (group *contracts.Group, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetGroupByName", begin)
+	}(time.Now())
+
+	return c.Client.GetGroupByName(ctx, name)
+}
+
+func (c *metricsClient) InsertAuditLogRecord(ctx context.Context, record AuditLogRecord) (insertedRecord *AuditLogRecord, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertAuditLogRecord", begin)
+	}(time.Now())
+
+	return c.Client.InsertAuditLogRecord(ctx, record)
+}
+
+func (c *metricsClient) InsertCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertCustomRole", begin)
+	}(time.Now())
+
+	return c.Client.InsertCustomRole(ctx, customRole)
+}
+
+func (c *metricsClient) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdateCustomRole", begin)
+	}(time.Now())
+
+	return c.Client.UpdateCustomRole(ctx, customRole)
+}
+
+func (c *metricsClient) DeleteCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "DeleteCustomRole", begin)
+	}(time.Now())
+
+	return c.Client.DeleteCustomRole(ctx, customRole)
+}
+
+func (c *metricsClient) GetCustomRoleByID(ctx context.Context, id string) (customRole *api.CustomRole, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetCustomRoleByID", begin)
+	}(time.Now())
+
+	return c.Client.GetCustomRoleByID(ctx, id)
+}
+
+func (c *metricsClient) GetCustomRoles(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (customRoles []*api.CustomRole, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetCustomRoles", begin)
+	}(time.Now())
+
+	return c.Client.GetCustomRoles(ctx, pageNumber, pageSize, filters, sortings)
+}
+
+func (c *metricsClient) GetCustomRolesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetCustomRolesCount", begin)
+	}(time.Now())
+
+	return c.Client.GetCustomRolesCount(ctx, filters)
+}
+
+func (c *metricsClient) GetAuditLogRecords(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (records []*AuditLogRecord, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetAuditLogRecords", begin)
+	}(time.Now())
+
+	return c.Client.GetAuditLogRecords(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *metricsClient) GetAuditLogRecordsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetAuditLogRecordsCount", begin)
+	}(time.Now())
+
+	return c.Client.GetAuditLogRecordsCount(ctx, filters)
+}
+
+func (c *metricsClient) InsertPersonalAccessToken(ctx context.Context, token api.PersonalAccessToken, tokenHash string) (insertedToken *api.PersonalAccessToken, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertPersonalAccessToken", begin)
+	}(time.Now())
+
+	return c.Client.InsertPersonalAccessToken(ctx, token, tokenHash)
+}
+
+func (c *metricsClient) UpdatePersonalAccessTokenLastUsed(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdatePersonalAccessTokenLastUsed", begin)
+	}(time.Now())
+
+	return c.Client.UpdatePersonalAccessTokenLastUsed(ctx, id)
+}
+
+func (c *metricsClient) DeletePersonalAccessToken(ctx context.Context, token api.PersonalAccessToken) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "DeletePersonalAccessToken", begin)
+	}(time.Now())
+
+	return c.Client.DeletePersonalAccessToken(ctx, token)
+}
+
+func (c *metricsClient) GetPersonalAccessTokenByID(ctx context.Context, id string) (token *api.PersonalAccessToken, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPersonalAccessTokenByID", begin)
+	}(time.Now())
+
+	return c.Client.GetPersonalAccessTokenByID(ctx, id)
+}
+
+func (c *metricsClient) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (token *api.PersonalAccessToken, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPersonalAccessTokenByHash", begin)
+	}(time.Now())
+
+	return c.Client.GetPersonalAccessTokenByHash(ctx, tokenHash)
+}
+
+func (c *metricsClient) GetPersonalAccessTokensForUser(ctx context.Context, userID string) (tokens []*api.PersonalAccessToken, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPersonalAccessTokensForUser", begin)
+	}(time.Now())
+
+	return c.Client.GetPersonalAccessTokensForUser(ctx, userID)
+}
+
+func (c *metricsClient) InsertSession(ctx context.Context, session api.Session) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertSession", begin)
+	}(time.Now())
+
+	return c.Client.InsertSession(ctx, session)
+}
+
+func (c *metricsClient) GetSessionByID(ctx context.Context, id string) (session *api.Session, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetSessionByID", begin)
+	}(time.Now())
+
+	return c.Client.GetSessionByID(ctx, id)
+}
+
+func (c *metricsClient) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetActiveSessionsForSubject", begin)
+	}(time.Now())
+
+	return c.Client.GetActiveSessionsForSubject(ctx, subjectID)
+}
+
+func (c *metricsClient) RevokeSession(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "RevokeSession", begin)
+	}(time.Now())
+
+	return c.Client.RevokeSession(ctx, id)
+}
+
+func (c *metricsClient) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "RevokeSessionsForSubject", begin)
+	}(time.Now())
+
+	return c.Client.RevokeSessionsForSubject(ctx, subjectID)
+}
+
+func (c *metricsClient) InsertClientSecret(ctx context.Context, secret api.ClientSecret, secretHash string) (insertedSecret *api.ClientSecret, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertClientSecret", begin)
+	}(time.Now())
+
+	return c.Client.InsertClientSecret(ctx, secret, secretHash)
+}
+
+func (c *metricsClient) UpdateClientSecretLastUsed(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdateClientSecretLastUsed", begin)
+	}(time.Now())
+
+	return c.Client.UpdateClientSecretLastUsed(ctx, id)
+}
+
+func (c *metricsClient) DeleteClientSecret(ctx context.Context, secret api.ClientSecret) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "DeleteClientSecret", begin)
+	}(time.Now())
+
+	return c.Client.DeleteClientSecret(ctx, secret)
+}
+
+func (c *metricsClient) GetClientSecretByHash(ctx context.Context, clientID, secretHash string) (secret *api.ClientSecret, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetClientSecretByHash", begin)
+	}(time.Now())
+
+	return c.Client.GetClientSecretByHash(ctx, clientID, secretHash)
+}
+
+func (c *metricsClient) GetClientSecretsForClient(ctx context.Context, clientID string) (secrets []*api.ClientSecret, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetClientSecretsForClient", begin)
+	}(time.Now())
+
+	return c.Client.GetClientSecretsForClient(ctx, clientID)
+}
+
+func (c *metricsClient) InsertNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (insertedDelivery *NotificationDelivery, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "InsertNotificationDelivery", begin)
+	}(time.Now())
+
+	return c.Client.InsertNotificationDelivery(ctx, delivery)
+}
+
+func (c *metricsClient) PickNotificationDeliveries(ctx context.Context, limit int, staleBefore time.Time) (deliveries []*NotificationDelivery, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "PickNotificationDeliveries", begin)
+	}(time.Now())
+
+	return c.Client.PickNotificationDeliveries(ctx, limit, staleBefore)
+}
+
+func (c *metricsClient) UpdateNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "UpdateNotificationDelivery", begin)
+	}(time.Now())
+
+	return c.Client.UpdateNotificationDelivery(ctx, delivery)
+}
+
+func (c *metricsClient) GetNotificationDeliveryByID(ctx context.Context, id string) (delivery *NotificationDelivery, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetNotificationDeliveryByID", begin)
+	}(time.Now())
+
+	return c.Client.GetNotificationDeliveryByID(ctx, id)
+}
+
+func (c *metricsClient) GetNotificationDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (deliveries []*NotificationDelivery, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetNotificationDeliveries", begin)
+	}(time.Now())
+
+	return c.Client.GetNotificationDeliveries(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *metricsClient) GetNotificationDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetNotificationDeliveriesCount", begin)
+	}(time.Now())
+
+	return c.Client.GetNotificationDeliveriesCount(ctx, filters)
+}
+
+func (c *metricsClient) GetUserByEmail(ctx context.Context, email string) (user *contracts.User, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetUserByEmail", begin)
+	}(time.Now())
+
+	return c.Client.GetUserByEmail(ctx, email)
+}
+
+func (c *metricsClient) GetPipelineBuildResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, repoBranch string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPipelineBuildResourceUsages", begin)
+	}(time.Now())
+
+	return c.Client.GetPipelineBuildResourceUsages(ctx, repoSource, repoOwner, repoName, repoBranch, lastNRecords)
+}
+
+func (c *metricsClient) GetPipelineReleaseResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, targetName, actionName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPipelineReleaseResourceUsages", begin)
+	}(time.Now())
+
+	return c.Client.GetPipelineReleaseResourceUsages(ctx, repoSource, repoOwner, repoName, targetName, actionName, lastNRecords)
+}
+
+func (c *metricsClient) GetPipelineBotResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, botName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetPipelineBotResourceUsages", begin)
+	}(time.Now())
+
+	return c.Client.GetPipelineBotResourceUsages(ctx, repoSource, repoOwner, repoName, botName, lastNRecords)
+}
+
+func (c *metricsClient) GetJobResourceUtilizations(ctx context.Context, jobType contracts.JobType, from, to time.Time) (utilizations []*JobResourceUtilization, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetJobResourceUtilizations", begin)
+	}(time.Now())
+
+	return c.Client.GetJobResourceUtilizations(ctx, jobType, from, to)
+}
+
+func (c *metricsClient) GetReleaseOutcomes(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (outcomes []*ReleaseOutcome, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetReleaseOutcomes", begin)
+	}(time.Now())
+
+	return c.Client.GetReleaseOutcomes(ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
+
+func (c *metricsClient) ClaimQueuedJob(ctx context.Context, queuedJob QueuedJob) (claimed bool, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "ClaimQueuedJob", begin)
+	}(time.Now())
+
+	return c.Client.ClaimQueuedJob(ctx, queuedJob)
+}
+
+func (c *metricsClient) RequeueJob(ctx context.Context, queuedJob QueuedJob) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "RequeueJob", begin)
+	}(time.Now())
+
+	return c.Client.RequeueJob(ctx, queuedJob)
+}
+
+func (c *metricsClient) AcquireLock(ctx context.Context, name, holder string, ttl time.Duration) (acquired bool, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "AcquireLock", begin)
+	}(time.Now())
+
+	return c.Client.AcquireLock(ctx, name, holder, ttl)
+}
+
+func (c *metricsClient) ReleaseLock(ctx context.Context, name, holder string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "ReleaseLock", begin)
+	}(time.Now())
+
+	return c.Client.ReleaseLock(ctx, name, holder)
+}
+
+func (c *metricsClient) GetJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string) (jobResources JobResources, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetJobResources", begin)
+	}(time.Now())
+
+	return c.Client.GetJobResources(ctx, jobType, repoSource, repoOwner, repoName, jobID)
+}
+
+func (c *metricsClient) HashLegacyClientSecret(ctx context.Context, client contracts.Client, secretHash string) (hashed bool, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "HashLegacyClientSecret", begin)
+	}(time.Now())
+
+	return c.Client.HashLegacyClientSecret(ctx, client, secretHash)
+}
@@ -38,6 +38,21 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// AcquireLock mocks base method.
+func (m *MockClient) AcquireLock(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLock", ctx, name, holder, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLock indicates an expected call of AcquireLock.
+func (mr *MockClientMockRecorder) AcquireLock(ctx, name, holder, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLock", reflect.TypeOf((*MockClient)(nil).AcquireLock), ctx, name, holder, ttl)
+}
+
 // ArchiveComputedPipeline mocks base method.
 func (m *MockClient) ArchiveComputedPipeline(ctx context.Context, repoSource, repoOwner, repoName string) error {
 	m.ctrl.T.Helper()
@@ -66,6 +81,21 @@ func (mr *MockClientMockRecorder) AwaitDatabaseReadiness(ctx interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwaitDatabaseReadiness", reflect.TypeOf((*MockClient)(nil).AwaitDatabaseReadiness), ctx)
 }
 
+// ClaimQueuedJob mocks base method.
+func (m *MockClient) ClaimQueuedJob(ctx context.Context, queuedJob QueuedJob) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimQueuedJob", ctx, queuedJob)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimQueuedJob indicates an expected call of ClaimQueuedJob.
+func (mr *MockClientMockRecorder) ClaimQueuedJob(ctx, queuedJob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimQueuedJob", reflect.TypeOf((*MockClient)(nil).ClaimQueuedJob), ctx, queuedJob)
+}
+
 // Connect mocks base method.
 func (m *MockClient) Connect(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -122,6 +152,34 @@ func (mr *MockClientMockRecorder) DeleteClient(ctx, client interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockClient)(nil).DeleteClient), ctx, client)
 }
 
+// DeleteClientSecret mocks base method.
+func (m *MockClient) DeleteClientSecret(ctx context.Context, secret api.ClientSecret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClientSecret", ctx, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClientSecret indicates an expected call of DeleteClientSecret.
+func (mr *MockClientMockRecorder) DeleteClientSecret(ctx, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientSecret", reflect.TypeOf((*MockClient)(nil).DeleteClientSecret), ctx, secret)
+}
+
+// DeleteCustomRole mocks base method.
+func (m *MockClient) DeleteCustomRole(ctx context.Context, customRole api.CustomRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCustomRole", ctx, customRole)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCustomRole indicates an expected call of DeleteCustomRole.
+func (mr *MockClientMockRecorder) DeleteCustomRole(ctx, customRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomRole", reflect.TypeOf((*MockClient)(nil).DeleteCustomRole), ctx, customRole)
+}
+
 // DeleteGroup mocks base method.
 func (m *MockClient) DeleteGroup(ctx context.Context, group ziplinee_ci_contracts.Group) error {
 	m.ctrl.T.Helper()
@@ -136,6 +194,21 @@ func (mr *MockClientMockRecorder) DeleteGroup(ctx, group interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroup", reflect.TypeOf((*MockClient)(nil).DeleteGroup), ctx, group)
 }
 
+// DeleteLogsByID mocks base method.
+func (m *MockClient) DeleteLogsByID(ctx context.Context, jobType ziplinee_ci_contracts.JobType, logIDs []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLogsByID", ctx, jobType, logIDs)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLogsByID indicates an expected call of DeleteLogsByID.
+func (mr *MockClientMockRecorder) DeleteLogsByID(ctx, jobType, logIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogsByID", reflect.TypeOf((*MockClient)(nil).DeleteLogsByID), ctx, jobType, logIDs)
+}
+
 // DeleteOrganization mocks base method.
 func (m *MockClient) DeleteOrganization(ctx context.Context, organization ziplinee_ci_contracts.Organization) error {
 	m.ctrl.T.Helper()
@@ -150,6 +223,35 @@ func (mr *MockClientMockRecorder) DeleteOrganization(ctx, organization interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganization", reflect.TypeOf((*MockClient)(nil).DeleteOrganization), ctx, organization)
 }
 
+// DeletePersonalAccessToken mocks base method.
+func (m *MockClient) DeletePersonalAccessToken(ctx context.Context, token api.PersonalAccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePersonalAccessToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePersonalAccessToken indicates an expected call of DeletePersonalAccessToken.
+func (mr *MockClientMockRecorder) DeletePersonalAccessToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePersonalAccessToken", reflect.TypeOf((*MockClient)(nil).DeletePersonalAccessToken), ctx, token)
+}
+
+// DeleteQueuedJob mocks base method.
+func (m *MockClient) DeleteQueuedJob(ctx context.Context, jobType ziplinee_ci_contracts.JobType, jobID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQueuedJob", ctx, jobType, jobID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteQueuedJob indicates an expected call of DeleteQueuedJob.
+func (mr *MockClientMockRecorder) DeleteQueuedJob(ctx, jobType, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQueuedJob", reflect.TypeOf((*MockClient)(nil).DeleteQueuedJob), ctx, jobType, jobID)
+}
+
 // DeleteUser mocks base method.
 func (m *MockClient) DeleteUser(ctx context.Context, user ziplinee_ci_contracts.User) error {
 	m.ctrl.T.Helper()
@@ -164,6 +266,51 @@ func (mr *MockClientMockRecorder) DeleteUser(ctx, user interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockClient)(nil).DeleteUser), ctx, user)
 }
 
+// GetActiveJobs mocks base method.
+func (m *MockClient) GetActiveJobs(ctx context.Context) ([]*ActiveJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveJobs", ctx)
+	ret0, _ := ret[0].([]*ActiveJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveJobs indicates an expected call of GetActiveJobs.
+func (mr *MockClientMockRecorder) GetActiveJobs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveJobs", reflect.TypeOf((*MockClient)(nil).GetActiveJobs), ctx)
+}
+
+// GetActiveLogReconciliation mocks base method.
+func (m *MockClient) GetActiveLogReconciliation(ctx context.Context) (*LogReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveLogReconciliation", ctx)
+	ret0, _ := ret[0].(*LogReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveLogReconciliation indicates an expected call of GetActiveLogReconciliation.
+func (mr *MockClientMockRecorder) GetActiveLogReconciliation(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveLogReconciliation", reflect.TypeOf((*MockClient)(nil).GetActiveLogReconciliation), ctx)
+}
+
+// GetActiveSessionsForSubject mocks base method.
+func (m *MockClient) GetActiveSessionsForSubject(ctx context.Context, subjectID string) ([]*api.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSessionsForSubject", ctx, subjectID)
+	ret0, _ := ret[0].([]*api.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSessionsForSubject indicates an expected call of GetActiveSessionsForSubject.
+func (mr *MockClientMockRecorder) GetActiveSessionsForSubject(ctx, subjectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessionsForSubject", reflect.TypeOf((*MockClient)(nil).GetActiveSessionsForSubject), ctx, subjectID)
+}
+
 // GetAllNotifications mocks base method.
 func (m *MockClient) GetAllNotifications(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*ziplinee_ci_contracts.NotificationRecord, error) {
 	m.ctrl.T.Helper()
@@ -344,6 +491,36 @@ func (mr *MockClientMockRecorder) GetAllReleasesReleaseTargetsCount(ctx, filters
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllReleasesReleaseTargetsCount", reflect.TypeOf((*MockClient)(nil).GetAllReleasesReleaseTargetsCount), ctx, filters)
 }
 
+// GetAuditLogRecords mocks base method.
+func (m *MockClient) GetAuditLogRecords(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) ([]*AuditLogRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogRecords", ctx, pageNumber, pageSize, filters)
+	ret0, _ := ret[0].([]*AuditLogRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogRecords indicates an expected call of GetAuditLogRecords.
+func (mr *MockClientMockRecorder) GetAuditLogRecords(ctx, pageNumber, pageSize, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogRecords", reflect.TypeOf((*MockClient)(nil).GetAuditLogRecords), ctx, pageNumber, pageSize, filters)
+}
+
+// GetAuditLogRecordsCount mocks base method.
+func (m *MockClient) GetAuditLogRecordsCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogRecordsCount", ctx, filters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogRecordsCount indicates an expected call of GetAuditLogRecordsCount.
+func (mr *MockClientMockRecorder) GetAuditLogRecordsCount(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogRecordsCount", reflect.TypeOf((*MockClient)(nil).GetAuditLogRecordsCount), ctx, filters)
+}
+
 // GetAutoIncrement mocks base method.
 func (m *MockClient) GetAutoIncrement(ctx context.Context, shortRepoSource, repoOwner, repoName string) (int, error) {
 	m.ctrl.T.Helper()
@@ -389,6 +566,21 @@ func (mr *MockClientMockRecorder) GetBotsCount(ctx, filters interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotsCount", reflect.TypeOf((*MockClient)(nil).GetBotsCount), ctx, filters)
 }
 
+// GetBuildStatusReasons mocks base method.
+func (m *MockClient) GetBuildStatusReasons(ctx context.Context, buildIDs []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuildStatusReasons", ctx, buildIDs)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuildStatusReasons indicates an expected call of GetBuildStatusReasons.
+func (mr *MockClientMockRecorder) GetBuildStatusReasons(ctx, buildIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuildStatusReasons", reflect.TypeOf((*MockClient)(nil).GetBuildStatusReasons), ctx, buildIDs)
+}
+
 // GetBuildsCount mocks base method.
 func (m *MockClient) GetBuildsCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
 	m.ctrl.T.Helper()
@@ -644,6 +836,36 @@ func (mr *MockClientMockRecorder) GetClientByID(ctx, id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientByID", reflect.TypeOf((*MockClient)(nil).GetClientByID), ctx, id)
 }
 
+// GetClientSecretByHash mocks base method.
+func (m *MockClient) GetClientSecretByHash(ctx context.Context, clientID, secretHash string) (*api.ClientSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientSecretByHash", ctx, clientID, secretHash)
+	ret0, _ := ret[0].(*api.ClientSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientSecretByHash indicates an expected call of GetClientSecretByHash.
+func (mr *MockClientMockRecorder) GetClientSecretByHash(ctx, clientID, secretHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientSecretByHash", reflect.TypeOf((*MockClient)(nil).GetClientSecretByHash), ctx, clientID, secretHash)
+}
+
+// GetClientSecretsForClient mocks base method.
+func (m *MockClient) GetClientSecretsForClient(ctx context.Context, clientID string) ([]*api.ClientSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientSecretsForClient", ctx, clientID)
+	ret0, _ := ret[0].([]*api.ClientSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientSecretsForClient indicates an expected call of GetClientSecretsForClient.
+func (mr *MockClientMockRecorder) GetClientSecretsForClient(ctx, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientSecretsForClient", reflect.TypeOf((*MockClient)(nil).GetClientSecretsForClient), ctx, clientID)
+}
+
 // GetClients mocks base method.
 func (m *MockClient) GetClients(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*ziplinee_ci_contracts.Client, error) {
 	m.ctrl.T.Helper()
@@ -689,6 +911,81 @@ func (mr *MockClientMockRecorder) GetCronTriggers(ctx interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCronTriggers", reflect.TypeOf((*MockClient)(nil).GetCronTriggers), ctx)
 }
 
+// GetCustomRoleByID mocks base method.
+func (m *MockClient) GetCustomRoleByID(ctx context.Context, id string) (*api.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRoleByID", ctx, id)
+	ret0, _ := ret[0].(*api.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRoleByID indicates an expected call of GetCustomRoleByID.
+func (mr *MockClientMockRecorder) GetCustomRoleByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRoleByID", reflect.TypeOf((*MockClient)(nil).GetCustomRoleByID), ctx, id)
+}
+
+// GetCustomRoles mocks base method.
+func (m *MockClient) GetCustomRoles(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*api.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRoles", ctx, pageNumber, pageSize, filters, sortings)
+	ret0, _ := ret[0].([]*api.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRoles indicates an expected call of GetCustomRoles.
+func (mr *MockClientMockRecorder) GetCustomRoles(ctx, pageNumber, pageSize, filters, sortings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRoles", reflect.TypeOf((*MockClient)(nil).GetCustomRoles), ctx, pageNumber, pageSize, filters, sortings)
+}
+
+// GetCustomRolesCount mocks base method.
+func (m *MockClient) GetCustomRolesCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomRolesCount", ctx, filters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomRolesCount indicates an expected call of GetCustomRolesCount.
+func (mr *MockClientMockRecorder) GetCustomRolesCount(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomRolesCount", reflect.TypeOf((*MockClient)(nil).GetCustomRolesCount), ctx, filters)
+}
+
+// GetExpiredLogIDs mocks base method.
+func (m *MockClient) GetExpiredLogIDs(ctx context.Context, jobType ziplinee_ci_contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredLogIDs", ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredLogIDs indicates an expected call of GetExpiredLogIDs.
+func (mr *MockClientMockRecorder) GetExpiredLogIDs(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredLogIDs", reflect.TypeOf((*MockClient)(nil).GetExpiredLogIDs), ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs, limit)
+}
+
+// GetExpiredLogsCount mocks base method.
+func (m *MockClient) GetExpiredLogsCount(ctx context.Context, jobType ziplinee_ci_contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredLogsCount", ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredLogsCount indicates an expected call of GetExpiredLogsCount.
+func (mr *MockClientMockRecorder) GetExpiredLogsCount(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredLogsCount", reflect.TypeOf((*MockClient)(nil).GetExpiredLogsCount), ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs)
+}
+
 // GetFirstBotTimes mocks base method.
 func (m *MockClient) GetFirstBotTimes(ctx context.Context) ([]time.Time, error) {
 	m.ctrl.T.Helper()
@@ -854,6 +1151,21 @@ func (mr *MockClientMockRecorder) GetGroupByIdentity(ctx, identity interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupByIdentity", reflect.TypeOf((*MockClient)(nil).GetGroupByIdentity), ctx, identity)
 }
 
+// GetGroupByName mocks base method.
+func (m *MockClient) GetGroupByName(ctx context.Context, name string) (*ziplinee_ci_contracts.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupByName", ctx, name)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupByName indicates an expected call of GetGroupByName.
+func (mr *MockClientMockRecorder) GetGroupByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupByName", reflect.TypeOf((*MockClient)(nil).GetGroupByName), ctx, name)
+}
+
 // GetGroups mocks base method.
 func (m *MockClient) GetGroups(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*ziplinee_ci_contracts.Group, error) {
 	m.ctrl.T.Helper()
@@ -884,6 +1196,36 @@ func (mr *MockClientMockRecorder) GetGroupsCount(ctx, filters interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsCount", reflect.TypeOf((*MockClient)(nil).GetGroupsCount), ctx, filters)
 }
 
+// GetJobResourceUtilizations mocks base method.
+func (m *MockClient) GetJobResourceUtilizations(ctx context.Context, jobType ziplinee_ci_contracts.JobType, from, to time.Time) ([]*JobResourceUtilization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobResourceUtilizations", ctx, jobType, from, to)
+	ret0, _ := ret[0].([]*JobResourceUtilization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobResourceUtilizations indicates an expected call of GetJobResourceUtilizations.
+func (mr *MockClientMockRecorder) GetJobResourceUtilizations(ctx, jobType, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobResourceUtilizations", reflect.TypeOf((*MockClient)(nil).GetJobResourceUtilizations), ctx, jobType, from, to)
+}
+
+// GetJobResources mocks base method.
+func (m *MockClient) GetJobResources(ctx context.Context, jobType ziplinee_ci_contracts.JobType, repoSource, repoOwner, repoName, jobID string) (JobResources, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobResources", ctx, jobType, repoSource, repoOwner, repoName, jobID)
+	ret0, _ := ret[0].(JobResources)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobResources indicates an expected call of GetJobResources.
+func (mr *MockClientMockRecorder) GetJobResources(ctx, jobType, repoSource, repoOwner, repoName, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobResources", reflect.TypeOf((*MockClient)(nil).GetJobResources), ctx, jobType, repoSource, repoOwner, repoName, jobID)
+}
+
 // GetLabelValues mocks base method.
 func (m *MockClient) GetLabelValues(ctx context.Context, labelKey string) ([]map[string]interface{}, error) {
 	m.ctrl.T.Helper()
@@ -944,143 +1286,383 @@ func (mr *MockClientMockRecorder) GetLastPipelineReleases(ctx, repoSource, repoO
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastPipelineReleases", reflect.TypeOf((*MockClient)(nil).GetLastPipelineReleases), ctx, repoSource, repoOwner, repoName, releaseName, releaseAction, pageSize)
 }
 
-// GetOrganizationByID mocks base method.
-func (m *MockClient) GetOrganizationByID(ctx context.Context, id string) (*ziplinee_ci_contracts.Organization, error) {
+// GetLatestSuccessfulReleaseLogIDs mocks base method.
+func (m *MockClient) GetLatestSuccessfulReleaseLogIDs(ctx context.Context, repoSource, repoOwner, repoName string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationByID", ctx, id)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.Organization)
+	ret := m.ctrl.Call(m, "GetLatestSuccessfulReleaseLogIDs", ctx, repoSource, repoOwner, repoName)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationByID indicates an expected call of GetOrganizationByID.
-func (mr *MockClientMockRecorder) GetOrganizationByID(ctx, id interface{}) *gomock.Call {
+// GetLatestSuccessfulReleaseLogIDs indicates an expected call of GetLatestSuccessfulReleaseLogIDs.
+func (mr *MockClientMockRecorder) GetLatestSuccessfulReleaseLogIDs(ctx, repoSource, repoOwner, repoName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationByID", reflect.TypeOf((*MockClient)(nil).GetOrganizationByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestSuccessfulReleaseLogIDs", reflect.TypeOf((*MockClient)(nil).GetLatestSuccessfulReleaseLogIDs), ctx, repoSource, repoOwner, repoName)
 }
 
-// GetOrganizationByIdentity mocks base method.
-func (m *MockClient) GetOrganizationByIdentity(ctx context.Context, identity ziplinee_ci_contracts.OrganizationIdentity) (*ziplinee_ci_contracts.Organization, error) {
+// GetLogReconciliationByID mocks base method.
+func (m *MockClient) GetLogReconciliationByID(ctx context.Context, id string) (*LogReconciliation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationByIdentity", ctx, identity)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.Organization)
+	ret := m.ctrl.Call(m, "GetLogReconciliationByID", ctx, id)
+	ret0, _ := ret[0].(*LogReconciliation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationByIdentity indicates an expected call of GetOrganizationByIdentity.
-func (mr *MockClientMockRecorder) GetOrganizationByIdentity(ctx, identity interface{}) *gomock.Call {
+// GetLogReconciliationByID indicates an expected call of GetLogReconciliationByID.
+func (mr *MockClientMockRecorder) GetLogReconciliationByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationByIdentity", reflect.TypeOf((*MockClient)(nil).GetOrganizationByIdentity), ctx, identity)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogReconciliationByID", reflect.TypeOf((*MockClient)(nil).GetLogReconciliationByID), ctx, id)
 }
 
-// GetOrganizationByName mocks base method.
-func (m *MockClient) GetOrganizationByName(ctx context.Context, name string) (*ziplinee_ci_contracts.Organization, error) {
+// GetLogReconciliations mocks base method.
+func (m *MockClient) GetLogReconciliations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) ([]*LogReconciliation, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationByName", ctx, name)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.Organization)
+	ret := m.ctrl.Call(m, "GetLogReconciliations", ctx, pageNumber, pageSize, filters)
+	ret0, _ := ret[0].([]*LogReconciliation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationByName indicates an expected call of GetOrganizationByName.
-func (mr *MockClientMockRecorder) GetOrganizationByName(ctx, name interface{}) *gomock.Call {
+// GetLogReconciliations indicates an expected call of GetLogReconciliations.
+func (mr *MockClientMockRecorder) GetLogReconciliations(ctx, pageNumber, pageSize, filters interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationByName", reflect.TypeOf((*MockClient)(nil).GetOrganizationByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogReconciliations", reflect.TypeOf((*MockClient)(nil).GetLogReconciliations), ctx, pageNumber, pageSize, filters)
 }
 
-// GetOrganizations mocks base method.
-func (m *MockClient) GetOrganizations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*ziplinee_ci_contracts.Organization, error) {
+// GetLogReconciliationsCount mocks base method.
+func (m *MockClient) GetLogReconciliationsCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizations", ctx, pageNumber, pageSize, filters, sortings)
-	ret0, _ := ret[0].([]*ziplinee_ci_contracts.Organization)
+	ret := m.ctrl.Call(m, "GetLogReconciliationsCount", ctx, filters)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizations indicates an expected call of GetOrganizations.
-func (mr *MockClientMockRecorder) GetOrganizations(ctx, pageNumber, pageSize, filters, sortings interface{}) *gomock.Call {
+// GetLogReconciliationsCount indicates an expected call of GetLogReconciliationsCount.
+func (mr *MockClientMockRecorder) GetLogReconciliationsCount(ctx, filters interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizations", reflect.TypeOf((*MockClient)(nil).GetOrganizations), ctx, pageNumber, pageSize, filters, sortings)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogReconciliationsCount", reflect.TypeOf((*MockClient)(nil).GetLogReconciliationsCount), ctx, filters)
 }
 
-// GetOrganizationsCount mocks base method.
-func (m *MockClient) GetOrganizationsCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
+// GetLogRecords mocks base method.
+func (m *MockClient) GetLogRecords(ctx context.Context, jobType ziplinee_ci_contracts.JobType, repoSource, repoOwner, repoName string) ([]*LogRecord, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationsCount", ctx, filters)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "GetLogRecords", ctx, jobType, repoSource, repoOwner, repoName)
+	ret0, _ := ret[0].([]*LogRecord)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationsCount indicates an expected call of GetOrganizationsCount.
-func (mr *MockClientMockRecorder) GetOrganizationsCount(ctx, filters interface{}) *gomock.Call {
+// GetLogRecords indicates an expected call of GetLogRecords.
+func (mr *MockClientMockRecorder) GetLogRecords(ctx, jobType, repoSource, repoOwner, repoName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsCount", reflect.TypeOf((*MockClient)(nil).GetOrganizationsCount), ctx, filters)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogRecords", reflect.TypeOf((*MockClient)(nil).GetLogRecords), ctx, jobType, repoSource, repoOwner, repoName)
 }
 
-// GetPipeline mocks base method.
-func (m *MockClient) GetPipeline(ctx context.Context, repoSource, repoOwner, repoName string, filters map[api.FilterType][]string, optimized bool) (*ziplinee_ci_contracts.Pipeline, error) {
+// GetMigratedBuildLogIDs mocks base method.
+func (m *MockClient) GetMigratedBuildLogIDs(ctx context.Context, task MigrationTask) (map[string]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPipeline", ctx, repoSource, repoOwner, repoName, filters, optimized)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.Pipeline)
+	ret := m.ctrl.Call(m, "GetMigratedBuildLogIDs", ctx, task)
+	ret0, _ := ret[0].(map[string]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPipeline indicates an expected call of GetPipeline.
-func (mr *MockClientMockRecorder) GetPipeline(ctx, repoSource, repoOwner, repoName, filters, optimized interface{}) *gomock.Call {
+// GetMigratedBuildLogIDs indicates an expected call of GetMigratedBuildLogIDs.
+func (mr *MockClientMockRecorder) GetMigratedBuildLogIDs(ctx, task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipeline", reflect.TypeOf((*MockClient)(nil).GetPipeline), ctx, repoSource, repoOwner, repoName, filters, optimized)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMigratedBuildLogIDs", reflect.TypeOf((*MockClient)(nil).GetMigratedBuildLogIDs), ctx, task)
 }
 
-// GetPipelineBot mocks base method.
-func (m *MockClient) GetPipelineBot(ctx context.Context, repoSource, repoOwner, repoName, botID string) (*ziplinee_ci_contracts.Bot, error) {
+// GetMigratedReleaseLogIDs mocks base method.
+func (m *MockClient) GetMigratedReleaseLogIDs(ctx context.Context, task MigrationTask) (map[string]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPipelineBot", ctx, repoSource, repoOwner, repoName, botID)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.Bot)
+	ret := m.ctrl.Call(m, "GetMigratedReleaseLogIDs", ctx, task)
+	ret0, _ := ret[0].(map[string]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPipelineBot indicates an expected call of GetPipelineBot.
-func (mr *MockClientMockRecorder) GetPipelineBot(ctx, repoSource, repoOwner, repoName, botID interface{}) *gomock.Call {
+// GetMigratedReleaseLogIDs indicates an expected call of GetMigratedReleaseLogIDs.
+func (mr *MockClientMockRecorder) GetMigratedReleaseLogIDs(ctx, task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBot", reflect.TypeOf((*MockClient)(nil).GetPipelineBot), ctx, repoSource, repoOwner, repoName, botID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMigratedReleaseLogIDs", reflect.TypeOf((*MockClient)(nil).GetMigratedReleaseLogIDs), ctx, task)
 }
 
-// GetPipelineBotLogs mocks base method.
-func (m *MockClient) GetPipelineBotLogs(ctx context.Context, repoSource, repoOwner, repoName, botID string) (*ziplinee_ci_contracts.BotLog, error) {
+// GetMigrationTaskByFromRepo mocks base method.
+func (m *MockClient) GetMigrationTaskByFromRepo(ctx context.Context, fromSource, fromOwner, fromName string) (*MigrationTask, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPipelineBotLogs", ctx, repoSource, repoOwner, repoName, botID)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.BotLog)
+	ret := m.ctrl.Call(m, "GetMigrationTaskByFromRepo", ctx, fromSource, fromOwner, fromName)
+	ret0, _ := ret[0].(*MigrationTask)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPipelineBotLogs indicates an expected call of GetPipelineBotLogs.
-func (mr *MockClientMockRecorder) GetPipelineBotLogs(ctx, repoSource, repoOwner, repoName, botID interface{}) *gomock.Call {
+// GetMigrationTaskByFromRepo indicates an expected call of GetMigrationTaskByFromRepo.
+func (mr *MockClientMockRecorder) GetMigrationTaskByFromRepo(ctx, fromSource, fromOwner, fromName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotLogs", reflect.TypeOf((*MockClient)(nil).GetPipelineBotLogs), ctx, repoSource, repoOwner, repoName, botID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMigrationTaskByFromRepo", reflect.TypeOf((*MockClient)(nil).GetMigrationTaskByFromRepo), ctx, fromSource, fromOwner, fromName)
 }
 
-// GetPipelineBotLogsByID mocks base method.
-func (m *MockClient) GetPipelineBotLogsByID(ctx context.Context, repoSource, repoOwner, repoName, botID, id string) (*ziplinee_ci_contracts.BotLog, error) {
+// GetMigrationTaskByID mocks base method.
+func (m *MockClient) GetMigrationTaskByID(ctx context.Context, id string) (*MigrationTask, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPipelineBotLogsByID", ctx, repoSource, repoOwner, repoName, botID, id)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.BotLog)
+	ret := m.ctrl.Call(m, "GetMigrationTaskByID", ctx, id)
+	ret0, _ := ret[0].(*MigrationTask)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPipelineBotLogsByID indicates an expected call of GetPipelineBotLogsByID.
-func (mr *MockClientMockRecorder) GetPipelineBotLogsByID(ctx, repoSource, repoOwner, repoName, botID, id interface{}) *gomock.Call {
+// GetMigrationTaskByID indicates an expected call of GetMigrationTaskByID.
+func (mr *MockClientMockRecorder) GetMigrationTaskByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotLogsByID", reflect.TypeOf((*MockClient)(nil).GetPipelineBotLogsByID), ctx, repoSource, repoOwner, repoName, botID, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMigrationTaskByID", reflect.TypeOf((*MockClient)(nil).GetMigrationTaskByID), ctx, id)
 }
 
-// GetPipelineBotLogsCount mocks base method.
-func (m *MockClient) GetPipelineBotLogsCount(ctx context.Context, repoSource, repoOwner, repoName, botID string) (int, error) {
+// GetMigrationTasks mocks base method.
+func (m *MockClient) GetMigrationTasks(ctx context.Context) ([]*MigrationTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMigrationTasks", ctx)
+	ret0, _ := ret[0].([]*MigrationTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMigrationTasks indicates an expected call of GetMigrationTasks.
+func (mr *MockClientMockRecorder) GetMigrationTasks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMigrationTasks", reflect.TypeOf((*MockClient)(nil).GetMigrationTasks), ctx)
+}
+
+// GetNotificationDeliveries mocks base method.
+func (m *MockClient) GetNotificationDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) ([]*NotificationDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationDeliveries", ctx, pageNumber, pageSize, filters)
+	ret0, _ := ret[0].([]*NotificationDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationDeliveries indicates an expected call of GetNotificationDeliveries.
+func (mr *MockClientMockRecorder) GetNotificationDeliveries(ctx, pageNumber, pageSize, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationDeliveries", reflect.TypeOf((*MockClient)(nil).GetNotificationDeliveries), ctx, pageNumber, pageSize, filters)
+}
+
+// GetNotificationDeliveriesCount mocks base method.
+func (m *MockClient) GetNotificationDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationDeliveriesCount", ctx, filters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationDeliveriesCount indicates an expected call of GetNotificationDeliveriesCount.
+func (mr *MockClientMockRecorder) GetNotificationDeliveriesCount(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationDeliveriesCount", reflect.TypeOf((*MockClient)(nil).GetNotificationDeliveriesCount), ctx, filters)
+}
+
+// GetNotificationDeliveryByID mocks base method.
+func (m *MockClient) GetNotificationDeliveryByID(ctx context.Context, id string) (*NotificationDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationDeliveryByID", ctx, id)
+	ret0, _ := ret[0].(*NotificationDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationDeliveryByID indicates an expected call of GetNotificationDeliveryByID.
+func (mr *MockClientMockRecorder) GetNotificationDeliveryByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationDeliveryByID", reflect.TypeOf((*MockClient)(nil).GetNotificationDeliveryByID), ctx, id)
+}
+
+// GetOrganizationByID mocks base method.
+func (m *MockClient) GetOrganizationByID(ctx context.Context, id string) (*ziplinee_ci_contracts.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationByID", ctx, id)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationByID indicates an expected call of GetOrganizationByID.
+func (mr *MockClientMockRecorder) GetOrganizationByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationByID", reflect.TypeOf((*MockClient)(nil).GetOrganizationByID), ctx, id)
+}
+
+// GetOrganizationByIdentity mocks base method.
+func (m *MockClient) GetOrganizationByIdentity(ctx context.Context, identity ziplinee_ci_contracts.OrganizationIdentity) (*ziplinee_ci_contracts.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationByIdentity", ctx, identity)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationByIdentity indicates an expected call of GetOrganizationByIdentity.
+func (mr *MockClientMockRecorder) GetOrganizationByIdentity(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationByIdentity", reflect.TypeOf((*MockClient)(nil).GetOrganizationByIdentity), ctx, identity)
+}
+
+// GetOrganizationByName mocks base method.
+func (m *MockClient) GetOrganizationByName(ctx context.Context, name string) (*ziplinee_ci_contracts.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationByName", ctx, name)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationByName indicates an expected call of GetOrganizationByName.
+func (mr *MockClientMockRecorder) GetOrganizationByName(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationByName", reflect.TypeOf((*MockClient)(nil).GetOrganizationByName), ctx, name)
+}
+
+// GetOrganizations mocks base method.
+func (m *MockClient) GetOrganizations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*ziplinee_ci_contracts.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizations", ctx, pageNumber, pageSize, filters, sortings)
+	ret0, _ := ret[0].([]*ziplinee_ci_contracts.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizations indicates an expected call of GetOrganizations.
+func (mr *MockClientMockRecorder) GetOrganizations(ctx, pageNumber, pageSize, filters, sortings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizations", reflect.TypeOf((*MockClient)(nil).GetOrganizations), ctx, pageNumber, pageSize, filters, sortings)
+}
+
+// GetOrganizationsCount mocks base method.
+func (m *MockClient) GetOrganizationsCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationsCount", ctx, filters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationsCount indicates an expected call of GetOrganizationsCount.
+func (mr *MockClientMockRecorder) GetOrganizationsCount(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsCount", reflect.TypeOf((*MockClient)(nil).GetOrganizationsCount), ctx, filters)
+}
+
+// GetPersonalAccessTokenByHash mocks base method.
+func (m *MockClient) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*api.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessTokenByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*api.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessTokenByHash indicates an expected call of GetPersonalAccessTokenByHash.
+func (mr *MockClientMockRecorder) GetPersonalAccessTokenByHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokenByHash", reflect.TypeOf((*MockClient)(nil).GetPersonalAccessTokenByHash), ctx, tokenHash)
+}
+
+// GetPersonalAccessTokenByID mocks base method.
+func (m *MockClient) GetPersonalAccessTokenByID(ctx context.Context, id string) (*api.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessTokenByID", ctx, id)
+	ret0, _ := ret[0].(*api.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessTokenByID indicates an expected call of GetPersonalAccessTokenByID.
+func (mr *MockClientMockRecorder) GetPersonalAccessTokenByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokenByID", reflect.TypeOf((*MockClient)(nil).GetPersonalAccessTokenByID), ctx, id)
+}
+
+// GetPersonalAccessTokensForUser mocks base method.
+func (m *MockClient) GetPersonalAccessTokensForUser(ctx context.Context, userID string) ([]*api.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessTokensForUser", ctx, userID)
+	ret0, _ := ret[0].([]*api.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessTokensForUser indicates an expected call of GetPersonalAccessTokensForUser.
+func (mr *MockClientMockRecorder) GetPersonalAccessTokensForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokensForUser", reflect.TypeOf((*MockClient)(nil).GetPersonalAccessTokensForUser), ctx, userID)
+}
+
+// GetPipeline mocks base method.
+func (m *MockClient) GetPipeline(ctx context.Context, repoSource, repoOwner, repoName string, filters map[api.FilterType][]string, optimized bool) (*ziplinee_ci_contracts.Pipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipeline", ctx, repoSource, repoOwner, repoName, filters, optimized)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.Pipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipeline indicates an expected call of GetPipeline.
+func (mr *MockClientMockRecorder) GetPipeline(ctx, repoSource, repoOwner, repoName, filters, optimized interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipeline", reflect.TypeOf((*MockClient)(nil).GetPipeline), ctx, repoSource, repoOwner, repoName, filters, optimized)
+}
+
+// GetPipelineBot mocks base method.
+func (m *MockClient) GetPipelineBot(ctx context.Context, repoSource, repoOwner, repoName, botID string) (*ziplinee_ci_contracts.Bot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineBot", ctx, repoSource, repoOwner, repoName, botID)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.Bot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineBot indicates an expected call of GetPipelineBot.
+func (mr *MockClientMockRecorder) GetPipelineBot(ctx, repoSource, repoOwner, repoName, botID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBot", reflect.TypeOf((*MockClient)(nil).GetPipelineBot), ctx, repoSource, repoOwner, repoName, botID)
+}
+
+// GetPipelineBotLogs mocks base method.
+func (m *MockClient) GetPipelineBotLogs(ctx context.Context, repoSource, repoOwner, repoName, botID string) (*ziplinee_ci_contracts.BotLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineBotLogs", ctx, repoSource, repoOwner, repoName, botID)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.BotLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineBotLogs indicates an expected call of GetPipelineBotLogs.
+func (mr *MockClientMockRecorder) GetPipelineBotLogs(ctx, repoSource, repoOwner, repoName, botID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotLogs", reflect.TypeOf((*MockClient)(nil).GetPipelineBotLogs), ctx, repoSource, repoOwner, repoName, botID)
+}
+
+// GetPipelineBotLogsByID mocks base method.
+func (m *MockClient) GetPipelineBotLogsByID(ctx context.Context, repoSource, repoOwner, repoName, botID, id string) (*ziplinee_ci_contracts.BotLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineBotLogsByID", ctx, repoSource, repoOwner, repoName, botID, id)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.BotLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineBotLogsByID indicates an expected call of GetPipelineBotLogsByID.
+func (mr *MockClientMockRecorder) GetPipelineBotLogsByID(ctx, repoSource, repoOwner, repoName, botID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotLogsByID", reflect.TypeOf((*MockClient)(nil).GetPipelineBotLogsByID), ctx, repoSource, repoOwner, repoName, botID, id)
+}
+
+// GetPipelineBotLogsCount mocks base method.
+func (m *MockClient) GetPipelineBotLogsCount(ctx context.Context, repoSource, repoOwner, repoName, botID string) (int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPipelineBotLogsCount", ctx, repoSource, repoOwner, repoName, botID)
 	ret0, _ := ret[0].(int)
@@ -1155,6 +1737,21 @@ func (mr *MockClientMockRecorder) GetPipelineBotNamesCount(ctx, repoSource, repo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotNamesCount", reflect.TypeOf((*MockClient)(nil).GetPipelineBotNamesCount), ctx, repoSource, repoOwner, repoName, filters)
 }
 
+// GetPipelineBotResourceUsages mocks base method.
+func (m *MockClient) GetPipelineBotResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, botName string, lastNRecords int) ([]*JobResourceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineBotResourceUsages", ctx, repoSource, repoOwner, repoName, botName, lastNRecords)
+	ret0, _ := ret[0].([]*JobResourceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineBotResourceUsages indicates an expected call of GetPipelineBotResourceUsages.
+func (mr *MockClientMockRecorder) GetPipelineBotResourceUsages(ctx, repoSource, repoOwner, repoName, botName, lastNRecords interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBotResourceUsages", reflect.TypeOf((*MockClient)(nil).GetPipelineBotResourceUsages), ctx, repoSource, repoOwner, repoName, botName, lastNRecords)
+}
+
 // GetPipelineBots mocks base method.
 func (m *MockClient) GetPipelineBots(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*ziplinee_ci_contracts.Bot, error) {
 	m.ctrl.T.Helper()
@@ -1366,6 +1963,21 @@ func (mr *MockClientMockRecorder) GetPipelineBuildMaxResourceUtilization(ctx, re
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBuildMaxResourceUtilization", reflect.TypeOf((*MockClient)(nil).GetPipelineBuildMaxResourceUtilization), ctx, repoSource, repoOwner, repoName, lastNRecords)
 }
 
+// GetPipelineBuildResourceUsages mocks base method.
+func (m *MockClient) GetPipelineBuildResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, repoBranch string, lastNRecords int) ([]*JobResourceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineBuildResourceUsages", ctx, repoSource, repoOwner, repoName, repoBranch, lastNRecords)
+	ret0, _ := ret[0].([]*JobResourceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineBuildResourceUsages indicates an expected call of GetPipelineBuildResourceUsages.
+func (mr *MockClientMockRecorder) GetPipelineBuildResourceUsages(ctx, repoSource, repoOwner, repoName, repoBranch, lastNRecords interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineBuildResourceUsages", reflect.TypeOf((*MockClient)(nil).GetPipelineBuildResourceUsages), ctx, repoSource, repoOwner, repoName, repoBranch, lastNRecords)
+}
+
 // GetPipelineBuilds mocks base method.
 func (m *MockClient) GetPipelineBuilds(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField, optimized bool) ([]*ziplinee_ci_contracts.Build, error) {
 	m.ctrl.T.Helper()
@@ -1577,6 +2189,21 @@ func (mr *MockClientMockRecorder) GetPipelineReleaseMaxResourceUtilization(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineReleaseMaxResourceUtilization", reflect.TypeOf((*MockClient)(nil).GetPipelineReleaseMaxResourceUtilization), ctx, repoSource, repoOwner, repoName, targetName, lastNRecords)
 }
 
+// GetPipelineReleaseResourceUsages mocks base method.
+func (m *MockClient) GetPipelineReleaseResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, targetName, actionName string, lastNRecords int) ([]*JobResourceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPipelineReleaseResourceUsages", ctx, repoSource, repoOwner, repoName, targetName, actionName, lastNRecords)
+	ret0, _ := ret[0].([]*JobResourceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPipelineReleaseResourceUsages indicates an expected call of GetPipelineReleaseResourceUsages.
+func (mr *MockClientMockRecorder) GetPipelineReleaseResourceUsages(ctx, repoSource, repoOwner, repoName, targetName, actionName, lastNRecords interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPipelineReleaseResourceUsages", reflect.TypeOf((*MockClient)(nil).GetPipelineReleaseResourceUsages), ctx, repoSource, repoOwner, repoName, targetName, actionName, lastNRecords)
+}
+
 // GetPipelineReleases mocks base method.
 func (m *MockClient) GetPipelineReleases(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*ziplinee_ci_contracts.Release, error) {
 	m.ctrl.T.Helper()
@@ -1817,6 +2444,36 @@ func (mr *MockClientMockRecorder) GetPubSubTriggers(ctx interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubSubTriggers", reflect.TypeOf((*MockClient)(nil).GetPubSubTriggers), ctx)
 }
 
+// GetQueuedJobs mocks base method.
+func (m *MockClient) GetQueuedJobs(ctx context.Context, limit int) ([]*QueuedJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueuedJobs", ctx, limit)
+	ret0, _ := ret[0].([]*QueuedJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueuedJobs indicates an expected call of GetQueuedJobs.
+func (mr *MockClientMockRecorder) GetQueuedJobs(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueuedJobs", reflect.TypeOf((*MockClient)(nil).GetQueuedJobs), ctx, limit)
+}
+
+// GetReleaseOutcomes mocks base method.
+func (m *MockClient) GetReleaseOutcomes(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) ([]*ReleaseOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReleaseOutcomes", ctx, repoSource, repoOwner, repoName, from, to, filters)
+	ret0, _ := ret[0].([]*ReleaseOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReleaseOutcomes indicates an expected call of GetReleaseOutcomes.
+func (mr *MockClientMockRecorder) GetReleaseOutcomes(ctx, repoSource, repoOwner, repoName, from, to, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleaseOutcomes", reflect.TypeOf((*MockClient)(nil).GetReleaseOutcomes), ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
+
 // GetReleaseTargets mocks base method.
 func (m *MockClient) GetReleaseTargets(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) ([]map[string]interface{}, error) {
 	m.ctrl.T.Helper()
@@ -1877,6 +2534,21 @@ func (mr *MockClientMockRecorder) GetReleasesCount(ctx, filters interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleasesCount", reflect.TypeOf((*MockClient)(nil).GetReleasesCount), ctx, filters)
 }
 
+// GetSessionByID mocks base method.
+func (m *MockClient) GetSessionByID(ctx context.Context, id string) (*api.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionByID", ctx, id)
+	ret0, _ := ret[0].(*api.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionByID indicates an expected call of GetSessionByID.
+func (mr *MockClientMockRecorder) GetSessionByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByID", reflect.TypeOf((*MockClient)(nil).GetSessionByID), ctx, id)
+}
+
 // GetTriggers mocks base method.
 func (m *MockClient) GetTriggers(ctx context.Context, triggerType, identifier, event string) ([]*ziplinee_ci_contracts.Pipeline, error) {
 	m.ctrl.T.Helper()
@@ -1892,6 +2564,21 @@ func (mr *MockClientMockRecorder) GetTriggers(ctx, triggerType, identifier, even
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTriggers", reflect.TypeOf((*MockClient)(nil).GetTriggers), ctx, triggerType, identifier, event)
 }
 
+// GetUserByEmail mocks base method.
+func (m *MockClient) GetUserByEmail(ctx context.Context, email string) (*ziplinee_ci_contracts.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockClientMockRecorder) GetUserByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockClient)(nil).GetUserByEmail), ctx, email)
+}
+
 // GetUserByID mocks base method.
 func (m *MockClient) GetUserByID(ctx context.Context, id string, filters map[api.FilterType][]string) (*ziplinee_ci_contracts.User, error) {
 	m.ctrl.T.Helper()
@@ -1952,16 +2639,91 @@ func (mr *MockClientMockRecorder) GetUsersCount(ctx, filters interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersCount", reflect.TypeOf((*MockClient)(nil).GetUsersCount), ctx, filters)
 }
 
-// InsertBot mocks base method.
-func (m *MockClient) InsertBot(ctx context.Context, bot ziplinee_ci_contracts.Bot, jobResources JobResources) (*ziplinee_ci_contracts.Bot, error) {
+// GetWebhookDeliveries mocks base method.
+func (m *MockClient) GetWebhookDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) ([]*WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertBot", ctx, bot, jobResources)
-	ret0, _ := ret[0].(*ziplinee_ci_contracts.Bot)
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, pageNumber, pageSize, filters, sortings)
+	ret0, _ := ret[0].([]*WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertBot indicates an expected call of InsertBot.
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockClientMockRecorder) GetWebhookDeliveries(ctx, pageNumber, pageSize, filters, sortings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockClient)(nil).GetWebhookDeliveries), ctx, pageNumber, pageSize, filters, sortings)
+}
+
+// GetWebhookDeliveriesCount mocks base method.
+func (m *MockClient) GetWebhookDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveriesCount", ctx, filters)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveriesCount indicates an expected call of GetWebhookDeliveriesCount.
+func (mr *MockClientMockRecorder) GetWebhookDeliveriesCount(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesCount", reflect.TypeOf((*MockClient)(nil).GetWebhookDeliveriesCount), ctx, filters)
+}
+
+// GetWebhookDeliveryByID mocks base method.
+func (m *MockClient) GetWebhookDeliveryByID(ctx context.Context, id string) (*WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveryByID", ctx, id)
+	ret0, _ := ret[0].(*WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveryByID indicates an expected call of GetWebhookDeliveryByID.
+func (mr *MockClientMockRecorder) GetWebhookDeliveryByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveryByID", reflect.TypeOf((*MockClient)(nil).GetWebhookDeliveryByID), ctx, id)
+}
+
+// HashLegacyClientSecret mocks base method.
+func (m *MockClient) HashLegacyClientSecret(ctx context.Context, client ziplinee_ci_contracts.Client, secretHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashLegacyClientSecret", ctx, client, secretHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HashLegacyClientSecret indicates an expected call of HashLegacyClientSecret.
+func (mr *MockClientMockRecorder) HashLegacyClientSecret(ctx, client, secretHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashLegacyClientSecret", reflect.TypeOf((*MockClient)(nil).HashLegacyClientSecret), ctx, client, secretHash)
+}
+
+// InsertAuditLogRecord mocks base method.
+func (m *MockClient) InsertAuditLogRecord(ctx context.Context, record AuditLogRecord) (*AuditLogRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditLogRecord", ctx, record)
+	ret0, _ := ret[0].(*AuditLogRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAuditLogRecord indicates an expected call of InsertAuditLogRecord.
+func (mr *MockClientMockRecorder) InsertAuditLogRecord(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLogRecord", reflect.TypeOf((*MockClient)(nil).InsertAuditLogRecord), ctx, record)
+}
+
+// InsertBot mocks base method.
+func (m *MockClient) InsertBot(ctx context.Context, bot ziplinee_ci_contracts.Bot, jobResources JobResources) (*ziplinee_ci_contracts.Bot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBot", ctx, bot, jobResources)
+	ret0, _ := ret[0].(*ziplinee_ci_contracts.Bot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBot indicates an expected call of InsertBot.
 func (mr *MockClientMockRecorder) InsertBot(ctx, bot, jobResources interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBot", reflect.TypeOf((*MockClient)(nil).InsertBot), ctx, bot, jobResources)
@@ -2042,6 +2804,36 @@ func (mr *MockClientMockRecorder) InsertClient(ctx, client interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertClient", reflect.TypeOf((*MockClient)(nil).InsertClient), ctx, client)
 }
 
+// InsertClientSecret mocks base method.
+func (m *MockClient) InsertClientSecret(ctx context.Context, secret api.ClientSecret, secretHash string) (*api.ClientSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertClientSecret", ctx, secret, secretHash)
+	ret0, _ := ret[0].(*api.ClientSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertClientSecret indicates an expected call of InsertClientSecret.
+func (mr *MockClientMockRecorder) InsertClientSecret(ctx, secret, secretHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertClientSecret", reflect.TypeOf((*MockClient)(nil).InsertClientSecret), ctx, secret, secretHash)
+}
+
+// InsertCustomRole mocks base method.
+func (m *MockClient) InsertCustomRole(ctx context.Context, customRole api.CustomRole) (*api.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCustomRole", ctx, customRole)
+	ret0, _ := ret[0].(*api.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCustomRole indicates an expected call of InsertCustomRole.
+func (mr *MockClientMockRecorder) InsertCustomRole(ctx, customRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCustomRole", reflect.TypeOf((*MockClient)(nil).InsertCustomRole), ctx, customRole)
+}
+
 // InsertGroup mocks base method.
 func (m *MockClient) InsertGroup(ctx context.Context, group ziplinee_ci_contracts.Group) (*ziplinee_ci_contracts.Group, error) {
 	m.ctrl.T.Helper()
@@ -2072,6 +2864,21 @@ func (mr *MockClientMockRecorder) InsertNotification(ctx, notificationRecord int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotification", reflect.TypeOf((*MockClient)(nil).InsertNotification), ctx, notificationRecord)
 }
 
+// InsertNotificationDelivery mocks base method.
+func (m *MockClient) InsertNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (*NotificationDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNotificationDelivery", ctx, delivery)
+	ret0, _ := ret[0].(*NotificationDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertNotificationDelivery indicates an expected call of InsertNotificationDelivery.
+func (mr *MockClientMockRecorder) InsertNotificationDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotificationDelivery", reflect.TypeOf((*MockClient)(nil).InsertNotificationDelivery), ctx, delivery)
+}
+
 // InsertOrganization mocks base method.
 func (m *MockClient) InsertOrganization(ctx context.Context, organization ziplinee_ci_contracts.Organization) (*ziplinee_ci_contracts.Organization, error) {
 	m.ctrl.T.Helper()
@@ -2087,6 +2894,36 @@ func (mr *MockClientMockRecorder) InsertOrganization(ctx, organization interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOrganization", reflect.TypeOf((*MockClient)(nil).InsertOrganization), ctx, organization)
 }
 
+// InsertPersonalAccessToken mocks base method.
+func (m *MockClient) InsertPersonalAccessToken(ctx context.Context, token api.PersonalAccessToken, tokenHash string) (*api.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPersonalAccessToken", ctx, token, tokenHash)
+	ret0, _ := ret[0].(*api.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertPersonalAccessToken indicates an expected call of InsertPersonalAccessToken.
+func (mr *MockClientMockRecorder) InsertPersonalAccessToken(ctx, token, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPersonalAccessToken", reflect.TypeOf((*MockClient)(nil).InsertPersonalAccessToken), ctx, token, tokenHash)
+}
+
+// InsertQueuedJob mocks base method.
+func (m *MockClient) InsertQueuedJob(ctx context.Context, queuedJob QueuedJob) (*QueuedJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertQueuedJob", ctx, queuedJob)
+	ret0, _ := ret[0].(*QueuedJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertQueuedJob indicates an expected call of InsertQueuedJob.
+func (mr *MockClientMockRecorder) InsertQueuedJob(ctx, queuedJob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertQueuedJob", reflect.TypeOf((*MockClient)(nil).InsertQueuedJob), ctx, queuedJob)
+}
+
 // InsertRelease mocks base method.
 func (m *MockClient) InsertRelease(ctx context.Context, release ziplinee_ci_contracts.Release, jobResources JobResources) (*ziplinee_ci_contracts.Release, error) {
 	m.ctrl.T.Helper()
@@ -2117,6 +2954,20 @@ func (mr *MockClientMockRecorder) InsertReleaseLog(ctx, releaseLog interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReleaseLog", reflect.TypeOf((*MockClient)(nil).InsertReleaseLog), ctx, releaseLog)
 }
 
+// InsertSession mocks base method.
+func (m *MockClient) InsertSession(ctx context.Context, session api.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSession", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSession indicates an expected call of InsertSession.
+func (mr *MockClientMockRecorder) InsertSession(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSession", reflect.TypeOf((*MockClient)(nil).InsertSession), ctx, session)
+}
+
 // InsertUser mocks base method.
 func (m *MockClient) InsertUser(ctx context.Context, user ziplinee_ci_contracts.User) (*ziplinee_ci_contracts.User, error) {
 	m.ctrl.T.Helper()
@@ -2132,6 +2983,196 @@ func (mr *MockClientMockRecorder) InsertUser(ctx, user interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockClient)(nil).InsertUser), ctx, user)
 }
 
+// InsertWebhookDelivery mocks base method.
+func (m *MockClient) InsertWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (*WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(*WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookDelivery indicates an expected call of InsertWebhookDelivery.
+func (mr *MockClientMockRecorder) InsertWebhookDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDelivery", reflect.TypeOf((*MockClient)(nil).InsertWebhookDelivery), ctx, delivery)
+}
+
+// MigrateBuildLogs mocks base method.
+func (m *MockClient) MigrateBuildLogs(ctx context.Context, task MigrationTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateBuildLogs", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MigrateBuildLogs indicates an expected call of MigrateBuildLogs.
+func (mr *MockClientMockRecorder) MigrateBuildLogs(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateBuildLogs", reflect.TypeOf((*MockClient)(nil).MigrateBuildLogs), ctx, task)
+}
+
+// MigrateBuildVersions mocks base method.
+func (m *MockClient) MigrateBuildVersions(ctx context.Context, task MigrationTask, shortFromRepoSource, shortToRepoSource string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateBuildVersions", ctx, task, shortFromRepoSource, shortToRepoSource)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MigrateBuildVersions indicates an expected call of MigrateBuildVersions.
+func (mr *MockClientMockRecorder) MigrateBuildVersions(ctx, task, shortFromRepoSource, shortToRepoSource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateBuildVersions", reflect.TypeOf((*MockClient)(nil).MigrateBuildVersions), ctx, task, shortFromRepoSource, shortToRepoSource)
+}
+
+// MigrateBuilds mocks base method.
+func (m *MockClient) MigrateBuilds(ctx context.Context, task MigrationTask) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateBuilds", ctx, task)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrateBuilds indicates an expected call of MigrateBuilds.
+func (mr *MockClientMockRecorder) MigrateBuilds(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateBuilds", reflect.TypeOf((*MockClient)(nil).MigrateBuilds), ctx, task)
+}
+
+// MigrateComputedTables mocks base method.
+func (m *MockClient) MigrateComputedTables(ctx context.Context, task MigrationTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateComputedTables", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MigrateComputedTables indicates an expected call of MigrateComputedTables.
+func (mr *MockClientMockRecorder) MigrateComputedTables(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateComputedTables", reflect.TypeOf((*MockClient)(nil).MigrateComputedTables), ctx, task)
+}
+
+// MigrateReleaseLogs mocks base method.
+func (m *MockClient) MigrateReleaseLogs(ctx context.Context, task MigrationTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateReleaseLogs", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MigrateReleaseLogs indicates an expected call of MigrateReleaseLogs.
+func (mr *MockClientMockRecorder) MigrateReleaseLogs(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateReleaseLogs", reflect.TypeOf((*MockClient)(nil).MigrateReleaseLogs), ctx, task)
+}
+
+// MigrateReleases mocks base method.
+func (m *MockClient) MigrateReleases(ctx context.Context, task MigrationTask) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateReleases", ctx, task)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrateReleases indicates an expected call of MigrateReleases.
+func (mr *MockClientMockRecorder) MigrateReleases(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateReleases", reflect.TypeOf((*MockClient)(nil).MigrateReleases), ctx, task)
+}
+
+// PickLogReconciliation mocks base method.
+func (m *MockClient) PickLogReconciliation(ctx context.Context, staleBefore time.Time) (*LogReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickLogReconciliation", ctx, staleBefore)
+	ret0, _ := ret[0].(*LogReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PickLogReconciliation indicates an expected call of PickLogReconciliation.
+func (mr *MockClientMockRecorder) PickLogReconciliation(ctx, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickLogReconciliation", reflect.TypeOf((*MockClient)(nil).PickLogReconciliation), ctx, staleBefore)
+}
+
+// PickMigrationTasks mocks base method.
+func (m *MockClient) PickMigrationTasks(ctx context.Context, maxTasks int) ([]*MigrationTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickMigrationTasks", ctx, maxTasks)
+	ret0, _ := ret[0].([]*MigrationTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PickMigrationTasks indicates an expected call of PickMigrationTasks.
+func (mr *MockClientMockRecorder) PickMigrationTasks(ctx, maxTasks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickMigrationTasks", reflect.TypeOf((*MockClient)(nil).PickMigrationTasks), ctx, maxTasks)
+}
+
+// PickNotificationDeliveries mocks base method.
+func (m *MockClient) PickNotificationDeliveries(ctx context.Context, limit int, staleBefore time.Time) ([]*NotificationDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickNotificationDeliveries", ctx, limit, staleBefore)
+	ret0, _ := ret[0].([]*NotificationDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PickNotificationDeliveries indicates an expected call of PickNotificationDeliveries.
+func (mr *MockClientMockRecorder) PickNotificationDeliveries(ctx, limit, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickNotificationDeliveries", reflect.TypeOf((*MockClient)(nil).PickNotificationDeliveries), ctx, limit, staleBefore)
+}
+
+// QueueLogReconciliation mocks base method.
+func (m *MockClient) QueueLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (*LogReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueLogReconciliation", ctx, reconciliation)
+	ret0, _ := ret[0].(*LogReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueLogReconciliation indicates an expected call of QueueLogReconciliation.
+func (mr *MockClientMockRecorder) QueueLogReconciliation(ctx, reconciliation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueLogReconciliation", reflect.TypeOf((*MockClient)(nil).QueueLogReconciliation), ctx, reconciliation)
+}
+
+// QueueMigrationTask mocks base method.
+func (m *MockClient) QueueMigrationTask(ctx context.Context, task MigrationTask) (*MigrationTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueMigrationTask", ctx, task)
+	ret0, _ := ret[0].(*MigrationTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueMigrationTask indicates an expected call of QueueMigrationTask.
+func (mr *MockClientMockRecorder) QueueMigrationTask(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueMigrationTask", reflect.TypeOf((*MockClient)(nil).QueueMigrationTask), ctx, task)
+}
+
+// ReleaseLock mocks base method.
+func (m *MockClient) ReleaseLock(ctx context.Context, name, holder string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLock", ctx, name, holder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLock indicates an expected call of ReleaseLock.
+func (mr *MockClientMockRecorder) ReleaseLock(ctx, name, holder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLock", reflect.TypeOf((*MockClient)(nil).ReleaseLock), ctx, name, holder)
+}
+
 // Rename mocks base method.
 func (m *MockClient) Rename(ctx context.Context, shortFromRepoSource, fromRepoSource, fromRepoOwner, fromRepoName, shortToRepoSource, toRepoSource, toRepoOwner, toRepoName string) error {
 	m.ctrl.T.Helper()
@@ -2244,6 +3285,78 @@ func (mr *MockClientMockRecorder) RenameReleases(ctx, fromRepoSource, fromRepoOw
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameReleases", reflect.TypeOf((*MockClient)(nil).RenameReleases), ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName)
 }
 
+// RequeueJob mocks base method.
+func (m *MockClient) RequeueJob(ctx context.Context, queuedJob QueuedJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueJob", ctx, queuedJob)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueJob indicates an expected call of RequeueJob.
+func (mr *MockClientMockRecorder) RequeueJob(ctx, queuedJob interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueJob", reflect.TypeOf((*MockClient)(nil).RequeueJob), ctx, queuedJob)
+}
+
+// RevokeSession mocks base method.
+func (m *MockClient) RevokeSession(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockClientMockRecorder) RevokeSession(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockClient)(nil).RevokeSession), ctx, id)
+}
+
+// RevokeSessionsForSubject mocks base method.
+func (m *MockClient) RevokeSessionsForSubject(ctx context.Context, subjectID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionsForSubject", ctx, subjectID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessionsForSubject indicates an expected call of RevokeSessionsForSubject.
+func (mr *MockClientMockRecorder) RevokeSessionsForSubject(ctx, subjectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionsForSubject", reflect.TypeOf((*MockClient)(nil).RevokeSessionsForSubject), ctx, subjectID)
+}
+
+// RollbackMigration mocks base method.
+func (m *MockClient) RollbackMigration(ctx context.Context, task MigrationTask, shortToRepoSource string) (*MigrationRollback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackMigration", ctx, task, shortToRepoSource)
+	ret0, _ := ret[0].(*MigrationRollback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackMigration indicates an expected call of RollbackMigration.
+func (mr *MockClientMockRecorder) RollbackMigration(ctx, task, shortToRepoSource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackMigration", reflect.TypeOf((*MockClient)(nil).RollbackMigration), ctx, task, shortToRepoSource)
+}
+
+// RollbackMigrationStep mocks base method.
+func (m *MockClient) RollbackMigrationStep(ctx context.Context, task MigrationTask, step MigrationStep, shortToRepoSource string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackMigrationStep", ctx, task, step, shortToRepoSource)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackMigrationStep indicates an expected call of RollbackMigrationStep.
+func (mr *MockClientMockRecorder) RollbackMigrationStep(ctx, task, step, shortToRepoSource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackMigrationStep", reflect.TypeOf((*MockClient)(nil).RollbackMigrationStep), ctx, task, step, shortToRepoSource)
+}
+
 // UnarchiveComputedPipeline mocks base method.
 func (m *MockClient) UnarchiveComputedPipeline(ctx context.Context, repoSource, repoOwner, repoName string) error {
 	m.ctrl.T.Helper()
@@ -2314,6 +3427,20 @@ func (mr *MockClientMockRecorder) UpdateBuildStatus(ctx, repoSource, repoOwner,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBuildStatus", reflect.TypeOf((*MockClient)(nil).UpdateBuildStatus), ctx, repoSource, repoOwner, repoName, buildID, buildStatus)
 }
 
+// UpdateBuildStatusReason mocks base method.
+func (m *MockClient) UpdateBuildStatusReason(ctx context.Context, repoSource, repoOwner, repoName, buildID, statusReason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBuildStatusReason", ctx, repoSource, repoOwner, repoName, buildID, statusReason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBuildStatusReason indicates an expected call of UpdateBuildStatusReason.
+func (mr *MockClientMockRecorder) UpdateBuildStatusReason(ctx, repoSource, repoOwner, repoName, buildID, statusReason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBuildStatusReason", reflect.TypeOf((*MockClient)(nil).UpdateBuildStatusReason), ctx, repoSource, repoOwner, repoName, buildID, statusReason)
+}
+
 // UpdateCatalogEntity mocks base method.
 func (m *MockClient) UpdateCatalogEntity(ctx context.Context, catalogEntity ziplinee_ci_contracts.CatalogEntity) error {
 	m.ctrl.T.Helper()
@@ -2342,6 +3469,20 @@ func (mr *MockClientMockRecorder) UpdateClient(ctx, client interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClient", reflect.TypeOf((*MockClient)(nil).UpdateClient), ctx, client)
 }
 
+// UpdateClientSecretLastUsed mocks base method.
+func (m *MockClient) UpdateClientSecretLastUsed(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateClientSecretLastUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateClientSecretLastUsed indicates an expected call of UpdateClientSecretLastUsed.
+func (mr *MockClientMockRecorder) UpdateClientSecretLastUsed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClientSecretLastUsed", reflect.TypeOf((*MockClient)(nil).UpdateClientSecretLastUsed), ctx, id)
+}
+
 // UpdateComputedPipelineFirstInsertedAt mocks base method.
 func (m *MockClient) UpdateComputedPipelineFirstInsertedAt(ctx context.Context, repoSource, repoOwner, repoName string) error {
 	m.ctrl.T.Helper()
@@ -2398,6 +3539,20 @@ func (mr *MockClientMockRecorder) UpdateComputedTables(ctx, repoSource, repoOwne
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComputedTables", reflect.TypeOf((*MockClient)(nil).UpdateComputedTables), ctx, repoSource, repoOwner, repoName)
 }
 
+// UpdateCustomRole mocks base method.
+func (m *MockClient) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomRole", ctx, customRole)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCustomRole indicates an expected call of UpdateCustomRole.
+func (mr *MockClientMockRecorder) UpdateCustomRole(ctx, customRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomRole", reflect.TypeOf((*MockClient)(nil).UpdateCustomRole), ctx, customRole)
+}
+
 // UpdateGroup mocks base method.
 func (m *MockClient) UpdateGroup(ctx context.Context, group ziplinee_ci_contracts.Group) error {
 	m.ctrl.T.Helper()
@@ -2412,6 +3567,48 @@ func (mr *MockClientMockRecorder) UpdateGroup(ctx, group interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroup", reflect.TypeOf((*MockClient)(nil).UpdateGroup), ctx, group)
 }
 
+// UpdateLogReconciliation mocks base method.
+func (m *MockClient) UpdateLogReconciliation(ctx context.Context, reconciliation LogReconciliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLogReconciliation", ctx, reconciliation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLogReconciliation indicates an expected call of UpdateLogReconciliation.
+func (mr *MockClientMockRecorder) UpdateLogReconciliation(ctx, reconciliation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogReconciliation", reflect.TypeOf((*MockClient)(nil).UpdateLogReconciliation), ctx, reconciliation)
+}
+
+// UpdateMigrationTask mocks base method.
+func (m *MockClient) UpdateMigrationTask(ctx context.Context, id string, status MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMigrationTask", ctx, id, status, lastStep, builds, releases, duration, errorDetails)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMigrationTask indicates an expected call of UpdateMigrationTask.
+func (mr *MockClientMockRecorder) UpdateMigrationTask(ctx, id, status, lastStep, builds, releases, duration, errorDetails interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMigrationTask", reflect.TypeOf((*MockClient)(nil).UpdateMigrationTask), ctx, id, status, lastStep, builds, releases, duration, errorDetails)
+}
+
+// UpdateNotificationDelivery mocks base method.
+func (m *MockClient) UpdateNotificationDelivery(ctx context.Context, delivery NotificationDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationDelivery indicates an expected call of UpdateNotificationDelivery.
+func (mr *MockClientMockRecorder) UpdateNotificationDelivery(ctx, delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationDelivery", reflect.TypeOf((*MockClient)(nil).UpdateNotificationDelivery), ctx, delivery)
+}
+
 // UpdateOrganization mocks base method.
 func (m *MockClient) UpdateOrganization(ctx context.Context, organization ziplinee_ci_contracts.Organization) error {
 	m.ctrl.T.Helper()
@@ -2426,6 +3623,20 @@ func (mr *MockClientMockRecorder) UpdateOrganization(ctx, organization interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganization", reflect.TypeOf((*MockClient)(nil).UpdateOrganization), ctx, organization)
 }
 
+// UpdatePersonalAccessTokenLastUsed mocks base method.
+func (m *MockClient) UpdatePersonalAccessTokenLastUsed(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePersonalAccessTokenLastUsed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePersonalAccessTokenLastUsed indicates an expected call of UpdatePersonalAccessTokenLastUsed.
+func (mr *MockClientMockRecorder) UpdatePersonalAccessTokenLastUsed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePersonalAccessTokenLastUsed", reflect.TypeOf((*MockClient)(nil).UpdatePersonalAccessTokenLastUsed), ctx, id)
+}
+
 // UpdateReleaseResourceUtilization mocks base method.
 func (m *MockClient) UpdateReleaseResourceUtilization(ctx context.Context, repoSource, repoOwner, repoName, releaseID string, jobResources JobResources) error {
 	m.ctrl.T.Helper()
@@ -2468,6 +3679,20 @@ func (mr *MockClientMockRecorder) UpdateUser(ctx, user interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockClient)(nil).UpdateUser), ctx, user)
 }
 
+// UpdateWebhookDeliveryOutcome mocks base method.
+func (m *MockClient) UpdateWebhookDeliveryOutcome(ctx context.Context, id, outcome string, statusCode int, errorMessage string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDeliveryOutcome", ctx, id, outcome, statusCode, errorMessage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDeliveryOutcome indicates an expected call of UpdateWebhookDeliveryOutcome.
+func (mr *MockClientMockRecorder) UpdateWebhookDeliveryOutcome(ctx, id, outcome, statusCode, errorMessage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDeliveryOutcome", reflect.TypeOf((*MockClient)(nil).UpdateWebhookDeliveryOutcome), ctx, id, outcome, statusCode, errorMessage)
+}
+
 // UpsertComputedPipeline mocks base method.
 func (m *MockClient) UpsertComputedPipeline(ctx context.Context, repoSource, repoOwner, repoName string) error {
 	m.ctrl.T.Helper()
@@ -1181,3 +1181,584 @@ func (c *tracingClient) GetPipelineBotNamesCount(ctx context.Context, repoSource
 
 	return c.Client.GetPipelineBotNamesCount(ctx, repoSource, repoOwner, repoName, filters)
 }
+
+func (c *tracingClient) InsertQueuedJob(ctx context.Context, queuedJob QueuedJob) (qj *QueuedJob, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertQueuedJob"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertQueuedJob(ctx, queuedJob)
+}
+
+func (c *tracingClient) GetQueuedJobs(ctx context.Context, limit int) (queuedJobs []*QueuedJob, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetQueuedJobs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetQueuedJobs(ctx, limit)
+}
+
+func (c *tracingClient) DeleteQueuedJob(ctx context.Context, jobType contracts.JobType, jobID string) (deleted bool, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "DeleteQueuedJob"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.DeleteQueuedJob(ctx, jobType, jobID)
+}
+
+func (c *tracingClient) GetActiveJobs(ctx context.Context) (activeJobs []*ActiveJob, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetActiveJobs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetActiveJobs(ctx)
+}
+
+func (c *tracingClient) UpdateBuildStatusReason(ctx context.Context, repoSource, repoOwner, repoName string, buildID string, statusReason string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdateBuildStatusReason"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdateBuildStatusReason(ctx, repoSource, repoOwner, repoName, buildID, statusReason)
+}
+
+func (c *tracingClient) GetBuildStatusReasons(ctx context.Context, buildIDs []string) (statusReasons map[string]string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetBuildStatusReasons"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetBuildStatusReasons(ctx, buildIDs)
+}
+
+func (c *tracingClient) InsertWebhookDelivery(ctx context.Context, delivery WebhookDelivery) (wd *WebhookDelivery, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertWebhookDelivery"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertWebhookDelivery(ctx, delivery)
+}
+
+func (c *tracingClient) UpdateWebhookDeliveryOutcome(ctx context.Context, id, outcome string, statusCode int, errorMessage string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdateWebhookDeliveryOutcome"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdateWebhookDeliveryOutcome(ctx, id, outcome, statusCode, errorMessage)
+}
+
+func (c *tracingClient) GetWebhookDeliveryByID(ctx context.Context, id string) (delivery *WebhookDelivery, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetWebhookDeliveryByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetWebhookDeliveryByID(ctx, id)
+}
+
+func (c *tracingClient) GetWebhookDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (deliveries []*WebhookDelivery, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetWebhookDeliveries"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetWebhookDeliveries(ctx, pageNumber, pageSize, filters, sortings)
+}
+
+func (c *tracingClient) GetWebhookDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetWebhookDeliveriesCount"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetWebhookDeliveriesCount(ctx, filters)
+}
+
+func (c *tracingClient) QueueMigrationTask(ctx context.Context, task MigrationTask) (queuedTask *MigrationTask, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "QueueMigrationTask"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.QueueMigrationTask(ctx, task)
+}
+
+func (c *tracingClient) PickMigrationTasks(ctx context.Context, maxTasks int) (tasks []*MigrationTask, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "PickMigrationTasks"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.PickMigrationTasks(ctx, maxTasks)
+}
+
+func (c *tracingClient) UpdateMigrationTask(ctx context.Context, id string, status MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdateMigrationTask"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdateMigrationTask(ctx, id, status, lastStep, builds, releases, duration, errorDetails)
+}
+
+func (c *tracingClient) GetMigrationTaskByID(ctx context.Context, id string) (task *MigrationTask, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetMigrationTaskByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetMigrationTaskByID(ctx, id)
+}
+
+func (c *tracingClient) GetMigrationTaskByFromRepo(ctx context.Context, fromSource, fromOwner, fromName string) (task *MigrationTask, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetMigrationTaskByFromRepo"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetMigrationTaskByFromRepo(ctx, fromSource, fromOwner, fromName)
+}
+
+func (c *tracingClient) GetMigrationTasks(ctx context.Context) (tasks []*MigrationTask, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetMigrationTasks"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetMigrationTasks(ctx)
+}
+
+func (c *tracingClient) MigrateReleases(ctx context.Context, task MigrationTask) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "MigrateReleases"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.MigrateReleases(ctx, task)
+}
+
+func (c *tracingClient) MigrateReleaseLogs(ctx context.Context, task MigrationTask) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "MigrateReleaseLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.MigrateReleaseLogs(ctx, task)
+}
+
+func (c *tracingClient) GetMigratedReleaseLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetMigratedReleaseLogIDs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetMigratedReleaseLogIDs(ctx, task)
+}
+
+func (c *tracingClient) MigrateBuilds(ctx context.Context, task MigrationTask) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "MigrateBuilds"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.MigrateBuilds(ctx, task)
+}
+
+func (c *tracingClient) MigrateBuildLogs(ctx context.Context, task MigrationTask) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "MigrateBuildLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.MigrateBuildLogs(ctx, task)
+}
+
+func (c *tracingClient) GetMigratedBuildLogIDs(ctx context.Context, task MigrationTask) (logIDs map[string]string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetMigratedBuildLogIDs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetMigratedBuildLogIDs(ctx, task)
+}
+
+func (c *tracingClient) MigrateBuildVersions(ctx context.Context, task MigrationTask, shortFromRepoSource, shortToRepoSource string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "MigrateBuildVersions"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.MigrateBuildVersions(ctx, task, shortFromRepoSource, shortToRepoSource)
+}
+
+func (c *tracingClient) MigrateComputedTables(ctx context.Context, task MigrationTask) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "MigrateComputedTables"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.MigrateComputedTables(ctx, task)
+}
+
+func (c *tracingClient) RollbackMigrationStep(ctx context.Context, task MigrationTask, step MigrationStep, shortToRepoSource string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "RollbackMigrationStep"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.RollbackMigrationStep(ctx, task, step, shortToRepoSource)
+}
+
+func (c *tracingClient) RollbackMigration(ctx context.Context, task MigrationTask, shortToRepoSource string) (rollback *MigrationRollback, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "RollbackMigration"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.RollbackMigration(ctx, task, shortToRepoSource)
+}
+
+func (c *tracingClient) GetLatestSuccessfulReleaseLogIDs(ctx context.Context, repoSource, repoOwner, repoName string) (logIDs []string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetLatestSuccessfulReleaseLogIDs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetLatestSuccessfulReleaseLogIDs(ctx, repoSource, repoOwner, repoName)
+}
+
+func (c *tracingClient) GetExpiredLogIDs(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string, limit int) (logIDs []string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetExpiredLogIDs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetExpiredLogIDs(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs, limit)
+}
+
+func (c *tracingClient) GetExpiredLogsCount(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string, insertedBefore time.Time, keepLogIDs []string) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetExpiredLogsCount"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetExpiredLogsCount(ctx, jobType, repoSource, repoOwner, repoName, insertedBefore, keepLogIDs)
+}
+
+func (c *tracingClient) DeleteLogsByID(ctx context.Context, jobType contracts.JobType, logIDs []string) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "DeleteLogsByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.DeleteLogsByID(ctx, jobType, logIDs)
+}
+
+func (c *tracingClient) QueueLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (queuedReconciliation *LogReconciliation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "QueueLogReconciliation"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.QueueLogReconciliation(ctx, reconciliation)
+}
+
+func (c *tracingClient) PickLogReconciliation(ctx context.Context, staleBefore time.Time) (reconciliation *LogReconciliation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "PickLogReconciliation"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.PickLogReconciliation(ctx, staleBefore)
+}
+
+func (c *tracingClient) UpdateLogReconciliation(ctx context.Context, reconciliation LogReconciliation) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdateLogReconciliation"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdateLogReconciliation(ctx, reconciliation)
+}
+
+func (c *tracingClient) GetLogReconciliationByID(ctx context.Context, id string) (reconciliation *LogReconciliation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetLogReconciliationByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetLogReconciliationByID(ctx, id)
+}
+
+func (c *tracingClient) GetActiveLogReconciliation(ctx context.Context) (reconciliation *LogReconciliation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetActiveLogReconciliation"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetActiveLogReconciliation(ctx)
+}
+
+func (c *tracingClient) GetLogReconciliations(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (reconciliations []*LogReconciliation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetLogReconciliations"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetLogReconciliations(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *tracingClient) GetLogReconciliationsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetLogReconciliationsCount"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetLogReconciliationsCount(ctx, filters)
+}
+
+func (c *tracingClient) GetLogRecords(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName string) (records []*LogRecord, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetLogRecords"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetLogRecords(ctx, jobType, repoSource, repoOwner, repoName)
+}
+
+func (c *tracingClient) GetGroupByName(ctx context.Context, name string) (group *contracts.Group, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetGroupByName"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetGroupByName(ctx, name)
+}
+
+func (c *tracingClient) InsertAuditLogRecord(ctx context.Context, record AuditLogRecord) (insertedRecord *AuditLogRecord, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertAuditLogRecord"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertAuditLogRecord(ctx, record)
+}
+
+func (c *tracingClient) InsertCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertCustomRole"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertCustomRole(ctx, customRole)
+}
+
+func (c *tracingClient) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdateCustomRole"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdateCustomRole(ctx, customRole)
+}
+
+func (c *tracingClient) DeleteCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "DeleteCustomRole"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.DeleteCustomRole(ctx, customRole)
+}
+
+func (c *tracingClient) GetCustomRoleByID(ctx context.Context, id string) (customRole *api.CustomRole, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetCustomRoleByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetCustomRoleByID(ctx, id)
+}
+
+func (c *tracingClient) GetCustomRoles(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField) (customRoles []*api.CustomRole, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetCustomRoles"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetCustomRoles(ctx, pageNumber, pageSize, filters, sortings)
+}
+
+func (c *tracingClient) GetCustomRolesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetCustomRolesCount"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetCustomRolesCount(ctx, filters)
+}
+
+func (c *tracingClient) GetAuditLogRecords(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (records []*AuditLogRecord, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetAuditLogRecords"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetAuditLogRecords(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *tracingClient) GetAuditLogRecordsCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetAuditLogRecordsCount"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetAuditLogRecordsCount(ctx, filters)
+}
+
+func (c *tracingClient) InsertPersonalAccessToken(ctx context.Context, token api.PersonalAccessToken, tokenHash string) (insertedToken *api.PersonalAccessToken, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertPersonalAccessToken"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertPersonalAccessToken(ctx, token, tokenHash)
+}
+
+func (c *tracingClient) UpdatePersonalAccessTokenLastUsed(ctx context.Context, id string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdatePersonalAccessTokenLastUsed"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdatePersonalAccessTokenLastUsed(ctx, id)
+}
+
+func (c *tracingClient) DeletePersonalAccessToken(ctx context.Context, token api.PersonalAccessToken) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "DeletePersonalAccessToken"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.DeletePersonalAccessToken(ctx, token)
+}
+
+func (c *tracingClient) GetPersonalAccessTokenByID(ctx context.Context, id string) (token *api.PersonalAccessToken, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPersonalAccessTokenByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPersonalAccessTokenByID(ctx, id)
+}
+
+func (c *tracingClient) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (token *api.PersonalAccessToken, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPersonalAccessTokenByHash"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPersonalAccessTokenByHash(ctx, tokenHash)
+}
+
+func (c *tracingClient) GetPersonalAccessTokensForUser(ctx context.Context, userID string) (tokens []*api.PersonalAccessToken, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPersonalAccessTokensForUser"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPersonalAccessTokensForUser(ctx, userID)
+}
+
+func (c *tracingClient) InsertSession(ctx context.Context, session api.Session) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertSession"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertSession(ctx, session)
+}
+
+func (c *tracingClient) GetSessionByID(ctx context.Context, id string) (session *api.Session, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetSessionByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetSessionByID(ctx, id)
+}
+
+func (c *tracingClient) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetActiveSessionsForSubject"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetActiveSessionsForSubject(ctx, subjectID)
+}
+
+func (c *tracingClient) RevokeSession(ctx context.Context, id string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "RevokeSession"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.RevokeSession(ctx, id)
+}
+
+func (c *tracingClient) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "RevokeSessionsForSubject"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.RevokeSessionsForSubject(ctx, subjectID)
+}
+
+func (c *tracingClient) InsertClientSecret(ctx context.Context, secret api.ClientSecret, secretHash string) (insertedSecret *api.ClientSecret, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertClientSecret"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertClientSecret(ctx, secret, secretHash)
+}
+
+func (c *tracingClient) UpdateClientSecretLastUsed(ctx context.Context, id string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdateClientSecretLastUsed"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdateClientSecretLastUsed(ctx, id)
+}
+
+func (c *tracingClient) DeleteClientSecret(ctx context.Context, secret api.ClientSecret) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "DeleteClientSecret"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.DeleteClientSecret(ctx, secret)
+}
+
+func (c *tracingClient) GetClientSecretByHash(ctx context.Context, clientID, secretHash string) (secret *api.ClientSecret, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetClientSecretByHash"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetClientSecretByHash(ctx, clientID, secretHash)
+}
+
+func (c *tracingClient) GetClientSecretsForClient(ctx context.Context, clientID string) (secrets []*api.ClientSecret, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetClientSecretsForClient"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetClientSecretsForClient(ctx, clientID)
+}
+
+func (c *tracingClient) InsertNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (insertedDelivery *NotificationDelivery, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "InsertNotificationDelivery"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.InsertNotificationDelivery(ctx, delivery)
+}
+
+func (c *tracingClient) PickNotificationDeliveries(ctx context.Context, limit int, staleBefore time.Time) (deliveries []*NotificationDelivery, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "PickNotificationDeliveries"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.PickNotificationDeliveries(ctx, limit, staleBefore)
+}
+
+func (c *tracingClient) UpdateNotificationDelivery(ctx context.Context, delivery NotificationDelivery) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "UpdateNotificationDelivery"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.UpdateNotificationDelivery(ctx, delivery)
+}
+
+func (c *tracingClient) GetNotificationDeliveryByID(ctx context.Context, id string) (delivery *NotificationDelivery, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetNotificationDeliveryByID"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetNotificationDeliveryByID(ctx, id)
+}
+
+func (c *tracingClient) GetNotificationDeliveries(ctx context.Context, pageNumber, pageSize int, filters map[api.FilterType][]string) (deliveries []*NotificationDelivery, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetNotificationDeliveries"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetNotificationDeliveries(ctx, pageNumber, pageSize, filters)
+}
+
+func (c *tracingClient) GetNotificationDeliveriesCount(ctx context.Context, filters map[api.FilterType][]string) (count int, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetNotificationDeliveriesCount"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetNotificationDeliveriesCount(ctx, filters)
+}
+
+func (c *tracingClient) GetUserByEmail(ctx context.Context, email string) (user *contracts.User, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetUserByEmail"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetUserByEmail(ctx, email)
+}
+
+func (c *tracingClient) GetPipelineBuildResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, repoBranch string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPipelineBuildResourceUsages"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPipelineBuildResourceUsages(ctx, repoSource, repoOwner, repoName, repoBranch, lastNRecords)
+}
+
+func (c *tracingClient) GetPipelineReleaseResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, targetName, actionName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPipelineReleaseResourceUsages"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPipelineReleaseResourceUsages(ctx, repoSource, repoOwner, repoName, targetName, actionName, lastNRecords)
+}
+
+func (c *tracingClient) GetPipelineBotResourceUsages(ctx context.Context, repoSource, repoOwner, repoName, botName string, lastNRecords int) (usages []*JobResourceUsage, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetPipelineBotResourceUsages"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetPipelineBotResourceUsages(ctx, repoSource, repoOwner, repoName, botName, lastNRecords)
+}
+
+func (c *tracingClient) GetJobResourceUtilizations(ctx context.Context, jobType contracts.JobType, from, to time.Time) (utilizations []*JobResourceUtilization, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetJobResourceUtilizations"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetJobResourceUtilizations(ctx, jobType, from, to)
+}
+
+func (c *tracingClient) GetReleaseOutcomes(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (outcomes []*ReleaseOutcome, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetReleaseOutcomes"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetReleaseOutcomes(ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
+
+func (c *tracingClient) ClaimQueuedJob(ctx context.Context, queuedJob QueuedJob) (claimed bool, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "ClaimQueuedJob"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.ClaimQueuedJob(ctx, queuedJob)
+}
+
+func (c *tracingClient) RequeueJob(ctx context.Context, queuedJob QueuedJob) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "RequeueJob"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.RequeueJob(ctx, queuedJob)
+}
+
+func (c *tracingClient) AcquireLock(ctx context.Context, name, holder string, ttl time.Duration) (acquired bool, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "AcquireLock"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.AcquireLock(ctx, name, holder, ttl)
+}
+
+func (c *tracingClient) ReleaseLock(ctx context.Context, name, holder string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "ReleaseLock"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.ReleaseLock(ctx, name, holder)
+}
+
+func (c *tracingClient) GetJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string) (jobResources JobResources, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetJobResources"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetJobResources(ctx, jobType, repoSource, repoOwner, repoName, jobID)
+}
+
+func (c *tracingClient) HashLegacyClientSecret(ctx context.Context, client contracts.Client, secretHash string) (hashed bool, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "HashLegacyClientSecret"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.HashLegacyClientSecret(ctx, client, secretHash)
+}
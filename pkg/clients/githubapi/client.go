@@ -27,6 +27,16 @@ import (
 
 var (
 	ErrMissingInstallation = errors.New("installation is missing")
+	ErrUnknownApp          = errors.New("app is unknown")
+)
+
+const (
+	// maxPreviousWebhookSecrets is the number of replaced webhook secrets kept to verify deliveries signed before a rotation
+	maxPreviousWebhookSecrets = 2
+
+	// previousWebhookSecretTTL is how long a replaced webhook secret keeps being accepted, long enough for github to redeliver
+	// deliveries signed before the rotation
+	previousWebhookSecretTTL = 72 * time.Hour
 )
 
 // Client is the interface for communicating with the github api
@@ -467,7 +477,7 @@ func (c *client) GetAppByID(ctx context.Context, id int) (app *GithubApp, err er
 		}
 	}
 
-	return nil, fmt.Errorf("App for id %v is unknown", id)
+	return nil, fmt.Errorf("App for id %v: %w", id, ErrUnknownApp)
 }
 
 func (c *client) AddApp(ctx context.Context, app GithubApp) (err error) {
@@ -488,6 +498,9 @@ func (c *client) AddApp(ctx context.Context, app GithubApp) (err error) {
 			appExists = true
 
 			ap.PrivateKey = app.PrivateKey
+			if ap.WebhookSecret != app.WebhookSecret {
+				ap.PreviousWebhookSecrets = rotateWebhookSecrets(ap.WebhookSecret, ap.PreviousWebhookSecrets, time.Now().UTC())
+			}
 			ap.WebhookSecret = app.WebhookSecret
 		}
 	}
@@ -681,6 +694,16 @@ func (c *client) encryptAppSecrets(ctx context.Context, apps []*GithubApp) (err
 			app.WebhookSecret = encryptedWebhookSecret
 		}
 
+		for _, previousWebhookSecret := range app.PreviousWebhookSecrets {
+			if !c.secretHelper.IsEncryptedEnvelope(previousWebhookSecret.Secret) {
+				encryptedWebhookSecret, encryptErr := c.secretHelper.EncryptEnvelope(previousWebhookSecret.Secret, crypt.DefaultPipelineAllowList)
+				if encryptErr != nil {
+					return encryptErr
+				}
+				previousWebhookSecret.Secret = encryptedWebhookSecret
+			}
+		}
+
 		if !c.secretHelper.IsEncryptedEnvelope(app.ClientSecret) {
 			encryptedClientSecret, encryptErr := c.secretHelper.EncryptEnvelope(app.ClientSecret, crypt.DefaultPipelineAllowList)
 			if encryptErr != nil {
@@ -707,6 +730,14 @@ func (c *client) decryptAppSecrets(ctx context.Context, apps []*GithubApp) (err
 		}
 		app.WebhookSecret = decryptedWebhookSecret
 
+		for _, previousWebhookSecret := range app.PreviousWebhookSecrets {
+			decryptedWebhookSecret, _, decryptErr := c.secretHelper.DecryptEnvelope(previousWebhookSecret.Secret, "")
+			if decryptErr != nil {
+				return decryptErr
+			}
+			previousWebhookSecret.Secret = decryptedWebhookSecret
+		}
+
 		decryptedClientSecret, _, decryptErr := c.secretHelper.DecryptEnvelope(app.ClientSecret, "")
 		if decryptErr != nil {
 			return decryptErr
@@ -715,3 +746,25 @@ func (c *client) decryptAppSecrets(ctx context.Context, apps []*GithubApp) (err
 	}
 	return nil
 }
+
+// rotateWebhookSecrets prepends the replaced webhook secret to the previous secrets and only keeps the most recent ones that haven't expired
+func rotateWebhookSecrets(replacedWebhookSecret string, previousWebhookSecrets []*GithubPreviousWebhookSecret, now time.Time) []*GithubPreviousWebhookSecret {
+	rotated := []*GithubPreviousWebhookSecret{}
+	if replacedWebhookSecret != "" {
+		rotated = append(rotated, &GithubPreviousWebhookSecret{
+			Secret:    replacedWebhookSecret,
+			ExpiresAt: now.Add(previousWebhookSecretTTL),
+		})
+	}
+	for _, s := range previousWebhookSecrets {
+		if s != nil && !s.IsExpired(now) {
+			rotated = append(rotated, s)
+		}
+	}
+
+	if len(rotated) > maxPreviousWebhookSecrets {
+		rotated = rotated[:maxPreviousWebhookSecrets]
+	}
+
+	return rotated
+}
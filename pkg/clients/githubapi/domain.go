@@ -3,6 +3,7 @@ package githubapi
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 )
@@ -203,4 +204,31 @@ type GithubApp struct {
 	ClientID      string                `json:"client_id"`
 	ClientSecret  string                `json:"client_secret"`
 	Installations []*GithubInstallation `json:"installations"`
+
+	// PreviousWebhookSecrets are still accepted until they expire when verifying webhook signatures, so deliveries signed before a secret
+	// rotation don't get rejected
+	PreviousWebhookSecrets []*GithubPreviousWebhookSecret `json:"previous_webhook_secrets,omitempty"`
+}
+
+// GithubPreviousWebhookSecret is a webhook secret replaced by a rotation
+type GithubPreviousWebhookSecret struct {
+	Secret    string    `json:"secret"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IsExpired returns true if a previous webhook secret no longer validates deliveries
+func (s *GithubPreviousWebhookSecret) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// GetWebhookSecrets returns the current webhook secret and the previous ones that haven't expired yet
+func (a *GithubApp) GetWebhookSecrets(now time.Time) (webhookSecrets []string) {
+	webhookSecrets = append(webhookSecrets, a.WebhookSecret)
+	for _, s := range a.PreviousWebhookSecrets {
+		if s != nil && !s.IsExpired(now) {
+			webhookSecrets = append(webhookSecrets, s.Secret)
+		}
+	}
+
+	return webhookSecrets
 }
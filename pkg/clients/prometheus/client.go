@@ -3,7 +3,9 @@ package prometheus
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"math"
 	"time"
 
 	"fmt"
@@ -23,8 +25,13 @@ type Client interface {
 	AwaitScrapeInterval(ctx context.Context)
 	GetMaxMemoryByPodName(ctx context.Context, podName string) (max float64, err error)
 	GetMaxCPUByPodName(ctx context.Context, podName string) (max float64, err error)
+	GetOOMKilledByPodName(ctx context.Context, podName string) (oomKilled bool, err error)
+	GetCPUThrottledRatioByPodName(ctx context.Context, podName string) (ratio float64, err error)
 }
 
+// ErrNoQueryResult is returned when a query has no series matching it
+var ErrNoQueryResult = errors.New("Query has no result")
+
 // NewClient creates an prometheus.Client to communicate with Prometheus
 func NewClient(config *api.APIConfig) Client {
 	if config == nil || config.Integrations == nil || config.Integrations.Prometheus == nil || config.Integrations.Prometheus.Enable == nil || !*config.Integrations.Prometheus.Enable {
@@ -83,6 +90,50 @@ func (c *client) GetMaxCPUByPodName(ctx context.Context, podName string) (maxCPU
 	return
 }
 
+// GetOOMKilledByPodName returns true if the builder container of a pod got killed for running out of memory
+func (c *client) GetOOMKilledByPodName(ctx context.Context, podName string) (oomKilled bool, err error) {
+	if !c.enabled {
+		return
+	}
+
+	query := fmt.Sprintf("max_over_time(kube_pod_container_status_last_terminated_reason{container=\"ziplinee-ci-builder\",pod=\"%v\",reason=\"OOMKilled\"}[6h])", podName)
+
+	// the series only exists once the container got killed, so no result means it didn't
+	result, err := c.getQueryResult(query)
+	if errors.Is(err, ErrNoQueryResult) {
+		return false, nil
+	}
+	if err != nil {
+		return
+	}
+
+	return result > 0, nil
+}
+
+// GetCPUThrottledRatioByPodName returns the fraction of cpu periods in which the builder container of a pod got throttled
+func (c *client) GetCPUThrottledRatioByPodName(ctx context.Context, podName string) (ratio float64, err error) {
+	if !c.enabled {
+		return
+	}
+
+	query := fmt.Sprintf("sum(increase(container_cpu_cfs_throttled_periods_total{container=\"ziplinee-ci-builder\",pod=\"%v\"}[6h])) / sum(increase(container_cpu_cfs_periods_total{container=\"ziplinee-ci-builder\",pod=\"%v\"}[6h]))", podName, podName)
+
+	ratio, err = c.getQueryResult(query)
+	if errors.Is(err, ErrNoQueryResult) {
+		return 0, nil
+	}
+	if err != nil {
+		return
+	}
+
+	// a container without cpu limit has no cfs periods
+	if math.IsNaN(ratio) {
+		return 0, nil
+	}
+
+	return ratio, nil
+}
+
 func (c *client) getQueryResult(query string) (float64, error) {
 
 	prometheusQueryURL := fmt.Sprintf("%v/api/v1/query?query=%v", c.config.Integrations.Prometheus.ServerURL, url.QueryEscape(query))
@@ -111,6 +162,10 @@ func (c *client) getQueryResult(query string) (float64, error) {
 		return 0, fmt.Errorf("Query response data result type %v for query url %v not equal to 'vector'", queryResponse.Data.ResultType, prometheusQueryURL)
 	}
 
+	if len(queryResponse.Data.Result) == 0 {
+		return 0, fmt.Errorf("Query response data vector for query url %v is empty: %w", prometheusQueryURL, ErrNoQueryResult)
+	}
+
 	if len(queryResponse.Data.Result) != 1 {
 		return 0, fmt.Errorf("Query response data vector length %v for query url %v not equal to 1", len(queryResponse.Data.Result), prometheusQueryURL)
 	}
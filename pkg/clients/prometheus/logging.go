@@ -31,3 +31,15 @@ func (c *loggingClient) GetMaxCPUByPodName(ctx context.Context, podName string)
 
 	return c.Client.GetMaxCPUByPodName(ctx, podName)
 }
+
+func (c *loggingClient) GetOOMKilledByPodName(ctx context.Context, podName string) (oomKilled bool, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetOOMKilledByPodName", err) }()
+
+	return c.Client.GetOOMKilledByPodName(ctx, podName)
+}
+
+func (c *loggingClient) GetCPUThrottledRatioByPodName(ctx context.Context, podName string) (ratio float64, err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "GetCPUThrottledRatioByPodName", err) }()
+
+	return c.Client.GetCPUThrottledRatioByPodName(ctx, podName)
+}
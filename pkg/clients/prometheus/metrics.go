@@ -42,3 +42,19 @@ func (c *metricsClient) GetMaxCPUByPodName(ctx context.Context, podName string)
 
 	return c.Client.GetMaxCPUByPodName(ctx, podName)
 }
+
+func (c *metricsClient) GetOOMKilledByPodName(ctx context.Context, podName string) (oomKilled bool, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetOOMKilledByPodName", begin)
+	}(time.Now())
+
+	return c.Client.GetOOMKilledByPodName(ctx, podName)
+}
+
+func (c *metricsClient) GetCPUThrottledRatioByPodName(ctx context.Context, podName string) (ratio float64, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "GetCPUThrottledRatioByPodName", begin)
+	}(time.Now())
+
+	return c.Client.GetCPUThrottledRatioByPodName(ctx, podName)
+}
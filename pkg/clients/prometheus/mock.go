@@ -46,6 +46,21 @@ func (mr *MockClientMockRecorder) AwaitScrapeInterval(ctx interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwaitScrapeInterval", reflect.TypeOf((*MockClient)(nil).AwaitScrapeInterval), ctx)
 }
 
+// GetCPUThrottledRatioByPodName mocks base method.
+func (m *MockClient) GetCPUThrottledRatioByPodName(ctx context.Context, podName string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCPUThrottledRatioByPodName", ctx, podName)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCPUThrottledRatioByPodName indicates an expected call of GetCPUThrottledRatioByPodName.
+func (mr *MockClientMockRecorder) GetCPUThrottledRatioByPodName(ctx, podName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCPUThrottledRatioByPodName", reflect.TypeOf((*MockClient)(nil).GetCPUThrottledRatioByPodName), ctx, podName)
+}
+
 // GetMaxCPUByPodName mocks base method.
 func (m *MockClient) GetMaxCPUByPodName(ctx context.Context, podName string) (float64, error) {
 	m.ctrl.T.Helper()
@@ -75,3 +90,18 @@ func (mr *MockClientMockRecorder) GetMaxMemoryByPodName(ctx, podName interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxMemoryByPodName", reflect.TypeOf((*MockClient)(nil).GetMaxMemoryByPodName), ctx, podName)
 }
+
+// GetOOMKilledByPodName mocks base method.
+func (m *MockClient) GetOOMKilledByPodName(ctx context.Context, podName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOOMKilledByPodName", ctx, podName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOOMKilledByPodName indicates an expected call of GetOOMKilledByPodName.
+func (mr *MockClientMockRecorder) GetOOMKilledByPodName(ctx, podName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOOMKilledByPodName", reflect.TypeOf((*MockClient)(nil).GetOOMKilledByPodName), ctx, podName)
+}
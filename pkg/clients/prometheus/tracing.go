@@ -37,3 +37,17 @@ func (c *tracingClient) GetMaxCPUByPodName(ctx context.Context, podName string)
 
 	return c.Client.GetMaxCPUByPodName(ctx, podName)
 }
+
+func (c *tracingClient) GetOOMKilledByPodName(ctx context.Context, podName string) (oomKilled bool, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetOOMKilledByPodName"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetOOMKilledByPodName(ctx, podName)
+}
+
+func (c *tracingClient) GetCPUThrottledRatioByPodName(ctx context.Context, podName string) (ratio float64, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "GetCPUThrottledRatioByPodName"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.GetCPUThrottledRatioByPodName(ctx, podName)
+}
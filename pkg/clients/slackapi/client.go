@@ -1,6 +1,7 @@
 package slackapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 //go:generate mockgen -package=slackapi -destination ./mock.go -source=client.go
 type Client interface {
 	GetUserProfile(ctx context.Context, userID string) (profile *UserProfile, err error)
+	PostResponse(ctx context.Context, responseURL string, message Message) (err error)
 }
 
 // NewClient returns a slack.Client to communicate with the Slack API
@@ -92,5 +94,58 @@ func (c *client) GetUserProfile(ctx context.Context, userID string) (profile *Us
 		return
 	}
 
+	if !profileResponse.OK || profileResponse.Profile == nil {
+		return nil, fmt.Errorf("Retrieving Slack user profile for user %v failed: %v", userID, profileResponse.Error)
+	}
+
 	return profileResponse.Profile, nil
 }
+
+// PostResponse posts a message to the response url of a command or interaction, to reply after the request itself has been answered
+func (c *client) PostResponse(ctx context.Context, responseURL string, message Message) (err error) {
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	// create client, in order to add headers
+	client := pester.NewExtendedClient(&http.Client{Transport: &nethttp.Transport{}})
+	client.MaxRetries = 3
+	client.Backoff = pester.ExponentialJitterBackoff
+	client.KeepLog = true
+	client.Timeout = time.Second * 10
+	request, err := http.NewRequest("POST", responseURL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	span := opentracing.SpanFromContext(ctx)
+	var ht *nethttp.Tracer
+	if span != nil {
+		// add tracing context
+		request = request.WithContext(opentracing.ContextWithSpan(request.Context(), span))
+
+		// collect additional information on setting up connections
+		request, ht = nethttp.TraceRequest(span.Tracer(), request)
+	}
+
+	// add headers
+	request.Header.Add("Content-Type", "application/json")
+
+	// perform actual request
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	if ht != nil {
+		ht.Finish()
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Posting Slack response returned status %v", response.StatusCode)
+	}
+
+	return nil
+}
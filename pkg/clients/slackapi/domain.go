@@ -27,3 +27,65 @@ type GetUserProfileResponse struct {
 type UserProfile struct {
 	Email string `json:"email"`
 }
+
+// Message is a Slack message, returned in response to a command or posted to the response url of a command or interaction;
+// see https://api.slack.com/reference/block-kit/blocks
+type Message struct {
+	ResponseType    string   `json:"response_type,omitempty"`
+	ReplaceOriginal bool     `json:"replace_original,omitempty"`
+	Text            string   `json:"text"`
+	Blocks          []*Block `json:"blocks,omitempty"`
+}
+
+// Block is a Block Kit layout block of a message
+type Block struct {
+	Type     string     `json:"type"`
+	Text     *Text      `json:"text,omitempty"`
+	Elements []*Element `json:"elements,omitempty"`
+}
+
+// Text is a plain text or markdown text object
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Element is an interactive Block Kit element like a button or a select menu
+type Element struct {
+	Type        string    `json:"type"`
+	ActionID    string    `json:"action_id,omitempty"`
+	Text        *Text     `json:"text,omitempty"`
+	Value       string    `json:"value,omitempty"`
+	Style       string    `json:"style,omitempty"`
+	Placeholder *Text     `json:"placeholder,omitempty"`
+	Options     []*Option `json:"options,omitempty"`
+}
+
+// Option is one of the options of a select menu
+type Option struct {
+	Text  *Text  `json:"text"`
+	Value string `json:"value"`
+}
+
+// InteractionPayload is sent when a user interacts with an element of a message, in the payload field of a form post;
+// see https://api.slack.com/reference/interaction-payloads/block-actions
+type InteractionPayload struct {
+	Type        string          `json:"type"`
+	Token       string          `json:"token"`
+	User        InteractionUser `json:"user"`
+	ResponseURL string          `json:"response_url"`
+	Actions     []*Action       `json:"actions"`
+}
+
+// InteractionUser is the Slack user who interacted with a message
+type InteractionUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// Action is the interaction with a single element of a message
+type Action struct {
+	ActionID       string  `json:"action_id"`
+	Value          string  `json:"value,omitempty"`
+	SelectedOption *Option `json:"selected_option,omitempty"`
+}
@@ -21,3 +21,9 @@ func (c *loggingClient) GetUserProfile(ctx context.Context, userID string) (prof
 
 	return c.Client.GetUserProfile(ctx, userID)
 }
+
+func (c *loggingClient) PostResponse(ctx context.Context, responseURL string, message Message) (err error) {
+	defer func() { api.HandleLogError(c.prefix, "Client", "PostResponse", err) }()
+
+	return c.Client.PostResponse(ctx, responseURL, message)
+}
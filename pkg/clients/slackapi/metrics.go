@@ -26,3 +26,11 @@ func (c *metricsClient) GetUserProfile(ctx context.Context, userID string) (prof
 
 	return c.Client.GetUserProfile(ctx, userID)
 }
+
+func (c *metricsClient) PostResponse(ctx context.Context, responseURL string, message Message) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(c.requestCount, c.requestLatency, "PostResponse", begin)
+	}(time.Now())
+
+	return c.Client.PostResponse(ctx, responseURL, message)
+}
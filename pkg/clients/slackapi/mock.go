@@ -48,3 +48,17 @@ func (mr *MockClientMockRecorder) GetUserProfile(ctx, userID interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProfile", reflect.TypeOf((*MockClient)(nil).GetUserProfile), ctx, userID)
 }
+
+// PostResponse mocks base method.
+func (m *MockClient) PostResponse(ctx context.Context, responseURL string, message Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostResponse", ctx, responseURL, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostResponse indicates an expected call of PostResponse.
+func (mr *MockClientMockRecorder) PostResponse(ctx, responseURL, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostResponse", reflect.TypeOf((*MockClient)(nil).PostResponse), ctx, responseURL, message)
+}
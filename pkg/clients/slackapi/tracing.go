@@ -23,3 +23,10 @@ func (c *tracingClient) GetUserProfile(ctx context.Context, userID string) (prof
 
 	return c.Client.GetUserProfile(ctx, userID)
 }
+
+func (c *tracingClient) PostResponse(ctx context.Context, responseURL string, message Message) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(c.prefix, "PostResponse"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return c.Client.PostResponse(ctx, responseURL, message)
+}
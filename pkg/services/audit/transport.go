@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+)
+
+// NewHandler returns a new audit.Handler
+func NewHandler(config *api.APIConfig, databaseClient database.Client) Handler {
+	return Handler{
+		config:         config,
+		databaseClient: databaseClient,
+	}
+}
+
+type Handler struct {
+	config         *api.APIConfig
+	databaseClient database.Client
+}
+
+// Middleware records every successful request that changes state in the audit log, with the details added by its handler or
+// otherwise derived from its route; requests that don't change state are only recorded if their handler adds audit details
+func (h *Handler) Middleware() gin.HandlerFunc {
+	return h.middleware(true)
+}
+
+// DetailsMiddleware only records the changes its handler adds audit details for, for webhook routes where most requests
+// don't change anything worth recording
+func (h *Handler) DetailsMiddleware() gin.HandlerFunc {
+	return h.middleware(false)
+}
+
+func (h *Handler) middleware(recordRouteDetails bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		requestID := c.GetHeader(api.RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		api.SetRequestID(c, requestID)
+		c.Header(api.RequestIDHeader, requestID)
+
+		c.Next()
+
+		// handlers only add details for changes they've made, which are recorded even if a later change of the same request failed
+		details, hasDetails := api.GetAuditDetails(c)
+		if !hasDetails {
+			if !recordRouteDetails || !isMutatingMethod(c.Request.Method) || c.Writer.Status() >= http.StatusBadRequest {
+				return
+			}
+			details = []api.AuditDetails{api.GetRouteAuditDetails(c)}
+		}
+
+		for _, d := range details {
+			_, err := h.databaseClient.InsertAuditLogRecord(c.Request.Context(), database.AuditLogRecord{
+				Actor:        getActor(c),
+				ClientID:     api.GetClientIDFromRequest(c),
+				Impersonator: api.GetImpersonatorFromRequest(c),
+				Action:       d.Action,
+				TargetType:   d.TargetType,
+				TargetID:     d.TargetID,
+				Before:       d.Before,
+				After:        d.After,
+				RequestID:    requestID,
+			})
+			if err != nil {
+				// the change has already been made, so failing to record it shouldn't fail the request
+				log.Error().Err(err).Msgf("Failed recording %v on %v %v in audit log for request %v", d.Action, d.TargetType, d.TargetID, requestID)
+			}
+		}
+	}
+}
+
+func (h *Handler) GetAuditLogRecords(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionAuditLogList) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	pageNumber, pageSize, filters, _ := api.GetQueryParameters(c)
+
+	ctx := c.Request.Context()
+
+	response, err := api.GetPagedListResponse(
+		func() ([]interface{}, error) {
+			records, err := h.databaseClient.GetAuditLogRecords(ctx, pageNumber, pageSize, filters)
+			if err != nil {
+				return nil, err
+			}
+
+			// convert typed array to interface array O(n)
+			items := make([]interface{}, len(records))
+			for i := range records {
+				items[i] = records[i]
+			}
+			return items, nil
+		},
+		func() (int, error) {
+			return h.databaseClient.GetAuditLogRecordsCount(ctx, filters)
+		},
+		pageNumber,
+		pageSize)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed retrieving audit log records from db")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// getActor returns the email address of the logged in user or client, or for integrations without a jwt the authenticated user set by their middleware
+func getActor(c *gin.Context) string {
+	if email := api.GetEmailFromRequest(c); email != "" {
+		return email
+	}
+
+	return c.GetString(gin.AuthUserKey)
+}
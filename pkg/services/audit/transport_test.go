@@ -0,0 +1,236 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	setClaims := func(c *gin.Context) {
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "admin@server.com",
+			"email":         "admin@server.com",
+		})
+	}
+
+	t.Run("RecordsSuccessfulPostWithRouteDetails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var record database.AuditLogRecord
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, r database.AuditLogRecord) (*database.AuditLogRecord, error) {
+				record = r
+				return &r, nil
+			}).
+			Times(1)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+		recorder := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(recorder)
+		router.POST("/api/pipelines/:source/:owner/:repo/builds", setClaims, handler.Middleware(), func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+		request := httptest.NewRequest(http.MethodPost, "/api/pipelines/github.com/ziplineeci/ziplinee-ci-api/builds", nil)
+		request.Header.Set(api.RequestIDHeader, "abc")
+
+		// act
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, "abc", recorder.Header().Get(api.RequestIDHeader))
+		assert.Equal(t, "admin@server.com", record.Actor)
+		assert.Equal(t, "POST /api/pipelines/:source/:owner/:repo/builds", record.Action)
+		assert.Equal(t, "builds", record.TargetType)
+		assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api", record.TargetID)
+		assert.Equal(t, "abc", record.RequestID)
+	})
+
+	t.Run("RecordsEachChangeAddedByHandler", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		records := []database.AuditLogRecord{}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, r database.AuditLogRecord) (*database.AuditLogRecord, error) {
+				records = append(records, r)
+				return &r, nil
+			}).
+			Times(2)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+		recorder := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(recorder)
+		router.PUT("/api/users", setClaims, handler.Middleware(), func(c *gin.Context) {
+			api.AddAuditDetails(c, "user.updated", "user", "1", nil, nil)
+			api.AddAuditDetails(c, "user.updated", "user", "2", nil, nil)
+			c.Status(http.StatusInternalServerError)
+		})
+
+		// act
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/api/users", nil))
+
+		if assert.Equal(t, 2, len(records)) {
+			assert.Equal(t, "user.updated", records[0].Action)
+			assert.Equal(t, "1", records[0].TargetID)
+			assert.Equal(t, "2", records[1].TargetID)
+			assert.NotEmpty(t, records[0].RequestID)
+			assert.Equal(t, records[0].RequestID, records[1].RequestID)
+		}
+	})
+
+	t.Run("SkipsGetWithoutDetails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+		recorder := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(recorder)
+		router.GET("/api/users", setClaims, handler.Middleware(), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		// act
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NotEmpty(t, recorder.Header().Get(api.RequestIDHeader))
+	})
+
+	t.Run("SkipsFailedPostWithoutDetails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+		recorder := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(recorder)
+		router.POST("/api/users", setClaims, handler.Middleware(), func(c *gin.Context) {
+			c.Status(http.StatusBadRequest)
+		})
+
+		// act
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/users", nil))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestDetailsMiddleware(t *testing.T) {
+
+	t.Run("SkipsSuccessfulPostWithoutDetails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+		recorder := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(recorder)
+		router.POST("/api/integrations/github/events", handler.DetailsMiddleware(), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		// act
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/integrations/github/events", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("RecordsChangeAddedByHandler", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var record database.AuditLogRecord
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, r database.AuditLogRecord) (*database.AuditLogRecord, error) {
+				record = r
+				return &r, nil
+			}).
+			Times(1)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+		recorder := httptest.NewRecorder()
+		_, router := gin.CreateTestContext(recorder)
+		router.POST("/api/integrations/github/events", handler.DetailsMiddleware(), func(c *gin.Context) {
+			api.AddAuditDetails(c, "integration.created", "integration", "github/15", nil, nil)
+			c.Status(http.StatusOK)
+		})
+
+		// act
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/integrations/github/events", nil))
+
+		assert.Equal(t, "integration.created", record.Action)
+		assert.Equal(t, "github/15", record.TargetID)
+	})
+}
+
+func TestGetAuditLogRecords(t *testing.T) {
+
+	t.Run("ReturnsForbiddenWithoutPermission", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetAuditLogRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "someone@server.com",
+			"email":         "someone@server.com",
+			"roles":         []interface{}{api.RoleUserViewer.String()},
+		})
+
+		// act
+		handler.GetAuditLogRecords(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
@@ -21,6 +21,7 @@ var (
 	ErrNonCloneableEvent = errors.New("The event is not cloneable")
 	ErrNoManifest        = errors.New("The repository has no manifest at the pushed commit")
 	ErrOwnerIsEmpty      = errors.New("The owner slug is empty")
+	ErrNotAllowed        = errors.New("The owner is not allowed")
 )
 
 // Service handles http events for Bitbucket integration
@@ -41,6 +41,7 @@ func (h *Handler) Handle(c *gin.Context) {
 	installation, err := h.bitbucketapiClient.ValidateInstallationJWT(c.Request.Context(), authorizationHeader)
 	if err != nil {
 		log.Error().Err(err).Str("authorization", authorizationHeader).Msg("Validating authorization header failed")
+		_ = c.Error(err)
 		c.Status(http.StatusBadRequest)
 		return
 	}
@@ -63,6 +64,7 @@ func (h *Handler) Handle(c *gin.Context) {
 	err = json.Unmarshal(body, &eventCheck)
 	if err != nil {
 		log.Error().Err(err).Str("body", string(body)).Msg("Deserializing body for EventCheck failed")
+		_ = c.Error(err)
 		c.Status(http.StatusBadRequest)
 		return
 	}
@@ -71,6 +73,7 @@ func (h *Handler) Handle(c *gin.Context) {
 	isAllowed, _ := h.service.IsAllowedOwner(c.Request.Context(), eventCheck.GetRepository())
 	if !isAllowed {
 		log.Warn().Interface("event", eventCheck).Str("body", string(body)).Msg("Bitbucket EventCheck owner is not allowed")
+		_ = c.Error(ErrNotAllowed)
 		c.Status(http.StatusUnauthorized)
 		return
 	}
@@ -99,6 +102,10 @@ func (h *Handler) Handle(c *gin.Context) {
 		}
 
 		err = h.service.CreateJobForBitbucketPush(c.Request.Context(), *installation, pushEvent)
+		if err != nil {
+			// keep track of why the push didn't result in a build, even if it's not a failure
+			_ = c.Error(err)
+		}
 		if err != nil && !errors.Is(err, ErrNonCloneableEvent) && !errors.Is(err, ErrNoManifest) {
 			c.Status(http.StatusInternalServerError)
 			return
@@ -11,6 +11,14 @@ import (
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 )
 
+const (
+	auditTargetTypeCatalogEntity = "catalogentity"
+
+	auditActionCatalogEntityCreated = "catalogentity.created"
+	auditActionCatalogEntityUpdated = "catalogentity.updated"
+	auditActionCatalogEntityDeleted = "catalogentity.deleted"
+)
+
 // NewHandler returns a new rbac.Handler
 func NewHandler(config *api.APIConfig, service Service, databaseClient database.Client) Handler {
 	return Handler{
@@ -325,6 +333,8 @@ func (h *Handler) CreateCatalogEntity(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditActionCatalogEntityCreated, auditTargetTypeCatalogEntity, insertedCatalogEntity.ID, nil, insertedCatalogEntity)
+
 	c.JSON(http.StatusCreated, insertedCatalogEntity)
 }
 
@@ -354,6 +364,9 @@ func (h *Handler) UpdateCatalogEntity(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	// the state before and after the update is recorded in the audit log, failing to retrieve it doesn't fail the update
+	before, _ := h.databaseClient.GetCatalogEntityByID(ctx, id)
+
 	err = h.service.UpdateCatalogEntity(ctx, catalogEntity)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating catalog entity")
@@ -361,6 +374,9 @@ func (h *Handler) UpdateCatalogEntity(c *gin.Context) {
 		return
 	}
 
+	after, _ := h.databaseClient.GetCatalogEntityByID(ctx, id)
+	api.AddAuditDetails(c, auditActionCatalogEntityUpdated, auditTargetTypeCatalogEntity, id, before, after)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
@@ -375,6 +391,8 @@ func (h *Handler) DeleteCatalogEntity(c *gin.Context) {
 	id := c.Param("id")
 	ctx := c.Request.Context()
 
+	before, _ := h.databaseClient.GetCatalogEntityByID(ctx, id)
+
 	err := h.service.DeleteCatalogEntity(ctx, id)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed deleting catalog entity")
@@ -382,6 +400,8 @@ func (h *Handler) DeleteCatalogEntity(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditActionCatalogEntityDeleted, auditTargetTypeCatalogEntity, id, before, nil)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
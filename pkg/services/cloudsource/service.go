@@ -18,6 +18,7 @@ import (
 var (
 	ErrNonCloneableEvent = errors.New("The event is not cloneable")
 	ErrNoManifest        = errors.New("The repository has no manifest at the pushed commit")
+	ErrNotAllowed        = errors.New("The project is not allowed")
 )
 
 // Service handles pubsub events for Cloud Source Repository integration
@@ -35,6 +35,7 @@ func (h *Handler) PostPubsubEvent(c *gin.Context) {
 	if err != nil {
 		errorMessage := "Binding PostPubsubEvent body failed"
 		log.Error().Err(err).Msg(errorMessage)
+		_ = c.Error(err)
 		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
 		return
 	}
@@ -54,11 +55,16 @@ func (h *Handler) PostPubsubEvent(c *gin.Context) {
 	// verify project is allowed
 	isAllowed, _ := h.service.IsAllowedProject(c.Request.Context(), notification)
 	if !isAllowed {
+		_ = c.Error(ErrNotAllowed)
 		c.Status(http.StatusUnauthorized)
 		return
 	}
 
 	err = h.service.CreateJobForCloudSourcePush(c.Request.Context(), notification)
+	if err != nil {
+		// keep track of why the push didn't result in a build, even if it's not a failure
+		_ = c.Error(err)
+	}
 	if err != nil && !errors.Is(err, ErrNonCloneableEvent) && !errors.Is(err, ErrNoManifest) {
 		c.String(http.StatusInternalServerError, "Oops, something went wrong!")
 		return
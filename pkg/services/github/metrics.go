@@ -12,14 +12,15 @@ import (
 )
 
 // NewMetricsService returns a new instance of a metrics Service.
-func NewMetricsService(s Service, requestCount metrics.Counter, requestLatency metrics.Histogram) Service {
-	return &metricsService{s, requestCount, requestLatency}
+func NewMetricsService(s Service, requestCount metrics.Counter, requestLatency metrics.Histogram, rejectionCount metrics.Counter) Service {
+	return &metricsService{s, requestCount, requestLatency, rejectionCount}
 }
 
 type metricsService struct {
 	Service        Service
 	requestCount   metrics.Counter
 	requestLatency metrics.Histogram
+	rejectionCount metrics.Counter
 }
 
 func (s *metricsService) CreateJobForGithubPush(ctx context.Context, event githubapi.PushEvent) (err error) {
@@ -38,12 +39,17 @@ func (s *metricsService) PublishGithubEvent(ctx context.Context, event manifest.
 	return s.Service.PublishGithubEvent(ctx, event)
 }
 
-func (s *metricsService) HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader string) (valid bool, err error) {
+func (s *metricsService) HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader, signature256Header string) (valid bool, err error) {
 	defer func(begin time.Time) {
 		api.UpdateMetrics(s.requestCount, s.requestLatency, "HasValidSignature", begin)
+
+		// count rejected webhook deliveries separately to be able to alert on forged or misconfigured webhooks
+		if err == nil && !valid {
+			s.rejectionCount.With("reason", "invalid_signature").Add(1)
+		}
 	}(time.Now())
 
-	return s.Service.HasValidSignature(ctx, body, appIDHeader, signatureHeader)
+	return s.Service.HasValidSignature(ctx, body, appIDHeader, signatureHeader, signature256Header)
 }
 
 func (s *metricsService) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) (err error) {
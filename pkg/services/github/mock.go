@@ -66,18 +66,18 @@ func (mr *MockServiceMockRecorder) CreateJobForGithubPush(ctx, event interface{}
 }
 
 // HasValidSignature mocks base method.
-func (m *MockService) HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader string) (bool, error) {
+func (m *MockService) HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader, signature256Header string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HasValidSignature", ctx, body, appIDHeader, signatureHeader)
+	ret := m.ctrl.Call(m, "HasValidSignature", ctx, body, appIDHeader, signatureHeader, signature256Header)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // HasValidSignature indicates an expected call of HasValidSignature.
-func (mr *MockServiceMockRecorder) HasValidSignature(ctx, body, appIDHeader, signatureHeader interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) HasValidSignature(ctx, body, appIDHeader, signatureHeader, signature256Header interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasValidSignature", reflect.TypeOf((*MockService)(nil).HasValidSignature), ctx, body, appIDHeader, signatureHeader)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasValidSignature", reflect.TypeOf((*MockService)(nil).HasValidSignature), ctx, body, appIDHeader, signatureHeader, signature256Header)
 }
 
 // IsAllowedInstallation mocks base method.
@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog/log"
@@ -24,6 +26,8 @@ import (
 var (
 	ErrNonCloneableEvent = errors.New("The event is not cloneable")
 	ErrNoManifest        = errors.New("The repository has no manifest at the pushed commit")
+	ErrInvalidSignature  = errors.New("The webhook signature is invalid")
+	ErrNotAllowed        = errors.New("The installation is not allowed")
 )
 
 // Service handles http events for Github integration
@@ -32,7 +36,7 @@ var (
 type Service interface {
 	CreateJobForGithubPush(ctx context.Context, event githubapi.PushEvent) (err error)
 	PublishGithubEvent(ctx context.Context, event manifest.ZiplineeGithubEvent) (err error)
-	HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader string) (validSignature bool, err error)
+	HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader, signature256Header string) (validSignature bool, err error)
 	Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) (err error)
 	Archive(ctx context.Context, repoSource, repoOwner, repoName string) (err error)
 	Unarchive(ctx context.Context, repoSource, repoOwner, repoName string) (err error)
@@ -176,15 +180,37 @@ func (s *service) PublishGithubEvent(ctx context.Context, event manifest.Zipline
 	return s.queueService.PublishGithubEvent(ctx, event)
 }
 
-func (s *service) HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader string) (bool, error) {
+func (s *service) HasValidSignature(ctx context.Context, body []byte, appIDHeader, signatureHeader, signature256Header string) (bool, error) {
+
+	// https://docs.github.com/en/webhooks/using-webhooks/validating-webhook-deliveries
+	// prefer the sha256 signature and only fall back to the legacy sha1 signature if it's missing
+	hashFunc, signature := sha256.New, strings.TrimPrefix(signature256Header, "sha256=")
+	if signature256Header == "" {
+		hashFunc, signature = sha1.New, strings.TrimPrefix(signatureHeader, "sha1=")
+	}
+	if signature == "" {
+		log.Warn().Msg("Github webhook has no X-Hub-Signature-256 or X-Hub-Signature header")
+		return false, nil
+	}
+
+	actualMAC, err := hex.DecodeString(signature)
+	if err != nil {
+		log.Warn().Err(err).Msg("Decoding hexadecimal Github webhook signature to byte array failed")
+		return false, nil
+	}
 
 	id, err := strconv.Atoi(appIDHeader)
 	if err != nil {
-		return false, err
+		log.Warn().Err(err).Msgf("Github webhook has invalid app id '%v'", appIDHeader)
+		return false, nil
 	}
 
 	app, err := s.githubapiClient.GetAppByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, githubapi.ErrUnknownApp) {
+			log.Warn().Err(err).Msg("Github webhook is for an unknown app")
+			return false, nil
+		}
 		return false, err
 	}
 
@@ -192,27 +218,27 @@ func (s *service) HasValidSignature(ctx context.Context, body []byte, appIDHeade
 		return false, fmt.Errorf("App for id %v is nil", id)
 	}
 
-	// https://developer.github.com/webhooks/securing/
-	signature := strings.Replace(signatureHeader, "sha1=", "", 1)
-	actualMAC, err := hex.DecodeString(signature)
-	if err != nil {
-		return false, errors.New("Decoding hexadecimal X-Hub-Signature to byte array failed")
-	}
+	// accept the current secret as well as the previous ones that haven't expired, so deliveries signed shortly before a secret rotation still validate
+	for _, webhookSecret := range app.GetWebhookSecrets(time.Now().UTC()) {
+		if webhookSecret == "" {
+			continue
+		}
 
-	// calculate expected MAC
-	mac := hmac.New(sha1.New, []byte(app.WebhookSecret))
-	mac.Write(body)
-	expectedMAC := mac.Sum(nil)
+		// calculate expected MAC
+		mac := hmac.New(hashFunc, []byte(webhookSecret))
+		mac.Write(body)
+		expectedMAC := mac.Sum(nil)
 
-	// compare actual and expected MAC
-	if hmac.Equal(actualMAC, expectedMAC) {
-		return true, nil
+		// compare actual and expected MAC
+		if hmac.Equal(actualMAC, expectedMAC) {
+			return true, nil
+		}
 	}
 
 	log.Warn().
-		Str("expectedMAC", hex.EncodeToString(expectedMAC)).
+		Int("appID", id).
 		Str("actualMAC", hex.EncodeToString(actualMAC)).
-		Msg("Expected and actual MAC do not match")
+		Msg("Github webhook signature does not match any of the app's webhook secrets")
 
 	return false, nil
 }
@@ -3,8 +3,10 @@ package github
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -375,7 +377,7 @@ func TestHasValidSignature(t *testing.T) {
 		signatureHeader := "sha1=7d38cdd689735b008b3c702edd92eea23791c5f6"
 
 		// act
-		validSignature, err := service.HasValidSignature(context.Background(), body, "15", signatureHeader)
+		validSignature, err := service.HasValidSignature(context.Background(), body, "15", signatureHeader, "")
 
 		assert.Nil(t, err)
 		assert.False(t, validSignature)
@@ -411,9 +413,184 @@ func TestHasValidSignature(t *testing.T) {
 		signatureHeader := "sha1=765539562e575982123574d8325a636e16e0efba"
 
 		// act
-		validSignature, err := service.HasValidSignature(context.Background(), body, "15", signatureHeader)
+		validSignature, err := service.HasValidSignature(context.Background(), body, "15", signatureHeader, "")
 
 		assert.Nil(t, err)
 		assert.True(t, validSignature)
 	})
+
+	t.Run("ReturnsTrueIfSha256SignatureMatchesExpectedSignature", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			Integrations: &api.APIConfigIntegrations{
+				Github: &api.GithubConfig{},
+			},
+		}
+
+		githubapiClient := githubapi.NewMockClient(ctrl)
+		pubsubapiClient := pubsubapi.NewMockClient(ctrl)
+		ziplineeService := ziplinee.NewMockService(ctrl)
+		queueService := queue.NewMockService(ctrl)
+
+		githubapiClient.
+			EXPECT().
+			GetAppByID(gomock.Any(), 15).
+			Return(&githubapi.GithubApp{
+				ID:            15,
+				WebhookSecret: "m1gw5wmje424dmfvpb72ny6vjnubw79jvi7dlw2h",
+			}, nil)
+
+		service := NewService(config, githubapiClient, pubsubapiClient, ziplineeService, queueService)
+
+		body := []byte(`{"action": "opened","issue": {"url": "https://api.github.com/repos/octocat/Hello-World/issues/1347","number": 1347,...},"repository" : {"id": 1296269,"full_name": "octocat/Hello-World","owner": {"login": "octocat","id": 1,...},...},"sender": {"login": "octocat","id": 1,...}}`)
+
+		// act
+		validSignature, err := service.HasValidSignature(context.Background(), body, "15", "sha1=7d38cdd689735b008b3c702edd92eea23791c5f6", "sha256=3358f07229c8b99518363001b9a722e818cbd5bddd730b3371ebe887199276fd")
+
+		assert.Nil(t, err)
+		assert.True(t, validSignature)
+	})
+
+	t.Run("ReturnsTrueIfSignatureMatchesPreviousWebhookSecret", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			Integrations: &api.APIConfigIntegrations{
+				Github: &api.GithubConfig{},
+			},
+		}
+
+		githubapiClient := githubapi.NewMockClient(ctrl)
+		pubsubapiClient := pubsubapi.NewMockClient(ctrl)
+		ziplineeService := ziplinee.NewMockService(ctrl)
+		queueService := queue.NewMockService(ctrl)
+
+		githubapiClient.
+			EXPECT().
+			GetAppByID(gomock.Any(), 15).
+			Return(&githubapi.GithubApp{
+				ID:            15,
+				WebhookSecret: "wk8b2a7l0d5mq6xh4xq3ch2ej9nrb5ztpgf1yslo",
+				PreviousWebhookSecrets: []*githubapi.GithubPreviousWebhookSecret{
+					{Secret: "m1gw5wmje424dmfvpb72ny6vjnubw79jvi7dlw2h", ExpiresAt: time.Now().Add(time.Hour)},
+				},
+			}, nil)
+
+		service := NewService(config, githubapiClient, pubsubapiClient, ziplineeService, queueService)
+
+		body := []byte(`{"action": "opened","issue": {"url": "https://api.github.com/repos/octocat/Hello-World/issues/1347","number": 1347,...},"repository" : {"id": 1296269,"full_name": "octocat/Hello-World","owner": {"login": "octocat","id": 1,...},...},"sender": {"login": "octocat","id": 1,...}}`)
+
+		// act
+		validSignature, err := service.HasValidSignature(context.Background(), body, "15", "", "sha256=3358f07229c8b99518363001b9a722e818cbd5bddd730b3371ebe887199276fd")
+
+		assert.Nil(t, err)
+		assert.True(t, validSignature)
+	})
+
+	t.Run("ReturnsFalseIfSignatureMatchesExpiredPreviousWebhookSecret", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			Integrations: &api.APIConfigIntegrations{
+				Github: &api.GithubConfig{},
+			},
+		}
+
+		githubapiClient := githubapi.NewMockClient(ctrl)
+		pubsubapiClient := pubsubapi.NewMockClient(ctrl)
+		ziplineeService := ziplinee.NewMockService(ctrl)
+		queueService := queue.NewMockService(ctrl)
+
+		githubapiClient.
+			EXPECT().
+			GetAppByID(gomock.Any(), 15).
+			Return(&githubapi.GithubApp{
+				ID:            15,
+				WebhookSecret: "wk8b2a7l0d5mq6xh4xq3ch2ej9nrb5ztpgf1yslo",
+				PreviousWebhookSecrets: []*githubapi.GithubPreviousWebhookSecret{
+					{Secret: "m1gw5wmje424dmfvpb72ny6vjnubw79jvi7dlw2h", ExpiresAt: time.Now().Add(-time.Hour)},
+				},
+			}, nil)
+
+		service := NewService(config, githubapiClient, pubsubapiClient, ziplineeService, queueService)
+
+		body := []byte(`{"action": "opened","issue": {"url": "https://api.github.com/repos/octocat/Hello-World/issues/1347","number": 1347,...},"repository" : {"id": 1296269,"full_name": "octocat/Hello-World","owner": {"login": "octocat","id": 1,...},...},"sender": {"login": "octocat","id": 1,...}}`)
+
+		// act
+		validSignature, err := service.HasValidSignature(context.Background(), body, "15", "", "sha256=3358f07229c8b99518363001b9a722e818cbd5bddd730b3371ebe887199276fd")
+
+		assert.Nil(t, err)
+		assert.False(t, validSignature)
+	})
+
+	t.Run("ReturnsFalseIfSignatureHeadersAreMissing", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			Integrations: &api.APIConfigIntegrations{
+				Github: &api.GithubConfig{},
+			},
+		}
+
+		githubapiClient := githubapi.NewMockClient(ctrl)
+		pubsubapiClient := pubsubapi.NewMockClient(ctrl)
+		ziplineeService := ziplinee.NewMockService(ctrl)
+		queueService := queue.NewMockService(ctrl)
+
+		githubapiClient.
+			EXPECT().
+			GetAppByID(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, githubapiClient, pubsubapiClient, ziplineeService, queueService)
+
+		body := []byte(`{"action": "opened","issue": {"url": "https://api.github.com/repos/octocat/Hello-World/issues/1347","number": 1347,...},"repository" : {"id": 1296269,"full_name": "octocat/Hello-World","owner": {"login": "octocat","id": 1,...},...},"sender": {"login": "octocat","id": 1,...}}`)
+
+		// act
+		validSignature, err := service.HasValidSignature(context.Background(), body, "15", "", "")
+
+		assert.Nil(t, err)
+		assert.False(t, validSignature)
+	})
+
+	t.Run("ReturnsFalseIfAppIsUnknown", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			Integrations: &api.APIConfigIntegrations{
+				Github: &api.GithubConfig{},
+			},
+		}
+
+		githubapiClient := githubapi.NewMockClient(ctrl)
+		pubsubapiClient := pubsubapi.NewMockClient(ctrl)
+		ziplineeService := ziplinee.NewMockService(ctrl)
+		queueService := queue.NewMockService(ctrl)
+
+		githubapiClient.
+			EXPECT().
+			GetAppByID(gomock.Any(), 15).
+			Return(nil, fmt.Errorf("App for id 15: %w", githubapi.ErrUnknownApp))
+
+		service := NewService(config, githubapiClient, pubsubapiClient, ziplineeService, queueService)
+
+		body := []byte(`{"action": "opened","issue": {"url": "https://api.github.com/repos/octocat/Hello-World/issues/1347","number": 1347,...},"repository" : {"id": 1296269,"full_name": "octocat/Hello-World","owner": {"login": "octocat","id": 1,...},...},"sender": {"login": "octocat","id": 1,...}}`)
+
+		// act
+		validSignature, err := service.HasValidSignature(context.Background(), body, "15", "", "sha256=3358f07229c8b99518363001b9a722e818cbd5bddd730b3371ebe887199276fd")
+
+		assert.Nil(t, err)
+		assert.False(t, validSignature)
+	})
 }
@@ -19,6 +19,13 @@ import (
 	manifest "github.com/ziplineeci/ziplinee-ci-manifest"
 )
 
+const (
+	auditTargetTypeIntegration = "integration"
+
+	auditActionIntegrationCreated = "integration.created"
+	auditActionIntegrationDeleted = "integration.deleted"
+)
+
 func NewHandler(service Service, config *api.APIConfig, githubapiClient githubapi.Client, databaseClient database.Client) Handler {
 	return Handler{
 		config:          config,
@@ -48,25 +55,31 @@ func (h *Handler) Handle(c *gin.Context) {
 		return
 	}
 
-	// TODO: Add check local or production - if local, skip signature check, else check signature
-	// verify hmac signature
-	//hasValidSignature, err := h.service.HasValidSignature(c.Request.Context(), body, c.GetHeader("X-GitHub-Hook-Installation-Target-ID"), c.GetHeader("X-Hub-Signature"))
-	//if err != nil {
-	//	log.Error().Err(err).Msg("Verifying signature from Github webhook failed")
-	//	c.Status(http.StatusInternalServerError)
-	//	return
-	//}
-	//if !hasValidSignature {
-	//	log.Error().Msg("Signature from Github webhook is invalid")
-	//	c.Status(http.StatusBadRequest)
-	//	return
-	//}
+	// verify hmac signature, unless running in insecure local mode where webhooks can't be signed
+	if h.config.Integrations.Github.InsecureLocalMode {
+		log.Warn().Msg("Skipping signature verification for Github webhook due to insecure local mode")
+	} else {
+		hasValidSignature, err := h.service.HasValidSignature(c.Request.Context(), body, c.GetHeader("X-GitHub-Hook-Installation-Target-ID"), c.GetHeader("X-Hub-Signature"), c.GetHeader("X-Hub-Signature-256"))
+		if err != nil {
+			log.Error().Err(err).Msg("Verifying signature from Github webhook failed")
+			_ = c.Error(err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		if !hasValidSignature {
+			log.Error().Msg("Signature from Github webhook is invalid")
+			_ = c.Error(ErrInvalidSignature)
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+	}
 
 	// unmarshal json body to check if installation is allowed
 	var anyEvent githubapi.AnyEvent
 	err = json.Unmarshal(body, &anyEvent)
 	if err != nil {
 		log.Error().Err(err).Str("body", string(body)).Msg("Deserializing body to GithubAnyEvent failed")
+		_ = c.Error(err)
 		c.Status(http.StatusBadRequest)
 		return
 	}
@@ -78,6 +91,7 @@ func (h *Handler) Handle(c *gin.Context) {
 		isAllowed, _ := h.service.IsAllowedInstallation(c.Request.Context(), anyEvent.Installation.ID)
 		if !isAllowed {
 			log.Warn().Interface("event", anyEvent).Str("body", string(body)).Msg("GithubAnyEvent installation is not allowed")
+			_ = c.Error(ErrNotAllowed)
 			c.Status(http.StatusUnauthorized)
 			return
 		}
@@ -92,6 +106,10 @@ func (h *Handler) Handle(c *gin.Context) {
 		}
 
 		err = h.service.CreateJobForGithubPush(c.Request.Context(), pushEvent)
+		if err != nil {
+			// keep track of why the push didn't result in a build, even if it's not a failure
+			_ = c.Error(err)
+		}
 		if err != nil && !errors.Is(err, ErrNonCloneableEvent) && !errors.Is(err, ErrNoManifest) {
 			log.Error().Err(err).Msg("Creating build job for github push failed")
 			c.Status(http.StatusInternalServerError)
@@ -118,6 +136,7 @@ func (h *Handler) Handle(c *gin.Context) {
 				c.Status(http.StatusBadRequest)
 				return
 			}
+			api.AddAuditDetails(c, auditActionIntegrationCreated, auditTargetTypeIntegration, fmt.Sprintf("github/%v", anyEvent.Installation.ID), nil, anyEvent.Installation)
 
 		case "deleted":
 			err = h.githubapiClient.RemoveInstallation(c.Request.Context(), anyEvent.Installation)
@@ -126,6 +145,7 @@ func (h *Handler) Handle(c *gin.Context) {
 				c.Status(http.StatusBadRequest)
 				return
 			}
+			api.AddAuditDetails(c, auditActionIntegrationDeleted, auditTargetTypeIntegration, fmt.Sprintf("github/%v", anyEvent.Installation.ID), anyEvent.Installation, nil)
 
 		case "suspend":
 		case "unsuspend":
@@ -254,6 +274,8 @@ func (h *Handler) Redirect(c *gin.Context) {
 		c.Status(http.StatusInternalServerError)
 		return
 	}
+	// the app's id and secrets aren't known here, only that one has been created from the manifest
+	api.AddAuditDetails(c, auditActionIntegrationCreated, auditTargetTypeIntegration, "github", nil, nil)
 
 	c.Redirect(http.StatusTemporaryRedirect, fmt.Sprintf("%v/admin/integrations", strings.TrimRight(h.config.APIServer.BaseURL, "/")))
 }
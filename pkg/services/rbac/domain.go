@@ -1,8 +1,13 @@
 package rbac
 
 import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/bitbucketapi"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/githubapi"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 )
 
 type githubResponse struct {
@@ -31,3 +36,279 @@ type bitbucketResponse struct {
 	RedirectURI string                       `json:"redirectURI,omitempty"`
 	Apps        []*bitbucketapi.BitbucketApp `json:"apps,omitempty"`
 }
+
+const (
+	// AuditActionUserRoleMappingsApplied is recorded when role mappings change the memberships of a user at login
+	AuditActionUserRoleMappingsApplied = "user.rolemappings.applied"
+
+	// AuditTargetTypeUser is the target type of audit log records for users
+	AuditTargetTypeUser = "user"
+
+	auditActionCreated      = "created"
+	auditActionUpdated      = "updated"
+	auditActionDeleted      = "deleted"
+	auditActionImpersonated = "impersonated"
+	auditActionRevoked      = "revoked"
+
+	auditTargetTypeGroup        = "group"
+	auditTargetTypeOrganization = "organization"
+	auditTargetTypeClient       = "client"
+	auditTargetTypeCustomRole   = "customrole"
+	auditTargetTypeIntegration  = "integration"
+	auditTargetTypePipeline     = "pipeline"
+
+	auditTargetTypePersonalAccessToken = "personalaccesstoken"
+	auditTargetTypeSession             = "session"
+	auditTargetTypeClientSecret        = "clientsecret"
+)
+
+// auditAction returns the action recorded in the audit log for a change to a target type, for example 'group.updated'
+func auditAction(targetType, action string) string {
+	return targetType + "." + action
+}
+
+// auditChanges collects the changes made by concurrent batch updates, to add them to the audit details of the request at once
+type auditChanges struct {
+	mu      sync.Mutex
+	changes []api.AuditDetails
+}
+
+func (a *auditChanges) add(targetType, targetID string, before, after interface{}) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.changes = append(a.changes, api.AuditDetails{
+		Action:     auditAction(targetType, auditActionUpdated),
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+	})
+}
+
+func (a *auditChanges) addTo(c *gin.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, d := range a.changes {
+		api.AddAuditDetails(c, d.Action, d.TargetType, d.TargetID, d.Before, d.After)
+	}
+}
+
+// obfuscateClientSecret keeps client secrets out of responses and the audit log
+func obfuscateClientSecret(client *contracts.Client) *contracts.Client {
+	if client == nil {
+		return nil
+	}
+
+	obfuscated := *client
+	obfuscated.ClientSecret = "***"
+
+	return &obfuscated
+}
+
+// userMemberships is the part of a user managed by role mappings, as recorded in the audit log
+type userMemberships struct {
+	Groups        []string `json:"groups"`
+	Organizations []string `json:"organizations"`
+	Roles         []string `json:"roles"`
+}
+
+// pipelinePermissions is the part of a pipeline managed through rbac, as recorded in the audit log
+type pipelinePermissions struct {
+	Groups        []*contracts.Group        `json:"groups"`
+	Organizations []*contracts.Organization `json:"organizations"`
+	Archived      bool                      `json:"archived"`
+}
+
+func getPipelinePermissions(pipeline *contracts.Pipeline) *pipelinePermissions {
+	if pipeline == nil {
+		return nil
+	}
+
+	return &pipelinePermissions{
+		Groups:        pipeline.Groups,
+		Organizations: pipeline.Organizations,
+		Archived:      pipeline.Archived,
+	}
+}
+
+// groupMemberships is the part of a group managed by batch updates, as recorded in the audit log
+type groupMemberships struct {
+	Organizations []string `json:"organizations"`
+	Roles         []string `json:"roles"`
+}
+
+func getGroupMemberships(group contracts.Group) (memberships groupMemberships) {
+	memberships = groupMemberships{
+		Organizations: []string{},
+		Roles:         []string{},
+	}
+	for _, o := range group.Organizations {
+		if o != nil {
+			memberships.Organizations = append(memberships.Organizations, o.Name)
+		}
+	}
+	for _, r := range group.Roles {
+		if r != nil {
+			memberships.Roles = append(memberships.Roles, *r)
+		}
+	}
+
+	return
+}
+
+// organizationScope limits what a request with an organization scoped role can manage to its own organizations; a nil
+// scope is that of a request that can manage all organizations, so every check passes
+type organizationScope struct {
+	organizations []string
+	roles         []string
+}
+
+// getOrganizationScope returns whether the request has a permission, and the scope it has the permission in
+func getOrganizationScope(c *gin.Context, permission api.Permission) (scope *organizationScope, hasPermission bool) {
+
+	hasPermission, organizations := api.GetOrganizationScopeFromRequest(c, permission)
+	if !hasPermission || len(organizations) == 0 {
+		return nil, hasPermission
+	}
+
+	scope = &organizationScope{
+		organizations: organizations,
+		roles:         []string{},
+	}
+	for _, r := range api.GetRolesFromRequest(c) {
+		scope.roles = append(scope.roles, r.String())
+	}
+
+	return scope, true
+}
+
+// filters limits list queries to the organizations of the scope
+func (s *organizationScope) filters(filters map[api.FilterType][]string) map[api.FilterType][]string {
+	if s != nil {
+		filters[api.FilterOrganizations] = s.organizations
+	}
+
+	return filters
+}
+
+// overlaps returns true if at least one of the organizations is within the scope
+func (s *organizationScope) overlaps(organizations []*contracts.Organization) bool {
+	if s == nil {
+		return true
+	}
+
+	for _, o := range organizations {
+		if o != nil && api.StringArrayContains(s.organizations, o.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contains returns true if there's at least one organization and all of them are within the scope
+func (s *organizationScope) contains(organizations []*contracts.Organization) bool {
+	if s == nil {
+		return true
+	}
+
+	for _, o := range organizations {
+		if o == nil || !api.StringArrayContains(s.organizations, o.Name) {
+			return false
+		}
+	}
+
+	return len(organizations) > 0
+}
+
+// holdsRoles returns true if the request has all roles itself, so it can't hand out or take away more than it has
+func (s *organizationScope) holdsRoles(roles []*string) bool {
+	if s == nil {
+		return true
+	}
+
+	for _, r := range roles {
+		if r != nil && !api.StringArrayContains(s.roles, *r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// manages returns true if something with the organizations and roles is entirely within the scope
+func (s *organizationScope) manages(organizations []*contracts.Organization, roles []*string) bool {
+	return s.contains(organizations) && s.holdsRoles(roles)
+}
+
+// allowsOrganizationChanges returns true if all organizations added or removed are within the scope
+func (s *organizationScope) allowsOrganizationChanges(before, after []*contracts.Organization) bool {
+	if s == nil {
+		return true
+	}
+
+	beforeNames, afterNames := getOrganizationNames(before), getOrganizationNames(after)
+	for _, n := range beforeNames {
+		if !api.StringArrayContains(afterNames, n) && !api.StringArrayContains(s.organizations, n) {
+			return false
+		}
+	}
+	for _, n := range afterNames {
+		if !api.StringArrayContains(beforeNames, n) && !api.StringArrayContains(s.organizations, n) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// allowsRoleChanges returns true if the request holds all roles added or removed
+func (s *organizationScope) allowsRoleChanges(before, after []*string) bool {
+	if s == nil {
+		return true
+	}
+
+	beforeNames, afterNames := getRoleNames(before), getRoleNames(after)
+	for _, n := range beforeNames {
+		if !api.StringArrayContains(afterNames, n) && !api.StringArrayContains(s.roles, n) {
+			return false
+		}
+	}
+	for _, n := range afterNames {
+		if !api.StringArrayContains(beforeNames, n) && !api.StringArrayContains(s.roles, n) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getChangedGroups returns the groups added or removed, by id
+func getChangedGroups(before, after []*contracts.Group) (changed []*contracts.Group) {
+
+	changed = []*contracts.Group{}
+	for _, b := range before {
+		if b != nil && !containsGroup(after, b.ID) {
+			changed = append(changed, b)
+		}
+	}
+	for _, a := range after {
+		if a != nil && !containsGroup(before, a.ID) {
+			changed = append(changed, a)
+		}
+	}
+
+	return
+}
+
+func containsGroup(groups []*contracts.Group, id string) bool {
+	for _, g := range groups {
+		if g != nil && g.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
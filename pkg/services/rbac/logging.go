@@ -136,3 +136,105 @@ func (s *loggingService) GetInheritedOrganizationsForUser(ctx context.Context, u
 
 	return s.Service.GetInheritedOrganizationsForUser(ctx, user)
 }
+
+func (s *loggingService) ApplyRoleMappings(ctx context.Context, user contracts.User, provider, organization string, identityProviderGroups []string) (mappedUser *contracts.User, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "ApplyRoleMappings", err) }()
+
+	return s.Service.ApplyRoleMappings(ctx, user, provider, organization, identityProviderGroups)
+}
+
+func (s *loggingService) CreateCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "CreateCustomRole", err) }()
+
+	return s.Service.CreateCustomRole(ctx, customRole)
+}
+
+func (s *loggingService) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "UpdateCustomRole", err) }()
+
+	return s.Service.UpdateCustomRole(ctx, customRole)
+}
+
+func (s *loggingService) DeleteCustomRole(ctx context.Context, id string) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "DeleteCustomRole", err) }()
+
+	return s.Service.DeleteCustomRole(ctx, id)
+}
+
+func (s *loggingService) CreatePersonalAccessToken(ctx context.Context, userID string, token api.PersonalAccessToken) (insertedToken *api.PersonalAccessToken, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "CreatePersonalAccessToken", err) }()
+
+	return s.Service.CreatePersonalAccessToken(ctx, userID, token)
+}
+
+func (s *loggingService) DeletePersonalAccessToken(ctx context.Context, userID, id string) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "DeletePersonalAccessToken", err) }()
+
+	return s.Service.DeletePersonalAccessToken(ctx, userID, id)
+}
+
+func (s *loggingService) AuthenticatePersonalAccessToken(ctx context.Context, token string) (user *contracts.User, personalAccessToken *api.PersonalAccessToken, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "AuthenticatePersonalAccessToken", err) }()
+
+	return s.Service.AuthenticatePersonalAccessToken(ctx, token)
+}
+
+func (s *loggingService) CreateSession(ctx context.Context, session api.Session) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "CreateSession", err) }()
+
+	return s.Service.CreateSession(ctx, session)
+}
+
+func (s *loggingService) RevokeSession(ctx context.Context, id string) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "RevokeSession", err) }()
+
+	return s.Service.RevokeSession(ctx, id)
+}
+
+func (s *loggingService) IsSessionRevoked(ctx context.Context, id string) (revoked bool, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "IsSessionRevoked", err) }()
+
+	return s.Service.IsSessionRevoked(ctx, id)
+}
+
+func (s *loggingService) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "GetActiveSessionsForSubject", err) }()
+
+	return s.Service.GetActiveSessionsForSubject(ctx, subjectID)
+}
+
+func (s *loggingService) RevokeSessionForSubject(ctx context.Context, subjectID, id string) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "RevokeSessionForSubject", err) }()
+
+	return s.Service.RevokeSessionForSubject(ctx, subjectID, id)
+}
+
+func (s *loggingService) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "RevokeSessionsForSubject", err) }()
+
+	return s.Service.RevokeSessionsForSubject(ctx, subjectID)
+}
+
+func (s *loggingService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (client *contracts.Client, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "AuthenticateClient", err) }()
+
+	return s.Service.AuthenticateClient(ctx, clientID, clientSecret)
+}
+
+func (s *loggingService) GetClientSecrets(ctx context.Context, id string) (secrets []*api.ClientSecret, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "GetClientSecrets", err) }()
+
+	return s.Service.GetClientSecrets(ctx, id)
+}
+
+func (s *loggingService) CreateClientSecret(ctx context.Context, id string) (insertedSecret *api.ClientSecret, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "CreateClientSecret", err) }()
+
+	return s.Service.CreateClientSecret(ctx, id)
+}
+
+func (s *loggingService) DeleteClientSecret(ctx context.Context, id, secretID string) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "DeleteClientSecret", err) }()
+
+	return s.Service.DeleteClientSecret(ctx, id, secretID)
+}
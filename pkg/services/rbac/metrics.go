@@ -175,3 +175,139 @@ func (s *metricsService) GetInheritedOrganizationsForUser(ctx context.Context, u
 
 	return s.Service.GetInheritedOrganizationsForUser(ctx, user)
 }
+
+func (s *metricsService) ApplyRoleMappings(ctx context.Context, user contracts.User, provider, organization string, identityProviderGroups []string) (mappedUser *contracts.User, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "ApplyRoleMappings", begin)
+	}(time.Now())
+
+	return s.Service.ApplyRoleMappings(ctx, user, provider, organization, identityProviderGroups)
+}
+
+func (s *metricsService) CreateCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "CreateCustomRole", begin)
+	}(time.Now())
+
+	return s.Service.CreateCustomRole(ctx, customRole)
+}
+
+func (s *metricsService) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "UpdateCustomRole", begin)
+	}(time.Now())
+
+	return s.Service.UpdateCustomRole(ctx, customRole)
+}
+
+func (s *metricsService) DeleteCustomRole(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "DeleteCustomRole", begin)
+	}(time.Now())
+
+	return s.Service.DeleteCustomRole(ctx, id)
+}
+
+func (s *metricsService) CreatePersonalAccessToken(ctx context.Context, userID string, token api.PersonalAccessToken) (insertedToken *api.PersonalAccessToken, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "CreatePersonalAccessToken", begin)
+	}(time.Now())
+
+	return s.Service.CreatePersonalAccessToken(ctx, userID, token)
+}
+
+func (s *metricsService) DeletePersonalAccessToken(ctx context.Context, userID, id string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "DeletePersonalAccessToken", begin)
+	}(time.Now())
+
+	return s.Service.DeletePersonalAccessToken(ctx, userID, id)
+}
+
+func (s *metricsService) AuthenticatePersonalAccessToken(ctx context.Context, token string) (user *contracts.User, personalAccessToken *api.PersonalAccessToken, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "AuthenticatePersonalAccessToken", begin)
+	}(time.Now())
+
+	return s.Service.AuthenticatePersonalAccessToken(ctx, token)
+}
+
+func (s *metricsService) CreateSession(ctx context.Context, session api.Session) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "CreateSession", begin)
+	}(time.Now())
+
+	return s.Service.CreateSession(ctx, session)
+}
+
+func (s *metricsService) RevokeSession(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "RevokeSession", begin)
+	}(time.Now())
+
+	return s.Service.RevokeSession(ctx, id)
+}
+
+func (s *metricsService) IsSessionRevoked(ctx context.Context, id string) (revoked bool, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "IsSessionRevoked", begin)
+	}(time.Now())
+
+	return s.Service.IsSessionRevoked(ctx, id)
+}
+
+func (s *metricsService) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "GetActiveSessionsForSubject", begin)
+	}(time.Now())
+
+	return s.Service.GetActiveSessionsForSubject(ctx, subjectID)
+}
+
+func (s *metricsService) RevokeSessionForSubject(ctx context.Context, subjectID, id string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "RevokeSessionForSubject", begin)
+	}(time.Now())
+
+	return s.Service.RevokeSessionForSubject(ctx, subjectID, id)
+}
+
+func (s *metricsService) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "RevokeSessionsForSubject", begin)
+	}(time.Now())
+
+	return s.Service.RevokeSessionsForSubject(ctx, subjectID)
+}
+
+func (s *metricsService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (client *contracts.Client, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "AuthenticateClient", begin)
+	}(time.Now())
+
+	return s.Service.AuthenticateClient(ctx, clientID, clientSecret)
+}
+
+func (s *metricsService) GetClientSecrets(ctx context.Context, id string) (secrets []*api.ClientSecret, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "GetClientSecrets", begin)
+	}(time.Now())
+
+	return s.Service.GetClientSecrets(ctx, id)
+}
+
+func (s *metricsService) CreateClientSecret(ctx context.Context, id string) (insertedSecret *api.ClientSecret, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "CreateClientSecret", begin)
+	}(time.Now())
+
+	return s.Service.CreateClientSecret(ctx, id)
+}
+
+func (s *metricsService) DeleteClientSecret(ctx context.Context, id, secretID string) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "DeleteClientSecret", begin)
+	}(time.Now())
+
+	return s.Service.DeleteClientSecret(ctx, id, secretID)
+}
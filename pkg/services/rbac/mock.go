@@ -36,6 +36,52 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ApplyRoleMappings mocks base method.
+func (m *MockService) ApplyRoleMappings(ctx context.Context, user contracts.User, provider, organization string, identityProviderGroups []string) (*contracts.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyRoleMappings", ctx, user, provider, organization, identityProviderGroups)
+	ret0, _ := ret[0].(*contracts.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyRoleMappings indicates an expected call of ApplyRoleMappings.
+func (mr *MockServiceMockRecorder) ApplyRoleMappings(ctx, user, provider, organization, identityProviderGroups interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyRoleMappings", reflect.TypeOf((*MockService)(nil).ApplyRoleMappings), ctx, user, provider, organization, identityProviderGroups)
+}
+
+// AuthenticateClient mocks base method.
+func (m *MockService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*contracts.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateClient", ctx, clientID, clientSecret)
+	ret0, _ := ret[0].(*contracts.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateClient indicates an expected call of AuthenticateClient.
+func (mr *MockServiceMockRecorder) AuthenticateClient(ctx, clientID, clientSecret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateClient", reflect.TypeOf((*MockService)(nil).AuthenticateClient), ctx, clientID, clientSecret)
+}
+
+// AuthenticatePersonalAccessToken mocks base method.
+func (m *MockService) AuthenticatePersonalAccessToken(ctx context.Context, token string) (*contracts.User, *api.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticatePersonalAccessToken", ctx, token)
+	ret0, _ := ret[0].(*contracts.User)
+	ret1, _ := ret[1].(*api.PersonalAccessToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AuthenticatePersonalAccessToken indicates an expected call of AuthenticatePersonalAccessToken.
+func (mr *MockServiceMockRecorder) AuthenticatePersonalAccessToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticatePersonalAccessToken", reflect.TypeOf((*MockService)(nil).AuthenticatePersonalAccessToken), ctx, token)
+}
+
 // CreateClient mocks base method.
 func (m *MockService) CreateClient(ctx context.Context, client contracts.Client) (*contracts.Client, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +97,36 @@ func (mr *MockServiceMockRecorder) CreateClient(ctx, client interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockService)(nil).CreateClient), ctx, client)
 }
 
+// CreateClientSecret mocks base method.
+func (m *MockService) CreateClientSecret(ctx context.Context, id string) (*api.ClientSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClientSecret", ctx, id)
+	ret0, _ := ret[0].(*api.ClientSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClientSecret indicates an expected call of CreateClientSecret.
+func (mr *MockServiceMockRecorder) CreateClientSecret(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClientSecret", reflect.TypeOf((*MockService)(nil).CreateClientSecret), ctx, id)
+}
+
+// CreateCustomRole mocks base method.
+func (m *MockService) CreateCustomRole(ctx context.Context, customRole api.CustomRole) (*api.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomRole", ctx, customRole)
+	ret0, _ := ret[0].(*api.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCustomRole indicates an expected call of CreateCustomRole.
+func (mr *MockServiceMockRecorder) CreateCustomRole(ctx, customRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomRole", reflect.TypeOf((*MockService)(nil).CreateCustomRole), ctx, customRole)
+}
+
 // CreateGroup mocks base method.
 func (m *MockService) CreateGroup(ctx context.Context, group contracts.Group) (*contracts.Group, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +157,35 @@ func (mr *MockServiceMockRecorder) CreateOrganization(ctx, organization interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockService)(nil).CreateOrganization), ctx, organization)
 }
 
+// CreatePersonalAccessToken mocks base method.
+func (m *MockService) CreatePersonalAccessToken(ctx context.Context, userID string, token api.PersonalAccessToken) (*api.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", ctx, userID, token)
+	ret0, _ := ret[0].(*api.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockServiceMockRecorder) CreatePersonalAccessToken(ctx, userID, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockService)(nil).CreatePersonalAccessToken), ctx, userID, token)
+}
+
+// CreateSession mocks base method.
+func (m *MockService) CreateSession(ctx context.Context, session api.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockServiceMockRecorder) CreateSession(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockService)(nil).CreateSession), ctx, session)
+}
+
 // CreateUser mocks base method.
 func (m *MockService) CreateUser(ctx context.Context, user contracts.User) (*contracts.User, error) {
 	m.ctrl.T.Helper()
@@ -125,6 +230,34 @@ func (mr *MockServiceMockRecorder) DeleteClient(ctx, id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockService)(nil).DeleteClient), ctx, id)
 }
 
+// DeleteClientSecret mocks base method.
+func (m *MockService) DeleteClientSecret(ctx context.Context, id, secretID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClientSecret", ctx, id, secretID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClientSecret indicates an expected call of DeleteClientSecret.
+func (mr *MockServiceMockRecorder) DeleteClientSecret(ctx, id, secretID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientSecret", reflect.TypeOf((*MockService)(nil).DeleteClientSecret), ctx, id, secretID)
+}
+
+// DeleteCustomRole mocks base method.
+func (m *MockService) DeleteCustomRole(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCustomRole", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCustomRole indicates an expected call of DeleteCustomRole.
+func (mr *MockServiceMockRecorder) DeleteCustomRole(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomRole", reflect.TypeOf((*MockService)(nil).DeleteCustomRole), ctx, id)
+}
+
 // DeleteGroup mocks base method.
 func (m *MockService) DeleteGroup(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -153,6 +286,20 @@ func (mr *MockServiceMockRecorder) DeleteOrganization(ctx, id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganization", reflect.TypeOf((*MockService)(nil).DeleteOrganization), ctx, id)
 }
 
+// DeletePersonalAccessToken mocks base method.
+func (m *MockService) DeletePersonalAccessToken(ctx context.Context, userID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePersonalAccessToken", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePersonalAccessToken indicates an expected call of DeletePersonalAccessToken.
+func (mr *MockServiceMockRecorder) DeletePersonalAccessToken(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePersonalAccessToken", reflect.TypeOf((*MockService)(nil).DeletePersonalAccessToken), ctx, userID, id)
+}
+
 // DeleteUser mocks base method.
 func (m *MockService) DeleteUser(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -167,6 +314,36 @@ func (mr *MockServiceMockRecorder) DeleteUser(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockService)(nil).DeleteUser), ctx, id)
 }
 
+// GetActiveSessionsForSubject mocks base method.
+func (m *MockService) GetActiveSessionsForSubject(ctx context.Context, subjectID string) ([]*api.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSessionsForSubject", ctx, subjectID)
+	ret0, _ := ret[0].([]*api.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSessionsForSubject indicates an expected call of GetActiveSessionsForSubject.
+func (mr *MockServiceMockRecorder) GetActiveSessionsForSubject(ctx, subjectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessionsForSubject", reflect.TypeOf((*MockService)(nil).GetActiveSessionsForSubject), ctx, subjectID)
+}
+
+// GetClientSecrets mocks base method.
+func (m *MockService) GetClientSecrets(ctx context.Context, id string) ([]*api.ClientSecret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientSecrets", ctx, id)
+	ret0, _ := ret[0].([]*api.ClientSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientSecrets indicates an expected call of GetClientSecrets.
+func (mr *MockServiceMockRecorder) GetClientSecrets(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientSecrets", reflect.TypeOf((*MockService)(nil).GetClientSecrets), ctx, id)
+}
+
 // GetInheritedOrganizationsForUser mocks base method.
 func (m *MockService) GetInheritedOrganizationsForUser(ctx context.Context, user contracts.User) ([]*contracts.Organization, error) {
 	m.ctrl.T.Helper()
@@ -257,6 +434,64 @@ func (mr *MockServiceMockRecorder) GetUserByIdentity(ctx, identity interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByIdentity", reflect.TypeOf((*MockService)(nil).GetUserByIdentity), ctx, identity)
 }
 
+// IsSessionRevoked mocks base method.
+func (m *MockService) IsSessionRevoked(ctx context.Context, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSessionRevoked", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSessionRevoked indicates an expected call of IsSessionRevoked.
+func (mr *MockServiceMockRecorder) IsSessionRevoked(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSessionRevoked", reflect.TypeOf((*MockService)(nil).IsSessionRevoked), ctx, id)
+}
+
+// RevokeSession mocks base method.
+func (m *MockService) RevokeSession(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockServiceMockRecorder) RevokeSession(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockService)(nil).RevokeSession), ctx, id)
+}
+
+// RevokeSessionForSubject mocks base method.
+func (m *MockService) RevokeSessionForSubject(ctx context.Context, subjectID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionForSubject", ctx, subjectID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSessionForSubject indicates an expected call of RevokeSessionForSubject.
+func (mr *MockServiceMockRecorder) RevokeSessionForSubject(ctx, subjectID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionForSubject", reflect.TypeOf((*MockService)(nil).RevokeSessionForSubject), ctx, subjectID, id)
+}
+
+// RevokeSessionsForSubject mocks base method.
+func (m *MockService) RevokeSessionsForSubject(ctx context.Context, subjectID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionsForSubject", ctx, subjectID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessionsForSubject indicates an expected call of RevokeSessionsForSubject.
+func (mr *MockServiceMockRecorder) RevokeSessionsForSubject(ctx, subjectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionsForSubject", reflect.TypeOf((*MockService)(nil).RevokeSessionsForSubject), ctx, subjectID)
+}
+
 // UpdateClient mocks base method.
 func (m *MockService) UpdateClient(ctx context.Context, client contracts.Client) error {
 	m.ctrl.T.Helper()
@@ -271,6 +506,20 @@ func (mr *MockServiceMockRecorder) UpdateClient(ctx, client interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClient", reflect.TypeOf((*MockService)(nil).UpdateClient), ctx, client)
 }
 
+// UpdateCustomRole mocks base method.
+func (m *MockService) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomRole", ctx, customRole)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCustomRole indicates an expected call of UpdateCustomRole.
+func (mr *MockServiceMockRecorder) UpdateCustomRole(ctx, customRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomRole", reflect.TypeOf((*MockService)(nil).UpdateCustomRole), ctx, customRole)
+}
+
 // UpdateGroup mocks base method.
 func (m *MockService) UpdateGroup(ctx context.Context, group contracts.Group) error {
 	m.ctrl.T.Helper()
@@ -2,8 +2,13 @@ package rbac
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +22,27 @@ import (
 var (
 	// ErrUserNotFound indicates that a user cannot be found in the database
 	ErrUserNotFound = errors.New("The user can't be found")
+
+	// ErrInvalidCustomRole indicates that a custom role has missing or incorrect fields
+	ErrInvalidCustomRole = errors.New("The custom role is invalid")
+
+	// ErrInvalidPersonalAccessToken indicates that a personal access token has missing or incorrect fields
+	ErrInvalidPersonalAccessToken = errors.New("The personal access token is invalid")
+
+	// ErrPersonalAccessTokenExpired indicates that a personal access token can no longer be used
+	ErrPersonalAccessTokenExpired = errors.New("The personal access token has expired")
+
+	// ErrInvalidClientCredentials indicates that a client id and secret don't match an active client
+	ErrInvalidClientCredentials = errors.New("The client credentials are invalid")
+
+	// ErrTooManyClientSecrets indicates that a client already has the maximum number of active secrets
+	ErrTooManyClientSecrets = errors.New("The client has too many active secrets")
+
+	// ErrLastClientSecret indicates that the only active secret of a client can't be deleted
+	ErrLastClientSecret = errors.New("The last active secret of a client can't be deleted")
+
+	// ErrOutsideOrganizationScope indicates that a request tries to make changes outside of the organizations it can manage
+	ErrOutsideOrganizationScope = errors.New("The request can only make changes within its own organizations")
 )
 
 // Service handles http requests for role-based-access-control
@@ -45,11 +71,32 @@ type Service interface {
 	CreateClient(ctx context.Context, client contracts.Client) (insertedClient *contracts.Client, err error)
 	UpdateClient(ctx context.Context, client contracts.Client) (err error)
 	DeleteClient(ctx context.Context, id string) (err error)
+	AuthenticateClient(ctx context.Context, clientID, clientSecret string) (client *contracts.Client, err error)
+	GetClientSecrets(ctx context.Context, id string) (secrets []*api.ClientSecret, err error)
+	CreateClientSecret(ctx context.Context, id string) (insertedSecret *api.ClientSecret, err error)
+	DeleteClientSecret(ctx context.Context, id, secretID string) (err error)
+
+	CreateCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error)
+	UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error)
+	DeleteCustomRole(ctx context.Context, id string) (err error)
+
+	CreatePersonalAccessToken(ctx context.Context, userID string, token api.PersonalAccessToken) (insertedToken *api.PersonalAccessToken, err error)
+	DeletePersonalAccessToken(ctx context.Context, userID, id string) (err error)
+	AuthenticatePersonalAccessToken(ctx context.Context, token string) (user *contracts.User, personalAccessToken *api.PersonalAccessToken, err error)
+
+	CreateSession(ctx context.Context, session api.Session) (err error)
+	RevokeSession(ctx context.Context, id string) (err error)
+	IsSessionRevoked(ctx context.Context, id string) (revoked bool, err error)
+	GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error)
+	RevokeSessionForSubject(ctx context.Context, subjectID, id string) (err error)
+	RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error)
 
 	UpdatePipeline(ctx context.Context, pipeline contracts.Pipeline) (err error)
 
 	GetInheritedRolesForUser(ctx context.Context, user contracts.User) (roles []*string, err error)
 	GetInheritedOrganizationsForUser(ctx context.Context, user contracts.User) (organizations []*contracts.Organization, err error)
+
+	ApplyRoleMappings(ctx context.Context, user contracts.User, provider, organization string, identityProviderGroups []string) (mappedUser *contracts.User, err error)
 }
 
 // NewService returns a github.Service to handle incoming webhook events
@@ -89,6 +136,12 @@ func (s *service) GetProviders(ctx context.Context) (providers []*api.OAuthProvi
 		providers = append(providers, s.config.Auth.Github)
 	}
 
+	for _, p := range s.config.Auth.OIDC {
+		if p != nil && p.ClientID != "" {
+			providers = append(providers, p)
+		}
+	}
+
 	return providers, nil
 }
 
@@ -178,6 +231,9 @@ func (s *service) UpdateUser(ctx context.Context, user contracts.User) (err erro
 		return fmt.Errorf("User is nil")
 	}
 
+	before := getUserMemberships(*currentUser)
+	wasActive := currentUser.Active
+
 	// copy updateable fields
 	currentUser.Name = user.Name
 	currentUser.Email = user.Email
@@ -193,7 +249,17 @@ func (s *service) UpdateUser(ctx context.Context, user contracts.User) (err erro
 
 	s.setAdminRoleForUserIfConfigured(currentUser)
 
-	return s.databaseClient.UpdateUser(ctx, *currentUser)
+	err = s.databaseClient.UpdateUser(ctx, *currentUser)
+	if err != nil {
+		return
+	}
+
+	// jwts carry the roles and organizations from login, so they have to be revoked when those change
+	if wasActive != currentUser.Active || !reflect.DeepEqual(before, getUserMemberships(*currentUser)) {
+		_, err = s.RevokeSessionsForSubject(ctx, currentUser.ID)
+	}
+
+	return
 }
 
 func (s *service) DeleteUser(ctx context.Context, id string) (err error) {
@@ -207,7 +273,14 @@ func (s *service) DeleteUser(ctx context.Context, id string) (err error) {
 		return fmt.Errorf("User is nil")
 	}
 
-	return s.databaseClient.DeleteUser(ctx, *currentUser)
+	err = s.databaseClient.DeleteUser(ctx, *currentUser)
+	if err != nil {
+		return
+	}
+
+	_, err = s.RevokeSessionsForSubject(ctx, currentUser.ID)
+
+	return
 }
 func (s *service) CreateGroup(ctx context.Context, group contracts.Group) (insertedGroup *contracts.Group, err error) {
 
@@ -235,6 +308,8 @@ func (s *service) UpdateGroup(ctx context.Context, group contracts.Group) (err e
 		return fmt.Errorf("Group is nil")
 	}
 
+	rolesOrOrganizationsChanged := !reflect.DeepEqual(getRoleNames(currentGroup.Roles), getRoleNames(group.Roles)) || !reflect.DeepEqual(getOrganizationNames(currentGroup.Organizations), getOrganizationNames(group.Organizations))
+
 	// copy updateable fields
 	currentGroup.Name = group.Name
 	currentGroup.Description = group.Description
@@ -242,7 +317,17 @@ func (s *service) UpdateGroup(ctx context.Context, group contracts.Group) (err e
 	currentGroup.Organizations = group.Organizations
 	currentGroup.Roles = group.Roles
 
-	return s.databaseClient.UpdateGroup(ctx, *currentGroup)
+	err = s.databaseClient.UpdateGroup(ctx, *currentGroup)
+	if err != nil {
+		return
+	}
+
+	// members inherit roles and organizations from the group in their jwts
+	if rolesOrOrganizationsChanged {
+		err = s.revokeSessionsForUsers(ctx, map[api.FilterType][]string{api.FilterGroupID: {currentGroup.ID}})
+	}
+
+	return
 }
 
 func (s *service) DeleteGroup(ctx context.Context, id string) (err error) {
@@ -283,12 +368,39 @@ func (s *service) UpdateOrganization(ctx context.Context, organization contracts
 		return fmt.Errorf("Organization is nil")
 	}
 
+	rolesChanged := !reflect.DeepEqual(getRoleNames(currentOrganization.Roles), getRoleNames(organization.Roles))
+	organizationName := currentOrganization.Name
+
 	// copy updateable fields
 	currentOrganization.Name = organization.Name
 	currentOrganization.Identities = organization.Identities
 	currentOrganization.Roles = organization.Roles
 
-	return s.databaseClient.UpdateOrganization(ctx, *currentOrganization)
+	err = s.databaseClient.UpdateOrganization(ctx, *currentOrganization)
+	if err != nil {
+		return
+	}
+
+	// members and members of its groups inherit roles from the organization in their jwts
+	if rolesChanged {
+		err = s.revokeSessionsForUsers(ctx, map[api.FilterType][]string{api.FilterOrganizationID: {currentOrganization.ID}})
+		if err != nil {
+			return
+		}
+
+		groups, err := s.getAllGroups(ctx, map[api.FilterType][]string{api.FilterOrganizations: {organizationName}})
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			err = s.revokeSessionsForUsers(ctx, map[api.FilterType][]string{api.FilterGroupID: {g.ID}})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return
 }
 
 func (s *service) DeleteOrganization(ctx context.Context, id string) (err error) {
@@ -324,10 +436,22 @@ func (s *service) CreateClient(ctx context.Context, client contracts.Client) (in
 	}
 
 	// set immutable fields
-	insertedClient.ClientSecret = clientSecret
 	insertedClient.ClientID = uuid.New().String()
 
-	return s.databaseClient.InsertClient(ctx, *insertedClient)
+	insertedClient, err = s.databaseClient.InsertClient(ctx, *insertedClient)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.databaseClient.InsertClientSecret(ctx, api.ClientSecret{ClientID: insertedClient.ID}, hashClientSecret(clientSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	// the secret is only returned this once
+	insertedClient.ClientSecret = clientSecret
+
+	return insertedClient, nil
 }
 
 func (s *service) UpdateClient(ctx context.Context, client contracts.Client) (err error) {
@@ -341,12 +465,23 @@ func (s *service) UpdateClient(ctx context.Context, client contracts.Client) (er
 		return fmt.Errorf("Client is nil")
 	}
 
+	rolesOrOrganizationsChanged := !reflect.DeepEqual(getRoleNames(currentClient.Roles), getRoleNames(client.Roles)) || !reflect.DeepEqual(getOrganizationNames(currentClient.Organizations), getOrganizationNames(client.Organizations))
+
 	// copy updateable fields
 	currentClient.Name = client.Name
 	currentClient.Roles = client.Roles
 	currentClient.Organizations = client.Organizations
 
-	return s.databaseClient.UpdateClient(ctx, *currentClient)
+	err = s.databaseClient.UpdateClient(ctx, *currentClient)
+	if err != nil {
+		return
+	}
+
+	if rolesOrOrganizationsChanged {
+		_, err = s.RevokeSessionsForSubject(ctx, currentClient.ID)
+	}
+
+	return
 }
 
 func (s *service) DeleteClient(ctx context.Context, id string) (err error) {
@@ -360,7 +495,373 @@ func (s *service) DeleteClient(ctx context.Context, id string) (err error) {
 		return fmt.Errorf("Client is nil")
 	}
 
-	return s.databaseClient.DeleteClient(ctx, *currentClient)
+	err = s.databaseClient.DeleteClient(ctx, *currentClient)
+	if err != nil {
+		return
+	}
+
+	_, err = s.RevokeSessionsForSubject(ctx, currentClient.ID)
+
+	return
+}
+
+func (s *service) CreateCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
+
+	log.Debug().Msgf("Creating record for custom role %v", customRole.Name)
+
+	insertedCustomRole = &api.CustomRole{
+		Name:        customRole.Name,
+		Description: customRole.Description,
+		Permissions: customRole.Permissions,
+		Bindings:    customRole.Bindings,
+	}
+
+	if err = insertedCustomRole.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCustomRole, err)
+	}
+
+	return s.databaseClient.InsertCustomRole(ctx, *insertedCustomRole)
+}
+
+func (s *service) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+
+	// get custom role from db
+	currentCustomRole, err := s.databaseClient.GetCustomRoleByID(ctx, customRole.ID)
+	if err != nil {
+		return
+	}
+	if currentCustomRole == nil {
+		return fmt.Errorf("Custom role is nil")
+	}
+
+	// copy updateable fields
+	currentCustomRole.Name = customRole.Name
+	currentCustomRole.Description = customRole.Description
+	currentCustomRole.Permissions = customRole.Permissions
+	currentCustomRole.Bindings = customRole.Bindings
+
+	if err = currentCustomRole.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCustomRole, err)
+	}
+
+	return s.databaseClient.UpdateCustomRole(ctx, *currentCustomRole)
+}
+
+func (s *service) DeleteCustomRole(ctx context.Context, id string) (err error) {
+
+	// get custom role from db
+	currentCustomRole, err := s.databaseClient.GetCustomRoleByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if currentCustomRole == nil {
+		return fmt.Errorf("Custom role is nil")
+	}
+
+	return s.databaseClient.DeleteCustomRole(ctx, *currentCustomRole)
+}
+
+func (s *service) CreatePersonalAccessToken(ctx context.Context, userID string, token api.PersonalAccessToken) (insertedToken *api.PersonalAccessToken, err error) {
+
+	log.Debug().Msgf("Creating personal access token %v for user %v", token.Name, userID)
+
+	insertedToken = &api.PersonalAccessToken{
+		UserID:    userID,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		ExpiresAt: token.ExpiresAt,
+	}
+
+	if err = insertedToken.Validate(time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPersonalAccessToken, err)
+	}
+
+	secret, err := password.Generate(40, 10, 0, false, true)
+	if err != nil {
+		return nil, err
+	}
+	insertedToken.Token = api.PersonalAccessTokenPrefix + secret
+
+	return s.databaseClient.InsertPersonalAccessToken(ctx, *insertedToken, hashPersonalAccessToken(insertedToken.Token))
+}
+
+func (s *service) DeletePersonalAccessToken(ctx context.Context, userID, id string) (err error) {
+
+	// get token from db
+	currentToken, err := s.databaseClient.GetPersonalAccessTokenByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	// users can only revoke their own tokens, others don't exist as far as they're concerned
+	if currentToken.UserID != userID {
+		return database.ErrPersonalAccessTokenNotFound
+	}
+
+	return s.databaseClient.DeletePersonalAccessToken(ctx, *currentToken)
+}
+
+func (s *service) AuthenticatePersonalAccessToken(ctx context.Context, token string) (user *contracts.User, personalAccessToken *api.PersonalAccessToken, err error) {
+
+	personalAccessToken, err = s.databaseClient.GetPersonalAccessTokenByHash(ctx, hashPersonalAccessToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if personalAccessToken.IsExpired(time.Now().UTC()) {
+		return nil, nil, ErrPersonalAccessTokenExpired
+	}
+
+	user, err = s.databaseClient.GetUserByID(ctx, personalAccessToken.UserID, map[api.FilterType][]string{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil || !user.Active {
+		return nil, nil, ErrUserNotFound
+	}
+
+	// get all roles and organizations the user inherits from groups and organizations, like at login
+	user.Roles, err = s.GetInheritedRolesForUser(ctx, *user)
+	if err != nil {
+		return nil, nil, err
+	}
+	user.Organizations, err = s.GetInheritedOrganizationsForUser(ctx, *user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.databaseClient.UpdatePersonalAccessTokenLastUsed(ctx, personalAccessToken.ID)
+	if err != nil {
+		// failing to track usage shouldn't stop the token from being used
+		log.Warn().Err(err).Msgf("Failed updating last used timestamp of personal access token %v", personalAccessToken.ID)
+	}
+
+	return user, personalAccessToken, nil
+}
+
+func (s *service) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (client *contracts.Client, err error) {
+
+	if clientID == "" || clientSecret == "" {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	client, err = s.databaseClient.GetClientByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, database.ErrClientNotFound) {
+			return nil, ErrInvalidClientCredentials
+		}
+		return nil, err
+	}
+	if client == nil || !client.Active {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	secret, err := s.databaseClient.GetClientSecretByHash(ctx, client.ID, hashClientSecret(clientSecret))
+	if err == nil {
+		err = s.databaseClient.UpdateClientSecretLastUsed(ctx, secret.ID)
+		if err != nil {
+			// failing to track usage shouldn't stop the secret from being used
+			log.Warn().Err(err).Msgf("Failed updating last used timestamp of secret %v of client %v", secret.ID, client.ID)
+		}
+
+		client.ClientSecret = ""
+		return client, nil
+	}
+	if !errors.Is(err, database.ErrClientSecretNotFound) {
+		return nil, err
+	}
+
+	// clients created before secrets were hashed still have their secret in plain text, until it's used once more
+	if client.ClientSecret != "" && subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) == 1 {
+		err = s.hashLegacyClientSecret(ctx, client)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed hashing plain text secret of client %v", client.ID)
+		}
+
+		client.ClientSecret = ""
+		return client, nil
+	}
+
+	return nil, ErrInvalidClientCredentials
+}
+
+func (s *service) GetClientSecrets(ctx context.Context, id string) (secrets []*api.ClientSecret, err error) {
+
+	client, err := s.databaseClient.GetClientByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if client.ClientSecret != "" {
+		err = s.hashLegacyClientSecret(ctx, client)
+		if err != nil {
+			return
+		}
+	}
+
+	return s.databaseClient.GetClientSecretsForClient(ctx, client.ID)
+}
+
+func (s *service) CreateClientSecret(ctx context.Context, id string) (insertedSecret *api.ClientSecret, err error) {
+
+	log.Debug().Msgf("Creating secret for client %v", id)
+
+	// a client can have two secrets at once, to roll out the new one before deleting the old one
+	secrets, err := s.GetClientSecrets(ctx, id)
+	if err != nil {
+		return
+	}
+	if len(secrets) >= api.MaxActiveClientSecrets {
+		return nil, ErrTooManyClientSecrets
+	}
+
+	clientSecret, err := password.Generate(64, 10, 0, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	insertedSecret, err = s.databaseClient.InsertClientSecret(ctx, api.ClientSecret{ClientID: id}, hashClientSecret(clientSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	// the secret is only returned this once
+	insertedSecret.Secret = clientSecret
+
+	return insertedSecret, nil
+}
+
+func (s *service) DeleteClientSecret(ctx context.Context, id, secretID string) (err error) {
+
+	secrets, err := s.GetClientSecrets(ctx, id)
+	if err != nil {
+		return
+	}
+
+	for _, secret := range secrets {
+		if secret.ID != secretID {
+			continue
+		}
+
+		// a client without secrets can't authenticate anymore, delete the client instead
+		if len(secrets) == 1 {
+			return ErrLastClientSecret
+		}
+
+		return s.databaseClient.DeleteClientSecret(ctx, *secret)
+	}
+
+	return database.ErrClientSecretNotFound
+}
+
+// hashLegacyClientSecret replaces the plain text secret of a client created before secrets were hashed by its hash
+func (s *service) hashLegacyClientSecret(ctx context.Context, client *contracts.Client) (err error) {
+
+	// concurrent requests for the same client race to do this, but only one of them replaces the plain text secret
+	_, err = s.databaseClient.HashLegacyClientSecret(ctx, *client, hashClientSecret(client.ClientSecret))
+	if err != nil {
+		return
+	}
+
+	client.ClientSecret = ""
+
+	return nil
+}
+
+func (s *service) CreateSession(ctx context.Context, session api.Session) (err error) {
+	return s.databaseClient.InsertSession(ctx, session)
+}
+
+func (s *service) RevokeSession(ctx context.Context, id string) (err error) {
+	return s.databaseClient.RevokeSession(ctx, id)
+}
+
+func (s *service) IsSessionRevoked(ctx context.Context, id string) (revoked bool, err error) {
+
+	session, err := s.databaseClient.GetSessionByID(ctx, id)
+	if err != nil {
+		// sessions that failed to be recorded at login can't have been revoked
+		if errors.Is(err, database.ErrSessionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return session.RevokedAt != nil, nil
+}
+
+func (s *service) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	return s.databaseClient.GetActiveSessionsForSubject(ctx, subjectID)
+}
+
+func (s *service) RevokeSessionForSubject(ctx context.Context, subjectID, id string) (err error) {
+
+	// get session from db
+	currentSession, err := s.databaseClient.GetSessionByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	// users can only revoke their own sessions, others don't exist as far as they're concerned
+	if currentSession.SubjectID != subjectID {
+		return database.ErrSessionNotFound
+	}
+
+	return s.databaseClient.RevokeSession(ctx, currentSession.ID)
+}
+
+func (s *service) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+
+	revokedSessionIDs, err = s.databaseClient.RevokeSessionsForSubject(ctx, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed revoking sessions for %v: %w", subjectID, err)
+	}
+
+	if len(revokedSessionIDs) > 0 {
+		log.Info().Msgf("Revoked %v sessions for %v", len(revokedSessionIDs), subjectID)
+	}
+
+	return
+}
+
+// revokeSessionsForUsers revokes the sessions of all users matching the filters
+func (s *service) revokeSessionsForUsers(ctx context.Context, filters map[api.FilterType][]string) (err error) {
+
+	pageSize := 100
+	for pageNumber := 1; ; pageNumber++ {
+		users, err := s.databaseClient.GetUsers(ctx, pageNumber, pageSize, filters, []api.OrderField{})
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			_, err = s.RevokeSessionsForSubject(ctx, u.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(users) < pageSize {
+			return nil
+		}
+	}
+}
+
+// getAllGroups retrieves the groups matching the filters across all pages
+func (s *service) getAllGroups(ctx context.Context, filters map[api.FilterType][]string) (groups []*contracts.Group, err error) {
+
+	pageSize := 100
+	for pageNumber := 1; ; pageNumber++ {
+		page, err := s.databaseClient.GetGroups(ctx, pageNumber, pageSize, filters, []api.OrderField{})
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, page...)
+
+		if len(page) < pageSize {
+			return groups, nil
+		}
+	}
 }
 
 func (s *service) UpdatePipeline(ctx context.Context, pipeline contracts.Pipeline) (err error) {
@@ -469,6 +970,195 @@ func (s *service) dedupeOrganizations(retrievedOrganizations []*contracts.Organi
 	return organizations
 }
 
+// ApplyRoleMappings grants and revokes the groups, organizations and roles managed by the role mappings of the organization used to log in, and records changes in the audit log
+func (s *service) ApplyRoleMappings(ctx context.Context, user contracts.User, provider, organization string, identityProviderGroups []string) (mappedUser *contracts.User, err error) {
+
+	mappedUser = &user
+
+	// everything mentioned in a mapping for this provider is managed by the mappings; it's granted if any of those mappings matches and revoked otherwise
+	managedGroups, managedOrganizations, managedRoles := []string{}, []string{}, []string{}
+	grantedGroups, grantedOrganizations, grantedRoles := map[string]bool{}, map[string]bool{}, map[string]bool{}
+
+	for _, m := range s.config.Auth.GetRoleMappings(organization) {
+		if !m.AppliesToProvider(provider) {
+			continue
+		}
+		matches := m.Matches(user.Email, identityProviderGroups)
+
+		for _, g := range m.Groups {
+			managedGroups = appendIfMissing(managedGroups, g)
+			grantedGroups[g] = grantedGroups[g] || matches
+		}
+		for _, o := range m.Organizations {
+			managedOrganizations = appendIfMissing(managedOrganizations, o)
+			grantedOrganizations[o] = grantedOrganizations[o] || matches
+		}
+		for _, r := range m.Roles {
+			managedRoles = appendIfMissing(managedRoles, r)
+			grantedRoles[r] = grantedRoles[r] || matches
+		}
+	}
+
+	if len(managedGroups) == 0 && len(managedOrganizations) == 0 && len(managedRoles) == 0 {
+		return mappedUser, nil
+	}
+
+	before := getUserMemberships(user)
+
+	// copy the slices to avoid modifying the ones shared with the passed in user
+	mappedUser.Groups = append([]*contracts.Group{}, user.Groups...)
+	mappedUser.Organizations = append([]*contracts.Organization{}, user.Organizations...)
+	mappedUser.Roles = append([]*string{}, user.Roles...)
+
+	for _, name := range managedGroups {
+		index := -1
+		for i, g := range mappedUser.Groups {
+			if g != nil && g.Name == name {
+				index = i
+				break
+			}
+		}
+
+		if grantedGroups[name] && index < 0 {
+			group, err := s.getOrCreateMappedGroup(ctx, name, organization)
+			if err != nil {
+				return nil, err
+			}
+			mappedUser.Groups = append(mappedUser.Groups, &contracts.Group{
+				ID:            group.ID,
+				Name:          group.Name,
+				Organizations: group.Organizations,
+			})
+		} else if !grantedGroups[name] && index >= 0 {
+			mappedUser.Groups = append(mappedUser.Groups[:index], mappedUser.Groups[index+1:]...)
+		}
+	}
+
+	for _, name := range managedOrganizations {
+		index := -1
+		for i, o := range mappedUser.Organizations {
+			if o != nil && o.Name == name {
+				index = i
+				break
+			}
+		}
+
+		if grantedOrganizations[name] && index < 0 {
+			org, err := s.getOrCreateOrganization(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			mappedUser.Organizations = append(mappedUser.Organizations, &contracts.Organization{
+				ID:   org.ID,
+				Name: org.Name,
+			})
+		} else if !grantedOrganizations[name] && index >= 0 {
+			mappedUser.Organizations = append(mappedUser.Organizations[:index], mappedUser.Organizations[index+1:]...)
+		}
+	}
+
+	for _, role := range managedRoles {
+		if grantedRoles[role] {
+			mappedUser.AddRole(role)
+		} else {
+			mappedUser.RemoveRole(role)
+		}
+	}
+
+	after := getUserMemberships(*mappedUser)
+	if reflect.DeepEqual(before, after) {
+		return mappedUser, nil
+	}
+
+	log.Info().Msgf("Role mappings of organization %v changed memberships of user %v from %v to %v", organization, user.Email, before, after)
+
+	_, err = s.databaseClient.InsertAuditLogRecord(ctx, database.AuditLogRecord{
+		Actor:      user.Email,
+		Action:     AuditActionUserRoleMappingsApplied,
+		TargetType: AuditTargetTypeUser,
+		TargetID:   user.ID,
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed recording role mapping changes for user %v in audit log: %w", user.Email, err)
+	}
+
+	return mappedUser, nil
+}
+
+func (s *service) getOrCreateMappedGroup(ctx context.Context, name, organization string) (group *contracts.Group, err error) {
+	group, err = s.databaseClient.GetGroupByName(ctx, name)
+	if err == nil || !errors.Is(err, database.ErrGroupNotFound) {
+		return
+	}
+
+	// group doesn't exist yet, create it in the organization the mapping is configured for
+	org, err := s.getOrCreateOrganization(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CreateGroup(ctx, contracts.Group{
+		Name:        name,
+		Description: fmt.Sprintf("Created by role mappings of organization %v", organization),
+		Organizations: []*contracts.Organization{
+			{
+				ID:   org.ID,
+				Name: org.Name,
+			},
+		},
+	})
+}
+
+func (s *service) getOrCreateOrganization(ctx context.Context, name string) (organization *contracts.Organization, err error) {
+	organization, err = s.databaseClient.GetOrganizationByName(ctx, name)
+	if err == nil || !errors.Is(err, database.ErrOrganizationNotFound) {
+		return
+	}
+
+	return s.CreateOrganization(ctx, contracts.Organization{
+		Name: name,
+	})
+}
+
+func getUserMemberships(user contracts.User) (memberships userMemberships) {
+	memberships = userMemberships{
+		Groups:        []string{},
+		Organizations: []string{},
+		Roles:         []string{},
+	}
+	for _, g := range user.Groups {
+		if g != nil {
+			memberships.Groups = append(memberships.Groups, g.Name)
+		}
+	}
+	for _, o := range user.Organizations {
+		if o != nil {
+			memberships.Organizations = append(memberships.Organizations, o.Name)
+		}
+	}
+	for _, r := range user.Roles {
+		if r != nil {
+			memberships.Roles = append(memberships.Roles, *r)
+		}
+	}
+	sort.Strings(memberships.Groups)
+	sort.Strings(memberships.Organizations)
+	sort.Strings(memberships.Roles)
+
+	return
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
 func (s *service) setAdminRoleForUserIfConfigured(user *contracts.User) {
 	// check if email matches configured administrators and add/remove administrator role correspondingly
 	if s.config.Auth.IsConfiguredAsAdministrator(user.Email) {
@@ -479,3 +1169,39 @@ func (s *service) setAdminRoleForUserIfConfigured(user *contracts.User) {
 		user.RemoveRole(api.RoleAdministrator.String())
 	}
 }
+
+// hashPersonalAccessToken returns the hash stored for a personal access token, so tokens can be looked up without storing them
+func hashPersonalAccessToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// hashClientSecret returns the hash stored for a client secret; secrets are long and random, so a fast hash suffices
+func hashClientSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func getRoleNames(roles []*string) (names []string) {
+	names = []string{}
+	for _, r := range roles {
+		if r != nil {
+			names = append(names, *r)
+		}
+	}
+	sort.Strings(names)
+
+	return
+}
+
+func getOrganizationNames(organizations []*contracts.Organization) (names []string) {
+	names = []string{}
+	for _, o := range organizations {
+		if o != nil {
+			names = append(names, o.Name)
+		}
+	}
+	sort.Strings(names)
+
+	return
+}
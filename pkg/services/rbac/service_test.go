@@ -1,9 +1,15 @@
 package rbac
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 )
 
@@ -69,3 +75,815 @@ func TestDedupeOrganizations(t *testing.T) {
 		assert.Equal(t, 2, len(organizations))
 	})
 }
+
+func TestApplyRoleMappings(t *testing.T) {
+
+	config := &api.APIConfig{
+		Auth: &api.AuthConfig{
+			Organizations: []*api.AuthOrganizationConfig{
+				{
+					Name: "Org A",
+					RoleMappings: []*api.RoleMappingConfig{
+						{
+							Provider:              "keycloak",
+							IdentityProviderGroup: "ci-operators",
+							Groups:                []string{"team-platform"},
+							Roles:                 []string{"organization.pipelines.operator"},
+						},
+						{
+							EmailRegex:    ".+@ziplinee\\.io",
+							Organizations: []string{"Org A"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("GrantsGroupsOrganizationsAndRolesOfMatchingMappings", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetGroupByName(gomock.Any(), "team-platform").
+			Return(&contracts.Group{ID: "12", Name: "team-platform"}, nil)
+		databaseClient.
+			EXPECT().
+			GetOrganizationByName(gomock.Any(), "Org A").
+			Return(&contracts.Organization{ID: "5", Name: "Org A"}, nil)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, record database.AuditLogRecord) (*database.AuditLogRecord, error) {
+				assert.Equal(t, AuditActionUserRoleMappingsApplied, record.Action)
+				assert.Equal(t, "7", record.TargetID)
+				assert.Equal(t, userMemberships{Groups: []string{}, Organizations: []string{}, Roles: []string{}}, record.Before)
+				assert.Equal(t, userMemberships{Groups: []string{"team-platform"}, Organizations: []string{"Org A"}, Roles: []string{"organization.pipelines.operator"}}, record.After)
+				return &record, nil
+			})
+
+		service := NewService(config, databaseClient)
+		user := contracts.User{ID: "7", Email: "jane@ziplinee.io"}
+
+		// act
+		mappedUser, err := service.ApplyRoleMappings(context.Background(), user, "keycloak", "Org A", []string{"ci-operators"})
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, mappedUser) {
+			assert.Equal(t, 1, len(mappedUser.Groups))
+			assert.Equal(t, "12", mappedUser.Groups[0].ID)
+			assert.Equal(t, 1, len(mappedUser.Organizations))
+			assert.Equal(t, "5", mappedUser.Organizations[0].ID)
+			assert.True(t, mappedUser.HasRole("organization.pipelines.operator"))
+		}
+	})
+
+	t.Run("RevokesManagedMembershipsOfMappingsNoLongerMatching", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			Return(&database.AuditLogRecord{}, nil)
+
+		service := NewService(config, databaseClient)
+		operatorRole := "organization.pipelines.operator"
+		viewerRole := "organization.pipelines.viewer"
+		user := contracts.User{
+			ID:            "7",
+			Email:         "jane@ziplinee.io",
+			Groups:        []*contracts.Group{{ID: "12", Name: "team-platform"}, {ID: "13", Name: "team-payments"}},
+			Organizations: []*contracts.Organization{{ID: "5", Name: "Org A"}},
+			Roles:         []*string{&operatorRole, &viewerRole},
+		}
+
+		// act
+		mappedUser, err := service.ApplyRoleMappings(context.Background(), user, "keycloak", "Org A", []string{"developers"})
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, mappedUser) {
+			// manually assigned group and role are left alone
+			assert.Equal(t, 1, len(mappedUser.Groups))
+			assert.Equal(t, "team-payments", mappedUser.Groups[0].Name)
+			assert.Equal(t, 1, len(mappedUser.Organizations))
+			assert.False(t, mappedUser.HasRole(operatorRole))
+			assert.True(t, mappedUser.HasRole(viewerRole))
+		}
+		// the passed in user is not modified
+		assert.Equal(t, 2, len(user.Groups))
+	})
+
+	t.Run("DoesNotRecordAuditLogIfMembershipsAreUnchanged", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient)
+		user := contracts.User{
+			ID:            "7",
+			Email:         "jane@ziplinee.io",
+			Organizations: []*contracts.Organization{{ID: "5", Name: "Org A"}},
+		}
+
+		// act
+		_, err := service.ApplyRoleMappings(context.Background(), user, "github", "Org A", []string{})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("CreatesMissingGroupInOrganizationOfMapping", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetGroupByName(gomock.Any(), "team-platform").
+			Return(nil, database.ErrGroupNotFound)
+		databaseClient.
+			EXPECT().
+			GetOrganizationByName(gomock.Any(), "Org A").
+			Return(&contracts.Organization{ID: "5", Name: "Org A"}, nil).
+			Times(2)
+		databaseClient.
+			EXPECT().
+			InsertGroup(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, group contracts.Group) (*contracts.Group, error) {
+				assert.Equal(t, "team-platform", group.Name)
+				if assert.Equal(t, 1, len(group.Organizations)) {
+					assert.Equal(t, "5", group.Organizations[0].ID)
+				}
+				group.ID = "14"
+				return &group, nil
+			})
+		databaseClient.
+			EXPECT().
+			InsertAuditLogRecord(gomock.Any(), gomock.Any()).
+			Return(&database.AuditLogRecord{}, nil)
+
+		service := NewService(config, databaseClient)
+		user := contracts.User{ID: "7", Email: "jane@ziplinee.io"}
+
+		// act
+		mappedUser, err := service.ApplyRoleMappings(context.Background(), user, "keycloak", "Org A", []string{"ci-operators"})
+
+		assert.Nil(t, err)
+		if assert.NotNil(t, mappedUser) && assert.Equal(t, 1, len(mappedUser.Groups)) {
+			assert.Equal(t, "14", mappedUser.Groups[0].ID)
+		}
+	})
+
+	t.Run("ReturnsUserUnchangedForOrganizationWithoutMappings", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		service := NewService(config, databaseClient)
+		user := contracts.User{ID: "7", Email: "jane@ziplinee.io"}
+
+		// act
+		mappedUser, err := service.ApplyRoleMappings(context.Background(), user, "keycloak", "", []string{"ci-operators"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, user, *mappedUser)
+	})
+}
+
+func TestCreateCustomRole(t *testing.T) {
+	t.Run("InsertsValidCustomRole", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertCustomRole(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, customRole api.CustomRole) (*api.CustomRole, error) {
+				customRole.ID = "15"
+				customRole.Active = true
+				return &customRole, nil
+			})
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		insertedCustomRole, err := service.CreateCustomRole(context.Background(), api.CustomRole{
+			Name:        "payments-releaser",
+			Permissions: []string{"ci.releases.create"},
+			Bindings: []*api.CustomRoleBinding{
+				{
+					Groups:         []string{"team-payments"},
+					Labels:         []contracts.Label{{Key: "team", Value: "payments"}},
+					ReleaseTargets: []string{"production"},
+				},
+			},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "15", insertedCustomRole.ID)
+	})
+
+	t.Run("ReturnsErrInvalidCustomRoleForUnknownPermission", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertCustomRole(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		_, err := service.CreateCustomRole(context.Background(), api.CustomRole{
+			Name:        "payments-releaser",
+			Permissions: []string{"ci.releases.launch"},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidCustomRole)
+	})
+}
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	t.Run("StoresHashOfTokenOnly", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var storedHash string
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertPersonalAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token api.PersonalAccessToken, tokenHash string) (*api.PersonalAccessToken, error) {
+				storedHash = tokenHash
+				token.ID = "7"
+				return &token, nil
+			})
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+		expiresAt := time.Now().UTC().Add(30 * 24 * time.Hour)
+
+		// act
+		insertedToken, err := service.CreatePersonalAccessToken(context.Background(), "15", api.PersonalAccessToken{
+			Name:      "release-script",
+			Scopes:    []string{"ci.releases.create"},
+			ExpiresAt: &expiresAt,
+		})
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, "15", insertedToken.UserID)
+			assert.True(t, strings.HasPrefix(insertedToken.Token, api.PersonalAccessTokenPrefix))
+			assert.NotEqual(t, insertedToken.Token, storedHash)
+			assert.Equal(t, hashPersonalAccessToken(insertedToken.Token), storedHash)
+		}
+	})
+
+	t.Run("ReturnsErrInvalidPersonalAccessTokenWithoutExpiry", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertPersonalAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		_, err := service.CreatePersonalAccessToken(context.Background(), "15", api.PersonalAccessToken{
+			Name:   "release-script",
+			Scopes: []string{"ci.releases.create"},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidPersonalAccessToken)
+	})
+}
+
+func TestAuthenticatePersonalAccessToken(t *testing.T) {
+	t.Run("ReturnsUserOfTokenAndUpdatesLastUsed", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expiresAt := time.Now().UTC().Add(time.Hour)
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPersonalAccessTokenByHash(gomock.Any(), hashPersonalAccessToken("zpat_abc")).
+			Return(&api.PersonalAccessToken{ID: "7", UserID: "15", Scopes: []string{"ci.builds.cancel"}, ExpiresAt: &expiresAt, Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetUserByID(gomock.Any(), "15", gomock.Any()).
+			Return(&contracts.User{ID: "15", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			UpdatePersonalAccessTokenLastUsed(gomock.Any(), "7").
+			Return(nil).
+			Times(1)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		user, token, err := service.AuthenticatePersonalAccessToken(context.Background(), "zpat_abc")
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, "15", user.ID)
+			assert.Equal(t, "7", token.ID)
+		}
+	})
+
+	t.Run("ReturnsErrPersonalAccessTokenExpiredForExpiredToken", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expiresAt := time.Now().UTC().Add(-time.Hour)
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPersonalAccessTokenByHash(gomock.Any(), gomock.Any()).
+			Return(&api.PersonalAccessToken{ID: "7", UserID: "15", ExpiresAt: &expiresAt, Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetUserByID(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		_, _, err := service.AuthenticatePersonalAccessToken(context.Background(), "zpat_abc")
+
+		assert.ErrorIs(t, err, ErrPersonalAccessTokenExpired)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	t.Run("RevokesSessionsOfUser", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetUserByID(gomock.Any(), "15", gomock.Any()).
+			Return(&contracts.User{ID: "15", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			DeleteUser(gomock.Any(), gomock.Any()).
+			Return(nil)
+		databaseClient.
+			EXPECT().
+			RevokeSessionsForSubject(gomock.Any(), "15").
+			Return([]string{"a", "b"}, nil).
+			Times(1)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		err := service.DeleteUser(context.Background(), "15")
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	t.Run("RevokesSessionsIfRolesChange", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetUserByID(gomock.Any(), "15", gomock.Any()).
+			Return(&contracts.User{ID: "15", Active: true, Roles: []*string{}}, nil)
+		databaseClient.
+			EXPECT().
+			UpdateUser(gomock.Any(), gomock.Any()).
+			Return(nil)
+		databaseClient.
+			EXPECT().
+			RevokeSessionsForSubject(gomock.Any(), "15").
+			Return([]string{"a"}, nil).
+			Times(1)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+		role := api.RoleUserViewer.String()
+
+		// act
+		err := service.UpdateUser(context.Background(), contracts.User{ID: "15", Active: true, Roles: []*string{&role}})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("DoesNotRevokeSessionsIfMembershipsAreUnchanged", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		role := api.RoleUserViewer.String()
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetUserByID(gomock.Any(), "15", gomock.Any()).
+			Return(&contracts.User{ID: "15", Name: "Before", Active: true, Roles: []*string{&role}}, nil)
+		databaseClient.
+			EXPECT().
+			UpdateUser(gomock.Any(), gomock.Any()).
+			Return(nil)
+		databaseClient.
+			EXPECT().
+			RevokeSessionsForSubject(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		err := service.UpdateUser(context.Background(), contracts.User{ID: "15", Name: "After", Active: true, Roles: []*string{&role}})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestIsSessionRevoked(t *testing.T) {
+	t.Run("ReturnsFalseForUnknownSession", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetSessionByID(gomock.Any(), "abc").
+			Return(nil, database.ErrSessionNotFound)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		revoked, err := service.IsSessionRevoked(context.Background(), "abc")
+
+		assert.Nil(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("ReturnsTrueForRevokedSession", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		revokedAt := time.Now().UTC()
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetSessionByID(gomock.Any(), "abc").
+			Return(&api.Session{ID: "abc", SubjectID: "15", RevokedAt: &revokedAt}, nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		revoked, err := service.IsSessionRevoked(context.Background(), "abc")
+
+		assert.Nil(t, err)
+		assert.True(t, revoked)
+	})
+}
+
+func TestAuthenticateClient(t *testing.T) {
+	t.Run("ReturnsClientForHashedSecret", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByClientID(gomock.Any(), "client-id").
+			Return(&contracts.Client{ID: "25", ClientID: "client-id", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretByHash(gomock.Any(), "25", hashClientSecret("my secret")).
+			Return(&api.ClientSecret{ID: "7", ClientID: "25", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			UpdateClientSecretLastUsed(gomock.Any(), "7").
+			Return(nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		client, err := service.AuthenticateClient(context.Background(), "client-id", "my secret")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "25", client.ID)
+	})
+
+	t.Run("HashesPlainTextSecretOfClientCreatedBeforeSecretsWereHashed", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByClientID(gomock.Any(), "client-id").
+			Return(&contracts.Client{ID: "25", ClientID: "client-id", ClientSecret: "my secret", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretByHash(gomock.Any(), "25", hashClientSecret("my secret")).
+			Return(nil, database.ErrClientSecretNotFound)
+		databaseClient.
+			EXPECT().
+			HashLegacyClientSecret(gomock.Any(), gomock.Any(), hashClientSecret("my secret")).
+			DoAndReturn(func(ctx context.Context, client contracts.Client, secretHash string) (bool, error) {
+				assert.Equal(t, "my secret", client.ClientSecret)
+				return true, nil
+			})
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		client, err := service.AuthenticateClient(context.Background(), "client-id", "my secret")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "25", client.ID)
+		assert.Equal(t, "", client.ClientSecret)
+	})
+
+	t.Run("ReturnsClientIfConcurrentRequestHashedPlainTextSecretAlready", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByClientID(gomock.Any(), "client-id").
+			Return(&contracts.Client{ID: "25", ClientID: "client-id", ClientSecret: "my secret", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretByHash(gomock.Any(), "25", hashClientSecret("my secret")).
+			Return(nil, database.ErrClientSecretNotFound)
+		databaseClient.
+			EXPECT().
+			HashLegacyClientSecret(gomock.Any(), gomock.Any(), hashClientSecret("my secret")).
+			Return(false, nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		client, err := service.AuthenticateClient(context.Background(), "client-id", "my secret")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "25", client.ID)
+	})
+
+	t.Run("ReturnsErrInvalidClientCredentialsForWrongSecret", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByClientID(gomock.Any(), "client-id").
+			Return(&contracts.Client{ID: "25", ClientID: "client-id", ClientSecret: "my secret", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretByHash(gomock.Any(), "25", hashClientSecret("guessed secret")).
+			Return(nil, database.ErrClientSecretNotFound)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		_, err := service.AuthenticateClient(context.Background(), "client-id", "guessed secret")
+
+		assert.ErrorIs(t, err, ErrInvalidClientCredentials)
+	})
+
+	t.Run("ReturnsErrInvalidClientCredentialsForUnknownClient", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByClientID(gomock.Any(), "client-id").
+			Return(nil, database.ErrClientNotFound)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		_, err := service.AuthenticateClient(context.Background(), "client-id", "my secret")
+
+		assert.ErrorIs(t, err, ErrInvalidClientCredentials)
+	})
+}
+
+func TestCreateClientSecret(t *testing.T) {
+	t.Run("ReturnsSecretOnlyStoringItsHash", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var storedHash string
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByID(gomock.Any(), "25").
+			Return(&contracts.Client{ID: "25", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretsForClient(gomock.Any(), "25").
+			Return([]*api.ClientSecret{{ID: "7", ClientID: "25", Active: true}}, nil)
+		databaseClient.
+			EXPECT().
+			InsertClientSecret(gomock.Any(), api.ClientSecret{ClientID: "25"}, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, secret api.ClientSecret, secretHash string) (*api.ClientSecret, error) {
+				storedHash = secretHash
+				return &api.ClientSecret{ID: "8", ClientID: "25", Active: true}, nil
+			})
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		secret, err := service.CreateClientSecret(context.Background(), "25")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "8", secret.ID)
+		assert.Equal(t, 64, len(secret.Secret))
+		assert.Equal(t, hashClientSecret(secret.Secret), storedHash)
+	})
+
+	t.Run("ReturnsErrTooManyClientSecretsIfClientHasTwoActiveSecrets", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByID(gomock.Any(), "25").
+			Return(&contracts.Client{ID: "25", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretsForClient(gomock.Any(), "25").
+			Return([]*api.ClientSecret{{ID: "7", ClientID: "25", Active: true}, {ID: "8", ClientID: "25", Active: true}}, nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		_, err := service.CreateClientSecret(context.Background(), "25")
+
+		assert.ErrorIs(t, err, ErrTooManyClientSecrets)
+	})
+}
+
+func TestDeleteClientSecret(t *testing.T) {
+	t.Run("ReturnsErrLastClientSecretForOnlyActiveSecret", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByID(gomock.Any(), "25").
+			Return(&contracts.Client{ID: "25", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretsForClient(gomock.Any(), "25").
+			Return([]*api.ClientSecret{{ID: "7", ClientID: "25", Active: true}}, nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		err := service.DeleteClientSecret(context.Background(), "25", "7")
+
+		assert.ErrorIs(t, err, ErrLastClientSecret)
+	})
+
+	t.Run("ReturnsErrClientSecretNotFoundForSecretOfOtherClient", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetClientByID(gomock.Any(), "25").
+			Return(&contracts.Client{ID: "25", Active: true}, nil)
+		databaseClient.
+			EXPECT().
+			GetClientSecretsForClient(gomock.Any(), "25").
+			Return([]*api.ClientSecret{{ID: "7", ClientID: "25", Active: true}, {ID: "8", ClientID: "25", Active: true}}, nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient)
+
+		// act
+		err := service.DeleteClientSecret(context.Background(), "25", "9")
+
+		assert.ErrorIs(t, err, database.ErrClientSecretNotFound)
+	})
+}
+
+func TestOrganizationScope(t *testing.T) {
+
+	viewer := api.RoleUserViewer.String()
+	admin := api.RoleAdministrator.String()
+	scope := &organizationScope{
+		organizations: []string{"Org A"},
+		roles:         []string{api.RoleOrganizationManager.String(), viewer},
+	}
+
+	t.Run("NilScopeAllowsEverything", func(t *testing.T) {
+
+		var unscoped *organizationScope
+
+		assert.True(t, unscoped.manages([]*contracts.Organization{{Name: "Org B"}}, []*string{&admin}))
+		assert.True(t, unscoped.allowsOrganizationChanges(nil, []*contracts.Organization{{Name: "Org B"}}))
+		assert.Equal(t, 0, len(unscoped.filters(map[api.FilterType][]string{})))
+	})
+
+	t.Run("FiltersOnOrganizationsOfScope", func(t *testing.T) {
+
+		// act
+		filters := scope.filters(map[api.FilterType][]string{})
+
+		assert.Equal(t, []string{"Org A"}, filters[api.FilterOrganizations])
+	})
+
+	t.Run("OverlapsIfAnyOrganizationIsWithinScope", func(t *testing.T) {
+
+		assert.True(t, scope.overlaps([]*contracts.Organization{{Name: "Org B"}, {Name: "Org A"}}))
+		assert.False(t, scope.overlaps([]*contracts.Organization{{Name: "Org B"}}))
+		assert.False(t, scope.overlaps(nil))
+	})
+
+	t.Run("ContainsOnlyIfAllOrganizationsAreWithinScope", func(t *testing.T) {
+
+		assert.True(t, scope.contains([]*contracts.Organization{{Name: "Org A"}}))
+		assert.False(t, scope.contains([]*contracts.Organization{{Name: "Org A"}, {Name: "Org B"}}))
+		assert.False(t, scope.contains(nil))
+	})
+
+	t.Run("ManagesOnlyRolesItHolds", func(t *testing.T) {
+
+		assert.True(t, scope.manages([]*contracts.Organization{{Name: "Org A"}}, []*string{&viewer}))
+		assert.False(t, scope.manages([]*contracts.Organization{{Name: "Org A"}}, []*string{&admin}))
+	})
+
+	t.Run("AllowsOnlyOrganizationChangesWithinScope", func(t *testing.T) {
+
+		before := []*contracts.Organization{{Name: "Org B"}}
+
+		assert.True(t, scope.allowsOrganizationChanges(before, []*contracts.Organization{{Name: "Org B"}, {Name: "Org A"}}))
+		assert.False(t, scope.allowsOrganizationChanges(before, []*contracts.Organization{{Name: "Org A"}}))
+		assert.False(t, scope.allowsOrganizationChanges(before, []*contracts.Organization{{Name: "Org B"}, {Name: "Org C"}}))
+	})
+
+	t.Run("AllowsOnlyRoleChangesItHolds", func(t *testing.T) {
+
+		before := []*string{&admin}
+
+		assert.True(t, scope.allowsRoleChanges(before, []*string{&admin, &viewer}))
+		assert.False(t, scope.allowsRoleChanges(before, []*string{&viewer}))
+	})
+}
+
+func TestGetChangedGroups(t *testing.T) {
+
+	t.Run("ReturnsAddedAndRemovedGroups", func(t *testing.T) {
+
+		before := []*contracts.Group{{ID: "1", Name: "Team A"}, {ID: "2", Name: "Team B"}}
+		after := []*contracts.Group{{ID: "2", Name: "Team B"}, {ID: "3", Name: "Team C"}}
+
+		// act
+		changed := getChangedGroups(before, after)
+
+		assert.Equal(t, 2, len(changed))
+		assert.Equal(t, "1", changed[0].ID)
+		assert.Equal(t, "3", changed[1].ID)
+	})
+}
@@ -157,3 +157,122 @@ func (s *tracingService) GetInheritedOrganizationsForUser(ctx context.Context, u
 
 	return s.Service.GetInheritedOrganizationsForUser(ctx, user)
 }
+
+func (s *tracingService) ApplyRoleMappings(ctx context.Context, user contracts.User, provider, organization string, identityProviderGroups []string) (mappedUser *contracts.User, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "ApplyRoleMappings"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.ApplyRoleMappings(ctx, user, provider, organization, identityProviderGroups)
+}
+
+func (s *tracingService) CreateCustomRole(ctx context.Context, customRole api.CustomRole) (insertedCustomRole *api.CustomRole, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "CreateCustomRole"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.CreateCustomRole(ctx, customRole)
+}
+
+func (s *tracingService) UpdateCustomRole(ctx context.Context, customRole api.CustomRole) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "UpdateCustomRole"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.UpdateCustomRole(ctx, customRole)
+}
+
+func (s *tracingService) DeleteCustomRole(ctx context.Context, id string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "DeleteCustomRole"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.DeleteCustomRole(ctx, id)
+}
+
+func (s *tracingService) CreatePersonalAccessToken(ctx context.Context, userID string, token api.PersonalAccessToken) (insertedToken *api.PersonalAccessToken, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "CreatePersonalAccessToken"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.CreatePersonalAccessToken(ctx, userID, token)
+}
+
+func (s *tracingService) DeletePersonalAccessToken(ctx context.Context, userID, id string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "DeletePersonalAccessToken"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.DeletePersonalAccessToken(ctx, userID, id)
+}
+
+func (s *tracingService) AuthenticatePersonalAccessToken(ctx context.Context, token string) (user *contracts.User, personalAccessToken *api.PersonalAccessToken, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "AuthenticatePersonalAccessToken"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.AuthenticatePersonalAccessToken(ctx, token)
+}
+
+func (s *tracingService) CreateSession(ctx context.Context, session api.Session) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "CreateSession"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.CreateSession(ctx, session)
+}
+
+func (s *tracingService) RevokeSession(ctx context.Context, id string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "RevokeSession"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.RevokeSession(ctx, id)
+}
+
+func (s *tracingService) IsSessionRevoked(ctx context.Context, id string) (revoked bool, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "IsSessionRevoked"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.IsSessionRevoked(ctx, id)
+}
+
+func (s *tracingService) GetActiveSessionsForSubject(ctx context.Context, subjectID string) (sessions []*api.Session, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "GetActiveSessionsForSubject"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.GetActiveSessionsForSubject(ctx, subjectID)
+}
+
+func (s *tracingService) RevokeSessionForSubject(ctx context.Context, subjectID, id string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "RevokeSessionForSubject"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.RevokeSessionForSubject(ctx, subjectID, id)
+}
+
+func (s *tracingService) RevokeSessionsForSubject(ctx context.Context, subjectID string) (revokedSessionIDs []string, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "RevokeSessionsForSubject"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.RevokeSessionsForSubject(ctx, subjectID)
+}
+
+func (s *tracingService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (client *contracts.Client, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "AuthenticateClient"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.AuthenticateClient(ctx, clientID, clientSecret)
+}
+
+func (s *tracingService) GetClientSecrets(ctx context.Context, id string) (secrets []*api.ClientSecret, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "GetClientSecrets"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.GetClientSecrets(ctx, id)
+}
+
+func (s *tracingService) CreateClientSecret(ctx context.Context, id string) (insertedSecret *api.ClientSecret, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "CreateClientSecret"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.CreateClientSecret(ctx, id)
+}
+
+func (s *tracingService) DeleteClientSecret(ctx context.Context, id, secretID string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "DeleteClientSecret"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.DeleteClientSecret(ctx, id, secretID)
+}
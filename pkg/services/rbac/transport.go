@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -15,7 +17,7 @@ import (
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
 	jwtgo "github.com/golang-jwt/jwt/v4"
-	"github.com/opentracing/opentracing-go"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/bitbucketapi"
@@ -26,6 +28,13 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
+const (
+	// loginNonceCookieName is the cookie holding the nonce of a login started from this browser, which has to match the nonce in the state
+	// and, for oidc providers, in the id token
+	loginNonceCookieName = "ziplinee_login_nonce"
+	loginNonceCookiePath = "/api/auth/handle/"
+)
+
 // NewHandler returns a new rbac.Handler
 func NewHandler(config *api.APIConfig, service Service, databaseClient database.Client, bitbucketapiClient bitbucketapi.Client, githubapiClient githubapi.Client) Handler {
 	return Handler{
@@ -60,6 +69,221 @@ func (h *Handler) GetLoggedInUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, user)
 }
+func (h *Handler) GetPersonalAccessTokens(c *gin.Context) {
+
+	// personal access tokens can't be used to manage personal access tokens
+	if !api.RequestTokenIsValid(c) || api.GetPersonalAccessTokenIDFromRequest(c) != "" {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	claims := jwt.ExtractClaims(c)
+	id := claims[jwt.IdentityKey].(string)
+
+	ctx := c.Request.Context()
+
+	tokens, err := h.databaseClient.GetPersonalAccessTokensForUser(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving personal access tokens for user %v from db", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *Handler) CreatePersonalAccessToken(c *gin.Context) {
+
+	// personal access tokens can't be used to manage personal access tokens
+	if !api.RequestTokenIsValid(c) || api.GetPersonalAccessTokenIDFromRequest(c) != "" {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	// a token created while impersonating would outlive the impersonation and no longer show who acts as the user
+	if api.GetImpersonatorFromRequest(c) != "" {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "Personal access tokens can't be created while impersonating a user"})
+		return
+	}
+
+	var token api.PersonalAccessToken
+	err := c.BindJSON(&token)
+	if err != nil {
+		errorMessage := "Binding CreatePersonalAccessToken body failed"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	// a token can only be scoped to permissions the user has
+	for _, s := range token.Scopes {
+		if permission := api.ToPermission(s); permission != nil && !api.RequestTokenHasPermission(c, *permission) {
+			c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": fmt.Sprintf("Request does not have permission %v to grant to personal access token", s)})
+			return
+		}
+	}
+
+	claims := jwt.ExtractClaims(c)
+	userID := claims[jwt.IdentityKey].(string)
+
+	ctx := c.Request.Context()
+
+	insertedToken, err := h.service.CreatePersonalAccessToken(ctx, userID, token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPersonalAccessToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Failed inserting personal access token")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	auditedToken := *insertedToken
+	auditedToken.Token = ""
+	api.AddAuditDetails(c, auditAction(auditTargetTypePersonalAccessToken, auditActionCreated), auditTargetTypePersonalAccessToken, insertedToken.ID, nil, auditedToken)
+
+	// the token is only returned this once
+	c.JSON(http.StatusCreated, insertedToken)
+}
+
+func (h *Handler) DeletePersonalAccessToken(c *gin.Context) {
+
+	// personal access tokens can't be used to manage personal access tokens
+	if !api.RequestTokenIsValid(c) || api.GetPersonalAccessTokenIDFromRequest(c) != "" {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	claims := jwt.ExtractClaims(c)
+	userID := claims[jwt.IdentityKey].(string)
+
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	err := h.service.DeletePersonalAccessToken(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, database.ErrPersonalAccessTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		log.Error().Err(err).Msg("Failed deleting personal access token")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	api.AddAuditDetails(c, auditAction(auditTargetTypePersonalAccessToken, auditActionDeleted), auditTargetTypePersonalAccessToken, id, nil, nil)
+
+	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
+}
+
+func (h *Handler) GetSessions(c *gin.Context) {
+
+	if !api.RequestTokenIsValid(c) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	claims := jwt.ExtractClaims(c)
+	id := claims[jwt.IdentityKey].(string)
+
+	ctx := c.Request.Context()
+
+	sessions, err := h.service.GetActiveSessionsForSubject(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving sessions for %v from db", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	currentSessionID := api.GetSessionIDFromRequest(c)
+	for _, s := range sessions {
+		s.Current = currentSessionID != "" && s.ID == currentSessionID
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *Handler) DeleteSessions(c *gin.Context) {
+
+	// personal access tokens can't be used to log out sessions
+	if !api.RequestTokenIsValid(c) || api.GetPersonalAccessTokenIDFromRequest(c) != "" {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	claims := jwt.ExtractClaims(c)
+	id := claims[jwt.IdentityKey].(string)
+
+	h.revokeSessionsForSubject(c, id)
+}
+
+func (h *Handler) DeleteSession(c *gin.Context) {
+
+	// personal access tokens can't be used to log out sessions
+	if !api.RequestTokenIsValid(c) || api.GetPersonalAccessTokenIDFromRequest(c) != "" {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	claims := jwt.ExtractClaims(c)
+	subjectID := claims[jwt.IdentityKey].(string)
+
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	err := h.service.RevokeSessionForSubject(ctx, subjectID, id)
+	if err != nil {
+		if errors.Is(err, database.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		log.Error().Err(err).Msg("Failed revoking session")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	api.AddAuditDetails(c, auditAction(auditTargetTypeSession, auditActionRevoked), auditTargetTypeSession, id, nil, nil)
+
+	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
+}
+
+func (h *Handler) DeleteUserSessions(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionUsersUpdate) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	h.revokeSessionsForSubject(c, c.Param("id"))
+}
+
+func (h *Handler) revokeSessionsForSubject(c *gin.Context, subjectID string) {
+
+	ctx := c.Request.Context()
+
+	revokedSessionIDs, err := h.service.RevokeSessionsForSubject(ctx, subjectID)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed revoking sessions for %v", subjectID)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	for _, id := range revokedSessionIDs {
+		api.AddAuditDetails(c, auditAction(auditTargetTypeSession, auditActionRevoked), auditTargetTypeSession, id, nil, gin.H{"subjectID": subjectID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK), "revoked": len(revokedSessionIDs)})
+}
+
+// HandlePersonalAccessTokenAuthenticator returns the user for a personal access token, to authenticate requests using one
+func (h *Handler) HandlePersonalAccessTokenAuthenticator() func(ctx context.Context, token string) (*contracts.User, *api.PersonalAccessToken, error) {
+	return func(ctx context.Context, token string) (*contracts.User, *api.PersonalAccessToken, error) {
+		return h.service.AuthenticatePersonalAccessToken(ctx, token)
+	}
+}
+
 func (h *Handler) GetRoles(c *gin.Context) {
 
 	// ensure the request has the correct permission
@@ -109,6 +333,19 @@ func (h *Handler) GetProviders(c *gin.Context) {
 	c.JSON(http.StatusOK, responseItems)
 }
 
+// GetJSONWebKeySet returns the public keys of the asymmetric jwt signing keys, for other services to verify jwts issued by this api
+func (h *Handler) GetJSONWebKeySet(c *gin.Context) {
+
+	keySet, err := h.config.Auth.JWT.GetJSONWebKeySet()
+	if err != nil {
+		log.Error().Err(err).Msg("Retrieving json web key set failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, keySet)
+}
+
 func (h *Handler) LoginProvider(c *gin.Context) {
 
 	ctx := c.Request.Context()
@@ -133,16 +370,33 @@ func (h *Handler) LoginProvider(c *gin.Context) {
 		optionalClaims["organization"] = organization
 	}
 
+	// tie the state to this browser with a nonce that's stored in a cookie as well
+	nonce := uuid.New().String()
+	optionalClaims["nonce"] = nonce
+
 	// generate jwt to use as state
 	now := time.Now().UTC()
-	expiry := now.Add(time.Duration(10) * time.Minute)
+	loginTimeout := time.Duration(10) * time.Minute
+	expiry := now.Add(loginTimeout)
 	state, err := api.GenerateJWT(h.config, now, expiry, optionalClaims)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed generating JWT to use as state")
 		c.String(http.StatusInternalServerError, "Failed generating JWT to use as state")
+		return
 	}
 
-	c.Redirect(http.StatusTemporaryRedirect, provider.AuthCodeURL(h.config.APIServer.BaseURL, state))
+	authCodeURL := provider.AuthCodeURL(h.config.APIServer.BaseURL, state, nonce)
+	if authCodeURL == "" {
+		log.Error().Msgf("Failed retrieving login url for provider %v", provider.Name)
+		c.String(http.StatusInternalServerError, "Failed retrieving login url for provider")
+		return
+	}
+
+	// lax, because the provider redirects back with a top level navigation from its own site
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(loginNonceCookieName, nonce, int(loginTimeout.Seconds()), loginNonceCookiePath, "", true, true)
+
+	c.Redirect(http.StatusTemporaryRedirect, authCodeURL)
 }
 
 func (h *Handler) HandleOAuthLoginProviderAuthenticator() func(c *gin.Context) (interface{}, error) {
@@ -158,6 +412,14 @@ func (h *Handler) HandleOAuthLoginProviderAuthenticator() func(c *gin.Context) (
 			return nil, err
 		}
 
+		// the state has to belong to a login started from this browser
+		nonce, _ := claims["nonce"].(string)
+		nonceCookie, err := c.Cookie(loginNonceCookieName)
+		if err != nil || nonce == "" || nonceCookie != nonce {
+			return nil, fmt.Errorf("Login state does not belong to a login started from this browser")
+		}
+		c.SetCookie(loginNonceCookieName, "", -1, loginNonceCookiePath, "", true, true)
+
 		// get optional return url from claim and store in gin context to use in LoginResponse handler for jwt middleware
 		if returnURL, ok := claims["returnURL"]; ok {
 			c.Set("returnURL", returnURL)
@@ -180,13 +442,16 @@ func (h *Handler) HandleOAuthLoginProviderAuthenticator() func(c *gin.Context) (
 
 		// retrieve oauth config
 		cfg := provider.GetConfig(h.config.APIServer.BaseURL)
+		if cfg == nil {
+			return nil, fmt.Errorf("Failed retrieving oauth config for provider %v", provider.Name)
+		}
 		token, err := cfg.Exchange(ctx, code)
 		if err != nil {
 			return nil, err
 		}
 
 		// fetch identity from oauth provider api
-		identity, err := provider.GetUserIdentity(ctx, cfg, token)
+		identity, err := provider.GetUserIdentity(ctx, cfg, token, nonce)
 		if err != nil {
 			return nil, err
 		}
@@ -274,17 +539,22 @@ func (h *Handler) HandleOAuthLoginProviderAuthenticator() func(c *gin.Context) (
 			}
 		}
 
-		go func(user contracts.User) {
-			// create new context to avoid cancellation impacting execution
-			span, _ := opentracing.StartSpanFromContext(c.Request.Context(), "rbac:AsyncUpdateUser")
-			ctx := opentracing.ContextWithSpan(context.Background(), span)
-			defer span.Finish()
+		// grant and revoke groups, organizations and roles with the role mappings of the organization used to log in
+		identityProviderGroups, err := provider.GetUserGroups(ctx, cfg, token, nonce)
+		if err != nil {
+			return nil, err
+		}
+		user, err = h.service.ApplyRoleMappings(ctx, *user, name, organization, identityProviderGroups)
+		if err != nil {
+			return nil, err
+		}
 
-			err = h.service.UpdateUser(ctx, user)
-			if err != nil {
-				log.Warn().Err(err).Msg("Failed updating user in db")
-			}
-		}(*user)
+		// updating the user revokes its sessions if its memberships changed, so this has to happen before the session for this
+		// login is created; otherwise the new session could be revoked as well
+		err = h.service.UpdateUser(ctx, *user)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed updating user in db")
+		}
 
 		// get all roles the user inherits from groups and organizations
 		inheritedRoles, err := h.service.GetInheritedRolesForUser(ctx, *user)
@@ -318,31 +588,172 @@ func (h *Handler) HandleClientLoginProviderAuthenticator() func(c *gin.Context)
 
 		ctx := c.Request.Context()
 
-		// get client from db by clientID
-		clientFromDB, err := h.databaseClient.GetClientByClientID(ctx, client.ClientID)
+		clientFromDB, err := h.service.AuthenticateClient(ctx, client.ClientID, client.ClientSecret)
 		if err != nil {
-			log.Error().Err(err).Msgf("Failed retrieving client by client id %v", client.ClientID)
+			log.Error().Err(err).Msgf("Failed authenticating client with client id %v", client.ClientID)
 			return nil, err
 		}
-		if clientFromDB == nil {
-			log.Error().Err(err).Msgf("Client for client id %v is nil", client.ClientID)
-			return nil, fmt.Errorf("Client from db is nil")
+
+		return clientFromDB, nil
+	}
+}
+
+// PostOAuthToken is the oauth2 token endpoint, issuing access tokens to clients with the client credentials grant,
+// see https://datatracker.ietf.org/doc/html/rfc6749#section-4.4
+func (h *Handler) PostOAuthToken(c *gin.Context) {
+
+	// responses contain credentials, so they mustn't be cached
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	grantType := c.PostForm("grant_type")
+	if grantType == "" {
+		oauthError(c, http.StatusBadRequest, api.OAuthErrorInvalidRequest, "The grant_type parameter is required")
+		return
+	}
+	if grantType != api.OAuthGrantTypeClientCredentials {
+		oauthError(c, http.StatusBadRequest, api.OAuthErrorUnsupportedGrantType, fmt.Sprintf("Only the %v grant is supported", api.OAuthGrantTypeClientCredentials))
+		return
+	}
+
+	client, ok := h.authenticateOAuthClient(c)
+	if !ok {
+		return
+	}
+
+	// a client can request a subset of its permissions as scopes
+	permissions := api.GetPermissionsForRoles(client.Roles)
+	scopes, err := api.GetOAuthScopes(c.PostForm("scope"), permissions)
+	if err != nil {
+		oauthError(c, http.StatusBadRequest, api.OAuthErrorInvalidScope, err.Error())
+		return
+	}
+
+	token, session, err := api.GenerateClientAccessToken(h.config, time.Now(), client, scopes)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed generating access token for client %v", client.ID)
+		oauthError(c, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	session.UserAgent = c.Request.UserAgent()
+	session.IPAddress = c.ClientIP()
+	err = h.service.CreateSession(ctx, session)
+	if err != nil {
+		// failing to record the session doesn't fail issuing the token, the session just can't be listed
+		log.Error().Err(err).Msgf("Failed recording session %v for client %v", session.ID, client.ID)
+	}
+
+	if len(scopes) == 0 {
+		scopes = permissions
+	}
+
+	c.JSON(http.StatusOK, api.OAuthTokenResponse{
+		AccessToken: token,
+		TokenType:   api.OAuthTokenTypeBearer,
+		ExpiresIn:   int(api.ClientAccessTokenLifetime.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	})
+}
+
+// PostOAuthIntrospect is the oauth2 token introspection endpoint, for clients to check whether a token issued by this api is
+// still active, see https://datatracker.ietf.org/doc/html/rfc7662
+func (h *Handler) PostOAuthIntrospect(c *gin.Context) {
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	// only clients can introspect tokens, so tokens can't be scanned anonymously
+	_, ok := h.authenticateOAuthClient(c)
+	if !ok {
+		return
+	}
+
+	token := c.PostForm("token")
+	if token == "" {
+		oauthError(c, http.StatusBadRequest, api.OAuthErrorInvalidRequest, "The token parameter is required")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.introspectToken(c.Request.Context(), token))
+}
+
+// authenticateOAuthClient returns the client authenticating an oauth2 request, or responds with an error if it fails
+func (h *Handler) authenticateOAuthClient(c *gin.Context) (client *contracts.Client, ok bool) {
+
+	clientID, clientSecret, usedBasicAuth := getOAuthClientCredentials(c)
+
+	client, err := h.service.AuthenticateClient(c.Request.Context(), clientID, clientSecret)
+	if err != nil {
+		if errors.Is(err, ErrInvalidClientCredentials) {
+			log.Warn().Msgf("Failed authenticating client with client id %v", clientID)
+			if usedBasicAuth {
+				c.Header("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", h.config.Auth.JWT.Domain))
+			}
+			oauthError(c, http.StatusUnauthorized, api.OAuthErrorInvalidClient, "Client authentication failed")
+			return nil, false
 		}
 
-		// see if secret from binded data and database match
-		if client.ClientSecret != clientFromDB.ClientSecret {
-			log.Error().Err(err).Msgf("Client secret for client id %v does not match", client.ClientID)
-			return nil, fmt.Errorf("Client secret does not match")
+		log.Error().Err(err).Msgf("Failed authenticating client with client id %v", clientID)
+		oauthError(c, http.StatusInternalServerError, api.OAuthErrorServerError, "")
+		return nil, false
+	}
+
+	return client, true
+}
+
+// introspectToken reports a token as active if it's a valid jwt issued by this api, whose session hasn't been revoked
+func (h *Handler) introspectToken(ctx context.Context, token string) api.OAuthIntrospectionResponse {
+
+	claims, err := api.GetClaimsFromJWT(h.config, token)
+	if err != nil {
+		return api.OAuthIntrospectionResponse{Active: false}
+	}
+
+	response := api.NewOAuthIntrospectionResponse(claims)
+
+	if response.JWTID != "" {
+		revoked, err := h.service.IsSessionRevoked(ctx, response.JWTID)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed checking whether session %v is revoked", response.JWTID)
+			return api.OAuthIntrospectionResponse{Active: false}
 		}
+		if revoked {
+			return api.OAuthIntrospectionResponse{Active: false}
+		}
+	}
 
-		// check if client is active
-		if !clientFromDB.Active {
-			log.Error().Msgf("Client with id %v is not active", client.ClientID)
-			return nil, fmt.Errorf("Client is not active")
+	return response
+}
+
+// getOAuthClientCredentials returns the credentials of a client from the authorization header, or from the form body
+func getOAuthClientCredentials(c *gin.Context) (clientID, clientSecret string, usedBasicAuth bool) {
+
+	if basicClientID, basicClientSecret, ok := c.Request.BasicAuth(); ok {
+		// both are form-urlencoded before being encoded for basic authentication, see https://datatracker.ietf.org/doc/html/rfc6749#section-2.3.1
+		clientID, err := url.QueryUnescape(basicClientID)
+		if err != nil {
+			return "", "", true
+		}
+		clientSecret, err := url.QueryUnescape(basicClientSecret)
+		if err != nil {
+			return "", "", true
 		}
 
-		return clientFromDB, nil
+		return clientID, clientSecret, true
 	}
+
+	return c.PostForm("client_id"), c.PostForm("client_secret"), false
+}
+
+// oauthError responds to an oauth2 request with an error, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.2
+func oauthError(c *gin.Context, code int, oauthErrorCode, description string) {
+	c.JSON(code, api.OAuthErrorResponse{
+		Error:            oauthErrorCode,
+		ErrorDescription: description,
+	})
 }
 
 func (h *Handler) HandleImpersonateAuthenticator() func(c *gin.Context) (interface{}, error) {
@@ -375,7 +786,13 @@ func (h *Handler) HandleImpersonateAuthenticator() func(c *gin.Context) (interfa
 		}
 		user.Organizations = inheritedOrganizations
 
-		return user, nil
+		impersonator := api.GetEmailFromRequest(c)
+		api.AddAuditDetails(c, auditAction(AuditTargetTypeUser, auditActionImpersonated), AuditTargetTypeUser, user.ID, nil, gin.H{"email": user.Email, "impersonator": impersonator})
+
+		return &api.Impersonation{
+			User:         user,
+			Impersonator: impersonator,
+		}, nil
 	}
 }
 
@@ -384,11 +801,15 @@ func (h *Handler) GetUsers(c *gin.Context) {
 	pageNumber, pageSize, filters, sortings := api.GetQueryParameters(c)
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionUsersList) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionUsersList)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
+	// requests with an organization scoped role only list their own organizations
+	filters = scope.filters(filters)
+
 	ctx := c.Request.Context()
 
 	response, err := api.GetPagedListResponse(
@@ -424,7 +845,8 @@ func (h *Handler) GetUsers(c *gin.Context) {
 func (h *Handler) GetUser(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionUsersGet) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionUsersGet)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -433,7 +855,7 @@ func (h *Handler) GetUser(c *gin.Context) {
 	id := c.Param("id")
 
 	user, err := h.databaseClient.GetUserByID(ctx, id, map[api.FilterType][]string{})
-	if err != nil || user == nil {
+	if err != nil || user == nil || !scope.overlaps(user.Organizations) {
 		log.Error().Err(err).Msgf("Failed retrieving user with id %v from db", id)
 		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
 		return
@@ -445,7 +867,8 @@ func (h *Handler) GetUser(c *gin.Context) {
 func (h *Handler) CreateUser(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionUsersCreate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionUsersCreate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -461,6 +884,12 @@ func (h *Handler) CreateUser(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	err = h.checkUserChange(ctx, scope, nil, user)
+	if err != nil {
+		respondToScopeError(c, err)
+		return
+	}
+
 	insertedUser, err := h.service.CreateUser(ctx, user)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed inserting user")
@@ -468,13 +897,16 @@ func (h *Handler) CreateUser(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(AuditTargetTypeUser, auditActionCreated), AuditTargetTypeUser, insertedUser.ID, nil, insertedUser)
+
 	c.JSON(http.StatusCreated, insertedUser)
 }
 
 func (h *Handler) UpdateUser(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionUsersUpdate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionUsersUpdate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -497,26 +929,45 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	err = h.service.UpdateUser(ctx, user)
+	// the state before and after the update is recorded in the audit log, failing to retrieve it doesn't fail the update
+	before, _ := h.databaseClient.GetUserByID(ctx, id, map[api.FilterType][]string{})
+
+	err = h.checkUserChange(ctx, scope, before, user)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed updating user")
-		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		respondToScopeError(c, err)
 		return
 	}
 
+	err = h.service.UpdateUser(ctx, user)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed updating user")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	after, _ := h.databaseClient.GetUserByID(ctx, id, map[api.FilterType][]string{})
+	api.AddAuditDetails(c, auditAction(AuditTargetTypeUser, auditActionUpdated), AuditTargetTypeUser, id, before, after)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
 func (h *Handler) DeleteUser(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionUsersDelete) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionUsersDelete)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
 	ctx := c.Request.Context()
 	id := c.Param("id")
+	before, _ := h.databaseClient.GetUserByID(ctx, id, map[api.FilterType][]string{})
+	if scope != nil && (before == nil || !scope.manages(before.Organizations, before.Roles)) {
+		respondToScopeError(c, ErrOutsideOrganizationScope)
+		return
+	}
+
 	err := h.service.DeleteUser(ctx, id)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed deleting user")
@@ -524,6 +975,8 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(AuditTargetTypeUser, auditActionDeleted), AuditTargetTypeUser, id, before, nil)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
@@ -532,11 +985,15 @@ func (h *Handler) GetGroups(c *gin.Context) {
 	pageNumber, pageSize, filters, sortings := api.GetQueryParameters(c)
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionGroupsList) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionGroupsList)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
+	// requests with an organization scoped role only list their own organizations
+	filters = scope.filters(filters)
+
 	ctx := c.Request.Context()
 
 	response, err := api.GetPagedListResponse(
@@ -578,7 +1035,8 @@ func (h *Handler) GetGroups(c *gin.Context) {
 func (h *Handler) GetGroup(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionGroupsGet) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionGroupsGet)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -587,7 +1045,7 @@ func (h *Handler) GetGroup(c *gin.Context) {
 	id := c.Param("id")
 
 	group, err := h.databaseClient.GetGroupByID(ctx, id, map[api.FilterType][]string{})
-	if err != nil || group == nil {
+	if err != nil || group == nil || !scope.overlaps(group.Organizations) {
 		log.Error().Err(err).Msgf("Failed retrieving group with id %v from db", id)
 		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
 		return
@@ -599,7 +1057,8 @@ func (h *Handler) GetGroup(c *gin.Context) {
 func (h *Handler) CreateGroup(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionGroupsCreate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionGroupsCreate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -615,6 +1074,12 @@ func (h *Handler) CreateGroup(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	err = h.checkGroupChange(scope, nil, group)
+	if err != nil {
+		respondToScopeError(c, err)
+		return
+	}
+
 	insertedGroup, err := h.service.CreateGroup(ctx, group)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed inserting group")
@@ -622,13 +1087,16 @@ func (h *Handler) CreateGroup(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(auditTargetTypeGroup, auditActionCreated), auditTargetTypeGroup, insertedGroup.ID, nil, insertedGroup)
+
 	c.JSON(http.StatusCreated, insertedGroup)
 }
 
 func (h *Handler) UpdateGroup(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionGroupsUpdate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionGroupsUpdate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -651,6 +1119,15 @@ func (h *Handler) UpdateGroup(c *gin.Context) {
 		return
 	}
 
+	// the state before and after the update is recorded in the audit log, failing to retrieve it doesn't fail the update
+	before, _ := h.databaseClient.GetGroupByID(ctx, id, map[api.FilterType][]string{})
+
+	err = h.checkGroupChange(scope, before, group)
+	if err != nil {
+		respondToScopeError(c, err)
+		return
+	}
+
 	err = h.service.UpdateGroup(ctx, group)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating group")
@@ -658,19 +1135,29 @@ func (h *Handler) UpdateGroup(c *gin.Context) {
 		return
 	}
 
+	after, _ := h.databaseClient.GetGroupByID(ctx, id, map[api.FilterType][]string{})
+	api.AddAuditDetails(c, auditAction(auditTargetTypeGroup, auditActionUpdated), auditTargetTypeGroup, id, before, after)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
 func (h *Handler) DeleteGroup(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionGroupsDelete) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionGroupsDelete)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
 	ctx := c.Request.Context()
 	id := c.Param("id")
+	before, _ := h.databaseClient.GetGroupByID(ctx, id, map[api.FilterType][]string{})
+	if scope != nil && (before == nil || !scope.manages(before.Organizations, before.Roles)) {
+		respondToScopeError(c, ErrOutsideOrganizationScope)
+		return
+	}
+
 	err := h.service.DeleteGroup(ctx, id)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed deleting group")
@@ -678,6 +1165,8 @@ func (h *Handler) DeleteGroup(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(auditTargetTypeGroup, auditActionDeleted), auditTargetTypeGroup, id, before, nil)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
@@ -740,107 +1229,435 @@ func (h *Handler) GetOrganization(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
-	organization, err := h.databaseClient.GetOrganizationByID(ctx, id)
-	if err != nil || organization == nil {
-		log.Error().Err(err).Msgf("Failed retrieving organization with id %v from db", id)
-		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+	organization, err := h.databaseClient.GetOrganizationByID(ctx, id)
+	if err != nil || organization == nil {
+		log.Error().Err(err).Msgf("Failed retrieving organization with id %v from db", id)
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+func (h *Handler) CreateOrganization(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionOrganizationsCreate) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	var organization contracts.Organization
+	err := c.BindJSON(&organization)
+	if err != nil {
+		errorMessage := "Binding CreateOrganization body failed"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	insertedOrganization, err := h.service.CreateOrganization(ctx, organization)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed inserting organization")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	api.AddAuditDetails(c, auditAction(auditTargetTypeOrganization, auditActionCreated), auditTargetTypeOrganization, insertedOrganization.ID, nil, insertedOrganization)
+
+	c.JSON(http.StatusCreated, insertedOrganization)
+}
+
+func (h *Handler) UpdateOrganization(c *gin.Context) {
+
+	// ensure the user has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionOrganizationsUpdate) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	var organization contracts.Organization
+	err := c.BindJSON(&organization)
+	if err != nil {
+		errorMessage := "Binding UpdateOrganization body failed"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	id := c.Param("id")
+	if organization.ID != id {
+		log.Error().Err(err).Msg("Organization id is incorrect")
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// the state before and after the update is recorded in the audit log, failing to retrieve it doesn't fail the update
+	before, _ := h.databaseClient.GetOrganizationByID(ctx, id)
+
+	err = h.service.UpdateOrganization(ctx, organization)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed updating organization")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	after, _ := h.databaseClient.GetOrganizationByID(ctx, id)
+	api.AddAuditDetails(c, auditAction(auditTargetTypeOrganization, auditActionUpdated), auditTargetTypeOrganization, id, before, after)
+
+	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
+}
+
+func (h *Handler) DeleteOrganization(c *gin.Context) {
+
+	// ensure the user has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionOrganizationsDelete) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	before, _ := h.databaseClient.GetOrganizationByID(ctx, id)
+
+	err := h.service.DeleteOrganization(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed deleting organization")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	api.AddAuditDetails(c, auditAction(auditTargetTypeOrganization, auditActionDeleted), auditTargetTypeOrganization, id, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
+}
+
+func (h *Handler) GetClients(c *gin.Context) {
+
+	pageNumber, pageSize, filters, sortings := api.GetQueryParameters(c)
+
+	// ensure the request has the correct permission
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsList)
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	// requests with an organization scoped role only list their own organizations
+	filters = scope.filters(filters)
+
+	ctx := c.Request.Context()
+
+	response, err := api.GetPagedListResponse(
+		func() ([]interface{}, error) {
+			clients, err := h.databaseClient.GetClients(ctx, pageNumber, pageSize, filters, sortings)
+			if err != nil {
+				return nil, err
+			}
+
+			// convert typed array to interface array O(n)
+			items := make([]interface{}, len(clients))
+			for i := range clients {
+				if !api.RequestTokenHasPermission(c, api.PermissionClientsViewSecret) {
+					// obfuscate client secret
+					clients[i].ClientSecret = "***"
+				}
+				items[i] = clients[i]
+			}
+			return items, nil
+		},
+		func() (int, error) {
+			return h.databaseClient.GetClientsCount(ctx, filters)
+		},
+		pageNumber,
+		pageSize)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed retrieving clients from db")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetClient(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsGet)
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	client, err := h.databaseClient.GetClientByID(ctx, id)
+	if err != nil || client == nil || !scope.overlaps(client.Organizations) {
+		log.Error().Err(err).Msgf("Failed retrieving client with id %v from db", id)
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+		return
+	}
+
+	if !api.RequestTokenHasPermission(c, api.PermissionClientsViewSecret) {
+		// obfuscate client secret
+		client.ClientSecret = "***"
+	}
+
+	c.JSON(http.StatusOK, client)
+}
+
+func (h *Handler) CreateClient(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsCreate)
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	var client contracts.Client
+	err := c.BindJSON(&client)
+	if err != nil {
+		errorMessage := "Binding CreateClient body failed"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	err = h.checkClientChange(scope, nil, client)
+	if err != nil {
+		respondToScopeError(c, err)
+		return
+	}
+
+	insertedClient, err := h.service.CreateClient(ctx, client)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed inserting client")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	api.AddAuditDetails(c, auditAction(auditTargetTypeClient, auditActionCreated), auditTargetTypeClient, insertedClient.ID, nil, obfuscateClientSecret(insertedClient))
+
+	c.JSON(http.StatusCreated, insertedClient)
+}
+
+func (h *Handler) UpdateClient(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsUpdate)
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	var client contracts.Client
+	err := c.BindJSON(&client)
+	if err != nil {
+		errorMessage := "Binding UpdateClient body failed"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	id := c.Param("id")
+	if client.ID != id {
+		log.Error().Err(err).Msg("Client id is incorrect")
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// the state before and after the update is recorded in the audit log, failing to retrieve it doesn't fail the update
+	before, _ := h.databaseClient.GetClientByID(ctx, id)
+
+	err = h.checkClientChange(scope, before, client)
+	if err != nil {
+		respondToScopeError(c, err)
+		return
+	}
+
+	err = h.service.UpdateClient(ctx, client)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed updating client")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	after, _ := h.databaseClient.GetClientByID(ctx, id)
+	api.AddAuditDetails(c, auditAction(auditTargetTypeClient, auditActionUpdated), auditTargetTypeClient, id, obfuscateClientSecret(before), obfuscateClientSecret(after))
+
+	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
+}
+
+func (h *Handler) DeleteClient(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsDelete)
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	before, _ := h.databaseClient.GetClientByID(ctx, id)
+	if scope != nil && (before == nil || !scope.manages(before.Organizations, before.Roles)) {
+		respondToScopeError(c, ErrOutsideOrganizationScope)
+		return
+	}
+
+	err := h.service.DeleteClient(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed deleting client")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
-	c.JSON(http.StatusOK, organization)
+	api.AddAuditDetails(c, auditAction(auditTargetTypeClient, auditActionDeleted), auditTargetTypeClient, id, obfuscateClientSecret(before), nil)
+
+	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
-func (h *Handler) CreateOrganization(c *gin.Context) {
+func (h *Handler) GetClientSecrets(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionOrganizationsCreate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsGet)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
-	var organization contracts.Organization
-	err := c.BindJSON(&organization)
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	err := h.checkClientWithinScope(ctx, scope, id)
 	if err != nil {
-		errorMessage := "Binding CreateOrganization body failed"
-		log.Error().Err(err).Msg(errorMessage)
-		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		if errors.Is(err, database.ErrClientNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		respondToScopeError(c, err)
 		return
 	}
 
-	ctx := c.Request.Context()
-
-	insertedOrganization, err := h.service.CreateOrganization(ctx, organization)
+	secrets, err := h.service.GetClientSecrets(ctx, id)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed inserting organization")
+		if errors.Is(err, database.ErrClientNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		log.Error().Err(err).Msgf("Failed retrieving secrets for client %v", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
-	c.JSON(http.StatusCreated, insertedOrganization)
+	c.JSON(http.StatusOK, secrets)
 }
 
-func (h *Handler) UpdateOrganization(c *gin.Context) {
+func (h *Handler) CreateClientSecret(c *gin.Context) {
 
-	// ensure the user has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionOrganizationsUpdate) {
+	// ensure the request has the correct permission
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsUpdate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
-	var organization contracts.Organization
-	err := c.BindJSON(&organization)
-	if err != nil {
-		errorMessage := "Binding UpdateOrganization body failed"
-		log.Error().Err(err).Msg(errorMessage)
-		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
-		return
-	}
-
+	ctx := c.Request.Context()
 	id := c.Param("id")
-	if organization.ID != id {
-		log.Error().Err(err).Msg("Organization id is incorrect")
-		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest)})
+
+	err := h.checkClientWithinScope(ctx, scope, id)
+	if err != nil {
+		if errors.Is(err, database.ErrClientNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		respondToScopeError(c, err)
 		return
 	}
 
-	ctx := c.Request.Context()
-
-	err = h.service.UpdateOrganization(ctx, organization)
+	insertedSecret, err := h.service.CreateClientSecret(ctx, id)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed updating organization")
+		if errors.Is(err, database.ErrClientNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		if errors.Is(err, ErrTooManyClientSecrets) {
+			c.JSON(http.StatusConflict, gin.H{"code": http.StatusText(http.StatusConflict), "message": fmt.Sprintf("A client can have at most %v active secrets, delete one first", api.MaxActiveClientSecrets)})
+			return
+		}
+		log.Error().Err(err).Msgf("Failed creating secret for client %v", id)
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
+	auditedSecret := *insertedSecret
+	auditedSecret.Secret = ""
+	api.AddAuditDetails(c, auditAction(auditTargetTypeClientSecret, auditActionCreated), auditTargetTypeClientSecret, insertedSecret.ID, nil, auditedSecret)
+
+	// the secret is only returned this once
+	c.JSON(http.StatusCreated, insertedSecret)
 }
 
-func (h *Handler) DeleteOrganization(c *gin.Context) {
+func (h *Handler) DeleteClientSecret(c *gin.Context) {
 
-	// ensure the user has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionOrganizationsDelete) {
+	// ensure the request has the correct permission
+	scope, hasPermission := getOrganizationScope(c, api.PermissionClientsUpdate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
 	ctx := c.Request.Context()
 	id := c.Param("id")
-	err := h.service.DeleteOrganization(ctx, id)
+	secretID := c.Param("secretID")
+
+	err := h.checkClientWithinScope(ctx, scope, id)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed deleting organization")
+		if errors.Is(err, database.ErrClientNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		respondToScopeError(c, err)
+		return
+	}
+
+	err = h.service.DeleteClientSecret(ctx, id, secretID)
+	if err != nil {
+		if errors.Is(err, database.ErrClientNotFound) || errors.Is(err, database.ErrClientSecretNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+			return
+		}
+		if errors.Is(err, ErrLastClientSecret) {
+			c.JSON(http.StatusConflict, gin.H{"code": http.StatusText(http.StatusConflict), "message": err.Error()})
+			return
+		}
+		log.Error().Err(err).Msgf("Failed deleting secret %v of client %v", secretID, id)
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(auditTargetTypeClientSecret, auditActionDeleted), auditTargetTypeClientSecret, secretID, nil, nil)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
-func (h *Handler) GetClients(c *gin.Context) {
+func (h *Handler) GetCustomRoles(c *gin.Context) {
 
 	pageNumber, pageSize, filters, sortings := api.GetQueryParameters(c)
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionClientsList) {
+	if !api.RequestTokenHasPermission(c, api.PermissionCustomRolesList) {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -849,30 +1666,27 @@ func (h *Handler) GetClients(c *gin.Context) {
 
 	response, err := api.GetPagedListResponse(
 		func() ([]interface{}, error) {
-			clients, err := h.databaseClient.GetClients(ctx, pageNumber, pageSize, filters, sortings)
+			customRoles, err := h.databaseClient.GetCustomRoles(ctx, pageNumber, pageSize, filters, sortings)
 			if err != nil {
 				return nil, err
 			}
 
 			// convert typed array to interface array O(n)
-			items := make([]interface{}, len(clients))
-			for i := range clients {
-				if !api.RequestTokenHasPermission(c, api.PermissionClientsViewSecret) {
-					// obfuscate client secret
-					clients[i].ClientSecret = "***"
-				}
-				items[i] = clients[i]
+			items := make([]interface{}, len(customRoles))
+			for i := range customRoles {
+				items[i] = customRoles[i]
 			}
+
 			return items, nil
 		},
 		func() (int, error) {
-			return h.databaseClient.GetClientsCount(ctx, filters)
+			return h.databaseClient.GetCustomRolesCount(ctx, filters)
 		},
 		pageNumber,
 		pageSize)
 
 	if err != nil {
-		log.Error().Err(err).Msg("Failed retrieving clients from db")
+		log.Error().Err(err).Msg("Failed retrieving custom roles from db")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
@@ -880,10 +1694,10 @@ func (h *Handler) GetClients(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func (h *Handler) GetClient(c *gin.Context) {
+func (h *Handler) GetCustomRole(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionClientsGet) {
+	if !api.RequestTokenHasPermission(c, api.PermissionCustomRolesGet) {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -891,33 +1705,28 @@ func (h *Handler) GetClient(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
-	client, err := h.databaseClient.GetClientByID(ctx, id)
-	if err != nil || client == nil {
-		log.Error().Err(err).Msgf("Failed retrieving client with id %v from db", id)
+	customRole, err := h.databaseClient.GetCustomRoleByID(ctx, id)
+	if err != nil || customRole == nil {
+		log.Error().Err(err).Msgf("Failed retrieving custom role with id %v from db", id)
 		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
 		return
 	}
 
-	if !api.RequestTokenHasPermission(c, api.PermissionClientsViewSecret) {
-		// obfuscate client secret
-		client.ClientSecret = "***"
-	}
-
-	c.JSON(http.StatusOK, client)
+	c.JSON(http.StatusOK, customRole)
 }
 
-func (h *Handler) CreateClient(c *gin.Context) {
+func (h *Handler) CreateCustomRole(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionClientsCreate) {
+	if !api.RequestTokenHasPermission(c, api.PermissionCustomRolesCreate) {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
-	var client contracts.Client
-	err := c.BindJSON(&client)
+	var customRole api.CustomRole
+	err := c.BindJSON(&customRole)
 	if err != nil {
-		errorMessage := "Binding CreateClient body failed"
+		errorMessage := "Binding CreateCustomRole body failed"
 		log.Error().Err(err).Msg(errorMessage)
 		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
 		return
@@ -925,69 +1734,89 @@ func (h *Handler) CreateClient(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
-	insertedClient, err := h.service.CreateClient(ctx, client)
+	insertedCustomRole, err := h.service.CreateCustomRole(ctx, customRole)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed inserting client")
+		if errors.Is(err, ErrInvalidCustomRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Failed inserting custom role")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
-	c.JSON(http.StatusCreated, insertedClient)
+	api.AddAuditDetails(c, auditAction(auditTargetTypeCustomRole, auditActionCreated), auditTargetTypeCustomRole, insertedCustomRole.ID, nil, insertedCustomRole)
+
+	c.JSON(http.StatusCreated, insertedCustomRole)
 }
 
-func (h *Handler) UpdateClient(c *gin.Context) {
+func (h *Handler) UpdateCustomRole(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionClientsUpdate) {
+	if !api.RequestTokenHasPermission(c, api.PermissionCustomRolesUpdate) {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
-	var client contracts.Client
-	err := c.BindJSON(&client)
+	var customRole api.CustomRole
+	err := c.BindJSON(&customRole)
 	if err != nil {
-		errorMessage := "Binding UpdateClient body failed"
+		errorMessage := "Binding UpdateCustomRole body failed"
 		log.Error().Err(err).Msg(errorMessage)
 		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
 		return
 	}
 
 	id := c.Param("id")
-	if client.ID != id {
-		log.Error().Err(err).Msg("Client id is incorrect")
+	if customRole.ID != id {
+		log.Error().Err(err).Msg("Custom role id is incorrect")
 		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest)})
 		return
 	}
 
 	ctx := c.Request.Context()
 
-	err = h.service.UpdateClient(ctx, client)
+	// the state before and after the update is recorded in the audit log, failing to retrieve it doesn't fail the update
+	before, _ := h.databaseClient.GetCustomRoleByID(ctx, id)
+
+	err = h.service.UpdateCustomRole(ctx, customRole)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed updating client")
+		if errors.Is(err, ErrInvalidCustomRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Failed updating custom role")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
+	after, _ := h.databaseClient.GetCustomRoleByID(ctx, id)
+	api.AddAuditDetails(c, auditAction(auditTargetTypeCustomRole, auditActionUpdated), auditTargetTypeCustomRole, id, before, after)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
-func (h *Handler) DeleteClient(c *gin.Context) {
+func (h *Handler) DeleteCustomRole(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionClientsDelete) {
+	if !api.RequestTokenHasPermission(c, api.PermissionCustomRolesDelete) {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
 	ctx := c.Request.Context()
 	id := c.Param("id")
-	err := h.service.DeleteClient(ctx, id)
+	before, _ := h.databaseClient.GetCustomRoleByID(ctx, id)
+
+	err := h.service.DeleteCustomRole(ctx, id)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed deleting client")
+		log.Error().Err(err).Msg("Failed deleting custom role")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(auditTargetTypeCustomRole, auditActionDeleted), auditTargetTypeCustomRole, id, before, nil)
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
@@ -1121,6 +1950,8 @@ func (h *Handler) UpdateGithubInstallation(c *gin.Context) {
 		return
 	}
 
+	before := installation.Organizations
+
 	// update organizations
 	installation.Organizations = installationFromForm.Organizations
 
@@ -1131,6 +1962,8 @@ func (h *Handler) UpdateGithubInstallation(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(auditTargetTypeIntegration, auditActionUpdated), auditTargetTypeIntegration, fmt.Sprintf("github/%v", installationFromForm.ID), gin.H{"organizations": before}, gin.H{"organizations": installation.Organizations})
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
@@ -1161,6 +1994,8 @@ func (h *Handler) UpdateBitbucketInstallation(c *gin.Context) {
 		return
 	}
 
+	before := installation.Organizations
+
 	// update organizations
 	installation.Organizations = installationFromForm.Organizations
 
@@ -1171,19 +2006,25 @@ func (h *Handler) UpdateBitbucketInstallation(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditAction(auditTargetTypeIntegration, auditActionUpdated), auditTargetTypeIntegration, fmt.Sprintf("bitbucket/%v", installationFromForm.ClientKey), gin.H{"organizations": before}, gin.H{"organizations": installation.Organizations})
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
 func (h *Handler) GetPipelines(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionPipelinesList) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionPipelinesList)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
 	pageNumber, pageSize, filters, sortings := api.GetQueryParameters(c)
 
+	// requests with an organization scoped role only list their own organizations
+	filters = scope.filters(filters)
+
 	ctx := c.Request.Context()
 
 	response, err := api.GetPagedListResponse(
@@ -1219,7 +2060,8 @@ func (h *Handler) GetPipelines(c *gin.Context) {
 func (h *Handler) GetPipeline(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionPipelinesGet) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionPipelinesGet)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -1230,7 +2072,7 @@ func (h *Handler) GetPipeline(c *gin.Context) {
 	repo := c.Param("repo")
 
 	pipeline, err := h.databaseClient.GetPipeline(ctx, source, owner, repo, map[api.FilterType][]string{}, false)
-	if err != nil || pipeline == nil {
+	if err != nil || pipeline == nil || !scope.overlaps(pipeline.Organizations) {
 		log.Error().Err(err).Msgf("Failed retrieving pipeline for %v/%v/%v from db", source, owner, repo)
 		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
 		return
@@ -1242,7 +2084,8 @@ func (h *Handler) GetPipeline(c *gin.Context) {
 func (h *Handler) UpdatePipeline(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionPipelinesUpdate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionPipelinesUpdate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -1267,6 +2110,15 @@ func (h *Handler) UpdatePipeline(c *gin.Context) {
 		return
 	}
 
+	// the state before and after the update is recorded in the audit log, failing to retrieve it doesn't fail the update
+	before, _ := h.databaseClient.GetPipeline(ctx, source, owner, repo, map[api.FilterType][]string{}, true)
+
+	err = h.checkPipelineChange(ctx, scope, before, pipeline)
+	if err != nil {
+		respondToScopeError(c, err)
+		return
+	}
+
 	err = h.service.UpdatePipeline(ctx, pipeline)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating pipeline")
@@ -1274,13 +2126,17 @@ func (h *Handler) UpdatePipeline(c *gin.Context) {
 		return
 	}
 
+	after, _ := h.databaseClient.GetPipeline(ctx, source, owner, repo, map[api.FilterType][]string{}, true)
+	api.AddAuditDetails(c, auditAction(auditTargetTypePipeline, auditActionUpdated), auditTargetTypePipeline, fmt.Sprintf("%v/%v/%v", source, owner, repo), getPipelinePermissions(before), getPipelinePermissions(after))
+
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
 
 func (h *Handler) BatchUpdateUsers(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionUsersUpdate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionUsersUpdate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -1314,6 +2170,33 @@ func (h *Handler) BatchUpdateUsers(c *gin.Context) {
 		return
 	}
 
+	// a request with an organization scoped role can't make any of the changes if one of them is outside its organizations
+	if scope != nil {
+		roles := append([]*string{body.Role}, body.RolesToAdd...)
+		groups := append([]*contracts.Group{body.Group}, body.GroupsToAdd...)
+		organizations := append([]*contracts.Organization{body.Organization}, body.OrganizationsToAdd...)
+		err = h.checkBatchChange(ctx, scope, append(roles, body.RolesToRemove...), append(groups, body.GroupsToRemove...), append(organizations, body.OrganizationsToRemove...))
+		if err != nil {
+			respondToScopeError(c, err)
+			return
+		}
+
+		for _, u := range body.Users {
+			user, err := h.databaseClient.GetUserByID(ctx, u, map[api.FilterType][]string{})
+			if err != nil {
+				respondToScopeError(c, err)
+				return
+			}
+			if user == nil || !scope.overlaps(user.Organizations) {
+				respondToScopeError(c, ErrOutsideOrganizationScope)
+				return
+			}
+		}
+	}
+
+	// changes are recorded in the audit log, including those made before any of the updates failed
+	changes := &auditChanges{}
+
 	// limit concurrency using a semaphore
 	semaphore := semaphore.NewWeighted(10)
 	g, ctx := errgroup.WithContext(ctx)
@@ -1333,6 +2216,8 @@ func (h *Handler) BatchUpdateUsers(c *gin.Context) {
 				return err
 			}
 
+			before := *user
+
 			// add role if not present
 			if body.Role != nil {
 				hasRole := false
@@ -1444,10 +2329,16 @@ func (h *Handler) BatchUpdateUsers(c *gin.Context) {
 				user.Organizations = newOrganizations
 			}
 
+			err = h.checkUserChange(ctx, scope, &before, *user)
+			if err != nil {
+				return err
+			}
+
 			err = h.service.UpdateUser(ctx, *user)
 			if err != nil {
 				return err
 			}
+			changes.add(AuditTargetTypeUser, u, getUserMemberships(before), getUserMemberships(*user))
 
 			return nil
 		})
@@ -1455,6 +2346,11 @@ func (h *Handler) BatchUpdateUsers(c *gin.Context) {
 
 	// wait until all concurrent goroutines are done
 	err = g.Wait()
+	changes.addTo(c)
+	if errors.Is(err, ErrOutsideOrganizationScope) {
+		respondToScopeError(c, err)
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating user")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
@@ -1498,6 +2394,9 @@ func (h *Handler) BatchUpdateGroups(c *gin.Context) {
 		return
 	}
 
+	// changes are recorded in the audit log, including those made before any of the updates failed
+	changes := &auditChanges{}
+
 	// limit concurrency using a semaphore
 	semaphore := semaphore.NewWeighted(10)
 	errgrp, ctx := errgroup.WithContext(ctx)
@@ -1517,6 +2416,8 @@ func (h *Handler) BatchUpdateGroups(c *gin.Context) {
 				return err
 			}
 
+			before := *group
+
 			// add role if not present
 			if body.Role != nil {
 				hasRole := false
@@ -1595,6 +2496,7 @@ func (h *Handler) BatchUpdateGroups(c *gin.Context) {
 			if err != nil {
 				return err
 			}
+			changes.add(auditTargetTypeGroup, g, getGroupMemberships(before), getGroupMemberships(*group))
 
 			return nil
 		})
@@ -1602,6 +2504,7 @@ func (h *Handler) BatchUpdateGroups(c *gin.Context) {
 
 	// wait until all concurrent goroutines are done
 	err = errgrp.Wait()
+	changes.addTo(c)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating group")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
@@ -1642,6 +2545,9 @@ func (h *Handler) BatchUpdateOrganizations(c *gin.Context) {
 		return
 	}
 
+	// changes are recorded in the audit log, including those made before any of the updates failed
+	changes := &auditChanges{}
+
 	// limit concurrency using a semaphore
 	semaphore := semaphore.NewWeighted(10)
 	g, ctx := errgroup.WithContext(ctx)
@@ -1661,6 +2567,8 @@ func (h *Handler) BatchUpdateOrganizations(c *gin.Context) {
 				return err
 			}
 
+			before := *organization
+
 			// add role if not present
 			if body.Role != nil {
 				hasRole := false
@@ -1702,6 +2610,7 @@ func (h *Handler) BatchUpdateOrganizations(c *gin.Context) {
 			if err != nil {
 				return err
 			}
+			changes.add(auditTargetTypeOrganization, o, before, organization)
 
 			return nil
 		})
@@ -1709,6 +2618,7 @@ func (h *Handler) BatchUpdateOrganizations(c *gin.Context) {
 
 	// wait until all concurrent goroutines are done
 	err = g.Wait()
+	changes.addTo(c)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating organization")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
@@ -1752,6 +2662,9 @@ func (h *Handler) BatchUpdateClients(c *gin.Context) {
 		return
 	}
 
+	// changes are recorded in the audit log, including those made before any of the updates failed
+	changes := &auditChanges{}
+
 	// limit concurrency using a semaphore
 	semaphore := semaphore.NewWeighted(10)
 	g, ctx := errgroup.WithContext(ctx)
@@ -1771,6 +2684,8 @@ func (h *Handler) BatchUpdateClients(c *gin.Context) {
 				return err
 			}
 
+			before := *client
+
 			// add role if not present
 			if body.Role != nil {
 				hasRole := false
@@ -1849,6 +2764,7 @@ func (h *Handler) BatchUpdateClients(c *gin.Context) {
 			if err != nil {
 				return err
 			}
+			changes.add(auditTargetTypeClient, c, obfuscateClientSecret(&before), obfuscateClientSecret(client))
 
 			return nil
 		})
@@ -1856,6 +2772,7 @@ func (h *Handler) BatchUpdateClients(c *gin.Context) {
 
 	// wait until all concurrent goroutines are done
 	err = g.Wait()
+	changes.addTo(c)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating client")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
@@ -1868,7 +2785,8 @@ func (h *Handler) BatchUpdateClients(c *gin.Context) {
 func (h *Handler) BatchUpdatePipelines(c *gin.Context) {
 
 	// ensure the request has the correct permission
-	if !api.RequestTokenHasPermission(c, api.PermissionPipelinesUpdate) {
+	scope, hasPermission := getOrganizationScope(c, api.PermissionPipelinesUpdate)
+	if !hasPermission {
 		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
@@ -1899,6 +2817,37 @@ func (h *Handler) BatchUpdatePipelines(c *gin.Context) {
 		return
 	}
 
+	// a request with an organization scoped role can't make any of the changes if one of them is outside its organizations
+	if scope != nil {
+		groups := append([]*contracts.Group{body.Group}, body.GroupsToAdd...)
+		organizations := append([]*contracts.Organization{body.Organization}, body.OrganizationsToAdd...)
+		err = h.checkBatchChange(ctx, scope, nil, append(groups, body.GroupsToRemove...), append(organizations, body.OrganizationsToRemove...))
+		if err != nil {
+			respondToScopeError(c, err)
+			return
+		}
+
+		for _, p := range body.Pipelines {
+			pipelineParts := strings.Split(p, "/")
+			if len(pipelineParts) != 3 {
+				c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("Pipeline '%v' has invalid name", p)})
+				return
+			}
+			pipeline, err := h.databaseClient.GetPipeline(ctx, pipelineParts[0], pipelineParts[1], pipelineParts[2], map[api.FilterType][]string{}, true)
+			if err != nil {
+				respondToScopeError(c, err)
+				return
+			}
+			if pipeline == nil || !scope.overlaps(pipeline.Organizations) {
+				respondToScopeError(c, ErrOutsideOrganizationScope)
+				return
+			}
+		}
+	}
+
+	// changes are recorded in the audit log, including those made before any of the updates failed
+	changes := &auditChanges{}
+
 	// limit concurrency using a semaphore
 	semaphore := semaphore.NewWeighted(10)
 	g, ctx := errgroup.WithContext(ctx)
@@ -1924,6 +2873,8 @@ func (h *Handler) BatchUpdatePipelines(c *gin.Context) {
 				return err
 			}
 
+			before := *pipeline
+
 			// add group if not present
 			if body.Group != nil {
 				hasGroup := false
@@ -1998,10 +2949,16 @@ func (h *Handler) BatchUpdatePipelines(c *gin.Context) {
 				pipeline.Organizations = newOrganizations
 			}
 
+			err = h.checkPipelineChange(ctx, scope, &before, *pipeline)
+			if err != nil {
+				return err
+			}
+
 			err = h.service.UpdatePipeline(ctx, *pipeline)
 			if err != nil {
 				return err
 			}
+			changes.add(auditTargetTypePipeline, p, getPipelinePermissions(&before), getPipelinePermissions(pipeline))
 
 			return nil
 		})
@@ -2009,6 +2966,11 @@ func (h *Handler) BatchUpdatePipelines(c *gin.Context) {
 
 	// wait until all concurrent goroutines are done
 	err = g.Wait()
+	changes.addTo(c)
+	if errors.Is(err, ErrOutsideOrganizationScope) {
+		respondToScopeError(c, err)
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("Failed updating pipeline")
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
@@ -2017,3 +2979,152 @@ func (h *Handler) BatchUpdatePipelines(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"code": http.StatusText(http.StatusOK)})
 }
+
+// respondToScopeError responds to a request that failed checking whether its changes are within its organization scope
+func respondToScopeError(c *gin.Context, err error) {
+	if errors.Is(err, ErrOutsideOrganizationScope) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": err.Error()})
+		return
+	}
+
+	log.Error().Err(err).Msg("Failed checking organization scope of request")
+	c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+}
+
+// checkUserChange returns ErrOutsideOrganizationScope if the request can't create (without before) or update a user
+func (h *Handler) checkUserChange(ctx context.Context, scope *organizationScope, before *contracts.User, after contracts.User) (err error) {
+	if scope == nil {
+		return nil
+	}
+
+	if before == nil {
+		if !scope.manages(after.Organizations, after.Roles) {
+			return ErrOutsideOrganizationScope
+		}
+		return h.checkGroupsWithinScope(ctx, scope, after.Groups)
+	}
+
+	if !scope.overlaps(before.Organizations) {
+		return ErrOutsideOrganizationScope
+	}
+
+	// changes to the user itself affect it in all of its organizations
+	if before.Active != after.Active || before.Name != after.Name || before.Email != after.Email || !reflect.DeepEqual(before.Identities, after.Identities) {
+		if !scope.manages(before.Organizations, before.Roles) {
+			return ErrOutsideOrganizationScope
+		}
+	}
+
+	if !scope.allowsOrganizationChanges(before.Organizations, after.Organizations) || !scope.allowsRoleChanges(before.Roles, after.Roles) {
+		return ErrOutsideOrganizationScope
+	}
+
+	return h.checkGroupsWithinScope(ctx, scope, getChangedGroups(before.Groups, after.Groups))
+}
+
+// checkGroupChange returns ErrOutsideOrganizationScope if the request can't create (without before) or update a group
+func (h *Handler) checkGroupChange(scope *organizationScope, before *contracts.Group, after contracts.Group) (err error) {
+	if scope == nil {
+		return nil
+	}
+
+	if before == nil {
+		if !scope.manages(after.Organizations, after.Roles) {
+			return ErrOutsideOrganizationScope
+		}
+		return nil
+	}
+
+	if !scope.contains(before.Organizations) || !scope.contains(after.Organizations) || !scope.allowsRoleChanges(before.Roles, after.Roles) {
+		return ErrOutsideOrganizationScope
+	}
+
+	return nil
+}
+
+// checkClientChange returns ErrOutsideOrganizationScope if the request can't create (without before) or update a client
+func (h *Handler) checkClientChange(scope *organizationScope, before *contracts.Client, after contracts.Client) (err error) {
+	if scope == nil {
+		return nil
+	}
+
+	if before == nil {
+		if !scope.manages(after.Organizations, after.Roles) {
+			return ErrOutsideOrganizationScope
+		}
+		return nil
+	}
+
+	if !scope.contains(before.Organizations) || !scope.contains(after.Organizations) || !scope.allowsRoleChanges(before.Roles, after.Roles) {
+		return ErrOutsideOrganizationScope
+	}
+
+	return nil
+}
+
+// checkClientWithinScope returns ErrOutsideOrganizationScope if the request can't manage the secrets of a client
+func (h *Handler) checkClientWithinScope(ctx context.Context, scope *organizationScope, id string) (err error) {
+	if scope == nil {
+		return nil
+	}
+
+	client, err := h.databaseClient.GetClientByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if client == nil || !scope.manages(client.Organizations, client.Roles) {
+		return ErrOutsideOrganizationScope
+	}
+
+	return nil
+}
+
+// checkPipelineChange returns ErrOutsideOrganizationScope if the request can't update the groups and organizations of a pipeline
+func (h *Handler) checkPipelineChange(ctx context.Context, scope *organizationScope, before *contracts.Pipeline, after contracts.Pipeline) (err error) {
+	if scope == nil {
+		return nil
+	}
+
+	if before == nil || !scope.overlaps(before.Organizations) || before.Archived != after.Archived || !scope.allowsOrganizationChanges(before.Organizations, after.Organizations) {
+		return ErrOutsideOrganizationScope
+	}
+
+	return h.checkGroupsWithinScope(ctx, scope, getChangedGroups(before.Groups, after.Groups))
+}
+
+// checkBatchChange returns ErrOutsideOrganizationScope if the request can't hand out or take away the roles, groups or
+// organizations of a batch update
+func (h *Handler) checkBatchChange(ctx context.Context, scope *organizationScope, roles []*string, groups []*contracts.Group, organizations []*contracts.Organization) (err error) {
+	if scope == nil {
+		return nil
+	}
+
+	if !scope.holdsRoles(roles) || !scope.allowsOrganizationChanges(nil, organizations) {
+		return ErrOutsideOrganizationScope
+	}
+
+	return h.checkGroupsWithinScope(ctx, scope, groups)
+}
+
+// checkGroupsWithinScope returns ErrOutsideOrganizationScope if any of the groups isn't managed by the request; as
+// members inherit the roles of a group, the request needs to hold them itself
+func (h *Handler) checkGroupsWithinScope(ctx context.Context, scope *organizationScope, groups []*contracts.Group) (err error) {
+	if scope == nil {
+		return nil
+	}
+
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		group, err := h.databaseClient.GetGroupByID(ctx, g.ID, map[api.FilterType][]string{})
+		if err != nil {
+			return err
+		}
+		if group == nil || !scope.manages(group.Organizations, group.Roles) {
+			return ErrOutsideOrganizationScope
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// the audit log records actions taken from slack the same way as those taken through the api
+const (
+	auditTargetTypeBuild   = "build"
+	auditTargetTypeRelease = "release"
+
+	auditActionBuildCreated   = "build.created"
+	auditActionBuildCanceled  = "build.canceled"
+	auditActionReleaseCreated = "release.created"
+)
+
+// getJobAuditTargetID returns the id of a build or release prefixed by its pipeline, like the api records it in the audit log
+func getJobAuditTargetID(source, owner, repo, id string) string {
+	return fmt.Sprintf("%v/%v/%v/%v", source, owner, repo, id)
+}
+
+// releaseRequest is the release a user is asked to confirm, stored as value of the interactive elements of a release message
+type releaseRequest struct {
+	RepoSource string `json:"repoSource"`
+	RepoOwner  string `json:"repoOwner"`
+	RepoName   string `json:"repoName"`
+	Version    string `json:"version"`
+	Name       string `json:"name"`
+	Action     string `json:"action,omitempty"`
+}
+
+func (r releaseRequest) target() string {
+	if r.Action != "" {
+		return fmt.Sprintf("%v/%v", r.Name, r.Action)
+	}
+
+	return r.Name
+}
+
+func (r releaseRequest) String() string {
+	return fmt.Sprintf("version %v of %v/%v/%v to %v", r.Version, r.RepoSource, r.RepoOwner, r.RepoName, r.target())
+}
+
+func (r releaseRequest) value() string {
+	value, _ := json.Marshal(r)
+
+	return string(value)
+}
+
+func parseReleaseRequest(value string) (request releaseRequest, err error) {
+	err = json.Unmarshal([]byte(value), &request)
+
+	return
+}
+
+// splitReleaseTarget splits a release target in the form <release>/<action> into its name and action
+func splitReleaseTarget(target string) (name, action string) {
+	parts := strings.SplitN(target, "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+
+	return parts[0], ""
+}
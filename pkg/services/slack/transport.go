@@ -1,228 +1,696 @@
 package slack
 
 import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/slackapi"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/rbac"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/ziplinee"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 	crypt "github.com/ziplineeci/ziplinee-ci-crypt"
 	manifest "github.com/ziplineeci/ziplinee-ci-manifest"
 )
 
+const (
+	// signatureMaxAge is how old the timestamp of a signed request can be, so captured requests can't be replayed later on
+	signatureMaxAge = 5 * time.Minute
+
+	actionIDReleaseTarget  = "release_target"
+	actionIDReleaseConfirm = "release_confirm"
+	actionIDReleaseCancel  = "release_cancel"
+
+	usage = "Use one of the following commands:\n" +
+		"/ziplinee status <repo>\n" +
+		"/ziplinee release <repo> <version> [<release>[/<action>]]\n" +
+		"/ziplinee rebuild <repo> <version>\n" +
+		"/ziplinee cancel <repo> <version>\n" +
+		"/ziplinee encrypt <value>"
+)
+
 // NewHandler returns a pubsub.Handler
-func NewHandler(secretHelper crypt.SecretHelper, config *api.APIConfig, slackapiClient slackapi.Client, databaseClient database.Client, ziplineeService ziplinee.Service) Handler {
+func NewHandler(secretHelper crypt.SecretHelper, config *api.APIConfig, slackapiClient slackapi.Client, databaseClient database.Client, ziplineeService ziplinee.Service, rbacService rbac.Service) Handler {
 	return Handler{
-		config:          config,
-		secretHelper:    secretHelper,
-		slackapiClient:  slackapiClient,
-		databaseClient:  databaseClient,
-		ziplineeService: ziplineeService,
+		config:           config,
+		secretHelper:     secretHelper,
+		slackapiClient:   slackapiClient,
+		databaseClient:   databaseClient,
+		ziplineeService:  ziplineeService,
+		rbacService:      rbacService,
+		customRolesCache: api.NewCustomRolesCache(databaseClient),
 	}
 }
 
 type Handler struct {
-	config          *api.APIConfig
-	secretHelper    crypt.SecretHelper
-	slackapiClient  slackapi.Client
-	databaseClient  database.Client
-	ziplineeService ziplinee.Service
+	config           *api.APIConfig
+	secretHelper     crypt.SecretHelper
+	slackapiClient   slackapi.Client
+	databaseClient   database.Client
+	ziplineeService  ziplinee.Service
+	rbacService      rbac.Service
+	customRolesCache *api.CustomRolesCache
 }
 
 func (h *Handler) Handle(c *gin.Context) {
 
 	// https://api.slack.com/slash-commands
 
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		log.Error().Err(err).Msg("Reading body from Slack command webhook failed")
+		c.String(http.StatusInternalServerError, "Reading body from Slack command webhook failed")
+		return
+	}
+
+	// restore the body so it can be bound after having been read for verifying its signature
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
 	var slashCommand slackapi.SlashCommand
 	// This will infer what binder to use depending on the content-type header.
-	err := c.Bind(&slashCommand)
+	err = c.Bind(&slashCommand)
 	if err != nil {
 		log.Error().Err(err).Msg("Binding form data from Slack command webhook failed")
 		c.String(http.StatusInternalServerError, "Binding form data from Slack command webhook failed")
 		return
 	}
 
-	hasValidVerificationToken := h.hasValidVerificationToken(slashCommand)
-	if !hasValidVerificationToken {
-		log.Warn().Str("expectedToken", h.config.Integrations.Slack.AppVerificationToken).Str("actualToken", slashCommand.Token).Msg("Verification token for Slack command is invalid")
-		c.String(http.StatusBadRequest, "Verification token for Slack command is invalid")
+	if !h.isVerifiedRequest(c, body, slashCommand.Token) {
+		log.Warn().Msg("Signature or verification token for Slack command is invalid")
+		c.String(http.StatusUnauthorized, "Signature or verification token for Slack command is invalid")
+		return
+	}
+
+	if slashCommand.Command != "/ziplinee" {
+		c.String(http.StatusOK, "Aye aye!")
+		return
+	}
+
+	splittedText := strings.Split(slashCommand.Text, " ")
+	command := splittedText[0]
+	arguments := strings.Fields(strings.Join(splittedText[1:], " "))
+
+	log.Debug().Msgf("Handling slash command /ziplinee %v", command)
+
+	var message slackapi.Message
+	switch command {
+	case "encrypt":
+		encryptedString, err := h.secretHelper.Encrypt(strings.Join(splittedText[1:], " "), "")
+		if err != nil {
+			log.Error().Err(err).Interface("slashCommand", slashCommand).Msg("Failed to encrypt secret")
+			message = getTextMessage("Incorrect usage of /ziplinee encrypt")
+			break
+		}
+		message = getTextMessage(fmt.Sprintf("ziplinee.secret(%v)", encryptedString))
+
+	case "status":
+		message = h.getStatus(c.Request.Context(), arguments)
+
+	case "release":
+		message = h.getReleasePrompt(c.Request.Context(), arguments)
+
+	case "rebuild":
+		message = h.rebuild(c, slashCommand.UserID, arguments)
+
+	case "cancel":
+		message = h.cancel(c, slashCommand.UserID, arguments)
+
+	default:
+		message = getTextMessage(usage)
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+// HandleInteraction handles users clicking the buttons and selecting the options of interactive messages
+func (h *Handler) HandleInteraction(c *gin.Context) {
+
+	// https://api.slack.com/interactivity/handling
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		log.Error().Err(err).Msg("Reading body from Slack interaction webhook failed")
+		c.String(http.StatusInternalServerError, "Reading body from Slack interaction webhook failed")
+		return
+	}
+
+	// restore the body so it can be bound after having been read for verifying its signature
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload slackapi.InteractionPayload
+	err = json.Unmarshal([]byte(c.PostForm("payload")), &payload)
+	if err != nil {
+		log.Error().Err(err).Msg("Unmarshalling payload from Slack interaction webhook failed")
+		c.String(http.StatusBadRequest, "Unmarshalling payload from Slack interaction webhook failed")
+		return
+	}
+
+	if !h.isVerifiedRequest(c, body, payload.Token) {
+		log.Warn().Msg("Signature or verification token for Slack interaction is invalid")
+		c.String(http.StatusUnauthorized, "Signature or verification token for Slack interaction is invalid")
+		return
+	}
+
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	action := payload.Actions[0]
+
+	value := action.Value
+	if action.SelectedOption != nil {
+		value = action.SelectedOption.Value
+	}
+	request, err := parseReleaseRequest(value)
+	if err != nil {
+		log.Error().Err(err).Msgf("Unmarshalling value of Slack action %v failed", action.ActionID)
+		c.String(http.StatusBadRequest, "Unmarshalling value of Slack action failed")
+		return
+	}
+
+	var message slackapi.Message
+	switch action.ActionID {
+	case actionIDReleaseTarget:
+		message = getReleaseConfirmation(request)
+	case actionIDReleaseConfirm:
+		message = h.release(c, payload.User.ID, request)
+	case actionIDReleaseCancel:
+		message = getTextMessage(fmt.Sprintf("Not releasing %v", request))
+	default:
+		c.Status(http.StatusOK)
 		return
 	}
 
-	if slashCommand.Command == "/ziplinee" {
-		if slashCommand.Text != "" {
-			splittedText := strings.Split(slashCommand.Text, " ")
-			if len(splittedText) > 0 {
-				command := splittedText[0]
-				arguments := splittedText[1:]
-				switch command {
-				case "encrypt":
-
-					log.Debug().Msg("Handling slash command /ziplinee encrypt")
-
-					encryptedString, err := h.secretHelper.Encrypt(strings.Join(arguments, " "), "")
-					if err != nil {
-						log.Error().Err(err).Interface("slashCommand", slashCommand).Msg("Failed to encrypt secret")
-						c.String(http.StatusOK, "Incorrect usage of /ziplinee encrypt")
-						return
-					}
-
-					c.String(http.StatusOK, fmt.Sprintf("ziplinee.secret(%v)", encryptedString))
-					return
-
-				case "release":
-
-					log.Debug().Msg("Handling slash command /ziplinee release")
-
-					// # release latest green version (for release branch?)
-					// /ziplinee release github.com/ziplinee/ziplinee-ci-builder beta
-					// /ziplinee release github.com/ziplinee/ziplinee-ci-api tooling
-
-					// # release latest green version for particular branch
-					// /ziplinee release github.com/ziplinee/ziplinee-ci-builder beta master
-
-					// # release specific version
-					// /ziplinee release github.com/ziplinee/ziplinee-ci-builder beta 0.0.47
-					// /ziplinee release github.com/ziplinee/ziplinee-ci-api beta 0.0.130
-
-					if len(arguments) < 3 {
-						c.String(http.StatusOK, "You have to few arguments, the command has to be of type /ziplinee release <repo> <release> <version>")
-						return
-					}
-
-					// retrieve pipeline with first argument
-					fullRepoName := arguments[0]
-					releaseName := arguments[1]
-					buildVersion := arguments[2]
-
-					fullRepoNameArray := strings.Split(fullRepoName, "/")
-					if len(fullRepoNameArray) != 1 && len(fullRepoNameArray) != 3 {
-						c.String(http.StatusOK, "Your repository needs to be of the form <repo name> or <repo source>/<repo owner>/<repo name>")
-						return
-					}
-
-					var pipeline *contracts.Pipeline
-					if len(fullRepoNameArray) == 1 {
-						pipelines, err := h.databaseClient.GetPipelinesByRepoName(c.Request.Context(), fullRepoName, false)
-						if err != nil {
-							log.Error().Err(err).Msgf("Failed retrieving pipelines for repo name %v by name", fullRepoName)
-							c.String(http.StatusOK, fmt.Sprintf("Retrieving the pipeline for repository %v from the database failed: %v", fullRepoName, err))
-							return
-						}
-						if len(pipelines) <= 0 {
-							c.String(http.StatusOK, fmt.Sprintf("The repo %v in your command does not have any ziplinee builds", fullRepoName))
-							return
-						}
-						if len(pipelines) > 1 {
-							commandsExample := ""
-							for _, p := range pipelines {
-								commandsExample += fmt.Sprintf("/ziplinee release %v/%v/%v %v %v\n", p.RepoSource, p.RepoOwner, p.RepoName, releaseName, buildVersion)
-							}
-							c.String(http.StatusOK, fmt.Sprintf("There are multiple pipelines with name %v, use the full name instead:\n%v", fullRepoName, commandsExample))
-							return
-						}
-						pipeline = pipelines[0]
-					} else {
-						pipeline, err := h.databaseClient.GetPipeline(c.Request.Context(), fullRepoNameArray[0], fullRepoNameArray[1], fullRepoNameArray[2], map[api.FilterType][]string{}, false)
-						if err != nil {
-							c.String(http.StatusOK, fmt.Sprintf("Retrieving the pipeline for repository %v from the database failed: %v", fullRepoName, err))
-							return
-						}
-						if pipeline == nil {
-							c.String(http.StatusOK, fmt.Sprintf("The repo %v in your command does not have any ziplinee builds", fullRepoName))
-							return
-						}
-					}
-
-					// check if version exists
-					builds, err := h.databaseClient.GetPipelineBuildsByVersion(c.Request.Context(), pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, buildVersion, []contracts.Status{contracts.StatusSucceeded}, 1, false)
-
-					if err != nil {
-						c.String(http.StatusOK, fmt.Sprintf("Retrieving the build for repository %v and version %v from the database failed: %v", fullRepoName, buildVersion, err))
-						return
-					}
-
-					var build *contracts.Build
-					// get first build
-					if len(builds) > 0 {
-						build = builds[0]
-					}
-					if build == nil {
-						c.String(http.StatusOK, fmt.Sprintf("The version %v in your command does not exist", buildVersion))
-						return
-					}
-					if build.BuildStatus != contracts.StatusSucceeded {
-						c.String(http.StatusOK, fmt.Sprintf("The build for version %v is not successful and cannot be used", buildVersion))
-						return
-					}
-
-					// check if release target exists
-					releaseExists := false
-					for _, releaseTarget := range build.ReleaseTargets {
-						if releaseTarget.Name == releaseName {
-							// todo support release action
-
-							releaseExists = true
-							break
-						}
-					}
-
-					if !releaseExists {
-						c.String(http.StatusOK, fmt.Sprintf("The release %v in your command is not defined in the manifest", releaseName))
-						return
-					}
-
-					// get user profile from api to set email address for TriggeredBy
-					profile, err := h.slackapiClient.GetUserProfile(c.Request.Context(), slashCommand.UserID)
-					if err != nil {
-						c.String(http.StatusOK, fmt.Sprintf("Failed retrieving Slack user profile for user id %v: %v", slashCommand.UserID, err))
-						return
-					}
-
-					// create release object and hand off to build service
-					createdRelease, err := h.ziplineeService.CreateRelease(c.Request.Context(), contracts.Release{
-						Name:           releaseName,
-						Action:         "", // no support for releas action yet
-						RepoSource:     build.RepoSource,
-						RepoOwner:      build.RepoOwner,
-						RepoName:       build.RepoName,
-						ReleaseVersion: buildVersion,
-						Groups:         build.Groups,
-						Organizations:  build.Organizations,
-
-						Events: []manifest.ZiplineeEvent{
-							{
-								Fired: true,
-								Manual: &manifest.ZiplineeManualEvent{
-									UserID: profile.Email,
-								},
-							},
-						},
-					}, *build.ManifestObject, build.RepoBranch, build.RepoRevision)
-
-					if err != nil {
-						errorMessage := fmt.Sprintf("Failed creating release %v for pipeline %v/%v/%v version %v for release command issued by %v", releaseName, build.RepoSource, build.RepoOwner, build.RepoName, buildVersion, profile.Email)
-						log.Error().Err(err).Msg(errorMessage)
-						c.String(http.StatusOK, fmt.Sprintf("Inserting starting the release: %v", err))
-						return
-					}
-
-					c.String(http.StatusOK, fmt.Sprintf("Started releasing version %v to %v: %vpipelines/%v/%v/%v/releases/%v/logs", buildVersion, releaseName, h.config.APIServer.BaseURL, build.RepoSource, build.RepoOwner, build.RepoName, createdRelease.ID))
-					return
-				}
+	// replace the interactive message, so it can't be used again
+	message.ReplaceOriginal = true
+	err = h.slackapiClient.PostResponse(c.Request.Context(), payload.ResponseURL, message)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed responding to Slack action %v", action.ActionID)
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *Handler) getStatus(ctx context.Context, arguments []string) slackapi.Message {
+
+	if len(arguments) < 1 {
+		return getTextMessage("You have too few arguments, the command has to be of type /ziplinee status <repo>")
+	}
+
+	pipeline, message := h.getPipeline(ctx, arguments[0])
+	if pipeline == nil {
+		return getTextMessage(message)
+	}
+
+	lines := []string{
+		fmt.Sprintf("*%v* version %v on branch %v: %v", pipeline.GetFullRepoPath(), pipeline.BuildVersion, pipeline.RepoBranch, pipeline.BuildStatus),
+	}
+	for _, rt := range pipeline.ReleaseTargets {
+		for _, r := range rt.ActiveReleases {
+			lines = append(lines, fmt.Sprintf("%v version %v: %v", releaseRequest{Name: r.Name, Action: r.Action}.target(), r.ReleaseVersion, r.ReleaseStatus))
+		}
+	}
+	lines = append(lines, fmt.Sprintf("%vpipelines/%v", h.config.APIServer.BaseURL, pipeline.GetFullRepoPath()))
+
+	return getTextMessage(strings.Join(lines, "\n"))
+}
+
+// getReleasePrompt returns a message for selecting the release target to release a version to, or for confirming the
+// release if the target is part of the command already
+func (h *Handler) getReleasePrompt(ctx context.Context, arguments []string) slackapi.Message {
+
+	// /ziplinee release github.com/ziplineeci/ziplinee-ci-builder 0.0.47
+	// /ziplinee release ziplinee-ci-api 0.0.130 beta
+	// /ziplinee release ziplinee-ci-api 0.0.130 production/deploy-canary
+
+	if len(arguments) < 2 {
+		return getTextMessage("You have too few arguments, the command has to be of type /ziplinee release <repo> <version> [<release>[/<action>]]")
+	}
+
+	pipeline, message := h.getPipeline(ctx, arguments[0])
+	if pipeline == nil {
+		return getTextMessage(message)
+	}
+
+	build, message := h.getReleasableBuild(ctx, pipeline, arguments[1])
+	if build == nil {
+		return getTextMessage(message)
+	}
+
+	request := releaseRequest{
+		RepoSource: build.RepoSource,
+		RepoOwner:  build.RepoOwner,
+		RepoName:   build.RepoName,
+		Version:    build.BuildVersion,
+	}
+
+	if len(arguments) > 2 {
+		request.Name, request.Action = splitReleaseTarget(arguments[2])
+		if !releaseTargetExists(build, request.Name, request.Action) {
+			return getTextMessage(fmt.Sprintf("The release %v in your command is not defined in the manifest", arguments[2]))
+		}
+
+		return getReleaseConfirmation(request)
+	}
+
+	// offer every release target and action, with the version that was last released to it
+	options := []*slackapi.Option{}
+	for _, rt := range build.ReleaseTargets {
+		actions := []string{}
+		for _, a := range rt.Actions {
+			actions = append(actions, a.Name)
+		}
+
+		lastReleases, err := h.databaseClient.GetPipelineLastReleasesByName(ctx, build.RepoSource, build.RepoOwner, build.RepoName, rt.Name, actions)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed retrieving last releases of %v for %v/%v/%v", rt.Name, build.RepoSource, build.RepoOwner, build.RepoName)
+		}
+
+		if len(actions) == 0 {
+			actions = []string{""}
+		}
+		for _, a := range actions {
+			option := request
+			option.Name = rt.Name
+			option.Action = a
+			options = append(options, getReleaseOption(option, lastReleases))
+		}
+	}
+
+	if len(options) == 0 {
+		return getTextMessage(fmt.Sprintf("Version %v of %v has no releases defined in its manifest", build.BuildVersion, pipeline.GetFullRepoPath()))
+	}
+
+	text := fmt.Sprintf("Where do you want to release version %v of %v to?", build.BuildVersion, pipeline.GetFullRepoPath())
+
+	return slackapi.Message{
+		Text: text,
+		Blocks: []*slackapi.Block{
+			{Type: "section", Text: &slackapi.Text{Type: "mrkdwn", Text: text}},
+			{Type: "actions", Elements: []*slackapi.Element{
+				{
+					Type:        "static_select",
+					ActionID:    actionIDReleaseTarget,
+					Placeholder: &slackapi.Text{Type: "plain_text", Text: "Select a release"},
+					Options:     options,
+				},
+			}},
+		},
+	}
+}
+
+// release creates a release confirmed by a Slack user, if the user they're linked to has permission to release
+func (h *Handler) release(c *gin.Context, slackUserID string, request releaseRequest) slackapi.Message {
+
+	ctx := c.Request.Context()
+
+	pipeline, err := h.databaseClient.GetPipeline(ctx, request.RepoSource, request.RepoOwner, request.RepoName, map[api.FilterType][]string{}, false)
+	if err != nil {
+		return getTextMessage(fmt.Sprintf("Retrieving the pipeline for repository %v/%v/%v from the database failed: %v", request.RepoSource, request.RepoOwner, request.RepoName, err))
+	}
+	if pipeline == nil {
+		return getTextMessage(fmt.Sprintf("The repo %v/%v/%v does not have any ziplinee builds", request.RepoSource, request.RepoOwner, request.RepoName))
+	}
+
+	build, message := h.getReleasableBuild(ctx, pipeline, request.Version)
+	if build == nil {
+		return getTextMessage(message)
+	}
+	if !releaseTargetExists(build, request.Name, request.Action) {
+		return getTextMessage(fmt.Sprintf("The release %v is not defined in the manifest", request.target()))
+	}
+
+	email, message := h.authorize(c, slackUserID, api.PermissionReleasesCreate, *pipeline, request.Name)
+	if message != "" {
+		return getTextMessage(message)
+	}
+
+	// create release object and hand off to build service
+	createdRelease, err := h.ziplineeService.CreateRelease(ctx, contracts.Release{
+		Name:           request.Name,
+		Action:         request.Action,
+		RepoSource:     build.RepoSource,
+		RepoOwner:      build.RepoOwner,
+		RepoName:       build.RepoName,
+		ReleaseVersion: build.BuildVersion,
+		Groups:         build.Groups,
+		Organizations:  build.Organizations,
+
+		Events: []manifest.ZiplineeEvent{
+			{
+				Fired: true,
+				Manual: &manifest.ZiplineeManualEvent{
+					UserID: email,
+				},
+			},
+		},
+	}, *build.ManifestObject, build.RepoBranch, build.RepoRevision)
+
+	if err != nil {
+		if errors.Is(err, ziplinee.ErrReleaseNotAllowed) {
+			return getTextMessage(fmt.Sprintf("Releasing %v is not allowed: %v", request, err))
+		}
+		log.Error().Err(err).Msgf("Failed creating release %v for release command issued by %v", request, email)
+		return getTextMessage(fmt.Sprintf("Starting the release failed: %v", err))
+	}
+
+	api.AddAuditDetails(c, auditActionReleaseCreated, auditTargetTypeRelease, getJobAuditTargetID(createdRelease.RepoSource, createdRelease.RepoOwner, createdRelease.RepoName, createdRelease.ID), nil, gin.H{"status": createdRelease.ReleaseStatus, "version": createdRelease.ReleaseVersion, "name": createdRelease.Name, "action": createdRelease.Action})
+
+	return getTextMessage(fmt.Sprintf("Started releasing %v: %vpipelines/%v/%v/%v/releases/%v/logs", request, h.config.APIServer.BaseURL, build.RepoSource, build.RepoOwner, build.RepoName, createdRelease.ID))
+}
+
+// rebuild re-runs a version of which all builds failed, like retrying a build in the web interface does
+func (h *Handler) rebuild(c *gin.Context, slackUserID string, arguments []string) slackapi.Message {
+
+	if len(arguments) < 2 {
+		return getTextMessage("You have too few arguments, the command has to be of type /ziplinee rebuild <repo> <version>")
+	}
+
+	ctx := c.Request.Context()
+	buildVersion := arguments[1]
+
+	pipeline, message := h.getPipeline(ctx, arguments[0])
+	if pipeline == nil {
+		return getTextMessage(message)
+	}
+
+	failedBuilds, err := h.databaseClient.GetPipelineBuildsByVersion(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, buildVersion, []contracts.Status{contracts.StatusFailed, contracts.StatusCanceled}, 1, false)
+	if err != nil {
+		return getTextMessage(fmt.Sprintf("Retrieving the build for repository %v and version %v from the database failed: %v", pipeline.GetFullRepoPath(), buildVersion, err))
+	}
+	if len(failedBuilds) == 0 {
+		return getTextMessage(fmt.Sprintf("The version %v in your command has no failed or canceled build", buildVersion))
+	}
+	failedBuild := failedBuilds[0]
+
+	nonFailedBuilds, err := h.databaseClient.GetPipelineBuildsByVersion(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, buildVersion, []contracts.Status{contracts.StatusSucceeded, contracts.StatusRunning, contracts.StatusPending, contracts.StatusCanceling, api.StatusQueued}, 1, false)
+	if err != nil {
+		return getTextMessage(fmt.Sprintf("Retrieving the build for repository %v and version %v from the database failed: %v", pipeline.GetFullRepoPath(), buildVersion, err))
+	}
+	if len(nonFailedBuilds) > 0 {
+		return getTextMessage(fmt.Sprintf("The version %v in your command has builds that are succeeded or running; only if all builds are failed the pipeline can be re-run", buildVersion))
+	}
+
+	email, message := h.authorize(c, slackUserID, api.PermissionBuildsRebuild, contracts.Pipeline{RepoSource: failedBuild.RepoSource, RepoOwner: failedBuild.RepoOwner, RepoName: failedBuild.RepoName, Labels: failedBuild.Labels}, "")
+	if message != "" {
+		return getTextMessage(message)
+	}
+
+	// set trigger event to manual
+	failedBuild.Events = []manifest.ZiplineeEvent{
+		{
+			Fired: true,
+			Manual: &manifest.ZiplineeManualEvent{
+				UserID: email,
+			},
+		},
+	}
+
+	createdBuild, err := h.ziplineeService.CreateBuild(ctx, *failedBuild)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed creating build %v version %v for rebuild command issued by %v", pipeline.GetFullRepoPath(), buildVersion, email)
+		return getTextMessage(fmt.Sprintf("Starting the build failed: %v", err))
+	}
+
+	api.AddAuditDetails(c, auditActionBuildCreated, auditTargetTypeBuild, getJobAuditTargetID(createdBuild.RepoSource, createdBuild.RepoOwner, createdBuild.RepoName, createdBuild.ID), nil, gin.H{"status": createdBuild.BuildStatus, "version": createdBuild.BuildVersion})
+
+	return getTextMessage(fmt.Sprintf("Started rebuilding version %v: %vpipelines/%v/%v/%v/builds/%v/logs", buildVersion, h.config.APIServer.BaseURL, createdBuild.RepoSource, createdBuild.RepoOwner, createdBuild.RepoName, createdBuild.ID))
+}
+
+// cancel cancels the build of a version that hasn't finished yet
+func (h *Handler) cancel(c *gin.Context, slackUserID string, arguments []string) slackapi.Message {
+
+	if len(arguments) < 2 {
+		return getTextMessage("You have too few arguments, the command has to be of type /ziplinee cancel <repo> <version>")
+	}
+
+	ctx := c.Request.Context()
+	buildVersion := arguments[1]
+
+	pipeline, message := h.getPipeline(ctx, arguments[0])
+	if pipeline == nil {
+		return getTextMessage(message)
+	}
+
+	builds, err := h.databaseClient.GetPipelineBuildsByVersion(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, buildVersion, []contracts.Status{contracts.StatusRunning, contracts.StatusPending, contracts.StatusCanceling, api.StatusQueued}, 1, false)
+	if err != nil {
+		return getTextMessage(fmt.Sprintf("Retrieving the build for repository %v and version %v from the database failed: %v", pipeline.GetFullRepoPath(), buildVersion, err))
+	}
+	if len(builds) == 0 {
+		return getTextMessage(fmt.Sprintf("The version %v in your command has no build that can be canceled", buildVersion))
+	}
+	build := builds[0]
+
+	email, message := h.authorize(c, slackUserID, api.PermissionBuildsCancel, contracts.Pipeline{RepoSource: build.RepoSource, RepoOwner: build.RepoOwner, RepoName: build.RepoName, Labels: build.Labels}, "")
+	if message != "" {
+		return getTextMessage(message)
+	}
+
+	status, err := h.ziplineeService.CancelJob(ctx, contracts.JobTypeBuild, build.RepoSource, build.RepoOwner, build.RepoName, build.ID, build.BuildStatus)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed canceling build %v version %v for cancel command issued by %v", pipeline.GetFullRepoPath(), buildVersion, email)
+		return getTextMessage(fmt.Sprintf("Canceling the build failed: %v", err))
+	}
+
+	api.AddAuditDetails(c, auditActionBuildCanceled, auditTargetTypeBuild, getJobAuditTargetID(build.RepoSource, build.RepoOwner, build.RepoName, build.ID), gin.H{"status": build.BuildStatus}, gin.H{"status": status})
+
+	if status == contracts.StatusCanceling {
+		return getTextMessage(fmt.Sprintf("Canceling the build of version %v of %v", buildVersion, pipeline.GetFullRepoPath()))
+	}
+
+	return getTextMessage(fmt.Sprintf("Canceled the build of version %v of %v", buildVersion, pipeline.GetFullRepoPath()))
+}
+
+// getPipeline returns the pipeline for a repository of the form <repo name> or <repo source>/<repo owner>/<repo name>, or
+// a message explaining why it can't be found
+func (h *Handler) getPipeline(ctx context.Context, fullRepoName string) (pipeline *contracts.Pipeline, message string) {
+
+	fullRepoNameArray := strings.Split(fullRepoName, "/")
+	if len(fullRepoNameArray) != 1 && len(fullRepoNameArray) != 3 {
+		return nil, "Your repository needs to be of the form <repo name> or <repo source>/<repo owner>/<repo name>"
+	}
+
+	if len(fullRepoNameArray) == 3 {
+		pipeline, err := h.databaseClient.GetPipeline(ctx, fullRepoNameArray[0], fullRepoNameArray[1], fullRepoNameArray[2], map[api.FilterType][]string{}, false)
+		if err != nil {
+			return nil, fmt.Sprintf("Retrieving the pipeline for repository %v from the database failed: %v", fullRepoName, err)
+		}
+		if pipeline == nil {
+			return nil, fmt.Sprintf("The repo %v in your command does not have any ziplinee builds", fullRepoName)
+		}
+
+		return pipeline, ""
+	}
+
+	pipelines, err := h.databaseClient.GetPipelinesByRepoName(ctx, fullRepoName, false)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving pipelines for repo name %v by name", fullRepoName)
+		return nil, fmt.Sprintf("Retrieving the pipeline for repository %v from the database failed: %v", fullRepoName, err)
+	}
+	if len(pipelines) <= 0 {
+		return nil, fmt.Sprintf("The repo %v in your command does not have any ziplinee builds", fullRepoName)
+	}
+	if len(pipelines) > 1 {
+		fullNames := []string{}
+		for _, p := range pipelines {
+			fullNames = append(fullNames, p.GetFullRepoPath())
+		}
+		return nil, fmt.Sprintf("There are multiple pipelines with name %v, use the full name instead:\n%v", fullRepoName, strings.Join(fullNames, "\n"))
+	}
+
+	return pipelines[0], ""
+}
+
+// getReleasableBuild returns the succeeded build of a version, or a message explaining why it can't be released
+func (h *Handler) getReleasableBuild(ctx context.Context, pipeline *contracts.Pipeline, buildVersion string) (build *contracts.Build, message string) {
+
+	builds, err := h.databaseClient.GetPipelineBuildsByVersion(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, buildVersion, []contracts.Status{contracts.StatusSucceeded}, 1, false)
+	if err != nil {
+		return nil, fmt.Sprintf("Retrieving the build for repository %v and version %v from the database failed: %v", pipeline.GetFullRepoPath(), buildVersion, err)
+	}
+
+	// get first build
+	if len(builds) > 0 {
+		build = builds[0]
+	}
+	if build == nil {
+		return nil, fmt.Sprintf("The version %v in your command does not exist", buildVersion)
+	}
+	if build.BuildStatus != contracts.StatusSucceeded {
+		return nil, fmt.Sprintf("The build for version %v is not successful and cannot be used", buildVersion)
+	}
+	if build.ManifestObject == nil {
+		return nil, fmt.Sprintf("The build for version %v has no valid manifest and cannot be released", buildVersion)
+	}
+
+	return build, ""
+}
+
+// authorize sets the claims of the Ziplinee user linked to a Slack user through the email address of their Slack profile on
+// the request, and checks whether that user has a permission for the pipeline; if not it returns a message explaining why
+func (h *Handler) authorize(c *gin.Context, slackUserID string, permission api.Permission, pipeline contracts.Pipeline, releaseTarget string) (email, message string) {
+
+	profile, err := h.slackapiClient.GetUserProfile(c.Request.Context(), slackUserID)
+	if err != nil {
+		return "", fmt.Sprintf("Failed retrieving Slack user profile for user id %v: %v", slackUserID, err)
+	}
+	if profile.Email == "" {
+		return "", "Your Slack profile has no email address to link it to a Ziplinee user"
+	}
+
+	user, err := h.databaseClient.GetUserByEmail(c.Request.Context(), profile.Email)
+	if errors.Is(err, database.ErrUserNotFound) || (err == nil && (user == nil || !user.Active)) {
+		return "", fmt.Sprintf("There is no Ziplinee user for your email address %v; log in to Ziplinee first", profile.Email)
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving user for Slack user %v", slackUserID)
+		return "", fmt.Sprintf("Retrieving the Ziplinee user for your email address %v failed: %v", profile.Email, err)
+	}
+
+	// get all roles and organizations the user inherits from groups and organizations, like at login
+	user.Roles, err = h.rbacService.GetInheritedRolesForUser(c.Request.Context(), *user)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving inherited roles of user %v for Slack user %v", user.ID, slackUserID)
+		return "", fmt.Sprintf("Retrieving the permissions of your Ziplinee user failed: %v", err)
+	}
+	user.Organizations, err = h.rbacService.GetInheritedOrganizationsForUser(c.Request.Context(), *user)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving inherited organizations of user %v for Slack user %v", user.ID, slackUserID)
+		return "", fmt.Sprintf("Retrieving the permissions of your Ziplinee user failed: %v", err)
+	}
+
+	err = api.SetUserClaims(c, user)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed setting claims of user %v for Slack user %v", user.ID, slackUserID)
+		return "", fmt.Sprintf("Retrieving the permissions of your Ziplinee user failed: %v", err)
+	}
+
+	hasPermission, err := api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, permission, pipeline, releaseTarget)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed checking permission %v for Slack user %v", permission, slackUserID)
+		return "", fmt.Sprintf("Checking your permissions failed: %v", err)
+	}
+	if !hasPermission {
+		return "", fmt.Sprintf("Your Ziplinee user %v does not have permission %v for %v", profile.Email, permission, pipeline.GetFullRepoPath())
+	}
+
+	return profile.Email, ""
+}
+
+// isVerifiedRequest returns true if a request is signed with the app's signing secret, or if no signing secret is configured
+// if it has the deprecated verification token
+func (h *Handler) isVerifiedRequest(c *gin.Context, body []byte, token string) bool {
+
+	slackConfig := h.config.Integrations.Slack
+	if slackConfig == nil {
+		return false
+	}
+	if slackConfig.SigningSecret == "" {
+		return token != "" && token == slackConfig.AppVerificationToken
+	}
+
+	return hasValidSignature(slackConfig.SigningSecret, body, c.GetHeader("X-Slack-Request-Timestamp"), c.GetHeader("X-Slack-Signature"), time.Now())
+}
+
+// hasValidSignature verifies the signature of a request from Slack, see https://api.slack.com/authentication/verifying-requests-from-slack
+func hasValidSignature(signingSecret string, body []byte, timestamp, signature string, now time.Time) bool {
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Slack request has invalid timestamp '%v'", timestamp)
+		return false
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > signatureMaxAge || age < -signatureMaxAge {
+		log.Warn().Msgf("Slack request timestamp is %v old", age)
+		return false
+	}
+
+	if !strings.HasPrefix(signature, "v0=") {
+		return false
+	}
+	actualMAC, err := hex.DecodeString(strings.TrimPrefix(signature, "v0="))
+	if err != nil {
+		log.Warn().Err(err).Msg("Decoding hexadecimal Slack request signature to byte array failed")
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(fmt.Sprintf("v0:%v:", timestamp)))
+	mac.Write(body)
+
+	return hmac.Equal(actualMAC, mac.Sum(nil))
+}
+
+// releaseTargetExists returns true if a build has the release target and, for targets with actions, the action
+func releaseTargetExists(build *contracts.Build, name, action string) bool {
+	for _, rt := range build.ReleaseTargets {
+		if rt.Name != name {
+			continue
+		}
+		if len(rt.Actions) == 0 {
+			return action == ""
+		}
+		for _, a := range rt.Actions {
+			if a.Name == action {
+				return true
 			}
 		}
 	}
 
-	c.String(http.StatusOK, "Aye aye!")
+	return false
+}
+
+func getReleaseConfirmation(request releaseRequest) slackapi.Message {
+
+	text := fmt.Sprintf("Do you want to release %v?", request)
+
+	return slackapi.Message{
+		Text: text,
+		Blocks: []*slackapi.Block{
+			{Type: "section", Text: &slackapi.Text{Type: "mrkdwn", Text: text}},
+			{Type: "actions", Elements: []*slackapi.Element{
+				{Type: "button", ActionID: actionIDReleaseConfirm, Text: &slackapi.Text{Type: "plain_text", Text: "Release"}, Value: request.value(), Style: "primary"},
+				{Type: "button", ActionID: actionIDReleaseCancel, Text: &slackapi.Text{Type: "plain_text", Text: "Cancel"}, Value: request.value()},
+			}},
+		},
+	}
+}
+
+// getReleaseOption returns the option for selecting a release target, showing what was released to it last
+func getReleaseOption(request releaseRequest, lastReleases []contracts.Release) *slackapi.Option {
+
+	text := request.target()
+	for _, r := range lastReleases {
+		if r.Name == request.Name && r.Action == request.Action {
+			text = fmt.Sprintf("%v (%v %v)", text, r.ReleaseVersion, r.ReleaseStatus)
+			break
+		}
+	}
+
+	return &slackapi.Option{
+		Text:  &slackapi.Text{Type: "plain_text", Text: text},
+		Value: request.value(),
+	}
 }
 
-func (h *Handler) hasValidVerificationToken(slashCommand slackapi.SlashCommand) bool {
-	return slashCommand.Token == h.config.Integrations.Slack.AppVerificationToken
+func getTextMessage(text string) slackapi.Message {
+	return slackapi.Message{
+		Text: text,
+	}
 }
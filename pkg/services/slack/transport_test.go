@@ -0,0 +1,291 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/slackapi"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/rbac"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/services/ziplinee"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+func TestHasValidSignature(t *testing.T) {
+
+	now := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	timestamp := fmt.Sprint(now.Unix())
+	body := []byte("token=abc&command=%2Fziplinee&text=status+ziplinee-ci-api")
+
+	t.Run("ReturnsTrueIfSignedWithSigningSecret", func(t *testing.T) {
+
+		// act
+		valid := hasValidSignature("my-secret", body, timestamp, getSignature("my-secret", timestamp, body), now.Add(time.Minute))
+
+		assert.True(t, valid)
+	})
+
+	t.Run("ReturnsFalseIfSignedWithOtherSecret", func(t *testing.T) {
+
+		// act
+		valid := hasValidSignature("my-secret", body, timestamp, getSignature("other-secret", timestamp, body), now)
+
+		assert.False(t, valid)
+	})
+
+	t.Run("ReturnsFalseIfTimestampIsTooOld", func(t *testing.T) {
+
+		// act
+		valid := hasValidSignature("my-secret", body, timestamp, getSignature("my-secret", timestamp, body), now.Add(10*time.Minute))
+
+		assert.False(t, valid)
+	})
+}
+
+func TestHandle(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("ReturnsUnauthorizedIfSignatureIsInvalid", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler := getTestHandler(ctrl, nil)
+
+		form := url.Values{"command": {"/ziplinee"}, "text": {"status ziplinee-ci-api"}}
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = getSignedRequest("/api/integrations/slack/slash", form.Encode(), "other-secret")
+
+		// act
+		handler.Handle(c)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("ReturnsUsageForUnknownCommand", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler := getTestHandler(ctrl, nil)
+
+		form := url.Values{"command": {"/ziplinee"}, "text": {"deploy ziplinee-ci-api"}}
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = getSignedRequest("/api/integrations/slack/slash", form.Encode(), "my-secret")
+
+		// act
+		handler.Handle(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Use one of the following commands")
+	})
+}
+
+func TestHandle_Cancel(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	getCancelTestHandler := func(ctrl *gomock.Controller, inheritedRoles []*string) (Handler, *ziplinee.MockService) {
+
+		slackapiClient := slackapi.NewMockClient(ctrl)
+		slackapiClient.
+			EXPECT().
+			GetUserProfile(gomock.Any(), "U1").
+			Return(&slackapi.UserProfile{Email: "me@server.com"}, nil)
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", gomock.Any(), false).
+			Return(&contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api"}, nil)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildsByVersion(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "1.0.3", gomock.Any(), uint64(1), false).
+			Return([]*contracts.Build{{ID: "15", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", BuildStatus: contracts.StatusRunning}}, nil)
+		databaseClient.
+			EXPECT().
+			GetUserByEmail(gomock.Any(), "me@server.com").
+			Return(&contracts.User{ID: "5", Active: true, Identities: []*contracts.UserIdentity{{Email: "me@server.com"}}}, nil)
+		databaseClient.
+			EXPECT().
+			GetCustomRoles(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*api.CustomRole{}, nil).
+			AnyTimes()
+
+		// the user only has roles through its groups
+		rbacService := rbac.NewMockService(ctrl)
+		rbacService.
+			EXPECT().
+			GetInheritedRolesForUser(gomock.Any(), gomock.Any()).
+			Return(inheritedRoles, nil)
+		rbacService.
+			EXPECT().
+			GetInheritedOrganizationsForUser(gomock.Any(), gomock.Any()).
+			Return([]*contracts.Organization{}, nil)
+
+		ziplineeService := ziplinee.NewMockService(ctrl)
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				BaseURL: "https://ci.ziplinee.io/",
+			},
+			Auth: &api.AuthConfig{
+				EnforcePipelinePermissions: true,
+			},
+			Integrations: &api.APIConfigIntegrations{
+				Slack: &api.SlackConfig{
+					Enable:        true,
+					SigningSecret: "my-secret",
+				},
+			},
+		}
+
+		return NewHandler(nil, config, slackapiClient, databaseClient, ziplineeService, rbacService), ziplineeService
+	}
+
+	t.Run("CancelsBuildWithPermissionInheritedFromGroupAndRecordsIt", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		administrator := api.RoleAdministrator.String()
+		handler, ziplineeService := getCancelTestHandler(ctrl, []*string{&administrator})
+		ziplineeService.
+			EXPECT().
+			CancelJob(gomock.Any(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "15", contracts.StatusRunning).
+			Return(contracts.StatusCanceling, nil)
+
+		form := url.Values{"command": {"/ziplinee"}, "text": {"cancel github.com/ziplineeci/ziplinee-ci-api 1.0.3"}, "user_id": {"U1"}}
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = getSignedRequest("/api/integrations/slack/slash", form.Encode(), "my-secret")
+
+		// act
+		handler.Handle(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Canceling the build of version 1.0.3")
+		details, ok := api.GetAuditDetails(c)
+		if assert.True(t, ok) && assert.Equal(t, 1, len(details)) {
+			assert.Equal(t, "build.canceled", details[0].Action)
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api/15", details[0].TargetID)
+		}
+		assert.Equal(t, "me@server.com", api.GetEmailFromRequest(c))
+	})
+
+	t.Run("RefusesToCancelBuildWithoutPermission", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, ziplineeService := getCancelTestHandler(ctrl, []*string{})
+		ziplineeService.
+			EXPECT().
+			CancelJob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		form := url.Values{"command": {"/ziplinee"}, "text": {"cancel github.com/ziplineeci/ziplinee-ci-api 1.0.3"}, "user_id": {"U1"}}
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = getSignedRequest("/api/integrations/slack/slash", form.Encode(), "my-secret")
+
+		// act
+		handler.Handle(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "does not have permission")
+		_, ok := api.GetAuditDetails(c)
+		assert.False(t, ok)
+	})
+}
+
+func TestHandleInteraction(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("ReplacesConfirmationMessageWhenReleaseIsCanceled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		slackapiClient := slackapi.NewMockClient(ctrl)
+		handler := getTestHandler(ctrl, slackapiClient)
+
+		request := releaseRequest{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Version: "1.0.3", Name: "production"}
+
+		slackapiClient.
+			EXPECT().
+			PostResponse(gomock.Any(), "https://hooks.slack.com/actions/T0/1/abc", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, responseURL string, message slackapi.Message) error {
+				assert.True(t, message.ReplaceOriginal)
+				assert.Equal(t, "Not releasing version 1.0.3 of github.com/ziplineeci/ziplinee-ci-api to production", message.Text)
+				return nil
+			})
+
+		payload := fmt.Sprintf(`{"type":"block_actions","user":{"id":"U1"},"response_url":"https://hooks.slack.com/actions/T0/1/abc","actions":[{"action_id":"release_cancel","value":%q}]}`, request.value())
+		form := url.Values{"payload": {payload}}
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = getSignedRequest("/api/integrations/slack/interactive", form.Encode(), "my-secret")
+
+		// act
+		handler.HandleInteraction(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
+
+func getTestHandler(ctrl *gomock.Controller, slackapiClient slackapi.Client) Handler {
+	if slackapiClient == nil {
+		slackapiClient = slackapi.NewMockClient(ctrl)
+	}
+
+	config := &api.APIConfig{
+		APIServer: &api.APIServerConfig{
+			BaseURL: "https://ci.ziplinee.io/",
+		},
+		Integrations: &api.APIConfigIntegrations{
+			Slack: &api.SlackConfig{
+				Enable:        true,
+				SigningSecret: "my-secret",
+			},
+		},
+	}
+
+	return NewHandler(nil, config, slackapiClient, database.NewMockClient(ctrl), ziplinee.NewMockService(ctrl), rbac.NewMockService(ctrl))
+}
+
+func getSignedRequest(path, body, signingSecret string) *http.Request {
+	timestamp := fmt.Sprint(time.Now().Unix())
+
+	request := httptest.NewRequest("POST", "https://ci.ziplinee.io"+path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	request.Header.Set("X-Slack-Signature", getSignature(signingSecret, timestamp, []byte(body)))
+
+	return request
+}
+
+func getSignature(signingSecret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(fmt.Sprintf("v0:%v:", timestamp)))
+	mac.Write(body)
+
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,326 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+)
+
+const (
+	// OutcomeReceived is set when a delivery is stored, before it's handled
+	OutcomeReceived = "received"
+	// OutcomeProcessed is set when a delivery has been handled successfully
+	OutcomeProcessed = "processed"
+	// OutcomeIgnored is set when a delivery has been accepted, but dropped without taking action
+	OutcomeIgnored = "ignored"
+	// OutcomeRejected is set when a delivery has been refused, for example due to an invalid signature
+	OutcomeRejected = "rejected"
+	// OutcomeFailed is set when handling a delivery failed
+	OutcomeFailed = "failed"
+)
+
+// headers that are needed to replay a delivery, but shouldn't be exposed through the api
+var redactedHeaders = []string{"Authorization", "Cookie"}
+
+// NewHandler returns a new webhook.Handler
+func NewHandler(config *api.APIConfig, databaseClient database.Client) Handler {
+	return Handler{
+		config:         config,
+		databaseClient: databaseClient,
+		registrations:  map[string]registration{},
+	}
+}
+
+type Handler struct {
+	config         *api.APIConfig
+	databaseClient database.Client
+	registrations  map[string]registration
+}
+
+type registration struct {
+	eventTypeHeader string
+	handlerFunc     gin.HandlerFunc
+	authenticate    gin.HandlerFunc
+}
+
+// Record wraps an integration's event handler, to store every delivery with its outcome and to be able to replay it later on
+func (h *Handler) Record(source, eventTypeHeader string, handlerFunc gin.HandlerFunc) gin.HandlerFunc {
+	h.registrations[source] = registration{
+		eventTypeHeader: eventTypeHeader,
+		handlerFunc:     handlerFunc,
+	}
+
+	return func(c *gin.Context) {
+		h.record(c, source, "")
+	}
+}
+
+// RecordAuthenticated wraps an integration's event handler like Record, for sources whose deliveries are authenticated by middleware in front
+// of the handler; the same middleware re-checks the stored credentials when a delivery gets replayed
+func (h *Handler) RecordAuthenticated(source, eventTypeHeader string, authenticate, handlerFunc gin.HandlerFunc) gin.HandlerFunc {
+	recordFunc := h.Record(source, eventTypeHeader, handlerFunc)
+
+	reg := h.registrations[source]
+	reg.authenticate = authenticate
+	h.registrations[source] = reg
+
+	return recordFunc
+}
+
+func (h *Handler) record(c *gin.Context, source, replayOf string) (delivery *database.WebhookDelivery) {
+
+	reg := h.registrations[source]
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		log.Error().Err(err).Msgf("Reading body from %v webhook failed", source)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	// restore the body so the wrapped handler can read it as if nothing happened
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	bodyHash := sha256.Sum256(body)
+	authUser, _ := c.Get(gin.AuthUserKey)
+	authUserString, _ := authUser.(string)
+
+	eventType := ""
+	if reg.eventTypeHeader != "" {
+		eventType = c.GetHeader(reg.eventTypeHeader)
+	}
+
+	delivery, err = h.databaseClient.InsertWebhookDelivery(c.Request.Context(), database.WebhookDelivery{
+		Source:    source,
+		EventType: eventType,
+		Headers:   c.Request.Header.Clone(),
+		Body:      body,
+		BodyHash:  hex.EncodeToString(bodyHash[:]),
+		AuthUser:  authUserString,
+		Outcome:   OutcomeReceived,
+		ReplayOf:  replayOf,
+	})
+	if err != nil {
+		// failing to store the delivery shouldn't stop the event from being handled
+		log.Warn().Err(err).Msgf("Storing %v webhook delivery failed", source)
+	}
+
+	reg.handlerFunc(c)
+
+	if delivery == nil {
+		return
+	}
+
+	delivery.StatusCode = c.Writer.Status()
+	delivery.Outcome = getOutcome(delivery.StatusCode, c.Errors)
+	if lastError := c.Errors.Last(); lastError != nil {
+		delivery.Error = lastError.Error()
+	}
+
+	err = h.databaseClient.UpdateWebhookDeliveryOutcome(c.Request.Context(), delivery.ID, delivery.Outcome, delivery.StatusCode, delivery.Error)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Updating outcome for %v webhook delivery %v failed", source, delivery.ID)
+	}
+
+	return
+}
+
+func getOutcome(statusCode int, errs []*gin.Error) string {
+	switch {
+	case statusCode >= 500:
+		return OutcomeFailed
+	case statusCode >= 400:
+		return OutcomeRejected
+	case len(errs) > 0:
+		return OutcomeIgnored
+	}
+
+	return OutcomeProcessed
+}
+
+func (h *Handler) GetDeliveries(c *gin.Context) {
+
+	pageNumber, pageSize, filters, sortings := api.GetQueryParameters(c)
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionWebhooksList) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	response, err := api.GetPagedListResponse(
+		func() ([]interface{}, error) {
+			deliveries, err := h.databaseClient.GetWebhookDeliveries(ctx, pageNumber, pageSize, filters, sortings)
+			if err != nil {
+				return nil, err
+			}
+
+			// convert typed array to interface array O(n)
+			items := make([]interface{}, len(deliveries))
+			for i := range deliveries {
+				items[i] = deliveries[i]
+			}
+			return items, nil
+		},
+		func() (int, error) {
+			return h.databaseClient.GetWebhookDeliveriesCount(ctx, filters)
+		},
+		pageNumber,
+		pageSize)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed retrieving webhook deliveries from db")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetDelivery(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionWebhooksGet) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	delivery, err := h.databaseClient.GetWebhookDeliveryByID(ctx, id)
+	if err != nil || delivery == nil {
+		log.Error().Err(err).Msgf("Failed retrieving webhook delivery with id %v from db", id)
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+		return
+	}
+
+	c.JSON(http.StatusOK, newDeliveryResponse(delivery))
+}
+
+func (h *Handler) ReplayDelivery(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionWebhooksReplay) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	delivery, err := h.databaseClient.GetWebhookDeliveryByID(ctx, id)
+	if err != nil || delivery == nil {
+		log.Error().Err(err).Msgf("Failed retrieving webhook delivery with id %v from db", id)
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound)})
+		return
+	}
+
+	replayed, err := h.replay(c, delivery)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed replaying webhook delivery with id %v", id)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newDeliveryResponse(replayed))
+}
+
+// replay re-feeds a stored delivery through the same handler it originally went through; the handler itself still verifies signatures and
+// tokens, and for sources authenticated by middleware the middleware runs again against the stored headers instead of trusting the stored user
+func (h *Handler) replay(c *gin.Context, delivery *database.WebhookDelivery) (replayed *database.WebhookDelivery, err error) {
+
+	reg, ok := h.registrations[delivery.Source]
+	if !ok {
+		return nil, fmt.Errorf("Webhook deliveries for source %v can't be replayed", delivery.Source)
+	}
+
+	request, err := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, "/replay", bytes.NewReader(delivery.Body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range delivery.Headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	// run the handler in its own router, so its response doesn't end up in the response for the admin request
+	router := gin.New()
+	router.POST("/replay", func(rc *gin.Context) {
+		if reg.authenticate != nil {
+			reg.authenticate(rc)
+			if _, ok := rc.Get(gin.AuthUserKey); !ok {
+				err = fmt.Errorf("The stored credentials of webhook delivery %v are no longer valid, it can't be replayed", delivery.ID)
+				return
+			}
+		}
+		replayed = h.record(rc, delivery.Source, delivery.ID)
+	})
+	router.ServeHTTP(newDiscardResponseWriter(), request)
+
+	if err != nil {
+		return nil, err
+	}
+	if replayed == nil {
+		return nil, errors.New("Storing replayed webhook delivery failed")
+	}
+
+	return replayed, nil
+}
+
+type deliveryResponse struct {
+	*database.WebhookDelivery
+	Body string `json:"body,omitempty"`
+}
+
+// newDeliveryResponse returns the delivery with its body as text and with sensitive header values obfuscated
+func newDeliveryResponse(delivery *database.WebhookDelivery) deliveryResponse {
+	redacted := *delivery
+	redacted.Headers = make(http.Header, len(delivery.Headers))
+	for key, values := range delivery.Headers {
+		redacted.Headers[key] = values
+		for _, rh := range redactedHeaders {
+			if strings.EqualFold(key, rh) {
+				redacted.Headers[key] = []string{"***"}
+			}
+		}
+	}
+
+	return deliveryResponse{
+		WebhookDelivery: &redacted,
+		Body:            string(delivery.Body),
+	}
+}
+
+// discardResponseWriter is an http.ResponseWriter that only keeps track of headers, to let a replayed handler write its response into the void
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func newDiscardResponseWriter() *discardResponseWriter {
+	return &discardResponseWriter{header: http.Header{}}
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(statusCode int) {
+}
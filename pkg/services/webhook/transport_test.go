@@ -0,0 +1,294 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+)
+
+func TestRecord(t *testing.T) {
+	t.Run("StoresDeliveryAndUpdatesOutcomeAfterHandlingIt", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			InsertWebhookDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.WebhookDelivery) (*database.WebhookDelivery, error) {
+				assert.Equal(t, "github", delivery.Source)
+				assert.Equal(t, "push", delivery.EventType)
+				assert.Equal(t, `{"ref":"refs/heads/main"}`, string(delivery.Body))
+				assert.Equal(t, "090ba1b9ca860d37bb4ca7492549a8a347caac3490f763399e6498622c9b47f9", delivery.BodyHash)
+				assert.Equal(t, "push", delivery.Headers.Get("X-Github-Event"))
+				assert.Equal(t, OutcomeReceived, delivery.Outcome)
+				delivery.ID = "15"
+				return &delivery, nil
+			})
+
+		databaseClient.
+			EXPECT().
+			UpdateWebhookDeliveryOutcome(gomock.Any(), "15", OutcomeProcessed, http.StatusOK, "").
+			Times(1)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+
+		var handledBody string
+		handlerFunc := handler.Record("github", "X-Github-Event", func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			handledBody = string(body)
+			c.Status(http.StatusOK)
+		})
+
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/integrations/github/events", strings.NewReader(`{"ref":"refs/heads/main"}`))
+		c.Request.Header.Set("X-Github-Event", "push")
+
+		// act
+		handlerFunc(c)
+
+		assert.Equal(t, `{"ref":"refs/heads/main"}`, handledBody)
+	})
+
+	t.Run("StoresErrorAsIgnoredIfHandlerDropsEventWithoutFailing", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			InsertWebhookDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.WebhookDelivery) (*database.WebhookDelivery, error) {
+				delivery.ID = "15"
+				return &delivery, nil
+			})
+
+		databaseClient.
+			EXPECT().
+			UpdateWebhookDeliveryOutcome(gomock.Any(), "15", OutcomeIgnored, http.StatusOK, "The repository has no manifest at the pushed commit").
+			Times(1)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+
+		handlerFunc := handler.Record("github", "X-Github-Event", func(c *gin.Context) {
+			_ = c.Error(errors.New("The repository has no manifest at the pushed commit"))
+			c.Status(http.StatusOK)
+		})
+
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/integrations/github/events", strings.NewReader(`{}`))
+
+		// act
+		handlerFunc(c)
+	})
+
+	t.Run("HandlesEventIfStoringDeliveryFails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			InsertWebhookDelivery(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("database is down"))
+
+		databaseClient.
+			EXPECT().
+			UpdateWebhookDeliveryOutcome(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+
+		handled := false
+		handlerFunc := handler.Record("bitbucket", "X-Event-Key", func(c *gin.Context) {
+			handled = true
+			c.Status(http.StatusOK)
+		})
+
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/integrations/bitbucket/events", strings.NewReader(`{}`))
+
+		// act
+		handlerFunc(c)
+
+		assert.True(t, handled)
+	})
+}
+
+func TestGetOutcome(t *testing.T) {
+	t.Run("ReturnsOutcomeForStatusCodeAndErrors", func(t *testing.T) {
+
+		assert.Equal(t, OutcomeProcessed, getOutcome(http.StatusOK, nil))
+		assert.Equal(t, OutcomeIgnored, getOutcome(http.StatusOK, []*gin.Error{{Err: errors.New("not cloneable")}}))
+		assert.Equal(t, OutcomeRejected, getOutcome(http.StatusUnauthorized, nil))
+		assert.Equal(t, OutcomeFailed, getOutcome(http.StatusInternalServerError, nil))
+	})
+}
+
+func TestReplayDelivery(t *testing.T) {
+	t.Run("ReFeedsStoredPayloadThroughRegisteredHandler", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetWebhookDeliveryByID(gomock.Any(), "15").
+			Return(&database.WebhookDelivery{
+				ID:       "15",
+				Source:   "cloudsource",
+				Headers:  http.Header{"Content-Type": []string{"application/json"}, "Authorization": []string{"Bearer abc"}},
+				Body:     []byte(`{"message":{}}`),
+				AuthUser: "google-jwt",
+				Outcome:  OutcomeFailed,
+			}, nil)
+
+		databaseClient.
+			EXPECT().
+			InsertWebhookDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.WebhookDelivery) (*database.WebhookDelivery, error) {
+				assert.Equal(t, "15", delivery.ReplayOf)
+				assert.Equal(t, "google-jwt", delivery.AuthUser)
+				delivery.ID = "16"
+				return &delivery, nil
+			})
+
+		databaseClient.
+			EXPECT().
+			UpdateWebhookDeliveryOutcome(gomock.Any(), "16", OutcomeProcessed, http.StatusOK, "").
+			Times(1)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+
+		var handledBody, handledAuthUser, handledAuthorization string
+		authenticate := func(c *gin.Context) {
+			if c.GetHeader("Authorization") == "Bearer abc" {
+				c.Set(gin.AuthUserKey, "google-jwt")
+			}
+		}
+		_ = handler.RecordAuthenticated("cloudsource", "", authenticate, func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			handledBody = string(body)
+			handledAuthUser = c.MustGet(gin.AuthUserKey).(string)
+			handledAuthorization = c.GetHeader("Authorization")
+			c.String(http.StatusOK, "Aye aye!")
+		})
+
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "user@ziplinee.io",
+			"roles":         []interface{}{"administrator"},
+		})
+		c.Params = append(c.Params, gin.Param{Key: "id", Value: "15"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/admin/webhooks/15/replay", nil)
+
+		// act
+		handler.ReplayDelivery(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `{"message":{}}`, handledBody)
+		assert.Equal(t, "google-jwt", handledAuthUser)
+		assert.Equal(t, "Bearer abc", handledAuthorization)
+		assert.Contains(t, recorder.Body.String(), `"id":"16"`)
+		assert.Contains(t, recorder.Body.String(), `"replayOf":"15"`)
+		assert.NotContains(t, recorder.Body.String(), "Bearer abc")
+	})
+
+	t.Run("RefusesToReplayDeliveryIfStoredCredentialsAreNoLongerValid", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetWebhookDeliveryByID(gomock.Any(), "15").
+			Return(&database.WebhookDelivery{
+				ID:       "15",
+				Source:   "cloudsource",
+				Headers:  http.Header{"Content-Type": []string{"application/json"}, "Authorization": []string{"Bearer expired"}},
+				Body:     []byte(`{"message":{}}`),
+				AuthUser: "google-jwt",
+				Outcome:  OutcomeFailed,
+			}, nil)
+
+		databaseClient.
+			EXPECT().
+			InsertWebhookDelivery(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+
+		handled := false
+		authenticate := func(c *gin.Context) {
+			if c.GetHeader("Authorization") == "Bearer abc" {
+				c.Set(gin.AuthUserKey, "google-jwt")
+			}
+		}
+		_ = handler.RecordAuthenticated("cloudsource", "", authenticate, func(c *gin.Context) {
+			handled = true
+			c.String(http.StatusOK, "Aye aye!")
+		})
+
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "user@ziplinee.io",
+			"roles":         []interface{}{"administrator"},
+		})
+		c.Params = append(c.Params, gin.Param{Key: "id", Value: "15"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/admin/webhooks/15/replay", nil)
+
+		// act
+		handler.ReplayDelivery(c)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.False(t, handled)
+	})
+
+	t.Run("ReturnsForbiddenWithoutReplayPermission", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		handler := NewHandler(&api.APIConfig{}, databaseClient)
+
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "user@ziplinee.io",
+		})
+		c.Params = append(c.Params, gin.Param{Key: "id", Value: "15"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/admin/webhooks/15/replay", nil)
+
+		// act
+		handler.ReplayDelivery(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
@@ -0,0 +1,228 @@
+package ziplinee
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	stdbigquery "cloud.google.com/go/bigquery"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/bigquery"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+// insertJobEvent exports a finished build, release or bot with its resource usage to BigQuery
+func (s *service) insertJobEvent(ctx context.Context, ciBuilderEvent contracts.ZiplineeCiBuilderEvent, jobResources database.JobResources) {
+
+	// skip retrieving the job and its pipeline if there's nowhere to export them to
+	if !s.bigqueryEnabled() || ciBuilderEvent.Git == nil {
+		return
+	}
+	repoSource, repoOwner, repoName := ciBuilderEvent.Git.RepoSource, ciBuilderEvent.Git.RepoOwner, ciBuilderEvent.Git.RepoName
+
+	jobResources = s.addRequestedJobResources(ctx, ciBuilderEvent, jobResources)
+
+	var err error
+	switch ciBuilderEvent.JobType {
+	case contracts.JobTypeBuild:
+		var build *contracts.Build
+		build, err = s.databaseClient.GetPipelineBuildByID(ctx, repoSource, repoOwner, repoName, ciBuilderEvent.Build.ID, false)
+		if err == nil && build != nil && isFinishedStatus(build.BuildStatus) {
+			err = s.bigqueryClient.InsertBuildEvent(ctx, newPipelineBuildEvent(*build, jobResources))
+		}
+
+	case contracts.JobTypeRelease:
+		var release *contracts.Release
+		release, err = s.databaseClient.GetPipelineRelease(ctx, repoSource, repoOwner, repoName, ciBuilderEvent.Release.ID)
+		if err == nil && release != nil && isFinishedStatus(release.ReleaseStatus) {
+			err = s.bigqueryClient.InsertReleaseEvent(ctx, newPipelineReleaseEvent(*release, s.getPipelineLabels(ctx, repoSource, repoOwner, repoName), jobResources))
+		}
+
+	case contracts.JobTypeBot:
+		var bot *contracts.Bot
+		bot, err = s.databaseClient.GetPipelineBot(ctx, repoSource, repoOwner, repoName, ciBuilderEvent.Bot.ID)
+		if err == nil && bot != nil && isFinishedStatus(bot.BotStatus) {
+			err = s.bigqueryClient.InsertBotEvent(ctx, newPipelineBotEvent(*bot, s.getPipelineLabels(ctx, repoSource, repoOwner, repoName), jobResources))
+		}
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed inserting %v event for job %v into BigQuery", ciBuilderEvent.JobType, ciBuilderEvent.JobName)
+	}
+}
+
+func (s *service) bigqueryEnabled() bool {
+	return s.config.Integrations != nil && s.config.Integrations.BigQuery != nil && s.config.Integrations.BigQuery.Enable
+}
+
+// addRequestedJobResources adds the cpu and memory the job requested to the maximum it used, since the builder event only carries the latter
+func (s *service) addRequestedJobResources(ctx context.Context, ciBuilderEvent contracts.ZiplineeCiBuilderEvent, jobResources database.JobResources) database.JobResources {
+
+	var jobID string
+	switch ciBuilderEvent.JobType {
+	case contracts.JobTypeBuild:
+		if ciBuilderEvent.Build != nil {
+			jobID = ciBuilderEvent.Build.ID
+		}
+	case contracts.JobTypeRelease:
+		if ciBuilderEvent.Release != nil {
+			jobID = ciBuilderEvent.Release.ID
+		}
+	case contracts.JobTypeBot:
+		if ciBuilderEvent.Bot != nil {
+			jobID = ciBuilderEvent.Bot.ID
+		}
+	}
+	if jobID == "" {
+		return jobResources
+	}
+
+	requestedResources, err := s.databaseClient.GetJobResources(ctx, ciBuilderEvent.JobType, ciBuilderEvent.Git.RepoSource, ciBuilderEvent.Git.RepoOwner, ciBuilderEvent.Git.RepoName, jobID)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed retrieving requested resources for %v %v, exporting the event without them", ciBuilderEvent.JobType, jobID)
+		return jobResources
+	}
+
+	jobResources.CPURequest = requestedResources.CPURequest
+	jobResources.CPULimit = requestedResources.CPULimit
+	jobResources.MemoryRequest = requestedResources.MemoryRequest
+	jobResources.MemoryLimit = requestedResources.MemoryLimit
+
+	return jobResources
+}
+
+// getPipelineLabels returns the labels of a pipeline, since releases and bots don't carry them themselves
+func (s *service) getPipelineLabels(ctx context.Context, repoSource, repoOwner, repoName string) []contracts.Label {
+	pipeline, err := s.databaseClient.GetPipeline(ctx, repoSource, repoOwner, repoName, map[api.FilterType][]string{}, true)
+	if err != nil || pipeline == nil {
+		return nil
+	}
+
+	return pipeline.Labels
+}
+
+func isFinishedStatus(status contracts.Status) bool {
+	return status == contracts.StatusSucceeded || status == contracts.StatusFailed || status == contracts.StatusCanceled
+}
+
+func newPipelineBuildEvent(build contracts.Build, jobResources database.JobResources) bigquery.PipelineBuildEvent {
+	buildID, _ := strconv.Atoi(build.ID)
+
+	event := bigquery.PipelineBuildEvent{
+		BuildID:        buildID,
+		RepoSource:     build.RepoSource,
+		RepoOwner:      build.RepoOwner,
+		RepoName:       build.RepoName,
+		RepoBranch:     build.RepoBranch,
+		RepoRevision:   build.RepoRevision,
+		BuildVersion:   build.BuildVersion,
+		BuildStatus:    string(build.BuildStatus),
+		Labels:         toBigqueryLabels(build.Labels),
+		InsertedAt:     build.InsertedAt,
+		UpdatedAt:      build.UpdatedAt,
+		CPURequest:     toNullFloat64(jobResources.CPURequest),
+		CPULimit:       toNullFloat64(jobResources.CPULimit),
+		CPUMaxUsage:    toNullFloat64(jobResources.CPUMaxUsage),
+		MemoryRequest:  toNullFloat64(jobResources.MemoryRequest),
+		MemoryLimit:    toNullFloat64(jobResources.MemoryLimit),
+		MemoryMaxUsage: toNullFloat64(jobResources.MemoryMaxUsage),
+		Manifest:       build.Manifest,
+	}
+
+	for _, c := range build.Commits {
+		commit := bigquery.Commit{Message: c.Message}
+		commit.Author.Email = c.Author.Email
+		event.Commits = append(event.Commits, commit)
+	}
+
+	event.TotalDuration, event.TimeToRunning = getDurations(&build.Duration, build.PendingDuration)
+
+	return event
+}
+
+func newPipelineReleaseEvent(release contracts.Release, labels []contracts.Label, jobResources database.JobResources) bigquery.PipelineReleaseEvent {
+	releaseID, _ := strconv.Atoi(release.ID)
+
+	event := bigquery.PipelineReleaseEvent{
+		ReleaseID:      releaseID,
+		RepoSource:     release.RepoSource,
+		RepoOwner:      release.RepoOwner,
+		RepoName:       release.RepoName,
+		ReleaseTarget:  release.Name,
+		ReleaseVersion: release.ReleaseVersion,
+		ReleaseStatus:  string(release.ReleaseStatus),
+		Labels:         toBigqueryLabels(labels),
+		CPURequest:     toNullFloat64(jobResources.CPURequest),
+		CPULimit:       toNullFloat64(jobResources.CPULimit),
+		CPUMaxUsage:    toNullFloat64(jobResources.CPUMaxUsage),
+		MemoryRequest:  toNullFloat64(jobResources.MemoryRequest),
+		MemoryLimit:    toNullFloat64(jobResources.MemoryLimit),
+		MemoryMaxUsage: toNullFloat64(jobResources.MemoryMaxUsage),
+	}
+	if release.InsertedAt != nil {
+		event.InsertedAt = *release.InsertedAt
+	}
+	if release.UpdatedAt != nil {
+		event.UpdatedAt = *release.UpdatedAt
+	}
+
+	event.TotalDuration, event.TimeToRunning = getDurations(release.Duration, release.PendingDuration)
+
+	return event
+}
+
+func newPipelineBotEvent(bot contracts.Bot, labels []contracts.Label, jobResources database.JobResources) bigquery.PipelineBotEvent {
+	botID, _ := strconv.Atoi(bot.ID)
+
+	event := bigquery.PipelineBotEvent{
+		BotID:          botID,
+		RepoSource:     bot.RepoSource,
+		RepoOwner:      bot.RepoOwner,
+		RepoName:       bot.RepoName,
+		BotName:        bot.Name,
+		BotStatus:      string(bot.BotStatus),
+		Labels:         toBigqueryLabels(labels),
+		CPURequest:     toNullFloat64(jobResources.CPURequest),
+		CPULimit:       toNullFloat64(jobResources.CPULimit),
+		CPUMaxUsage:    toNullFloat64(jobResources.CPUMaxUsage),
+		MemoryRequest:  toNullFloat64(jobResources.MemoryRequest),
+		MemoryLimit:    toNullFloat64(jobResources.MemoryLimit),
+		MemoryMaxUsage: toNullFloat64(jobResources.MemoryMaxUsage),
+	}
+	if bot.InsertedAt != nil {
+		event.InsertedAt = *bot.InsertedAt
+	}
+	if bot.UpdatedAt != nil {
+		event.UpdatedAt = *bot.UpdatedAt
+	}
+
+	event.TotalDuration, event.TimeToRunning = getDurations(bot.Duration, bot.PendingDuration)
+
+	return event
+}
+
+// getDurations returns the total duration from insertion until the last update and the time it took to start running
+func getDurations(runningDuration, pendingDuration *time.Duration) (totalDuration, timeToRunning time.Duration) {
+	if pendingDuration != nil {
+		timeToRunning = *pendingDuration
+	}
+	if runningDuration != nil {
+		totalDuration = timeToRunning + *runningDuration
+	}
+
+	return
+}
+
+func toBigqueryLabels(labels []contracts.Label) (bigqueryLabels []bigquery.Label) {
+	for _, l := range labels {
+		bigqueryLabels = append(bigqueryLabels, bigquery.Label{Key: l.Key, Value: l.Value})
+	}
+
+	return
+}
+
+func toNullFloat64(value float64) stdbigquery.NullFloat64 {
+	return stdbigquery.NullFloat64{Float64: value, Valid: value > 0}
+}
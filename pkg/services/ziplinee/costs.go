@@ -0,0 +1,256 @@
+package ziplinee
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+const (
+	// bytesPerGB is the size of the GB that memory is priced by
+	bytesPerGB = 1 << 30
+
+	// costKeyUnattributed is the key of the spend on jobs without organization, group or the label a report is aggregated by
+	costKeyUnattributed = "unattributed"
+)
+
+var (
+	// ErrCostsNotConfigured indicates the api config has no cost model to price jobs by
+	ErrCostsNotConfigured = errors.New("The cost model is not configured")
+
+	// ErrInvalidCostDimension indicates a cost report can't be aggregated by a dimension
+	ErrInvalidCostDimension = errors.New("The cost dimension is invalid")
+)
+
+// GetCostReport prices the resources requested by the builds, releases and bots inserted between from and to and aggregates their
+// spend and waste by a dimension; only jobs of the organizations and groups in the filters are included
+func (s *service) GetCostReport(ctx context.Context, dimension CostDimension, labelKey string, from, to time.Time, filters map[api.FilterType][]string) (report *CostReport, err error) {
+
+	if s.config.Costs == nil || len(s.config.Costs.Prices) == 0 {
+		return nil, ErrCostsNotConfigured
+	}
+	if !dimension.isValid() || (dimension == CostDimensionLabel && labelKey == "") {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCostDimension, dimension)
+	}
+
+	report = &CostReport{
+		Dimension: dimension,
+		Currency:  s.config.Costs.Currency,
+		From:      from,
+		To:        to,
+		Items:     []*CostReportItem{},
+		Total:     &CostReportItem{Key: "total"},
+	}
+	if dimension == CostDimensionLabel {
+		report.LabelKey = labelKey
+	}
+
+	items := map[string]*CostReportItem{}
+
+	for _, jobType := range []contracts.JobType{contracts.JobTypeBuild, contracts.JobTypeRelease, contracts.JobTypeBot} {
+		price := s.config.Costs.GetPrice(jobType)
+		if price == nil {
+			log.Debug().Msgf("No price configured for %v jobs, leaving them out of the cost report", jobType)
+			continue
+		}
+
+		utilizations, err := s.databaseClient.GetJobResourceUtilizations(ctx, jobType, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range utilizations {
+			if !isCostVisible(u, filters) {
+				continue
+			}
+
+			jobCost := getJobCost(u, price)
+			report.Total.add(jobCost, 1)
+
+			// split the spend of a job evenly over all organizations or groups it belongs to, so the items add up to the total
+			keys := getCostKeys(u, dimension, labelKey)
+			for _, key := range keys {
+				item, ok := items[key]
+				if !ok {
+					item = &CostReportItem{Key: key}
+					items[key] = item
+					report.Items = append(report.Items, item)
+				}
+				item.add(jobCost, 1/float64(len(keys)))
+			}
+		}
+	}
+
+	// months are listed chronologically, everything else by what it costs
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		if dimension == CostDimensionMonth {
+			return report.Items[i].Key < report.Items[j].Key
+		}
+		return report.Items[i].Cost > report.Items[j].Cost
+	})
+
+	return report, nil
+}
+
+// getJobCost prices the cpu and memory a job requested for the duration of its run; the difference with the maximum it used is waste
+func getJobCost(utilization *database.JobResourceUtilization, price *api.CostsPriceConfig) CostReportItem {
+
+	hours := utilization.Duration.Hours()
+
+	jobCost := CostReportItem{
+		CoreHours: utilization.CPURequest * hours,
+		GBHours:   utilization.MemoryRequest / bytesPerGB * hours,
+	}
+
+	// without measured usage there's no telling what was wasted
+	if utilization.Measured {
+		jobCost.WastedCoreHours = math.Max(utilization.CPURequest-utilization.CPUMaxUsage, 0) * hours
+		jobCost.WastedGBHours = math.Max(utilization.MemoryRequest-utilization.MemoryMaxUsage, 0) / bytesPerGB * hours
+	}
+
+	jobCost.Cost = jobCost.CoreHours*price.PricePerCoreHour + jobCost.GBHours*price.PricePerGBHour
+	jobCost.WastedCost = jobCost.WastedCoreHours*price.PricePerCoreHour + jobCost.WastedGBHours*price.PricePerGBHour
+
+	return jobCost
+}
+
+// isCostVisible returns true if a job belongs to one of the organizations and one of the groups the report is filtered on, the same
+// way pipelines are filtered for the request
+func isCostVisible(utilization *database.JobResourceUtilization, filters map[api.FilterType][]string) bool {
+
+	if organizations := filters[api.FilterOrganizations]; len(organizations) > 0 {
+		isVisible := false
+		for _, o := range utilization.Organizations {
+			if o != nil && api.StringArrayContains(organizations, o.Name) {
+				isVisible = true
+				break
+			}
+		}
+		if !isVisible {
+			return false
+		}
+	}
+
+	if groups := filters[api.FilterGroups]; len(groups) > 0 {
+		isVisible := false
+		for _, g := range utilization.Groups {
+			if g != nil && api.StringArrayContains(groups, g.Name) {
+				isVisible = true
+				break
+			}
+		}
+		if !isVisible {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getCostKeys returns the keys of the report items the spend of a job is attributed to
+func getCostKeys(utilization *database.JobResourceUtilization, dimension CostDimension, labelKey string) (keys []string) {
+
+	switch dimension {
+	case CostDimensionOrganization:
+		for _, o := range utilization.Organizations {
+			if o != nil && o.Name != "" {
+				keys = append(keys, o.Name)
+			}
+		}
+	case CostDimensionGroup:
+		for _, g := range utilization.Groups {
+			if g != nil && g.Name != "" {
+				keys = append(keys, g.Name)
+			}
+		}
+	case CostDimensionPipeline:
+		keys = append(keys, fmt.Sprintf("%v/%v/%v", utilization.RepoSource, utilization.RepoOwner, utilization.RepoName))
+	case CostDimensionLabel:
+		for _, l := range utilization.Labels {
+			if l.Key == labelKey && l.Value != "" {
+				keys = append(keys, l.Value)
+				break
+			}
+		}
+	case CostDimensionMonth:
+		keys = append(keys, utilization.InsertedAt.UTC().Format("2006-01"))
+	}
+
+	if len(keys) == 0 {
+		keys = append(keys, costKeyUnattributed)
+	}
+
+	return keys
+}
+
+func (d CostDimension) isValid() bool {
+	switch d {
+	case CostDimensionOrganization, CostDimensionGroup, CostDimensionPipeline, CostDimensionLabel, CostDimensionMonth:
+		return true
+	}
+
+	return false
+}
+
+// add adds a share of the spend of a job to an item
+func (i *CostReportItem) add(jobCost CostReportItem, share float64) {
+	i.NrJobs++
+	i.CoreHours += jobCost.CoreHours * share
+	i.GBHours += jobCost.GBHours * share
+	i.WastedCoreHours += jobCost.WastedCoreHours * share
+	i.WastedGBHours += jobCost.WastedGBHours * share
+	i.Cost += jobCost.Cost * share
+	i.WastedCost += jobCost.WastedCost * share
+}
+
+// writeCostReportCSV writes a cost report as csv, one row per item followed by the total
+func writeCostReportCSV(w io.Writer, report *CostReport) error {
+
+	writer := csv.NewWriter(w)
+
+	keyHeader := string(report.Dimension)
+	if report.Dimension == CostDimensionLabel {
+		keyHeader = report.LabelKey
+	}
+
+	err := writer.Write([]string{keyHeader, "jobs", "core hours", "GB hours", "wasted core hours", "wasted GB hours", "cost", "wasted cost", "currency"})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range append(report.Items, report.Total) {
+		err = writer.Write([]string{
+			item.Key,
+			strconv.Itoa(item.NrJobs),
+			formatCostValue(item.CoreHours),
+			formatCostValue(item.GBHours),
+			formatCostValue(item.WastedCoreHours),
+			formatCostValue(item.WastedGBHours),
+			formatCostValue(item.Cost),
+			formatCostValue(item.WastedCost),
+			report.Currency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func formatCostValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
@@ -0,0 +1,251 @@
+package ziplinee
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+// buildResponse adds details about the build status that aren't part of contracts.Build
+type buildResponse struct {
+	*contracts.Build
+	QueuePosition int    `json:"queuePosition,omitempty"`
+	StatusReason  string `json:"statusReason,omitempty"`
+}
+
+// releaseResponse adds details about the release status that aren't part of contracts.Release
+type releaseResponse struct {
+	*contracts.Release
+	QueuePosition int `json:"queuePosition,omitempty"`
+}
+
+// botResponse adds details about the bot status that aren't part of contracts.Bot
+type botResponse struct {
+	*contracts.Bot
+	QueuePosition int `json:"queuePosition,omitempty"`
+}
+
+// migrationRequest queues the move of a pipeline to another repository
+type migrationRequest struct {
+	ID          string `json:"id"`
+	FromSource  string `json:"fromSource" binding:"required"`
+	FromOwner   string `json:"fromOwner" binding:"required"`
+	FromName    string `json:"fromName" binding:"required"`
+	ToSource    string `json:"toSource" binding:"required"`
+	ToOwner     string `json:"toOwner" binding:"required"`
+	ToName      string `json:"toName" binding:"required"`
+	CallbackURL string `json:"callbackURL"`
+	Restart     string `json:"restart"`
+}
+
+type logReconciliationRequest struct {
+	DryRun     bool   `json:"dryRun"`
+	RepoSource string `json:"repoSource"`
+	RepoOwner  string `json:"repoOwner"`
+	RepoName   string `json:"repoName"`
+}
+
+// LogSearchQuery selects the log lines to find in the most recent builds, releases and bots of a pipeline
+type LogSearchQuery struct {
+	// Text is matched case-insensitively against each log line, or as a regular expression if Regex is set
+	Text        string
+	Regex       bool
+	Last        int
+	JobTypes    []contracts.JobType
+	Steps       []string
+	StreamTypes []string
+	Since       *time.Time
+	Until       *time.Time
+	Limit       int
+}
+
+// LogSearchMatch is a log line matching a LogSearchQuery, with a link to the job it was logged by
+type LogSearchMatch struct {
+	JobType       contracts.JobType `json:"jobType"`
+	JobID         string            `json:"jobID"`
+	JobName       string            `json:"jobName,omitempty"`
+	JobVersion    string            `json:"jobVersion,omitempty"`
+	JobStatus     contracts.Status  `json:"jobStatus,omitempty"`
+	JobLink       string            `json:"jobLink"`
+	JobInsertedAt time.Time         `json:"jobInsertedAt"`
+	Step          string            `json:"step"`
+	RunIndex      int               `json:"runIndex,omitempty"`
+	LineNumber    int               `json:"line"`
+	Timestamp     time.Time         `json:"timestamp"`
+	StreamType    string            `json:"streamType"`
+	Text          string            `json:"text"`
+}
+
+// LogSearchResult holds the matches of a LogSearchQuery, oldest first
+type LogSearchResult struct {
+	Matches      []*LogSearchMatch `json:"matches"`
+	SearchedJobs int               `json:"searchedJobs"`
+	Truncated    bool              `json:"truncated"`
+}
+
+// LogRetentionReport sums up the logs pruned, or to be pruned in a dry run, for being past their retention
+type LogRetentionReport struct {
+	DryRun    bool                      `json:"dryRun"`
+	Pipelines int                       `json:"pipelines"`
+	Logs      int                       `json:"logs"`
+	Bytes     int64                     `json:"bytes,omitempty"`
+	Items     []*LogRetentionReportItem `json:"items"`
+}
+
+// LogRetentionReportItem holds the number of logs pruned for a single pipeline, job type and log target
+type LogRetentionReportItem struct {
+	RepoSource    string            `json:"repoSource"`
+	RepoOwner     string            `json:"repoOwner"`
+	RepoName      string            `json:"repoName"`
+	JobType       contracts.JobType `json:"jobType"`
+	LogTarget     api.LogTarget     `json:"logTarget"`
+	RetentionDays int               `json:"retentionDays"`
+	Logs          int               `json:"logs"`
+	Bytes         int64             `json:"bytes,omitempty"`
+}
+
+func (r *LogRetentionReport) add(items ...*LogRetentionReportItem) {
+	for _, i := range items {
+		r.Items = append(r.Items, i)
+		r.Logs += i.Logs
+		r.Bytes += i.Bytes
+	}
+}
+
+// LogReconciliationResult holds the outcome of comparing the log records of a pipeline in the database with its log files in cloud storage
+type LogReconciliationResult struct {
+	Checked       int `json:"checked"`
+	Copied        int `json:"copied"`
+	Unrecoverable int `json:"unrecoverable"`
+	Orphaned      int `json:"orphaned"`
+}
+
+// NotificationEvent is the payload delivered to notification channels for a status change of a build, release or bot, or for a stored notification
+type NotificationEvent struct {
+	Event         api.NotificationEventType     `json:"event"`
+	Status        contracts.Status              `json:"status,omitempty"`
+	RepoSource    string                        `json:"repoSource,omitempty"`
+	RepoOwner     string                        `json:"repoOwner,omitempty"`
+	RepoName      string                        `json:"repoName,omitempty"`
+	RepoBranch    string                        `json:"repoBranch,omitempty"`
+	RepoRevision  string                        `json:"repoRevision,omitempty"`
+	ID            string                        `json:"id,omitempty"`
+	Name          string                        `json:"name,omitempty"`
+	Action        string                        `json:"action,omitempty"`
+	Version       string                        `json:"version,omitempty"`
+	Labels        []contracts.Label             `json:"labels,omitempty"`
+	Organizations []string                      `json:"organizations,omitempty"`
+	Link          string                        `json:"link,omitempty"`
+	Notification  *contracts.NotificationRecord `json:"notification,omitempty"`
+	Time          time.Time                     `json:"time"`
+}
+
+const (
+	auditTargetTypeBuild   = "build"
+	auditTargetTypeRelease = "release"
+	auditTargetTypeBot     = "bot"
+
+	auditActionBuildCreated    = "build.created"
+	auditActionBuildCanceled   = "build.canceled"
+	auditActionReleaseCreated  = "release.created"
+	auditActionReleaseCanceled = "release.canceled"
+	auditActionBotCreated      = "bot.created"
+	auditActionBotCanceled     = "bot.canceled"
+)
+
+// jobAuditState is the state of a build, release or bot as recorded in the audit log
+type jobAuditState struct {
+	Status  contracts.Status `json:"status"`
+	Version string           `json:"version,omitempty"`
+	Name    string           `json:"name,omitempty"`
+	Action  string           `json:"action,omitempty"`
+}
+
+// getJobAuditTargetID returns the id of a build, release or bot prefixed by its pipeline, since ids are only unique per pipeline for older jobs
+func getJobAuditTargetID(source, owner, repo, id string) string {
+	return fmt.Sprintf("%v/%v/%v/%v", source, owner, repo, id)
+}
+
+// JobResourcesExplanation shows which resources the next job of a pipeline gets and why
+type JobResourcesExplanation struct {
+	JobType       contracts.JobType `json:"jobType"`
+	History       string            `json:"history,omitempty"`
+	NrRecords     int               `json:"nrRecords"`
+	CPURequest    float64           `json:"cpuRequest"`
+	CPULimit      float64           `json:"cpuLimit"`
+	MemoryRequest float64           `json:"memoryRequest"`
+	MemoryLimit   float64           `json:"memoryLimit"`
+	Reasons       []string          `json:"reasons"`
+}
+
+func (e *JobResourcesExplanation) addReason(reason string) {
+	e.Reasons = append(e.Reasons, reason)
+}
+
+func (e *JobResourcesExplanation) getJobResources() database.JobResources {
+	return database.JobResources{
+		CPURequest:    e.CPURequest,
+		CPULimit:      e.CPULimit,
+		MemoryRequest: e.MemoryRequest,
+		MemoryLimit:   e.MemoryLimit,
+	}
+}
+
+// CostDimension is what the spend of a cost report is aggregated by
+type CostDimension string
+
+const (
+	CostDimensionOrganization CostDimension = "organizations"
+	CostDimensionGroup        CostDimension = "groups"
+	CostDimensionPipeline     CostDimension = "pipelines"
+	CostDimensionLabel        CostDimension = "labels"
+	CostDimensionMonth        CostDimension = "months"
+)
+
+// CostReport is the spend on ci jobs inserted between from and to, aggregated by a dimension; for labels the spend is aggregated
+// by the values of the label with key LabelKey
+type CostReport struct {
+	Dimension CostDimension     `json:"dimension"`
+	LabelKey  string            `json:"labelKey,omitempty"`
+	Currency  string            `json:"currency"`
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Items     []*CostReportItem `json:"items"`
+	Total     *CostReportItem   `json:"total"`
+}
+
+// CostReportItem is the spend attributed to a single organization, group, pipeline, label value or month; waste is what jobs
+// requested but didn't use
+type CostReportItem struct {
+	Key             string  `json:"key"`
+	NrJobs          int     `json:"nrJobs"`
+	CoreHours       float64 `json:"coreHours"`
+	GBHours         float64 `json:"gbHours"`
+	WastedCoreHours float64 `json:"wastedCoreHours"`
+	WastedGBHours   float64 `json:"wastedGBHours"`
+	Cost            float64 `json:"cost"`
+	WastedCost      float64 `json:"wastedCost"`
+}
+
+// DoraMetrics is the delivery performance of the releases inserted between From and To, per release target
+type DoraMetrics struct {
+	From    time.Time            `json:"from"`
+	To      time.Time            `json:"to"`
+	Targets []*DoraTargetMetrics `json:"targets"`
+}
+
+// DoraTargetMetrics holds deployment frequency, lead time for changes, change failure rate and time to restore of a release target;
+// lead time and time to restore are medians and left out when there's nothing to measure them on
+type DoraTargetMetrics struct {
+	Target               string         `json:"target"`
+	NrDeployments        int            `json:"nrDeployments"`
+	NrFailedDeployments  int            `json:"nrFailedDeployments"`
+	DeploymentsPerDay    float64        `json:"deploymentsPerDay"`
+	LeadTime             *time.Duration `json:"leadTime,omitempty"`
+	ChangeFailureRate    float64        `json:"changeFailureRate"`
+	TimeToRestore        *time.Duration `json:"timeToRestore,omitempty"`
+	NrUnrestoredFailures int            `json:"nrUnrestoredFailures"`
+}
@@ -0,0 +1,108 @@
+package ziplinee
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+// GetDoraMetrics measures deployment frequency, lead time for changes, change failure rate and time to restore per release target
+// from the releases inserted between from and to, for a single pipeline or for all pipelines if repoSource, repoOwner and repoName
+// are empty.
+//
+// Commits carry no timestamp, so lead time runs from the creation of the build of the released version - which is triggered by
+// pushing its commits - to the end of the succeeded release. Time to restore runs from the end of the first failed release of a
+// pipeline to a target to the end of the next succeeded one.
+func (s *service) GetDoraMetrics(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (metrics *DoraMetrics, err error) {
+
+	outcomes, err := s.databaseClient.GetReleaseOutcomes(ctx, repoSource, repoOwner, repoName, from, to, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics = &DoraMetrics{
+		From:    from,
+		To:      to,
+		Targets: []*DoraTargetMetrics{},
+	}
+
+	targets := map[string]*DoraTargetMetrics{}
+	leadTimes := map[string][]float64{}
+	restoreTimes := map[string][]float64{}
+
+	// keeps the end of the first failed release per pipeline and target that hasn't been followed by a succeeded one yet
+	type pipelineTarget struct {
+		pipeline, target string
+	}
+	failingSince := map[pipelineTarget]time.Time{}
+
+	// outcomes are ordered by insertion time, so a failure is restored by the first succeeded release after it
+	for _, o := range outcomes {
+		target, ok := targets[o.Name]
+		if !ok {
+			target = &DoraTargetMetrics{Target: o.Name}
+			targets[o.Name] = target
+			metrics.Targets = append(metrics.Targets, target)
+		}
+
+		key := pipelineTarget{fmt.Sprintf("%v/%v/%v", o.RepoSource, o.RepoOwner, o.RepoName), o.Name}
+
+		switch o.ReleaseStatus {
+		case contracts.StatusSucceeded:
+			target.NrDeployments++
+
+			if o.BuildInsertedAt != nil && o.FinishedAt.After(*o.BuildInsertedAt) {
+				leadTimes[o.Name] = append(leadTimes[o.Name], o.FinishedAt.Sub(*o.BuildInsertedAt).Seconds())
+			}
+			if since, ok := failingSince[key]; ok {
+				restoreTimes[o.Name] = append(restoreTimes[o.Name], o.FinishedAt.Sub(since).Seconds())
+				delete(failingSince, key)
+			}
+
+		case contracts.StatusFailed:
+			target.NrFailedDeployments++
+
+			if _, ok := failingSince[key]; !ok {
+				failingSince[key] = o.FinishedAt
+			}
+		}
+	}
+
+	for key := range failingSince {
+		targets[key.target].NrUnrestoredFailures++
+	}
+
+	days := to.Sub(from).Hours() / 24
+
+	for _, target := range metrics.Targets {
+		if days > 0 {
+			target.DeploymentsPerDay = float64(target.NrDeployments) / days
+		}
+		if target.NrDeployments+target.NrFailedDeployments > 0 {
+			target.ChangeFailureRate = float64(target.NrFailedDeployments) / float64(target.NrDeployments+target.NrFailedDeployments)
+		}
+		target.LeadTime = getMedianDuration(leadTimes[target.Target])
+		target.TimeToRestore = getMedianDuration(restoreTimes[target.Target])
+	}
+
+	sort.Slice(metrics.Targets, func(i, j int) bool {
+		return metrics.Targets[i].Target < metrics.Targets[j].Target
+	})
+
+	return metrics, nil
+}
+
+// getMedianDuration returns the median of durations in seconds, or nil if there are none
+func getMedianDuration(seconds []float64) *time.Duration {
+	if len(seconds) == 0 {
+		return nil
+	}
+
+	median := time.Duration(getPercentile(seconds, 50) * float64(time.Second))
+
+	return &median
+}
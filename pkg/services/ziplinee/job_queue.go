@@ -0,0 +1,75 @@
+package ziplinee
+
+import (
+	"fmt"
+
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+// jobConcurrencyCounter keeps track of the number of active jobs in order to check the job queue concurrency limits
+type jobConcurrencyCounter struct {
+	config          api.JobQueueConfig
+	total           int
+	perOrganization map[string]int
+	perPipeline     map[string]int
+	perJobType      map[contracts.JobType]int
+}
+
+func newJobConcurrencyCounter(config api.JobQueueConfig, activeJobs []*database.ActiveJob) *jobConcurrencyCounter {
+	counter := &jobConcurrencyCounter{
+		config:          config,
+		perOrganization: map[string]int{},
+		perPipeline:     map[string]int{},
+		perJobType:      map[contracts.JobType]int{},
+	}
+
+	for _, aj := range activeJobs {
+		counter.add(aj.JobType, aj.RepoSource, aj.RepoOwner, aj.RepoName, aj.Organizations)
+	}
+
+	return counter
+}
+
+func (c *jobConcurrencyCounter) add(jobType contracts.JobType, repoSource, repoOwner, repoName string, organizations []*contracts.Organization) {
+	c.total++
+	c.perJobType[jobType]++
+	c.perPipeline[pipelineKey(repoSource, repoOwner, repoName)]++
+	for _, o := range organizations {
+		c.perOrganization[o.Name]++
+	}
+}
+
+func (c *jobConcurrencyCounter) globalLimitReached() bool {
+	return c.config.MaxConcurrentJobs > 0 && c.total >= c.config.MaxConcurrentJobs
+}
+
+// allows returns true if one more job of this type and for this pipeline stays within all limits
+func (c *jobConcurrencyCounter) allows(jobType contracts.JobType, repoSource, repoOwner, repoName string, organizations []*contracts.Organization) bool {
+	if c.globalLimitReached() {
+		return false
+	}
+
+	if limit := c.config.MaxConcurrentJobsForJobType(jobType); limit > 0 && c.perJobType[jobType] >= limit {
+		return false
+	}
+
+	if limit := c.config.MaxConcurrentJobsPerPipeline; limit > 0 && c.perPipeline[pipelineKey(repoSource, repoOwner, repoName)] >= limit {
+		return false
+	}
+
+	if limit := c.config.MaxConcurrentJobsPerOrganization; limit > 0 {
+		for _, o := range organizations {
+			if c.perOrganization[o.Name] >= limit {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func pipelineKey(repoSource, repoOwner, repoName string) string {
+	return fmt.Sprintf("%v/%v/%v", repoSource, repoOwner, repoName)
+}
@@ -2,8 +2,10 @@ package ziplinee
 
 import (
 	"context"
+	"time"
 
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 	manifest "github.com/ziplineeci/ziplinee-ci-manifest"
 )
@@ -131,3 +133,105 @@ func (s *loggingService) GetEventsForJobEnvvars(ctx context.Context, triggers []
 
 	return s.Service.GetEventsForJobEnvvars(ctx, triggers, events)
 }
+
+func (s *loggingService) DispatchQueuedJobs(ctx context.Context) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "DispatchQueuedJobs", err) }()
+
+	return s.Service.DispatchQueuedJobs(ctx)
+}
+
+func (s *loggingService) QueueMigration(ctx context.Context, task database.MigrationTask, restart string) (queuedTask *database.MigrationTask, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "QueueMigration", err) }()
+
+	return s.Service.QueueMigration(ctx, task, restart)
+}
+
+func (s *loggingService) RunMigrationTasks(ctx context.Context) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "RunMigrationTasks", err) }()
+
+	return s.Service.RunMigrationTasks(ctx)
+}
+
+func (s *loggingService) RollbackMigration(ctx context.Context, task database.MigrationTask) (rollback *database.MigrationRollback, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "RollbackMigration", err) }()
+
+	return s.Service.RollbackMigration(ctx, task)
+}
+
+func (s *loggingService) SearchPipelineLogs(ctx context.Context, repoSource, repoOwner, repoName string, query LogSearchQuery) (result *LogSearchResult, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "SearchPipelineLogs", err) }()
+
+	return s.Service.SearchPipelineLogs(ctx, repoSource, repoOwner, repoName, query)
+}
+
+func (s *loggingService) PruneExpiredLogs(ctx context.Context, dryRun bool) (report *LogRetentionReport, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "PruneExpiredLogs", err) }()
+
+	return s.Service.PruneExpiredLogs(ctx, dryRun)
+}
+
+func (s *loggingService) PrunePipelineExpiredLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (items []*LogRetentionReportItem, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "PrunePipelineExpiredLogs", err) }()
+
+	return s.Service.PrunePipelineExpiredLogs(ctx, pipeline, dryRun)
+}
+
+func (s *loggingService) QueueLogReconciliation(ctx context.Context, reconciliation database.LogReconciliation) (queuedReconciliation *database.LogReconciliation, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "QueueLogReconciliation", err) }()
+
+	return s.Service.QueueLogReconciliation(ctx, reconciliation)
+}
+
+func (s *loggingService) RunLogReconciliations(ctx context.Context) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "RunLogReconciliations", err) }()
+
+	return s.Service.RunLogReconciliations(ctx)
+}
+
+func (s *loggingService) ReconcilePipelineLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (result *LogReconciliationResult, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "ReconcilePipelineLogs", err) }()
+
+	return s.Service.ReconcilePipelineLogs(ctx, pipeline, dryRun)
+}
+
+func (s *loggingService) DispatchJobStatus(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "DispatchJobStatus", err) }()
+
+	return s.Service.DispatchJobStatus(ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
+func (s *loggingService) DispatchNotification(ctx context.Context, notification contracts.NotificationRecord) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "DispatchNotification", err) }()
+
+	return s.Service.DispatchNotification(ctx, notification)
+}
+
+func (s *loggingService) DeliverNotifications(ctx context.Context) (err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "DeliverNotifications", err) }()
+
+	return s.Service.DeliverNotifications(ctx)
+}
+
+func (s *loggingService) CancelJob(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (canceledStatus contracts.Status, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "CancelJob", err) }()
+
+	return s.Service.CancelJob(ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
+func (s *loggingService) ExplainJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, name, action string) (explanation *JobResourcesExplanation, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "ExplainJobResources", err) }()
+
+	return s.Service.ExplainJobResources(ctx, jobType, repoSource, repoOwner, repoName, name, action)
+}
+
+func (s *loggingService) GetCostReport(ctx context.Context, dimension CostDimension, labelKey string, from, to time.Time, filters map[api.FilterType][]string) (report *CostReport, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "GetCostReport", err) }()
+
+	return s.Service.GetCostReport(ctx, dimension, labelKey, from, to, filters)
+}
+
+func (s *loggingService) GetDoraMetrics(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (metrics *DoraMetrics, err error) {
+	defer func() { api.HandleLogError(s.prefix, "Service", "GetDoraMetrics", err) }()
+
+	return s.Service.GetDoraMetrics(ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
@@ -0,0 +1,217 @@
+package ziplinee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+const (
+	// logReconciliationPipelinesPageSize is the number of pipelines retrieved at once when reconciling logs for all pipelines
+	logReconciliationPipelinesPageSize = 100
+
+	// logReconciliationStaleAfter is the time after which a reconciliation that stopped reporting progress is picked up again
+	logReconciliationStaleAfter = 15 * time.Minute
+)
+
+var (
+	ErrLogReconciliationNotEnabled       = errors.New("Log reconciliation is not enabled or logs are not written to object storage")
+	ErrLogReconciliationInProgress       = errors.New("A log reconciliation is already queued or in progress")
+	ErrLogReconciliationPipelineNotFound = errors.New("The pipeline to reconcile logs for can't be found")
+)
+
+func (s *service) QueueLogReconciliation(ctx context.Context, reconciliation database.LogReconciliation) (queuedReconciliation *database.LogReconciliation, err error) {
+
+	if s.config.LogReconciliation == nil || !s.config.LogReconciliation.Enable || !s.config.APIServer.WriteLogToObjectStorage() {
+		return nil, ErrLogReconciliationNotEnabled
+	}
+
+	if reconciliation.RepoSource != "" {
+		pipeline, err := s.databaseClient.GetPipeline(ctx, reconciliation.RepoSource, reconciliation.RepoOwner, reconciliation.RepoName, map[api.FilterType][]string{}, true)
+		if err != nil {
+			return nil, err
+		}
+		if pipeline == nil {
+			return nil, ErrLogReconciliationPipelineNotFound
+		}
+	}
+
+	_, err = s.databaseClient.GetActiveLogReconciliation(ctx)
+	if err == nil {
+		return nil, ErrLogReconciliationInProgress
+	}
+	if !errors.Is(err, database.ErrLogReconciliationNotFound) {
+		return nil, err
+	}
+
+	return s.databaseClient.QueueLogReconciliation(ctx, reconciliation)
+}
+
+func (s *service) RunLogReconciliations(ctx context.Context) (err error) {
+
+	reconciliation, err := s.databaseClient.PickLogReconciliation(ctx, time.Now().UTC().Add(-logReconciliationStaleAfter))
+	if err != nil {
+		return err
+	}
+	if reconciliation == nil {
+		return nil
+	}
+
+	start := time.Now().UTC()
+	log.Info().Msgf("Starting log reconciliation %v (dry run: %v)", reconciliation.ID, reconciliation.DryRun)
+
+	err = s.runLogReconciliation(ctx, reconciliation)
+
+	completedAt := time.Now().UTC()
+	reconciliation.CompletedAt = &completedAt
+	reconciliation.Status = database.LogReconciliationStatusCompleted
+	if err != nil {
+		reconciliation.Status = database.LogReconciliationStatusFailed
+		reconciliation.ErrorDetails = err.Error()
+	}
+
+	if updateErr := s.databaseClient.UpdateLogReconciliation(ctx, *reconciliation); updateErr != nil {
+		return updateErr
+	}
+	if err != nil {
+		return fmt.Errorf("log reconciliation %v failed: %w", reconciliation.ID, err)
+	}
+
+	log.Info().Msgf("Log reconciliation %v checked %v logs for %v pipelines in %v; copied %v, unrecoverable %v, orphaned %v (dry run: %v)", reconciliation.ID, reconciliation.Checked, reconciliation.Pipelines, time.Since(start), reconciliation.Copied, reconciliation.Unrecoverable, reconciliation.Orphaned, reconciliation.DryRun)
+
+	return nil
+}
+
+// runLogReconciliation reconciles either the single pipeline set on the reconciliation or all pipelines, storing progress after each pipeline
+func (s *service) runLogReconciliation(ctx context.Context, reconciliation *database.LogReconciliation) (err error) {
+
+	reconcile := func(pipeline contracts.Pipeline) error {
+		result, err := s.ReconcilePipelineLogs(ctx, pipeline, reconciliation.DryRun)
+		if err != nil {
+			return err
+		}
+
+		reconciliation.Pipelines++
+		reconciliation.Checked += result.Checked
+		reconciliation.Copied += result.Copied
+		reconciliation.Unrecoverable += result.Unrecoverable
+		reconciliation.Orphaned += result.Orphaned
+
+		return s.databaseClient.UpdateLogReconciliation(ctx, *reconciliation)
+	}
+
+	if reconciliation.RepoSource != "" {
+		pipeline, err := s.databaseClient.GetPipeline(ctx, reconciliation.RepoSource, reconciliation.RepoOwner, reconciliation.RepoName, map[api.FilterType][]string{}, true)
+		if err != nil {
+			return err
+		}
+		if pipeline == nil {
+			return ErrLogReconciliationPipelineNotFound
+		}
+		return reconcile(*pipeline)
+	}
+
+	for pageNumber := 1; ; pageNumber++ {
+		pipelines, err := s.databaseClient.GetPipelines(ctx, pageNumber, logReconciliationPipelinesPageSize, map[api.FilterType][]string{}, []api.OrderField{}, true)
+		if err != nil {
+			return fmt.Errorf("failed retrieving pipelines to reconcile logs for: %w", err)
+		}
+
+		for _, p := range pipelines {
+			err = reconcile(*p)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(pipelines) < logReconciliationPipelinesPageSize {
+			return nil
+		}
+	}
+}
+
+// ReconcilePipelineLogs checks whether the log record the log endpoints return for each build, release and bot has a log file in object storage;
+// a missing log file is copied from an earlier log record of the same job if that one has a log file, otherwise it's counted as unrecoverable,
+// because the database doesn't hold log lines itself; log files without a log record are counted as orphaned
+func (s *service) ReconcilePipelineLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (result *LogReconciliationResult, err error) {
+
+	result = &LogReconciliationResult{}
+
+	for _, jobType := range []contracts.JobType{contracts.JobTypeBuild, contracts.JobTypeRelease, contracts.JobTypeBot} {
+
+		records, err := s.databaseClient.GetLogRecords(ctx, jobType, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName)
+		if err != nil {
+			return nil, err
+		}
+		logIDs, err := s.cloudStorageClient.GetLogIDs(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, jobType)
+		if err != nil {
+			return nil, err
+		}
+
+		storedLogIDs := map[string]bool{}
+		for _, id := range logIDs {
+			storedLogIDs[id] = true
+		}
+		recordIDs := map[string]bool{}
+		for _, r := range records {
+			recordIDs[r.ID] = true
+		}
+
+		// records are ordered newest first, so the first record of a job is the one the log endpoints return
+		copies := map[string]string{}
+		checkedJobIDs := map[string]bool{}
+		for i, r := range records {
+			if r.JobID != "" {
+				if checkedJobIDs[r.JobID] {
+					continue
+				}
+				checkedJobIDs[r.JobID] = true
+			}
+
+			result.Checked++
+			if storedLogIDs[r.ID] {
+				continue
+			}
+
+			sourceID := ""
+			if r.JobID != "" {
+				for _, earlier := range records[i+1:] {
+					if earlier.JobID == r.JobID && storedLogIDs[earlier.ID] {
+						sourceID = earlier.ID
+						break
+					}
+				}
+			}
+			if sourceID == "" {
+				result.Unrecoverable++
+				continue
+			}
+			copies[sourceID] = r.ID
+		}
+
+		for _, id := range logIDs {
+			if !recordIDs[id] {
+				result.Orphaned++
+			}
+		}
+
+		// in a dry run copied holds the number of log files that would be copied
+		result.Copied += len(copies)
+		if dryRun || len(copies) == 0 {
+			continue
+		}
+
+		err = s.cloudStorageClient.CopyLogs(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, jobType, copies)
+		if err != nil {
+			return nil, fmt.Errorf("failed copying %v logs for %v/%v/%v: %w", jobType, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package ziplinee
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+const (
+	// logRetentionPipelinesPageSize is the number of pipelines retrieved at once when pruning logs for all pipelines
+	logRetentionPipelinesPageSize = 100
+)
+
+var (
+	logRetentionPrunedLogsCounter = kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "api",
+		Subsystem: "log_retention",
+		Name:      "pruned_logs_count",
+		Help:      "Number of logs deleted for being past their retention.",
+	}, []string{"target", "job_type"})
+
+	logRetentionPrunedBytesCounter = kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "api",
+		Subsystem: "log_retention",
+		Name:      "pruned_bytes_count",
+		Help:      "Number of bytes of logs deleted for being past their retention.",
+	}, []string{"target", "job_type"})
+
+	logRetentionRunHistogram = kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+		Namespace: "api",
+		Subsystem: "log_retention",
+		Name:      "run_duration_seconds",
+		Help:      "Total duration of pruning logs for all pipelines in seconds.",
+	}, []string{"result"})
+)
+
+func (s *service) PruneExpiredLogs(ctx context.Context, dryRun bool) (report *LogRetentionReport, err error) {
+
+	start := time.Now().UTC()
+	defer func() {
+		if !dryRun {
+			result := "succeeded"
+			if err != nil {
+				result = "failed"
+			}
+			logRetentionRunHistogram.With("result", result).Observe(time.Since(start).Seconds())
+		}
+	}()
+
+	report = &LogRetentionReport{
+		DryRun: dryRun,
+		Items:  []*LogRetentionReportItem{},
+	}
+
+	for pageNumber := 1; ; pageNumber++ {
+		pipelines, err := s.databaseClient.GetPipelines(ctx, pageNumber, logRetentionPipelinesPageSize, map[api.FilterType][]string{}, []api.OrderField{}, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving pipelines to prune logs for: %w", err)
+		}
+
+		for _, p := range pipelines {
+			items, err := s.PrunePipelineExpiredLogs(ctx, *p, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			report.add(items...)
+			report.Pipelines++
+		}
+
+		if len(pipelines) < logRetentionPipelinesPageSize {
+			break
+		}
+	}
+
+	log.Info().Msgf("Log retention pruned %v logs (%v bytes) for %v pipelines in %v (dry run: %v)", report.Logs, report.Bytes, report.Pipelines, time.Since(start), dryRun)
+
+	return report, nil
+}
+
+func (s *service) PrunePipelineExpiredLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (items []*LogRetentionReportItem, err error) {
+
+	items = []*LogRetentionReportItem{}
+
+	if s.config.LogRetention == nil || len(s.config.LogRetention.Rules) == 0 {
+		return items, nil
+	}
+
+	logTargets := []api.LogTarget{}
+	if s.config.APIServer.WriteLogToDatabase() {
+		logTargets = append(logTargets, api.LogTargetDatabase)
+	}
+	if s.config.APIServer.WriteLogToObjectStorage() {
+		logTargets = append(logTargets, s.config.APIServer.ObjectStorageLogTarget())
+	}
+
+	var keepReleaseLogIDs []string
+
+	for _, jobType := range []contracts.JobType{contracts.JobTypeBuild, contracts.JobTypeRelease, contracts.JobTypeBot} {
+		for _, logTarget := range logTargets {
+
+			retentionDays, found := s.config.LogRetention.GetRetentionDays(logTarget, jobType, pipeline.Labels)
+			if !found {
+				continue
+			}
+
+			// the logs of the latest successful release of each target are kept regardless of their age
+			keepLogIDs := []string{}
+			if jobType == contracts.JobTypeRelease {
+				if keepReleaseLogIDs == nil {
+					keepReleaseLogIDs, err = s.databaseClient.GetLatestSuccessfulReleaseLogIDs(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName)
+					if err != nil {
+						return nil, err
+					}
+				}
+				keepLogIDs = keepReleaseLogIDs
+			}
+
+			item := &LogRetentionReportItem{
+				RepoSource:    pipeline.RepoSource,
+				RepoOwner:     pipeline.RepoOwner,
+				RepoName:      pipeline.RepoName,
+				JobType:       jobType,
+				LogTarget:     logTarget,
+				RetentionDays: retentionDays,
+			}
+			expiredBefore := time.Now().UTC().AddDate(0, 0, -retentionDays)
+
+			if logTarget == api.LogTargetDatabase {
+				item.Logs, err = s.pruneDatabaseLogs(ctx, pipeline, jobType, expiredBefore, keepLogIDs, dryRun)
+			} else {
+				item.Logs, item.Bytes, err = s.cloudStorageClient.PruneLogs(ctx, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, jobType, expiredBefore, keepLogIDs, s.config.LogRetention.BatchSize, dryRun)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed pruning %v logs in %v for %v/%v/%v: %w", jobType, logTarget, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, err)
+			}
+
+			if !dryRun {
+				logRetentionPrunedLogsCounter.With("target", string(logTarget), "job_type", string(jobType)).Add(float64(item.Logs))
+				logRetentionPrunedBytesCounter.With("target", string(logTarget), "job_type", string(jobType)).Add(float64(item.Bytes))
+			}
+
+			if item.Logs > 0 {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// pruneDatabaseLogs deletes expired log records in batches, so a single statement never locks many rows
+func (s *service) pruneDatabaseLogs(ctx context.Context, pipeline contracts.Pipeline, jobType contracts.JobType, expiredBefore time.Time, keepLogIDs []string, dryRun bool) (count int, err error) {
+
+	if dryRun {
+		return s.databaseClient.GetExpiredLogsCount(ctx, jobType, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, expiredBefore, keepLogIDs)
+	}
+
+	for {
+		logIDs, err := s.databaseClient.GetExpiredLogIDs(ctx, jobType, pipeline.RepoSource, pipeline.RepoOwner, pipeline.RepoName, expiredBefore, keepLogIDs, s.config.LogRetention.BatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(logIDs) == 0 {
+			return count, nil
+		}
+
+		deleted, err := s.databaseClient.DeleteLogsByID(ctx, jobType, logIDs)
+		count += deleted
+		if err != nil {
+			return count, err
+		}
+
+		if len(logIDs) < s.config.LogRetention.BatchSize {
+			return count, nil
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package ziplinee
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/cloudstorage"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	// logSearchConcurrency is the maximum number of job logs read in parallel for a single search
+	logSearchConcurrency = 5
+
+	logSearchDefaultLast  = 10
+	logSearchMaxLast      = 50
+	logSearchDefaultLimit = 100
+	logSearchMaxLimit     = 1000
+)
+
+var (
+	ErrInvalidLogSearchQuery = errors.New("The log search query is invalid")
+)
+
+// logSearchJob is a build, release or bot whose logs are searched
+type logSearchJob struct {
+	jobType    contracts.JobType
+	id         string
+	name       string
+	version    string
+	status     contracts.Status
+	link       string
+	insertedAt time.Time
+	updatedAt  time.Time
+	readSteps  func(ctx context.Context) ([]*contracts.BuildLogStep, error)
+}
+
+func (s *service) SearchPipelineLogs(ctx context.Context, repoSource, repoOwner, repoName string, query LogSearchQuery) (result *LogSearchResult, err error) {
+
+	query = s.getLogSearchQueryWithDefaults(query)
+
+	matchLine, err := getLogSearchLineMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.getLogSearchJobs(ctx, repoSource, repoOwner, repoName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// limit concurrency using a semaphore
+	semaphore := semaphore.NewWeighted(logSearchConcurrency)
+	g, ctx := errgroup.WithContext(ctx)
+
+	matchesPerJob := make([][]*LogSearchMatch, len(jobs))
+	truncatedPerJob := make([]bool, len(jobs))
+	for i, j := range jobs {
+		i := i
+		j := j
+
+		g.Go(func() error {
+			err := semaphore.Acquire(ctx, 1)
+			if err != nil {
+				return err
+			}
+			defer semaphore.Release(1)
+
+			steps, err := j.readSteps(ctx)
+			if err != nil {
+				if errors.Is(err, cloudstorage.ErrLogNotExist) {
+					log.Debug().Err(err).Msgf("No logs to search for %v %v of %v/%v/%v", j.jobType, j.id, repoSource, repoOwner, repoName)
+					return nil
+				}
+				return fmt.Errorf("failed reading logs for %v %v: %w", j.jobType, j.id, err)
+			}
+
+			matchesPerJob[i], truncatedPerJob[i] = searchLogSteps(j, steps, query, matchLine)
+
+			return nil
+		})
+	}
+
+	// wait until all concurrent goroutines are done
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	result = &LogSearchResult{
+		Matches:      []*LogSearchMatch{},
+		SearchedJobs: len(jobs),
+	}
+	for i := range jobs {
+		result.Matches = append(result.Matches, matchesPerJob[i]...)
+		result.Truncated = result.Truncated || truncatedPerJob[i]
+	}
+
+	// order oldest first to show when a line first appeared
+	sort.SliceStable(result.Matches, func(i, j int) bool {
+		if result.Matches[i].JobInsertedAt.Equal(result.Matches[j].JobInsertedAt) {
+			return result.Matches[i].Timestamp.Before(result.Matches[j].Timestamp)
+		}
+		return result.Matches[i].JobInsertedAt.Before(result.Matches[j].JobInsertedAt)
+	})
+
+	if len(result.Matches) > query.Limit {
+		result.Matches = result.Matches[:query.Limit]
+		result.Truncated = true
+	}
+
+	return result, nil
+}
+
+func (s *service) getLogSearchQueryWithDefaults(query LogSearchQuery) LogSearchQuery {
+	if query.Last <= 0 {
+		query.Last = logSearchDefaultLast
+	}
+	if query.Last > logSearchMaxLast {
+		query.Last = logSearchMaxLast
+	}
+	if query.Limit <= 0 {
+		query.Limit = logSearchDefaultLimit
+	}
+	if query.Limit > logSearchMaxLimit {
+		query.Limit = logSearchMaxLimit
+	}
+	if len(query.JobTypes) == 0 {
+		query.JobTypes = []contracts.JobType{contracts.JobTypeBuild, contracts.JobTypeRelease, contracts.JobTypeBot}
+	}
+
+	return query
+}
+
+func getLogSearchLineMatcher(query LogSearchQuery) (func(string) bool, error) {
+	if query.Text == "" {
+		return nil, errors.Wrap(ErrInvalidLogSearchQuery, "text to search for is empty")
+	}
+
+	if query.Regex {
+		re, err := regexp.Compile(query.Text)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidLogSearchQuery, "regular expression %v does not compile: %v", query.Text, err)
+		}
+		return re.MatchString, nil
+	}
+
+	text := strings.ToLower(query.Text)
+	return func(line string) bool {
+		return strings.Contains(strings.ToLower(line), text)
+	}, nil
+}
+
+// getLogSearchJobs returns the last builds, releases and bots of a pipeline that ran within the time range of the query
+func (s *service) getLogSearchJobs(ctx context.Context, repoSource, repoOwner, repoName string, query LogSearchQuery) (jobs []*logSearchJob, err error) {
+
+	jobs = []*logSearchJob{}
+	baseURL := strings.TrimRight(s.config.APIServer.BaseURL, "/")
+
+	for _, jobType := range query.JobTypes {
+		switch jobType {
+		case contracts.JobTypeBuild:
+			builds, err := s.databaseClient.GetPipelineBuilds(ctx, repoSource, repoOwner, repoName, 1, query.Last, map[api.FilterType][]string{}, []api.OrderField{}, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed retrieving builds to search logs for: %w", err)
+			}
+			for _, b := range builds {
+				b := b
+				jobs = append(jobs, &logSearchJob{
+					jobType:    contracts.JobTypeBuild,
+					id:         b.ID,
+					name:       b.RepoBranch,
+					version:    b.BuildVersion,
+					status:     b.BuildStatus,
+					link:       fmt.Sprintf("%v/pipelines/%v/%v/%v/builds/%v/logs", baseURL, repoSource, repoOwner, repoName, b.ID),
+					insertedAt: b.InsertedAt,
+					updatedAt:  b.UpdatedAt,
+					readSteps: func(ctx context.Context) ([]*contracts.BuildLogStep, error) {
+						buildLog, err := s.databaseClient.GetPipelineBuildLogs(ctx, repoSource, repoOwner, repoName, b.RepoBranch, b.RepoRevision, b.ID)
+						if err != nil || buildLog == nil {
+							return nil, err
+						}
+						if s.config.APIServer.ReadLogFromObjectStorage() {
+							return s.cloudStorageClient.GetPipelineBuildLogSteps(ctx, *buildLog)
+						}
+						return buildLog.Steps, nil
+					},
+				})
+			}
+
+		case contracts.JobTypeRelease:
+			releases, err := s.databaseClient.GetPipelineReleases(ctx, repoSource, repoOwner, repoName, 1, query.Last, map[api.FilterType][]string{}, []api.OrderField{})
+			if err != nil {
+				return nil, fmt.Errorf("failed retrieving releases to search logs for: %w", err)
+			}
+			for _, r := range releases {
+				r := r
+				jobs = append(jobs, &logSearchJob{
+					jobType:    contracts.JobTypeRelease,
+					id:         r.ID,
+					name:       strings.Trim(fmt.Sprintf("%v %v", r.Name, r.Action), " "),
+					version:    r.ReleaseVersion,
+					status:     r.ReleaseStatus,
+					link:       fmt.Sprintf("%v/pipelines/%v/%v/%v/releases/%v/logs", baseURL, repoSource, repoOwner, repoName, r.ID),
+					insertedAt: timeOrZero(r.InsertedAt),
+					updatedAt:  timeOrZero(r.UpdatedAt),
+					readSteps: func(ctx context.Context) ([]*contracts.BuildLogStep, error) {
+						releaseLog, err := s.databaseClient.GetPipelineReleaseLogs(ctx, repoSource, repoOwner, repoName, r.ID)
+						if err != nil || releaseLog == nil {
+							return nil, err
+						}
+						if s.config.APIServer.ReadLogFromObjectStorage() {
+							return s.cloudStorageClient.GetPipelineReleaseLogSteps(ctx, *releaseLog)
+						}
+						return releaseLog.Steps, nil
+					},
+				})
+			}
+
+		case contracts.JobTypeBot:
+			bots, err := s.databaseClient.GetPipelineBots(ctx, repoSource, repoOwner, repoName, 1, query.Last, map[api.FilterType][]string{}, []api.OrderField{})
+			if err != nil {
+				return nil, fmt.Errorf("failed retrieving bots to search logs for: %w", err)
+			}
+			for _, b := range bots {
+				b := b
+				jobs = append(jobs, &logSearchJob{
+					jobType:    contracts.JobTypeBot,
+					id:         b.ID,
+					name:       b.Name,
+					status:     b.BotStatus,
+					link:       fmt.Sprintf("%v/pipelines/%v/%v/%v/bots/%v/logs", baseURL, repoSource, repoOwner, repoName, b.ID),
+					insertedAt: timeOrZero(b.InsertedAt),
+					updatedAt:  timeOrZero(b.UpdatedAt),
+					readSteps: func(ctx context.Context) ([]*contracts.BuildLogStep, error) {
+						botLog, err := s.databaseClient.GetPipelineBotLogs(ctx, repoSource, repoOwner, repoName, b.ID)
+						if err != nil || botLog == nil {
+							return nil, err
+						}
+						if s.config.APIServer.ReadLogFromObjectStorage() {
+							return s.cloudStorageClient.GetPipelineBotLogSteps(ctx, *botLog)
+						}
+						return botLog.Steps, nil
+					},
+				})
+			}
+
+		default:
+			return nil, errors.Wrapf(ErrInvalidLogSearchQuery, "job type %v is not supported", jobType)
+		}
+	}
+
+	// skip jobs that can't have logged anything within the time range
+	filteredJobs := []*logSearchJob{}
+	for _, j := range jobs {
+		if query.Since != nil && !j.updatedAt.IsZero() && j.updatedAt.Before(*query.Since) {
+			continue
+		}
+		if query.Until != nil && j.insertedAt.After(*query.Until) {
+			continue
+		}
+		filteredJobs = append(filteredJobs, j)
+	}
+
+	return filteredJobs, nil
+}
+
+// searchLogSteps returns the first query.Limit matching lines of a job's log, including nested steps and services
+func searchLogSteps(job *logSearchJob, steps []*contracts.BuildLogStep, query LogSearchQuery, matchLine func(string) bool) (matches []*LogSearchMatch, truncated bool) {
+
+	matches = []*LogSearchMatch{}
+
+	var searchSteps func(steps []*contracts.BuildLogStep, parentStepSelected bool) bool
+	searchSteps = func(steps []*contracts.BuildLogStep, parentStepSelected bool) bool {
+		for _, step := range steps {
+			if step == nil {
+				continue
+			}
+
+			stepSelected := parentStepSelected || len(query.Steps) == 0 || api.StringArrayContains(query.Steps, step.Step)
+
+			if stepSelected {
+				for _, line := range step.LogLines {
+					if len(query.StreamTypes) > 0 && !api.StringArrayContains(query.StreamTypes, line.StreamType) {
+						continue
+					}
+					if query.Since != nil && line.Timestamp.Before(*query.Since) {
+						continue
+					}
+					if query.Until != nil && line.Timestamp.After(*query.Until) {
+						continue
+					}
+					if !matchLine(line.Text) {
+						continue
+					}
+					if len(matches) >= query.Limit {
+						return false
+					}
+
+					matches = append(matches, &LogSearchMatch{
+						JobType:       job.jobType,
+						JobID:         job.id,
+						JobName:       job.name,
+						JobVersion:    job.version,
+						JobStatus:     job.status,
+						JobLink:       job.link,
+						JobInsertedAt: job.insertedAt,
+						Step:          step.Step,
+						RunIndex:      step.RunIndex,
+						LineNumber:    line.LineNumber,
+						Timestamp:     line.Timestamp,
+						StreamType:    line.StreamType,
+						Text:          line.Text,
+					})
+				}
+			}
+
+			if !searchSteps(step.NestedSteps, stepSelected) || !searchSteps(step.Services, stepSelected) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	truncated = !searchSteps(steps, false)
+
+	return matches, truncated
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
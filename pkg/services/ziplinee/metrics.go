@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-kit/kit/metrics"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 	manifest "github.com/ziplineeci/ziplinee-ci-manifest"
 )
@@ -154,3 +155,139 @@ func (s *metricsService) GetEventsForJobEnvvars(ctx context.Context, triggers []
 
 	return s.Service.GetEventsForJobEnvvars(ctx, triggers, events)
 }
+
+func (s *metricsService) DispatchQueuedJobs(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "DispatchQueuedJobs", begin)
+	}(time.Now())
+
+	return s.Service.DispatchQueuedJobs(ctx)
+}
+
+func (s *metricsService) QueueMigration(ctx context.Context, task database.MigrationTask, restart string) (queuedTask *database.MigrationTask, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "QueueMigration", begin)
+	}(time.Now())
+
+	return s.Service.QueueMigration(ctx, task, restart)
+}
+
+func (s *metricsService) RunMigrationTasks(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "RunMigrationTasks", begin)
+	}(time.Now())
+
+	return s.Service.RunMigrationTasks(ctx)
+}
+
+func (s *metricsService) RollbackMigration(ctx context.Context, task database.MigrationTask) (rollback *database.MigrationRollback, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "RollbackMigration", begin)
+	}(time.Now())
+
+	return s.Service.RollbackMigration(ctx, task)
+}
+
+func (s *metricsService) SearchPipelineLogs(ctx context.Context, repoSource, repoOwner, repoName string, query LogSearchQuery) (result *LogSearchResult, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "SearchPipelineLogs", begin)
+	}(time.Now())
+
+	return s.Service.SearchPipelineLogs(ctx, repoSource, repoOwner, repoName, query)
+}
+
+func (s *metricsService) PruneExpiredLogs(ctx context.Context, dryRun bool) (report *LogRetentionReport, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "PruneExpiredLogs", begin)
+	}(time.Now())
+
+	return s.Service.PruneExpiredLogs(ctx, dryRun)
+}
+
+func (s *metricsService) PrunePipelineExpiredLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (items []*LogRetentionReportItem, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "PrunePipelineExpiredLogs", begin)
+	}(time.Now())
+
+	return s.Service.PrunePipelineExpiredLogs(ctx, pipeline, dryRun)
+}
+
+func (s *metricsService) QueueLogReconciliation(ctx context.Context, reconciliation database.LogReconciliation) (queuedReconciliation *database.LogReconciliation, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "QueueLogReconciliation", begin)
+	}(time.Now())
+
+	return s.Service.QueueLogReconciliation(ctx, reconciliation)
+}
+
+func (s *metricsService) RunLogReconciliations(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "RunLogReconciliations", begin)
+	}(time.Now())
+
+	return s.Service.RunLogReconciliations(ctx)
+}
+
+func (s *metricsService) ReconcilePipelineLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (result *LogReconciliationResult, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "ReconcilePipelineLogs", begin)
+	}(time.Now())
+
+	return s.Service.ReconcilePipelineLogs(ctx, pipeline, dryRun)
+}
+
+func (s *metricsService) DispatchJobStatus(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "DispatchJobStatus", begin)
+	}(time.Now())
+
+	return s.Service.DispatchJobStatus(ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
+func (s *metricsService) DispatchNotification(ctx context.Context, notification contracts.NotificationRecord) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "DispatchNotification", begin)
+	}(time.Now())
+
+	return s.Service.DispatchNotification(ctx, notification)
+}
+
+func (s *metricsService) DeliverNotifications(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "DeliverNotifications", begin)
+	}(time.Now())
+
+	return s.Service.DeliverNotifications(ctx)
+}
+
+func (s *metricsService) CancelJob(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (canceledStatus contracts.Status, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "CancelJob", begin)
+	}(time.Now())
+
+	return s.Service.CancelJob(ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
+func (s *metricsService) ExplainJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, name, action string) (explanation *JobResourcesExplanation, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "ExplainJobResources", begin)
+	}(time.Now())
+
+	return s.Service.ExplainJobResources(ctx, jobType, repoSource, repoOwner, repoName, name, action)
+}
+
+func (s *metricsService) GetCostReport(ctx context.Context, dimension CostDimension, labelKey string, from, to time.Time, filters map[api.FilterType][]string) (report *CostReport, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "GetCostReport", begin)
+	}(time.Now())
+
+	return s.Service.GetCostReport(ctx, dimension, labelKey, from, to, filters)
+}
+
+func (s *metricsService) GetDoraMetrics(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (metrics *DoraMetrics, err error) {
+	defer func(begin time.Time) {
+		api.UpdateMetrics(s.requestCount, s.requestLatency, "GetDoraMetrics", begin)
+	}(time.Now())
+
+	return s.Service.GetDoraMetrics(ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
@@ -0,0 +1,307 @@
+package ziplinee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sethgrid/pester"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+)
+
+const (
+	// migrationRestartLastStep restarts a migration task where it stopped
+	migrationRestartLastStep = "last_stage"
+)
+
+var (
+	ErrMigrationPipelineNotFound = errors.New("The pipeline to migrate can't be found")
+	ErrMigrationInProgress       = errors.New("The migration task is in progress")
+	ErrMigrationInvalidRestart   = errors.New("The migration task can't be restarted from the requested step")
+)
+
+func (s *service) QueueMigration(ctx context.Context, task database.MigrationTask, restart string) (queuedTask *database.MigrationTask, err error) {
+
+	pipeline, err := s.databaseClient.GetPipeline(ctx, task.FromSource, task.FromOwner, task.FromName, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if pipeline == nil {
+		return nil, ErrMigrationPipelineNotFound
+	}
+
+	lastStep, err := getMigrationLastStepForRestart(restart)
+	if err != nil {
+		return nil, err
+	}
+
+	existingTask, err := s.databaseClient.GetMigrationTaskByFromRepo(ctx, task.FromSource, task.FromOwner, task.FromName)
+	if err != nil && !errors.Is(err, database.ErrMigrationTaskNotFound) {
+		return nil, err
+	}
+	// a task that is being worked on can only be queued again explicitly, in case its worker got killed
+	if existingTask != nil && existingTask.Status == database.MigrationStatusInProgress && restart == "" {
+		return nil, ErrMigrationInProgress
+	}
+
+	if task.ID == "" {
+		task.ID = uuid.New().String()
+	}
+	task.Status = database.MigrationStatusQueued
+	task.LastStep = lastStep
+
+	return s.databaseClient.QueueMigrationTask(ctx, task)
+}
+
+func (s *service) RunMigrationTasks(ctx context.Context) (err error) {
+
+	tasks, err := s.databaseClient.PickMigrationTasks(ctx, s.config.Migration.MaxConcurrentTasks)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(tasks))
+
+	for _, t := range tasks {
+		go func(task database.MigrationTask) {
+			defer wg.Done()
+			s.runMigrationTask(ctx, task)
+		}(*t)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (s *service) RollbackMigration(ctx context.Context, task database.MigrationTask) (rollback *database.MigrationRollback, err error) {
+
+	if task.Status == database.MigrationStatusInProgress {
+		return nil, ErrMigrationInProgress
+	}
+
+	// the last step archives the original pipeline; make sure it shows up again
+	err = s.databaseClient.UnarchiveComputedPipeline(ctx, task.FromSource, task.FromOwner, task.FromName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.databaseClient.RollbackMigration(ctx, task, s.getShortRepoSource(task.ToSource))
+}
+
+// runMigrationTask executes all steps of a migration task, starting after the last step that succeeded; a failing step is rolled back and stops the task so it can be restarted from that step
+func (s *service) runMigrationTask(ctx context.Context, task database.MigrationTask) {
+
+	log.Info().Msgf("Migrating %v/%v/%v to %v/%v/%v for task %v, starting after step %v", task.FromSource, task.FromOwner, task.FromName, task.ToSource, task.ToOwner, task.ToName, task.ID, task.LastStep)
+
+	steps := getMigrationStepsToRun(task.LastStep)
+	if len(steps) == 0 {
+		// all steps are done already
+		err := s.databaseClient.UpdateMigrationTask(ctx, task.ID, database.MigrationStatusCompleted, "", 0, 0, 0, "")
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed updating status of migration task %v", task.ID)
+		}
+		return
+	}
+
+	for i, step := range steps {
+		start := time.Now()
+
+		builds, releases, err := s.runMigrationStep(ctx, task, step)
+		if err != nil {
+			log.Error().Err(err).Msgf("Migration step %v failed for task %v", step, task.ID)
+
+			if rollbackErr := s.rollbackMigrationStep(ctx, task, step); rollbackErr != nil {
+				log.Error().Err(rollbackErr).Msgf("Rolling back migration step %v failed for task %v", step, task.ID)
+			}
+
+			err = s.databaseClient.UpdateMigrationTask(ctx, task.ID, database.MigrationStatusFailed, step.Failed(), 0, 0, time.Since(start), fmt.Sprintf("%v: %v\n", step, err))
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed updating status of migration task %v", task.ID)
+			}
+			return
+		}
+
+		status := database.MigrationStatusInProgress
+		if i == len(steps)-1 {
+			status = database.MigrationStatusCompleted
+		}
+
+		err = s.databaseClient.UpdateMigrationTask(ctx, task.ID, status, step.Done(), builds, releases, time.Since(start), "")
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed updating status of migration task %v", task.ID)
+			return
+		}
+		task.Status = status
+		task.LastStep = step.Done()
+	}
+
+	log.Info().Msgf("Migrated %v/%v/%v to %v/%v/%v for task %v", task.FromSource, task.FromOwner, task.FromName, task.ToSource, task.ToOwner, task.ToName, task.ID)
+}
+
+func (s *service) runMigrationStep(ctx context.Context, task database.MigrationTask, step database.MigrationStep) (builds, releases int, err error) {
+
+	switch step {
+	case database.MigrationStepReleases:
+		releases, err = s.databaseClient.MigrateReleases(ctx, task)
+
+	case database.MigrationStepReleaseLogs:
+		err = s.databaseClient.MigrateReleaseLogs(ctx, task)
+
+	case database.MigrationStepReleaseLogObjects:
+		if !s.config.APIServer.WriteLogToObjectStorage() {
+			return
+		}
+		var logIDs map[string]string
+		logIDs, err = s.databaseClient.GetMigratedReleaseLogIDs(ctx, task)
+		if err != nil {
+			return
+		}
+		err = s.cloudStorageClient.CopyReleaseLogs(ctx, task.FromSource, task.FromOwner, task.FromName, task.ToSource, task.ToOwner, task.ToName, logIDs)
+
+	case database.MigrationStepBuilds:
+		builds, err = s.databaseClient.MigrateBuilds(ctx, task)
+
+	case database.MigrationStepBuildLogs:
+		err = s.databaseClient.MigrateBuildLogs(ctx, task)
+
+	case database.MigrationStepBuildLogObjects:
+		if !s.config.APIServer.WriteLogToObjectStorage() {
+			return
+		}
+		var logIDs map[string]string
+		logIDs, err = s.databaseClient.GetMigratedBuildLogIDs(ctx, task)
+		if err != nil {
+			return
+		}
+		err = s.cloudStorageClient.CopyBuildLogs(ctx, task.FromSource, task.FromOwner, task.FromName, task.ToSource, task.ToOwner, task.ToName, logIDs)
+
+	case database.MigrationStepBuildVersions:
+		err = s.databaseClient.MigrateBuildVersions(ctx, task, s.getShortRepoSource(task.FromSource), s.getShortRepoSource(task.ToSource))
+
+	case database.MigrationStepComputedTables:
+		err = s.databaseClient.MigrateComputedTables(ctx, task)
+
+	case database.MigrationStepCallback:
+		// switch over to the migrated pipeline before letting the caller know
+		err = s.databaseClient.ArchiveComputedPipeline(ctx, task.FromSource, task.FromOwner, task.FromName)
+		if err != nil {
+			return
+		}
+		err = s.databaseClient.UnarchiveComputedPipeline(ctx, task.ToSource, task.ToOwner, task.ToName)
+		if err != nil {
+			return
+		}
+		err = s.sendMigrationCallback(ctx, task)
+	}
+
+	return
+}
+
+func (s *service) rollbackMigrationStep(ctx context.Context, task database.MigrationTask, step database.MigrationStep) (err error) {
+
+	if step == database.MigrationStepCallback {
+		err = s.databaseClient.ArchiveComputedPipeline(ctx, task.ToSource, task.ToOwner, task.ToName)
+		if err != nil {
+			return
+		}
+		return s.databaseClient.UnarchiveComputedPipeline(ctx, task.FromSource, task.FromOwner, task.FromName)
+	}
+
+	return s.databaseClient.RollbackMigrationStep(ctx, task, step, s.getShortRepoSource(task.ToSource))
+}
+
+// sendMigrationCallback posts the completed task to the callback url, if the task has one
+func (s *service) sendMigrationCallback(ctx context.Context, task database.MigrationTask) (err error) {
+
+	if task.CallbackURL == "" {
+		return nil
+	}
+
+	task.Status = database.MigrationStatusCompleted
+	task.LastStep = database.MigrationStepCallback.Done()
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return
+	}
+
+	client := pester.NewExtendedClient(&http.Client{Transport: &nethttp.Transport{}})
+	client.MaxRetries = 3
+	client.Backoff = pester.ExponentialJitterBackoff
+	client.KeepLog = true
+	client.Timeout = time.Second * 10
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, task.CallbackURL, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("Callback to %v responded with status code %v", task.CallbackURL, response.StatusCode)
+	}
+
+	return nil
+}
+
+// getMigrationLastStepForRestart returns the last step to store for a queued task, so the worker picks up at the requested step
+func getMigrationLastStepForRestart(restart string) (lastStep string, err error) {
+
+	if restart == "" || restart == migrationRestartLastStep {
+		// keep the last step, to continue where the task stopped
+		return "", nil
+	}
+
+	for i, step := range database.MigrationSteps {
+		if string(step) != restart {
+			continue
+		}
+		if i == 0 {
+			return database.MigrationLastStepWaiting, nil
+		}
+		return database.MigrationSteps[i-1].Done(), nil
+	}
+
+	return "", ErrMigrationInvalidRestart
+}
+
+// getMigrationStepsToRun returns the steps following the last succeeded step, or including the last failed step
+func getMigrationStepsToRun(lastStep string) []database.MigrationStep {
+
+	for i, step := range database.MigrationSteps {
+		switch lastStep {
+		case step.Done():
+			return database.MigrationSteps[i+1:]
+		case step.Failed():
+			return database.MigrationSteps[i:]
+		}
+	}
+
+	// waiting, unknown or empty
+	return database.MigrationSteps
+}
+
+// getMigrationRestartSteps returns the values allowed for restarting a migration task
+func getMigrationRestartSteps() string {
+	steps := []string{migrationRestartLastStep}
+	for _, step := range database.MigrationSteps {
+		steps = append(steps, string(step))
+	}
+	return strings.Join(steps, ", ")
+}
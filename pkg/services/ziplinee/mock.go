@@ -7,8 +7,11 @@ package ziplinee
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	api "github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	database "github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 	manifest "github.com/ziplineeci/ziplinee-ci-manifest"
 )
@@ -50,6 +53,21 @@ func (mr *MockServiceMockRecorder) Archive(ctx, repoSource, repoOwner, repoName
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockService)(nil).Archive), ctx, repoSource, repoOwner, repoName)
 }
 
+// CancelJob mocks base method.
+func (m *MockService) CancelJob(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (contracts.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+	ret0, _ := ret[0].(contracts.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockServiceMockRecorder) CancelJob(ctx, jobType, repoSource, repoOwner, repoName, jobID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockService)(nil).CancelJob), ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
 // CreateBot mocks base method.
 func (m *MockService) CreateBot(ctx context.Context, bot contracts.Bot, mft manifest.ZiplineeManifest, repoBranch string) (*contracts.Bot, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +113,77 @@ func (mr *MockServiceMockRecorder) CreateRelease(ctx, release, mft, repoBranch,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRelease", reflect.TypeOf((*MockService)(nil).CreateRelease), ctx, release, mft, repoBranch, repoRevision)
 }
 
+// DeliverNotifications mocks base method.
+func (m *MockService) DeliverNotifications(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverNotifications", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeliverNotifications indicates an expected call of DeliverNotifications.
+func (mr *MockServiceMockRecorder) DeliverNotifications(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverNotifications", reflect.TypeOf((*MockService)(nil).DeliverNotifications), ctx)
+}
+
+// DispatchJobStatus mocks base method.
+func (m *MockService) DispatchJobStatus(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchJobStatus", ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchJobStatus indicates an expected call of DispatchJobStatus.
+func (mr *MockServiceMockRecorder) DispatchJobStatus(ctx, jobType, repoSource, repoOwner, repoName, jobID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchJobStatus", reflect.TypeOf((*MockService)(nil).DispatchJobStatus), ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
+// DispatchNotification mocks base method.
+func (m *MockService) DispatchNotification(ctx context.Context, notification contracts.NotificationRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchNotification", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchNotification indicates an expected call of DispatchNotification.
+func (mr *MockServiceMockRecorder) DispatchNotification(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchNotification", reflect.TypeOf((*MockService)(nil).DispatchNotification), ctx, notification)
+}
+
+// DispatchQueuedJobs mocks base method.
+func (m *MockService) DispatchQueuedJobs(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchQueuedJobs", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DispatchQueuedJobs indicates an expected call of DispatchQueuedJobs.
+func (mr *MockServiceMockRecorder) DispatchQueuedJobs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchQueuedJobs", reflect.TypeOf((*MockService)(nil).DispatchQueuedJobs), ctx)
+}
+
+// ExplainJobResources mocks base method.
+func (m *MockService) ExplainJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, name, action string) (*JobResourcesExplanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainJobResources", ctx, jobType, repoSource, repoOwner, repoName, name, action)
+	ret0, _ := ret[0].(*JobResourcesExplanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainJobResources indicates an expected call of ExplainJobResources.
+func (mr *MockServiceMockRecorder) ExplainJobResources(ctx, jobType, repoSource, repoOwner, repoName, name, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainJobResources", reflect.TypeOf((*MockService)(nil).ExplainJobResources), ctx, jobType, repoSource, repoOwner, repoName, name, action)
+}
+
 // FinishBot mocks base method.
 func (m *MockService) FinishBot(ctx context.Context, repoSource, repoOwner, repoName, botID string, botStatus contracts.Status) error {
 	m.ctrl.T.Helper()
@@ -235,6 +324,36 @@ func (mr *MockServiceMockRecorder) FireReleaseTriggers(ctx, release, event inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FireReleaseTriggers", reflect.TypeOf((*MockService)(nil).FireReleaseTriggers), ctx, release, event)
 }
 
+// GetCostReport mocks base method.
+func (m *MockService) GetCostReport(ctx context.Context, dimension CostDimension, labelKey string, from, to time.Time, filters map[api.FilterType][]string) (*CostReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostReport", ctx, dimension, labelKey, from, to, filters)
+	ret0, _ := ret[0].(*CostReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostReport indicates an expected call of GetCostReport.
+func (mr *MockServiceMockRecorder) GetCostReport(ctx, dimension, labelKey, from, to, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostReport", reflect.TypeOf((*MockService)(nil).GetCostReport), ctx, dimension, labelKey, from, to, filters)
+}
+
+// GetDoraMetrics mocks base method.
+func (m *MockService) GetDoraMetrics(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (*DoraMetrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDoraMetrics", ctx, repoSource, repoOwner, repoName, from, to, filters)
+	ret0, _ := ret[0].(*DoraMetrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDoraMetrics indicates an expected call of GetDoraMetrics.
+func (mr *MockServiceMockRecorder) GetDoraMetrics(ctx, repoSource, repoOwner, repoName, from, to, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDoraMetrics", reflect.TypeOf((*MockService)(nil).GetDoraMetrics), ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
+
 // GetEventsForJobEnvvars mocks base method.
 func (m *MockService) GetEventsForJobEnvvars(ctx context.Context, triggers []manifest.ZiplineeTrigger, events []manifest.ZiplineeEvent) ([]manifest.ZiplineeEvent, error) {
 	m.ctrl.T.Helper()
@@ -250,6 +369,81 @@ func (mr *MockServiceMockRecorder) GetEventsForJobEnvvars(ctx, triggers, events
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsForJobEnvvars", reflect.TypeOf((*MockService)(nil).GetEventsForJobEnvvars), ctx, triggers, events)
 }
 
+// PruneExpiredLogs mocks base method.
+func (m *MockService) PruneExpiredLogs(ctx context.Context, dryRun bool) (*LogRetentionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneExpiredLogs", ctx, dryRun)
+	ret0, _ := ret[0].(*LogRetentionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneExpiredLogs indicates an expected call of PruneExpiredLogs.
+func (mr *MockServiceMockRecorder) PruneExpiredLogs(ctx, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneExpiredLogs", reflect.TypeOf((*MockService)(nil).PruneExpiredLogs), ctx, dryRun)
+}
+
+// PrunePipelineExpiredLogs mocks base method.
+func (m *MockService) PrunePipelineExpiredLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) ([]*LogRetentionReportItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrunePipelineExpiredLogs", ctx, pipeline, dryRun)
+	ret0, _ := ret[0].([]*LogRetentionReportItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrunePipelineExpiredLogs indicates an expected call of PrunePipelineExpiredLogs.
+func (mr *MockServiceMockRecorder) PrunePipelineExpiredLogs(ctx, pipeline, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrunePipelineExpiredLogs", reflect.TypeOf((*MockService)(nil).PrunePipelineExpiredLogs), ctx, pipeline, dryRun)
+}
+
+// QueueLogReconciliation mocks base method.
+func (m *MockService) QueueLogReconciliation(ctx context.Context, reconciliation database.LogReconciliation) (*database.LogReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueLogReconciliation", ctx, reconciliation)
+	ret0, _ := ret[0].(*database.LogReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueLogReconciliation indicates an expected call of QueueLogReconciliation.
+func (mr *MockServiceMockRecorder) QueueLogReconciliation(ctx, reconciliation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueLogReconciliation", reflect.TypeOf((*MockService)(nil).QueueLogReconciliation), ctx, reconciliation)
+}
+
+// QueueMigration mocks base method.
+func (m *MockService) QueueMigration(ctx context.Context, task database.MigrationTask, restart string) (*database.MigrationTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueMigration", ctx, task, restart)
+	ret0, _ := ret[0].(*database.MigrationTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueMigration indicates an expected call of QueueMigration.
+func (mr *MockServiceMockRecorder) QueueMigration(ctx, task, restart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueMigration", reflect.TypeOf((*MockService)(nil).QueueMigration), ctx, task, restart)
+}
+
+// ReconcilePipelineLogs mocks base method.
+func (m *MockService) ReconcilePipelineLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (*LogReconciliationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcilePipelineLogs", ctx, pipeline, dryRun)
+	ret0, _ := ret[0].(*LogReconciliationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcilePipelineLogs indicates an expected call of ReconcilePipelineLogs.
+func (mr *MockServiceMockRecorder) ReconcilePipelineLogs(ctx, pipeline, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcilePipelineLogs", reflect.TypeOf((*MockService)(nil).ReconcilePipelineLogs), ctx, pipeline, dryRun)
+}
+
 // Rename mocks base method.
 func (m *MockService) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) error {
 	m.ctrl.T.Helper()
@@ -264,6 +458,64 @@ func (mr *MockServiceMockRecorder) Rename(ctx, fromRepoSource, fromRepoOwner, fr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockService)(nil).Rename), ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName)
 }
 
+// RollbackMigration mocks base method.
+func (m *MockService) RollbackMigration(ctx context.Context, task database.MigrationTask) (*database.MigrationRollback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackMigration", ctx, task)
+	ret0, _ := ret[0].(*database.MigrationRollback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackMigration indicates an expected call of RollbackMigration.
+func (mr *MockServiceMockRecorder) RollbackMigration(ctx, task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackMigration", reflect.TypeOf((*MockService)(nil).RollbackMigration), ctx, task)
+}
+
+// RunLogReconciliations mocks base method.
+func (m *MockService) RunLogReconciliations(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunLogReconciliations", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunLogReconciliations indicates an expected call of RunLogReconciliations.
+func (mr *MockServiceMockRecorder) RunLogReconciliations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunLogReconciliations", reflect.TypeOf((*MockService)(nil).RunLogReconciliations), ctx)
+}
+
+// RunMigrationTasks mocks base method.
+func (m *MockService) RunMigrationTasks(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunMigrationTasks", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunMigrationTasks indicates an expected call of RunMigrationTasks.
+func (mr *MockServiceMockRecorder) RunMigrationTasks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMigrationTasks", reflect.TypeOf((*MockService)(nil).RunMigrationTasks), ctx)
+}
+
+// SearchPipelineLogs mocks base method.
+func (m *MockService) SearchPipelineLogs(ctx context.Context, repoSource, repoOwner, repoName string, query LogSearchQuery) (*LogSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPipelineLogs", ctx, repoSource, repoOwner, repoName, query)
+	ret0, _ := ret[0].(*LogSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPipelineLogs indicates an expected call of SearchPipelineLogs.
+func (mr *MockServiceMockRecorder) SearchPipelineLogs(ctx, repoSource, repoOwner, repoName, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPipelineLogs", reflect.TypeOf((*MockService)(nil).SearchPipelineLogs), ctx, repoSource, repoOwner, repoName, query)
+}
+
 // Unarchive mocks base method.
 func (m *MockService) Unarchive(ctx context.Context, repoSource, repoOwner, repoName string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,487 @@
+package ziplinee
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+const (
+	// notificationDeliveryStaleAfter is the time after which a delivery that was picked but never finished is picked up again
+	notificationDeliveryStaleAfter = 5 * time.Minute
+
+	// notificationEmailTimeout bounds connecting to the smtp server and sending an email, so an unresponsive server can't stall deliveries
+	notificationEmailTimeout = 30 * time.Second
+
+	// notificationSignatureHeader holds the hex encoded hmac-sha256 of the body of a webhook delivery, keyed with the channel's signing secret
+	notificationSignatureHeader = "X-Ziplinee-Signature-256"
+)
+
+var (
+	// ErrNotificationChannelNotFound indicates a delivery is for a channel that has been removed from the configuration since it was queued
+	ErrNotificationChannelNotFound = errors.New("The notification channel is no longer configured")
+
+	notificationDeliveriesCounter = kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "api",
+		Subsystem: "notifications",
+		Name:      "deliveries_count",
+		Help:      "Number of attempts to deliver a notification to a channel.",
+	}, []string{"channel_type", "result"})
+)
+
+func (s *service) DispatchJobStatus(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (err error) {
+
+	if !s.notificationsEnabled() {
+		return nil
+	}
+
+	event := NotificationEvent{
+		Status:     status,
+		RepoSource: repoSource,
+		RepoOwner:  repoOwner,
+		RepoName:   repoName,
+		ID:         jobID,
+		Time:       time.Now().UTC(),
+	}
+	baseURL := strings.TrimRight(s.config.APIServer.BaseURL, "/")
+
+	switch jobType {
+	case contracts.JobTypeBuild:
+		build, err := s.databaseClient.GetPipelineBuildByID(ctx, repoSource, repoOwner, repoName, jobID, false)
+		if err != nil {
+			return err
+		}
+		event.Event = api.NotificationEventTypeBuild
+		event.Link = fmt.Sprintf("%v/pipelines/%v/%v/%v/builds/%v/logs", baseURL, repoSource, repoOwner, repoName, jobID)
+		if build != nil {
+			event.RepoBranch = build.RepoBranch
+			event.RepoRevision = build.RepoRevision
+			event.Version = build.BuildVersion
+		}
+
+	case contracts.JobTypeRelease:
+		release, err := s.databaseClient.GetPipelineRelease(ctx, repoSource, repoOwner, repoName, jobID)
+		if err != nil {
+			return err
+		}
+		event.Event = api.NotificationEventTypeRelease
+		event.Link = fmt.Sprintf("%v/pipelines/%v/%v/%v/releases/%v/logs", baseURL, repoSource, repoOwner, repoName, jobID)
+		if release != nil {
+			event.Name = release.Name
+			event.Action = release.Action
+			event.Version = release.ReleaseVersion
+		}
+
+	case contracts.JobTypeBot:
+		bot, err := s.databaseClient.GetPipelineBot(ctx, repoSource, repoOwner, repoName, jobID)
+		if err != nil {
+			return err
+		}
+		event.Event = api.NotificationEventTypeBot
+		event.Link = fmt.Sprintf("%v/pipelines/%v/%v/%v/bots/%v/logs", baseURL, repoSource, repoOwner, repoName, jobID)
+		if bot != nil {
+			event.Name = bot.Name
+		}
+
+	default:
+		return fmt.Errorf("Job type %v has no notifications", jobType)
+	}
+
+	// routing by organization and label uses those of the pipeline
+	pipeline, err := s.databaseClient.GetPipeline(ctx, repoSource, repoOwner, repoName, map[api.FilterType][]string{}, true)
+	if err != nil {
+		return err
+	}
+	if pipeline != nil {
+		event.Labels = pipeline.Labels
+		event.Organizations = getNotificationOrganizations(pipeline.Organizations)
+	}
+
+	return s.queueNotificationDeliveries(ctx, event)
+}
+
+func (s *service) DispatchNotification(ctx context.Context, notification contracts.NotificationRecord) (err error) {
+
+	if !s.notificationsEnabled() {
+		return nil
+	}
+
+	event := NotificationEvent{
+		Event:         api.NotificationEventTypeNotification,
+		ID:            notification.ID,
+		Organizations: getNotificationOrganizations(notification.Organizations),
+		Notification:  &notification,
+		Time:          time.Now().UTC(),
+	}
+	if notification.PipelineDetail != nil {
+		event.Status = notification.PipelineDetail.Status
+		event.RepoBranch = notification.PipelineDetail.Branch
+		event.RepoRevision = notification.PipelineDetail.Revision
+		event.Version = notification.PipelineDetail.Version
+	}
+
+	// notifications linked to a pipeline are routed by its labels as well
+	if notification.LinkType == contracts.NotificationLinkTypePipeline {
+		pipelineParts := strings.Split(notification.LinkID, "/")
+		if len(pipelineParts) == 3 {
+			event.RepoSource, event.RepoOwner, event.RepoName = pipelineParts[0], pipelineParts[1], pipelineParts[2]
+			event.Link = fmt.Sprintf("%v/pipelines/%v", strings.TrimRight(s.config.APIServer.BaseURL, "/"), notification.LinkID)
+
+			pipeline, err := s.databaseClient.GetPipeline(ctx, event.RepoSource, event.RepoOwner, event.RepoName, map[api.FilterType][]string{}, true)
+			if err != nil {
+				return err
+			}
+			if pipeline != nil {
+				event.Labels = pipeline.Labels
+				if len(event.Organizations) == 0 {
+					event.Organizations = getNotificationOrganizations(pipeline.Organizations)
+				}
+			}
+		}
+	}
+
+	return s.queueNotificationDeliveries(ctx, event)
+}
+
+func (s *service) DeliverNotifications(ctx context.Context) (err error) {
+
+	if !s.notificationsEnabled() {
+		return nil
+	}
+
+	deliveries, err := s.databaseClient.PickNotificationDeliveries(ctx, s.getNotificationBatchSize(), time.Now().UTC().Add(-notificationDeliveryStaleAfter))
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deliveries {
+		err = s.deliverNotification(ctx, d)
+		if err != nil {
+			// the delivery gets picked up again once it's stale; that shouldn't hold up the rest of the batch
+			log.Warn().Err(err).Msgf("Failed storing outcome of delivering notification %v to channel %v", d.ID, d.Channel)
+		}
+	}
+
+	return nil
+}
+
+// getNotificationBatchSize limits the configured batch size so all deliveries in a batch are sent before they're considered
+// stale, since another replica would pick and send them again
+func (s *service) getNotificationBatchSize() int {
+
+	deliveryTimeout := time.Duration(s.config.Notifications.TimeoutSeconds) * time.Second
+	for _, ch := range s.config.Notifications.Channels {
+		if ch != nil && ch.Type == api.NotificationChannelTypeEmail && notificationEmailTimeout > deliveryTimeout {
+			deliveryTimeout = notificationEmailTimeout
+		}
+	}
+	if deliveryTimeout <= 0 {
+		return s.config.Notifications.BatchSize
+	}
+
+	// leave one delivery's worth of time for storing the outcomes
+	maxBatchSize := int(notificationDeliveryStaleAfter/deliveryTimeout) - 1
+	if maxBatchSize < 1 {
+		maxBatchSize = 1
+	}
+	if s.config.Notifications.BatchSize > maxBatchSize {
+		return maxBatchSize
+	}
+
+	return s.config.Notifications.BatchSize
+}
+
+func (s *service) notificationsEnabled() bool {
+	return s.config.Notifications != nil && s.config.Notifications.Enable
+}
+
+// queueNotificationDeliveries stores a delivery for each channel the event is routed to, for DeliverNotifications to send
+func (s *service) queueNotificationDeliveries(ctx context.Context, event NotificationEvent) (err error) {
+
+	channels := s.config.Notifications.GetChannels(event.Event, event.Status, event.Organizations, event.Labels)
+	if len(channels) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range channels {
+		_, err = s.databaseClient.InsertNotificationDelivery(ctx, database.NotificationDelivery{
+			Channel:     ch.Name,
+			ChannelType: string(ch.Type),
+			EventType:   string(event.Event),
+			RepoSource:  event.RepoSource,
+			RepoOwner:   event.RepoOwner,
+			RepoName:    event.RepoName,
+			SourceID:    event.ID,
+			Payload:     payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliverNotification makes a single attempt to send a delivery and stores the outcome; failed attempts are retried with
+// exponential backoff until the maximum number of attempts is reached
+func (s *service) deliverNotification(ctx context.Context, delivery *database.NotificationDelivery) (err error) {
+
+	delivery.Attempts++
+	sendErr := s.sendNotification(ctx, *delivery)
+	now := time.Now().UTC()
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = database.NotificationDeliveryStatusSucceeded
+		delivery.ErrorDetails = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= s.config.Notifications.MaxAttempts || errors.Is(sendErr, ErrNotificationChannelNotFound):
+		delivery.Status = database.NotificationDeliveryStatusFailed
+		delivery.ErrorDetails = sendErr.Error()
+	default:
+		delivery.Status = database.NotificationDeliveryStatusPending
+		delivery.ErrorDetails = sendErr.Error()
+		delivery.NextAttemptAt = now.Add(s.config.Notifications.GetBackoff(delivery.Attempts))
+	}
+
+	result := "succeeded"
+	if sendErr != nil {
+		log.Warn().Err(sendErr).Msgf("Failed attempt %v of delivering notification %v to channel %v", delivery.Attempts, delivery.ID, delivery.Channel)
+		result = "failed"
+	}
+	notificationDeliveriesCounter.With("channel_type", delivery.ChannelType, "result", result).Add(1)
+
+	return s.databaseClient.UpdateNotificationDelivery(ctx, *delivery)
+}
+
+func (s *service) sendNotification(ctx context.Context, delivery database.NotificationDelivery) (err error) {
+
+	channel := s.config.Notifications.GetChannel(delivery.Channel)
+	if channel == nil {
+		return ErrNotificationChannelNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.Notifications.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	switch channel.Type {
+	case api.NotificationChannelTypeWebhook:
+		headers := map[string]string{
+			"X-Ziplinee-Event":    delivery.EventType,
+			"X-Ziplinee-Delivery": delivery.ID,
+		}
+		if channel.SigningSecret != "" {
+			headers[notificationSignatureHeader] = "sha256=" + getNotificationSignature(channel.SigningSecret, delivery.Payload)
+		}
+		return postNotification(ctx, channel.URL, delivery.Payload, headers)
+
+	case api.NotificationChannelTypeSlack:
+		var event NotificationEvent
+		err = json.Unmarshal(delivery.Payload, &event)
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(map[string]string{"text": getNotificationSummary(event)})
+		if err != nil {
+			return err
+		}
+		return postNotification(ctx, channel.URL, body, map[string]string{})
+
+	case api.NotificationChannelTypeEmail:
+		var event NotificationEvent
+		err = json.Unmarshal(delivery.Payload, &event)
+		if err != nil {
+			return err
+		}
+		return sendNotificationEmail(ctx, channel, event)
+	}
+
+	return fmt.Errorf("Notification channel %v has unsupported type %v", channel.Name, channel.Type)
+}
+
+// postNotification posts a json body and fails on any response other than 2xx
+func postNotification(ctx context.Context, url string, body []byte, headers map[string]string) (err error) {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("Posting notification to %v returned status %v", url, response.StatusCode)
+	}
+
+	return nil
+}
+
+func sendNotificationEmail(ctx context.Context, channel *api.NotificationChannelConfig, event NotificationEvent) (err error) {
+
+	ctx, cancel := context.WithTimeout(ctx, notificationEmailTimeout)
+	defer cancel()
+
+	summary := getNotificationSummary(event)
+	subject := strings.SplitN(summary, "\n", 2)[0]
+
+	message := strings.Join([]string{
+		"From: " + channel.SMTP.From,
+		"To: " + strings.Join(channel.Recipients, ", "),
+		"Subject: " + subject,
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		summary,
+	}, "\r\n")
+
+	dialer := net.Dialer{Timeout: notificationEmailTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(channel.SMTP.Host, strconv.Itoa(channel.SMTP.Port)))
+	if err != nil {
+		return err
+	}
+
+	// the deadline applies to the whole smtp conversation, not just to connecting
+	if deadline, ok := ctx.Deadline(); ok {
+		err = conn.SetDeadline(deadline)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, channel.SMTP.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	// same as smtp.SendMail, use tls if the server supports it and authenticate if credentials are configured
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: channel.SMTP.Host})
+		if err != nil {
+			return err
+		}
+	}
+	if channel.SMTP.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("Smtp server %v of notification channel %v doesn't support authentication", channel.SMTP.Host, channel.Name)
+		}
+		err = client.Auth(smtp.PlainAuth("", channel.SMTP.Username, channel.SMTP.Password, channel.SMTP.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(channel.SMTP.From)
+	if err != nil {
+		return err
+	}
+	for _, r := range channel.Recipients {
+		err = client.Rcpt(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write([]byte(message))
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// getNotificationSignature returns the hex encoded hmac-sha256 of a payload, for receivers to verify it was sent by this api
+func getNotificationSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// getNotificationSummary returns a human readable description of an event, for channels that don't receive the event itself
+func getNotificationSummary(event NotificationEvent) (summary string) {
+
+	pipeline := fmt.Sprintf("%v/%v/%v", event.RepoSource, event.RepoOwner, event.RepoName)
+
+	switch event.Event {
+	case api.NotificationEventTypeBuild:
+		summary = fmt.Sprintf("Build %v of %v on branch %v %v", event.Version, pipeline, event.RepoBranch, event.Status)
+	case api.NotificationEventTypeRelease:
+		target := event.Name
+		if event.Action != "" {
+			target = fmt.Sprintf("%v (%v)", event.Name, event.Action)
+		}
+		summary = fmt.Sprintf("Release %v of %v to %v %v", event.Version, pipeline, target, event.Status)
+	case api.NotificationEventTypeBot:
+		summary = fmt.Sprintf("Bot %v of %v %v", event.Name, pipeline, event.Status)
+	default:
+		subject := pipeline
+		if event.Notification != nil && event.Notification.LinkType != contracts.NotificationLinkTypePipeline {
+			subject = event.Notification.LinkID
+		}
+		summary = fmt.Sprintf("Notification for %v", subject)
+		if event.Notification != nil {
+			for _, n := range event.Notification.Notifications {
+				summary += fmt.Sprintf("\n%v: %v", n.Level, n.Message)
+			}
+		}
+	}
+
+	if event.Link != "" {
+		summary += "\n" + event.Link
+	}
+
+	return summary
+}
+
+func getNotificationOrganizations(organizations []*contracts.Organization) (names []string) {
+	names = []string{}
+	for _, o := range organizations {
+		if o != nil {
+			names = append(names, o.Name)
+		}
+	}
+
+	return names
+}
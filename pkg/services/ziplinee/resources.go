@@ -0,0 +1,338 @@
+package ziplinee
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
+	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
+)
+
+// ExplainJobResources sizes the resources of the next job of a pipeline and explains how; name is the branch for builds, the
+// release target for releases and the bot name for bots, action the release action if any
+func (s *service) ExplainJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, name, action string) (explanation *JobResourcesExplanation, err error) {
+	switch jobType {
+	case contracts.JobTypeBuild:
+		return s.explainBuildJobResources(ctx, repoSource, repoOwner, repoName, name), nil
+	case contracts.JobTypeRelease:
+		return s.explainReleaseJobResources(ctx, repoSource, repoOwner, repoName, name, action), nil
+	case contracts.JobTypeBot:
+		return s.explainBotJobResources(ctx, repoSource, repoOwner, repoName, name), nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrInvalidJobType, jobType)
+}
+
+func (s *service) getBuildJobResources(ctx context.Context, build contracts.Build) database.JobResources {
+	return s.explainBuildJobResources(ctx, build.RepoSource, build.RepoOwner, build.RepoName, build.RepoBranch).getJobResources()
+}
+
+func (s *service) getReleaseJobResources(ctx context.Context, release contracts.Release) database.JobResources {
+	return s.explainReleaseJobResources(ctx, release.RepoSource, release.RepoOwner, release.RepoName, release.Name, release.Action).getJobResources()
+}
+
+func (s *service) getBotJobResources(ctx context.Context, bot contracts.Bot) database.JobResources {
+	return s.explainBotJobResources(ctx, bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.Name).getJobResources()
+}
+
+// explainBuildJobResources sizes a build on previous builds of its branch, or of all branches if the branch has too few of them
+func (s *service) explainBuildJobResources(ctx context.Context, repoSource, repoOwner, repoName, repoBranch string) *JobResourcesExplanation {
+
+	// define resource request and limit values to fit reasonably well inside a n1-standard-8 (8 vCPUs, 30 GB memory) machine
+	defaultCPUCores := s.config.Jobs.DefaultCPUCores
+	if defaultCPUCores == 0 {
+		defaultCPUCores = s.config.Jobs.MaxCPUCores
+	}
+	defaultMemory := s.config.Jobs.DefaultMemoryBytes
+	if defaultMemory == 0 {
+		defaultMemory = s.config.Jobs.MaxMemoryBytes
+	}
+
+	explanation := &JobResourcesExplanation{
+		JobType:       contracts.JobTypeBuild,
+		CPURequest:    defaultCPUCores,
+		CPULimit:      s.config.Jobs.MaxCPUCores,
+		MemoryRequest: defaultMemory,
+		MemoryLimit:   s.config.Jobs.MaxMemoryBytes,
+	}
+
+	sizing := s.getJobSizingConfig()
+
+	usages, err := s.databaseClient.GetPipelineBuildResourceUsages(ctx, repoSource, repoOwner, repoName, repoBranch, sizing.LastNRecords)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed retrieving resource usage for recent builds of %v/%v/%v branch %v, using defaults...", repoSource, repoOwner, repoName, repoBranch)
+		explanation.addReason("Retrieving the resource usage of previous builds failed, using the defaults")
+		return explanation
+	}
+
+	explanation.History = fmt.Sprintf("builds of branch %v", repoBranch)
+	latest := getLatestJobResourceUsage(usages)
+
+	if len(usages) < sizing.MinRecords {
+		allUsages, err := s.databaseClient.GetPipelineBuildResourceUsages(ctx, repoSource, repoOwner, repoName, "", sizing.LastNRecords)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed retrieving resource usage for recent builds of %v/%v/%v, using builds of branch %v only...", repoSource, repoOwner, repoName, repoBranch)
+		} else if len(allUsages) > len(usages) {
+			explanation.addReason(fmt.Sprintf("Branch %v has only %v previous builds with measured resource usage, using builds of all branches instead", repoBranch, len(usages)))
+			explanation.History = "builds of all branches"
+			usages = allUsages
+		}
+	}
+
+	s.sizeJobResources(explanation, usages, latest, true)
+
+	return explanation
+}
+
+// explainReleaseJobResources sizes a release on previous releases of its target and action, or of all actions of the target if there are
+// too few of them
+func (s *service) explainReleaseJobResources(ctx context.Context, repoSource, repoOwner, repoName, targetName, actionName string) *JobResourcesExplanation {
+
+	// define resource request and limit values to fit reasonably well inside a n1-standard-8 (8 vCPUs, 30 GB memory) machine
+	explanation := &JobResourcesExplanation{
+		JobType:       contracts.JobTypeRelease,
+		CPURequest:    s.config.Jobs.MaxCPUCores,
+		CPULimit:      s.config.Jobs.MaxCPUCores,
+		MemoryRequest: s.config.Jobs.MaxMemoryBytes,
+		MemoryLimit:   s.config.Jobs.MaxMemoryBytes,
+	}
+
+	sizing := s.getJobSizingConfig()
+
+	usages, err := s.databaseClient.GetPipelineReleaseResourceUsages(ctx, repoSource, repoOwner, repoName, targetName, actionName, sizing.LastNRecords)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed retrieving resource usage for recent releases of %v/%v/%v target %v, using defaults...", repoSource, repoOwner, repoName, targetName)
+		explanation.addReason("Retrieving the resource usage of previous releases failed, using the defaults")
+		return explanation
+	}
+
+	explanation.History = fmt.Sprintf("releases to %v", targetName)
+	if actionName != "" {
+		explanation.History = fmt.Sprintf("releases to %v with action %v", targetName, actionName)
+	}
+	latest := getLatestJobResourceUsage(usages)
+
+	if actionName != "" && len(usages) < sizing.MinRecords {
+		allUsages, err := s.databaseClient.GetPipelineReleaseResourceUsages(ctx, repoSource, repoOwner, repoName, targetName, "", sizing.LastNRecords)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed retrieving resource usage for recent releases of %v/%v/%v target %v, using action %v only...", repoSource, repoOwner, repoName, targetName, actionName)
+		} else if len(allUsages) > len(usages) {
+			explanation.addReason(fmt.Sprintf("Action %v has only %v previous releases with measured resource usage, using releases with all actions instead", actionName, len(usages)))
+			explanation.History = fmt.Sprintf("releases to %v", targetName)
+			usages = allUsages
+		}
+	}
+
+	s.sizeJobResources(explanation, usages, latest, false)
+
+	return explanation
+}
+
+// explainBotJobResources sizes a bot on previous runs of the same bot
+func (s *service) explainBotJobResources(ctx context.Context, repoSource, repoOwner, repoName, botName string) *JobResourcesExplanation {
+
+	// define resource request and limit values to fit reasonably well inside a n1-standard-8 (8 vCPUs, 30 GB memory) machine
+	explanation := &JobResourcesExplanation{
+		JobType:       contracts.JobTypeBot,
+		CPURequest:    s.config.Jobs.MaxCPUCores,
+		CPULimit:      s.config.Jobs.MaxCPUCores,
+		MemoryRequest: s.config.Jobs.MaxMemoryBytes,
+		MemoryLimit:   s.config.Jobs.MaxMemoryBytes,
+	}
+
+	sizing := s.getJobSizingConfig()
+
+	usages, err := s.databaseClient.GetPipelineBotResourceUsages(ctx, repoSource, repoOwner, repoName, botName, sizing.LastNRecords)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed retrieving resource usage for recent bots of %v/%v/%v bot %v, using defaults...", repoSource, repoOwner, repoName, botName)
+		explanation.addReason("Retrieving the resource usage of previous bot runs failed, using the defaults")
+		return explanation
+	}
+
+	explanation.History = fmt.Sprintf("runs of bot %v", botName)
+
+	s.sizeJobResources(explanation, usages, getLatestJobResourceUsage(usages), false)
+
+	return explanation
+}
+
+// sizeJobResources overrides the default requests with a percentile of the max usage of previous jobs - or the usage the trend predicts
+// for the next job if higher - and bumps them up if the latest job ran out of memory or failed while its cpu got throttled
+func (s *service) sizeJobResources(explanation *JobResourcesExplanation, usages []*database.JobResourceUsage, latest *database.JobResourceUsage, applyLimitRatios bool) {
+
+	sizing := s.getJobSizingConfig()
+	explanation.NrRecords = len(usages)
+
+	sizedCPU, sizedMemory := false, false
+
+	if len(usages) < sizing.MinRecords {
+		explanation.addReason(fmt.Sprintf("Only %v previous jobs have measured resource usage and at least %v are needed, using the defaults", len(usages), sizing.MinRecords))
+	} else {
+		// usages are ordered newest first, the trend needs them in chronological order
+		cpuUsages, memoryUsages := []float64{}, []float64{}
+		for i := len(usages) - 1; i >= 0; i-- {
+			if usages[i].CPUMaxUsage > 0 {
+				cpuUsages = append(cpuUsages, usages[i].CPUMaxUsage)
+			}
+			if usages[i].MemoryMaxUsage > 0 {
+				memoryUsages = append(memoryUsages, usages[i].MemoryMaxUsage)
+			}
+		}
+
+		if len(cpuUsages) > 0 {
+			desiredCPU := getPercentile(cpuUsages, sizing.CPUPercentile)
+			explanation.addReason(fmt.Sprintf("The %vth percentile of the max cpu usage of %v previous jobs is %.3f cores", sizing.CPUPercentile, len(cpuUsages), desiredCPU))
+
+			if sizing.IsTrendExtrapolated() {
+				if expectedCPU := getExtrapolatedValue(cpuUsages); expectedCPU > desiredCPU {
+					desiredCPU = expectedCPU
+					explanation.addReason(fmt.Sprintf("Cpu usage is trending upwards, the next job is expected to use %.3f cores", desiredCPU))
+				}
+			}
+
+			explanation.CPURequest = desiredCPU * s.config.Jobs.CPURequestRatio
+			explanation.addReason(fmt.Sprintf("The cpu request is %v times that usage", s.config.Jobs.CPURequestRatio))
+			sizedCPU = true
+		}
+
+		if len(memoryUsages) > 0 {
+			desiredMemory := getPercentile(memoryUsages, sizing.MemoryPercentile)
+			explanation.addReason(fmt.Sprintf("The %vth percentile of the max memory usage of %v previous jobs is %.0f bytes", sizing.MemoryPercentile, len(memoryUsages), desiredMemory))
+
+			if sizing.IsTrendExtrapolated() {
+				if expectedMemory := getExtrapolatedValue(memoryUsages); expectedMemory > desiredMemory {
+					desiredMemory = expectedMemory
+					explanation.addReason(fmt.Sprintf("Memory usage is trending upwards, the next job is expected to use %.0f bytes", desiredMemory))
+				}
+			}
+
+			explanation.MemoryRequest = desiredMemory * s.config.Jobs.MemoryRequestRatio
+			explanation.addReason(fmt.Sprintf("The memory request is %v times that usage", s.config.Jobs.MemoryRequestRatio))
+			sizedMemory = true
+		}
+	}
+
+	// bump up the request that made the latest job fail, even if there's too little history to size on
+	if latest != nil && latest.OOMKilled {
+		bumpedMemory := math.Max(latest.MemoryRequest, latest.MemoryMaxUsage) * sizing.OOMBumpRatio
+		if bumpedMemory > explanation.MemoryRequest {
+			explanation.MemoryRequest = bumpedMemory
+			explanation.addReason(fmt.Sprintf("The previous job ran out of memory, raising the memory request %v times its request to %.0f bytes", sizing.OOMBumpRatio, bumpedMemory))
+			sizedMemory = true
+		}
+	}
+	if latest != nil && latest.CPUThrottled && latest.Status == contracts.StatusFailed {
+		bumpedCPU := math.Max(latest.CPURequest, latest.CPUMaxUsage) * sizing.CPUThrottledBumpRatio
+		if bumpedCPU > explanation.CPURequest {
+			explanation.CPURequest = bumpedCPU
+			explanation.addReason(fmt.Sprintf("The previous job failed while its cpu was throttled, raising the cpu request %v times its request to %.3f cores", sizing.CPUThrottledBumpRatio, bumpedCPU))
+			sizedCPU = true
+		}
+	}
+
+	// only override cpu and memory request values if they're within min and max
+	if sizedCPU {
+		if explanation.CPURequest <= s.config.Jobs.MinCPUCores {
+			explanation.CPURequest = s.config.Jobs.MinCPUCores
+			explanation.addReason(fmt.Sprintf("The cpu request is raised to the minimum of %v cores", s.config.Jobs.MinCPUCores))
+		} else if explanation.CPURequest >= s.config.Jobs.MaxCPUCores {
+			explanation.CPURequest = s.config.Jobs.MaxCPUCores
+			explanation.addReason(fmt.Sprintf("The cpu request is capped at the maximum of %v cores", s.config.Jobs.MaxCPUCores))
+		}
+
+		if applyLimitRatios {
+			if s.config.Jobs.CPULimitRatio > 1.0 {
+				explanation.CPULimit = explanation.CPURequest * s.config.Jobs.CPULimitRatio
+				explanation.addReason(fmt.Sprintf("The cpu limit is %v times the cpu request", s.config.Jobs.CPULimitRatio))
+			} else if explanation.CPURequest > explanation.CPULimit {
+				// keep limit at default, unless cpu request is larger
+				explanation.CPULimit = explanation.CPURequest
+			}
+		}
+	}
+
+	if sizedMemory {
+		if explanation.MemoryRequest <= s.config.Jobs.MinMemoryBytes {
+			explanation.MemoryRequest = s.config.Jobs.MinMemoryBytes
+			explanation.addReason(fmt.Sprintf("The memory request is raised to the minimum of %v bytes", s.config.Jobs.MinMemoryBytes))
+		} else if explanation.MemoryRequest >= s.config.Jobs.MaxMemoryBytes {
+			explanation.MemoryRequest = s.config.Jobs.MaxMemoryBytes
+			explanation.addReason(fmt.Sprintf("The memory request is capped at the maximum of %v bytes", s.config.Jobs.MaxMemoryBytes))
+		}
+
+		if applyLimitRatios {
+			if s.config.Jobs.MemoryLimitRatio > 1.0 {
+				explanation.MemoryLimit = explanation.MemoryRequest * s.config.Jobs.MemoryLimitRatio
+				explanation.addReason(fmt.Sprintf("The memory limit is %v times the memory request", s.config.Jobs.MemoryLimitRatio))
+			} else if explanation.MemoryRequest > explanation.MemoryLimit {
+				// keep limit at default, unless memory request is larger
+				explanation.MemoryLimit = explanation.MemoryRequest
+			}
+		}
+	}
+}
+
+// getJobSizingConfig returns the sizing config, with its defaults if it isn't configured
+func (s *service) getJobSizingConfig() *api.JobSizingConfig {
+	if s.config.Jobs.Sizing != nil {
+		return s.config.Jobs.Sizing
+	}
+
+	sizing := &api.JobSizingConfig{}
+	sizing.SetDefaults()
+
+	return sizing
+}
+
+func getLatestJobResourceUsage(usages []*database.JobResourceUsage) *database.JobResourceUsage {
+	if len(usages) == 0 {
+		return nil
+	}
+
+	return usages[0]
+}
+
+// getPercentile returns the nearest-rank percentile of values
+func getPercentile(values []float64, percentile float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sortedValues := append([]float64{}, values...)
+	sort.Float64s(sortedValues)
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(sortedValues))))
+	if rank < 1 {
+		rank = 1
+	} else if rank > len(sortedValues) {
+		rank = len(sortedValues)
+	}
+
+	return sortedValues[rank-1]
+}
+
+// getExtrapolatedValue fits a line through chronologically ordered values with least squares and returns its value for the next one
+func getExtrapolatedValue(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	n := float64(len(values))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, v := range values {
+		x := float64(i)
+		sumX += x
+		sumY += v
+		sumXY += x * v
+		sumXX += x * x
+	}
+
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / n
+
+	return intercept + slope*n
+}
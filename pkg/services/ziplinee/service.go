@@ -2,6 +2,7 @@ package ziplinee
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -9,10 +10,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/bigquery"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/bitbucketapi"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/builderapi"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/cloudsourceapi"
@@ -30,6 +33,15 @@ import (
 
 const (
 	releaseNotAllowed = "Release not allowed on this branch"
+
+	// queuedJobsBatchSize is the maximum number of queued jobs considered for dispatching in a single run
+	queuedJobsBatchSize = 500
+
+	// jobQueueLockName is the lock that makes only one replica at a time count active jobs and dispatch queued ones
+	jobQueueLockName = "job-queue-dispatcher"
+
+	// jobQueueLockTTL bounds a single dispatching run, after which another replica can take over the lock
+	jobQueueLockTTL = 2 * time.Minute
 )
 
 var (
@@ -37,6 +49,12 @@ var (
 	ErrNoReleaseCreated  = errors.New("No release is created")
 	ErrNoBotCreated      = errors.New("No bot is created")
 	ErrReleaseNotAllowed = &ReleaseError{Message: releaseNotAllowed}
+
+	// ErrJobNotCancelable indicates a job has already finished and can't be canceled anymore
+	ErrJobNotCancelable = errors.New("The job has a status that cannot be canceled")
+
+	// ErrInvalidJobType indicates a job type other than build, release or bot
+	ErrInvalidJobType = errors.New("The job type is invalid")
 )
 
 type ReleaseError struct {
@@ -80,14 +98,32 @@ type Service interface {
 	UpdateBuildStatus(ctx context.Context, event contracts.ZiplineeCiBuilderEvent) (err error)
 	UpdateJobResources(ctx context.Context, event contracts.ZiplineeCiBuilderEvent) (err error)
 	GetEventsForJobEnvvars(ctx context.Context, triggers []manifest.ZiplineeTrigger, events []manifest.ZiplineeEvent) (triggersAsEvents []manifest.ZiplineeEvent, err error)
+	DispatchQueuedJobs(ctx context.Context) (err error)
+	QueueMigration(ctx context.Context, task database.MigrationTask, restart string) (queuedTask *database.MigrationTask, err error)
+	RunMigrationTasks(ctx context.Context) (err error)
+	RollbackMigration(ctx context.Context, task database.MigrationTask) (rollback *database.MigrationRollback, err error)
+	SearchPipelineLogs(ctx context.Context, repoSource, repoOwner, repoName string, query LogSearchQuery) (result *LogSearchResult, err error)
+	PruneExpiredLogs(ctx context.Context, dryRun bool) (report *LogRetentionReport, err error)
+	PrunePipelineExpiredLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (items []*LogRetentionReportItem, err error)
+	QueueLogReconciliation(ctx context.Context, reconciliation database.LogReconciliation) (queuedReconciliation *database.LogReconciliation, err error)
+	RunLogReconciliations(ctx context.Context) (err error)
+	ReconcilePipelineLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (result *LogReconciliationResult, err error)
+	CancelJob(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (canceledStatus contracts.Status, err error)
+	DispatchJobStatus(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (err error)
+	DispatchNotification(ctx context.Context, notification contracts.NotificationRecord) (err error)
+	DeliverNotifications(ctx context.Context) (err error)
+	ExplainJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, name, action string) (explanation *JobResourcesExplanation, err error)
+	GetCostReport(ctx context.Context, dimension CostDimension, labelKey string, from, to time.Time, filters map[api.FilterType][]string) (report *CostReport, err error)
+	GetDoraMetrics(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (metrics *DoraMetrics, err error)
 }
 
 // NewService returns a new ziplinee.Service
-func NewService(config *api.APIConfig, databaseClient database.Client, secretHelper crypt.SecretHelper, prometheusClient prometheus.Client, cloudStorageClient cloudstorage.Client, builderapiClient builderapi.Client, githubJobVarsFunc func(context.Context, string, string, string) (string, error), bitbucketJobVarsFunc func(context.Context, string, string, string) (string, error), cloudsourceJobVarsFunc func(context.Context, string, string, string) (string, error)) Service {
+func NewService(config *api.APIConfig, databaseClient database.Client, bigqueryClient bigquery.Client, secretHelper crypt.SecretHelper, prometheusClient prometheus.Client, cloudStorageClient cloudstorage.Client, builderapiClient builderapi.Client, githubJobVarsFunc func(context.Context, string, string, string) (string, error), bitbucketJobVarsFunc func(context.Context, string, string, string) (string, error), cloudsourceJobVarsFunc func(context.Context, string, string, string) (string, error)) Service {
 
 	return &service{
 		config:                 config,
 		databaseClient:         databaseClient,
+		bigqueryClient:         bigqueryClient,
 		secretHelper:           secretHelper,
 		prometheusClient:       prometheusClient,
 		cloudStorageClient:     cloudStorageClient,
@@ -96,12 +132,14 @@ func NewService(config *api.APIConfig, databaseClient database.Client, secretHel
 		bitbucketJobVarsFunc:   bitbucketJobVarsFunc,
 		cloudsourceJobVarsFunc: cloudsourceJobVarsFunc,
 		triggerConcurrency:     5,
+		instanceID:             uuid.New().String(),
 	}
 }
 
 type service struct {
 	config                 *api.APIConfig
 	databaseClient         database.Client
+	bigqueryClient         bigquery.Client
 	secretHelper           crypt.SecretHelper
 	prometheusClient       prometheus.Client
 	cloudStorageClient     cloudstorage.Client
@@ -110,6 +148,9 @@ type service struct {
 	bitbucketJobVarsFunc   func(context.Context, string, string, string) (string, error)
 	cloudsourceJobVarsFunc func(context.Context, string, string, string) (string, error)
 	triggerConcurrency     int64
+
+	// instanceID identifies this replica as holder of database locks
+	instanceID string
 }
 
 func (s *service) CreateBuild(ctx context.Context, build contracts.Build) (createdBuild *contracts.Build, err error) {
@@ -139,7 +180,7 @@ func (s *service) CreateBuild(ctx context.Context, build contracts.Build) (creat
 	// set build status
 	buildStatus := contracts.StatusFailed
 	if hasValidManifest && invalidSecretsErr == nil {
-		buildStatus = contracts.StatusPending
+		buildStatus = s.getInitialJobStatus()
 	}
 
 	// inject build stages
@@ -254,12 +295,16 @@ func (s *service) CreateBuild(ctx context.Context, build contracts.Build) (creat
 	// create ci builder job
 	if hasValidManifest && invalidSecretsErr == nil {
 		log.Debug().Msgf("Pipeline %v/%v/%v revision %v has valid manifest, creating build job...", build.RepoSource, build.RepoOwner, build.RepoName, build.RepoRevision)
-		// create ci builder job
-		_, err = s.builderapiClient.CreateCiBuilderJob(ctx, ciBuilderParams)
+		// create ci builder job or queue it until it fits within the concurrency limits
+		err = s.createOrQueueCiBuilderJob(ctx, ciBuilderParams)
 		if err != nil {
 			return
 		}
 
+		if s.config.Jobs.CancelSupersededBuilds {
+			s.cancelSupersededBuilds(ctx, *createdBuild)
+		}
+
 		// handle triggers
 		go func() {
 			// create new context to avoid cancellation impacting execution
@@ -315,7 +360,7 @@ func (s *service) CreateBuild(ctx context.Context, build contracts.Build) (creat
 			log.Warn().Err(err).Msgf("Failed inserting build log for invalid manifest")
 		}
 
-		if s.config.APIServer.WriteLogToCloudStorage() {
+		if s.config.APIServer.WriteLogToObjectStorage() {
 			err = s.cloudStorageClient.InsertBuildLog(ctx, insertedBuildLog)
 			if err != nil {
 				log.Warn().Err(err).Msgf("Failed inserting build log into cloud storage for invalid manifest")
@@ -355,7 +400,7 @@ func (s *service) CreateBuild(ctx context.Context, build contracts.Build) (creat
 			log.Warn().Err(err).Msgf("Failed inserting build log for invalid manifest")
 		}
 
-		if s.config.APIServer.WriteLogToCloudStorage() {
+		if s.config.APIServer.WriteLogToObjectStorage() {
 			err = s.cloudStorageClient.InsertBuildLog(ctx, insertedBuildLog)
 			if err != nil {
 				log.Warn().Err(err).Msgf("Failed inserting build log into cloud storage for invalid manifest")
@@ -373,6 +418,11 @@ func (s *service) FinishBuild(ctx context.Context, repoSource, repoOwner, repoNa
 		return err
 	}
 
+	err = s.DispatchJobStatus(ctx, contracts.JobTypeBuild, repoSource, repoOwner, repoName, buildID, buildStatus)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed dispatching notifications for build %v/%v/%v id %v", repoSource, repoOwner, repoName, buildID)
+	}
+
 	// handle triggers
 	go func() {
 		// create new context to avoid cancellation impacting execution
@@ -434,7 +484,7 @@ func (s *service) CreateRelease(ctx context.Context, release contracts.Release,
 	// set release status
 	releaseStatus := contracts.StatusFailed
 	if invalidSecretsErr == nil {
-		releaseStatus = contracts.StatusPending
+		releaseStatus = s.getInitialJobStatus()
 	}
 
 	// inject release stages
@@ -537,8 +587,8 @@ func (s *service) CreateRelease(ctx context.Context, release contracts.Release,
 	}
 
 	if invalidSecretsErr == nil {
-		// create ci release job
-		_, err = s.builderapiClient.CreateCiBuilderJob(ctx, ciBuilderParams)
+		// create ci release job or queue it until it fits within the concurrency limits
+		err = s.createOrQueueCiBuilderJob(ctx, ciBuilderParams)
 		if err != nil {
 			return
 		}
@@ -582,7 +632,7 @@ func (s *service) CreateRelease(ctx context.Context, release contracts.Release,
 			log.Warn().Err(err).Msgf("Failed inserting release log for manifest with restricted secrets")
 		}
 
-		if s.config.APIServer.WriteLogToCloudStorage() {
+		if s.config.APIServer.WriteLogToObjectStorage() {
 			err = s.cloudStorageClient.InsertReleaseLog(ctx, insertedReleaseLog)
 			if err != nil {
 				log.Warn().Err(err).Msgf("Failed inserting release log into cloud storage for manifest with restricted secrets")
@@ -612,6 +662,11 @@ func (s *service) FinishRelease(ctx context.Context, repoSource, repoOwner, repo
 		return err
 	}
 
+	err = s.DispatchJobStatus(ctx, contracts.JobTypeRelease, repoSource, repoOwner, repoName, releaseID, releaseStatus)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed dispatching notifications for release %v/%v/%v id %v", repoSource, repoOwner, repoName, releaseID)
+	}
+
 	// handle triggers
 	go func() {
 		// create new context to avoid cancellation impacting execution
@@ -667,7 +722,7 @@ func (s *service) CreateBot(ctx context.Context, bot contracts.Bot, mft manifest
 	// set bot status
 	botStatus := contracts.StatusFailed
 	if invalidSecretsErr == nil {
-		botStatus = contracts.StatusPending
+		botStatus = s.getInitialJobStatus()
 	}
 
 	// inject release stages
@@ -728,8 +783,8 @@ func (s *service) CreateBot(ctx context.Context, bot contracts.Bot, mft manifest
 	}
 
 	if invalidSecretsErr == nil {
-		// create ci release job
-		_, err = s.builderapiClient.CreateCiBuilderJob(ctx, ciBuilderParams)
+		// create ci release job or queue it until it fits within the concurrency limits
+		err = s.createOrQueueCiBuilderJob(ctx, ciBuilderParams)
 		if err != nil {
 			return
 		}
@@ -773,7 +828,7 @@ func (s *service) CreateBot(ctx context.Context, bot contracts.Bot, mft manifest
 			log.Warn().Err(err).Msgf("Failed inserting bot log for manifest with restricted secrets")
 		}
 
-		if s.config.APIServer.WriteLogToCloudStorage() {
+		if s.config.APIServer.WriteLogToObjectStorage() {
 			err = s.cloudStorageClient.InsertBotLog(ctx, insertedBotLog)
 			if err != nil {
 				log.Warn().Err(err).Msgf("Failed inserting bot log into cloud storage for manifest with restricted secrets")
@@ -790,9 +845,82 @@ func (s *service) FinishBot(ctx context.Context, repoSource, repoOwner, repoName
 		return err
 	}
 
+	err = s.DispatchJobStatus(ctx, contracts.JobTypeBot, repoSource, repoOwner, repoName, botID, botStatus)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed dispatching notifications for bot %v/%v/%v id %v", repoSource, repoOwner, repoName, botID)
+	}
+
 	return nil
 }
 
+func (s *service) CancelJob(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (canceledStatus contracts.Status, err error) {
+
+	if !jobCanBeCanceled(status) {
+		return status, ErrJobNotCancelable
+	}
+
+	job := &database.QueuedJob{JobType: jobType, JobID: jobID, RepoSource: repoSource, RepoOwner: repoOwner, RepoName: repoName}
+
+	if status == api.StatusQueued {
+		deleted, deleteErr := s.databaseClient.DeleteQueuedJob(ctx, jobType, jobID)
+		if deleteErr != nil {
+			return status, deleteErr
+		}
+		if !deleted {
+			// a replica dispatched the job after its status was read, so its builder job needs canceling like a pending one
+			status = contracts.StatusPending
+		}
+	}
+
+	switch status {
+	case contracts.StatusCanceling:
+		// apparently cancel was already clicked, but somehow the job didn't update the status to canceled
+		_ = s.builderapiClient.CancelCiBuilderJob(ctx, s.builderapiClient.GetJobName(ctx, jobType, repoOwner, repoName, jobID))
+		canceledStatus = contracts.StatusCanceled
+		_ = s.updateJobStatus(ctx, job, canceledStatus)
+
+	case api.StatusQueued:
+		// no builder job has been created yet, so removing it from the queue is enough
+		canceledStatus = contracts.StatusCanceled
+		err = s.updateJobStatus(ctx, job, canceledStatus)
+		if err != nil {
+			return status, err
+		}
+
+	default:
+		// set status 'canceling' and cancel the builder job
+		cancelErr := s.builderapiClient.CancelCiBuilderJob(ctx, s.builderapiClient.GetJobName(ctx, jobType, repoOwner, repoName, jobID))
+		canceledStatus = contracts.StatusCanceling
+		if status == contracts.StatusPending {
+			// job might not have created a builder yet, so set status to canceled straightaway
+			canceledStatus = contracts.StatusCanceled
+		}
+		err = s.updateJobStatus(ctx, job, canceledStatus)
+		if err != nil {
+			return status, err
+		}
+
+		// canceling the job failed because it no longer existed we should set canceled status right after having set it to canceling
+		if errors.Is(cancelErr, builderapi.ErrJobNotFound) && status == contracts.StatusRunning {
+			canceledStatus = contracts.StatusCanceled
+			err = s.updateJobStatus(ctx, job, canceledStatus)
+			if err != nil {
+				return contracts.StatusCanceling, err
+			}
+		}
+	}
+
+	// jobs that are still canceling notify once their builder reports them as canceled
+	if canceledStatus == contracts.StatusCanceled {
+		err = s.DispatchJobStatus(ctx, jobType, repoSource, repoOwner, repoName, jobID, canceledStatus)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed dispatching notifications for canceled %v %v/%v/%v id %v", jobType, repoSource, repoOwner, repoName, jobID)
+		}
+	}
+
+	return canceledStatus, nil
+}
+
 func (s *service) FireGitTriggers(ctx context.Context, gitEvent manifest.ZiplineeGitEvent) error {
 
 	log.Debug().Msgf("[trigger:git(%v-%v:%v)] Checking if triggers need to be fired...", gitEvent.Repository, gitEvent.Branch, gitEvent.Event)
@@ -1626,7 +1754,7 @@ func (s *service) getSourceCodeAccessToken(ctx context.Context, repoSource, repo
 func (s *service) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) error {
 
 	nrOfGoroutines := 1
-	if s.config.APIServer.WriteLogToCloudStorage() {
+	if s.config.APIServer.WriteLogToObjectStorage() {
 		nrOfGoroutines++
 	}
 	var wg sync.WaitGroup
@@ -1646,7 +1774,7 @@ func (s *service) Rename(ctx context.Context, fromRepoSource, fromRepoOwner, fro
 		}
 	}(&wg, ctx, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName)
 
-	if s.config.APIServer.WriteLogToCloudStorage() {
+	if s.config.APIServer.WriteLogToObjectStorage() {
 		go func(wg *sync.WaitGroup, ctx context.Context, fromRepoSource, fromRepoOwner, fromRepoName, toRepoSource, toRepoOwner, toRepoName string) {
 			defer wg.Done()
 
@@ -1733,6 +1861,12 @@ func (s *service) UpdateJobResources(ctx context.Context, ciBuilderEvent contrac
 		return
 	}
 
+	// export the finished job once its resource usage is known, even if retrieving it fails
+	var jobResources database.JobResources
+	defer func() {
+		s.insertJobEvent(ctx, ciBuilderEvent, jobResources)
+	}()
+
 	if ciBuilderEvent.PodName != "" {
 
 		s.prometheusClient.AwaitScrapeInterval(ctx)
@@ -1751,9 +1885,22 @@ func (s *service) UpdateJobResources(ctx context.Context, ciBuilderEvent contrac
 
 		log.Debug().Msgf("Max memory usage for pod %v is %v", ciBuilderEvent.PodName, maxMemory)
 
-		jobResources := database.JobResources{
+		// whether the job ran out of memory or got its cpu throttled only serves sizing the next job up, so it doesn't fail the update
+		oomKilled, err := s.prometheusClient.GetOOMKilledByPodName(ctx, ciBuilderEvent.PodName)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed retrieving whether pod %v got killed for running out of memory", ciBuilderEvent.PodName)
+		}
+
+		cpuThrottledRatio, err := s.prometheusClient.GetCPUThrottledRatioByPodName(ctx, ciBuilderEvent.PodName)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed retrieving cpu throttling for pod %v", ciBuilderEvent.PodName)
+		}
+
+		jobResources = database.JobResources{
 			CPUMaxUsage:    maxCPU,
 			MemoryMaxUsage: maxMemory,
+			OOMKilled:      oomKilled,
+			CPUThrottled:   cpuThrottledRatio >= s.getJobSizingConfig().CPUThrottledThreshold,
 		}
 
 		switch ciBuilderEvent.JobType {
@@ -1784,6 +1931,272 @@ func (s *service) UpdateJobResources(ctx context.Context, ciBuilderEvent contrac
 	return nil
 }
 
+func (s *service) DispatchQueuedJobs(ctx context.Context) (err error) {
+
+	if !s.jobQueueEnabled() {
+		return nil
+	}
+
+	// counting active jobs and dispatching queued ones has to happen by one replica at a time to stay within the concurrency limits
+	locked, err := s.databaseClient.AcquireLock(ctx, jobQueueLockName, s.instanceID, jobQueueLockTTL)
+	if err != nil {
+		return errors.Wrap(err, "Failed acquiring job queue lock")
+	}
+	if !locked {
+		log.Debug().Msg("Another replica is dispatching queued jobs, skipping this run")
+		return nil
+	}
+	defer func() {
+		if releaseErr := s.databaseClient.ReleaseLock(context.Background(), jobQueueLockName, s.instanceID); releaseErr != nil {
+			log.Warn().Err(releaseErr).Msg("Failed releasing job queue lock")
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, jobQueueLockTTL)
+	defer cancel()
+
+	queuedJobs, err := s.databaseClient.GetQueuedJobs(ctx, queuedJobsBatchSize)
+	if err != nil {
+		return errors.Wrap(err, "Failed retrieving queued jobs")
+	}
+	if len(queuedJobs) == 0 {
+		return nil
+	}
+
+	activeJobs, err := s.databaseClient.GetActiveJobs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed retrieving active jobs")
+	}
+
+	counter := newJobConcurrencyCounter(*s.config.JobQueue, activeJobs)
+
+	log.Debug().Msgf("Dispatching up to %v queued jobs with %v active jobs...", len(queuedJobs), counter.total)
+
+	for _, qj := range queuedJobs {
+		if counter.globalLimitReached() {
+			log.Debug().Msgf("Reached the maximum of %v concurrent jobs, leaving remaining jobs queued", s.config.JobQueue.MaxConcurrentJobs)
+			break
+		}
+		if !counter.allows(qj.JobType, qj.RepoSource, qj.RepoOwner, qj.RepoName, qj.Organizations) {
+			continue
+		}
+
+		dispatched, err := s.dispatchQueuedJob(ctx, qj)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed dispatching queued %v %v for %v/%v/%v", qj.JobType, qj.JobID, qj.RepoSource, qj.RepoOwner, qj.RepoName)
+		}
+		if dispatched {
+			counter.add(qj.JobType, qj.RepoSource, qj.RepoOwner, qj.RepoName, qj.Organizations)
+		}
+	}
+
+	return nil
+}
+
+// cancelSupersededBuilds cancels all queued, pending and running builds for the same branch that were created before the new build
+func (s *service) cancelSupersededBuilds(ctx context.Context, newBuild contracts.Build) {
+
+	// a manual re-run of an older version shouldn't cancel builds for newer commits
+	for _, e := range newBuild.Events {
+		if e.Manual != nil {
+			return
+		}
+	}
+
+	filters := map[api.FilterType][]string{
+		api.FilterStatus: {string(api.StatusQueued), string(contracts.StatusPending), string(contracts.StatusRunning)},
+		api.FilterBranch: {newBuild.RepoBranch},
+	}
+
+	activeBuilds, err := s.databaseClient.GetPipelineBuilds(ctx, newBuild.RepoSource, newBuild.RepoOwner, newBuild.RepoName, 1, 100, filters, []api.OrderField{}, true)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed retrieving active builds for %v/%v/%v branch %v to cancel superseded builds", newBuild.RepoSource, newBuild.RepoOwner, newBuild.RepoName, newBuild.RepoBranch)
+		return
+	}
+
+	for _, b := range activeBuilds {
+		if b.ID == newBuild.ID || b.InsertedAt.After(newBuild.InsertedAt) {
+			continue
+		}
+
+		log.Info().Msgf("Canceling build %v for %v/%v/%v branch %v, it's superseded by build %v", b.ID, b.RepoSource, b.RepoOwner, b.RepoName, b.RepoBranch, newBuild.ID)
+
+		err = s.cancelBuild(ctx, b)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed canceling superseded build %v for %v/%v/%v", b.ID, b.RepoSource, b.RepoOwner, b.RepoName)
+			continue
+		}
+
+		err = s.databaseClient.UpdateBuildStatusReason(ctx, b.RepoSource, b.RepoOwner, b.RepoName, b.ID, api.StatusReasonSuperseded)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed setting status reason for superseded build %v for %v/%v/%v", b.ID, b.RepoSource, b.RepoOwner, b.RepoName)
+		}
+	}
+}
+
+// cancelBuild removes a queued build from the job queue or cancels the builder job of a pending or running build
+func (s *service) cancelBuild(ctx context.Context, build *contracts.Build) (err error) {
+
+	status := build.BuildStatus
+	if status == api.StatusQueued {
+		deleted, deleteErr := s.databaseClient.DeleteQueuedJob(ctx, contracts.JobTypeBuild, build.ID)
+		if deleteErr != nil {
+			return deleteErr
+		}
+		if deleted {
+			return s.databaseClient.UpdateBuildStatus(ctx, build.RepoSource, build.RepoOwner, build.RepoName, build.ID, contracts.StatusCanceled)
+		}
+		// a replica dispatched the build after its status was read, so its builder job needs canceling like a pending one
+		status = contracts.StatusPending
+	}
+
+	jobName := s.builderapiClient.GetJobName(ctx, contracts.JobTypeBuild, build.RepoOwner, build.RepoName, build.ID)
+	cancelErr := s.builderapiClient.CancelCiBuilderJob(ctx, jobName)
+	if cancelErr != nil && !errors.Is(cancelErr, builderapi.ErrJobNotFound) {
+		// the builder job might still be running, so leave the status alone
+		return cancelErr
+	}
+
+	buildStatus := contracts.StatusCanceling
+	if status == contracts.StatusPending || cancelErr != nil {
+		// there's no builder to report the canceled status, so set it straightaway
+		buildStatus = contracts.StatusCanceled
+	}
+	if status == contracts.StatusRunning && buildStatus == contracts.StatusCanceled {
+		// running builds can only transition to canceled via canceling
+		err = s.databaseClient.UpdateBuildStatus(ctx, build.RepoSource, build.RepoOwner, build.RepoName, build.ID, contracts.StatusCanceling)
+		if err != nil {
+			return
+		}
+	}
+
+	return s.databaseClient.UpdateBuildStatus(ctx, build.RepoSource, build.RepoOwner, build.RepoName, build.ID, buildStatus)
+}
+
+func (s *service) jobQueueEnabled() bool {
+	return s.config.JobQueue != nil && s.config.JobQueue.Enable
+}
+
+// getInitialJobStatus returns the status for a new build, release or bot with a valid manifest
+func (s *service) getInitialJobStatus() contracts.Status {
+	if s.jobQueueEnabled() {
+		return api.StatusQueued
+	}
+
+	return contracts.StatusPending
+}
+
+// createOrQueueCiBuilderJob creates the builder job straight away or stores it in the job queue if it's enabled
+func (s *service) createOrQueueCiBuilderJob(ctx context.Context, ciBuilderParams builderapi.CiBuilderParams) (err error) {
+
+	if !s.jobQueueEnabled() {
+		_, err = s.builderapiClient.CreateCiBuilderJob(ctx, ciBuilderParams)
+		return
+	}
+
+	queuedJob := database.QueuedJob{
+		JobType: ciBuilderParams.BuilderConfig.JobType,
+	}
+
+	switch ciBuilderParams.BuilderConfig.JobType {
+	case contracts.JobTypeBuild:
+		build := ciBuilderParams.BuilderConfig.Build
+		queuedJob.JobID, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, queuedJob.Organizations = build.ID, build.RepoSource, build.RepoOwner, build.RepoName, build.Organizations
+	case contracts.JobTypeRelease:
+		release := ciBuilderParams.BuilderConfig.Release
+		queuedJob.JobID, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, queuedJob.Organizations = release.ID, release.RepoSource, release.RepoOwner, release.RepoName, release.Organizations
+	case contracts.JobTypeBot:
+		bot := ciBuilderParams.BuilderConfig.Bot
+		queuedJob.JobID, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, queuedJob.Organizations = bot.ID, bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.Organizations
+	default:
+		return fmt.Errorf("Builder params have invalid JobType %v", ciBuilderParams.BuilderConfig.JobType)
+	}
+
+	// source code access tokens are short-lived, so they're retrieved again when the job gets dispatched
+	ciBuilderParams.EnvironmentVariables = nil
+
+	queuedJob.CiBuilderParams, err = json.Marshal(ciBuilderParams)
+	if err != nil {
+		return errors.Wrapf(err, "Failed marshalling builder params for %v %v", queuedJob.JobType, queuedJob.JobID)
+	}
+
+	_, err = s.databaseClient.InsertQueuedJob(ctx, queuedJob)
+	if err != nil {
+		return errors.Wrapf(err, "Failed queueing %v %v for %v/%v/%v", queuedJob.JobType, queuedJob.JobID, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName)
+	}
+
+	log.Debug().Msgf("Queued %v %v for %v/%v/%v", queuedJob.JobType, queuedJob.JobID, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName)
+
+	return nil
+}
+
+// dispatchQueuedJob takes a job from the queue and creates its builder job; it returns false if another replica already took it
+func (s *service) dispatchQueuedJob(ctx context.Context, queuedJob *database.QueuedJob) (dispatched bool, err error) {
+
+	var ciBuilderParams builderapi.CiBuilderParams
+	err = json.Unmarshal(queuedJob.CiBuilderParams, &ciBuilderParams)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed unmarshalling builder params")
+	}
+
+	claimed, err := s.databaseClient.ClaimQueuedJob(ctx, *queuedJob)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	switch queuedJob.JobType {
+	case contracts.JobTypeBuild:
+		ciBuilderParams.BuilderConfig.Build.BuildStatus = contracts.StatusPending
+	case contracts.JobTypeRelease:
+		ciBuilderParams.BuilderConfig.Release.ReleaseStatus = contracts.StatusPending
+	case contracts.JobTypeBot:
+		ciBuilderParams.BuilderConfig.Bot.BotStatus = contracts.StatusPending
+	}
+
+	ciBuilderParams.EnvironmentVariables, err = s.getSourceCodeAccessToken(ctx, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName)
+	if err == nil {
+		_, err = s.builderapiClient.CreateCiBuilderJob(ctx, ciBuilderParams)
+	}
+	if err != nil {
+		// put the job back in its place in the queue to retry it in the next run, unless it has been tried too often already
+		var requeueErr error
+		if queuedJob.Attempts+1 >= s.config.JobQueue.MaxDispatchAttempts {
+			requeueErr = fmt.Errorf("Dispatching failed %v times", queuedJob.Attempts+1)
+		} else {
+			requeueErr = s.databaseClient.RequeueJob(ctx, *queuedJob)
+		}
+		if requeueErr != nil {
+			log.Warn().Err(requeueErr).Msgf("Failed putting %v %v back in the queue, setting its status to failed", queuedJob.JobType, queuedJob.JobID)
+			if updateErr := s.updateJobStatus(ctx, queuedJob, contracts.StatusFailed); updateErr != nil {
+				log.Warn().Err(updateErr).Msgf("Failed setting status of %v %v to failed", queuedJob.JobType, queuedJob.JobID)
+			}
+		}
+		return false, err
+	}
+
+	log.Debug().Msgf("Dispatched queued %v %v for %v/%v/%v after %v", queuedJob.JobType, queuedJob.JobID, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, time.Since(queuedJob.QueuedAt))
+
+	return true, nil
+}
+
+func (s *service) updateJobStatus(ctx context.Context, queuedJob *database.QueuedJob, status contracts.Status) error {
+	switch queuedJob.JobType {
+	case contracts.JobTypeBuild:
+		return s.databaseClient.UpdateBuildStatus(ctx, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, queuedJob.JobID, status)
+	case contracts.JobTypeRelease:
+		return s.databaseClient.UpdateReleaseStatus(ctx, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, queuedJob.JobID, status)
+	case contracts.JobTypeBot:
+		return s.databaseClient.UpdateBotStatus(ctx, queuedJob.RepoSource, queuedJob.RepoOwner, queuedJob.RepoName, queuedJob.JobID, status)
+	}
+
+	return fmt.Errorf("Queued job has invalid JobType %v", queuedJob.JobType)
+}
+
+// jobCanBeCanceled returns true for jobs that haven't finished yet
+func jobCanBeCanceled(status contracts.Status) bool {
+	return status == contracts.StatusPending || status == contracts.StatusRunning || status == contracts.StatusCanceling || status == api.StatusQueued
+}
+
 func (s *service) getBuildLabels(build contracts.Build, hasValidManifest bool, mft manifest.ZiplineeManifest, pipeline *contracts.Pipeline) []contracts.Label {
 	if len(build.Labels) == 0 {
 		var labels []contracts.Label
@@ -1918,162 +2331,6 @@ func (s *service) getVersionCounter(ctx context.Context, version string, mft man
 	return counter
 }
 
-func (s *service) getBuildJobResources(ctx context.Context, build contracts.Build) database.JobResources {
-	// define resource request and limit values to fit reasonably well inside a n1-standard-8 (8 vCPUs, 30 GB memory) machine
-	defaultCPUCores := s.config.Jobs.DefaultCPUCores
-	if defaultCPUCores == 0 {
-		defaultCPUCores = s.config.Jobs.MaxCPUCores
-	}
-	defaultMemory := s.config.Jobs.DefaultMemoryBytes
-	if defaultMemory == 0 {
-		defaultMemory = s.config.Jobs.MaxMemoryBytes
-	}
-
-	jobResources := database.JobResources{
-		CPURequest:    defaultCPUCores,
-		CPULimit:      s.config.Jobs.MaxCPUCores,
-		MemoryRequest: defaultMemory,
-		MemoryLimit:   s.config.Jobs.MaxMemoryBytes,
-	}
-
-	// get max usage from previous builds
-	measuredResources, nrRecords, err := s.databaseClient.GetPipelineBuildMaxResourceUtilization(ctx, build.RepoSource, build.RepoOwner, build.RepoName, 25)
-	if err != nil {
-		log.Warn().Err(err).Msgf("Failed retrieving max resource utilization for recent builds of %v/%v/%v, using defaults...", build.RepoSource, build.RepoOwner, build.RepoName)
-	} else if nrRecords < 5 {
-		log.Debug().Msgf("Retrieved max resource utilization for recent builds of %v/%v/%v only has %v records, using defaults...", build.RepoSource, build.RepoOwner, build.RepoName, nrRecords)
-	} else {
-		log.Debug().Msgf("Retrieved max resource utilization for recent builds of %v/%v/%v, checking if they are within lower and upper bound...", build.RepoSource, build.RepoOwner, build.RepoName)
-
-		// only override cpu and memory request values if measured values are within min and max
-		if measuredResources.CPUMaxUsage > 0 {
-			desiredCPURequest := measuredResources.CPUMaxUsage * s.config.Jobs.CPURequestRatio
-			if desiredCPURequest <= s.config.Jobs.MinCPUCores {
-				jobResources.CPURequest = s.config.Jobs.MinCPUCores
-			} else if desiredCPURequest >= s.config.Jobs.MaxCPUCores {
-				jobResources.CPURequest = s.config.Jobs.MaxCPUCores
-			} else {
-				jobResources.CPURequest = desiredCPURequest
-			}
-
-			if s.config.Jobs.CPULimitRatio > 1.0 {
-				jobResources.CPULimit = jobResources.CPURequest * s.config.Jobs.CPULimitRatio
-			} else if jobResources.CPURequest > jobResources.CPULimit {
-				// keep limit at default, unless cpu request is larger
-				jobResources.CPULimit = jobResources.CPURequest
-			}
-		}
-
-		if measuredResources.MemoryMaxUsage > 0 {
-			desiredMemoryRequest := measuredResources.MemoryMaxUsage * s.config.Jobs.MemoryRequestRatio
-			if desiredMemoryRequest <= s.config.Jobs.MinMemoryBytes {
-				jobResources.MemoryRequest = s.config.Jobs.MinMemoryBytes
-			} else if desiredMemoryRequest >= s.config.Jobs.MaxMemoryBytes {
-				jobResources.MemoryRequest = s.config.Jobs.MaxMemoryBytes
-			} else {
-				jobResources.MemoryRequest = desiredMemoryRequest
-			}
-
-			if s.config.Jobs.MemoryLimitRatio > 1.0 {
-				jobResources.MemoryLimit = jobResources.MemoryRequest * s.config.Jobs.MemoryLimitRatio
-			} else if jobResources.MemoryRequest > jobResources.MemoryLimit {
-				// keep limit at default, unless memory request is larger
-				jobResources.MemoryLimit = jobResources.MemoryRequest
-			}
-		}
-	}
-
-	return jobResources
-}
-
-func (s *service) getReleaseJobResources(ctx context.Context, release contracts.Release) database.JobResources {
-
-	// define resource request and limit values to fit reasonably well inside a n1-standard-8 (8 vCPUs, 30 GB memory) machine
-	jobResources := database.JobResources{
-		CPURequest:    s.config.Jobs.MaxCPUCores,
-		CPULimit:      s.config.Jobs.MaxCPUCores,
-		MemoryRequest: s.config.Jobs.MaxMemoryBytes,
-		MemoryLimit:   s.config.Jobs.MaxMemoryBytes,
-	}
-
-	// get max usage from previous releases
-	measuredResources, nrRecords, err := s.databaseClient.GetPipelineReleaseMaxResourceUtilization(ctx, release.RepoSource, release.RepoOwner, release.RepoName, release.Name, 25)
-	if err != nil {
-		log.Warn().Err(err).Msgf("Failed retrieving max resource utilization for recent releases of %v/%v/%v target %v, using defaults...", release.RepoSource, release.RepoOwner, release.RepoName, release.Name)
-	} else if nrRecords < 5 {
-		log.Debug().Msgf("Retrieved max resource utilization for recent releases of %v/%v/%v target %v only has %v records, using defaults...", release.RepoSource, release.RepoOwner, release.RepoName, release.Name, nrRecords)
-	} else {
-		log.Debug().Msgf("Retrieved max resource utilization for recent releases of %v/%v/%v target %v, checking if they are within lower and upper bound...", release.RepoSource, release.RepoOwner, release.RepoName, release.Name)
-
-		// only override cpu and memory request values if measured values are within min and max
-		if measuredResources.CPUMaxUsage > 0 {
-			if measuredResources.CPUMaxUsage*s.config.Jobs.CPURequestRatio <= s.config.Jobs.MinCPUCores {
-				jobResources.CPURequest = s.config.Jobs.MinCPUCores
-			} else if measuredResources.CPUMaxUsage*s.config.Jobs.CPURequestRatio >= s.config.Jobs.MaxCPUCores {
-				jobResources.CPURequest = s.config.Jobs.MaxCPUCores
-			} else {
-				jobResources.CPURequest = measuredResources.CPUMaxUsage * s.config.Jobs.CPURequestRatio
-			}
-		}
-
-		if measuredResources.MemoryMaxUsage > 0 {
-			if measuredResources.MemoryMaxUsage*s.config.Jobs.MemoryRequestRatio <= s.config.Jobs.MinMemoryBytes {
-				jobResources.MemoryRequest = s.config.Jobs.MinMemoryBytes
-			} else if measuredResources.MemoryMaxUsage*s.config.Jobs.MemoryRequestRatio >= s.config.Jobs.MaxMemoryBytes {
-				jobResources.MemoryRequest = s.config.Jobs.MaxMemoryBytes
-			} else {
-				jobResources.MemoryRequest = measuredResources.MemoryMaxUsage * s.config.Jobs.MemoryRequestRatio
-			}
-		}
-	}
-
-	return jobResources
-}
-
-func (s *service) getBotJobResources(ctx context.Context, bot contracts.Bot) database.JobResources {
-
-	// define resource request and limit values to fit reasonably well inside a n1-standard-8 (8 vCPUs, 30 GB memory) machine
-	jobResources := database.JobResources{
-		CPURequest:    s.config.Jobs.MaxCPUCores,
-		CPULimit:      s.config.Jobs.MaxCPUCores,
-		MemoryRequest: s.config.Jobs.MaxMemoryBytes,
-		MemoryLimit:   s.config.Jobs.MaxMemoryBytes,
-	}
-
-	// get max usage from previous releases
-	measuredResources, nrRecords, err := s.databaseClient.GetPipelineReleaseMaxResourceUtilization(ctx, bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.Name, 25)
-	if err != nil {
-		log.Warn().Err(err).Msgf("Failed retrieving max resource utilization for recent bots of %v/%v/%v target %v, using defaults...", bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.Name)
-	} else if nrRecords < 5 {
-		log.Debug().Msgf("Retrieved max resource utilization for recent bots of %v/%v/%v target %v only has %v records, using defaults...", bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.Name, nrRecords)
-	} else {
-		log.Debug().Msgf("Retrieved max resource utilization for recent bots of %v/%v/%v target %v, checking if they are within lower and upper bound...", bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.Name)
-
-		// only override cpu and memory request values if measured values are within min and max
-		if measuredResources.CPUMaxUsage > 0 {
-			if measuredResources.CPUMaxUsage*s.config.Jobs.CPURequestRatio <= s.config.Jobs.MinCPUCores {
-				jobResources.CPURequest = s.config.Jobs.MinCPUCores
-			} else if measuredResources.CPUMaxUsage*s.config.Jobs.CPURequestRatio >= s.config.Jobs.MaxCPUCores {
-				jobResources.CPURequest = s.config.Jobs.MaxCPUCores
-			} else {
-				jobResources.CPURequest = measuredResources.CPUMaxUsage * s.config.Jobs.CPURequestRatio
-			}
-		}
-
-		if measuredResources.MemoryMaxUsage > 0 {
-			if measuredResources.MemoryMaxUsage*s.config.Jobs.MemoryRequestRatio <= s.config.Jobs.MinMemoryBytes {
-				jobResources.MemoryRequest = s.config.Jobs.MinMemoryBytes
-			} else if measuredResources.MemoryMaxUsage*s.config.Jobs.MemoryRequestRatio >= s.config.Jobs.MaxMemoryBytes {
-				jobResources.MemoryRequest = s.config.Jobs.MaxMemoryBytes
-			} else {
-				jobResources.MemoryRequest = measuredResources.MemoryMaxUsage * s.config.Jobs.MemoryRequestRatio
-			}
-		}
-	}
-
-	return jobResources
-}
-
 func (s *service) supportsBuildStatus(repoSource string) bool {
 
 	switch {
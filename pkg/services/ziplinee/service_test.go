@@ -1,12 +1,19 @@
 package ziplinee
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	gomock "github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/bigquery"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/bitbucketapi"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/builderapi"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/cloudsourceapi"
@@ -17,6 +24,7 @@ import (
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 	crypt "github.com/ziplineeci/ziplinee-ci-crypt"
 	manifest "github.com/ziplineeci/ziplinee-ci-manifest"
+	batchv1 "k8s.io/api/batch/v1"
 )
 
 func TestCreateBuild(t *testing.T) {
@@ -34,6 +42,7 @@ func TestCreateBuild(t *testing.T) {
 		}
 
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -60,11 +69,11 @@ func TestCreateBuild(t *testing.T) {
 		cloudsourceapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
 			return
 		}
-		databaseClient.EXPECT().GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().InsertBuild(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -90,6 +99,7 @@ func TestCreateBuild(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -103,7 +113,7 @@ func TestCreateBuild(t *testing.T) {
 
 		githubapiClient.EXPECT().JobVarsFunc(gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-		databaseClient.EXPECT().GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
 			return
 		}
@@ -116,7 +126,7 @@ func TestCreateBuild(t *testing.T) {
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().InsertBuild(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -130,7 +140,7 @@ func TestCreateBuild(t *testing.T) {
 		_, _ = service.CreateBuild(ctx, build)
 	})
 
-	t.Run("CallsGetPipelineBuildMaxResourceUtilizationOndatabaseClient", func(t *testing.T) {
+	t.Run("CallsGetPipelineBuildResourceUsagesOndatabaseClient", func(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -142,6 +152,7 @@ func TestCreateBuild(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -149,7 +160,11 @@ func TestCreateBuild(t *testing.T) {
 
 		databaseClient.
 			EXPECT().
-			GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "master", 25).
+			Times(1)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "", 25).
 			Times(1)
 		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
 			return
@@ -164,7 +179,7 @@ func TestCreateBuild(t *testing.T) {
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().InsertBuild(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -190,6 +205,7 @@ func TestCreateBuild(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -210,9 +226,9 @@ func TestCreateBuild(t *testing.T) {
 		}
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-		databaseClient.EXPECT().GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -238,23 +254,190 @@ func TestCreateBuild(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
+		secretHelper := crypt.NewSecretHelper("abc", false)
+		prometheusClient := prometheus.NewMockClient(ctrl)
+		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			InsertBuild(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, build contracts.Build, jobResources database.JobResources) (b *contracts.Build, err error) {
+				b = &build
+				b.ID = "5"
+				return
+			})
+		builderapiClient.
+			EXPECT().
+			CreateCiBuilderJob(gomock.Any(), gomock.Any()).
+			Times(1)
+		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+		bitbucketapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+		cloudsourceapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineTriggers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+
+		build := contracts.Build{
+			RepoSource: "github.com",
+			RepoOwner:  "ziplineeci",
+			RepoName:   "ziplinee-ci-api",
+			RepoBranch: "master",
+			Manifest:   "builder:\n  track: dev\nstages:\n  stage-1:\n    image: extensionci/doesnothing:dev",
+		}
+
+		// act
+		_, err := service.CreateBuild(ctx, build)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("CancelsSupersededBuildsForSameBranchIfCancelSupersededBuildsIsEnabled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			Jobs: &api.JobsConfig{
+				CancelSupersededBuilds: true,
+			},
+			APIServer: &api.APIServerConfig{},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
 		builderapiClient := builderapi.NewMockClient(ctrl)
 
+		insertedAt := time.Now().UTC()
+
 		databaseClient.
 			EXPECT().
 			InsertBuild(gomock.Any(), gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, build contracts.Build, jobResources database.JobResources) (b *contracts.Build, err error) {
 				b = &build
 				b.ID = "5"
+				b.InsertedAt = insertedAt
 				return
 			})
+		databaseClient.
+			EXPECT().
+			GetPipelineBuilds(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", 1, gomock.Any(), gomock.Any(), gomock.Any(), true).
+			DoAndReturn(func(ctx context.Context, repoSource, repoOwner, repoName string, pageNumber, pageSize int, filters map[api.FilterType][]string, sortings []api.OrderField, optimized bool) (builds []*contracts.Build, err error) {
+				assert.Equal(t, []string{"master"}, filters[api.FilterBranch])
+				return []*contracts.Build{
+					{ID: "5", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", RepoBranch: "master", BuildStatus: contracts.StatusPending, InsertedAt: insertedAt},
+					{ID: "4", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", RepoBranch: "master", BuildStatus: contracts.StatusRunning, InsertedAt: insertedAt.Add(-1 * time.Minute)},
+				}, nil
+			})
 		builderapiClient.
 			EXPECT().
 			CreateCiBuilderJob(gomock.Any(), gomock.Any()).
 			Times(1)
+		builderapiClient.
+			EXPECT().
+			GetJobName(gomock.Any(), contracts.JobTypeBuild, "ziplineeci", "ziplinee-ci-api", "4").
+			Return("build-ziplineeci-ziplinee-ci-api-4")
+		builderapiClient.
+			EXPECT().
+			CancelCiBuilderJob(gomock.Any(), "build-ziplineeci-ziplinee-ci-api-4").
+			Times(1)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "4", contracts.StatusCanceling).
+			Times(1)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatusReason(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "4", api.StatusReasonSuperseded).
+			Times(1)
+		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+		bitbucketapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+		cloudsourceapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineTriggers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+
+		build := contracts.Build{
+			RepoSource: "github.com",
+			RepoOwner:  "ziplineeci",
+			RepoName:   "ziplinee-ci-api",
+			RepoBranch: "master",
+			Manifest:   "builder:\n  track: dev\nstages:\n  stage-1:\n    image: extensionci/doesnothing:dev",
+		}
+
+		// act
+		_, err := service.CreateBuild(ctx, build)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("CallsInsertQueuedJobOndatabaseClientInsteadOfCreateCiBuilderJobOnBuilderapiClientIfJobQueueIsEnabled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			Jobs:      &api.JobsConfig{},
+			APIServer: &api.APIServerConfig{},
+			JobQueue: &api.JobQueueConfig{
+				Enable:              true,
+				PollIntervalSeconds: 5,
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
+		secretHelper := crypt.NewSecretHelper("abc", false)
+		prometheusClient := prometheus.NewMockClient(ctrl)
+		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			InsertBuild(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, build contracts.Build, jobResources database.JobResources) (b *contracts.Build, err error) {
+				assert.Equal(t, api.StatusQueued, build.BuildStatus)
+				b = &build
+				b.ID = "5"
+				return
+			})
+		databaseClient.
+			EXPECT().
+			InsertQueuedJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, queuedJob database.QueuedJob) (qj *database.QueuedJob, err error) {
+				assert.Equal(t, contracts.JobTypeBuild, queuedJob.JobType)
+				assert.Equal(t, "5", queuedJob.JobID)
+				assert.Equal(t, "ziplinee-ci-api", queuedJob.RepoName)
+				return &queuedJob, nil
+			}).
+			Times(1)
+		builderapiClient.
+			EXPECT().
+			CreateCiBuilderJob(gomock.Any(), gomock.Any()).
+			Times(0)
 		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
 			return
 		}
@@ -266,10 +449,10 @@ func TestCreateBuild(t *testing.T) {
 		}
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-		databaseClient.EXPECT().GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipelineTriggers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -297,6 +480,7 @@ func TestCreateBuild(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -331,9 +515,9 @@ func TestCreateBuild(t *testing.T) {
 		}
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-		databaseClient.EXPECT().GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -363,6 +547,7 @@ func TestCreateBuild(t *testing.T) {
 			},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -398,10 +583,10 @@ func TestCreateBuild(t *testing.T) {
 
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-		databaseClient.EXPECT().GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().InsertBuildLog(gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -429,6 +614,7 @@ func TestCreateBuild(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -463,9 +649,9 @@ func TestCreateBuild(t *testing.T) {
 		}
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetAutoIncrement(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-		databaseClient.EXPECT().GetPipelineBuildMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		build := contracts.Build{
 			RepoSource: "github.com",
@@ -498,6 +684,7 @@ func TestFinishBuild(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -519,7 +706,7 @@ func TestFinishBuild(t *testing.T) {
 
 		databaseClient.EXPECT().GetPipelineBuildByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		repoSource := "github.com"
 		repoOwner := "ziplineeci"
@@ -548,6 +735,7 @@ func TestCreateRelease(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -569,13 +757,13 @@ func TestCreateRelease(t *testing.T) {
 			return
 		}
 
-		databaseClient.EXPECT().GetPipelineReleaseMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineReleaseResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		builderapiClient.EXPECT().CreateCiBuilderJob(gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetReleaseTriggers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipelineBuildByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		release := contracts.Release{
 			RepoSource:     "github.com",
@@ -605,6 +793,7 @@ func TestCreateRelease(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -634,12 +823,12 @@ func TestCreateRelease(t *testing.T) {
 			return
 		}
 
-		databaseClient.EXPECT().GetPipelineReleaseMaxResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineReleaseResourceUsages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipelineBuildByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetReleaseTriggers(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		release := contracts.Release{
 			RepoSource:     "github.com",
@@ -672,6 +861,7 @@ func TestFinishRelease(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -694,7 +884,7 @@ func TestFinishRelease(t *testing.T) {
 
 		databaseClient.EXPECT().GetPipelineRelease(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		repoSource := "github.com"
 		repoOwner := "ziplineeci"
@@ -723,6 +913,7 @@ func TestRename(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -743,7 +934,7 @@ func TestRename(t *testing.T) {
 			return
 		}
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		// act
 		err := service.Rename(ctx, "github.com", "ziplineeci", "ziplinee-ci-contracts", "github.com", "ziplineeci", "ziplinee-ci-protos")
@@ -765,6 +956,7 @@ func TestRename(t *testing.T) {
 			},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -787,7 +979,7 @@ func TestRename(t *testing.T) {
 
 		databaseClient.EXPECT().Rename(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		// act
 		err := service.Rename(ctx, "github.com", "ziplineeci", "ziplinee-ci-contracts", "github.com", "ziplineeci", "ziplinee-ci-protos")
@@ -809,6 +1001,7 @@ func TestUpdateBuildStatus(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -832,7 +1025,7 @@ func TestUpdateBuildStatus(t *testing.T) {
 		databaseClient.EXPECT().GetPipelineBuildByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipelineRelease(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		event := contracts.ZiplineeCiBuilderEvent{
 			JobType: contracts.JobTypeBuild,
@@ -861,6 +1054,7 @@ func TestUpdateBuildStatus(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -884,7 +1078,7 @@ func TestUpdateBuildStatus(t *testing.T) {
 		databaseClient.EXPECT().GetPipelineBuildByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 		databaseClient.EXPECT().GetPipelineRelease(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		event := contracts.ZiplineeCiBuilderEvent{
 			JobType: contracts.JobTypeRelease,
@@ -915,6 +1109,7 @@ func TestUpdateJobResources(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -938,8 +1133,12 @@ func TestUpdateJobResources(t *testing.T) {
 		prometheusClient.EXPECT().AwaitScrapeInterval(gomock.Any()).AnyTimes()
 		prometheusClient.EXPECT().GetMaxCPUByPodName(gomock.Any(), gomock.Any()).AnyTimes()
 		prometheusClient.EXPECT().GetMaxMemoryByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetOOMKilledByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetCPUThrottledRatioByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineBuildByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetJobResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		event := contracts.ZiplineeCiBuilderEvent{
 			PodName: "build-ziplinee-ziplinee-ci-api-123456-mhrzk",
@@ -969,6 +1168,7 @@ func TestUpdateJobResources(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -992,8 +1192,12 @@ func TestUpdateJobResources(t *testing.T) {
 		prometheusClient.EXPECT().AwaitScrapeInterval(gomock.Any()).AnyTimes()
 		prometheusClient.EXPECT().GetMaxCPUByPodName(gomock.Any(), gomock.Any()).AnyTimes()
 		prometheusClient.EXPECT().GetMaxMemoryByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetOOMKilledByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetCPUThrottledRatioByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetPipelineRelease(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.EXPECT().GetJobResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		event := contracts.ZiplineeCiBuilderEvent{
 			PodName: "release-ziplinee-ziplinee-ci-api-123456-mhrzk",
@@ -1010,6 +1214,142 @@ func TestUpdateJobResources(t *testing.T) {
 
 		assert.Nil(t, err)
 	})
+
+	t.Run("CallsInsertBuildEventOnBigqueryClientIfBuildIsFinished", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			Jobs:      &api.JobsConfig{},
+			APIServer: &api.APIServerConfig{},
+			Integrations: &api.APIConfigIntegrations{
+				BigQuery: &api.BigQueryConfig{Enable: true},
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
+		prometheusClient := prometheus.NewMockClient(ctrl)
+
+		pendingDuration := 10 * time.Second
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildByID(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "123456", false).
+			Return(&contracts.Build{
+				ID:              "123456",
+				RepoSource:      "github.com",
+				RepoOwner:       "ziplineeci",
+				RepoName:        "ziplinee-ci-api",
+				BuildStatus:     contracts.StatusSucceeded,
+				Labels:          []contracts.Label{{Key: "team", Value: "ziplinee"}},
+				Commits:         []contracts.GitCommit{{Message: "fix", Author: contracts.GitAuthor{Email: "me@ziplinee.io"}}},
+				Duration:        2 * time.Minute,
+				PendingDuration: &pendingDuration,
+			}, nil)
+		databaseClient.EXPECT().UpdateBuildResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.
+			EXPECT().
+			GetJobResources(gomock.Any(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "123456").
+			Return(database.JobResources{CPURequest: 2.0, CPULimit: 4.0, MemoryRequest: 1024.0, MemoryLimit: 2048.0}, nil)
+
+		prometheusClient.EXPECT().AwaitScrapeInterval(gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetMaxCPUByPodName(gomock.Any(), gomock.Any()).Return(1.5, nil)
+		prometheusClient.EXPECT().GetMaxMemoryByPodName(gomock.Any(), gomock.Any()).Return(0.0, nil)
+		prometheusClient.EXPECT().GetOOMKilledByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetCPUThrottledRatioByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+
+		service := NewService(config, databaseClient, bigqueryClient, nil, prometheusClient, nil, nil, nil, nil, nil)
+
+		event := contracts.ZiplineeCiBuilderEvent{
+			PodName: "build-ziplinee-ziplinee-ci-api-123456-mhrzk",
+			JobType: contracts.JobTypeBuild,
+			Build: &contracts.Build{
+				ID:          "123456",
+				BuildStatus: contracts.StatusSucceeded,
+			},
+			Git: &contracts.GitConfig{
+				RepoSource: "github.com",
+				RepoOwner:  "ziplineeci",
+				RepoName:   "ziplinee-ci-api",
+			},
+		}
+
+		// act
+		err := service.UpdateJobResources(ctx, event)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(bigqueryClient.BuildEvents)) {
+			buildEvent := bigqueryClient.BuildEvents[0]
+			assert.Equal(t, 123456, buildEvent.BuildID)
+			assert.Equal(t, "succeeded", buildEvent.BuildStatus)
+			assert.Equal(t, "ziplinee", buildEvent.Labels[0].Value)
+			assert.Equal(t, "me@ziplinee.io", buildEvent.Commits[0].Author.Email)
+			assert.Equal(t, 2.0, buildEvent.CPURequest.Float64)
+			assert.Equal(t, 4.0, buildEvent.CPULimit.Float64)
+			assert.Equal(t, 1.5, buildEvent.CPUMaxUsage.Float64)
+			assert.Equal(t, 1024.0, buildEvent.MemoryRequest.Float64)
+			assert.Equal(t, 2048.0, buildEvent.MemoryLimit.Float64)
+			assert.False(t, buildEvent.MemoryMaxUsage.Valid)
+			assert.Equal(t, 130*time.Second, buildEvent.TotalDuration)
+			assert.Equal(t, 10*time.Second, buildEvent.TimeToRunning)
+		}
+	})
+
+	t.Run("DoesNotRetrieveJobForExportIfBigqueryIsDisabled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			Jobs:      &api.JobsConfig{},
+			APIServer: &api.APIServerConfig{},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
+		prometheusClient := prometheus.NewMockClient(ctrl)
+
+		databaseClient.EXPECT().UpdateBuildResourceUtilization(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		databaseClient.
+			EXPECT().
+			GetJobResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		prometheusClient.EXPECT().AwaitScrapeInterval(gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetMaxCPUByPodName(gomock.Any(), gomock.Any()).Return(1.5, nil)
+		prometheusClient.EXPECT().GetMaxMemoryByPodName(gomock.Any(), gomock.Any()).Return(0.0, nil)
+		prometheusClient.EXPECT().GetOOMKilledByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+		prometheusClient.EXPECT().GetCPUThrottledRatioByPodName(gomock.Any(), gomock.Any()).AnyTimes()
+
+		service := NewService(config, databaseClient, bigqueryClient, nil, prometheusClient, nil, nil, nil, nil, nil)
+
+		event := contracts.ZiplineeCiBuilderEvent{
+			PodName: "build-ziplinee-ziplinee-ci-api-123456-mhrzk",
+			JobType: contracts.JobTypeBuild,
+			Build: &contracts.Build{
+				ID:          "123456",
+				BuildStatus: contracts.StatusSucceeded,
+			},
+			Git: &contracts.GitConfig{
+				RepoSource: "github.com",
+				RepoOwner:  "ziplineeci",
+				RepoName:   "ziplinee-ci-api",
+			},
+		}
+
+		// act
+		err := service.UpdateJobResources(ctx, event)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(bigqueryClient.BuildEvents))
+	})
 }
 
 func TestGetEventsForJobEnvvars(t *testing.T) {
@@ -1025,6 +1365,7 @@ func TestGetEventsForJobEnvvars(t *testing.T) {
 			APIServer: &api.APIServerConfig{},
 		}
 		databaseClient := database.NewMockClient(ctrl)
+		bigqueryClient := bigquery.NewFakeClient()
 		secretHelper := crypt.NewSecretHelper("abc", false)
 		prometheusClient := prometheus.NewMockClient(ctrl)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
@@ -1066,7 +1407,7 @@ func TestGetEventsForJobEnvvars(t *testing.T) {
 				}, nil
 			})
 
-		service := NewService(config, databaseClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
+		service := NewService(config, databaseClient, bigqueryClient, secretHelper, prometheusClient, cloudStorageClient, builderapiClient, githubapiClientJobVarsFunc, bitbucketapiClientJobVarsFunc, cloudsourceapiClientJobVarsFunc)
 
 		triggers := []manifest.ZiplineeTrigger{
 			{
@@ -1111,6 +1452,2038 @@ func TestGetEventsForJobEnvvars(t *testing.T) {
 	})
 }
 
+func Test_cancelSupersededBuilds(t *testing.T) {
+
+	insertedAt := time.Now().UTC()
+	newBuild := contracts.Build{ID: "5", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", RepoBranch: "master", InsertedAt: insertedAt}
+
+	t.Run("DoesNotCancelBuildsInsertedAfterNewBuild", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetPipelineBuilds(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", 1, gomock.Any(), gomock.Any(), gomock.Any(), true).
+			Return([]*contracts.Build{
+				{ID: "6", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", RepoBranch: "master", BuildStatus: contracts.StatusRunning, InsertedAt: insertedAt.Add(time.Minute)},
+			}, nil)
+		builderapiClient.
+			EXPECT().
+			CancelCiBuilderJob(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(&api.APIConfig{}, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil).(*service)
+
+		// act
+		service.cancelSupersededBuilds(context.Background(), newBuild)
+	})
+
+	t.Run("LeavesStatusOfSupersededBuildAloneIfCancelingBuilderJobFails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetPipelineBuilds(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", 1, gomock.Any(), gomock.Any(), gomock.Any(), true).
+			Return([]*contracts.Build{
+				{ID: "4", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", RepoBranch: "master", BuildStatus: contracts.StatusRunning, InsertedAt: insertedAt.Add(-1 * time.Minute)},
+			}, nil)
+		builderapiClient.
+			EXPECT().
+			GetJobName(gomock.Any(), contracts.JobTypeBuild, "ziplineeci", "ziplinee-ci-api", "4").
+			Return("build-ziplineeci-ziplinee-ci-api-4")
+		builderapiClient.
+			EXPECT().
+			CancelCiBuilderJob(gomock.Any(), "build-ziplineeci-ziplinee-ci-api-4").
+			Return(errors.New("kubernetes api unavailable"))
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatusReason(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(&api.APIConfig{}, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil).(*service)
+
+		// act
+		service.cancelSupersededBuilds(context.Background(), newBuild)
+	})
+}
+
+func TestDispatchQueuedJobs(t *testing.T) {
+
+	t.Run("DoesNotCallGetQueuedJobsOndatabaseClientIfJobQueueIsDisabled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			Jobs:      &api.JobsConfig{},
+			APIServer: &api.APIServerConfig{},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetQueuedJobs(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil)
+
+		// act
+		err := service.DispatchQueuedJobs(ctx)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("CallsCreateCiBuilderJobOnBuilderapiClientOnlyForQueuedJobsWithinConcurrencyLimits", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			Jobs:      &api.JobsConfig{},
+			APIServer: &api.APIServerConfig{},
+			JobQueue: &api.JobQueueConfig{
+				Enable:                       true,
+				PollIntervalSeconds:          5,
+				MaxConcurrentJobs:            3,
+				MaxConcurrentJobsPerPipeline: 1,
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		queuedBuildParams := func(repoName, buildID string) []byte {
+			params, _ := json.Marshal(builderapi.CiBuilderParams{
+				BuilderConfig: contracts.BuilderConfig{
+					JobType: contracts.JobTypeBuild,
+					Build: &contracts.Build{
+						ID:          buildID,
+						RepoSource:  "github.com",
+						RepoOwner:   "ziplineeci",
+						RepoName:    repoName,
+						BuildStatus: api.StatusQueued,
+					},
+				},
+			})
+			return params
+		}
+
+		databaseClient.
+			EXPECT().
+			AcquireLock(gomock.Any(), jobQueueLockName, gomock.Any(), jobQueueLockTTL).
+			Return(true, nil)
+		databaseClient.
+			EXPECT().
+			ReleaseLock(gomock.Any(), jobQueueLockName, gomock.Any())
+		databaseClient.
+			EXPECT().
+			GetQueuedJobs(gomock.Any(), gomock.Any()).
+			Return([]*database.QueuedJob{
+				{ID: "1", JobType: contracts.JobTypeBuild, JobID: "11", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-a", CiBuilderParams: queuedBuildParams("repo-a", "11")},
+				{ID: "2", JobType: contracts.JobTypeBuild, JobID: "12", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-b", CiBuilderParams: queuedBuildParams("repo-b", "12")},
+				{ID: "3", JobType: contracts.JobTypeBuild, JobID: "13", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-b", CiBuilderParams: queuedBuildParams("repo-b", "13")},
+				{ID: "4", JobType: contracts.JobTypeBuild, JobID: "14", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-c", CiBuilderParams: queuedBuildParams("repo-c", "14")},
+			}, nil)
+		databaseClient.
+			EXPECT().
+			GetActiveJobs(gomock.Any()).
+			Return([]*database.ActiveJob{
+				{JobType: contracts.JobTypeBuild, JobID: "10", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-a"},
+			}, nil)
+		databaseClient.
+			EXPECT().
+			ClaimQueuedJob(gomock.Any(), gomock.Any()).
+			Return(true, nil).
+			Times(2)
+		builderapiClient.
+			EXPECT().
+			CreateCiBuilderJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params builderapi.CiBuilderParams) (job *batchv1.Job, err error) {
+				assert.Contains(t, []string{"12", "14"}, params.BuilderConfig.Build.ID)
+				assert.Equal(t, contracts.StatusPending, params.BuilderConfig.Build.BuildStatus)
+				return
+			}).
+			Times(2)
+		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, githubapiClientJobVarsFunc, nil, nil)
+
+		// act
+		err := service.DispatchQueuedJobs(ctx)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("DoesNotDispatchQueuedJobsIfAnotherReplicaHoldsTheLock", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			JobQueue: &api.JobQueueConfig{
+				Enable:            true,
+				MaxConcurrentJobs: 3,
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			AcquireLock(gomock.Any(), jobQueueLockName, gomock.Any(), jobQueueLockTTL).
+			Return(false, nil)
+		databaseClient.
+			EXPECT().
+			GetQueuedJobs(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.DispatchQueuedJobs(ctx)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("PutsJobBackInTheQueueIfCreatingBuilderJobFails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			JobQueue: &api.JobQueueConfig{
+				Enable:              true,
+				MaxConcurrentJobs:   3,
+				MaxDispatchAttempts: 5,
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		params, _ := json.Marshal(builderapi.CiBuilderParams{
+			BuilderConfig: contracts.BuilderConfig{
+				JobType: contracts.JobTypeBuild,
+				Build:   &contracts.Build{ID: "11", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-a", BuildStatus: api.StatusQueued},
+			},
+		})
+		queuedJob := &database.QueuedJob{ID: "1", JobType: contracts.JobTypeBuild, JobID: "11", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-a", CiBuilderParams: params}
+
+		databaseClient.EXPECT().AcquireLock(gomock.Any(), jobQueueLockName, gomock.Any(), jobQueueLockTTL).Return(true, nil)
+		databaseClient.EXPECT().ReleaseLock(gomock.Any(), jobQueueLockName, gomock.Any())
+		databaseClient.EXPECT().GetQueuedJobs(gomock.Any(), gomock.Any()).Return([]*database.QueuedJob{queuedJob}, nil)
+		databaseClient.EXPECT().GetActiveJobs(gomock.Any()).Return([]*database.ActiveJob{}, nil)
+		databaseClient.EXPECT().ClaimQueuedJob(gomock.Any(), *queuedJob).Return(true, nil)
+		builderapiClient.
+			EXPECT().
+			CreateCiBuilderJob(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("kubernetes api unavailable"))
+		databaseClient.
+			EXPECT().
+			RequeueJob(gomock.Any(), *queuedJob).
+			Return(nil)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), contracts.StatusFailed).
+			Times(0)
+		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, githubapiClientJobVarsFunc, nil, nil)
+
+		// act
+		err := service.DispatchQueuedJobs(ctx)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("SetsStatusFailedInsteadOfPuttingJobBackInTheQueueAfterMaxDispatchAttempts", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			JobQueue: &api.JobQueueConfig{
+				Enable:              true,
+				MaxConcurrentJobs:   3,
+				MaxDispatchAttempts: 5,
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		params, _ := json.Marshal(builderapi.CiBuilderParams{
+			BuilderConfig: contracts.BuilderConfig{
+				JobType: contracts.JobTypeBuild,
+				Build:   &contracts.Build{ID: "11", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-a", BuildStatus: api.StatusQueued},
+			},
+		})
+		queuedJob := &database.QueuedJob{ID: "1", JobType: contracts.JobTypeBuild, JobID: "11", RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "repo-a", CiBuilderParams: params, Attempts: 4}
+
+		databaseClient.EXPECT().AcquireLock(gomock.Any(), jobQueueLockName, gomock.Any(), jobQueueLockTTL).Return(true, nil)
+		databaseClient.EXPECT().ReleaseLock(gomock.Any(), jobQueueLockName, gomock.Any())
+		databaseClient.EXPECT().GetQueuedJobs(gomock.Any(), gomock.Any()).Return([]*database.QueuedJob{queuedJob}, nil)
+		databaseClient.EXPECT().GetActiveJobs(gomock.Any()).Return([]*database.ActiveJob{}, nil)
+		databaseClient.EXPECT().ClaimQueuedJob(gomock.Any(), *queuedJob).Return(true, nil)
+		builderapiClient.
+			EXPECT().
+			CreateCiBuilderJob(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("invalid builder params"))
+		databaseClient.
+			EXPECT().
+			RequeueJob(gomock.Any(), gomock.Any()).
+			Times(0)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), "github.com", "ziplineeci", "repo-a", "11", contracts.StatusFailed).
+			Return(nil)
+		githubapiClientJobVarsFunc := func(ctx context.Context, repoSource, repoOwner, repoName string) (token string, err error) {
+			return
+		}
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, githubapiClientJobVarsFunc, nil, nil)
+
+		// act
+		err := service.DispatchQueuedJobs(ctx)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestQueueMigration(t *testing.T) {
+
+	t.Run("ReturnsErrMigrationPipelineNotFoundIfPipelineDoesNotExist", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "repo-a", gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		databaseClient.
+			EXPECT().
+			QueueMigrationTask(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.QueueMigration(ctx, database.MigrationTask{FromSource: "github.com", FromOwner: "ziplineeci", FromName: "repo-a", ToSource: "github.com", ToOwner: "ziplineeci", ToName: "repo-b"}, "")
+
+		assert.ErrorIs(t, err, ErrMigrationPipelineNotFound)
+	})
+
+	t.Run("ReturnsErrMigrationInProgressIfTaskIsInProgressAndNoRestartIsRequested", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "repo-a", gomock.Any(), gomock.Any()).
+			Return(&contracts.Pipeline{}, nil)
+		databaseClient.
+			EXPECT().
+			GetMigrationTaskByFromRepo(gomock.Any(), "github.com", "ziplineeci", "repo-a").
+			Return(&database.MigrationTask{ID: "abc", Status: database.MigrationStatusInProgress}, nil)
+		databaseClient.
+			EXPECT().
+			QueueMigrationTask(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.QueueMigration(ctx, database.MigrationTask{FromSource: "github.com", FromOwner: "ziplineeci", FromName: "repo-a", ToSource: "github.com", ToOwner: "ziplineeci", ToName: "repo-b"}, "")
+
+		assert.ErrorIs(t, err, ErrMigrationInProgress)
+	})
+
+	t.Run("QueuesTaskWithLastStepBeforeRequestedRestartStep", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "repo-a", gomock.Any(), gomock.Any()).
+			Return(&contracts.Pipeline{}, nil)
+		databaseClient.
+			EXPECT().
+			GetMigrationTaskByFromRepo(gomock.Any(), "github.com", "ziplineeci", "repo-a").
+			Return(nil, database.ErrMigrationTaskNotFound)
+		databaseClient.
+			EXPECT().
+			QueueMigrationTask(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, task database.MigrationTask) (*database.MigrationTask, error) {
+				assert.NotEmpty(t, task.ID)
+				assert.Equal(t, database.MigrationStatusQueued, task.Status)
+				assert.Equal(t, "build_logs_done", task.LastStep)
+				return &task, nil
+			})
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.QueueMigration(ctx, database.MigrationTask{FromSource: "github.com", FromOwner: "ziplineeci", FromName: "repo-a", ToSource: "github.com", ToOwner: "ziplineeci", ToName: "repo-b"}, "build_log_objects")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsErrMigrationInvalidRestartForUnknownStep", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&contracts.Pipeline{}, nil)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.QueueMigration(ctx, database.MigrationTask{FromSource: "github.com", FromOwner: "ziplineeci", FromName: "repo-a", ToSource: "github.com", ToOwner: "ziplineeci", ToName: "repo-b"}, "pipelines")
+
+		assert.ErrorIs(t, err, ErrMigrationInvalidRestart)
+	})
+}
+
+func TestRunMigrationTasks(t *testing.T) {
+
+	t.Run("RunsRemainingStepsAndCompletesTask", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{},
+			Migration: &api.MigrationConfig{Enable: true, PollIntervalSeconds: 10, MaxConcurrentTasks: 1},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		task := database.MigrationTask{ID: "abc", Status: database.MigrationStatusInProgress, LastStep: "build_logs_done", FromSource: "github.com", FromOwner: "ziplineeci", FromName: "repo-a", ToSource: "bitbucket.org", ToOwner: "ziplineeci", ToName: "repo-b"}
+
+		databaseClient.
+			EXPECT().
+			PickMigrationTasks(gomock.Any(), 1).
+			Return([]*database.MigrationTask{&task}, nil)
+		databaseClient.
+			EXPECT().
+			MigrateReleases(gomock.Any(), gomock.Any()).
+			Times(0)
+		databaseClient.
+			EXPECT().
+			MigrateBuilds(gomock.Any(), gomock.Any()).
+			Times(0)
+		databaseClient.
+			EXPECT().
+			MigrateBuildVersions(gomock.Any(), gomock.Any(), "github", "bitbucket")
+		databaseClient.
+			EXPECT().
+			MigrateComputedTables(gomock.Any(), gomock.Any())
+		databaseClient.
+			EXPECT().
+			ArchiveComputedPipeline(gomock.Any(), "github.com", "ziplineeci", "repo-a")
+		databaseClient.
+			EXPECT().
+			UnarchiveComputedPipeline(gomock.Any(), "bitbucket.org", "ziplineeci", "repo-b")
+
+		lastSteps := []string{}
+		statuses := []database.MigrationStatus{}
+		databaseClient.
+			EXPECT().
+			UpdateMigrationTask(gomock.Any(), "abc", gomock.Any(), gomock.Any(), 0, 0, gomock.Any(), "").
+			DoAndReturn(func(ctx context.Context, id string, status database.MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) error {
+				statuses = append(statuses, status)
+				lastSteps = append(lastSteps, lastStep)
+				return nil
+			}).
+			Times(4)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.RunMigrationTasks(ctx)
+
+		assert.Nil(t, err)
+		// log objects are skipped when logs aren't written to cloud storage
+		assert.Equal(t, []string{"build_log_objects_done", "build_versions_done", "computed_tables_done", "callback_done"}, lastSteps)
+		assert.Equal(t, database.MigrationStatusCompleted, statuses[3])
+	})
+
+	t.Run("RollsBackFailedStepAndFailsTask", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{},
+			Migration: &api.MigrationConfig{Enable: true, PollIntervalSeconds: 10, MaxConcurrentTasks: 1},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		task := database.MigrationTask{ID: "abc", Status: database.MigrationStatusInProgress, LastStep: "release_log_objects_done", FromSource: "github.com", FromOwner: "ziplineeci", FromName: "repo-a", ToSource: "github.com", ToOwner: "ziplineeci", ToName: "repo-b"}
+
+		databaseClient.
+			EXPECT().
+			PickMigrationTasks(gomock.Any(), 1).
+			Return([]*database.MigrationTask{&task}, nil)
+		databaseClient.
+			EXPECT().
+			MigrateBuilds(gomock.Any(), gomock.Any()).
+			Return(0, assert.AnError)
+		databaseClient.
+			EXPECT().
+			RollbackMigrationStep(gomock.Any(), gomock.Any(), database.MigrationStepBuilds, "github")
+		databaseClient.
+			EXPECT().
+			MigrateBuildLogs(gomock.Any(), gomock.Any()).
+			Times(0)
+		databaseClient.
+			EXPECT().
+			UpdateMigrationTask(gomock.Any(), "abc", database.MigrationStatusFailed, "builds_failed", 0, 0, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id string, status database.MigrationStatus, lastStep string, builds, releases int, duration time.Duration, errorDetails string) error {
+				assert.Contains(t, errorDetails, assert.AnError.Error())
+				return nil
+			})
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.RunMigrationTasks(ctx)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestSearchPipelineLogs(t *testing.T) {
+
+	t.Run("ReturnsMatchingLinesFromCloudStorageLogsOldestFirst", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				BaseURL:   "https://ci.ziplinee.io/",
+				LogReader: api.LogTargetCloudStorage,
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
+
+		now := time.Now().UTC()
+		databaseClient.
+			EXPECT().
+			GetPipelineBuilds(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", 1, 10, gomock.Any(), gomock.Any(), true).
+			Return([]*contracts.Build{
+				{ID: "2", RepoBranch: "main", RepoRevision: "sha2", BuildVersion: "1.0.2", InsertedAt: now, UpdatedAt: now},
+				{ID: "1", RepoBranch: "main", RepoRevision: "sha1", BuildVersion: "1.0.1", InsertedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour)},
+			}, nil)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildLogs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "main", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, repoSource, repoOwner, repoName, repoBranch, repoRevision, buildID string) (*contracts.BuildLog, error) {
+				return &contracts.BuildLog{ID: "log" + buildID, BuildID: buildID}, nil
+			}).
+			Times(2)
+		cloudStorageClient.
+			EXPECT().
+			GetPipelineBuildLogSteps(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, buildLog contracts.BuildLog) ([]*contracts.BuildLogStep, error) {
+				return []*contracts.BuildLogStep{
+					{
+						Step: "build",
+						LogLines: []contracts.BuildLogLine{
+							{LineNumber: 1, StreamType: "stdout", Text: "compiling"},
+							{LineNumber: 2, StreamType: "stderr", Text: "ERROR: connection refused"},
+						},
+					},
+					{
+						Step: "test",
+						NestedSteps: []*contracts.BuildLogStep{
+							{
+								Step: "unit",
+								LogLines: []contracts.BuildLogLine{
+									{LineNumber: 1, StreamType: "stderr", Text: "error: connection refused by " + buildLog.BuildID},
+								},
+							},
+						},
+					},
+				}, nil
+			}).
+			Times(2)
+
+		service := NewService(config, databaseClient, nil, nil, nil, cloudStorageClient, nil, nil, nil, nil)
+
+		// act
+		result, err := service.SearchPipelineLogs(ctx, "github.com", "ziplineeci", "ziplinee-ci-api", LogSearchQuery{Text: "Connection refused", JobTypes: []contracts.JobType{contracts.JobTypeBuild}, StreamTypes: []string{"stderr"}, Steps: []string{"test"}})
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, 2, result.SearchedJobs)
+			assert.False(t, result.Truncated)
+			if assert.Equal(t, 2, len(result.Matches)) {
+				assert.Equal(t, "1", result.Matches[0].JobID)
+				assert.Equal(t, "unit", result.Matches[0].Step)
+				assert.Equal(t, 1, result.Matches[0].LineNumber)
+				assert.Equal(t, "https://ci.ziplinee.io/pipelines/github.com/ziplineeci/ziplinee-ci-api/builds/1/logs", result.Matches[0].JobLink)
+				assert.Equal(t, "2", result.Matches[1].JobID)
+			}
+		}
+	})
+
+	t.Run("ReturnsErrInvalidLogSearchQueryForInvalidRegex", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.SearchPipelineLogs(ctx, "github.com", "ziplineeci", "ziplinee-ci-api", LogSearchQuery{Text: "error(", Regex: true})
+
+		assert.ErrorIs(t, err, ErrInvalidLogSearchQuery)
+	})
+
+	t.Run("TruncatesMatchesToLimit", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{LogReader: api.LogTargetCloudStorage},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
+
+		now := time.Now().UTC()
+		databaseClient.
+			EXPECT().
+			GetPipelineBots(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", 1, 10, gomock.Any(), gomock.Any()).
+			Return([]*contracts.Bot{{ID: "5", Name: "stale-issues", InsertedAt: &now, UpdatedAt: &now}}, nil)
+		databaseClient.
+			EXPECT().
+			GetPipelineBotLogs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "5").
+			Return(&contracts.BotLog{ID: "log5", BotID: "5"}, nil)
+		cloudStorageClient.
+			EXPECT().
+			GetPipelineBotLogSteps(gomock.Any(), gomock.Any()).
+			Return([]*contracts.BuildLogStep{
+				{
+					Step: "close",
+					LogLines: []contracts.BuildLogLine{
+						{LineNumber: 1, StreamType: "stdout", Text: "closing issue 1"},
+						{LineNumber: 2, StreamType: "stdout", Text: "closing issue 2"},
+						{LineNumber: 3, StreamType: "stdout", Text: "closing issue 3"},
+					},
+				},
+			}, nil)
+
+		service := NewService(config, databaseClient, nil, nil, nil, cloudStorageClient, nil, nil, nil, nil)
+
+		// act
+		result, err := service.SearchPipelineLogs(ctx, "github.com", "ziplineeci", "ziplinee-ci-api", LogSearchQuery{Text: `issue \d`, Regex: true, JobTypes: []contracts.JobType{contracts.JobTypeBot}, Limit: 2})
+
+		if assert.Nil(t, err) {
+			assert.True(t, result.Truncated)
+			assert.Equal(t, 2, len(result.Matches))
+		}
+	})
+}
+
+func TestPrunePipelineExpiredLogs(t *testing.T) {
+
+	t.Run("DeletesExpiredDatabaseLogsInBatchesAndKeepsLatestSuccessfulReleaseLogs", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				LogWriters: []api.LogTarget{api.LogTargetDatabase},
+			},
+			LogRetention: &api.LogRetentionConfig{
+				Enable:    true,
+				BatchSize: 2,
+				Rules: []api.LogRetentionRule{
+					{JobType: contracts.JobTypeBuild, RetentionDays: 90},
+					{JobType: contracts.JobTypeRelease, RetentionDays: 365},
+				},
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		pipeline := contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api"}
+
+		gomock.InOrder(
+			databaseClient.
+				EXPECT().
+				GetExpiredLogIDs(gomock.Any(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", gomock.Any(), []string{}, 2).
+				Return([]string{"1", "2"}, nil),
+			databaseClient.
+				EXPECT().
+				DeleteLogsByID(gomock.Any(), contracts.JobTypeBuild, []string{"1", "2"}).
+				Return(2, nil),
+			databaseClient.
+				EXPECT().
+				GetExpiredLogIDs(gomock.Any(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", gomock.Any(), []string{}, 2).
+				Return([]string{"3"}, nil),
+			databaseClient.
+				EXPECT().
+				DeleteLogsByID(gomock.Any(), contracts.JobTypeBuild, []string{"3"}).
+				Return(1, nil),
+		)
+		databaseClient.
+			EXPECT().
+			GetLatestSuccessfulReleaseLogIDs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api").
+			Return([]string{"7"}, nil)
+		databaseClient.
+			EXPECT().
+			GetExpiredLogIDs(gomock.Any(), contracts.JobTypeRelease, "github.com", "ziplineeci", "ziplinee-ci-api", gomock.Any(), []string{"7"}, 2).
+			Return([]string{}, nil)
+		databaseClient.
+			EXPECT().
+			GetExpiredLogIDs(gomock.Any(), contracts.JobTypeBot, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		items, err := service.PrunePipelineExpiredLogs(ctx, pipeline, false)
+
+		if assert.Nil(t, err) && assert.Equal(t, 1, len(items)) {
+			assert.Equal(t, contracts.JobTypeBuild, items[0].JobType)
+			assert.Equal(t, api.LogTargetDatabase, items[0].LogTarget)
+			assert.Equal(t, 90, items[0].RetentionDays)
+			assert.Equal(t, 3, items[0].Logs)
+		}
+	})
+
+	t.Run("OnlyCountsExpiredLogsOnDryRun", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				LogWriters: []api.LogTarget{api.LogTargetDatabase, api.LogTargetCloudStorage},
+			},
+			LogRetention: &api.LogRetentionConfig{
+				Enable:    true,
+				BatchSize: 500,
+				Rules: []api.LogRetentionRule{
+					{LogTarget: api.LogTargetCloudStorage, JobType: contracts.JobTypeBot, Labels: map[string]string{"team": "ziplinee-team"}, RetentionDays: 7},
+				},
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
+
+		pipeline := contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Labels: []contracts.Label{{Key: "team", Value: "ziplinee-team"}}}
+
+		cloudStorageClient.
+			EXPECT().
+			PruneLogs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBot, gomock.Any(), []string{}, 500, true).
+			DoAndReturn(func(ctx context.Context, repoSource, repoOwner, repoName string, jobType contracts.JobType, createdBefore time.Time, keepLogIDs []string, batchSize int, dryRun bool) (int, int64, error) {
+				assert.WithinDuration(t, time.Now().UTC().AddDate(0, 0, -7), createdBefore, time.Minute)
+				return 4, 2048, nil
+			})
+		databaseClient.
+			EXPECT().
+			DeleteLogsByID(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, cloudStorageClient, nil, nil, nil, nil)
+
+		// act
+		items, err := service.PrunePipelineExpiredLogs(ctx, pipeline, true)
+
+		if assert.Nil(t, err) && assert.Equal(t, 1, len(items)) {
+			assert.Equal(t, api.LogTargetCloudStorage, items[0].LogTarget)
+			assert.Equal(t, 4, items[0].Logs)
+			assert.Equal(t, int64(2048), items[0].Bytes)
+		}
+	})
+}
+
+func TestReconcilePipelineLogs(t *testing.T) {
+
+	t.Run("CopiesMissingLogFileFromEarlierLogRecordOfSameJob", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
+
+		pipeline := contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api"}
+
+		databaseClient.
+			EXPECT().
+			GetLogRecords(gomock.Any(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api").
+			Return([]*database.LogRecord{
+				{ID: "13", JobID: "3"},
+				{ID: "12", JobID: "2"},
+				{ID: "11", JobID: "2"},
+				{ID: "10", JobID: "1"},
+			}, nil)
+		cloudStorageClient.
+			EXPECT().
+			GetLogIDs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild).
+			Return([]string{"11", "10", "5"}, nil)
+		cloudStorageClient.
+			EXPECT().
+			CopyLogs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeBuild, map[string]string{"11": "12"}).
+			Return(nil)
+		databaseClient.
+			EXPECT().
+			GetLogRecords(gomock.Any(), gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api").
+			Return([]*database.LogRecord{}, nil).
+			Times(2)
+		cloudStorageClient.
+			EXPECT().
+			GetLogIDs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", gomock.Any()).
+			Return([]string{}, nil).
+			Times(2)
+
+		service := NewService(config, databaseClient, nil, nil, nil, cloudStorageClient, nil, nil, nil, nil)
+
+		// act
+		result, err := service.ReconcilePipelineLogs(ctx, pipeline, false)
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, 3, result.Checked)
+			assert.Equal(t, 1, result.Copied)
+			assert.Equal(t, 1, result.Unrecoverable)
+			assert.Equal(t, 1, result.Orphaned)
+		}
+	})
+
+	t.Run("DoesNotCopyOnDryRun", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
+
+		pipeline := contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api"}
+
+		databaseClient.
+			EXPECT().
+			GetLogRecords(gomock.Any(), contracts.JobTypeRelease, "github.com", "ziplineeci", "ziplinee-ci-api").
+			Return([]*database.LogRecord{
+				{ID: "22", JobID: "7"},
+				{ID: "21", JobID: "7"},
+			}, nil)
+		cloudStorageClient.
+			EXPECT().
+			GetLogIDs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", contracts.JobTypeRelease).
+			Return([]string{"21"}, nil)
+		databaseClient.
+			EXPECT().
+			GetLogRecords(gomock.Any(), gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api").
+			Return([]*database.LogRecord{}, nil).
+			Times(2)
+		cloudStorageClient.
+			EXPECT().
+			GetLogIDs(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", gomock.Any()).
+			Return([]string{}, nil).
+			Times(2)
+		cloudStorageClient.
+			EXPECT().
+			CopyLogs(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, cloudStorageClient, nil, nil, nil, nil)
+
+		// act
+		result, err := service.ReconcilePipelineLogs(ctx, pipeline, true)
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, 1, result.Checked)
+			assert.Equal(t, 1, result.Copied)
+			assert.Equal(t, 0, result.Unrecoverable)
+		}
+	})
+}
+
+func TestQueueLogReconciliation(t *testing.T) {
+
+	t.Run("ReturnsErrLogReconciliationNotEnabledIfLogsAreNotWrittenToCloudStorage", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				LogWriters: []api.LogTarget{api.LogTargetDatabase},
+			},
+			LogReconciliation: &api.LogReconciliationConfig{Enable: true},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.QueueLogReconciliation(context.Background(), database.LogReconciliation{})
+
+		assert.ErrorIs(t, err, ErrLogReconciliationNotEnabled)
+	})
+
+	t.Run("ReturnsErrLogReconciliationInProgressIfOneIsActive", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				LogWriters: []api.LogTarget{api.LogTargetDatabase, api.LogTargetCloudStorage},
+			},
+			LogReconciliation: &api.LogReconciliationConfig{Enable: true},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetActiveLogReconciliation(gomock.Any()).
+			Return(&database.LogReconciliation{ID: "1", Status: database.LogReconciliationStatusInProgress}, nil)
+		databaseClient.
+			EXPECT().
+			QueueLogReconciliation(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.QueueLogReconciliation(context.Background(), database.LogReconciliation{DryRun: true})
+
+		assert.ErrorIs(t, err, ErrLogReconciliationInProgress)
+	})
+
+	t.Run("QueuesLogReconciliationIfNoneIsActive", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				LogWriters: []api.LogTarget{api.LogTargetDatabase, api.LogTargetCloudStorage},
+			},
+			LogReconciliation: &api.LogReconciliationConfig{Enable: true},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetActiveLogReconciliation(gomock.Any()).
+			Return(nil, database.ErrLogReconciliationNotFound)
+		databaseClient.
+			EXPECT().
+			QueueLogReconciliation(gomock.Any(), database.LogReconciliation{DryRun: true, QueuedBy: "log-migrator"}).
+			Return(&database.LogReconciliation{ID: "2", Status: database.LogReconciliationStatusQueued, DryRun: true}, nil)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		reconciliation, err := service.QueueLogReconciliation(context.Background(), database.LogReconciliation{DryRun: true, QueuedBy: "log-migrator"})
+
+		if assert.Nil(t, err) {
+			assert.Equal(t, "2", reconciliation.ID)
+			assert.Equal(t, database.LogReconciliationStatusQueued, reconciliation.Status)
+		}
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+
+	t.Run("RemovesQueuedJobFromQueueAndSetsStatusCanceled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			DeleteQueuedJob(gomock.Any(), contracts.JobTypeBuild, "5").
+			Return(true, nil)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "5", contracts.StatusCanceled).
+			Return(nil)
+		builderapiClient.
+			EXPECT().
+			CancelCiBuilderJob(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil)
+
+		// act
+		status, err := service.CancelJob(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "5", api.StatusQueued)
+
+		assert.Nil(t, err)
+		assert.Equal(t, contracts.StatusCanceled, status)
+	})
+
+	t.Run("CancelsBuilderJobIfQueuedJobWasDispatchedInTheMeantime", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			DeleteQueuedJob(gomock.Any(), contracts.JobTypeBuild, "5").
+			Return(false, nil)
+		builderapiClient.
+			EXPECT().
+			GetJobName(gomock.Any(), contracts.JobTypeBuild, "ziplineeci", "ziplinee-ci-api", "5").
+			Return("build-ziplineeci-ziplinee-ci-api-5")
+		builderapiClient.
+			EXPECT().
+			CancelCiBuilderJob(gomock.Any(), "build-ziplineeci-ziplinee-ci-api-5").
+			Return(nil)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "5", contracts.StatusCanceled).
+			Return(nil)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil)
+
+		// act
+		status, err := service.CancelJob(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "5", api.StatusQueued)
+
+		assert.Nil(t, err)
+		assert.Equal(t, contracts.StatusCanceled, status)
+	})
+
+	t.Run("SetsStatusCancelingForRunningRelease", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		builderapiClient.
+			EXPECT().
+			GetJobName(gomock.Any(), contracts.JobTypeRelease, "ziplineeci", "ziplinee-ci-api", "7").
+			Return("release-ziplineeci-ziplinee-ci-api-7")
+		builderapiClient.
+			EXPECT().
+			CancelCiBuilderJob(gomock.Any(), "release-ziplineeci-ziplinee-ci-api-7").
+			Return(nil)
+		databaseClient.
+			EXPECT().
+			UpdateReleaseStatus(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "7", contracts.StatusCanceling).
+			Return(nil)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil)
+
+		// act
+		status, err := service.CancelJob(context.Background(), contracts.JobTypeRelease, "github.com", "ziplineeci", "ziplinee-ci-api", "7", contracts.StatusRunning)
+
+		assert.Nil(t, err)
+		assert.Equal(t, contracts.StatusCanceling, status)
+	})
+
+	t.Run("SetsStatusCanceledIfRunningJobNoLongerExists", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		builderapiClient.
+			EXPECT().
+			GetJobName(gomock.Any(), contracts.JobTypeBot, "ziplineeci", "ziplinee-ci-api", "9").
+			Return("bot-ziplineeci-ziplinee-ci-api-9")
+		builderapiClient.
+			EXPECT().
+			CancelCiBuilderJob(gomock.Any(), "bot-ziplineeci-ziplinee-ci-api-9").
+			Return(builderapi.ErrJobNotFound)
+		gomock.InOrder(
+			databaseClient.
+				EXPECT().
+				UpdateBotStatus(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "9", contracts.StatusCanceling).
+				Return(nil),
+			databaseClient.
+				EXPECT().
+				UpdateBotStatus(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "9", contracts.StatusCanceled).
+				Return(nil),
+		)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil)
+
+		// act
+		status, err := service.CancelJob(context.Background(), contracts.JobTypeBot, "github.com", "ziplineeci", "ziplinee-ci-api", "9", contracts.StatusRunning)
+
+		assert.Nil(t, err)
+		assert.Equal(t, contracts.StatusCanceled, status)
+	})
+
+	t.Run("ReturnsErrJobNotCancelableForFinishedJob", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		builderapiClient := builderapi.NewMockClient(ctrl)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, builderapiClient, nil, nil, nil)
+
+		// act
+		_, err := service.CancelJob(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "5", contracts.StatusSucceeded)
+
+		assert.ErrorIs(t, err, ErrJobNotCancelable)
+	})
+}
+
+func TestDispatchJobStatus(t *testing.T) {
+
+	t.Run("QueuesDeliveryForEachRoutedChannel", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			APIServer: &api.APIServerConfig{
+				BaseURL: "https://ci.ziplinee.io/",
+			},
+			Notifications: &api.NotificationsConfig{
+				Enable: true,
+				Channels: []*api.NotificationChannelConfig{
+					{Name: "deployments", Type: api.NotificationChannelTypeWebhook, URL: "https://hooks.ziplinee.io/deployments"},
+					{Name: "team-slack", Type: api.NotificationChannelTypeSlack, URL: "https://hooks.slack.com/services/T000/B000/XXXX"},
+				},
+				Routes: []api.NotificationRoute{
+					{Events: []api.NotificationEventType{api.NotificationEventTypeRelease}, Channels: []string{"deployments"}},
+					{Statuses: []contracts.Status{contracts.StatusFailed}, Labels: map[string]string{"team": "ziplinee-team"}, Channels: []string{"team-slack"}},
+				},
+			},
+		}
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			GetPipelineRelease(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "15").
+			Return(&contracts.Release{ID: "15", Name: "production", ReleaseVersion: "1.0.5"}, nil)
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", gomock.Any(), true).
+			Return(&contracts.Pipeline{Labels: []contracts.Label{{Key: "team", Value: "ziplinee-team"}}}, nil)
+
+		deliveries := []database.NotificationDelivery{}
+		databaseClient.
+			EXPECT().
+			InsertNotificationDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.NotificationDelivery) (*database.NotificationDelivery, error) {
+				deliveries = append(deliveries, delivery)
+				return &delivery, nil
+			}).
+			Times(2)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.DispatchJobStatus(context.Background(), contracts.JobTypeRelease, "github.com", "ziplineeci", "ziplinee-ci-api", "15", contracts.StatusFailed)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(deliveries)) {
+			assert.Equal(t, "deployments", deliveries[0].Channel)
+			assert.Equal(t, "team-slack", deliveries[1].Channel)
+			assert.Equal(t, "release", deliveries[0].EventType)
+
+			var event NotificationEvent
+			err = json.Unmarshal(deliveries[0].Payload, &event)
+			assert.Nil(t, err)
+			assert.Equal(t, "production", event.Name)
+			assert.Equal(t, "1.0.5", event.Version)
+			assert.Equal(t, "https://ci.ziplinee.io/pipelines/github.com/ziplineeci/ziplinee-ci-api/releases/15/logs", event.Link)
+		}
+	})
+
+	t.Run("DoesNothingIfNotificationsAreNotEnabled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.DispatchJobStatus(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "15", contracts.StatusFailed)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestDeliverNotifications(t *testing.T) {
+
+	t.Run("PostsSignedPayloadToWebhookAndMarksDeliverySucceeded", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		payload := []byte(`{"event":"build","status":"succeeded"}`)
+
+		var signature string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature = r.Header.Get(notificationSignatureHeader)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		config := &api.APIConfig{
+			Notifications: &api.NotificationsConfig{
+				Enable:   true,
+				Channels: []*api.NotificationChannelConfig{{Name: "deployments", Type: api.NotificationChannelTypeWebhook, URL: server.URL, SigningSecret: "my-secret"}},
+			},
+		}
+		config.Notifications.SetDefaults()
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			PickNotificationDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*database.NotificationDelivery{{ID: "1", Channel: "deployments", ChannelType: "webhook", EventType: "build", Payload: payload}}, nil)
+
+		var updatedDelivery database.NotificationDelivery
+		databaseClient.
+			EXPECT().
+			UpdateNotificationDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.NotificationDelivery) error {
+				updatedDelivery = delivery
+				return nil
+			})
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.DeliverNotifications(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "sha256="+getNotificationSignature("my-secret", payload), signature)
+		assert.Equal(t, database.NotificationDeliveryStatusSucceeded, updatedDelivery.Status)
+		assert.Equal(t, 1, updatedDelivery.Attempts)
+		assert.NotNil(t, updatedDelivery.DeliveredAt)
+	})
+
+	t.Run("ReschedulesDeliveryWithBackoffIfWebhookFails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		config := &api.APIConfig{
+			Notifications: &api.NotificationsConfig{
+				Enable:   true,
+				Channels: []*api.NotificationChannelConfig{{Name: "deployments", Type: api.NotificationChannelTypeWebhook, URL: server.URL}},
+			},
+		}
+		config.Notifications.SetDefaults()
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			PickNotificationDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*database.NotificationDelivery{{ID: "1", Channel: "deployments", ChannelType: "webhook", EventType: "build", Attempts: 1, Payload: []byte(`{}`)}}, nil)
+
+		var updatedDelivery database.NotificationDelivery
+		databaseClient.
+			EXPECT().
+			UpdateNotificationDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.NotificationDelivery) error {
+				updatedDelivery = delivery
+				return nil
+			})
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.DeliverNotifications(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, database.NotificationDeliveryStatusPending, updatedDelivery.Status)
+		assert.Equal(t, 2, updatedDelivery.Attempts)
+		assert.NotEmpty(t, updatedDelivery.ErrorDetails)
+		assert.WithinDuration(t, time.Now().UTC().Add(60*time.Second), updatedDelivery.NextAttemptAt, 5*time.Second)
+	})
+
+	t.Run("MarksDeliveryFailedIfChannelIsNoLongerConfigured", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			Notifications: &api.NotificationsConfig{Enable: true},
+		}
+		config.Notifications.SetDefaults()
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			PickNotificationDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*database.NotificationDelivery{{ID: "1", Channel: "removed", ChannelType: "slack", EventType: "build", Payload: []byte(`{}`)}}, nil)
+
+		var updatedDelivery database.NotificationDelivery
+		databaseClient.
+			EXPECT().
+			UpdateNotificationDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.NotificationDelivery) error {
+				updatedDelivery = delivery
+				return nil
+			})
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.DeliverNotifications(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, database.NotificationDeliveryStatusFailed, updatedDelivery.Status)
+		assert.Equal(t, ErrNotificationChannelNotFound.Error(), updatedDelivery.ErrorDetails)
+	})
+
+	t.Run("DeliversRestOfBatchIfStoringOutcomeFails", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		config := &api.APIConfig{
+			Notifications: &api.NotificationsConfig{Enable: true},
+		}
+		config.Notifications.SetDefaults()
+		databaseClient := database.NewMockClient(ctrl)
+
+		databaseClient.
+			EXPECT().
+			PickNotificationDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*database.NotificationDelivery{
+				{ID: "1", Channel: "removed", ChannelType: "slack", EventType: "build", Payload: []byte(`{}`)},
+				{ID: "2", Channel: "removed", ChannelType: "slack", EventType: "build", Payload: []byte(`{}`)},
+			}, nil)
+
+		updatedIDs := []string{}
+		databaseClient.
+			EXPECT().
+			UpdateNotificationDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, delivery database.NotificationDelivery) error {
+				updatedIDs = append(updatedIDs, delivery.ID)
+				return errors.New("connection reset")
+			}).
+			Times(2)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		err := service.DeliverNotifications(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1", "2"}, updatedIDs)
+	})
+}
+
+func TestGetNotificationBatchSize(t *testing.T) {
+
+	t.Run("ReturnsConfiguredBatchSizeIfBatchCanBeSentBeforeItsDeliveriesAreStale", func(t *testing.T) {
+
+		config := &api.APIConfig{
+			Notifications: &api.NotificationsConfig{Enable: true, BatchSize: 20, TimeoutSeconds: 10},
+		}
+		service := NewService(config, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*service)
+
+		// act
+		batchSize := service.getNotificationBatchSize()
+
+		assert.Equal(t, 20, batchSize)
+	})
+
+	t.Run("LimitsBatchSizeToDeliveriesThatCanBeSentBeforeTheyAreStale", func(t *testing.T) {
+
+		config := &api.APIConfig{
+			Notifications: &api.NotificationsConfig{Enable: true, BatchSize: 50, TimeoutSeconds: 10},
+		}
+		service := NewService(config, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*service)
+
+		// act
+		batchSize := service.getNotificationBatchSize()
+
+		assert.Equal(t, 29, batchSize)
+	})
+
+	t.Run("LimitsBatchSizeByEmailTimeoutIfAnEmailChannelIsConfigured", func(t *testing.T) {
+
+		config := &api.APIConfig{
+			Notifications: &api.NotificationsConfig{
+				Enable:         true,
+				BatchSize:      50,
+				TimeoutSeconds: 10,
+				Channels:       []*api.NotificationChannelConfig{{Name: "team-email", Type: api.NotificationChannelTypeEmail}},
+			},
+		}
+		service := NewService(config, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*service)
+
+		// act
+		batchSize := service.getNotificationBatchSize()
+
+		assert.Equal(t, 9, batchSize)
+	})
+}
+
+func TestExplainJobResources(t *testing.T) {
+
+	gibibyte := float64(1073741824)
+
+	getConfig := func(extrapolateTrend bool) *api.APIConfig {
+		return &api.APIConfig{
+			Jobs: &api.JobsConfig{
+				MinCPUCores:        0.1,
+				DefaultCPUCores:    0.5,
+				MaxCPUCores:        8.0,
+				CPURequestRatio:    1.0,
+				CPULimitRatio:      2.0,
+				MinMemoryBytes:     gibibyte / 16,
+				DefaultMemoryBytes: gibibyte / 4,
+				MaxMemoryBytes:     32 * gibibyte,
+				MemoryRequestRatio: 1.0,
+				MemoryLimitRatio:   1.0,
+				Sizing: &api.JobSizingConfig{
+					LastNRecords:          25,
+					MinRecords:            5,
+					CPUPercentile:         90,
+					MemoryPercentile:      95,
+					ExtrapolateTrend:      &extrapolateTrend,
+					OOMBumpRatio:          1.5,
+					CPUThrottledBumpRatio: 1.25,
+					CPUThrottledThreshold: 0.25,
+				},
+			},
+		}
+	}
+
+	t.Run("SizesBuildOnPercentileSoAnOutlierDoesNotInflateRequests", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		usages := []*database.JobResourceUsage{}
+		for i := 0; i < 10; i++ {
+			usage := &database.JobResourceUsage{JobResources: database.JobResources{CPUMaxUsage: 1.0, MemoryMaxUsage: 2 * gibibyte}, Status: contracts.StatusSucceeded}
+			if i == 4 {
+				usage.CPUMaxUsage = 7.5
+			}
+			usages = append(usages, usage)
+		}
+
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "main", 25).
+			Return(usages, nil)
+
+		service := NewService(getConfig(false), databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		explanation, err := service.ExplainJobResources(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "main", "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "builds of branch main", explanation.History)
+		assert.Equal(t, 10, explanation.NrRecords)
+		assert.Equal(t, 1.0, explanation.CPURequest)
+		assert.Equal(t, 2.0, explanation.CPULimit)
+		assert.Equal(t, 2*gibibyte, explanation.MemoryRequest)
+		assert.Equal(t, 32*gibibyte, explanation.MemoryLimit)
+	})
+
+	t.Run("FallsBackToBuildsOfAllBranchesIfBranchHasTooFewBuilds", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		usages := []*database.JobResourceUsage{}
+		for i := 0; i < 6; i++ {
+			usages = append(usages, &database.JobResourceUsage{JobResources: database.JobResources{CPUMaxUsage: 2.0, MemoryMaxUsage: gibibyte}, Status: contracts.StatusSucceeded})
+		}
+
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "feature", 25).
+			Return(usages[:2], nil)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "", 25).
+			Return(usages, nil)
+
+		service := NewService(getConfig(true), databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		explanation, err := service.ExplainJobResources(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "feature", "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "builds of all branches", explanation.History)
+		assert.Equal(t, 6, explanation.NrRecords)
+		assert.Equal(t, 2.0, explanation.CPURequest)
+		assert.Equal(t, gibibyte, explanation.MemoryRequest)
+	})
+
+	t.Run("SizesReleaseOnUsageTheTrendPredictsIfHigherThanPercentile", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		// newest first, growing by a gibibyte every release
+		usages := []*database.JobResourceUsage{}
+		for i := 10; i > 0; i-- {
+			usages = append(usages, &database.JobResourceUsage{JobResources: database.JobResources{CPUMaxUsage: 1.0, MemoryMaxUsage: float64(i) * gibibyte}, Status: contracts.StatusSucceeded})
+		}
+
+		databaseClient.
+			EXPECT().
+			GetPipelineReleaseResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "production", "deploy", 25).
+			Return(usages, nil)
+
+		service := NewService(getConfig(true), databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		explanation, err := service.ExplainJobResources(context.Background(), contracts.JobTypeRelease, "github.com", "ziplineeci", "ziplinee-ci-api", "production", "deploy")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "releases to production with action deploy", explanation.History)
+		assert.InDelta(t, 11*gibibyte, explanation.MemoryRequest, 1)
+		assert.Equal(t, 32*gibibyte, explanation.MemoryLimit)
+	})
+
+	t.Run("BumpsMemoryRequestAfterPreviousBuildWasOOMKilledEvenWithTooFewBuilds", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		usages := []*database.JobResourceUsage{
+			{JobResources: database.JobResources{CPURequest: 0.5, CPUMaxUsage: 0.5, MemoryRequest: 2 * gibibyte, MemoryMaxUsage: 2 * gibibyte, OOMKilled: true}, Status: contracts.StatusFailed},
+		}
+
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "main", 25).
+			Return(usages, nil)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "", 25).
+			Return(usages, nil)
+
+		service := NewService(getConfig(true), databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		explanation, err := service.ExplainJobResources(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "main", "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, explanation.NrRecords)
+		assert.Equal(t, 0.5, explanation.CPURequest)
+		assert.Equal(t, 3*gibibyte, explanation.MemoryRequest)
+		assert.Equal(t, 32*gibibyte, explanation.MemoryLimit)
+	})
+
+	t.Run("SizesBotOnPreviousRunsOfSameBot", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		usages := []*database.JobResourceUsage{}
+		for i := 0; i < 5; i++ {
+			usages = append(usages, &database.JobResourceUsage{JobResources: database.JobResources{CPUMaxUsage: 0.05, MemoryMaxUsage: gibibyte}, Status: contracts.StatusSucceeded})
+		}
+
+		databaseClient.
+			EXPECT().
+			GetPipelineBotResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "cleanup", 25).
+			Return(usages, nil)
+
+		service := NewService(getConfig(true), databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		explanation, err := service.ExplainJobResources(context.Background(), contracts.JobTypeBot, "github.com", "ziplineeci", "ziplinee-ci-api", "cleanup", "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "runs of bot cleanup", explanation.History)
+		assert.Equal(t, 0.1, explanation.CPURequest)
+		assert.Equal(t, 8.0, explanation.CPULimit)
+		assert.Equal(t, gibibyte, explanation.MemoryRequest)
+	})
+
+	t.Run("BumpsCPURequestAfterPreviousBuildFailedWhileThrottled", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+
+		usages := []*database.JobResourceUsage{
+			{JobResources: database.JobResources{CPURequest: 2.0, CPUMaxUsage: 2.0, MemoryMaxUsage: gibibyte, CPUThrottled: true}, Status: contracts.StatusFailed},
+		}
+		for i := 0; i < 5; i++ {
+			usages = append(usages, &database.JobResourceUsage{JobResources: database.JobResources{CPURequest: 2.0, CPUMaxUsage: 1.0, MemoryMaxUsage: gibibyte}, Status: contracts.StatusSucceeded})
+		}
+
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildResourceUsages(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", "main", 25).
+			Return(usages, nil)
+
+		service := NewService(getConfig(false), databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		explanation, err := service.ExplainJobResources(context.Background(), contracts.JobTypeBuild, "github.com", "ziplineeci", "ziplinee-ci-api", "main", "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2.5, explanation.CPURequest)
+		assert.Equal(t, 5.0, explanation.CPULimit)
+	})
+
+	t.Run("ReturnsErrInvalidJobTypeForUnknownJobType", func(t *testing.T) {
+
+		service := NewService(getConfig(true), nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.ExplainJobResources(context.Background(), contracts.JobType("deploy"), "github.com", "ziplineeci", "ziplinee-ci-api", "main", "")
+
+		assert.ErrorIs(t, err, ErrInvalidJobType)
+	})
+}
+
+func TestGetCostReport(t *testing.T) {
+
+	gibibyte := float64(1073741824)
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	config := &api.APIConfig{
+		Costs: &api.CostsConfig{
+			Currency: "EUR",
+			Prices: []*api.CostsPriceConfig{
+				{NodePool: "privileged", JobTypes: []contracts.JobType{contracts.JobTypeBuild}, PricePerCoreHour: 0.05, PricePerGBHour: 0.01},
+				{NodePool: "default", JobTypes: []contracts.JobType{contracts.JobTypeRelease}, PricePerCoreHour: 0.03, PricePerGBHour: 0.005},
+			},
+		},
+	}
+
+	builds := []*database.JobResourceUtilization{
+		{
+			JobResources:  database.JobResources{CPURequest: 2.0, CPUMaxUsage: 1.0, MemoryRequest: 4 * gibibyte, MemoryMaxUsage: 3 * gibibyte},
+			JobType:       contracts.JobTypeBuild,
+			RepoSource:    "github.com",
+			RepoOwner:     "ziplineeci",
+			RepoName:      "ziplinee-ci-api",
+			Labels:        []contracts.Label{{Key: "team", Value: "ziplinee-team"}},
+			Organizations: []*contracts.Organization{{Name: "Ziplinee"}, {Name: "Partner"}},
+			InsertedAt:    time.Date(2026, 9, 15, 10, 0, 0, 0, time.UTC),
+			Duration:      time.Hour,
+			Measured:      true,
+		},
+		{
+			JobResources: database.JobResources{CPURequest: 1.0, MemoryRequest: 2 * gibibyte},
+			JobType:      contracts.JobTypeBuild,
+			RepoSource:   "github.com",
+			RepoOwner:    "ziplineeci",
+			RepoName:     "ziplinee-ci-web",
+			InsertedAt:   time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC),
+			Duration:     2 * time.Hour,
+		},
+	}
+	releases := []*database.JobResourceUtilization{
+		{
+			JobResources:  database.JobResources{CPURequest: 1.0, CPUMaxUsage: 0.5, MemoryRequest: gibibyte, MemoryMaxUsage: gibibyte},
+			JobType:       contracts.JobTypeRelease,
+			RepoSource:    "github.com",
+			RepoOwner:     "ziplineeci",
+			RepoName:      "ziplinee-ci-api",
+			Labels:        []contracts.Label{{Key: "team", Value: "ziplinee-team"}},
+			Organizations: []*contracts.Organization{{Name: "Ziplinee"}},
+			InsertedAt:    time.Date(2026, 10, 5, 10, 0, 0, 0, time.UTC),
+			Duration:      30 * time.Minute,
+			Measured:      true,
+		},
+	}
+
+	getDatabaseClient := func(ctrl *gomock.Controller) *database.MockClient {
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.EXPECT().GetJobResourceUtilizations(gomock.Any(), contracts.JobTypeBuild, from, to).Return(builds, nil)
+		databaseClient.EXPECT().GetJobResourceUtilizations(gomock.Any(), contracts.JobTypeRelease, from, to).Return(releases, nil)
+		return databaseClient
+	}
+
+	t.Run("SplitsCostOfJobsEvenlyOverTheirOrganizations", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(config, getDatabaseClient(ctrl), nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		report, err := service.GetCostReport(context.Background(), CostDimensionOrganization, "", from, to, map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "EUR", report.Currency)
+		if assert.Equal(t, 3, len(report.Items)) {
+			// first build costs 2*0.05 + 4*0.01 = 0.14, split over 2 organizations; release costs 0.5*0.03 + 0.5*0.005 = 0.0175
+			assert.Equal(t, "unattributed", report.Items[0].Key)
+			assert.InDelta(t, 0.14, report.Items[0].Cost, 0.0001)
+			assert.Equal(t, "Ziplinee", report.Items[1].Key)
+			assert.Equal(t, 2, report.Items[1].NrJobs)
+			assert.InDelta(t, 0.0875, report.Items[1].Cost, 0.0001)
+			assert.Equal(t, "Partner", report.Items[2].Key)
+			assert.InDelta(t, 0.07, report.Items[2].Cost, 0.0001)
+		}
+		assert.Equal(t, 3, report.Total.NrJobs)
+		assert.InDelta(t, 0.2975, report.Total.Cost, 0.0001)
+	})
+
+	t.Run("ReportsWasteOnlyForJobsWithMeasuredUsage", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(config, getDatabaseClient(ctrl), nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		report, err := service.GetCostReport(context.Background(), CostDimensionPipeline, "", from, to, map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(report.Items)) {
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api", report.Items[0].Key)
+			assert.InDelta(t, 1.25, report.Items[0].WastedCoreHours, 0.0001)
+			assert.InDelta(t, 1.0, report.Items[0].WastedGBHours, 0.0001)
+			assert.InDelta(t, 0.0675, report.Items[0].WastedCost, 0.0001)
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-web", report.Items[1].Key)
+			assert.Equal(t, 0.0, report.Items[1].WastedCost)
+		}
+	})
+
+	t.Run("ListsMonthsChronologically", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(config, getDatabaseClient(ctrl), nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		report, err := service.GetCostReport(context.Background(), CostDimensionMonth, "", from, to, map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(report.Items)) {
+			assert.Equal(t, "2026-09", report.Items[0].Key)
+			assert.Equal(t, "2026-10", report.Items[1].Key)
+			assert.Equal(t, 2, report.Items[1].NrJobs)
+		}
+	})
+
+	t.Run("GroupsByValueOfLabelKey", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(config, getDatabaseClient(ctrl), nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		report, err := service.GetCostReport(context.Background(), CostDimensionLabel, "team", from, to, map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "team", report.LabelKey)
+		if assert.Equal(t, 2, len(report.Items)) {
+			assert.Equal(t, "ziplinee-team", report.Items[0].Key)
+			assert.Equal(t, "unattributed", report.Items[1].Key)
+		}
+	})
+
+	t.Run("LeavesOutJobTypesWithoutPrice", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := getDatabaseClient(ctrl)
+		databaseClient.EXPECT().GetJobResourceUtilizations(gomock.Any(), contracts.JobTypeBot, gomock.Any(), gomock.Any()).Times(0)
+
+		service := NewService(config, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		report, err := service.GetCostReport(context.Background(), CostDimensionPipeline, "", from, to, map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, report.Total.NrJobs)
+	})
+
+	t.Run("OnlyIncludesJobsOfFilteredOrganizations", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(config, getDatabaseClient(ctrl), nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		report, err := service.GetCostReport(context.Background(), CostDimensionPipeline, "", from, to, map[api.FilterType][]string{api.FilterOrganizations: {"Partner"}})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Total.NrJobs)
+		if assert.Equal(t, 1, len(report.Items)) {
+			assert.Equal(t, "github.com/ziplineeci/ziplinee-ci-api", report.Items[0].Key)
+		}
+	})
+
+	t.Run("ReturnsErrCostsNotConfiguredWithoutCostsConfig", func(t *testing.T) {
+
+		service := NewService(&api.APIConfig{}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.GetCostReport(context.Background(), CostDimensionPipeline, "", from, to, map[api.FilterType][]string{})
+
+		assert.ErrorIs(t, err, ErrCostsNotConfigured)
+	})
+
+	t.Run("ReturnsErrInvalidCostDimensionForUnknownDimension", func(t *testing.T) {
+
+		service := NewService(config, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		_, err := service.GetCostReport(context.Background(), CostDimension("weeks"), "", from, to, map[api.FilterType][]string{})
+
+		assert.ErrorIs(t, err, ErrInvalidCostDimension)
+	})
+}
+
+func Test_writeCostReportCSV(t *testing.T) {
+	t.Run("WritesHeaderItemsAndTotal", func(t *testing.T) {
+
+		report := &CostReport{
+			Dimension: CostDimensionLabel,
+			LabelKey:  "team",
+			Currency:  "EUR",
+			Items:     []*CostReportItem{{Key: "ziplinee-team", NrJobs: 2, CoreHours: 1.5, Cost: 0.25}},
+			Total:     &CostReportItem{Key: "total", NrJobs: 2, CoreHours: 1.5, Cost: 0.25},
+		}
+
+		var buffer bytes.Buffer
+
+		// act
+		err := writeCostReportCSV(&buffer, report)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "team,jobs,core hours,GB hours,wasted core hours,wasted GB hours,cost,wasted cost,currency\n"+
+			"ziplinee-team,2,1.5000,0.0000,0.0000,0.0000,0.2500,0.0000,EUR\n"+
+			"total,2,1.5000,0.0000,0.0000,0.0000,0.2500,0.0000,EUR\n", buffer.String())
+	})
+}
+
+func TestGetDoraMetrics(t *testing.T) {
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC)
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC)
+	}
+	atPtr := func(day, hour int) *time.Time {
+		value := at(day, hour)
+		return &value
+	}
+
+	outcomes := []*database.ReleaseOutcome{
+		{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Name: "production", ReleaseStatus: contracts.StatusSucceeded, InsertedAt: at(2, 11), FinishedAt: at(2, 12), BuildInsertedAt: atPtr(2, 10)},
+		{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Name: "production", ReleaseStatus: contracts.StatusFailed, InsertedAt: at(3, 9), FinishedAt: at(3, 10), BuildInsertedAt: atPtr(3, 8)},
+		{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Name: "production", ReleaseStatus: contracts.StatusFailed, InsertedAt: at(3, 10), FinishedAt: at(3, 11), BuildInsertedAt: atPtr(3, 8)},
+		{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-web", Name: "production", ReleaseStatus: contracts.StatusFailed, InsertedAt: at(3, 12), FinishedAt: at(3, 13), BuildInsertedAt: atPtr(3, 11)},
+		{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Name: "production", ReleaseStatus: contracts.StatusSucceeded, InsertedAt: at(3, 13), FinishedAt: at(3, 14), BuildInsertedAt: atPtr(3, 12)},
+		{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "ziplinee-ci-api", Name: "staging", ReleaseStatus: contracts.StatusSucceeded, InsertedAt: at(4, 9), FinishedAt: at(4, 10)},
+	}
+
+	t.Run("ComputesMetricsPerReleaseTarget", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		filters := map[api.FilterType][]string{api.FilterOrganizations: {"Ziplinee"}}
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetReleaseOutcomes(gomock.Any(), "", "", "", from, to, filters).
+			Return(outcomes, nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		metrics, err := service.GetDoraMetrics(context.Background(), "", "", "", from, to, filters)
+
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(metrics.Targets)) {
+			production := metrics.Targets[0]
+			assert.Equal(t, "production", production.Target)
+			assert.Equal(t, 2, production.NrDeployments)
+			assert.Equal(t, 3, production.NrFailedDeployments)
+			assert.InDelta(t, 0.2, production.DeploymentsPerDay, 0.0001)
+			assert.InDelta(t, 0.6, production.ChangeFailureRate, 0.0001)
+			if assert.NotNil(t, production.LeadTime) {
+				assert.Equal(t, 2*time.Hour, *production.LeadTime)
+			}
+			// restored 4 hours after the first of two consecutive failures
+			if assert.NotNil(t, production.TimeToRestore) {
+				assert.Equal(t, 4*time.Hour, *production.TimeToRestore)
+			}
+			// the failure of another pipeline isn't restored by the succeeded release of the first one
+			assert.Equal(t, 1, production.NrUnrestoredFailures)
+
+			staging := metrics.Targets[1]
+			assert.Equal(t, "staging", staging.Target)
+			assert.Equal(t, 1, staging.NrDeployments)
+			assert.InDelta(t, 0.1, staging.DeploymentsPerDay, 0.0001)
+			assert.Equal(t, 0.0, staging.ChangeFailureRate)
+			assert.Nil(t, staging.LeadTime)
+			assert.Nil(t, staging.TimeToRestore)
+		}
+	})
+
+	t.Run("ReturnsNoTargetsWithoutReleases", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetReleaseOutcomes(gomock.Any(), "github.com", "ziplineeci", "ziplinee-ci-api", from, to, gomock.Any()).
+			Return([]*database.ReleaseOutcome{}, nil)
+
+		service := NewService(&api.APIConfig{}, databaseClient, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		// act
+		metrics, err := service.GetDoraMetrics(context.Background(), "github.com", "ziplineeci", "ziplinee-ci-api", from, to, map[api.FilterType][]string{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(metrics.Targets))
+	})
+}
+
+func Test_getPercentile(t *testing.T) {
+	t.Run("ReturnsNearestRankPercentile", func(t *testing.T) {
+
+		values := []float64{5, 1, 9, 3, 7, 2, 8, 4, 6, 10}
+
+		assert.Equal(t, 9.0, getPercentile(values, 90))
+		assert.Equal(t, 10.0, getPercentile(values, 95))
+		assert.Equal(t, 5.0, getPercentile(values, 50))
+		assert.Equal(t, 1.0, getPercentile(values, 1))
+	})
+
+	t.Run("ReturnsZeroWithoutValues", func(t *testing.T) {
+		assert.Equal(t, 0.0, getPercentile([]float64{}, 90))
+	})
+}
+
+func Test_getExtrapolatedValue(t *testing.T) {
+	t.Run("ReturnsNextValueOfLinearTrend", func(t *testing.T) {
+		assert.InDelta(t, 12.0, getExtrapolatedValue([]float64{2, 4, 6, 8, 10}), 0.0001)
+	})
+
+	t.Run("ReturnsMeanForFlatValues", func(t *testing.T) {
+		assert.InDelta(t, 3.0, getExtrapolatedValue([]float64{3, 3, 3}), 0.0001)
+	})
+}
+
 func Test_isReleaseBlocked(t *testing.T) {
 	tests := []struct {
 		name, release, repo, branch string
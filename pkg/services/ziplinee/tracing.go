@@ -2,9 +2,11 @@ package ziplinee
 
 import (
 	"context"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/ziplineeci/ziplinee-ci-api/pkg/api"
+	"github.com/ziplineeci/ziplinee-ci-api/pkg/clients/database"
 	contracts "github.com/ziplineeci/ziplinee-ci-contracts"
 	manifest "github.com/ziplineeci/ziplinee-ci-manifest"
 )
@@ -151,3 +153,122 @@ func (s *tracingService) GetEventsForJobEnvvars(ctx context.Context, triggers []
 
 	return s.Service.GetEventsForJobEnvvars(ctx, triggers, events)
 }
+
+func (s *tracingService) DispatchQueuedJobs(ctx context.Context) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "DispatchQueuedJobs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.DispatchQueuedJobs(ctx)
+}
+
+func (s *tracingService) QueueMigration(ctx context.Context, task database.MigrationTask, restart string) (queuedTask *database.MigrationTask, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "QueueMigration"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.QueueMigration(ctx, task, restart)
+}
+
+func (s *tracingService) RunMigrationTasks(ctx context.Context) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "RunMigrationTasks"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.RunMigrationTasks(ctx)
+}
+
+func (s *tracingService) RollbackMigration(ctx context.Context, task database.MigrationTask) (rollback *database.MigrationRollback, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "RollbackMigration"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.RollbackMigration(ctx, task)
+}
+
+func (s *tracingService) SearchPipelineLogs(ctx context.Context, repoSource, repoOwner, repoName string, query LogSearchQuery) (result *LogSearchResult, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "SearchPipelineLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.SearchPipelineLogs(ctx, repoSource, repoOwner, repoName, query)
+}
+
+func (s *tracingService) PruneExpiredLogs(ctx context.Context, dryRun bool) (report *LogRetentionReport, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "PruneExpiredLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.PruneExpiredLogs(ctx, dryRun)
+}
+
+func (s *tracingService) PrunePipelineExpiredLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (items []*LogRetentionReportItem, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "PrunePipelineExpiredLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.PrunePipelineExpiredLogs(ctx, pipeline, dryRun)
+}
+
+func (s *tracingService) QueueLogReconciliation(ctx context.Context, reconciliation database.LogReconciliation) (queuedReconciliation *database.LogReconciliation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "QueueLogReconciliation"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.QueueLogReconciliation(ctx, reconciliation)
+}
+
+func (s *tracingService) RunLogReconciliations(ctx context.Context) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "RunLogReconciliations"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.RunLogReconciliations(ctx)
+}
+
+func (s *tracingService) ReconcilePipelineLogs(ctx context.Context, pipeline contracts.Pipeline, dryRun bool) (result *LogReconciliationResult, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "ReconcilePipelineLogs"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.ReconcilePipelineLogs(ctx, pipeline, dryRun)
+}
+
+func (s *tracingService) DispatchJobStatus(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "DispatchJobStatus"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.DispatchJobStatus(ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
+func (s *tracingService) DispatchNotification(ctx context.Context, notification contracts.NotificationRecord) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "DispatchNotification"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.DispatchNotification(ctx, notification)
+}
+
+func (s *tracingService) DeliverNotifications(ctx context.Context) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "DeliverNotifications"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.DeliverNotifications(ctx)
+}
+
+func (s *tracingService) CancelJob(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, jobID string, status contracts.Status) (canceledStatus contracts.Status, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "CancelJob"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.CancelJob(ctx, jobType, repoSource, repoOwner, repoName, jobID, status)
+}
+
+func (s *tracingService) ExplainJobResources(ctx context.Context, jobType contracts.JobType, repoSource, repoOwner, repoName, name, action string) (explanation *JobResourcesExplanation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "ExplainJobResources"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.ExplainJobResources(ctx, jobType, repoSource, repoOwner, repoName, name, action)
+}
+
+func (s *tracingService) GetCostReport(ctx context.Context, dimension CostDimension, labelKey string, from, to time.Time, filters map[api.FilterType][]string) (report *CostReport, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "GetCostReport"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.GetCostReport(ctx, dimension, labelKey, from, to, filters)
+}
+
+func (s *tracingService) GetDoraMetrics(ctx context.Context, repoSource, repoOwner, repoName string, from, to time.Time, filters map[api.FilterType][]string) (metrics *DoraMetrics, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, api.GetSpanName(s.prefix, "GetDoraMetrics"))
+	defer func() { api.FinishSpanWithError(span, err) }()
+
+	return s.Service.GetDoraMetrics(ctx, repoSource, repoOwner, repoName, from, to, filters)
+}
@@ -46,6 +46,7 @@ func NewHandler(templatesPath string, config *api.APIConfig, encryptedConfig *ap
 		buildService:       buildService,
 		warningHelper:      warningHelper,
 		secretHelper:       secretHelper,
+		customRolesCache:   api.NewCustomRolesCache(databaseClient),
 	}
 	return h
 }
@@ -60,6 +61,7 @@ type Handler struct {
 	buildService       Service
 	warningHelper      api.WarningHelper
 	secretHelper       crypt.SecretHelper
+	customRolesCache   *api.CustomRolesCache
 	// !! Migration changes !!
 	gcsMigratorClient migrationpb.ServiceClient
 }
@@ -194,13 +196,7 @@ func (h *Handler) GetPipelineBuilds(c *gin.Context) {
 				return nil, err
 			}
 
-			// convert typed array to interface array O(n)
-			items := make([]interface{}, len(builds))
-			for i := range builds {
-				items[i] = builds[i]
-			}
-
-			return items, nil
+			return h.getBuildResponses(c.Request.Context(), builds)
 		},
 		func() (int, error) {
 			return h.databaseClient.GetPipelineBuildsCount(c.Request.Context(), source, owner, repo, filters)
@@ -236,7 +232,7 @@ func (h *Handler) GetPipelineBuild(c *gin.Context) {
 			return
 		}
 
-		c.JSON(http.StatusOK, build)
+		c.JSON(http.StatusOK, h.getBuildResponse(c.Request.Context(), build))
 		return
 	}
 
@@ -264,7 +260,7 @@ func (h *Handler) GetPipelineBuild(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, build)
+	c.JSON(http.StatusOK, h.getBuildResponse(c.Request.Context(), build))
 }
 
 func (h *Handler) CreatePipelineBuild(c *gin.Context) {
@@ -315,7 +311,7 @@ func (h *Handler) CreatePipelineBuild(c *gin.Context) {
 		return
 	}
 
-	nonFailedBuilds, err := h.databaseClient.GetPipelineBuildsByVersion(c.Request.Context(), buildCommand.RepoSource, buildCommand.RepoOwner, buildCommand.RepoName, buildCommand.BuildVersion, []contracts.Status{contracts.StatusSucceeded, contracts.StatusRunning, contracts.StatusPending, contracts.StatusCanceling}, 1, false)
+	nonFailedBuilds, err := h.databaseClient.GetPipelineBuildsByVersion(c.Request.Context(), buildCommand.RepoSource, buildCommand.RepoOwner, buildCommand.RepoName, buildCommand.BuildVersion, []contracts.Status{contracts.StatusSucceeded, contracts.StatusRunning, contracts.StatusPending, contracts.StatusCanceling, api.StatusQueued}, 1, false)
 	if err != nil {
 		errorMessage := fmt.Sprintf("Failed retrieving build %v/%v/%v version %v for build command issued by %v", buildCommand.RepoSource, buildCommand.RepoOwner, buildCommand.RepoName, buildCommand.BuildVersion, email)
 		log.Error().Err(err).Msg(errorMessage)
@@ -338,6 +334,19 @@ func (h *Handler) CreatePipelineBuild(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
 		return
 	}
+
+	// ensure the request has the correct permission for this pipeline
+	hasPermission, err := api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, api.PermissionBuildsRebuild, contracts.Pipeline{RepoSource: failedBuild.RepoSource, RepoOwner: failedBuild.RepoOwner, RepoName: failedBuild.RepoName, Labels: failedBuild.Labels}, "")
+	if err != nil {
+		errorMessage := fmt.Sprintf("Failed checking permission for build command for %v/%v/%v issued by %v", buildCommand.RepoSource, buildCommand.RepoOwner, buildCommand.RepoName, email)
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		return
+	}
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
 	if hasNonFailedBuilds {
 		errorMessage := fmt.Sprintf("Version %v of pipeline %v/%v/%v has builds that are succeeded or running; only if all builds are failed the pipeline can be re-run; build command issued by %v", buildCommand.BuildVersion, buildCommand.RepoSource, buildCommand.RepoOwner, buildCommand.RepoName, email)
 		log.Error().Msg(errorMessage)
@@ -364,6 +373,8 @@ func (h *Handler) CreatePipelineBuild(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditActionBuildCreated, auditTargetTypeBuild, getJobAuditTargetID(createdBuild.RepoSource, createdBuild.RepoOwner, createdBuild.RepoName, createdBuild.ID), nil, jobAuditState{Status: createdBuild.BuildStatus, Version: createdBuild.BuildVersion})
+
 	c.JSON(http.StatusCreated, createdBuild)
 }
 
@@ -395,46 +406,32 @@ func (h *Handler) CancelPipelineBuild(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Pipeline build not found"})
 		return
 	}
-	if build.BuildStatus == contracts.StatusCanceling {
-		// apparently cancel was already clicked, but somehow the job didn't update the status to canceled
-		jobName := h.ciBuilderClient.GetJobName(c.Request.Context(), contracts.JobTypeBuild, build.RepoOwner, build.RepoName, build.ID)
-		_ = h.ciBuilderClient.CancelCiBuilderJob(c.Request.Context(), jobName)
-		_ = h.databaseClient.UpdateBuildStatus(c.Request.Context(), build.RepoSource, build.RepoOwner, build.RepoName, build.ID, contracts.StatusCanceled)
-		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Canceled build by user %v", email)})
+
+	// ensure the request has the correct permission for this pipeline
+	hasPermission, err := api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, api.PermissionBuildsCancel, contracts.Pipeline{RepoSource: build.RepoSource, RepoOwner: build.RepoOwner, RepoName: build.RepoName, Labels: build.Labels}, "")
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed checking permission for canceling build %v/%v/%v/builds/%v", source, owner, repo, revisionOrID)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Checking permission failed"})
 		return
 	}
-	if build.BuildStatus != contracts.StatusPending && build.BuildStatus != contracts.StatusRunning {
-		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("Build with status %v cannot be canceled", build.BuildStatus)})
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
-	// this build can be canceled, set status 'canceling' and cancel the build job
-	jobName := h.ciBuilderClient.GetJobName(c.Request.Context(), contracts.JobTypeBuild, build.RepoOwner, build.RepoName, build.ID)
-	cancelErr := h.ciBuilderClient.CancelCiBuilderJob(c.Request.Context(), jobName)
-	buildStatus := contracts.StatusCanceling
-	if build.BuildStatus == contracts.StatusPending {
-		// job might not have created a builder yet, so set status to canceled straightaway
-		buildStatus = contracts.StatusCanceled
+	if !jobCanBeCanceled(build.BuildStatus) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("Build with status %v cannot be canceled", build.BuildStatus)})
+		return
 	}
-	err = h.databaseClient.UpdateBuildStatus(c.Request.Context(), build.RepoSource, build.RepoOwner, build.RepoName, build.ID, buildStatus)
+
+	buildStatus, err := h.buildService.CancelJob(c.Request.Context(), contracts.JobTypeBuild, build.RepoSource, build.RepoOwner, build.RepoName, build.ID, build.BuildStatus)
 	if err != nil {
-		log.Error().Err(err).Msgf("Failed updating build status for %v/%v/%v/builds/%v in db", source, owner, repo, revisionOrID)
-		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline build status to canceling"})
+		log.Error().Err(err).Msgf("Failed canceling build %v/%v/%v/builds/%v", source, owner, repo, revisionOrID)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline build status to canceled"})
 		return
 	}
 
-	log.Debug().Msgf("Updated build status for canceling pipeline build %v/%v/%v with id %v...", source, owner, repo, revisionOrID)
-
-	// canceling the job failed because it no longer existed we should set canceled status right after having set it to canceling
-	if errors.Is(cancelErr, builderapi.ErrJobNotFound) && build.BuildStatus == contracts.StatusRunning {
-		buildStatus = contracts.StatusCanceled
-		err = h.databaseClient.UpdateBuildStatus(c.Request.Context(), build.RepoSource, build.RepoOwner, build.RepoName, build.ID, buildStatus)
-		if err != nil {
-			log.Error().Err(err).Msgf("Failed updating build status to canceled after setting it to canceling for %v/%v/%v/builds/%v in db", source, owner, repo, revisionOrID)
-			c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline build status to canceled"})
-			return
-		}
-	}
+	api.AddAuditDetails(c, auditActionBuildCanceled, auditTargetTypeBuild, getJobAuditTargetID(build.RepoSource, build.RepoOwner, build.RepoName, build.ID), jobAuditState{Status: build.BuildStatus}, jobAuditState{Status: buildStatus})
 
 	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Canceled build by user %v", email)})
 }
@@ -494,6 +491,19 @@ func (h *Handler) CreatePipelineBot(c *gin.Context) {
 		return
 	}
 
+	// ensure the request has the correct permission for this pipeline
+	hasPermission, err := api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, api.PermissionBotsCreate, *pipeline, "")
+	if err != nil {
+		errorMessage := fmt.Sprintf("Failed checking permission for bot command for %v/%v/%v issued by %v", botCommand.RepoSource, botCommand.RepoOwner, botCommand.RepoName, email)
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		return
+	}
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
 	// hand off to build service
 	// todo check which branch to pass
 	createdBot, err := h.buildService.CreateBot(c.Request.Context(), botCommand, *pipeline.ManifestObject, pipeline.RepoBranch)
@@ -504,6 +514,8 @@ func (h *Handler) CreatePipelineBot(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditActionBotCreated, auditTargetTypeBot, getJobAuditTargetID(createdBot.RepoSource, createdBot.RepoOwner, createdBot.RepoName, createdBot.ID), nil, jobAuditState{Status: createdBot.BotStatus, Name: createdBot.Name})
+
 	c.JSON(http.StatusCreated, createdBot)
 }
 
@@ -523,17 +535,57 @@ func (h *Handler) CreateNotification(c *gin.Context) {
 		return
 	}
 
+	hasPermission, err := h.requestHasNotificationPermission(c, notification)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed checking permission for creating notification %v of type %v", notification.LinkID, notification.LinkType)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Checking permission failed"})
+		return
+	}
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
 	createdNotification, err := h.databaseClient.InsertNotification(c.Request.Context(), notification)
 	if err != nil {
-		errorMessage := fmt.Sprintf("Failed creating notification %v of type %v", createdNotification.LinkID, createdNotification.LinkType)
+		errorMessage := fmt.Sprintf("Failed creating notification %v of type %v", notification.LinkID, notification.LinkType)
 		log.Error().Err(err).Msg(errorMessage)
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
 		return
 	}
 
+	err = h.buildService.DispatchNotification(c.Request.Context(), *createdNotification)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed dispatching notification %v of type %v", createdNotification.LinkID, createdNotification.LinkType)
+	}
+
 	c.JSON(http.StatusCreated, createdNotification)
 }
 
+// requestHasNotificationPermission checks the permission to create a notification, for the linked pipeline if there is one
+func (h *Handler) requestHasNotificationPermission(c *gin.Context, notification contracts.NotificationRecord) (bool, error) {
+
+	if notification.LinkType != contracts.NotificationLinkTypePipeline {
+		return api.RequestHasPermission(c, h.config, api.PermissionNotificationsCreate), nil
+	}
+
+	linkParts := strings.Split(notification.LinkID, "/")
+	if len(linkParts) != 3 {
+		return api.RequestHasPermission(c, h.config, api.PermissionNotificationsCreate), nil
+	}
+
+	// the pipeline labels are needed to match custom roles
+	pipeline, err := h.databaseClient.GetPipeline(c.Request.Context(), linkParts[0], linkParts[1], linkParts[2], map[api.FilterType][]string{}, false)
+	if err != nil {
+		return false, err
+	}
+	if pipeline == nil {
+		pipeline = &contracts.Pipeline{RepoSource: linkParts[0], RepoOwner: linkParts[1], RepoName: linkParts[2]}
+	}
+
+	return api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, api.PermissionNotificationsCreate, *pipeline, "")
+}
+
 func (h *Handler) GetPipelineBuildLogs(c *gin.Context) {
 
 	source := c.Param("source")
@@ -572,7 +624,7 @@ func (h *Handler) GetPipelineBuildLogs(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.ReadLogFromCloudStorage() {
+	if h.config.APIServer.ReadLogFromObjectStorage() {
 		err := h.cloudStorageClient.GetPipelineBuildLogs(c.Request.Context(), *buildLog, strings.Contains(c.Request.Header.Get("Accept-Encoding"), "gzip"), c.Writer)
 		if err != nil {
 
@@ -633,7 +685,7 @@ func (h *Handler) GetPipelineBuildLogsByID(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.ReadLogFromCloudStorage() {
+	if h.config.APIServer.ReadLogFromObjectStorage() {
 		err := h.cloudStorageClient.GetPipelineBuildLogs(c.Request.Context(), *buildLog, strings.Contains(c.Request.Header.Get("Accept-Encoding"), "gzip"), c.Writer)
 		if err != nil {
 
@@ -755,9 +807,8 @@ func (h *Handler) TailPipelineBuildLogs(c *gin.Context) {
 func (h *Handler) PostPipelineBuildLogs(c *gin.Context) {
 
 	// ensure the request has the correct claims
-	claims := jwt.ExtractClaims(c)
-	job := claims["job"].(string)
-	if job == "" {
+	jobClaims, ok := api.GetJobTokenClaimsFromRequest(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"code": http.StatusText(http.StatusUnauthorized), "message": "JWT is invalid or has invalid claim"})
 		return
 	}
@@ -767,6 +818,12 @@ func (h *Handler) PostPipelineBuildLogs(c *gin.Context) {
 	repo := c.Param("repo")
 	revisionOrID := c.Param("revisionOrId")
 
+	// a job can only post logs for itself
+	if !jobClaims.Matches(contracts.JobTypeBuild, source, owner, repo, revisionOrID) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is not issued for the job of this build"})
+		return
+	}
+
 	var buildLog contracts.BuildLog
 	err := c.Bind(&buildLog)
 	if err != nil {
@@ -775,19 +832,18 @@ func (h *Handler) PostPipelineBuildLogs(c *gin.Context) {
 		c.String(http.StatusInternalServerError, "Oops, something went wrong")
 		return
 	}
+	buildLog.RepoSource = source
+	buildLog.RepoOwner = owner
+	buildLog.RepoName = repo
 
-	if len(revisionOrID) != 40 {
-
-		_, err := strconv.Atoi(revisionOrID)
-		if err != nil {
-			log.Error().Err(err).
-				Msgf("Failed reading id from path parameter for %v/%v/%v/builds/%v", source, owner, repo, revisionOrID)
-			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": "Path parameter id is not of type integer"})
-			return
-		}
-
-		buildLog.BuildID = revisionOrID
+	_, err = strconv.Atoi(revisionOrID)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("Failed reading id from path parameter for %v/%v/%v/builds/%v", source, owner, repo, revisionOrID)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": "Path parameter id is not of type integer"})
+		return
 	}
+	buildLog.BuildID = revisionOrID
 
 	insertedBuildLog, err := h.databaseClient.InsertBuildLog(c.Request.Context(), buildLog)
 	if err != nil {
@@ -797,7 +853,7 @@ func (h *Handler) PostPipelineBuildLogs(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.WriteLogToCloudStorage() {
+	if h.config.APIServer.WriteLogToObjectStorage() {
 		err = h.cloudStorageClient.InsertBuildLog(c.Request.Context(), insertedBuildLog)
 		if err != nil {
 			log.Error().Err(err).
@@ -931,6 +987,19 @@ func (h *Handler) CreatePipelineRelease(c *gin.Context) {
 		return
 	}
 
+	// ensure the request has the correct permission for this pipeline and release target
+	hasPermission, err := api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, api.PermissionReleasesCreate, *pipeline, releaseCommand.Name)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Failed checking permission for release command for %v/%v/%v issued by %v", releaseCommand.RepoSource, releaseCommand.RepoOwner, releaseCommand.RepoName, email)
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		return
+	}
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
 	// check if version exists and is valid to release
 	builds, err := h.databaseClient.GetPipelineBuildsByVersion(c.Request.Context(), releaseCommand.RepoSource, releaseCommand.RepoOwner, releaseCommand.RepoName, releaseCommand.ReleaseVersion, []contracts.Status{contracts.StatusSucceeded}, 1, false)
 	if err != nil {
@@ -1027,6 +1096,8 @@ func (h *Handler) CreatePipelineRelease(c *gin.Context) {
 		return
 	}
 
+	api.AddAuditDetails(c, auditActionReleaseCreated, auditTargetTypeRelease, getJobAuditTargetID(createdRelease.RepoSource, createdRelease.RepoOwner, createdRelease.RepoName, createdRelease.ID), nil, jobAuditState{Status: createdRelease.ReleaseStatus, Version: createdRelease.ReleaseVersion, Name: createdRelease.Name, Action: createdRelease.Action})
+
 	c.JSON(http.StatusCreated, createdRelease)
 }
 
@@ -1057,45 +1128,41 @@ func (h *Handler) CancelPipelineRelease(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Pipeline release not found"})
 		return
 	}
-	if release.ReleaseStatus == contracts.StatusCanceling {
-		jobName := h.ciBuilderClient.GetJobName(c.Request.Context(), contracts.JobTypeRelease, release.RepoOwner, release.RepoName, release.ID)
-		_ = h.ciBuilderClient.CancelCiBuilderJob(c.Request.Context(), jobName)
-		_ = h.databaseClient.UpdateReleaseStatus(c.Request.Context(), release.RepoSource, release.RepoOwner, release.RepoName, release.ID, contracts.StatusCanceled)
-		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Canceled release by user %v", email)})
+
+	// ensure the request has the correct permission for this pipeline and release target; the pipeline labels are needed to match custom roles
+	pipeline, err := h.databaseClient.GetPipeline(c.Request.Context(), release.RepoSource, release.RepoOwner, release.RepoName, map[api.FilterType][]string{}, false)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving pipeline for %v/%v/%v from db in CancelPipelineRelease", source, owner, repo)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Retrieving pipeline failed"})
 		return
 	}
-	if release.ReleaseStatus != contracts.StatusPending && release.ReleaseStatus != contracts.StatusRunning {
-		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("Release with status %v cannot be canceled", release.ReleaseStatus)})
+	if pipeline == nil {
+		pipeline = &contracts.Pipeline{RepoSource: release.RepoSource, RepoOwner: release.RepoOwner, RepoName: release.RepoName}
+	}
+	hasPermission, err := api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, api.PermissionReleasesCancel, *pipeline, release.Name)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed checking permission for canceling release %v/%v/%v/%v", source, owner, repo, idValue)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Checking permission failed"})
+		return
+	}
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
-	// this release can be canceled, set status 'canceling' and cancel the release job
-	jobName := h.ciBuilderClient.GetJobName(c.Request.Context(), contracts.JobTypeRelease, release.RepoOwner, release.RepoName, release.ID)
-	cancelErr := h.ciBuilderClient.CancelCiBuilderJob(c.Request.Context(), jobName)
-	releaseStatus := contracts.StatusCanceling
-	if release.ReleaseStatus == contracts.StatusPending {
-		// job might not have created a builder yet, so set status to canceled straightaway
-		releaseStatus = contracts.StatusCanceled
+	if !jobCanBeCanceled(release.ReleaseStatus) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("Release with status %v cannot be canceled", release.ReleaseStatus)})
+		return
 	}
-	err = h.databaseClient.UpdateReleaseStatus(c.Request.Context(), release.RepoSource, release.RepoOwner, release.RepoName, release.ID, releaseStatus)
+
+	releaseStatus, err := h.buildService.CancelJob(c.Request.Context(), contracts.JobTypeRelease, release.RepoSource, release.RepoOwner, release.RepoName, release.ID, release.ReleaseStatus)
 	if err != nil {
-		log.Error().Err(err).Msgf("Failed updating release status for %v/%v/%v/builds/%v in db in CancelPipelineRelease", source, owner, repo, release.ID)
-		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline release status to canceling"})
+		log.Error().Err(err).Msgf("Failed canceling release %v/%v/%v/%v", source, owner, repo, idValue)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline release status to canceled"})
 		return
 	}
 
-	log.Debug().Msgf("Updated build status for canceling pipeline release %v/%v/%v with id %v...", source, owner, repo, idValue)
-
-	// canceling the job failed because it no longer existed we should set canceled status right after having set it to canceling
-	if errors.Is(cancelErr, builderapi.ErrJobNotFound) && release.ReleaseStatus == contracts.StatusRunning {
-		releaseStatus = contracts.StatusCanceled
-		err = h.databaseClient.UpdateReleaseStatus(c.Request.Context(), release.RepoSource, release.RepoOwner, release.RepoName, release.ID, releaseStatus)
-		if err != nil {
-			log.Error().Err(err).Msgf("Failed updating release status to canceled after setting it to canceling for %v/%v/%v/builds/%v in db", source, owner, repo, release.ID)
-			c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline release status to canceled"})
-			return
-		}
-	}
+	api.AddAuditDetails(c, auditActionReleaseCanceled, auditTargetTypeRelease, getJobAuditTargetID(release.RepoSource, release.RepoOwner, release.RepoName, release.ID), jobAuditState{Status: release.ReleaseStatus}, jobAuditState{Status: releaseStatus})
 
 	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Canceled release by user %v", email)})
 }
@@ -1117,6 +1184,11 @@ func (h *Handler) GetPipelineRelease(c *gin.Context) {
 		return
 	}
 
+	if release.ReleaseStatus == api.StatusQueued {
+		c.JSON(http.StatusOK, releaseResponse{Release: release, QueuePosition: h.getQueuePosition(c.Request.Context(), contracts.JobTypeRelease, release.ID)})
+		return
+	}
+
 	c.JSON(http.StatusOK, release)
 }
 
@@ -1137,7 +1209,7 @@ func (h *Handler) GetPipelineReleaseLogs(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.ReadLogFromCloudStorage() {
+	if h.config.APIServer.ReadLogFromObjectStorage() {
 		err := h.cloudStorageClient.GetPipelineReleaseLogs(c.Request.Context(), *releaseLog, strings.Contains(c.Request.Header.Get("Accept-Encoding"), "gzip"), c.Writer)
 		if err != nil {
 
@@ -1177,7 +1249,7 @@ func (h *Handler) GetPipelineReleaseLogsByID(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.ReadLogFromCloudStorage() {
+	if h.config.APIServer.ReadLogFromObjectStorage() {
 		err := h.cloudStorageClient.GetPipelineReleaseLogs(c.Request.Context(), *releaseLog, strings.Contains(c.Request.Header.Get("Accept-Encoding"), "gzip"), c.Writer)
 		if err != nil {
 
@@ -1279,9 +1351,8 @@ func (h *Handler) TailPipelineReleaseLogs(c *gin.Context) {
 func (h *Handler) PostPipelineReleaseLogs(c *gin.Context) {
 
 	// ensure the request has the correct claims
-	claims := jwt.ExtractClaims(c)
-	job := claims["job"].(string)
-	if job == "" {
+	jobClaims, ok := api.GetJobTokenClaimsFromRequest(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"code": http.StatusText(http.StatusUnauthorized), "message": "JWT is invalid or has invalid claim"})
 		return
 	}
@@ -1291,6 +1362,12 @@ func (h *Handler) PostPipelineReleaseLogs(c *gin.Context) {
 	repo := c.Param("repo")
 	releaseID := c.Param("releaseId")
 
+	// a job can only post logs for itself
+	if !jobClaims.Matches(contracts.JobTypeRelease, source, owner, repo, releaseID) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is not issued for the job of this release"})
+		return
+	}
+
 	var releaseLog contracts.ReleaseLog
 	err := c.Bind(&releaseLog)
 	if err != nil {
@@ -1299,6 +1376,10 @@ func (h *Handler) PostPipelineReleaseLogs(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": "INTERNAL_SERVER_ERROR", "message": "Failed binding release logs from body"})
 		return
 	}
+	releaseLog.RepoSource = source
+	releaseLog.RepoOwner = owner
+	releaseLog.RepoName = repo
+	releaseLog.ReleaseID = releaseID
 
 	insertedReleaseLog, err := h.databaseClient.InsertReleaseLog(c.Request.Context(), releaseLog)
 	if err != nil {
@@ -1308,7 +1389,7 @@ func (h *Handler) PostPipelineReleaseLogs(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.WriteLogToCloudStorage() {
+	if h.config.APIServer.WriteLogToObjectStorage() {
 		err = h.cloudStorageClient.InsertReleaseLog(c.Request.Context(), insertedReleaseLog)
 		if err != nil {
 			log.Error().Err(err).
@@ -1427,45 +1508,41 @@ func (h *Handler) CancelPipelineBot(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Pipeline bot not found"})
 		return
 	}
-	if bot.BotStatus == contracts.StatusCanceling {
-		jobName := h.ciBuilderClient.GetJobName(c.Request.Context(), contracts.JobTypeBot, bot.RepoOwner, bot.RepoName, bot.ID)
-		_ = h.ciBuilderClient.CancelCiBuilderJob(c.Request.Context(), jobName)
-		_ = h.databaseClient.UpdateBotStatus(c.Request.Context(), bot.RepoSource, bot.RepoOwner, bot.RepoName, botID, contracts.StatusCanceled)
-		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Canceled bot by user %v", email)})
+
+	// ensure the request has the correct permission for this pipeline; the pipeline labels are needed to match custom roles
+	pipeline, err := h.databaseClient.GetPipeline(c.Request.Context(), bot.RepoSource, bot.RepoOwner, bot.RepoName, map[api.FilterType][]string{}, false)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving pipeline for %v/%v/%v from db in CancelPipelineBot", source, owner, repo)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Retrieving pipeline failed"})
+		return
+	}
+	if pipeline == nil {
+		pipeline = &contracts.Pipeline{RepoSource: bot.RepoSource, RepoOwner: bot.RepoOwner, RepoName: bot.RepoName}
+	}
+	hasPermission, err := api.RequestHasPipelinePermission(c, h.config, h.customRolesCache, api.PermissionBotsCancel, *pipeline, "")
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed checking permission for canceling bot %v/%v/%v/bots/%v", source, owner, repo, botID)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Checking permission failed"})
 		return
 	}
-	if bot.BotStatus != contracts.StatusPending && bot.BotStatus != contracts.StatusRunning {
-		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("Bot with status %v cannot be canceled", bot.BotStatus)})
+	if !hasPermission {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
 		return
 	}
 
-	// this bot can be canceled, set status 'canceling' and cancel the bot job
-	jobName := h.ciBuilderClient.GetJobName(c.Request.Context(), contracts.JobTypeBot, bot.RepoOwner, bot.RepoName, bot.ID)
-	cancelErr := h.ciBuilderClient.CancelCiBuilderJob(c.Request.Context(), jobName)
-	botStatus := contracts.StatusCanceling
-	if bot.BotStatus == contracts.StatusPending {
-		// job might not have created a builder yet, so set status to canceled straightaway
-		botStatus = contracts.StatusCanceled
+	if !jobCanBeCanceled(bot.BotStatus) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("Bot with status %v cannot be canceled", bot.BotStatus)})
+		return
 	}
-	err = h.databaseClient.UpdateBotStatus(c.Request.Context(), bot.RepoSource, bot.RepoOwner, bot.RepoName, botID, botStatus)
+
+	botStatus, err := h.buildService.CancelJob(c.Request.Context(), contracts.JobTypeBot, bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.ID, bot.BotStatus)
 	if err != nil {
-		log.Error().Err(err).Msgf("Failed updating bot status for %v/%v/%v/bots/%v in db", source, owner, repo, botID)
-		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline bot status to canceling"})
+		log.Error().Err(err).Msgf("Failed canceling bot %v/%v/%v/bots/%v", source, owner, repo, botID)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline bot status to canceled"})
 		return
 	}
 
-	log.Debug().Msgf("Updated build status for canceling pipeline bot %v/%v/%v with id %v...", source, owner, repo, botID)
-
-	// canceling the job failed because it no longer existed we should set canceled status right after having set it to canceling
-	if errors.Is(cancelErr, builderapi.ErrJobNotFound) && bot.BotStatus == contracts.StatusRunning {
-		botStatus = contracts.StatusCanceled
-		err = h.databaseClient.UpdateBotStatus(c.Request.Context(), bot.RepoSource, bot.RepoOwner, bot.RepoName, botID, botStatus)
-		if err != nil {
-			log.Error().Err(err).Msgf("Failed updating bot status to canceled after setting it to canceling for %v/%v/%v/bots/%v in db", source, owner, repo, botID)
-			c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed setting pipeline bot status to canceled"})
-			return
-		}
-	}
+	api.AddAuditDetails(c, auditActionBotCanceled, auditTargetTypeBot, getJobAuditTargetID(bot.RepoSource, bot.RepoOwner, bot.RepoName, bot.ID), jobAuditState{Status: bot.BotStatus}, jobAuditState{Status: botStatus})
 
 	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Canceled bot by user %v", email)})
 }
@@ -1487,6 +1564,11 @@ func (h *Handler) GetPipelineBot(c *gin.Context) {
 		return
 	}
 
+	if bot.BotStatus == api.StatusQueued {
+		c.JSON(http.StatusOK, botResponse{Bot: bot, QueuePosition: h.getQueuePosition(c.Request.Context(), contracts.JobTypeBot, bot.ID)})
+		return
+	}
+
 	c.JSON(http.StatusOK, bot)
 }
 
@@ -1507,7 +1589,7 @@ func (h *Handler) GetPipelineBotLogs(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.ReadLogFromCloudStorage() {
+	if h.config.APIServer.ReadLogFromObjectStorage() {
 		err := h.cloudStorageClient.GetPipelineBotLogs(c.Request.Context(), *botLog, strings.Contains(c.Request.Header.Get("Accept-Encoding"), "gzip"), c.Writer)
 		if err != nil {
 
@@ -1547,7 +1629,7 @@ func (h *Handler) GetPipelineBotLogsByID(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.ReadLogFromCloudStorage() {
+	if h.config.APIServer.ReadLogFromObjectStorage() {
 		err := h.cloudStorageClient.GetPipelineBotLogs(c.Request.Context(), *botLog, strings.Contains(c.Request.Header.Get("Accept-Encoding"), "gzip"), c.Writer)
 		if err != nil {
 
@@ -1649,9 +1731,8 @@ func (h *Handler) TailPipelineBotLogs(c *gin.Context) {
 func (h *Handler) PostPipelineBotLogs(c *gin.Context) {
 
 	// ensure the request has the correct claims
-	claims := jwt.ExtractClaims(c)
-	job := claims["job"].(string)
-	if job == "" {
+	jobClaims, ok := api.GetJobTokenClaimsFromRequest(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"code": http.StatusText(http.StatusUnauthorized), "message": "JWT is invalid or has invalid claim"})
 		return
 	}
@@ -1661,6 +1742,12 @@ func (h *Handler) PostPipelineBotLogs(c *gin.Context) {
 	repo := c.Param("repo")
 	botIDValue := c.Param("botId")
 
+	// a job can only post logs for itself
+	if !jobClaims.Matches(contracts.JobTypeBot, source, owner, repo, botIDValue) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is not issued for the job of this bot"})
+		return
+	}
+
 	botID, err := strconv.Atoi(botIDValue)
 	if err != nil {
 		log.Error().Err(err).
@@ -1677,6 +1764,10 @@ func (h *Handler) PostPipelineBotLogs(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": "INTERNAL_SERVER_ERROR", "message": "Failed binding bot logs from body"})
 		return
 	}
+	botLog.RepoSource = source
+	botLog.RepoOwner = owner
+	botLog.RepoName = repo
+	botLog.BotID = botIDValue
 
 	insertedBotLog, err := h.databaseClient.InsertBotLog(c.Request.Context(), botLog)
 	if err != nil {
@@ -1686,7 +1777,7 @@ func (h *Handler) PostPipelineBotLogs(c *gin.Context) {
 		return
 	}
 
-	if h.config.APIServer.WriteLogToCloudStorage() {
+	if h.config.APIServer.WriteLogToObjectStorage() {
 		err = h.cloudStorageClient.InsertBotLog(c.Request.Context(), insertedBotLog)
 		if err != nil {
 			log.Error().Err(err).
@@ -1699,6 +1790,90 @@ func (h *Handler) PostPipelineBotLogs(c *gin.Context) {
 	c.String(http.StatusOK, "Aye aye!")
 }
 
+func (h *Handler) SearchPipelineLogs(c *gin.Context) {
+
+	source := c.Param("source")
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	query, err := getLogSearchQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+		return
+	}
+
+	result, err := h.buildService.SearchPipelineLogs(c.Request.Context(), source, owner, repo, query)
+	if err != nil {
+		if errors.Is(err, ErrInvalidLogSearchQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+			return
+		}
+
+		log.Error().Err(err).Msgf("Failed searching logs for %v/%v/%v", source, owner, repo)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Searching logs failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// getLogSearchQuery reads the log search query from query string parameters like ?q=error&regex=false&last=10&type=build,release&step=test&streamType=stderr&since=2006-01-02T15:04:05Z&limit=100
+func getLogSearchQuery(c *gin.Context) (query LogSearchQuery, err error) {
+
+	query.Text = c.Query("q")
+	if query.Text == "" {
+		return query, fmt.Errorf("Query parameter q is required")
+	}
+	query.Regex = c.Query("regex") == "true"
+
+	if last := c.Query("last"); last != "" {
+		query.Last, err = strconv.Atoi(last)
+		if err != nil {
+			return query, fmt.Errorf("Query parameter last is not a number")
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return query, fmt.Errorf("Query parameter limit is not a number")
+		}
+	}
+
+	for _, t := range getCommaSeparatedQueryArray(c, "type") {
+		query.JobTypes = append(query.JobTypes, contracts.JobType(t))
+	}
+	query.Steps = getCommaSeparatedQueryArray(c, "step")
+	query.StreamTypes = getCommaSeparatedQueryArray(c, "streamType")
+
+	if since := c.Query("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return query, fmt.Errorf("Query parameter since is not an RFC3339 timestamp")
+		}
+		query.Since = &sinceTime
+	}
+	if until := c.Query("until"); until != "" {
+		untilTime, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return query, fmt.Errorf("Query parameter until is not an RFC3339 timestamp")
+		}
+		query.Until = &untilTime
+	}
+
+	return query, nil
+}
+
+func getCommaSeparatedQueryArray(c *gin.Context, key string) (values []string) {
+	for _, v := range c.QueryArray(key) {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
 func (h *Handler) GetAllPipelineBuilds(c *gin.Context) {
 
 	pageNumber, pageSize, filters, sortings := api.GetQueryParameters(c)
@@ -2199,6 +2374,57 @@ func (h *Handler) GetPipelineStatsBotsMemoryUsageMeasurements(c *gin.Context) {
 	})
 }
 
+// ExplainPipelineJobResources shows the resources the next build of a branch (?type=build&branch=main), release to a target
+// (?type=release&target=production&action=deploy) or run of a bot (?type=bot&bot=cleanup) gets and why
+func (h *Handler) ExplainPipelineJobResources(c *gin.Context) {
+
+	source := c.Param("source")
+	owner := c.Param("owner")
+	repo := c.Param("repo")
+
+	jobType := contracts.JobType(c.DefaultQuery("type", string(contracts.JobTypeBuild)))
+
+	var name, nameParameter string
+	switch jobType {
+	case contracts.JobTypeBuild:
+		name, nameParameter = c.Query("branch"), "branch"
+	case contracts.JobTypeRelease:
+		name, nameParameter = c.Query("target"), "target"
+	case contracts.JobTypeBot:
+		name, nameParameter = c.Query("bot"), "bot"
+	default:
+		errorMessage := fmt.Sprintf("Query parameter type %v is invalid; use build, release or bot", jobType)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	if name == "" {
+		errorMessage := fmt.Sprintf("Query parameter %v is required for type %v", nameParameter, jobType)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	// only explain resources for pipelines the request can see
+	pipeline, err := h.databaseClient.GetPipeline(c.Request.Context(), source, owner, repo, api.GetPipelineFilters(c), false)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving pipeline for %v/%v/%v from db", source, owner, repo)
+	}
+	if pipeline == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Pipeline not found"})
+		return
+	}
+
+	explanation, err := h.buildService.ExplainJobResources(c.Request.Context(), jobType, source, owner, repo, name, c.Query("action"))
+	if err != nil {
+		errorMessage := fmt.Sprintf("Failed explaining %v job resources for %v/%v/%v", jobType, source, owner, repo)
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
+
 func (h *Handler) GetPipelineWarnings(c *gin.Context) {
 
 	source := c.Param("source")
@@ -2473,55 +2699,208 @@ func (h *Handler) GetStatsMostBots(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func (h *Handler) GetStatsBuildsDuration(c *gin.Context) {
+// GetStatsCosts reports what the jobs between ?from= and ?to= (defaulting to the current month) cost and waste per organization,
+// group, pipeline, label (?labelKey=team) or month; ?format=csv downloads the report as csv
+func (h *Handler) GetStatsCosts(c *gin.Context) {
 
-	// get filters (?filter[status]=running,succeeded&filter[since]=1w
-	filters := map[api.FilterType][]string{}
-	filters[api.FilterStatus] = api.GetStatusFilter(c)
-	filters[api.FilterSince] = api.GetSinceFilter(c)
+	dimension := CostDimension(c.Param("dimension"))
+	labelKey := c.Query("labelKey")
 
-	buildsDuration, err := h.databaseClient.GetBuildsDuration(c.Request.Context(), filters)
-	if err != nil {
-		log.Error().Err(err).
-			Msg("Failed retrieving builds duration from db")
+	if dimension == CostDimensionLabel && labelKey == "" {
+		errorMessage := "Query parameter labelKey is required for the labels dimension"
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"duration": buildsDuration,
-	})
-}
+	now := time.Now().UTC()
+	from, err := getCostReportTime(c.Query("from"), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		errorMessage := fmt.Sprintf("Query parameter from %v is invalid; use 2006-01-02 or RFC3339", c.Query("from"))
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+	to, err := getCostReportEndTime(c.Query("to"), now)
+	if err != nil {
+		errorMessage := fmt.Sprintf("Query parameter to %v is invalid; use 2006-01-02 or RFC3339", c.Query("to"))
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+	if !from.Before(to) {
+		errorMessage := "Query parameter from has to be before to"
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
 
-func (h *Handler) GetStatsBuildsAdoption(c *gin.Context) {
+	// only report the costs of pipelines the request can see
+	filters := api.SetPermissionsFilters(c, map[api.FilterType][]string{})
 
-	buildTimes, err := h.databaseClient.GetFirstBuildTimes(c.Request.Context())
+	report, err := h.buildService.GetCostReport(c.Request.Context(), dimension, labelKey, from, to, filters)
 	if err != nil {
-		errorMessage := "Failed retrieving first build times from db"
+		if errors.Is(err, ErrCostsNotConfigured) {
+			c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInvalidCostDimension) {
+			errorMessage := fmt.Sprintf("Cost dimension %v is invalid; use organizations, groups, pipelines, labels or months", dimension)
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+			return
+		}
+		errorMessage := fmt.Sprintf("Failed retrieving cost report by %v", dimension)
 		log.Error().Err(err).Msg(errorMessage)
 		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"datetimes": buildTimes,
-	})
-}
-
-func (h *Handler) GetStatsReleasesAdoption(c *gin.Context) {
+	if c.Query("format") == "csv" {
+		var buffer bytes.Buffer
+		err = writeCostReportCSV(&buffer, report)
+		if err != nil {
+			errorMessage := fmt.Sprintf("Failed writing cost report by %v as csv", dimension)
+			log.Error().Err(err).Msg(errorMessage)
+			c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+			return
+		}
 
-	releaseTimes, err := h.databaseClient.GetFirstReleaseTimes(c.Request.Context())
-	if err != nil {
-		errorMessage := "Failed retrieving first release times from db"
-		log.Error().Err(err).Msg(errorMessage)
-		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		filename := fmt.Sprintf("costs-%v-%v-%v.csv", dimension, from.Format("20060102"), to.Format("20060102"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%v\"", filename))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buffer.Bytes())
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"datetimes": releaseTimes,
-	})
+	c.JSON(http.StatusOK, report)
 }
 
-func (h *Handler) GetStatsBotsAdoption(c *gin.Context) {
+// getCostReportTime parses a date or timestamp query parameter, falling back to a default when it's empty; a date means the start
+// of that day
+func getCostReportTime(value string, defaultValue time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// getCostReportEndTime parses the end of a cost report like getCostReportTime, except that a date includes that whole day
+func getCostReportEndTime(value string, defaultValue time.Time) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+
+	return getCostReportTime(value, defaultValue)
+}
+
+// GetStatsDora reports the dora metrics per release target of all pipelines over a window (?filter[since]=1w, defaulting to 1m),
+// filterable by organizations, groups, labels and release target like the other stats
+func (h *Handler) GetStatsDora(c *gin.Context) {
+	h.getDoraMetrics(c, "", "", "")
+}
+
+// GetPipelineStatsDora reports the dora metrics per release target of a single pipeline over a window (?filter[since]=1w)
+func (h *Handler) GetPipelineStatsDora(c *gin.Context) {
+	h.getDoraMetrics(c, c.Param("source"), c.Param("owner"), c.Param("repo"))
+}
+
+func (h *Handler) getDoraMetrics(c *gin.Context, source, owner, repo string) {
+
+	since := api.GetGenericFilter(c, api.FilterSince, "1m")[0]
+
+	to := time.Now().UTC()
+	from, ok := getDoraWindowStart(since, to)
+	if !ok {
+		errorMessage := fmt.Sprintf("Filter since %v is invalid; use 1d, 1w, 1m or 1y", since)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	// get filters (?filter[organizations]=ziplinee&filter[groups]=team&filter[labels]=team%3Dziplinee-team&filter[target]=production)
+	filters := map[api.FilterType][]string{}
+	filters[api.FilterOrganizations] = api.GetGenericFilter(c, api.FilterOrganizations)
+	filters[api.FilterGroups] = api.GetGenericFilter(c, api.FilterGroups)
+	filters[api.FilterLabels] = api.GetLabelsFilter(c)
+	filters[api.FilterReleaseTarget] = api.GetGenericFilter(c, api.FilterReleaseTarget)
+
+	metrics, err := h.buildService.GetDoraMetrics(c.Request.Context(), source, owner, repo, from, to, filters)
+	if err != nil {
+		errorMessage := "Failed retrieving dora metrics"
+		if source != "" {
+			errorMessage = fmt.Sprintf("Failed retrieving dora metrics for %v/%v/%v", source, owner, repo)
+		}
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// getDoraWindowStart returns the start of a window ending at to for the since filter values that are a fixed period
+func getDoraWindowStart(since string, to time.Time) (time.Time, bool) {
+	switch since {
+	case "1d":
+		return to.AddDate(0, 0, -1), true
+	case "1w":
+		return to.AddDate(0, 0, -7), true
+	case "1m":
+		return to.AddDate(0, -1, 0), true
+	case "1y":
+		return to.AddDate(-1, 0, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+func (h *Handler) GetStatsBuildsDuration(c *gin.Context) {
+
+	// get filters (?filter[status]=running,succeeded&filter[since]=1w
+	filters := map[api.FilterType][]string{}
+	filters[api.FilterStatus] = api.GetStatusFilter(c)
+	filters[api.FilterSince] = api.GetSinceFilter(c)
+
+	buildsDuration, err := h.databaseClient.GetBuildsDuration(c.Request.Context(), filters)
+	if err != nil {
+		log.Error().Err(err).
+			Msg("Failed retrieving builds duration from db")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"duration": buildsDuration,
+	})
+}
+
+func (h *Handler) GetStatsBuildsAdoption(c *gin.Context) {
+
+	buildTimes, err := h.databaseClient.GetFirstBuildTimes(c.Request.Context())
+	if err != nil {
+		errorMessage := "Failed retrieving first build times from db"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"datetimes": buildTimes,
+	})
+}
+
+func (h *Handler) GetStatsReleasesAdoption(c *gin.Context) {
+
+	releaseTimes, err := h.databaseClient.GetFirstReleaseTimes(c.Request.Context())
+	if err != nil {
+		errorMessage := "Failed retrieving first release times from db"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": errorMessage})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"datetimes": releaseTimes,
+	})
+}
+
+func (h *Handler) GetStatsBotsAdoption(c *gin.Context) {
 
 	releaseTimes, err := h.databaseClient.GetFirstBotTimes(c.Request.Context())
 	if err != nil {
@@ -2784,6 +3163,601 @@ func (h *Handler) EncryptSecret(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"secret": encryptedString})
 }
 
+// PollQueuedJobs dispatches queued jobs at a regular interval until the stop channel is closed
+func (h *Handler) PollQueuedJobs(stopChannel <-chan struct{}, done func()) {
+	defer done()
+
+	if h.config.JobQueue == nil || !h.config.JobQueue.Enable {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(h.config.JobQueue.PollIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := h.buildService.DispatchQueuedJobs(context.Background())
+			if err != nil {
+				log.Error().Err(err).Msg("Failed dispatching queued jobs")
+			}
+		case <-stopChannel:
+			log.Info().Msg("Stopping dispatching of queued jobs")
+			return
+		}
+	}
+}
+
+// PollMigrationTasks runs queued migration tasks at a regular interval until the stop channel is closed
+func (h *Handler) PollMigrationTasks(stopChannel <-chan struct{}, done func()) {
+	defer done()
+
+	if h.config.Migration == nil || !h.config.Migration.Enable {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(h.config.Migration.PollIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := h.buildService.RunMigrationTasks(context.Background())
+			if err != nil {
+				log.Error().Err(err).Msg("Failed running migration tasks")
+			}
+		case <-stopChannel:
+			log.Info().Msg("Stopping running of migration tasks")
+			return
+		}
+	}
+}
+
+func (h *Handler) QueueMigration(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionMigrationsCreate) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	var request migrationRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		errorMessage := "Binding QueueMigration body failed"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	if request.FromSource == request.ToSource && request.FromOwner == request.ToOwner && request.FromName == request.ToName {
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": "The repository to migrate to has to differ from the repository to migrate from"})
+		return
+	}
+
+	task, err := h.buildService.QueueMigration(c.Request.Context(), database.MigrationTask{
+		ID:          request.ID,
+		FromSource:  request.FromSource,
+		FromOwner:   request.FromOwner,
+		FromName:    request.FromName,
+		ToSource:    request.ToSource,
+		ToOwner:     request.ToOwner,
+		ToName:      request.ToName,
+		CallbackURL: request.CallbackURL,
+	}, request.Restart)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrMigrationPipelineNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+		case errors.Is(err, ErrMigrationInvalidRestart):
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": fmt.Sprintf("%v; use one of %v", err.Error(), getMigrationRestartSteps())})
+		case errors.Is(err, ErrMigrationInProgress):
+			c.JSON(http.StatusConflict, gin.H{"code": http.StatusText(http.StatusConflict), "message": err.Error()})
+		default:
+			log.Error().Err(err).Msgf("Failed queuing migration of %v/%v/%v", request.FromSource, request.FromOwner, request.FromName)
+			c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Queuing migration failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *Handler) GetMigrations(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionMigrationsList) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	tasks, err := h.databaseClient.GetMigrationTasks(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed retrieving migration tasks from db")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed to get migration status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (h *Handler) GetMigrationByID(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionMigrationsGet) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	taskID := c.Param("taskID")
+
+	task, err := h.databaseClient.GetMigrationTaskByID(c.Request.Context(), taskID)
+	h.respondWithMigrationTask(c, task, err)
+}
+
+func (h *Handler) GetMigrationByFromRepo(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionMigrationsGet) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	source := c.Param("source")
+	owner := c.Param("owner")
+	name := c.Param("name")
+
+	task, err := h.databaseClient.GetMigrationTaskByFromRepo(c.Request.Context(), source, owner, name)
+	h.respondWithMigrationTask(c, task, err)
+}
+
+func (h *Handler) respondWithMigrationTask(c *gin.Context, task *database.MigrationTask, err error) {
+	if errors.Is(err, database.ErrMigrationTaskNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed retrieving migration task from db")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed to get migration status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+func (h *Handler) RollbackMigration(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionMigrationsRollback) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	taskID := c.Param("taskID")
+
+	task, err := h.databaseClient.GetMigrationTaskByID(ctx, taskID)
+	if errors.Is(err, database.ErrMigrationTaskNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving migration task %v from db", taskID)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed to get migration status"})
+		return
+	}
+
+	rollback, err := h.buildService.RollbackMigration(ctx, *task)
+	if errors.Is(err, ErrMigrationInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"code": http.StatusText(http.StatusConflict), "message": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed rolling back migration task %v", taskID)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Rolling back migration failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rollback)
+}
+
+func (h *Handler) PollLogRetention(stopChannel <-chan struct{}, done func()) {
+	defer done()
+
+	if h.config.LogRetention == nil || !h.config.LogRetention.Enable {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(h.config.LogRetention.PollIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_, err := h.buildService.PruneExpiredLogs(context.Background(), false)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed pruning expired logs")
+			}
+		case <-stopChannel:
+			log.Info().Msg("Stopping pruning of expired logs")
+			return
+		}
+	}
+}
+
+// GetLogRetentionDryRun reports the logs that would be pruned for all pipelines, or a single pipeline if ?pipeline=source/owner/name is set
+func (h *Handler) GetLogRetentionDryRun(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionLogRetentionGet) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	pipelineName := c.Query("pipeline")
+	if pipelineName == "" {
+		report, err := h.buildService.PruneExpiredLogs(c.Request.Context(), true)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed reporting expired logs")
+			c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Reporting expired logs failed"})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	pipelineNameParts := strings.Split(pipelineName, "/")
+	if len(pipelineNameParts) != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": "Query parameter pipeline should be formatted as source/owner/name"})
+		return
+	}
+
+	pipeline, err := h.databaseClient.GetPipeline(c.Request.Context(), pipelineNameParts[0], pipelineNameParts[1], pipelineNameParts[2], map[api.FilterType][]string{}, true)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving pipeline %v from db", pipelineName)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Reporting expired logs failed"})
+		return
+	}
+	if pipeline == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Pipeline not found"})
+		return
+	}
+
+	items, err := h.buildService.PrunePipelineExpiredLogs(c.Request.Context(), *pipeline, true)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed reporting expired logs for %v", pipelineName)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Reporting expired logs failed"})
+		return
+	}
+
+	report := &LogRetentionReport{
+		DryRun:    true,
+		Pipelines: 1,
+		Items:     []*LogRetentionReportItem{},
+	}
+	report.add(items...)
+
+	c.JSON(http.StatusOK, report)
+}
+
+// PollLogReconciliations runs queued log reconciliations at a regular interval until the stop channel is closed
+func (h *Handler) PollLogReconciliations(stopChannel <-chan struct{}, done func()) {
+	defer done()
+
+	if h.config.LogReconciliation == nil || !h.config.LogReconciliation.Enable {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(h.config.LogReconciliation.PollIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := h.buildService.RunLogReconciliations(context.Background())
+			if err != nil {
+				log.Error().Err(err).Msg("Failed running log reconciliations")
+			}
+		case <-stopChannel:
+			log.Info().Msg("Stopping running of log reconciliations")
+			return
+		}
+	}
+}
+
+func (h *Handler) QueueLogReconciliation(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionLogReconciliationsCreate) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	var request logReconciliationRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		errorMessage := "Binding QueueLogReconciliation body failed"
+		log.Error().Err(err).Msg(errorMessage)
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": errorMessage})
+		return
+	}
+
+	if (request.RepoSource != "" || request.RepoOwner != "" || request.RepoName != "") && (request.RepoSource == "" || request.RepoOwner == "" || request.RepoName == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": "Set repoSource, repoOwner and repoName to reconcile the logs of a single pipeline, or none of them for all pipelines"})
+		return
+	}
+
+	claims := jwt.ExtractClaims(c)
+	queuedBy, _ := claims[jwt.IdentityKey].(string)
+
+	reconciliation, err := h.buildService.QueueLogReconciliation(c.Request.Context(), database.LogReconciliation{
+		DryRun:     request.DryRun,
+		RepoSource: request.RepoSource,
+		RepoOwner:  request.RepoOwner,
+		RepoName:   request.RepoName,
+		QueuedBy:   queuedBy,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrLogReconciliationNotEnabled), errors.Is(err, ErrLogReconciliationPipelineNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"code": http.StatusText(http.StatusBadRequest), "message": err.Error()})
+		case errors.Is(err, ErrLogReconciliationInProgress):
+			c.JSON(http.StatusConflict, gin.H{"code": http.StatusText(http.StatusConflict), "message": err.Error()})
+		default:
+			log.Error().Err(err).Msg("Failed queuing log reconciliation")
+			c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Queuing log reconciliation failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+func (h *Handler) GetLogReconciliations(c *gin.Context) {
+
+	pageNumber, pageSize, filters, _ := api.GetQueryParameters(c)
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionLogReconciliationsList) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	response, err := api.GetPagedListResponse(
+		func() ([]interface{}, error) {
+			reconciliations, err := h.databaseClient.GetLogReconciliations(ctx, pageNumber, pageSize, filters)
+			if err != nil {
+				return nil, err
+			}
+
+			// convert typed array to interface array O(n)
+			items := make([]interface{}, len(reconciliations))
+			for i := range reconciliations {
+				items[i] = reconciliations[i]
+			}
+			return items, nil
+		},
+		func() (int, error) {
+			return h.databaseClient.GetLogReconciliationsCount(ctx, filters)
+		},
+		pageNumber,
+		pageSize)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed retrieving log reconciliations from db")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetLogReconciliation(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionLogReconciliationsGet) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	id := c.Param("id")
+
+	reconciliation, err := h.databaseClient.GetLogReconciliationByID(c.Request.Context(), id)
+	if errors.Is(err, database.ErrLogReconciliationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving log reconciliation %v from db", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed to get log reconciliation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// PollNotificationDeliveries delivers due notifications at a regular interval until the stop channel is closed
+func (h *Handler) PollNotificationDeliveries(stopChannel <-chan struct{}, done func()) {
+	defer done()
+
+	if h.config.Notifications == nil || !h.config.Notifications.Enable {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(h.config.Notifications.PollIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := h.buildService.DeliverNotifications(context.Background())
+			if err != nil {
+				log.Error().Err(err).Msg("Failed delivering notifications")
+			}
+		case <-stopChannel:
+			log.Info().Msg("Stopping delivery of notifications")
+			return
+		}
+	}
+}
+
+func (h *Handler) GetNotificationDeliveries(c *gin.Context) {
+
+	pageNumber, pageSize, filters, _ := api.GetQueryParameters(c)
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionNotificationDeliveriesList) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	response, err := api.GetPagedListResponse(
+		func() ([]interface{}, error) {
+			deliveries, err := h.databaseClient.GetNotificationDeliveries(ctx, pageNumber, pageSize, filters)
+			if err != nil {
+				return nil, err
+			}
+
+			// convert typed array to interface array O(n)
+			items := make([]interface{}, len(deliveries))
+			for i := range deliveries {
+				items[i] = deliveries[i]
+			}
+			return items, nil
+		},
+		func() (int, error) {
+			return h.databaseClient.GetNotificationDeliveriesCount(ctx, filters)
+		},
+		pageNumber,
+		pageSize)
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed retrieving notification deliveries from db")
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetNotificationDelivery(c *gin.Context) {
+
+	// ensure the request has the correct permission
+	if !api.RequestTokenHasPermission(c, api.PermissionNotificationDeliveriesGet) {
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is invalid or request does not have correct permission"})
+		return
+	}
+
+	id := c.Param("id")
+
+	delivery, err := h.databaseClient.GetNotificationDeliveryByID(c.Request.Context(), id)
+	if errors.Is(err, database.ErrNotificationDeliveryNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": http.StatusText(http.StatusNotFound), "message": "Not found"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed retrieving notification delivery %v from db", id)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": http.StatusText(http.StatusInternalServerError), "message": "Failed to get notification delivery"})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// getQueuePositions returns the 1-based position of each queued job, keyed by job type and id
+func (h *Handler) getQueuePositions(ctx context.Context) (queuePositions map[string]int, err error) {
+	queuedJobs, err := h.databaseClient.GetQueuedJobs(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	queuePositions = make(map[string]int, len(queuedJobs))
+	for i, qj := range queuedJobs {
+		queuePositions[queuePositionKey(qj.JobType, qj.JobID)] = i + 1
+	}
+
+	return queuePositions, nil
+}
+
+// getQueuePosition returns the 1-based position of a queued job or 0 if it can't be determined
+func (h *Handler) getQueuePosition(ctx context.Context, jobType contracts.JobType, jobID string) int {
+	queuePositions, err := h.getQueuePositions(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed retrieving queue position for %v %v", jobType, jobID)
+		return 0
+	}
+
+	return queuePositions[queuePositionKey(jobType, jobID)]
+}
+
+func queuePositionKey(jobType contracts.JobType, jobID string) string {
+	return fmt.Sprintf("%v/%v", jobType, jobID)
+}
+
+// getBuildResponses adds the queue position and status reason to builds where applicable
+func (h *Handler) getBuildResponses(ctx context.Context, builds []*contracts.Build) (items []interface{}, err error) {
+
+	var queuePositions map[string]int
+	canceledBuildIDs := []string{}
+	for _, b := range builds {
+		switch b.BuildStatus {
+		case api.StatusQueued:
+			if queuePositions == nil {
+				queuePositions, err = h.getQueuePositions(ctx)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case contracts.StatusCanceling, contracts.StatusCanceled:
+			canceledBuildIDs = append(canceledBuildIDs, b.ID)
+		}
+	}
+
+	var statusReasons map[string]string
+	if len(canceledBuildIDs) > 0 {
+		statusReasons, err = h.databaseClient.GetBuildStatusReasons(ctx, canceledBuildIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// convert typed array to interface array O(n)
+	items = make([]interface{}, len(builds))
+	for i, b := range builds {
+		items[i] = buildResponse{
+			Build:         b,
+			QueuePosition: queuePositions[queuePositionKey(contracts.JobTypeBuild, b.ID)],
+			StatusReason:  statusReasons[b.ID],
+		}
+	}
+
+	return items, nil
+}
+
+// getBuildResponse adds the queue position and status reason to a single build where applicable
+func (h *Handler) getBuildResponse(ctx context.Context, build *contracts.Build) buildResponse {
+
+	response := buildResponse{Build: build}
+
+	switch build.BuildStatus {
+	case api.StatusQueued:
+		response.QueuePosition = h.getQueuePosition(ctx, contracts.JobTypeBuild, build.ID)
+	case contracts.StatusCanceling, contracts.StatusCanceled:
+		statusReasons, err := h.databaseClient.GetBuildStatusReasons(ctx, []string{build.ID})
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed retrieving status reason for build %v", build.ID)
+		}
+		response.StatusReason = statusReasons[build.ID]
+	}
+
+	return response
+}
+
 func (h *Handler) obfuscateSecrets(input string) (string, error) {
 
 	r, err := regexp.Compile(`ziplinee\.secret\(([a-zA-Z0-9.=_-]+)\)`)
@@ -2798,9 +3772,8 @@ func (h *Handler) obfuscateSecrets(input string) (string, error) {
 func (h *Handler) Commands(c *gin.Context) {
 
 	// ensure the request has the correct claims
-	claims := jwt.ExtractClaims(c)
-	job := claims["job"].(string)
-	if job == "" {
+	jobClaims, ok := api.GetJobTokenClaimsFromRequest(c)
+	if !ok {
 		log.Error().Msg("JWT is invalid or has invalid claim")
 		c.JSON(http.StatusUnauthorized, gin.H{"code": http.StatusText(http.StatusUnauthorized), "message": "JWT is invalid or has invalid claim"})
 		return
@@ -2829,6 +3802,13 @@ func (h *Handler) Commands(c *gin.Context) {
 		return
 	}
 
+	// a job can only report for itself
+	if !jobClaims.MatchesEvent(ciBuilderEvent) {
+		log.Error().Interface("ciBuilderEvent", ciBuilderEvent).Msgf("JWT of job %v is not issued for the job of this event", jobClaims.JobName)
+		c.JSON(http.StatusForbidden, gin.H{"code": http.StatusText(http.StatusForbidden), "message": "JWT is not issued for the job of this event"})
+		return
+	}
+
 	switch ciBuilderEvent.BuildEventType {
 	case contracts.BuildEventTypeUpdateStatus:
 
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	jwt "github.com/appleboy/gin-jwt/v2"
@@ -364,6 +365,10 @@ func TestCreatePipelineRelease_Forbidden(t *testing.T) {
 				}
 				return
 			})
+		databaseClient.
+			EXPECT().
+			GetCustomRoles(gomock.Any(), 1, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*api.CustomRole{}, nil)
 		cloudStorageClient := cloudstorage.NewMockClient(ctrl)
 		builderapiClient := builderapi.NewMockClient(ctrl)
 		buildService := NewMockService(ctrl)
@@ -404,3 +409,794 @@ func TestCreatePipelineRelease_Forbidden(t *testing.T) {
 
 	})
 }
+
+func TestCancelPipelineBuild_Permissions(t *testing.T) {
+
+	build := &contracts.Build{
+		ID:          "1234",
+		RepoSource:  "github.com",
+		RepoOwner:   "ziplineeci",
+		RepoName:    "payments-api",
+		BuildStatus: contracts.StatusSucceeded,
+		Labels: []contracts.Label{
+			{
+				Key:   "team",
+				Value: "payments",
+			},
+		},
+	}
+
+	t.Run("ReturnsForbiddenWithoutPermissionIfPipelinePermissionsAreEnforced", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{Auth: &api.AuthConfig{EnforcePipelinePermissions: true}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildByID(gomock.Any(), "github.com", "ziplineeci", "payments-api", "1234", false).
+			Return(build, nil)
+		databaseClient.
+			EXPECT().
+			GetCustomRoles(gomock.Any(), 1, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*api.CustomRole{}, nil)
+		databaseClient.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("DELETE", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds/1234", nil)
+
+		// act
+		handler.CancelPipelineBuild(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+
+	t.Run("AllowsCancelThroughCustomRoleBoundToPipelineLabels", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{Auth: &api.AuthConfig{EnforcePipelinePermissions: true}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildByID(gomock.Any(), "github.com", "ziplineeci", "payments-api", "1234", false).
+			Return(build, nil)
+		databaseClient.
+			EXPECT().
+			GetCustomRoles(gomock.Any(), 1, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*api.CustomRole{
+				{
+					Name:        "payments-operator",
+					Permissions: []string{"ci.builds.cancel"},
+					Bindings: []*api.CustomRoleBinding{
+						{
+							Groups: []string{"team-payments"},
+							Labels: []contracts.Label{{Key: "team", Value: "payments"}},
+						},
+					},
+					Active: true,
+				},
+			}, nil)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-payments"},
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("DELETE", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds/1234", nil)
+
+		// act
+		handler.CancelPipelineBuild(c)
+
+		// the permission check passes, but a succeeded build cannot be canceled
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+
+	t.Run("AllowsCancelWithoutPermissionIfPipelinePermissionsAreNotEnforcedAndNoCustomRoleIsBoundToPipeline", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{Auth: &api.AuthConfig{}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildByID(gomock.Any(), "github.com", "ziplineeci", "payments-api", "1234", false).
+			Return(build, nil)
+		databaseClient.
+			EXPECT().
+			GetCustomRoles(gomock.Any(), 1, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*api.CustomRole{}, nil)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "jane@ziplinee.io",
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("DELETE", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds/1234", nil)
+
+		// act
+		handler.CancelPipelineBuild(c)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+
+	t.Run("ReturnsForbiddenIfPipelinePermissionsAreNotEnforcedButCustomRoleIsBoundToPipelineForOthers", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{Auth: &api.AuthConfig{}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildByID(gomock.Any(), "github.com", "ziplineeci", "payments-api", "1234", false).
+			Return(build, nil)
+		databaseClient.
+			EXPECT().
+			GetCustomRoles(gomock.Any(), 1, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*api.CustomRole{
+				{
+					Name:        "payments-operator",
+					Permissions: []string{"ci.builds.cancel"},
+					Bindings: []*api.CustomRoleBinding{
+						{
+							Groups: []string{"team-payments"},
+							Labels: []contracts.Label{{Key: "team", Value: "payments"}},
+						},
+					},
+					Active: true,
+				},
+			}, nil)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "jane@ziplinee.io",
+			"groups":        []interface{}{"team-orders"},
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("DELETE", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds/1234", nil)
+
+		// act
+		handler.CancelPipelineBuild(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+}
+
+func TestCreatePipelineBuild_Permissions(t *testing.T) {
+
+	failedBuild := &contracts.Build{
+		ID:           "1234",
+		RepoSource:   "github.com",
+		RepoOwner:    "ziplineeci",
+		RepoName:     "payments-api",
+		BuildVersion: "1.0.0",
+		BuildStatus:  contracts.StatusFailed,
+	}
+
+	t.Run("ReturnsForbiddenForTokenScopedToReadingIfPipelinePermissionsAreNotEnforced", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{Auth: &api.AuthConfig{}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildsByVersion(gomock.Any(), "github.com", "ziplineeci", "payments-api", "1.0.0", []contracts.Status{contracts.StatusFailed, contracts.StatusCanceled}, uint64(1), false).
+			Return([]*contracts.Build{failedBuild}, nil)
+		databaseClient.
+			EXPECT().
+			GetPipelineBuildsByVersion(gomock.Any(), "github.com", "ziplineeci", "payments-api", "1.0.0", gomock.Any(), uint64(1), false).
+			Return([]*contracts.Build{}, nil)
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			CreateBuild(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "jane@ziplinee.io",
+			"roles":         []interface{}{"administrator"},
+			"tokenID":       "5678",
+			"scopes":        []interface{}{"ci.pipelines.get", "ci.builds.get"},
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds", strings.NewReader(`{"repoSource":"github.com","repoOwner":"ziplineeci","repoName":"payments-api","buildVersion":"1.0.0"}`))
+
+		// act
+		handler.CreatePipelineBuild(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+}
+
+func TestCreatePipelineBot_Permissions(t *testing.T) {
+
+	t.Run("ReturnsForbiddenWithoutPermissionIfPipelinePermissionsAreEnforced", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{Auth: &api.AuthConfig{EnforcePipelinePermissions: true}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "payments-api", gomock.Any(), false).
+			Return(&contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "payments-api", ManifestObject: &manifest.ZiplineeManifest{}}, nil)
+		databaseClient.
+			EXPECT().
+			GetCustomRoles(gomock.Any(), 1, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*api.CustomRole{}, nil)
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			CreateBot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "jane@ziplinee.io",
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/bots", strings.NewReader(`{"repoSource":"github.com","repoOwner":"ziplineeci","repoName":"payments-api","name":"cleanup"}`))
+
+		// act
+		handler.CreatePipelineBot(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+}
+
+func TestCreateNotification_Permissions(t *testing.T) {
+
+	t.Run("ReturnsForbiddenForTokenWithoutNotificationScopeIfPipelinePermissionsAreNotEnforced", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{Auth: &api.AuthConfig{}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertNotification(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "jane@ziplinee.io",
+			"tokenID":       "5678",
+			"scopes":        []interface{}{"ci.pipelines.get"},
+		})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/notifications", strings.NewReader(`{"linkType":"container","linkID":"ziplineeci/payments-api"}`))
+
+		// act
+		handler.CreateNotification(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+}
+
+func TestExplainPipelineJobResources(t *testing.T) {
+
+	claims := jwt.MapClaims{
+		jwt.IdentityKey: "1231",
+		"email":         "jane@ziplinee.io",
+		"organizations": []interface{}{"team-orders"},
+	}
+
+	t.Run("ReturnsNotFoundForPipelineOutsideOrganizationsOfRequest", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "payments-api", gomock.Any(), false).
+			DoAndReturn(func(ctx context.Context, repoSource, repoOwner, repoName string, filters map[api.FilterType][]string, optimized bool) (*contracts.Pipeline, error) {
+				assert.Equal(t, []string{"team-orders"}, filters[api.FilterOrganizations])
+				return nil, nil
+			})
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			ExplainJobResources(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", claims)
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"})
+		c.Request = httptest.NewRequest("GET", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/resources/explain?branch=main", nil)
+
+		// act
+		handler.ExplainPipelineJobResources(c)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Result().StatusCode)
+	})
+
+	t.Run("ReturnsExplanationForPipelineInOrganizationsOfRequest", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			GetPipeline(gomock.Any(), "github.com", "ziplineeci", "orders-api", gomock.Any(), false).
+			Return(&contracts.Pipeline{RepoSource: "github.com", RepoOwner: "ziplineeci", RepoName: "orders-api"}, nil)
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			ExplainJobResources(gomock.Any(), contracts.JobTypeBuild, "github.com", "ziplineeci", "orders-api", "main", "").
+			Return(&JobResourcesExplanation{JobType: contracts.JobTypeBuild}, nil)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", claims)
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "orders-api"})
+		c.Request = httptest.NewRequest("GET", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/orders-api/resources/explain?branch=main", nil)
+
+		// act
+		handler.ExplainPipelineJobResources(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	})
+}
+
+func TestGetCostReportEndTime(t *testing.T) {
+	t.Run("ReturnsEndOfDayForDate", func(t *testing.T) {
+
+		// act
+		to, err := getCostReportEndTime("2026-10-31", time.Now())
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), to)
+	})
+
+	t.Run("ReturnsTimestampAsIs", func(t *testing.T) {
+
+		// act
+		to, err := getCostReportEndTime("2026-10-31T12:00:00Z", time.Now())
+
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC), to)
+	})
+
+	t.Run("ReturnsDefaultForEmptyValue", func(t *testing.T) {
+
+		now := time.Now().UTC()
+
+		// act
+		to, err := getCostReportEndTime("", now)
+
+		assert.Nil(t, err)
+		assert.Equal(t, now, to)
+	})
+}
+
+func TestPostPipelineBuildLogs_JobToken(t *testing.T) {
+
+	jobClaims := jwt.MapClaims{
+		"job":        "build-ziplineeci-payments-api-1234",
+		"jobType":    "build",
+		"repoSource": "github.com",
+		"repoOwner":  "ziplineeci",
+		"repoName":   "payments-api",
+		"jobID":      "1234",
+	}
+
+	t.Run("ReturnsForbiddenForTokenOfOtherBuild", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertBuildLog(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jobClaims)
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "orders-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/orders-api/builds/1234/logs", strings.NewReader(`{"steps":[]}`))
+
+		// act
+		handler.PostPipelineBuildLogs(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+
+	t.Run("ReturnsUnauthorizedForTokenWithoutJobClaims", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertBuildLog(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			"job": "build-ziplineeci-payments-api-1234",
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds/1234/logs", strings.NewReader(`{"steps":[]}`))
+
+		// act
+		handler.PostPipelineBuildLogs(c)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Result().StatusCode)
+	})
+
+	t.Run("InsertsLogForPipelineOfTokenIgnoringBody", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{APIServer: &api.APIServerConfig{}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertBuildLog(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, buildLog contracts.BuildLog) (contracts.BuildLog, error) {
+				assert.Equal(t, "payments-api", buildLog.RepoName)
+				assert.Equal(t, "1234", buildLog.BuildID)
+				return buildLog, nil
+			}).
+			Times(1)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jobClaims)
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds/1234/logs", strings.NewReader(`{"repoSource":"github.com","repoOwner":"ziplineeci","repoName":"orders-api","buildID":"5678","steps":[]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		// act
+		handler.PostPipelineBuildLogs(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	})
+
+	t.Run("InsertsLogForLegacyTokenIssuedBeforeStart", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{APIServer: &api.APIServerConfig{}}
+		databaseClient := database.NewMockClient(ctrl)
+		databaseClient.
+			EXPECT().
+			InsertBuildLog(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, buildLog contracts.BuildLog) (contracts.BuildLog, error) {
+				return buildLog, nil
+			}).
+			Times(1)
+
+		handler := NewHandler("", cfg, cfg, databaseClient, nil, nil, nil, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			"job":      "build-ziplineeci-payments-api-1234",
+			"orig_iat": float64(time.Now().Add(-time.Hour).Unix()),
+		})
+		c.Params = append(c.Params, gin.Param{Key: "source", Value: "github.com"},
+			gin.Param{Key: "owner", Value: "ziplineeci"},
+			gin.Param{Key: "repo", Value: "payments-api"},
+			gin.Param{Key: "revisionOrId", Value: "1234"})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/pipelines/github.com/ziplineeci/payments-api/builds/1234/logs", strings.NewReader(`{"steps":[]}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		// act
+		handler.PostPipelineBuildLogs(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	})
+}
+
+func TestCommands_JobToken(t *testing.T) {
+	t.Run("ReturnsForbiddenForEventOfOtherJob", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			UpdateBuildStatus(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, nil, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			"job":        "build-ziplineeci-payments-api-1234",
+			"jobType":    "build",
+			"repoSource": "github.com",
+			"repoOwner":  "ziplineeci",
+			"repoName":   "payments-api",
+			"jobID":      "1234",
+		})
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/commands", strings.NewReader(`{
+			"buildEventType": "updateStatus",
+			"jobType": "build",
+			"job_name": "build-ziplineeci-payments-api-5678",
+			"git": {"repoSource": "github.com", "repoOwner": "ziplineeci", "repoName": "payments-api"},
+			"build": {"id": "5678", "buildStatus": "succeeded"}
+		}`))
+
+		// act
+		handler.Commands(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+}
+
+func TestQueueMigration_Handler(t *testing.T) {
+	t.Run("ReturnsForbiddenWithoutMigrationPermission", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			QueueMigration(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, nil, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "user@ziplinee.io",
+		})
+		bodyReader := strings.NewReader(`{"fromSource":"github.com","fromOwner":"ziplineeci","fromName":"repo-a","toSource":"github.com","toOwner":"ziplineeci","toName":"repo-b"}`)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/migration", bodyReader)
+
+		// act
+		handler.QueueMigration(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+
+	t.Run("ReturnsConflictIfMigrationIsInProgress", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			QueueMigration(gomock.Any(), gomock.Any(), "").
+			Return(nil, ErrMigrationInProgress)
+
+		handler := NewHandler("", cfg, cfg, nil, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "user@ziplinee.io",
+			"roles":         []interface{}{"migration.admin"},
+		})
+		bodyReader := strings.NewReader(`{"fromSource":"github.com","fromOwner":"ziplineeci","fromName":"repo-a","toSource":"github.com","toOwner":"ziplineeci","toName":"repo-b"}`)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/migration", bodyReader)
+
+		// act
+		handler.QueueMigration(c)
+
+		assert.Equal(t, http.StatusConflict, recorder.Result().StatusCode)
+	})
+
+	t.Run("ReturnsQueuedTask", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			QueueMigration(gomock.Any(), gomock.Any(), "builds").
+			DoAndReturn(func(ctx context.Context, task database.MigrationTask, restart string) (*database.MigrationTask, error) {
+				task.ID = "abc"
+				task.Status = database.MigrationStatusQueued
+				return &task, nil
+			})
+
+		handler := NewHandler("", cfg, cfg, nil, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"email":         "user@ziplinee.io",
+			"roles":         []interface{}{"migration.admin"},
+		})
+		bodyReader := strings.NewReader(`{"fromSource":"github.com","fromOwner":"ziplineeci","fromName":"repo-a","toSource":"github.com","toOwner":"ziplineeci","toName":"repo-b","restart":"builds"}`)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/migration", bodyReader)
+
+		// act
+		handler.QueueMigration(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+		var task database.MigrationTask
+		err := json.NewDecoder(recorder.Result().Body).Decode(&task)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", task.ID)
+		assert.Equal(t, "repo-b", task.ToName)
+	})
+}
+
+func TestQueueLogReconciliation_Handler(t *testing.T) {
+	t.Run("ReturnsForbiddenWithoutLogReconciliationPermission", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			QueueLogReconciliation(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, nil, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"roles":         []interface{}{"migration.admin"},
+		})
+		bodyReader := strings.NewReader(`{"dryRun":true}`)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/admin/logreconciliations", bodyReader)
+
+		// act
+		handler.QueueLogReconciliation(c)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Result().StatusCode)
+	})
+
+	t.Run("ReturnsBadRequestForIncompletePipeline", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			QueueLogReconciliation(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		handler := NewHandler("", cfg, cfg, nil, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"roles":         []interface{}{"log.migrator"},
+		})
+		bodyReader := strings.NewReader(`{"repoSource":"github.com","repoOwner":"ziplineeci"}`)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/admin/logreconciliations", bodyReader)
+
+		// act
+		handler.QueueLogReconciliation(c)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+	})
+
+	t.Run("ReturnsQueuedReconciliationForLogMigrator", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		cfg := &api.APIConfig{}
+		buildService := NewMockService(ctrl)
+		buildService.
+			EXPECT().
+			QueueLogReconciliation(gomock.Any(), database.LogReconciliation{DryRun: true, QueuedBy: "1231"}).
+			Return(&database.LogReconciliation{ID: "abc", Status: database.LogReconciliationStatusQueued, DryRun: true, QueuedBy: "1231"}, nil)
+
+		handler := NewHandler("", cfg, cfg, nil, nil, nil, buildService, nil, nil)
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Set("JWT_PAYLOAD", jwt.MapClaims{
+			jwt.IdentityKey: "1231",
+			"roles":         []interface{}{"log.migrator"},
+		})
+		bodyReader := strings.NewReader(`{"dryRun":true}`)
+		c.Request = httptest.NewRequest("POST", "https://ci.ziplinee.io/api/admin/logreconciliations", bodyReader)
+
+		// act
+		handler.QueueLogReconciliation(c)
+
+		assert.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		var reconciliation database.LogReconciliation
+		err := json.Unmarshal(recorder.Body.Bytes(), &reconciliation)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", reconciliation.ID)
+		assert.Equal(t, database.LogReconciliationStatusQueued, reconciliation.Status)
+	})
+}